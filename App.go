@@ -38,6 +38,7 @@ import (
 	"github.com/casbin/casbin"
 	casbinv2 "github.com/casbin/casbin/v2"
 	authMiddleware "github.com/devtron-labs/authenticator/middleware"
+	"github.com/devtron-labs/devtron/api/apiToken"
 	"github.com/devtron-labs/devtron/api/router"
 	"github.com/devtron-labs/devtron/api/sse"
 	"github.com/devtron-labs/devtron/internal/middleware"
@@ -70,6 +71,7 @@ type App struct {
 	sessionManager2            *authMiddleware.SessionManager
 	OtelTracingService         *otel.OtelTracingServiceImpl
 	loggingMiddleware          util.LoggingMiddleware
+	apiTokenAccessMiddleware   apiToken.ApiTokenAccessMiddleware
 	pubSubClient               *pubsub.PubSubClientServiceImpl
 	workflowEventProcessorImpl *in.WorkflowEventProcessorImpl
 }
@@ -87,6 +89,7 @@ func NewApp(router *router.MuxRouter,
 	workflowEventProcessorImpl *in.WorkflowEventProcessorImpl,
 	enforcerV2 *casbinv2.SyncedEnforcer,
 	userService user.UserService,
+	apiTokenAccessMiddleware apiToken.ApiTokenAccessMiddleware,
 ) *App {
 	//check argo connection
 	//todo - check argo-cd version on acd integration installation
@@ -105,6 +108,7 @@ func NewApp(router *router.MuxRouter,
 		pubSubClient:               pubSubClient,
 		workflowEventProcessorImpl: workflowEventProcessorImpl,
 		userService:                userService,
+		apiTokenAccessMiddleware:   apiTokenAccessMiddleware,
 	}
 	return app
 }
@@ -122,6 +126,7 @@ func (app *App) Start() {
 
 	server := &http.Server{Addr: fmt.Sprintf(":%d", port), Handler: authMiddleware.Authorizer(app.sessionManager2, user.WhitelistChecker, app.userService.CheckUserStatusAndUpdateLoginAudit)(app.MuxRouter.Router)}
 	app.MuxRouter.Router.Use(app.loggingMiddleware.LoggingMiddleware)
+	app.MuxRouter.Router.Use(app.apiTokenAccessMiddleware.ApiTokenAccessMiddleware)
 	app.MuxRouter.Router.Use(middleware.PrometheusMiddleware)
 	app.MuxRouter.Router.Use(middlewares.Recovery)
 
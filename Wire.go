@@ -33,20 +33,26 @@ import (
 	appStoreDiscover "github.com/devtron-labs/devtron/api/appStore/discover"
 	appStoreValues "github.com/devtron-labs/devtron/api/appStore/values"
 	"github.com/devtron-labs/devtron/api/argoApplication"
+	accessGrant2 "github.com/devtron-labs/devtron/api/auth/accessGrant"
+	accessReview2 "github.com/devtron-labs/devtron/api/auth/accessReview"
 	"github.com/devtron-labs/devtron/api/auth/authorisation/globalConfig"
 	"github.com/devtron-labs/devtron/api/auth/sso"
 	"github.com/devtron-labs/devtron/api/auth/user"
+	"github.com/devtron-labs/devtron/api/celPolicy"
 	chartRepo "github.com/devtron-labs/devtron/api/chartRepo"
 	"github.com/devtron-labs/devtron/api/cluster"
 	"github.com/devtron-labs/devtron/api/connector"
 	"github.com/devtron-labs/devtron/api/dashboardEvent"
 	"github.com/devtron-labs/devtron/api/deployment"
+	"github.com/devtron-labs/devtron/api/deploymentWindow"
 	"github.com/devtron-labs/devtron/api/devtronResource"
 	"github.com/devtron-labs/devtron/api/externalLink"
 	fluxApplication "github.com/devtron-labs/devtron/api/fluxApplication"
 	client "github.com/devtron-labs/devtron/api/helm-app"
+	jira2 "github.com/devtron-labs/devtron/api/jira"
 	"github.com/devtron-labs/devtron/api/k8s"
 	"github.com/devtron-labs/devtron/api/module"
+	"github.com/devtron-labs/devtron/api/releaseTrain"
 	"github.com/devtron-labs/devtron/api/resourceScan"
 	"github.com/devtron-labs/devtron/api/restHandler"
 	"github.com/devtron-labs/devtron/api/restHandler/app/appInfo"
@@ -71,6 +77,7 @@ import (
 	status3 "github.com/devtron-labs/devtron/api/router/app/pipeline/status"
 	trigger2 "github.com/devtron-labs/devtron/api/router/app/pipeline/trigger"
 	workflow2 "github.com/devtron-labs/devtron/api/router/app/workflow"
+	scim2 "github.com/devtron-labs/devtron/api/scim"
 	"github.com/devtron-labs/devtron/api/server"
 	"github.com/devtron-labs/devtron/api/sse"
 	"github.com/devtron-labs/devtron/api/team"
@@ -125,6 +132,9 @@ import (
 	"github.com/devtron-labs/devtron/pkg/appWorkflow"
 	"github.com/devtron-labs/devtron/pkg/asyncProvider"
 	"github.com/devtron-labs/devtron/pkg/attributes"
+	"github.com/devtron-labs/devtron/pkg/auth/accessGrant"
+	"github.com/devtron-labs/devtron/pkg/auth/accessReview"
+	"github.com/devtron-labs/devtron/pkg/auth/scim"
 	"github.com/devtron-labs/devtron/pkg/build"
 	"github.com/devtron-labs/devtron/pkg/build/artifacts/imageTagging"
 	pipeline6 "github.com/devtron-labs/devtron/pkg/build/pipeline"
@@ -152,6 +162,7 @@ import (
 	"github.com/devtron-labs/devtron/pkg/gitops"
 	"github.com/devtron-labs/devtron/pkg/imageDigestPolicy"
 	"github.com/devtron-labs/devtron/pkg/infraConfig"
+	"github.com/devtron-labs/devtron/pkg/jira"
 	"github.com/devtron-labs/devtron/pkg/kubernetesResourceAuditLogs"
 	repository7 "github.com/devtron-labs/devtron/pkg/kubernetesResourceAuditLogs/repository"
 	"github.com/devtron-labs/devtron/pkg/notifier"
@@ -167,6 +178,7 @@ import (
 	repository6 "github.com/devtron-labs/devtron/pkg/pipeline/workflowStatus/repository"
 	"github.com/devtron-labs/devtron/pkg/plugin"
 	"github.com/devtron-labs/devtron/pkg/policyGovernance"
+	releaseTrain2 "github.com/devtron-labs/devtron/pkg/releaseTrain"
 	resourceGroup2 "github.com/devtron-labs/devtron/pkg/resourceGroup"
 	"github.com/devtron-labs/devtron/pkg/resourceQualifiers"
 	"github.com/devtron-labs/devtron/pkg/sql"
@@ -175,6 +187,7 @@ import (
 	"github.com/devtron-labs/devtron/pkg/variables"
 	"github.com/devtron-labs/devtron/pkg/variables/parsers"
 	repository10 "github.com/devtron-labs/devtron/pkg/variables/repository"
+	"github.com/devtron-labs/devtron/pkg/variables/secretBackend"
 	workflow3 "github.com/devtron-labs/devtron/pkg/workflow"
 	"github.com/devtron-labs/devtron/pkg/workflow/dag"
 	util2 "github.com/devtron-labs/devtron/util"
@@ -226,6 +239,18 @@ func InitializeApp() (*App, error) {
 		userResource.UserResourceWireSet,
 		policyGovernance.PolicyGovernanceWireSet,
 		resourceScan.ScanningResultWireSet,
+		celPolicy.CelPolicyRouterWireSet,
+		deploymentWindow.DeploymentWindowRouterWireSet,
+		releaseTrain2.ReleaseTrainWireSet,
+		releaseTrain.ReleaseTrainRouterWireSet,
+		jira.JiraWireSet,
+		jira2.JiraRouterWireSet,
+		scim.ScimWireSet,
+		scim2.ScimRouterWireSet,
+		accessGrant.AccessGrantWireSet,
+		accessGrant2.AccessGrantRouterWireSet,
+		accessReview.AccessReviewWireSet,
+		accessReview2.AccessReviewRouterWireSet,
 		executor.ExecutorWireSet,
 		fluxcd.DeploymentWireSet,
 		// -------wireset end ----------
@@ -273,6 +298,8 @@ func InitializeApp() (*App, error) {
 
 		repository11.NewBulkEditRepository,
 		wire.Bind(new(repository11.BulkEditRepository), new(*repository11.BulkEditRepositoryImpl)),
+		repository11.NewBulkEditJobRepositoryImpl,
+		wire.Bind(new(repository11.BulkEditJobRepository), new(*repository11.BulkEditJobRepositoryImpl)),
 
 		chartConfig.NewEnvConfigOverrideRepository,
 		wire.Bind(new(chartConfig.EnvConfigOverrideRepository), new(*chartConfig.EnvConfigOverrideRepositoryImpl)),
@@ -348,6 +375,12 @@ func InitializeApp() (*App, error) {
 		// scoped variables start
 		variables.NewScopedVariableServiceImpl,
 		wire.Bind(new(variables.ScopedVariableService), new(*variables.ScopedVariableServiceImpl)),
+		variables.NewScopedVariableVersionServiceImpl,
+		wire.Bind(new(variables.ScopedVariableVersionService), new(*variables.ScopedVariableVersionServiceImpl)),
+		repository10.NewScopedVariableVersionRepositoryImpl,
+		wire.Bind(new(repository10.ScopedVariableVersionRepository), new(*repository10.ScopedVariableVersionRepositoryImpl)),
+		secretBackend.NewSecretBackendServiceImpl,
+		wire.Bind(new(secretBackend.SecretBackendService), new(*secretBackend.SecretBackendServiceImpl)),
 
 		parsers.NewVariableTemplateParserImpl,
 		wire.Bind(new(parsers.VariableTemplateParser), new(*parsers.VariableTemplateParserImpl)),
@@ -379,6 +412,8 @@ func InitializeApp() (*App, error) {
 		service.NewBulkUpdateServiceEntImpl,
 		service.NewBulkUpdateServiceImpl,
 		wire.Bind(new(service.BulkUpdateService), new(*service.BulkUpdateServiceImpl)),
+		service.NewBulkEditJobServiceImpl,
+		wire.Bind(new(service.BulkEditJobService), new(*service.BulkEditJobServiceImpl)),
 
 		repository.NewImageTagRepository,
 		wire.Bind(new(repository.ImageTagRepository), new(*repository.ImageTagRepositoryImpl)),
@@ -481,6 +516,12 @@ func InitializeApp() (*App, error) {
 		wire.Bind(new(notifier.WebhookNotificationService), new(*notifier.WebhookNotificationServiceImpl)),
 		repository.NewWebhookNotificationRepositoryImpl,
 		wire.Bind(new(repository.WebhookNotificationRepository), new(*repository.WebhookNotificationRepositoryImpl)),
+		notifier.NewMsTeamsNotificationServiceImpl,
+		wire.Bind(new(notifier.MsTeamsNotificationService), new(*notifier.MsTeamsNotificationServiceImpl)),
+		repository.NewMsTeamsNotificationRepositoryImpl,
+		wire.Bind(new(repository.MsTeamsNotificationRepository), new(*repository.MsTeamsNotificationRepositoryImpl)),
+		repository.NewNotificationDigestEventRepositoryImpl,
+		wire.Bind(new(repository.NotificationDigestEventRepository), new(*repository.NotificationDigestEventRepositoryImpl)),
 
 		notifier.NewNotificationConfigServiceImpl,
 		wire.Bind(new(notifier.NotificationConfigService), new(*notifier.NotificationConfigServiceImpl)),
@@ -640,6 +681,10 @@ func InitializeApp() (*App, error) {
 		wire.Bind(new(repository4.ChartGroupEntriesRepository), new(*repository4.ChartGroupEntriesRepositoryImpl)),
 		chartGroup.NewChartGroupServiceImpl,
 		wire.Bind(new(chartGroup.ChartGroupService), new(*chartGroup.ChartGroupServiceImpl)),
+		repository4.NewChartGroupRunRepositoryImpl,
+		wire.Bind(new(repository4.ChartGroupRunRepository), new(*repository4.ChartGroupRunRepositoryImpl)),
+		chartGroup.NewChartGroupRunServiceImpl,
+		wire.Bind(new(chartGroup.ChartGroupRunService), new(*chartGroup.ChartGroupRunServiceImpl)),
 		chartGroup2.NewChartGroupRestHandlerImpl,
 		wire.Bind(new(chartGroup2.ChartGroupRestHandler), new(*chartGroup2.ChartGroupRestHandlerImpl)),
 		chartGroup2.NewChartGroupRouterImpl,
@@ -853,6 +898,10 @@ func InitializeApp() (*App, error) {
 		cron.NewCiTriggerCronImpl,
 		wire.Bind(new(cron.CiTriggerCron), new(*cron.CiTriggerCronImpl)),
 
+		cron.GetTektonWorkflowStatusCronConfig,
+		cron.NewTektonWorkflowStatusCronImpl,
+		wire.Bind(new(cron.TektonWorkflowStatusCron), new(*cron.TektonWorkflowStatusCronImpl)),
+
 		status2.NewPipelineStatusTimelineRestHandlerImpl,
 		wire.Bind(new(status2.PipelineStatusTimelineRestHandler), new(*status2.PipelineStatusTimelineRestHandlerImpl)),
 
@@ -925,6 +974,8 @@ func InitializeApp() (*App, error) {
 		wire.Bind(new(executors.ArgoWorkflowExecutor), new(*executors.ArgoWorkflowExecutorImpl)),
 		executors.NewSystemWorkflowExecutorImpl,
 		wire.Bind(new(executors.SystemWorkflowExecutor), new(*executors.SystemWorkflowExecutorImpl)),
+		executors.NewTektonWorkflowExecutorImpl,
+		wire.Bind(new(executors.TektonWorkflowExecutor), new(*executors.TektonWorkflowExecutorImpl)),
 		repository5.NewManifestPushConfigRepository,
 		wire.Bind(new(repository5.ManifestPushConfigRepository), new(*repository5.ManifestPushConfigRepositoryImpl)),
 		publish.NewGitOpsManifestPushServiceImpl,
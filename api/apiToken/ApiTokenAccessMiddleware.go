@@ -0,0 +1,88 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package apiToken
+
+import (
+	"errors"
+	"github.com/devtron-labs/authenticator/middleware"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/apiToken"
+	userBean "github.com/devtron-labs/devtron/pkg/auth/user/bean"
+	"github.com/golang-jwt/jwt/v4"
+	"go.uber.org/zap"
+	"net/http"
+	"strings"
+)
+
+type ApiTokenAccessMiddleware interface {
+	// ApiTokenAccessMiddleware rejects the requests made with api tokens outside of the scope of the token
+	// and records the use of the tokens
+	ApiTokenAccessMiddleware(next http.Handler) http.Handler
+}
+
+type ApiTokenAccessMiddlewareImpl struct {
+	logger          *zap.SugaredLogger
+	apiTokenService apiToken.ApiTokenService
+}
+
+func NewApiTokenAccessMiddlewareImpl(logger *zap.SugaredLogger, apiTokenService apiToken.ApiTokenService) *ApiTokenAccessMiddlewareImpl {
+	return &ApiTokenAccessMiddlewareImpl{
+		logger:          logger,
+		apiTokenService: apiTokenService,
+	}
+}
+
+func (impl ApiTokenAccessMiddlewareImpl) ApiTokenAccessMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		email, ok := getApiTokenEmail(r)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+		allowed, reason, err := impl.apiTokenService.AuthorizeApiTokenRequest(email, r)
+		if err != nil {
+			impl.logger.Errorw("error in authorizing api token request", "email", email, "path", r.URL.Path, "err", err)
+			common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+			return
+		}
+		if !allowed {
+			common.WriteJsonResp(w, errors.New(reason), nil, http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// getApiTokenEmail returns the email of the api token user if the request is made with an api token. The token is
+// parsed without verification, as it is verified by the auth middleware before and is only used to restrict access.
+func getApiTokenEmail(r *http.Request) (string, bool) {
+	token := r.Header.Get(middleware.ApiTokenHeaderKey)
+	if len(token) == 0 {
+		token = r.Header.Get("token")
+	}
+	if len(token) == 0 {
+		return "", false
+	}
+	claims := &apiToken.ApiTokenCustomClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(token, claims); err != nil {
+		return "", false
+	}
+	if claims.Issuer != middleware.ApiTokenClaimIssuer || !strings.HasPrefix(claims.Email, userBean.API_TOKEN_USER_EMAIL_PREFIX) {
+		return "", false
+	}
+	return claims.Email, true
+}
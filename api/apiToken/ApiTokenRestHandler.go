@@ -37,6 +37,10 @@ type ApiTokenRestHandler interface {
 	UpdateApiToken(w http.ResponseWriter, r *http.Request)
 	DeleteApiToken(w http.ResponseWriter, r *http.Request)
 	GetAllApiTokensForWebhook(w http.ResponseWriter, r *http.Request)
+	RotateApiToken(w http.ResponseWriter, r *http.Request)
+	GetApiTokenScope(w http.ResponseWriter, r *http.Request)
+	UpdateApiTokenScope(w http.ResponseWriter, r *http.Request)
+	GetStaleApiTokens(w http.ResponseWriter, r *http.Request)
 }
 
 type ApiTokenRestHandlerImpl struct {
@@ -245,3 +249,161 @@ func (handler ApiTokenRestHandlerImpl) CheckAuthorizationForWebhook(token string
 	}
 	return true
 }
+
+func (impl ApiTokenRestHandlerImpl) RotateApiToken(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.HandleUnauthorized(w, r)
+		return
+	}
+
+	// handle super-admin RBAC
+	token := r.Header.Get("token")
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionUpdate, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+
+	// get api-token Id
+	vars := mux.Vars(r)
+	apiTokenId, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		impl.logger.Errorw("request err in getting apiTokenId in RotateApiToken", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+
+	// decode request, all the fields are optional
+	request := &apiToken.RotateApiTokenRequest{}
+	if r.ContentLength != 0 {
+		err = json.NewDecoder(r.Body).Decode(request)
+		if err != nil {
+			impl.logger.Errorw("err in decoding request, RotateApiToken", "err", err)
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return
+		}
+	}
+	err = impl.validator.Struct(request)
+	if err != nil {
+		impl.logger.Errorw("validation err in RotateApiToken", "err", err, "request", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+
+	res, err := impl.apiTokenService.RotateApiToken(apiTokenId, request, userId)
+	if err != nil {
+		impl.logger.Errorw("service err, RotateApiToken", "err", err, "apiTokenId", apiTokenId, "request", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, err, res, http.StatusOK)
+}
+
+func (impl ApiTokenRestHandlerImpl) GetApiTokenScope(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.HandleUnauthorized(w, r)
+		return
+	}
+
+	// handle super-admin RBAC
+	token := r.Header.Get("token")
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionUpdate, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+
+	vars := mux.Vars(r)
+	apiTokenId, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		impl.logger.Errorw("request err in getting apiTokenId in GetApiTokenScope", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+
+	res, err := impl.apiTokenService.GetApiTokenScope(apiTokenId)
+	if err != nil {
+		impl.logger.Errorw("service err, GetApiTokenScope", "err", err, "apiTokenId", apiTokenId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, err, res, http.StatusOK)
+}
+
+func (impl ApiTokenRestHandlerImpl) UpdateApiTokenScope(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.HandleUnauthorized(w, r)
+		return
+	}
+
+	// handle super-admin RBAC
+	token := r.Header.Get("token")
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionUpdate, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+
+	vars := mux.Vars(r)
+	apiTokenId, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		impl.logger.Errorw("request err in getting apiTokenId in UpdateApiTokenScope", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+
+	request := &apiToken.ApiTokenScope{}
+	err = json.NewDecoder(r.Body).Decode(request)
+	if err != nil {
+		impl.logger.Errorw("err in decoding request, UpdateApiTokenScope", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	err = impl.validator.Struct(request)
+	if err != nil {
+		impl.logger.Errorw("validation err in UpdateApiTokenScope", "err", err, "request", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+
+	res, err := impl.apiTokenService.UpdateApiTokenScope(apiTokenId, request, userId)
+	if err != nil {
+		impl.logger.Errorw("service err, UpdateApiTokenScope", "err", err, "apiTokenId", apiTokenId, "request", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, err, res, http.StatusOK)
+}
+
+func (impl ApiTokenRestHandlerImpl) GetStaleApiTokens(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.HandleUnauthorized(w, r)
+		return
+	}
+
+	// handle super-admin RBAC
+	token := r.Header.Get("token")
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionUpdate, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+
+	var unusedForDays int
+	if days := r.URL.Query().Get("unusedForDays"); len(days) != 0 {
+		unusedForDays, err = strconv.Atoi(days)
+		if err != nil || unusedForDays < 0 {
+			impl.logger.Errorw("request err in getting unusedForDays in GetStaleApiTokens", "unusedForDays", days, "err", err)
+			common.WriteJsonResp(w, errors.New("invalid unusedForDays"), nil, http.StatusBadRequest)
+			return
+		}
+	}
+
+	res, err := impl.apiTokenService.GetStaleApiTokens(unusedForDays)
+	if err != nil {
+		impl.logger.Errorw("service err, GetStaleApiTokens", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, err, res, http.StatusOK)
+}
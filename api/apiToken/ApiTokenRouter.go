@@ -38,4 +38,8 @@ func (impl ApiTokenRouterImpl) InitApiTokenRouter(configRouter *mux.Router) {
 	configRouter.Path("/{id}").HandlerFunc(impl.apiTokenRestHandler.UpdateApiToken).Methods("PUT")
 	configRouter.Path("/{id}").HandlerFunc(impl.apiTokenRestHandler.DeleteApiToken).Methods("DELETE")
 	configRouter.Path("/webhook").HandlerFunc(impl.apiTokenRestHandler.GetAllApiTokensForWebhook).Methods("GET")
+	configRouter.Path("/stale").HandlerFunc(impl.apiTokenRestHandler.GetStaleApiTokens).Methods("GET")
+	configRouter.Path("/{id}/rotate").HandlerFunc(impl.apiTokenRestHandler.RotateApiToken).Methods("POST")
+	configRouter.Path("/{id}/scope").HandlerFunc(impl.apiTokenRestHandler.GetApiTokenScope).Methods("GET")
+	configRouter.Path("/{id}/scope").HandlerFunc(impl.apiTokenRestHandler.UpdateApiTokenScope).Methods("PUT")
 }
@@ -30,4 +30,6 @@ var ApiTokenWireSet = wire.NewSet(
 	wire.Bind(new(ApiTokenRestHandler), new(*ApiTokenRestHandlerImpl)),
 	NewApiTokenRouterImpl,
 	wire.Bind(new(ApiTokenRouter), new(*ApiTokenRouterImpl)),
+	NewApiTokenAccessMiddlewareImpl,
+	wire.Bind(new(ApiTokenAccessMiddleware), new(*ApiTokenAccessMiddlewareImpl)),
 )
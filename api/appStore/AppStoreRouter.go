@@ -98,6 +98,15 @@ func (router AppStoreRouterImpl) Init(configRouter *mux.Router) {
 	configRouter.Path("/installed-app/notes").Queries("installed-app-id", "{installed-app-id}").Queries("env-id", "{env-id}").
 		HandlerFunc(router.deployRestHandler.FetchNotesForArgoInstalledApp).
 		Methods("GET")
+	configRouter.Path("/installed-app/drift").Queries("installed-app-id", "{installed-app-id}").
+		HandlerFunc(router.deployRestHandler.GetInstalledAppDrift).
+		Methods("GET")
+	configRouter.Path("/installed-app/drift/check").Queries("installed-app-id", "{installed-app-id}").
+		HandlerFunc(router.deployRestHandler.CheckInstalledAppDrift).
+		Methods("POST")
+	configRouter.Path("/installed-app/drift/reconcile").Queries("installed-app-id", "{installed-app-id}").
+		HandlerFunc(router.deployRestHandler.ReconcileInstalledAppDrift).
+		Methods("POST")
 	configRouter.Path("/installed-app").
 		HandlerFunc(router.deployRestHandler.GetAllInstalledApp).Methods("GET")
 	configRouter.Path("/cluster-component/install/{clusterId}").
@@ -25,6 +25,7 @@ import (
 	client "github.com/devtron-labs/devtron/api/helm-app/gRPC"
 	"github.com/devtron-labs/devtron/pkg/appStore/installedApp/service/FullMode"
 	"github.com/devtron-labs/devtron/pkg/appStore/installedApp/service/FullMode/deploymentTypeChange"
+	"github.com/devtron-labs/devtron/pkg/appStore/installedApp/service/FullMode/drift"
 	"github.com/devtron-labs/devtron/pkg/appStore/installedApp/service/FullMode/resource"
 	util3 "github.com/devtron-labs/devtron/pkg/appStore/util"
 	"github.com/devtron-labs/devtron/pkg/bean"
@@ -70,6 +71,9 @@ type InstalledAppRestHandler interface {
 	FetchNotesForArgoInstalledApp(w http.ResponseWriter, r *http.Request)
 	MigrateDeploymentTypeForChartStore(w http.ResponseWriter, r *http.Request)
 	TriggerChartStoreAppAfterMigration(w http.ResponseWriter, r *http.Request)
+	GetInstalledAppDrift(w http.ResponseWriter, r *http.Request)
+	CheckInstalledAppDrift(w http.ResponseWriter, r *http.Request)
+	ReconcileInstalledAppDrift(w http.ResponseWriter, r *http.Request)
 }
 
 type InstalledAppRestHandlerImpl struct {
@@ -91,6 +95,7 @@ type InstalledAppRestHandlerImpl struct {
 	appCrudOperationService                 app2.AppCrudOperationService
 	installedAppDeploymentTypeChangeService deploymentTypeChange.InstalledAppDeploymentTypeChangeService
 	clusterReadService                      read.ClusterReadService
+	installedAppDriftService                drift.InstalledAppDriftService
 }
 
 func NewInstalledAppRestHandlerImpl(Logger *zap.SugaredLogger, userAuthService user.UserService,
@@ -106,7 +111,8 @@ func NewInstalledAppRestHandlerImpl(Logger *zap.SugaredLogger, userAuthService u
 	installedAppRepository repository.InstalledAppRepository,
 	appCrudOperationService app2.AppCrudOperationService,
 	installedAppDeploymentTypeChangeService deploymentTypeChange.InstalledAppDeploymentTypeChangeService,
-	clusterReadService read.ClusterReadService) *InstalledAppRestHandlerImpl {
+	clusterReadService read.ClusterReadService,
+	installedAppDriftService drift.InstalledAppDriftService) *InstalledAppRestHandlerImpl {
 	return &InstalledAppRestHandlerImpl{
 		Logger:                                  Logger,
 		userAuthService:                         userAuthService,
@@ -126,6 +132,7 @@ func NewInstalledAppRestHandlerImpl(Logger *zap.SugaredLogger, userAuthService u
 		appCrudOperationService:                 appCrudOperationService,
 		installedAppDeploymentTypeChangeService: installedAppDeploymentTypeChangeService,
 		clusterReadService:                      clusterReadService,
+		installedAppDriftService:                installedAppDriftService,
 	}
 }
 func (handler *InstalledAppRestHandlerImpl) FetchAppOverview(w http.ResponseWriter, r *http.Request) {
@@ -400,6 +407,7 @@ func (handler *InstalledAppRestHandlerImpl) DeployBulk(w http.ResponseWriter, r
 		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
 		return
 	} else {
+		authRes.RunId = res.RunId
 		res = authRes
 	}
 	common.WriteJsonResp(w, err, res, http.StatusOK)
@@ -722,6 +730,10 @@ func (handler *InstalledAppRestHandlerImpl) FetchAppDetailsForInstalledAppV2(w h
 		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), nil, http.StatusForbidden)
 		return
 	}
+	appDetail.Drift, err = handler.installedAppDriftService.GetDrift(installedAppId)
+	if err != nil {
+		handler.Logger.Errorw("service err, FetchAppDetailsForInstalledAppV2, drift", "err", err, "installedAppId", installedAppId)
+	}
 	common.WriteJsonResp(w, nil, appDetail, http.StatusOK)
 }
 
@@ -971,3 +983,81 @@ func (handler *InstalledAppRestHandlerImpl) TriggerChartStoreAppAfterMigration(w
 	common.WriteJsonResp(w, nil, resp, http.StatusOK)
 	return
 }
+
+func (handler *InstalledAppRestHandlerImpl) GetInstalledAppDrift(w http.ResponseWriter, r *http.Request) {
+	installedAppId, _, ok := handler.checkInstalledAppDriftAuth(w, r, casbin.ActionGet)
+	if !ok {
+		return
+	}
+	res, err := handler.installedAppDriftService.GetDrift(installedAppId)
+	if err != nil {
+		handler.Logger.Errorw("service err, GetInstalledAppDrift", "err", err, "installedAppId", installedAppId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *InstalledAppRestHandlerImpl) CheckInstalledAppDrift(w http.ResponseWriter, r *http.Request) {
+	installedAppId, _, ok := handler.checkInstalledAppDriftAuth(w, r, casbin.ActionGet)
+	if !ok {
+		return
+	}
+	res, err := handler.installedAppDriftService.CheckDrift(r.Context(), installedAppId)
+	if err != nil {
+		handler.Logger.Errorw("service err, CheckInstalledAppDrift", "err", err, "installedAppId", installedAppId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *InstalledAppRestHandlerImpl) ReconcileInstalledAppDrift(w http.ResponseWriter, r *http.Request) {
+	installedAppId, userId, ok := handler.checkInstalledAppDriftAuth(w, r, casbin.ActionUpdate)
+	if !ok {
+		return
+	}
+	res, err := handler.installedAppDriftService.ReconcileDrift(r.Context(), installedAppId, userId)
+	if err != nil {
+		handler.Logger.Errorw("service err, ReconcileInstalledAppDrift", "err", err, "installedAppId", installedAppId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+// checkInstalledAppDriftAuth writes the error response and returns false if the user is not allowed the action on the installed app
+func (handler *InstalledAppRestHandlerImpl) checkInstalledAppDriftAuth(w http.ResponseWriter, r *http.Request, action string) (int, int32, bool) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusUnauthorized)
+		return 0, 0, false
+	}
+	installedAppId, err := strconv.Atoi(mux.Vars(r)["installed-app-id"])
+	if err != nil {
+		handler.Logger.Errorw("request err, installed app drift", "err", err, "installedAppId", installedAppId)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return 0, 0, false
+	}
+	installedApp, err := handler.installedAppService.GetInstalledAppById(installedAppId)
+	if err != nil && err != pg.ErrNoRows {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return 0, 0, false
+	} else if err == pg.ErrNoRows || installedApp == nil {
+		common.WriteJsonResp(w, errors.New("helm app doses not exist"), nil, http.StatusNotFound)
+		return 0, 0, false
+	}
+	token := r.Header.Get("token")
+	object, object2 := handler.enforcerUtil.GetHelmObjectByAppNameAndEnvId(installedApp.App.AppName, installedApp.EnvironmentId)
+	var ok bool
+	if object2 == "" {
+		ok = handler.enforcer.Enforce(token, casbin.ResourceHelmApp, action, object)
+	} else {
+		ok = handler.enforcer.Enforce(token, casbin.ResourceHelmApp, action, object) || handler.enforcer.Enforce(token, casbin.ResourceHelmApp, action, object2)
+	}
+	if !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), nil, http.StatusForbidden)
+		return 0, 0, false
+	}
+	return installedAppId, userId, true
+}
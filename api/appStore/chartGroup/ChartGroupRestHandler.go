@@ -26,6 +26,7 @@ import (
 	"github.com/devtron-labs/devtron/pkg/appStore/chartGroup"
 	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
 	"github.com/devtron-labs/devtron/pkg/auth/user"
+	"github.com/devtron-labs/devtron/util/rbac"
 	"go.uber.org/zap"
 	"gopkg.in/go-playground/validator.v9"
 )
@@ -33,22 +34,27 @@ import (
 const CHART_GROUP_DELETE_SUCCESS_RESP = "Chart group deleted successfully."
 
 type ChartGroupRestHandlerImpl struct {
-	ChartGroupService chartGroup.ChartGroupService
-	Logger            *zap.SugaredLogger
-	userAuthService   user.UserService
-	enforcer          casbin.Enforcer
-	validator         *validator.Validate
+	ChartGroupService    chartGroup.ChartGroupService
+	chartGroupRunService chartGroup.ChartGroupRunService
+	Logger               *zap.SugaredLogger
+	userAuthService      user.UserService
+	enforcer             casbin.Enforcer
+	enforcerUtil         rbac.EnforcerUtil
+	validator            *validator.Validate
 }
 
 func NewChartGroupRestHandlerImpl(ChartGroupService chartGroup.ChartGroupService,
 	Logger *zap.SugaredLogger, userAuthService user.UserService,
-	enforcer casbin.Enforcer, validator *validator.Validate) *ChartGroupRestHandlerImpl {
+	enforcer casbin.Enforcer, validator *validator.Validate,
+	chartGroupRunService chartGroup.ChartGroupRunService, enforcerUtil rbac.EnforcerUtil) *ChartGroupRestHandlerImpl {
 	return &ChartGroupRestHandlerImpl{
-		ChartGroupService: ChartGroupService,
-		Logger:            Logger,
-		userAuthService:   userAuthService,
-		validator:         validator,
-		enforcer:          enforcer,
+		ChartGroupService:    ChartGroupService,
+		chartGroupRunService: chartGroupRunService,
+		Logger:               Logger,
+		userAuthService:      userAuthService,
+		validator:            validator,
+		enforcer:             enforcer,
+		enforcerUtil:         enforcerUtil,
 	}
 }
 
@@ -61,6 +67,11 @@ type ChartGroupRestHandler interface {
 	GetChartGroupInstallationDetail(w http.ResponseWriter, r *http.Request)
 	GetChartGroupListMin(w http.ResponseWriter, r *http.Request)
 	DeleteChartGroup(w http.ResponseWriter, r *http.Request)
+
+	GetChartGroupUpgradePlan(w http.ResponseWriter, r *http.Request)
+	UpgradeChartGroup(w http.ResponseWriter, r *http.Request)
+	GetChartGroupRun(w http.ResponseWriter, r *http.Request)
+	GetChartGroupRuns(w http.ResponseWriter, r *http.Request)
 }
 
 func (impl *ChartGroupRestHandlerImpl) CreateChartGroup(w http.ResponseWriter, r *http.Request) {
@@ -356,3 +367,158 @@ func (impl *ChartGroupRestHandlerImpl) DeleteChartGroup(w http.ResponseWriter, r
 	}
 	common.WriteJsonResp(w, err, CHART_GROUP_DELETE_SUCCESS_RESP, http.StatusOK)
 }
+
+func (impl *ChartGroupRestHandlerImpl) GetChartGroupUpgradePlan(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.HandleUnauthorized(w, r)
+		return
+	}
+	chartGroupId, err := common.ExtractIntPathParamWithContext(w, r, "chartGroupId")
+	if err != nil {
+		return
+	}
+	groupInstallationId := r.URL.Query().Get("groupInstallationId")
+
+	//RBAC block starts from here
+	token := r.Header.Get("token")
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceChartGroup, casbin.ActionGet, ""); !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	//RBAC block ends here
+
+	res, err := impl.chartGroupRunService.GetUpgradePlan(chartGroupId, groupInstallationId)
+	if err != nil {
+		impl.Logger.Errorw("service err, GetChartGroupUpgradePlan", "err", err, "chartGroupId", chartGroupId, "groupInstallationId", groupInstallationId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, err, res, http.StatusOK)
+}
+
+func (impl *ChartGroupRestHandlerImpl) UpgradeChartGroup(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.HandleUnauthorized(w, r)
+		return
+	}
+	decoder := json.NewDecoder(r.Body)
+	var request chartGroup.ChartGroupUpgradeRequest
+	err = decoder.Decode(&request)
+	if err != nil {
+		impl.Logger.Errorw("request err, UpgradeChartGroup", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	err = impl.validator.Struct(request)
+	if err != nil {
+		impl.Logger.Errorw("validate err, UpgradeChartGroup", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	request.UserId = userId
+	impl.Logger.Infow("request payload, UpgradeChartGroup", "payload", request)
+
+	//RBAC block starts from here
+	token := r.Header.Get("token")
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceChartGroup, casbin.ActionUpdate, ""); !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	plan, err := impl.chartGroupRunService.GetUpgradePlan(request.ChartGroupId, request.GroupInstallationId)
+	if err != nil {
+		impl.Logger.Errorw("service err, UpgradeChartGroup", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	// the user has to be allowed to update every app upgraded by the run
+	for _, item := range plan.Items {
+		if item.Action != chartGroup.ChartGroupUpgradeActionUpgrade {
+			continue
+		}
+		rbacObject, rbacObject2 := impl.enforcerUtil.GetHelmObject(item.AppId, item.EnvironmentId)
+		ok := impl.enforcer.Enforce(token, casbin.ResourceHelmApp, casbin.ActionUpdate, rbacObject)
+		if !ok && rbacObject2 != "" {
+			ok = impl.enforcer.Enforce(token, casbin.ResourceHelmApp, casbin.ActionUpdate, rbacObject2)
+		}
+		if !ok {
+			common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+			return
+		}
+	}
+	//RBAC block ends here
+
+	request.GroupInstallationId = plan.GroupInstallationId
+	res, err := impl.chartGroupRunService.StartUpgradeRun(&request)
+	if err != nil {
+		impl.Logger.Errorw("service err, UpgradeChartGroup", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, err, res, http.StatusOK)
+}
+
+func (impl *ChartGroupRestHandlerImpl) GetChartGroupRun(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.HandleUnauthorized(w, r)
+		return
+	}
+	runId, err := common.ExtractIntPathParamWithContext(w, r, "runId")
+	if err != nil {
+		return
+	}
+
+	//RBAC block starts from here
+	token := r.Header.Get("token")
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceChartGroup, casbin.ActionGet, ""); !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	//RBAC block ends here
+
+	res, err := impl.chartGroupRunService.GetRun(runId)
+	if err != nil {
+		impl.Logger.Errorw("service err, GetChartGroupRun", "err", err, "runId", runId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, err, res, http.StatusOK)
+}
+
+func (impl *ChartGroupRestHandlerImpl) GetChartGroupRuns(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.HandleUnauthorized(w, r)
+		return
+	}
+	chartGroupId, err := common.ExtractIntPathParamWithContext(w, r, "chartGroupId")
+	if err != nil {
+		return
+	}
+	offset, err := common.ExtractIntQueryParam(w, r, "offset", 0)
+	if err != nil {
+		return
+	}
+	size, err := common.ExtractIntQueryParam(w, r, "size", 20)
+	if err != nil {
+		return
+	}
+
+	//RBAC block starts from here
+	token := r.Header.Get("token")
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceChartGroup, casbin.ActionGet, ""); !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	//RBAC block ends here
+
+	res, err := impl.chartGroupRunService.GetRuns(chartGroupId, offset, size)
+	if err != nil {
+		impl.Logger.Errorw("service err, GetChartGroupRuns", "err", err, "chartGroupId", chartGroupId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, err, res, http.StatusOK)
+}
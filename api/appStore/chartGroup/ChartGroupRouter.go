@@ -41,6 +41,14 @@ func (impl *ChartGroupRouterImpl) InitChartGroupRouter(chartGroupRouter *mux.Rou
 		HandlerFunc(impl.ChartGroupRestHandler.SaveChartGroupEntries).Methods("PUT")
 	chartGroupRouter.Path("/list").
 		HandlerFunc(impl.ChartGroupRestHandler.GetChartGroupList).Methods("GET")
+	chartGroupRouter.Path("/upgrade").
+		HandlerFunc(impl.ChartGroupRestHandler.UpgradeChartGroup).Methods("POST")
+	chartGroupRouter.Path("/upgrade-plan/{chartGroupId}").
+		HandlerFunc(impl.ChartGroupRestHandler.GetChartGroupUpgradePlan).Methods("GET")
+	chartGroupRouter.Path("/runs/{chartGroupId}").
+		HandlerFunc(impl.ChartGroupRestHandler.GetChartGroupRuns).Methods("GET")
+	chartGroupRouter.Path("/run/{runId}").
+		HandlerFunc(impl.ChartGroupRestHandler.GetChartGroupRun).Methods("GET")
 	chartGroupRouter.Path("/{chartGroupId}").
 		HandlerFunc(impl.ChartGroupRestHandler.GetChartGroupWithChartMetaData).Methods("GET")
 	chartGroupRouter.Path("/installation-detail/{chartGroupId}").
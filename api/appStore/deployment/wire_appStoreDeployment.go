@@ -26,6 +26,7 @@ import (
 	deployment2 "github.com/devtron-labs/devtron/pkg/appStore/installedApp/service/EAMode/deployment"
 	"github.com/devtron-labs/devtron/pkg/appStore/installedApp/service/FullMode"
 	"github.com/devtron-labs/devtron/pkg/appStore/installedApp/service/FullMode/deploymentTypeChange"
+	"github.com/devtron-labs/devtron/pkg/appStore/installedApp/service/FullMode/drift"
 	"github.com/devtron-labs/devtron/pkg/appStore/installedApp/service/FullMode/resource"
 	appStoreDeploymentCommon "github.com/devtron-labs/devtron/pkg/appStore/installedApp/service/common"
 	"github.com/google/wire"
@@ -75,5 +76,10 @@ var FullModeWireSet = wire.NewSet(
 	deploymentTypeChange.NewInstalledAppDeploymentTypeChangeServiceImpl,
 	wire.Bind(new(deploymentTypeChange.InstalledAppDeploymentTypeChangeService), new(*deploymentTypeChange.InstalledAppDeploymentTypeChangeServiceImpl)),
 
+	repository3.NewInstalledAppDriftRepositoryImpl,
+	wire.Bind(new(repository3.InstalledAppDriftRepository), new(*repository3.InstalledAppDriftRepositoryImpl)),
+	drift.NewInstalledAppDriftServiceImpl,
+	wire.Bind(new(drift.InstalledAppDriftService), new(*drift.InstalledAppDriftServiceImpl)),
+
 	installedAppReader.WireSet,
 )
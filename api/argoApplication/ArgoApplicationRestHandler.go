@@ -18,11 +18,14 @@ package argoApplication
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"github.com/devtron-labs/devtron/api/restHandler/common"
 	"github.com/devtron-labs/devtron/pkg/argoApplication"
+	"github.com/devtron-labs/devtron/pkg/argoApplication/bean"
 	"github.com/devtron-labs/devtron/pkg/argoApplication/read"
 	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
 	"go.uber.org/zap"
 	"net/http"
 	"strconv"
@@ -32,6 +35,13 @@ import (
 type ArgoApplicationRestHandler interface {
 	ListApplications(w http.ResponseWriter, r *http.Request)
 	GetApplicationDetail(w http.ResponseWriter, r *http.Request)
+
+	ListApplicationSets(w http.ResponseWriter, r *http.Request)
+	GetApplicationSetDetail(w http.ResponseWriter, r *http.Request)
+	GetApplicationSetTree(w http.ResponseWriter, r *http.Request)
+	HibernateApplicationSet(w http.ResponseWriter, r *http.Request)
+	UnHibernateApplicationSet(w http.ResponseWriter, r *http.Request)
+	SyncApplicationSet(w http.ResponseWriter, r *http.Request)
 }
 
 type ArgoApplicationRestHandlerImpl struct {
@@ -39,15 +49,18 @@ type ArgoApplicationRestHandlerImpl struct {
 	readService            read.ArgoApplicationReadService
 	logger                 *zap.SugaredLogger
 	enforcer               casbin.Enforcer
+	userService            user.UserService
 }
 
 func NewArgoApplicationRestHandlerImpl(argoApplicationService argoApplication.ArgoApplicationService,
-	readService read.ArgoApplicationReadService, logger *zap.SugaredLogger, enforcer casbin.Enforcer) *ArgoApplicationRestHandlerImpl {
+	readService read.ArgoApplicationReadService, logger *zap.SugaredLogger, enforcer casbin.Enforcer,
+	userService user.UserService) *ArgoApplicationRestHandlerImpl {
 	return &ArgoApplicationRestHandlerImpl{
 		argoApplicationService: argoApplicationService,
 		readService:            readService,
 		logger:                 logger,
 		enforcer:               enforcer,
+		userService:            userService,
 	}
 
 }
@@ -116,3 +129,169 @@ func (handler *ArgoApplicationRestHandlerImpl) GetApplicationDetail(w http.Respo
 	}
 	common.WriteJsonResp(w, nil, resp, http.StatusOK)
 }
+
+func (handler *ArgoApplicationRestHandlerImpl) ListApplicationSets(w http.ResponseWriter, r *http.Request) {
+	// handle super-admin RBAC
+	token := r.Header.Get("token")
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionGet, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	clusterIdString := r.URL.Query().Get("clusterIds")
+	var clusterIds []int
+	if clusterIdString != "" {
+		for _, clusterId := range strings.Split(clusterIdString, ",") {
+			id, err := strconv.Atoi(clusterId)
+			if err != nil {
+				handler.logger.Errorw("error in converting clusterId", "err", err, "clusterIdString", clusterIdString)
+				common.WriteJsonResp(w, err, "please send valid cluster Ids", http.StatusBadRequest)
+				return
+			}
+			clusterIds = append(clusterIds, id)
+		}
+	}
+	resp, err := handler.argoApplicationService.ListApplicationSets(clusterIds)
+	if err != nil {
+		handler.logger.Errorw("error in listing argo application sets", "err", err, "clusterIds", clusterIds)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *ArgoApplicationRestHandlerImpl) GetApplicationSetDetail(w http.ResponseWriter, r *http.Request) {
+	// handle super-admin RBAC
+	token := r.Header.Get("token")
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionGet, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	applicationSet, err := getApplicationSetIdentifierFromQuery(r)
+	if err != nil {
+		handler.logger.Errorw("error in getting application set identifier", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	resp, err := handler.argoApplicationService.GetApplicationSetDetail(r.Context(), applicationSet)
+	if err != nil {
+		handler.logger.Errorw("error in getting argo application set detail", "err", err, "applicationSet", applicationSet)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *ArgoApplicationRestHandlerImpl) GetApplicationSetTree(w http.ResponseWriter, r *http.Request) {
+	// handle super-admin RBAC
+	token := r.Header.Get("token")
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionGet, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	applicationSet, err := getApplicationSetIdentifierFromQuery(r)
+	if err != nil {
+		handler.logger.Errorw("error in getting application set identifier", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	ctx := context.WithValue(r.Context(), "token", token)
+	resp, err := handler.argoApplicationService.GetApplicationSetTree(ctx, applicationSet)
+	if err != nil {
+		handler.logger.Errorw("error in getting argo application set tree", "err", err, "applicationSet", applicationSet)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *ArgoApplicationRestHandlerImpl) HibernateApplicationSet(w http.ResponseWriter, r *http.Request) {
+	applicationSet, ok := handler.decodeAndAuthorizeApplicationSetAction(w, r)
+	if !ok {
+		return
+	}
+	resp, err := handler.argoApplicationService.HibernateApplicationSet(r.Context(), applicationSet)
+	if err != nil {
+		handler.logger.Errorw("error in hibernating argo application set", "err", err, "applicationSet", applicationSet)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *ArgoApplicationRestHandlerImpl) UnHibernateApplicationSet(w http.ResponseWriter, r *http.Request) {
+	applicationSet, ok := handler.decodeAndAuthorizeApplicationSetAction(w, r)
+	if !ok {
+		return
+	}
+	resp, err := handler.argoApplicationService.UnHibernateApplicationSet(r.Context(), applicationSet)
+	if err != nil {
+		handler.logger.Errorw("error in un-hibernating argo application set", "err", err, "applicationSet", applicationSet)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *ArgoApplicationRestHandlerImpl) SyncApplicationSet(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	applicationSet, ok := handler.decodeAndAuthorizeApplicationSetAction(w, r)
+	if !ok {
+		return
+	}
+	userEmail, err := handler.userService.GetEmailById(userId)
+	if err != nil {
+		handler.logger.Errorw("error in getting user email", "err", err, "userId", userId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	resp, err := handler.argoApplicationService.SyncApplicationSet(r.Context(), applicationSet, userEmail)
+	if err != nil {
+		handler.logger.Errorw("error in syncing argo application set", "err", err, "applicationSet", applicationSet)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+// decodeAndAuthorizeApplicationSetAction decodes the application set of an action request and enforces super-admin RBAC,
+// the response is written in case of failure
+func (handler *ArgoApplicationRestHandlerImpl) decodeAndAuthorizeApplicationSetAction(w http.ResponseWriter, r *http.Request) (*bean.ArgoApplicationSetIdentifier, bool) {
+	token := r.Header.Get("token")
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionUpdate, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return nil, false
+	}
+	applicationSet := &bean.ArgoApplicationSetIdentifier{}
+	err := json.NewDecoder(r.Body).Decode(applicationSet)
+	if err != nil {
+		handler.logger.Errorw("error in decoding request body", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return nil, false
+	}
+	if applicationSet.ClusterId <= 0 || len(applicationSet.Namespace) == 0 || len(applicationSet.Name) == 0 {
+		common.WriteJsonResp(w, errors.New("clusterId, namespace and name of the application set are required"), nil, http.StatusBadRequest)
+		return nil, false
+	}
+	return applicationSet, true
+}
+
+func getApplicationSetIdentifierFromQuery(r *http.Request) (*bean.ArgoApplicationSetIdentifier, error) {
+	v := r.URL.Query()
+	clusterId, err := strconv.Atoi(v.Get("clusterId"))
+	if err != nil || clusterId <= 0 {
+		return nil, errors.New("please send a valid clusterId")
+	}
+	applicationSet := &bean.ArgoApplicationSetIdentifier{
+		ClusterId: clusterId,
+		Namespace: v.Get("namespace"),
+		Name:      v.Get("name"),
+	}
+	if len(applicationSet.Namespace) == 0 || len(applicationSet.Name) == 0 {
+		return nil, errors.New("namespace and name of the application set are required")
+	}
+	return applicationSet, nil
+}
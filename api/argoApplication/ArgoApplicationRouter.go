@@ -40,4 +40,28 @@ func (impl *ArgoApplicationRouterImpl) InitArgoApplicationRouter(argoApplication
 	argoApplicationRouter.Path("/detail").
 		Methods("GET").
 		HandlerFunc(impl.argoApplicationRestHandler.GetApplicationDetail)
+
+	argoApplicationRouter.Path("/application-set").
+		Methods("GET").
+		HandlerFunc(impl.argoApplicationRestHandler.ListApplicationSets)
+
+	argoApplicationRouter.Path("/application-set/detail").
+		Methods("GET").
+		HandlerFunc(impl.argoApplicationRestHandler.GetApplicationSetDetail)
+
+	argoApplicationRouter.Path("/application-set/tree").
+		Methods("GET").
+		HandlerFunc(impl.argoApplicationRestHandler.GetApplicationSetTree)
+
+	argoApplicationRouter.Path("/application-set/hibernate").
+		Methods("POST").
+		HandlerFunc(impl.argoApplicationRestHandler.HibernateApplicationSet)
+
+	argoApplicationRouter.Path("/application-set/unhibernate").
+		Methods("POST").
+		HandlerFunc(impl.argoApplicationRestHandler.UnHibernateApplicationSet)
+
+	argoApplicationRouter.Path("/application-set/sync").
+		Methods("POST").
+		HandlerFunc(impl.argoApplicationRestHandler.SyncApplicationSet)
 }
@@ -0,0 +1,247 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accessGrant
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/auth/accessGrant"
+	"github.com/devtron-labs/devtron/pkg/auth/accessGrant/bean"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+	"net/http"
+	"strconv"
+)
+
+type AccessGrantRestHandler interface {
+	GetAccessGrants(w http.ResponseWriter, r *http.Request)
+	GetAccessGrant(w http.ResponseWriter, r *http.Request)
+	GrantAccess(w http.ResponseWriter, r *http.Request)
+	RequestAccess(w http.ResponseWriter, r *http.Request)
+	ApproveRequest(w http.ResponseWriter, r *http.Request)
+	RejectRequest(w http.ResponseWriter, r *http.Request)
+	CancelRequest(w http.ResponseWriter, r *http.Request)
+	RevokeGrant(w http.ResponseWriter, r *http.Request)
+}
+
+type AccessGrantRestHandlerImpl struct {
+	logger             *zap.SugaredLogger
+	userService        user.UserService
+	accessGrantService accessGrant.AccessGrantService
+	enforcer           casbin.Enforcer
+	validator          *validator.Validate
+}
+
+func NewAccessGrantRestHandlerImpl(logger *zap.SugaredLogger,
+	userService user.UserService,
+	accessGrantService accessGrant.AccessGrantService,
+	enforcer casbin.Enforcer,
+	validator *validator.Validate) *AccessGrantRestHandlerImpl {
+	return &AccessGrantRestHandlerImpl{
+		logger:             logger,
+		userService:        userService,
+		accessGrantService: accessGrantService,
+		enforcer:           enforcer,
+		validator:          validator,
+	}
+}
+
+func (handler *AccessGrantRestHandlerImpl) GetAccessGrants(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.HandleUnauthorized(w, r)
+		return
+	}
+	v := r.URL.Query()
+	request := &bean.AccessGrantListRequest{
+		Status:      bean.AccessGrantStatus(v.Get("status")),
+		SubjectType: bean.SubjectType(v.Get("subjectType")),
+	}
+	if subjectId := v.Get("subjectId"); len(subjectId) != 0 {
+		id, err := strconv.Atoi(subjectId)
+		if err != nil {
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return
+		}
+		request.SubjectId = int32(id)
+	}
+	// users who are not super admins only see their own requests and grants
+	if ok := handler.enforcer.Enforce(r.Header.Get("token"), casbin.ResourceGlobal, casbin.ActionGet, "*"); !ok {
+		request.RequestedBy = userId
+	}
+	accessGrants, err := handler.accessGrantService.GetAccessGrants(request)
+	if err != nil {
+		handler.logger.Errorw("service err, GetAccessGrants", "request", request, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, accessGrants, http.StatusOK)
+}
+
+func (handler *AccessGrantRestHandlerImpl) GetAccessGrant(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.HandleUnauthorized(w, r)
+		return
+	}
+	id, err := common.ExtractIntPathParamWithContext(w, r, "id")
+	if err != nil {
+		return
+	}
+	accessGrant, err := handler.accessGrantService.GetAccessGrant(id)
+	if err != nil {
+		handler.logger.Errorw("service err, GetAccessGrant", "id", id, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	isOwnGrant := accessGrant.RequestedBy == userId || (accessGrant.SubjectType == bean.SubjectTypeUser && accessGrant.SubjectId == userId)
+	if !isOwnGrant {
+		if ok := handler.enforcer.Enforce(r.Header.Get("token"), casbin.ResourceGlobal, casbin.ActionGet, "*"); !ok {
+			common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+			return
+		}
+	}
+	common.WriteJsonResp(w, nil, accessGrant, http.StatusOK)
+}
+
+func (handler *AccessGrantRestHandlerImpl) GrantAccess(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.HandleUnauthorized(w, r)
+		return
+	}
+	if ok := handler.enforcer.Enforce(r.Header.Get("token"), casbin.ResourceGlobal, casbin.ActionUpdate, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	request, ok := handler.decodeAccessGrantRequest(w, r)
+	if !ok {
+		return
+	}
+	request.UserId = userId
+	accessGrant, err := handler.accessGrantService.GrantAccess(request)
+	if err != nil {
+		handler.logger.Errorw("service err, GrantAccess", "request", request, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, accessGrant, http.StatusOK)
+}
+
+func (handler *AccessGrantRestHandlerImpl) RequestAccess(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.HandleUnauthorized(w, r)
+		return
+	}
+	request, ok := handler.decodeAccessGrantRequest(w, r)
+	if !ok {
+		return
+	}
+	request.UserId = userId
+	accessGrant, err := handler.accessGrantService.RequestAccess(request)
+	if err != nil {
+		handler.logger.Errorw("service err, RequestAccess", "request", request, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, accessGrant, http.StatusOK)
+}
+
+func (handler *AccessGrantRestHandlerImpl) ApproveRequest(w http.ResponseWriter, r *http.Request) {
+	handler.review(w, r, "ApproveRequest", handler.accessGrantService.ApproveRequest)
+}
+
+func (handler *AccessGrantRestHandlerImpl) RejectRequest(w http.ResponseWriter, r *http.Request) {
+	handler.review(w, r, "RejectRequest", handler.accessGrantService.RejectRequest)
+}
+
+func (handler *AccessGrantRestHandlerImpl) RevokeGrant(w http.ResponseWriter, r *http.Request) {
+	handler.review(w, r, "RevokeGrant", handler.accessGrantService.RevokeGrant)
+}
+
+func (handler *AccessGrantRestHandlerImpl) CancelRequest(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.HandleUnauthorized(w, r)
+		return
+	}
+	id, err := common.ExtractIntPathParamWithContext(w, r, "id")
+	if err != nil {
+		return
+	}
+	// the service allows the requester only to cancel the request
+	accessGrant, err := handler.accessGrantService.CancelRequest(id, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, CancelRequest", "id", id, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, accessGrant, http.StatusOK)
+}
+
+// review approves, rejects or revokes an access grant, which is allowed for super admins only
+func (handler *AccessGrantRestHandlerImpl) review(w http.ResponseWriter, r *http.Request, operation string,
+	reviewFunc func(id int, request *bean.AccessGrantReviewRequest) (*bean.AccessGrantDto, error)) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.HandleUnauthorized(w, r)
+		return
+	}
+	if ok := handler.enforcer.Enforce(r.Header.Get("token"), casbin.ResourceGlobal, casbin.ActionUpdate, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	id, err := common.ExtractIntPathParamWithContext(w, r, "id")
+	if err != nil {
+		return
+	}
+	request := &bean.AccessGrantReviewRequest{}
+	if r.ContentLength != 0 {
+		if err = json.NewDecoder(r.Body).Decode(request); err != nil {
+			handler.logger.Errorw("request err, "+operation, "id", id, "err", err)
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return
+		}
+	}
+	request.UserId = userId
+	accessGrant, err := reviewFunc(id, request)
+	if err != nil {
+		handler.logger.Errorw("service err, "+operation, "id", id, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, accessGrant, http.StatusOK)
+}
+
+func (handler *AccessGrantRestHandlerImpl) decodeAccessGrantRequest(w http.ResponseWriter, r *http.Request) (*bean.AccessGrantRequest, bool) {
+	request := &bean.AccessGrantRequest{}
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+		handler.logger.Errorw("request err, decodeAccessGrantRequest", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return nil, false
+	}
+	if err := handler.validator.Struct(request); err != nil {
+		handler.logger.Errorw("validation err, decodeAccessGrantRequest", "request", request, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return nil, false
+	}
+	return request, true
+}
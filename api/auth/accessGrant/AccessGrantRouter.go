@@ -0,0 +1,67 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accessGrant
+
+import "github.com/gorilla/mux"
+
+type AccessGrantRouter interface {
+	InitAccessGrantRouter(accessGrantRouter *mux.Router)
+}
+
+type AccessGrantRouterImpl struct {
+	accessGrantRestHandler AccessGrantRestHandler
+}
+
+func NewAccessGrantRouterImpl(accessGrantRestHandler AccessGrantRestHandler) *AccessGrantRouterImpl {
+	return &AccessGrantRouterImpl{
+		accessGrantRestHandler: accessGrantRestHandler,
+	}
+}
+
+func (impl *AccessGrantRouterImpl) InitAccessGrantRouter(accessGrantRouter *mux.Router) {
+	accessGrantRouter.Path("").
+		HandlerFunc(impl.accessGrantRestHandler.GetAccessGrants).
+		Methods("GET")
+
+	accessGrantRouter.Path("").
+		HandlerFunc(impl.accessGrantRestHandler.GrantAccess).
+		Methods("POST")
+
+	accessGrantRouter.Path("/request").
+		HandlerFunc(impl.accessGrantRestHandler.RequestAccess).
+		Methods("POST")
+
+	accessGrantRouter.Path("/{id}").
+		HandlerFunc(impl.accessGrantRestHandler.GetAccessGrant).
+		Methods("GET")
+
+	accessGrantRouter.Path("/{id}/approve").
+		HandlerFunc(impl.accessGrantRestHandler.ApproveRequest).
+		Methods("PUT")
+
+	accessGrantRouter.Path("/{id}/reject").
+		HandlerFunc(impl.accessGrantRestHandler.RejectRequest).
+		Methods("PUT")
+
+	accessGrantRouter.Path("/{id}/cancel").
+		HandlerFunc(impl.accessGrantRestHandler.CancelRequest).
+		Methods("PUT")
+
+	accessGrantRouter.Path("/{id}/revoke").
+		HandlerFunc(impl.accessGrantRestHandler.RevokeGrant).
+		Methods("PUT")
+}
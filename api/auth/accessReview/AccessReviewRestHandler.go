@@ -0,0 +1,166 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accessReview
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/auth/accessReview"
+	"github.com/devtron-labs/devtron/pkg/auth/accessReview/bean"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type AccessReviewRestHandler interface {
+	WhoCan(w http.ResponseWriter, r *http.Request)
+	Explain(w http.ResponseWriter, r *http.Request)
+	GetEntitlementReport(w http.ResponseWriter, r *http.Request)
+}
+
+type AccessReviewRestHandlerImpl struct {
+	logger              *zap.SugaredLogger
+	userService         user.UserService
+	accessReviewService accessReview.AccessReviewService
+	enforcer            casbin.Enforcer
+	validator           *validator.Validate
+}
+
+func NewAccessReviewRestHandlerImpl(logger *zap.SugaredLogger,
+	userService user.UserService,
+	accessReviewService accessReview.AccessReviewService,
+	enforcer casbin.Enforcer,
+	validator *validator.Validate) *AccessReviewRestHandlerImpl {
+	return &AccessReviewRestHandlerImpl{
+		logger:              logger,
+		userService:         userService,
+		accessReviewService: accessReviewService,
+		enforcer:            enforcer,
+		validator:           validator,
+	}
+}
+
+func (handler *AccessReviewRestHandlerImpl) WhoCan(w http.ResponseWriter, r *http.Request) {
+	if !handler.authorize(w, r) {
+		return
+	}
+	request, ok := handler.getAccessReviewRequest(w, r)
+	if !ok {
+		return
+	}
+	subjects, err := handler.accessReviewService.WhoCan(request)
+	if err != nil {
+		handler.logger.Errorw("service err, WhoCan", "request", request, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, subjects, http.StatusOK)
+}
+
+func (handler *AccessReviewRestHandlerImpl) Explain(w http.ResponseWriter, r *http.Request) {
+	if !handler.authorize(w, r) {
+		return
+	}
+	request, ok := handler.getAccessReviewRequest(w, r)
+	if !ok {
+		return
+	}
+	if len(request.EmailId) == 0 {
+		common.WriteJsonResp(w, errors.New("emailId is required"), nil, http.StatusBadRequest)
+		return
+	}
+	decision, err := handler.accessReviewService.Explain(request)
+	if err != nil {
+		handler.logger.Errorw("service err, Explain", "request", request, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, decision, http.StatusOK)
+}
+
+func (handler *AccessReviewRestHandlerImpl) GetEntitlementReport(w http.ResponseWriter, r *http.Request) {
+	if !handler.authorize(w, r) {
+		return
+	}
+	format := strings.ToLower(r.URL.Query().Get("format"))
+	if len(format) == 0 {
+		format = bean.ReportFormatJson
+	}
+	if !bean.IsValidReportFormat(format) {
+		common.WriteJsonResp(w, fmt.Errorf("invalid format %s, supported formats are json and csv", format), nil, http.StatusBadRequest)
+		return
+	}
+	entitlements, err := handler.accessReviewService.GetEntitlementReport()
+	if err != nil {
+		handler.logger.Errorw("service err, GetEntitlementReport", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	if format == bean.ReportFormatJson {
+		common.WriteJsonResp(w, nil, entitlements, http.StatusOK)
+		return
+	}
+	fileName := fmt.Sprintf("entitlements-%s.csv", time.Now().Format("20060102"))
+	w.Header().Set(common.CONTENT_TYPE, "text/csv")
+	w.Header().Set(common.CONTENT_DISPOSITION, "attachment; filename="+fileName)
+	w.WriteHeader(http.StatusOK)
+	csvWriter := csv.NewWriter(w)
+	records := make([][]string, 0, len(entitlements)+1)
+	records = append(records, bean.EntitlementCsvHeader)
+	for _, entitlement := range entitlements {
+		records = append(records, entitlement.GetCsvRecord())
+	}
+	if err = csvWriter.WriteAll(records); err != nil {
+		handler.logger.Errorw("error in writing entitlement report csv", "err", err)
+	}
+}
+
+// authorize allows super admins only, as the access review exposes the access of all users
+func (handler *AccessReviewRestHandlerImpl) authorize(w http.ResponseWriter, r *http.Request) bool {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.HandleUnauthorized(w, r)
+		return false
+	}
+	if ok := handler.enforcer.Enforce(r.Header.Get("token"), casbin.ResourceGlobal, casbin.ActionGet, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+func (handler *AccessReviewRestHandlerImpl) getAccessReviewRequest(w http.ResponseWriter, r *http.Request) (*bean.AccessReviewRequest, bool) {
+	v := r.URL.Query()
+	request := &bean.AccessReviewRequest{
+		Resource: v.Get("resource"),
+		Action:   v.Get("action"),
+		Object:   v.Get("object"),
+		EmailId:  v.Get("emailId"),
+	}
+	if err := handler.validator.Struct(request); err != nil {
+		handler.logger.Errorw("validation err, getAccessReviewRequest", "request", request, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return nil, false
+	}
+	return request, true
+}
@@ -0,0 +1,47 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accessReview
+
+import "github.com/gorilla/mux"
+
+type AccessReviewRouter interface {
+	InitAccessReviewRouter(accessReviewRouter *mux.Router)
+}
+
+type AccessReviewRouterImpl struct {
+	accessReviewRestHandler AccessReviewRestHandler
+}
+
+func NewAccessReviewRouterImpl(accessReviewRestHandler AccessReviewRestHandler) *AccessReviewRouterImpl {
+	return &AccessReviewRouterImpl{
+		accessReviewRestHandler: accessReviewRestHandler,
+	}
+}
+
+func (impl *AccessReviewRouterImpl) InitAccessReviewRouter(accessReviewRouter *mux.Router) {
+	accessReviewRouter.Path("/who-can").
+		HandlerFunc(impl.accessReviewRestHandler.WhoCan).
+		Methods("GET")
+
+	accessReviewRouter.Path("/explain").
+		HandlerFunc(impl.accessReviewRestHandler.Explain).
+		Methods("GET")
+
+	accessReviewRouter.Path("/entitlements").
+		HandlerFunc(impl.accessReviewRestHandler.GetEntitlementReport).
+		Methods("GET")
+}
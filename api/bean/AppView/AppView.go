@@ -19,6 +19,7 @@ package AppView
 import (
 	"encoding/json"
 	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	driftBean "github.com/devtron-labs/devtron/pkg/appStore/installedApp/service/FullMode/drift/bean"
 	"github.com/devtron-labs/devtron/pkg/deployment/common/bean"
 	"time"
 )
@@ -198,6 +199,8 @@ type AppDetailContainer struct {
 	LinkOuts                  []LinkOuts             `json:"linkOuts,omitempty"`
 	ResourceTree              map[string]interface{} `json:"resourceTree,omitempty"`
 	Notes                     string                 `json:"notes,omitempty"`
+	// Drift is the last drift check of a chart store app
+	Drift *driftBean.InstalledAppDriftDto `json:"drift,omitempty"`
 }
 type AppDetailsContainer struct {
 	ResourceTree  map[string]interface{} `json:"resourceTree,omitempty"`
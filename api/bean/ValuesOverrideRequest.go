@@ -82,16 +82,19 @@ type ValuesOverrideRequest struct {
 	DeploymentType                        models.DeploymentType       `json:"deploymentType"`     // required for async install/upgrade handling; previously if was used internally
 	ForceSyncDeployment                   bool                        `json:"forceSyncDeployment,notnull"`
 	IsRollbackDeployment                  bool                        `json:"isRollbackDeployment"`
-	UserId                                int32                       `json:"-"`
-	EnvId                                 int                         `json:"-"`
-	EnvName                               string                      `json:"-"`
-	ClusterId                             int                         `json:"-"`
-	AppName                               string                      `json:"-"`
-	PipelineName                          string                      `json:"-"`
-	DeploymentAppType                     string                      `json:"-"`
-	Namespace                             string                      `json:"-"`
-	ReleaseName                           string                      `json:"-"`
-	Image                                 string                      `json:"-"`
+	// DeploymentWindowOverride deploys in spite of the blocking deployment windows, requires the override permission and a reason
+	DeploymentWindowOverride       bool   `json:"deploymentWindowOverride"`
+	DeploymentWindowOverrideReason string `json:"deploymentWindowOverrideReason"`
+	UserId                         int32  `json:"-"`
+	EnvId                          int    `json:"-"`
+	EnvName                        string `json:"-"`
+	ClusterId                      int    `json:"-"`
+	AppName                        string `json:"-"`
+	PipelineName                   string `json:"-"`
+	DeploymentAppType              string `json:"-"`
+	Namespace                      string `json:"-"`
+	ReleaseName                    string `json:"-"`
+	Image                          string `json:"-"`
 }
 
 type BulkCdDeployEvent struct {
@@ -0,0 +1,232 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package celPolicy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/celPolicy"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/celPolicy/bean"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+	"net/http"
+)
+
+type CelPolicyRestHandler interface {
+	GetAllPolicies(w http.ResponseWriter, r *http.Request)
+	GetPolicy(w http.ResponseWriter, r *http.Request)
+	CreatePolicy(w http.ResponseWriter, r *http.Request)
+	UpdatePolicy(w http.ResponseWriter, r *http.Request)
+	DeletePolicy(w http.ResponseWriter, r *http.Request)
+	GetParams(w http.ResponseWriter, r *http.Request)
+	DryRun(w http.ResponseWriter, r *http.Request)
+}
+
+type CelPolicyRestHandlerImpl struct {
+	logger           *zap.SugaredLogger
+	userService      user.UserService
+	celPolicyService celPolicy.CelPolicyService
+	enforcer         casbin.Enforcer
+	validator        *validator.Validate
+}
+
+func NewCelPolicyRestHandlerImpl(logger *zap.SugaredLogger,
+	userService user.UserService,
+	celPolicyService celPolicy.CelPolicyService,
+	enforcer casbin.Enforcer,
+	validator *validator.Validate) *CelPolicyRestHandlerImpl {
+	return &CelPolicyRestHandlerImpl{
+		logger:           logger,
+		userService:      userService,
+		celPolicyService: celPolicyService,
+		enforcer:         enforcer,
+		validator:        validator,
+	}
+}
+
+func (handler *CelPolicyRestHandlerImpl) GetAllPolicies(w http.ResponseWriter, r *http.Request) {
+	if !handler.isAuthorised(w, r, casbin.ActionGet) {
+		return
+	}
+	policies, err := handler.celPolicyService.GetAllPolicies()
+	if err != nil {
+		handler.logger.Errorw("service err, GetAllPolicies", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, policies, http.StatusOK)
+}
+
+func (handler *CelPolicyRestHandlerImpl) GetPolicy(w http.ResponseWriter, r *http.Request) {
+	if !handler.isAuthorised(w, r, casbin.ActionGet) {
+		return
+	}
+	id, err := common.ExtractIntPathParamWithContext(w, r, "id")
+	if err != nil {
+		return
+	}
+	policy, err := handler.celPolicyService.GetPolicyById(id)
+	if err != nil {
+		handler.logger.Errorw("service err, GetPolicy", "id", id, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, policy, http.StatusOK)
+}
+
+func (handler *CelPolicyRestHandlerImpl) CreatePolicy(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.HandleUnauthorized(w, r)
+		return
+	}
+	if !handler.isAuthorised(w, r, casbin.ActionCreate) {
+		return
+	}
+	policy, ok := handler.decodePolicy(w, r)
+	if !ok {
+		return
+	}
+	policy.Id = 0
+	policy.UserId = userId
+	policy, err = handler.celPolicyService.CreatePolicy(r.Context(), policy)
+	if err != nil {
+		handler.logger.Errorw("service err, CreatePolicy", "payload", policy, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, policy, http.StatusOK)
+}
+
+func (handler *CelPolicyRestHandlerImpl) UpdatePolicy(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.HandleUnauthorized(w, r)
+		return
+	}
+	if !handler.isAuthorised(w, r, casbin.ActionUpdate) {
+		return
+	}
+	id, err := common.ExtractIntPathParamWithContext(w, r, "id")
+	if err != nil {
+		return
+	}
+	policy, ok := handler.decodePolicy(w, r)
+	if !ok {
+		return
+	}
+	policy.Id = id
+	policy.UserId = userId
+	policy, err = handler.celPolicyService.UpdatePolicy(r.Context(), policy)
+	if err != nil {
+		handler.logger.Errorw("service err, UpdatePolicy", "id", id, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, policy, http.StatusOK)
+}
+
+func (handler *CelPolicyRestHandlerImpl) DeletePolicy(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.HandleUnauthorized(w, r)
+		return
+	}
+	if !handler.isAuthorised(w, r, casbin.ActionDelete) {
+		return
+	}
+	id, err := common.ExtractIntPathParamWithContext(w, r, "id")
+	if err != nil {
+		return
+	}
+	err = handler.celPolicyService.DeletePolicy(r.Context(), id, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, DeletePolicy", "id", id, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, id, http.StatusOK)
+}
+
+func (handler *CelPolicyRestHandlerImpl) GetParams(w http.ResponseWriter, r *http.Request) {
+	if !handler.isAuthorised(w, r, casbin.ActionGet) {
+		return
+	}
+	hook := bean.EvaluationHook(r.URL.Query().Get("hook"))
+	if !hook.IsValid() {
+		common.WriteJsonResp(w, fmt.Errorf(bean.InvalidHookMessage, hook), nil, http.StatusBadRequest)
+		return
+	}
+	common.WriteJsonResp(w, nil, bean.GetParamDeclarations(hook), http.StatusOK)
+}
+
+func (handler *CelPolicyRestHandlerImpl) DryRun(w http.ResponseWriter, r *http.Request) {
+	if !handler.isAuthorised(w, r, casbin.ActionGet) {
+		return
+	}
+	request := &bean.DryRunRequest{}
+	err := json.NewDecoder(r.Body).Decode(request)
+	if err != nil {
+		handler.logger.Errorw("request err, DryRun", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err, DryRun", "payload", request, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	response, err := handler.celPolicyService.DryRun(request)
+	if err != nil {
+		handler.logger.Errorw("service err, DryRun", "payload", request, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, response, http.StatusOK)
+}
+
+func (handler *CelPolicyRestHandlerImpl) decodePolicy(w http.ResponseWriter, r *http.Request) (*bean.CelPolicyDto, bool) {
+	policy := &bean.CelPolicyDto{}
+	err := json.NewDecoder(r.Body).Decode(policy)
+	if err != nil {
+		handler.logger.Errorw("request err, decodePolicy", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return nil, false
+	}
+	err = handler.validator.Struct(policy)
+	if err != nil {
+		handler.logger.Errorw("validation err, decodePolicy", "payload", policy, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return nil, false
+	}
+	return policy, true
+}
+
+// isAuthorised checks global resource access, policies apply across apps and environments
+func (handler *CelPolicyRestHandlerImpl) isAuthorised(w http.ResponseWriter, r *http.Request, action string) bool {
+	token := r.Header.Get("token")
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceGlobal, action, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return false
+	}
+	return true
+}
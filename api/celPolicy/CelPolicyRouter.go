@@ -0,0 +1,64 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package celPolicy
+
+import "github.com/gorilla/mux"
+
+type CelPolicyRouter interface {
+	InitCelPolicyRouter(configRouter *mux.Router)
+}
+
+type CelPolicyRouterImpl struct {
+	celPolicyRestHandler CelPolicyRestHandler
+}
+
+func NewCelPolicyRouterImpl(celPolicyRestHandler CelPolicyRestHandler) *CelPolicyRouterImpl {
+	return &CelPolicyRouterImpl{
+		celPolicyRestHandler: celPolicyRestHandler,
+	}
+}
+
+func (impl *CelPolicyRouterImpl) InitCelPolicyRouter(configRouter *mux.Router) {
+	configRouter.Path("/params").
+		Queries("hook", "{hook}").
+		HandlerFunc(impl.celPolicyRestHandler.GetParams).
+		Methods("GET")
+
+	configRouter.Path("/dry-run").
+		HandlerFunc(impl.celPolicyRestHandler.DryRun).
+		Methods("POST")
+
+	configRouter.Path("").
+		HandlerFunc(impl.celPolicyRestHandler.GetAllPolicies).
+		Methods("GET")
+
+	configRouter.Path("").
+		HandlerFunc(impl.celPolicyRestHandler.CreatePolicy).
+		Methods("POST")
+
+	configRouter.Path("/{id:[0-9]+}").
+		HandlerFunc(impl.celPolicyRestHandler.GetPolicy).
+		Methods("GET")
+
+	configRouter.Path("/{id:[0-9]+}").
+		HandlerFunc(impl.celPolicyRestHandler.UpdatePolicy).
+		Methods("PUT")
+
+	configRouter.Path("/{id:[0-9]+}").
+		HandlerFunc(impl.celPolicyRestHandler.DeletePolicy).
+		Methods("DELETE")
+}
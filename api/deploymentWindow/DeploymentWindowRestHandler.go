@@ -0,0 +1,241 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploymentWindow
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/deploymentWindow"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/deploymentWindow/bean"
+	"github.com/devtron-labs/devtron/util/rbac"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+	"net/http"
+	"time"
+)
+
+type DeploymentWindowRestHandler interface {
+	GetAllWindows(w http.ResponseWriter, r *http.Request)
+	GetWindow(w http.ResponseWriter, r *http.Request)
+	CreateWindow(w http.ResponseWriter, r *http.Request)
+	UpdateWindow(w http.ResponseWriter, r *http.Request)
+	DeleteWindow(w http.ResponseWriter, r *http.Request)
+	GetDeploymentWindowState(w http.ResponseWriter, r *http.Request)
+	GetOverrideAudits(w http.ResponseWriter, r *http.Request)
+}
+
+type DeploymentWindowRestHandlerImpl struct {
+	logger                  *zap.SugaredLogger
+	userService             user.UserService
+	deploymentWindowService deploymentWindow.DeploymentWindowService
+	enforcer                casbin.Enforcer
+	enforcerUtil            rbac.EnforcerUtil
+	validator               *validator.Validate
+}
+
+func NewDeploymentWindowRestHandlerImpl(logger *zap.SugaredLogger,
+	userService user.UserService,
+	deploymentWindowService deploymentWindow.DeploymentWindowService,
+	enforcer casbin.Enforcer,
+	enforcerUtil rbac.EnforcerUtil,
+	validator *validator.Validate) *DeploymentWindowRestHandlerImpl {
+	return &DeploymentWindowRestHandlerImpl{
+		logger:                  logger,
+		userService:             userService,
+		deploymentWindowService: deploymentWindowService,
+		enforcer:                enforcer,
+		enforcerUtil:            enforcerUtil,
+		validator:               validator,
+	}
+}
+
+func (handler *DeploymentWindowRestHandlerImpl) GetAllWindows(w http.ResponseWriter, r *http.Request) {
+	if !handler.isAuthorised(w, r, casbin.ActionGet) {
+		return
+	}
+	windows, err := handler.deploymentWindowService.GetAllWindows()
+	if err != nil {
+		handler.logger.Errorw("service err, GetAllWindows", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, windows, http.StatusOK)
+}
+
+func (handler *DeploymentWindowRestHandlerImpl) GetWindow(w http.ResponseWriter, r *http.Request) {
+	if !handler.isAuthorised(w, r, casbin.ActionGet) {
+		return
+	}
+	id, err := common.ExtractIntPathParamWithContext(w, r, "id")
+	if err != nil {
+		return
+	}
+	window, err := handler.deploymentWindowService.GetWindowById(id)
+	if err != nil {
+		handler.logger.Errorw("service err, GetWindow", "id", id, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, window, http.StatusOK)
+}
+
+func (handler *DeploymentWindowRestHandlerImpl) CreateWindow(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.HandleUnauthorized(w, r)
+		return
+	}
+	if !handler.isAuthorised(w, r, casbin.ActionCreate) {
+		return
+	}
+	window, ok := handler.decodeWindow(w, r)
+	if !ok {
+		return
+	}
+	window.Id = 0
+	window.UserId = userId
+	window, err = handler.deploymentWindowService.CreateWindow(r.Context(), window)
+	if err != nil {
+		handler.logger.Errorw("service err, CreateWindow", "payload", window, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, window, http.StatusOK)
+}
+
+func (handler *DeploymentWindowRestHandlerImpl) UpdateWindow(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.HandleUnauthorized(w, r)
+		return
+	}
+	if !handler.isAuthorised(w, r, casbin.ActionUpdate) {
+		return
+	}
+	id, err := common.ExtractIntPathParamWithContext(w, r, "id")
+	if err != nil {
+		return
+	}
+	window, ok := handler.decodeWindow(w, r)
+	if !ok {
+		return
+	}
+	window.Id = id
+	window.UserId = userId
+	window, err = handler.deploymentWindowService.UpdateWindow(r.Context(), window)
+	if err != nil {
+		handler.logger.Errorw("service err, UpdateWindow", "id", id, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, window, http.StatusOK)
+}
+
+func (handler *DeploymentWindowRestHandlerImpl) DeleteWindow(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.HandleUnauthorized(w, r)
+		return
+	}
+	if !handler.isAuthorised(w, r, casbin.ActionDelete) {
+		return
+	}
+	id, err := common.ExtractIntPathParamWithContext(w, r, "id")
+	if err != nil {
+		return
+	}
+	err = handler.deploymentWindowService.DeleteWindow(r.Context(), id, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, DeleteWindow", "id", id, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, id, http.StatusOK)
+}
+
+// GetDeploymentWindowState returns the windows currently blocking or allowing deployments on the app and env,
+// accessible to the users having view access on the app
+func (handler *DeploymentWindowRestHandlerImpl) GetDeploymentWindowState(w http.ResponseWriter, r *http.Request) {
+	appId, err := common.ExtractIntPathParamWithContext(w, r, "appId")
+	if err != nil {
+		return
+	}
+	envId, err := common.ExtractIntPathParamWithContext(w, r, "envId")
+	if err != nil {
+		return
+	}
+	token := r.Header.Get("token")
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionGet, handler.enforcerUtil.GetAppRBACNameByAppId(appId)); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	scope := &bean.WindowEvaluationScope{AppId: appId, EnvId: envId}
+	state, err := handler.deploymentWindowService.GetDeploymentWindowState(r.Context(), scope, time.Now())
+	if err != nil {
+		handler.logger.Errorw("service err, GetDeploymentWindowState", "appId", appId, "envId", envId, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, state, http.StatusOK)
+}
+
+func (handler *DeploymentWindowRestHandlerImpl) GetOverrideAudits(w http.ResponseWriter, r *http.Request) {
+	if !handler.isAuthorised(w, r, casbin.ActionGet) {
+		return
+	}
+	pipelineId, err := common.ExtractIntPathParamWithContext(w, r, "pipelineId")
+	if err != nil {
+		return
+	}
+	audits, err := handler.deploymentWindowService.GetOverrideAudits(pipelineId)
+	if err != nil {
+		handler.logger.Errorw("service err, GetOverrideAudits", "pipelineId", pipelineId, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, audits, http.StatusOK)
+}
+
+func (handler *DeploymentWindowRestHandlerImpl) decodeWindow(w http.ResponseWriter, r *http.Request) (*bean.DeploymentWindowDto, bool) {
+	window := &bean.DeploymentWindowDto{}
+	err := json.NewDecoder(r.Body).Decode(window)
+	if err != nil {
+		handler.logger.Errorw("request err, decodeWindow", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return nil, false
+	}
+	err = handler.validator.Struct(window)
+	if err != nil {
+		handler.logger.Errorw("validation err, decodeWindow", "payload", window, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return nil, false
+	}
+	return window, true
+}
+
+// isAuthorised checks global resource access, windows apply across apps and environments
+func (handler *DeploymentWindowRestHandlerImpl) isAuthorised(w http.ResponseWriter, r *http.Request, action string) bool {
+	token := r.Header.Get("token")
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceGlobal, action, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,65 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploymentWindow
+
+import "github.com/gorilla/mux"
+
+type DeploymentWindowRouter interface {
+	InitDeploymentWindowRouter(configRouter *mux.Router)
+}
+
+type DeploymentWindowRouterImpl struct {
+	deploymentWindowRestHandler DeploymentWindowRestHandler
+}
+
+func NewDeploymentWindowRouterImpl(deploymentWindowRestHandler DeploymentWindowRestHandler) *DeploymentWindowRouterImpl {
+	return &DeploymentWindowRouterImpl{
+		deploymentWindowRestHandler: deploymentWindowRestHandler,
+	}
+}
+
+func (impl *DeploymentWindowRouterImpl) InitDeploymentWindowRouter(configRouter *mux.Router) {
+	configRouter.Path("/state").
+		Queries("appId", "{appId}", "envId", "{envId}").
+		HandlerFunc(impl.deploymentWindowRestHandler.GetDeploymentWindowState).
+		Methods("GET")
+
+	configRouter.Path("/override-audit").
+		Queries("pipelineId", "{pipelineId}").
+		HandlerFunc(impl.deploymentWindowRestHandler.GetOverrideAudits).
+		Methods("GET")
+
+	configRouter.Path("").
+		HandlerFunc(impl.deploymentWindowRestHandler.GetAllWindows).
+		Methods("GET")
+
+	configRouter.Path("").
+		HandlerFunc(impl.deploymentWindowRestHandler.CreateWindow).
+		Methods("POST")
+
+	configRouter.Path("/{id:[0-9]+}").
+		HandlerFunc(impl.deploymentWindowRestHandler.GetWindow).
+		Methods("GET")
+
+	configRouter.Path("/{id:[0-9]+}").
+		HandlerFunc(impl.deploymentWindowRestHandler.UpdateWindow).
+		Methods("PUT")
+
+	configRouter.Path("/{id:[0-9]+}").
+		HandlerFunc(impl.deploymentWindowRestHandler.DeleteWindow).
+		Methods("DELETE")
+}
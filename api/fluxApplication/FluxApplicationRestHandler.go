@@ -1,11 +1,13 @@
 package fluxApplication
 
 import (
+	"encoding/json"
 	"errors"
 	"github.com/devtron-labs/devtron/api/restHandler/common"
 	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
 	clientErrors "github.com/devtron-labs/devtron/pkg/errors"
 	"github.com/devtron-labs/devtron/pkg/fluxApplication"
+	"github.com/devtron-labs/devtron/pkg/fluxApplication/bean"
 	"github.com/gorilla/mux"
 	"go.uber.org/zap"
 	"net/http"
@@ -14,6 +16,11 @@ import (
 type FluxApplicationRestHandler interface {
 	ListFluxApplications(w http.ResponseWriter, r *http.Request)
 	GetApplicationDetail(w http.ResponseWriter, r *http.Request)
+	ReconcileApplication(w http.ResponseWriter, r *http.Request)
+	SuspendApplication(w http.ResponseWriter, r *http.Request)
+	ResumeApplication(w http.ResponseWriter, r *http.Request)
+	GetReconciliationHistory(w http.ResponseWriter, r *http.Request)
+	GetApplicationSource(w http.ResponseWriter, r *http.Request)
 }
 
 type FluxApplicationRestHandlerImpl struct {
@@ -94,3 +101,110 @@ func (handler *FluxApplicationRestHandlerImpl) GetApplicationDetail(w http.Respo
 	}
 	common.WriteJsonResp(w, err, res, http.StatusOK)
 }
+
+func (handler *FluxApplicationRestHandlerImpl) ReconcileApplication(w http.ResponseWriter, r *http.Request) {
+	request, appIdentifier, ok := handler.decodeAndAuthorizeActionRequest(w, r)
+	if !ok {
+		return
+	}
+	res, err := handler.fluxApplicationService.ReconcileFluxApplication(r.Context(), appIdentifier, request.WithSource)
+	if err != nil {
+		handler.logger.Errorw("error in reconciling flux app", "appIdentifier", appIdentifier, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	res.AppId = request.AppId
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *FluxApplicationRestHandlerImpl) SuspendApplication(w http.ResponseWriter, r *http.Request) {
+	request, appIdentifier, ok := handler.decodeAndAuthorizeActionRequest(w, r)
+	if !ok {
+		return
+	}
+	res, err := handler.fluxApplicationService.SuspendFluxApplication(r.Context(), appIdentifier)
+	if err != nil {
+		handler.logger.Errorw("error in suspending flux app", "appIdentifier", appIdentifier, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	res.AppId = request.AppId
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *FluxApplicationRestHandlerImpl) ResumeApplication(w http.ResponseWriter, r *http.Request) {
+	request, appIdentifier, ok := handler.decodeAndAuthorizeActionRequest(w, r)
+	if !ok {
+		return
+	}
+	res, err := handler.fluxApplicationService.ResumeFluxApplication(r.Context(), appIdentifier)
+	if err != nil {
+		handler.logger.Errorw("error in resuming flux app", "appIdentifier", appIdentifier, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	res.AppId = request.AppId
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *FluxApplicationRestHandlerImpl) GetReconciliationHistory(w http.ResponseWriter, r *http.Request) {
+	appIdString := r.URL.Query().Get("appId")
+	appIdentifier, ok := handler.decodeAndAuthorizeAppId(w, r, appIdString, casbin.ActionGet)
+	if !ok {
+		return
+	}
+	res, err := handler.fluxApplicationService.GetFluxAppReconciliationHistory(r.Context(), appIdentifier)
+	if err != nil {
+		handler.logger.Errorw("error in getting flux app reconciliation history", "appIdentifier", appIdentifier, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	res.AppId = appIdString
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *FluxApplicationRestHandlerImpl) GetApplicationSource(w http.ResponseWriter, r *http.Request) {
+	appIdentifier, ok := handler.decodeAndAuthorizeAppId(w, r, r.URL.Query().Get("appId"), casbin.ActionGet)
+	if !ok {
+		return
+	}
+	res, err := handler.fluxApplicationService.GetFluxAppSource(r.Context(), appIdentifier)
+	if err != nil {
+		handler.logger.Errorw("error in getting flux app source", "appIdentifier", appIdentifier, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *FluxApplicationRestHandlerImpl) decodeAndAuthorizeActionRequest(w http.ResponseWriter, r *http.Request) (*bean.FluxAppActionRequest, *bean.FluxAppIdentifier, bool) {
+	request := &bean.FluxAppActionRequest{}
+	err := json.NewDecoder(r.Body).Decode(request)
+	if err != nil {
+		handler.logger.Errorw("error in decoding request body", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return nil, nil, false
+	}
+	appIdentifier, ok := handler.decodeAndAuthorizeAppId(w, r, request.AppId, casbin.ActionUpdate)
+	return request, appIdentifier, ok
+}
+
+// decodeAndAuthorizeAppId decodes the flux app id and enforces the super-admin RBAC of the flux app detail page,
+// the response is written in case of failure
+func (handler *FluxApplicationRestHandlerImpl) decodeAndAuthorizeAppId(w http.ResponseWriter, r *http.Request, appIdString string, action string) (*bean.FluxAppIdentifier, bool) {
+	appIdentifier, err := fluxApplication.DecodeFluxExternalAppId(appIdString)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return nil, false
+	}
+	if fluxApplication.IsFluxSystemRootApp(appIdentifier) {
+		common.WriteJsonResp(w, errors.New("cannot proceed for the flux system root level "), nil, http.StatusBadRequest)
+		return nil, false
+	}
+	token := r.Header.Get("token")
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceGlobal, action, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return nil, false
+	}
+	return appIdentifier, true
+}
@@ -24,4 +24,14 @@ func (impl *FluxApplicationRouterImpl) InitFluxApplicationRouter(fluxApplication
 		HandlerFunc(impl.fluxApplicationRestHandler.ListFluxApplications)
 	fluxApplicationRouter.Path("/app").Queries("appId", "{appId}").
 		HandlerFunc(impl.fluxApplicationRestHandler.GetApplicationDetail).Methods("GET")
+	fluxApplicationRouter.Path("/app/reconcile").
+		HandlerFunc(impl.fluxApplicationRestHandler.ReconcileApplication).Methods("POST")
+	fluxApplicationRouter.Path("/app/suspend").
+		HandlerFunc(impl.fluxApplicationRestHandler.SuspendApplication).Methods("POST")
+	fluxApplicationRouter.Path("/app/resume").
+		HandlerFunc(impl.fluxApplicationRestHandler.ResumeApplication).Methods("POST")
+	fluxApplicationRouter.Path("/app/reconciliation-history").Queries("appId", "{appId}").
+		HandlerFunc(impl.fluxApplicationRestHandler.GetReconciliationHistory).Methods("GET")
+	fluxApplicationRouter.Path("/app/source").Queries("appId", "{appId}").
+		HandlerFunc(impl.fluxApplicationRestHandler.GetApplicationSource).Methods("GET")
 }
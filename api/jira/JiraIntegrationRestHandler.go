@@ -0,0 +1,208 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jira
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	"github.com/devtron-labs/devtron/pkg/jira"
+	"github.com/devtron-labs/devtron/pkg/jira/bean"
+	"github.com/devtron-labs/devtron/util/rbac"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+	"net/http"
+)
+
+type JiraIntegrationRestHandler interface {
+	GetJiraConfigs(w http.ResponseWriter, r *http.Request)
+	GetJiraConfig(w http.ResponseWriter, r *http.Request)
+	SaveJiraConfig(w http.ResponseWriter, r *http.Request)
+	DeleteJiraConfig(w http.ResponseWriter, r *http.Request)
+	GetArtifactLinkedIssues(w http.ResponseWriter, r *http.Request)
+	GetDeploymentLinkedIssues(w http.ResponseWriter, r *http.Request)
+}
+
+type JiraIntegrationRestHandlerImpl struct {
+	logger                 *zap.SugaredLogger
+	userService            user.UserService
+	jiraIntegrationService jira.JiraIntegrationService
+	enforcer               casbin.Enforcer
+	enforcerUtil           rbac.EnforcerUtil
+	validator              *validator.Validate
+}
+
+func NewJiraIntegrationRestHandlerImpl(logger *zap.SugaredLogger,
+	userService user.UserService,
+	jiraIntegrationService jira.JiraIntegrationService,
+	enforcer casbin.Enforcer,
+	enforcerUtil rbac.EnforcerUtil,
+	validator *validator.Validate) *JiraIntegrationRestHandlerImpl {
+	return &JiraIntegrationRestHandlerImpl{
+		logger:                 logger,
+		userService:            userService,
+		jiraIntegrationService: jiraIntegrationService,
+		enforcer:               enforcer,
+		enforcerUtil:           enforcerUtil,
+		validator:              validator,
+	}
+}
+
+func (handler *JiraIntegrationRestHandlerImpl) GetJiraConfigs(w http.ResponseWriter, r *http.Request) {
+	if ok := handler.enforcer.Enforce(r.Header.Get("token"), casbin.ResourceGlobal, casbin.ActionGet, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	configs, err := handler.jiraIntegrationService.GetJiraConfigs()
+	if err != nil {
+		handler.logger.Errorw("service err, GetJiraConfigs", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, configs, http.StatusOK)
+}
+
+func (handler *JiraIntegrationRestHandlerImpl) GetJiraConfig(w http.ResponseWriter, r *http.Request) {
+	teamId, err := common.ExtractIntPathParamWithContext(w, r, "teamId")
+	if err != nil {
+		return
+	}
+	if ok := handler.enforcer.Enforce(r.Header.Get("token"), casbin.ResourceGlobal, casbin.ActionGet, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	config, err := handler.jiraIntegrationService.GetJiraConfigByTeamId(teamId)
+	if err != nil {
+		handler.logger.Errorw("service err, GetJiraConfig", "teamId", teamId, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, config, http.StatusOK)
+}
+
+func (handler *JiraIntegrationRestHandlerImpl) SaveJiraConfig(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.HandleUnauthorized(w, r)
+		return
+	}
+	config := &bean.JiraConfigDto{}
+	err = json.NewDecoder(r.Body).Decode(config)
+	if err != nil {
+		handler.logger.Errorw("request err, SaveJiraConfig", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	err = handler.validator.Struct(config)
+	if err != nil {
+		handler.logger.Errorw("validation err, SaveJiraConfig", "teamId", config.TeamId, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	// the config holds the credentials of the jira account, so it is managed by super admins only
+	if ok := handler.enforcer.Enforce(r.Header.Get("token"), casbin.ResourceGlobal, casbin.ActionUpdate, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	config.UserId = userId
+	config, err = handler.jiraIntegrationService.SaveJiraConfig(config)
+	if err != nil {
+		handler.logger.Errorw("service err, SaveJiraConfig", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, config, http.StatusOK)
+}
+
+func (handler *JiraIntegrationRestHandlerImpl) DeleteJiraConfig(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.HandleUnauthorized(w, r)
+		return
+	}
+	teamId, err := common.ExtractIntPathParamWithContext(w, r, "teamId")
+	if err != nil {
+		return
+	}
+	if ok := handler.enforcer.Enforce(r.Header.Get("token"), casbin.ResourceGlobal, casbin.ActionDelete, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	err = handler.jiraIntegrationService.DeleteJiraConfig(teamId, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, DeleteJiraConfig", "teamId", teamId, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, nil, http.StatusOK)
+}
+
+func (handler *JiraIntegrationRestHandlerImpl) GetArtifactLinkedIssues(w http.ResponseWriter, r *http.Request) {
+	appId, err := common.ExtractIntPathParamWithContext(w, r, "appId")
+	if err != nil {
+		return
+	}
+	artifactId, err := common.ExtractIntPathParamWithContext(w, r, "artifactId")
+	if err != nil {
+		return
+	}
+	rbacObject := handler.enforcerUtil.GetAppRBACNameByAppId(appId)
+	if ok := handler.enforcer.Enforce(r.Header.Get("token"), casbin.ResourceApplications, casbin.ActionGet, rbacObject); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	issues, err := handler.jiraIntegrationService.GetLinkedIssuesForArtifact(appId, artifactId)
+	if err != nil {
+		handler.logger.Errorw("service err, GetArtifactLinkedIssues", "appId", appId, "artifactId", artifactId, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, issues, http.StatusOK)
+}
+
+func (handler *JiraIntegrationRestHandlerImpl) GetDeploymentLinkedIssues(w http.ResponseWriter, r *http.Request) {
+	appId, err := common.ExtractIntPathParamWithContext(w, r, "appId")
+	if err != nil {
+		return
+	}
+	envId, err := common.ExtractIntPathParamWithContext(w, r, "envId")
+	if err != nil {
+		return
+	}
+	wfrId, err := common.ExtractIntPathParamWithContext(w, r, "wfrId")
+	if err != nil {
+		return
+	}
+	token := r.Header.Get("token")
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionGet, handler.enforcerUtil.GetAppRBACNameByAppId(appId)); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceEnvironment, casbin.ActionGet, handler.enforcerUtil.GetEnvRBACNameByAppId(appId, envId)); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	issues, err := handler.jiraIntegrationService.GetLinkedIssuesForDeployment(appId, envId, wfrId)
+	if err != nil {
+		handler.logger.Errorw("service err, GetDeploymentLinkedIssues", "appId", appId, "envId", envId, "wfrId", wfrId, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, issues, http.StatusOK)
+}
@@ -0,0 +1,59 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jira
+
+import "github.com/gorilla/mux"
+
+type JiraIntegrationRouter interface {
+	InitJiraIntegrationRouter(configRouter *mux.Router)
+}
+
+type JiraIntegrationRouterImpl struct {
+	jiraIntegrationRestHandler JiraIntegrationRestHandler
+}
+
+func NewJiraIntegrationRouterImpl(jiraIntegrationRestHandler JiraIntegrationRestHandler) *JiraIntegrationRouterImpl {
+	return &JiraIntegrationRouterImpl{
+		jiraIntegrationRestHandler: jiraIntegrationRestHandler,
+	}
+}
+
+func (impl *JiraIntegrationRouterImpl) InitJiraIntegrationRouter(configRouter *mux.Router) {
+	configRouter.Path("/config").
+		HandlerFunc(impl.jiraIntegrationRestHandler.GetJiraConfigs).
+		Methods("GET")
+
+	configRouter.Path("/config").
+		HandlerFunc(impl.jiraIntegrationRestHandler.SaveJiraConfig).
+		Methods("POST")
+
+	configRouter.Path("/config/team/{teamId:[0-9]+}").
+		HandlerFunc(impl.jiraIntegrationRestHandler.GetJiraConfig).
+		Methods("GET")
+
+	configRouter.Path("/config/team/{teamId:[0-9]+}").
+		HandlerFunc(impl.jiraIntegrationRestHandler.DeleteJiraConfig).
+		Methods("DELETE")
+
+	configRouter.Path("/issues/app/{appId:[0-9]+}/artifact/{artifactId:[0-9]+}").
+		HandlerFunc(impl.jiraIntegrationRestHandler.GetArtifactLinkedIssues).
+		Methods("GET")
+
+	configRouter.Path("/issues/app/{appId:[0-9]+}/env/{envId:[0-9]+}/deployment/{wfrId:[0-9]+}").
+		HandlerFunc(impl.jiraIntegrationRestHandler.GetDeploymentLinkedIssues).
+		Methods("GET")
+}
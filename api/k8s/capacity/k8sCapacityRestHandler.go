@@ -53,18 +53,24 @@ type K8sCapacityRestHandler interface {
 	CordonOrUnCordonNode(w http.ResponseWriter, r *http.Request)
 	DrainNode(w http.ResponseWriter, r *http.Request)
 	EditNodeTaints(w http.ResponseWriter, r *http.Request)
+	StartNodeMaintenance(w http.ResponseWriter, r *http.Request)
+	GetNodeMaintenanceRuns(w http.ResponseWriter, r *http.Request)
+	GetNodeMaintenanceRun(w http.ResponseWriter, r *http.Request)
+	ResumeNodeMaintenance(w http.ResponseWriter, r *http.Request)
+	AbortNodeMaintenance(w http.ResponseWriter, r *http.Request)
 }
 type K8sCapacityRestHandlerImpl struct {
-	logger              *zap.SugaredLogger
-	k8sCapacityService  capacity.K8sCapacityService
-	userService         user.UserService
-	enforcer            casbin.Enforcer
-	clusterService      cluster.ClusterService
-	environmentService  environment.EnvironmentService
-	clusterRbacService  rbac.ClusterRbacService
-	clusterReadService  read.ClusterReadService
-	validator           *validator.Validate
-	clusterCacheService overviewCache.ClusterCacheService
+	logger                 *zap.SugaredLogger
+	k8sCapacityService     capacity.K8sCapacityService
+	userService            user.UserService
+	enforcer               casbin.Enforcer
+	clusterService         cluster.ClusterService
+	environmentService     environment.EnvironmentService
+	clusterRbacService     rbac.ClusterRbacService
+	clusterReadService     read.ClusterReadService
+	validator              *validator.Validate
+	clusterCacheService    overviewCache.ClusterCacheService
+	nodeMaintenanceService capacity.NodeMaintenanceService
 }
 
 func NewK8sCapacityRestHandlerImpl(logger *zap.SugaredLogger,
@@ -76,18 +82,20 @@ func NewK8sCapacityRestHandlerImpl(logger *zap.SugaredLogger,
 	clusterReadService read.ClusterReadService,
 	validator *validator.Validate,
 	clusterCacheService overviewCache.ClusterCacheService,
+	nodeMaintenanceService capacity.NodeMaintenanceService,
 ) *K8sCapacityRestHandlerImpl {
 	return &K8sCapacityRestHandlerImpl{
-		logger:              logger,
-		k8sCapacityService:  k8sCapacityService,
-		userService:         userService,
-		enforcer:            enforcer,
-		clusterService:      clusterService,
-		environmentService:  environmentService,
-		clusterRbacService:  clusterRbacService,
-		clusterReadService:  clusterReadService,
-		validator:           validator,
-		clusterCacheService: clusterCacheService,
+		logger:                 logger,
+		k8sCapacityService:     k8sCapacityService,
+		userService:            userService,
+		enforcer:               enforcer,
+		clusterService:         clusterService,
+		environmentService:     environmentService,
+		clusterRbacService:     clusterRbacService,
+		clusterReadService:     clusterReadService,
+		validator:              validator,
+		clusterCacheService:    clusterCacheService,
+		nodeMaintenanceService: nodeMaintenanceService,
 	}
 }
 
@@ -63,4 +63,19 @@ func (impl *K8sCapacityRouterImpl) InitK8sCapacityRouter(k8sCapacityRouter *mux.
 
 	k8sCapacityRouter.Path("/node/taints/edit").
 		HandlerFunc(impl.k8sCapacityRestHandler.EditNodeTaints).Methods("PUT")
+
+	k8sCapacityRouter.Path("/node/maintenance").
+		HandlerFunc(impl.k8sCapacityRestHandler.StartNodeMaintenance).Methods("POST")
+
+	k8sCapacityRouter.Path("/node/maintenance/list").
+		HandlerFunc(impl.k8sCapacityRestHandler.GetNodeMaintenanceRuns).Methods("GET")
+
+	k8sCapacityRouter.Path("/node/maintenance/{runId:[0-9]+}").
+		HandlerFunc(impl.k8sCapacityRestHandler.GetNodeMaintenanceRun).Methods("GET")
+
+	k8sCapacityRouter.Path("/node/maintenance/{runId:[0-9]+}/resume").
+		HandlerFunc(impl.k8sCapacityRestHandler.ResumeNodeMaintenance).Methods("PUT")
+
+	k8sCapacityRouter.Path("/node/maintenance/{runId:[0-9]+}/abort").
+		HandlerFunc(impl.k8sCapacityRestHandler.AbortNodeMaintenance).Methods("PUT")
 }
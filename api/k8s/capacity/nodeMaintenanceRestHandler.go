@@ -0,0 +1,191 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package capacity
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/k8s/capacity/bean"
+	"github.com/gorilla/mux"
+	"net/http"
+	"strconv"
+)
+
+const defaultNodeMaintenanceRunsLimit = 20
+
+func (handler *K8sCapacityRestHandlerImpl) StartNodeMaintenance(w http.ResponseWriter, r *http.Request) {
+	decoder := json.NewDecoder(r.Body)
+	var request bean.NodeMaintenanceRequest
+	err := decoder.Decode(&request)
+	if err != nil {
+		handler.logger.Errorw("error in decoding request body", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation error", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.HandleUnauthorized(w, r)
+		return
+	}
+	request.UserId = userId
+	// RBAC enforcer applying, the run can drain any node matching the selection so update access on all nodes is needed
+	token := r.Header.Get("token")
+	if !handler.checkNodeMaintenanceAuthorisation(w, token, request.ClusterId, casbin.ActionUpdate) {
+		return
+	}
+	resp, err := handler.nodeMaintenanceService.StartRun(r.Context(), &request)
+	if err != nil {
+		handler.logger.Errorw("error in starting node maintenance", "err", err, "clusterId", request.ClusterId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *K8sCapacityRestHandlerImpl) GetNodeMaintenanceRuns(w http.ResponseWriter, r *http.Request) {
+	vars := r.URL.Query()
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.HandleUnauthorized(w, r)
+		return
+	}
+	clusterId, err := strconv.Atoi(vars.Get("clusterId"))
+	if err != nil {
+		handler.logger.Errorw("request err, GetNodeMaintenanceRuns", "err", err, "clusterId", clusterId)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	offset, limit := 0, defaultNodeMaintenanceRunsLimit
+	if offsetStr := vars.Get("offset"); len(offsetStr) != 0 {
+		if offset, err = strconv.Atoi(offsetStr); err != nil || offset < 0 {
+			common.WriteJsonResp(w, errors.New("invalid offset"), nil, http.StatusBadRequest)
+			return
+		}
+	}
+	if limitStr := vars.Get("limit"); len(limitStr) != 0 {
+		if limit, err = strconv.Atoi(limitStr); err != nil || limit <= 0 {
+			common.WriteJsonResp(w, errors.New("invalid limit"), nil, http.StatusBadRequest)
+			return
+		}
+	}
+	token := r.Header.Get("token")
+	if !handler.checkNodeMaintenanceAuthorisation(w, token, clusterId, casbin.ActionGet) {
+		return
+	}
+	runs, err := handler.nodeMaintenanceService.GetRuns(clusterId, offset, limit)
+	if err != nil {
+		handler.logger.Errorw("error in getting node maintenance runs", "err", err, "clusterId", clusterId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, runs, http.StatusOK)
+}
+
+func (handler *K8sCapacityRestHandlerImpl) GetNodeMaintenanceRun(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.HandleUnauthorized(w, r)
+		return
+	}
+	run, ok := handler.getAuthorisedNodeMaintenanceRun(w, r, casbin.ActionGet)
+	if !ok {
+		return
+	}
+	common.WriteJsonResp(w, nil, run, http.StatusOK)
+}
+
+func (handler *K8sCapacityRestHandlerImpl) ResumeNodeMaintenance(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.HandleUnauthorized(w, r)
+		return
+	}
+	run, ok := handler.getAuthorisedNodeMaintenanceRun(w, r, casbin.ActionUpdate)
+	if !ok {
+		return
+	}
+	resp, err := handler.nodeMaintenanceService.ResumeRun(run.Id, userId)
+	if err != nil {
+		handler.logger.Errorw("error in resuming node maintenance", "err", err, "runId", run.Id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *K8sCapacityRestHandlerImpl) AbortNodeMaintenance(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.HandleUnauthorized(w, r)
+		return
+	}
+	run, ok := handler.getAuthorisedNodeMaintenanceRun(w, r, casbin.ActionUpdate)
+	if !ok {
+		return
+	}
+	resp, err := handler.nodeMaintenanceService.AbortRun(run.Id, userId)
+	if err != nil {
+		handler.logger.Errorw("error in aborting node maintenance", "err", err, "runId", run.Id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+// getAuthorisedNodeMaintenanceRun fetches the run of the path and checks the access on the nodes of its cluster,
+// the response is written if the run cannot be returned
+func (handler *K8sCapacityRestHandlerImpl) getAuthorisedNodeMaintenanceRun(w http.ResponseWriter, r *http.Request, action string) (*bean.NodeMaintenanceRunDto, bool) {
+	runId, err := strconv.Atoi(mux.Vars(r)["runId"])
+	if err != nil {
+		handler.logger.Errorw("request err, node maintenance run id", "err", err, "runId", runId)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return nil, false
+	}
+	run, err := handler.nodeMaintenanceService.GetRun(runId)
+	if err != nil {
+		handler.logger.Errorw("error in getting node maintenance run", "err", err, "runId", runId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return nil, false
+	}
+	token := r.Header.Get("token")
+	if !handler.checkNodeMaintenanceAuthorisation(w, token, run.ClusterId, action) {
+		return nil, false
+	}
+	return run, true
+}
+
+func (handler *K8sCapacityRestHandlerImpl) checkNodeMaintenanceAuthorisation(w http.ResponseWriter, token string, clusterId int, action string) bool {
+	authenticated, err := handler.clusterRbacService.CheckAuthorisationForNodeWithClusterId(token, clusterId, "", action)
+	if err != nil {
+		handler.logger.Errorw("error in checking rbac for cluster", "err", err, "clusterId", clusterId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return false
+	}
+	if !authenticated {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return false
+	}
+	return true
+}
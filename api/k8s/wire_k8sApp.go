@@ -24,6 +24,7 @@ import (
 	"github.com/devtron-labs/devtron/pkg/k8s"
 	application2 "github.com/devtron-labs/devtron/pkg/k8s/application"
 	capacity2 "github.com/devtron-labs/devtron/pkg/k8s/capacity"
+	capacityRepository "github.com/devtron-labs/devtron/pkg/k8s/capacity/repository"
 	"github.com/devtron-labs/devtron/pkg/k8s/informer"
 	"github.com/devtron-labs/devtron/pkg/terminal"
 	"github.com/google/wire"
@@ -50,6 +51,10 @@ var K8sApplicationWireSet = wire.NewSet(
 	wire.Bind(new(capacity.K8sCapacityRestHandler), new(*capacity.K8sCapacityRestHandlerImpl)),
 	capacity2.NewK8sCapacityServiceImpl,
 	wire.Bind(new(capacity2.K8sCapacityService), new(*capacity2.K8sCapacityServiceImpl)),
+	capacityRepository.NewNodeMaintenanceRunRepositoryImpl,
+	wire.Bind(new(capacityRepository.NodeMaintenanceRunRepository), new(*capacityRepository.NodeMaintenanceRunRepositoryImpl)),
+	capacity2.NewNodeMaintenanceServiceImpl,
+	wire.Bind(new(capacity2.NodeMaintenanceService), new(*capacity2.NodeMaintenanceServiceImpl)),
 	informer.NewGlobalMapClusterNamespace,
 	informer.NewK8sInformerFactoryImpl,
 	wire.Bind(new(informer.K8sInformerFactory), new(*informer.K8sInformerFactoryImpl)),
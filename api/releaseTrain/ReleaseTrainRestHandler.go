@@ -0,0 +1,452 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package releaseTrain
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	"github.com/devtron-labs/devtron/pkg/releaseTrain"
+	"github.com/devtron-labs/devtron/pkg/releaseTrain/bean"
+	"github.com/devtron-labs/devtron/util/rbac"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+	"net/http"
+)
+
+type ReleaseTrainRestHandler interface {
+	GetAllReleaseTrains(w http.ResponseWriter, r *http.Request)
+	GetReleaseTrain(w http.ResponseWriter, r *http.Request)
+	CreateReleaseTrain(w http.ResponseWriter, r *http.Request)
+	UpdateReleaseTrain(w http.ResponseWriter, r *http.Request)
+	DeleteReleaseTrain(w http.ResponseWriter, r *http.Request)
+	StartRun(w http.ResponseWriter, r *http.Request)
+	GetRuns(w http.ResponseWriter, r *http.Request)
+	GetRun(w http.ResponseWriter, r *http.Request)
+	ApproveStage(w http.ResponseWriter, r *http.Request)
+	ResumeRun(w http.ResponseWriter, r *http.Request)
+	AbortRun(w http.ResponseWriter, r *http.Request)
+}
+
+type ReleaseTrainRestHandlerImpl struct {
+	logger              *zap.SugaredLogger
+	userService         user.UserService
+	releaseTrainService releaseTrain.ReleaseTrainService
+	enforcer            casbin.Enforcer
+	enforcerUtil        rbac.EnforcerUtil
+	validator           *validator.Validate
+}
+
+func NewReleaseTrainRestHandlerImpl(logger *zap.SugaredLogger,
+	userService user.UserService,
+	releaseTrainService releaseTrain.ReleaseTrainService,
+	enforcer casbin.Enforcer,
+	enforcerUtil rbac.EnforcerUtil,
+	validator *validator.Validate) *ReleaseTrainRestHandlerImpl {
+	return &ReleaseTrainRestHandlerImpl{
+		logger:              logger,
+		userService:         userService,
+		releaseTrainService: releaseTrainService,
+		enforcer:            enforcer,
+		enforcerUtil:        enforcerUtil,
+		validator:           validator,
+	}
+}
+
+// GetAllReleaseTrains returns the trains having all their apps accessible to the user
+func (handler *ReleaseTrainRestHandlerImpl) GetAllReleaseTrains(w http.ResponseWriter, r *http.Request) {
+	trains, err := handler.releaseTrainService.GetAllReleaseTrains()
+	if err != nil {
+		handler.logger.Errorw("service err, GetAllReleaseTrains", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	token := r.Header.Get("token")
+	authorisedTrains := make([]*bean.ReleaseTrainDto, 0, len(trains))
+	for _, train := range trains {
+		if handler.isAuthorisedForApps(token, train.AppIds, casbin.ActionGet) {
+			authorisedTrains = append(authorisedTrains, train)
+		}
+	}
+	common.WriteJsonResp(w, nil, authorisedTrains, http.StatusOK)
+}
+
+func (handler *ReleaseTrainRestHandlerImpl) GetReleaseTrain(w http.ResponseWriter, r *http.Request) {
+	id, err := common.ExtractIntPathParamWithContext(w, r, "id")
+	if err != nil {
+		return
+	}
+	train, err := handler.releaseTrainService.GetReleaseTrain(id)
+	if err != nil {
+		handler.logger.Errorw("service err, GetReleaseTrain", "id", id, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	if !handler.isAuthorisedForApps(r.Header.Get("token"), train.AppIds, casbin.ActionGet) {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	common.WriteJsonResp(w, nil, train, http.StatusOK)
+}
+
+func (handler *ReleaseTrainRestHandlerImpl) CreateReleaseTrain(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.HandleUnauthorized(w, r)
+		return
+	}
+	train, ok := handler.decodeReleaseTrain(w, r)
+	if !ok {
+		return
+	}
+	if !handler.isAuthorisedForApps(r.Header.Get("token"), train.AppIds, casbin.ActionUpdate) {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	train.Id = 0
+	train.UserId = userId
+	train, err = handler.releaseTrainService.CreateReleaseTrain(r.Context(), train)
+	if err != nil {
+		handler.logger.Errorw("service err, CreateReleaseTrain", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, train, http.StatusOK)
+}
+
+func (handler *ReleaseTrainRestHandlerImpl) UpdateReleaseTrain(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.HandleUnauthorized(w, r)
+		return
+	}
+	id, err := common.ExtractIntPathParamWithContext(w, r, "id")
+	if err != nil {
+		return
+	}
+	train, ok := handler.decodeReleaseTrain(w, r)
+	if !ok {
+		return
+	}
+	existingTrain, err := handler.releaseTrainService.GetReleaseTrain(id)
+	if err != nil {
+		handler.logger.Errorw("service err, UpdateReleaseTrain", "id", id, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	// the user needs access to the apps being removed from the train as well
+	token := r.Header.Get("token")
+	if !handler.isAuthorisedForApps(token, existingTrain.AppIds, casbin.ActionUpdate) ||
+		!handler.isAuthorisedForApps(token, train.AppIds, casbin.ActionUpdate) {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	train.Id = id
+	train.UserId = userId
+	train, err = handler.releaseTrainService.UpdateReleaseTrain(r.Context(), train)
+	if err != nil {
+		handler.logger.Errorw("service err, UpdateReleaseTrain", "id", id, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, train, http.StatusOK)
+}
+
+func (handler *ReleaseTrainRestHandlerImpl) DeleteReleaseTrain(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.HandleUnauthorized(w, r)
+		return
+	}
+	id, err := common.ExtractIntPathParamWithContext(w, r, "id")
+	if err != nil {
+		return
+	}
+	train, err := handler.releaseTrainService.GetReleaseTrain(id)
+	if err != nil {
+		handler.logger.Errorw("service err, DeleteReleaseTrain", "id", id, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	if !handler.isAuthorisedForApps(r.Header.Get("token"), train.AppIds, casbin.ActionUpdate) {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	err = handler.releaseTrainService.DeleteReleaseTrain(id, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, DeleteReleaseTrain", "id", id, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, id, http.StatusOK)
+}
+
+// StartRun needs trigger access on all the apps of the train on the environments of all the stages
+func (handler *ReleaseTrainRestHandlerImpl) StartRun(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.HandleUnauthorized(w, r)
+		return
+	}
+	id, err := common.ExtractIntPathParamWithContext(w, r, "id")
+	if err != nil {
+		return
+	}
+	request := &bean.ReleaseTrainRunRequest{}
+	err = json.NewDecoder(r.Body).Decode(request)
+	if err != nil {
+		handler.logger.Errorw("request err, StartRun", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err, StartRun", "payload", request, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	train, err := handler.releaseTrainService.GetReleaseTrain(id)
+	if err != nil {
+		handler.logger.Errorw("service err, StartRun", "id", id, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	envIds := make([]int, 0, len(train.Stages))
+	for _, stage := range train.Stages {
+		envIds = append(envIds, stage.EnvironmentId)
+	}
+	if !handler.isAuthorisedToTrigger(r.Header.Get("token"), train.AppIds, envIds) {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	request.ReleaseTrainId = id
+	request.UserId = userId
+	run, err := handler.releaseTrainService.StartRun(r.Context(), request)
+	if err != nil {
+		handler.logger.Errorw("service err, StartRun", "id", id, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, run, http.StatusOK)
+}
+
+func (handler *ReleaseTrainRestHandlerImpl) GetRuns(w http.ResponseWriter, r *http.Request) {
+	id, err := common.ExtractIntPathParamWithContext(w, r, "id")
+	if err != nil {
+		return
+	}
+	offset, err := common.ExtractIntQueryParam(w, r, "offset", 0)
+	if err != nil {
+		return
+	}
+	size, err := common.ExtractIntQueryParam(w, r, "size", 20)
+	if err != nil {
+		return
+	}
+	train, err := handler.releaseTrainService.GetReleaseTrain(id)
+	if err != nil {
+		handler.logger.Errorw("service err, GetRuns", "id", id, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	if !handler.isAuthorisedForApps(r.Header.Get("token"), train.AppIds, casbin.ActionGet) {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	runs, err := handler.releaseTrainService.GetRuns(id, offset, size)
+	if err != nil {
+		handler.logger.Errorw("service err, GetRuns", "id", id, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, runs, http.StatusOK)
+}
+
+func (handler *ReleaseTrainRestHandlerImpl) GetRun(w http.ResponseWriter, r *http.Request) {
+	run, ok := handler.getRun(w, r)
+	if !ok {
+		return
+	}
+	if !handler.isAuthorisedForApps(r.Header.Get("token"), getRunAppIds(run), casbin.ActionGet) {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	common.WriteJsonResp(w, nil, run, http.StatusOK)
+}
+
+// ApproveStage needs trigger access on all the apps of the run on the environment of the stage
+func (handler *ReleaseTrainRestHandlerImpl) ApproveStage(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.HandleUnauthorized(w, r)
+		return
+	}
+	stageId, err := common.ExtractIntPathParamWithContext(w, r, "stageId")
+	if err != nil {
+		return
+	}
+	run, ok := handler.getRun(w, r)
+	if !ok {
+		return
+	}
+	var stage *bean.ReleaseTrainRunStageDto
+	for _, runStage := range run.Stages {
+		if runStage.Id == stageId {
+			stage = runStage
+		}
+	}
+	if stage == nil {
+		common.WriteJsonResp(w, errors.New("stage not found in the release train run"), nil, http.StatusNotFound)
+		return
+	}
+	if !handler.isAuthorisedToTrigger(r.Header.Get("token"), getRunAppIds(run), []int{stage.EnvironmentId}) {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	run, err = handler.releaseTrainService.ApproveStage(run.Id, stageId, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, ApproveStage", "stageId", stageId, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, run, http.StatusOK)
+}
+
+func (handler *ReleaseTrainRestHandlerImpl) ResumeRun(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.HandleUnauthorized(w, r)
+		return
+	}
+	run, ok := handler.getRun(w, r)
+	if !ok {
+		return
+	}
+	if !handler.isAuthorisedToTrigger(r.Header.Get("token"), getRunAppIds(run), getRunEnvIds(run)) {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	run, err = handler.releaseTrainService.ResumeRun(run.Id, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, ResumeRun", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, run, http.StatusOK)
+}
+
+func (handler *ReleaseTrainRestHandlerImpl) AbortRun(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.HandleUnauthorized(w, r)
+		return
+	}
+	run, ok := handler.getRun(w, r)
+	if !ok {
+		return
+	}
+	if !handler.isAuthorisedToTrigger(r.Header.Get("token"), getRunAppIds(run), getRunEnvIds(run)) {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	err = handler.releaseTrainService.AbortRun(run.Id, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, AbortRun", "runId", run.Id, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, run.Id, http.StatusOK)
+}
+
+func (handler *ReleaseTrainRestHandlerImpl) getRun(w http.ResponseWriter, r *http.Request) (*bean.ReleaseTrainRunDto, bool) {
+	runId, err := common.ExtractIntPathParamWithContext(w, r, "runId")
+	if err != nil {
+		return nil, false
+	}
+	run, err := handler.releaseTrainService.GetRun(runId)
+	if err != nil {
+		handler.logger.Errorw("service err, GetRun", "runId", runId, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return nil, false
+	}
+	return run, true
+}
+
+func (handler *ReleaseTrainRestHandlerImpl) decodeReleaseTrain(w http.ResponseWriter, r *http.Request) (*bean.ReleaseTrainDto, bool) {
+	train := &bean.ReleaseTrainDto{}
+	err := json.NewDecoder(r.Body).Decode(train)
+	if err != nil {
+		handler.logger.Errorw("request err, decodeReleaseTrain", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return nil, false
+	}
+	err = handler.validator.Struct(train)
+	if err != nil {
+		handler.logger.Errorw("validation err, decodeReleaseTrain", "payload", train, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return nil, false
+	}
+	return train, true
+}
+
+// isAuthorisedForApps checks the access on all the apps, the apps which are not found are treated as unauthorised
+func (handler *ReleaseTrainRestHandlerImpl) isAuthorisedForApps(token string, appIds []int, action string) bool {
+	rbacObjects := handler.enforcerUtil.GetRbacObjectsByAppIds(appIds)
+	for _, appId := range appIds {
+		rbacObject, ok := rbacObjects[appId]
+		if !ok || !handler.enforcer.Enforce(token, casbin.ResourceApplications, action, rbacObject) {
+			return false
+		}
+	}
+	return true
+}
+
+// isAuthorisedToTrigger checks the trigger access of all the apps on all the environments
+func (handler *ReleaseTrainRestHandlerImpl) isAuthorisedToTrigger(token string, appIds []int, envIds []int) bool {
+	appIdToEnvIds := make(map[int][]int, len(appIds))
+	for _, appId := range appIds {
+		appIdToEnvIds[appId] = envIds
+	}
+	rbacObjects := handler.enforcerUtil.GetRbacObjectsByEnvIdsAndAppIdBatch(appIdToEnvIds)
+	for _, appId := range appIds {
+		for _, envId := range envIds {
+			rbacObject, ok := rbacObjects[appId][envId]
+			if !ok || !handler.enforcer.Enforce(token, casbin.ResourceEnvironment, casbin.ActionTrigger, rbacObject) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func getRunAppIds(run *bean.ReleaseTrainRunDto) []int {
+	appIds := make([]int, 0, len(run.Artifacts))
+	for _, artifact := range run.Artifacts {
+		appIds = append(appIds, artifact.AppId)
+	}
+	return appIds
+}
+
+func getRunEnvIds(run *bean.ReleaseTrainRunDto) []int {
+	envIds := make([]int, 0, len(run.Stages))
+	for _, stage := range run.Stages {
+		envIds = append(envIds, stage.EnvironmentId)
+	}
+	return envIds
+}
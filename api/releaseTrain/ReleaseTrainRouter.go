@@ -0,0 +1,79 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package releaseTrain
+
+import "github.com/gorilla/mux"
+
+type ReleaseTrainRouter interface {
+	InitReleaseTrainRouter(configRouter *mux.Router)
+}
+
+type ReleaseTrainRouterImpl struct {
+	releaseTrainRestHandler ReleaseTrainRestHandler
+}
+
+func NewReleaseTrainRouterImpl(releaseTrainRestHandler ReleaseTrainRestHandler) *ReleaseTrainRouterImpl {
+	return &ReleaseTrainRouterImpl{
+		releaseTrainRestHandler: releaseTrainRestHandler,
+	}
+}
+
+func (impl *ReleaseTrainRouterImpl) InitReleaseTrainRouter(configRouter *mux.Router) {
+	configRouter.Path("").
+		HandlerFunc(impl.releaseTrainRestHandler.GetAllReleaseTrains).
+		Methods("GET")
+
+	configRouter.Path("").
+		HandlerFunc(impl.releaseTrainRestHandler.CreateReleaseTrain).
+		Methods("POST")
+
+	configRouter.Path("/{id:[0-9]+}").
+		HandlerFunc(impl.releaseTrainRestHandler.GetReleaseTrain).
+		Methods("GET")
+
+	configRouter.Path("/{id:[0-9]+}").
+		HandlerFunc(impl.releaseTrainRestHandler.UpdateReleaseTrain).
+		Methods("PUT")
+
+	configRouter.Path("/{id:[0-9]+}").
+		HandlerFunc(impl.releaseTrainRestHandler.DeleteReleaseTrain).
+		Methods("DELETE")
+
+	configRouter.Path("/{id:[0-9]+}/run").
+		HandlerFunc(impl.releaseTrainRestHandler.StartRun).
+		Methods("POST")
+
+	configRouter.Path("/{id:[0-9]+}/run").
+		HandlerFunc(impl.releaseTrainRestHandler.GetRuns).
+		Methods("GET")
+
+	configRouter.Path("/run/{runId:[0-9]+}").
+		HandlerFunc(impl.releaseTrainRestHandler.GetRun).
+		Methods("GET")
+
+	configRouter.Path("/run/{runId:[0-9]+}/stage/{stageId:[0-9]+}/approve").
+		HandlerFunc(impl.releaseTrainRestHandler.ApproveStage).
+		Methods("POST")
+
+	configRouter.Path("/run/{runId:[0-9]+}/resume").
+		HandlerFunc(impl.releaseTrainRestHandler.ResumeRun).
+		Methods("POST")
+
+	configRouter.Path("/run/{runId:[0-9]+}/abort").
+		HandlerFunc(impl.releaseTrainRestHandler.AbortRun).
+		Methods("POST")
+}
@@ -0,0 +1,226 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package restHandler
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/internal/sql/repository/helper"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	util2 "github.com/devtron-labs/devtron/pkg/auth/user/util"
+	"github.com/devtron-labs/devtron/pkg/bulkAction/bean"
+	"github.com/gorilla/mux"
+	"net/http"
+	"strconv"
+)
+
+func (handler BulkUpdateRestHandlerImpl) CreateBulkEditJob(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.HandleUnauthorized(w, r)
+		return
+	}
+	decoder := json.NewDecoder(r.Body)
+	var request bean.BulkEditJobRequest
+	err = decoder.Decode(&request)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err, CreateBulkEditJob", "err", err, "request", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	token := r.Header.Get("token")
+	impactedObjects, err := handler.bulkUpdateService.DryRunBulkEdit(request.Script.Spec)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	if ok := handler.checkAuthForBulkEdit(impactedObjects, token); !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	isSuperAdmin := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionCreate, "*")
+	request.UserMetadata = util2.GetUserMetadata(r.Context(), userId, isSuperAdmin)
+	request.Impacted = impactedObjects
+	job, err := handler.bulkEditJobService.CreateJob(r.Context(), &request)
+	if err != nil {
+		handler.logger.Errorw("service err, CreateBulkEditJob", "err", err, "request", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, job, http.StatusOK)
+}
+
+func (handler BulkUpdateRestHandlerImpl) GetBulkEditJobs(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.HandleUnauthorized(w, r)
+		return
+	}
+	offset := 0
+	limit := 20
+	offsetQueryParam := r.URL.Query().Get("offset")
+	if offsetQueryParam != "" {
+		offset, err = strconv.Atoi(offsetQueryParam)
+		if err != nil || offset < 0 {
+			handler.logger.Errorw("request err, GetBulkEditJobs", "offsetQueryParam", offsetQueryParam, "err", err)
+			common.WriteJsonResp(w, err, "invalid offset", http.StatusBadRequest)
+			return
+		}
+	}
+	sizeQueryParam := r.URL.Query().Get("size")
+	if sizeQueryParam != "" {
+		limit, err = strconv.Atoi(sizeQueryParam)
+		if err != nil || limit <= 0 {
+			handler.logger.Errorw("request err, GetBulkEditJobs", "sizeQueryParam", sizeQueryParam, "err", err)
+			common.WriteJsonResp(w, err, "invalid size", http.StatusBadRequest)
+			return
+		}
+	}
+	token := r.Header.Get("token")
+	// users other than super admin can only see their own jobs
+	isSuperAdmin := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionCreate, "*")
+	userMetadata := util2.GetUserMetadata(r.Context(), userId, isSuperAdmin)
+	jobs, err := handler.bulkEditJobService.GetAllJobs(userMetadata, offset, limit)
+	if err != nil {
+		handler.logger.Errorw("service err, GetBulkEditJobs", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, jobs, http.StatusOK)
+}
+
+func (handler BulkUpdateRestHandlerImpl) GetBulkEditJob(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.HandleUnauthorized(w, r)
+		return
+	}
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	job, err := handler.bulkEditJobService.GetJob(id)
+	if err != nil {
+		handler.logger.Errorw("service err, GetBulkEditJob", "err", err, "id", id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	token := r.Header.Get("token")
+	if ok := handler.checkAuthForBulkEditJob(job, userId, token); !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	common.WriteJsonResp(w, nil, job, http.StatusOK)
+}
+
+func (handler BulkUpdateRestHandlerImpl) CancelBulkEditJob(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.HandleUnauthorized(w, r)
+		return
+	}
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	job, err := handler.bulkEditJobService.GetJob(id)
+	if err != nil {
+		handler.logger.Errorw("service err, CancelBulkEditJob", "err", err, "id", id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	token := r.Header.Get("token")
+	if ok := handler.checkAuthForBulkEditJob(job, userId, token); !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	err = handler.bulkEditJobService.CancelJob(id, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, CancelBulkEditJob", "err", err, "id", id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, "bulk edit job cancelled", http.StatusOK)
+}
+
+func (handler BulkUpdateRestHandlerImpl) RollbackBulkEditJob(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.HandleUnauthorized(w, r)
+		return
+	}
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	var request bean.BulkEditJobRollbackRequest
+	if r.ContentLength != 0 {
+		err = json.NewDecoder(r.Body).Decode(&request)
+		if err != nil {
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return
+		}
+	}
+	request.UserId = userId
+	job, err := handler.bulkEditJobService.GetJob(id)
+	if err != nil {
+		handler.logger.Errorw("service err, RollbackBulkEditJob", "err", err, "id", id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	token := r.Header.Get("token")
+	if ok := handler.checkAuthForBulkEditJob(job, userId, token) && handler.checkAuthForBulkEditJobObjects(job, token); !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	job, err = handler.bulkEditJobService.RollbackJob(r.Context(), id, &request)
+	if err != nil {
+		handler.logger.Errorw("service err, RollbackBulkEditJob", "err", err, "id", id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, job, http.StatusOK)
+}
+
+// checkAuthForBulkEditJob allows access on a job only to the user who created it and to super admins
+func (handler BulkUpdateRestHandlerImpl) checkAuthForBulkEditJob(job *bean.BulkEditJobDto, userId int32, token string) bool {
+	if job.CreatedBy == userId {
+		return true
+	}
+	return handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionCreate, "*")
+}
+
+// checkAuthForBulkEditJobObjects checks the update access of the user on every object of the job,
+// the access on the job does not grant access on the apps and environments it edited
+func (handler BulkUpdateRestHandlerImpl) checkAuthForBulkEditJobObjects(job *bean.BulkEditJobDto, token string) bool {
+	rbacObjects := handler.enforcerUtil.GetRbacObjectsForAllApps(helper.CustomApp)
+	for _, object := range job.Objects {
+		if ok := handler.CheckAuthForBulkUpdate(object.AppId, object.EnvId, object.AppName, rbacObjects, token); !ok {
+			return false
+		}
+	}
+	return true
+}
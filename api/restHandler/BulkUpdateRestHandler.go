@@ -18,6 +18,7 @@ package restHandler
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	util2 "github.com/devtron-labs/devtron/pkg/auth/user/util"
 	"github.com/devtron-labs/devtron/pkg/build/git/gitMaterial/repository"
@@ -25,6 +26,7 @@ import (
 	"github.com/devtron-labs/devtron/pkg/bulkAction/bean"
 	"github.com/devtron-labs/devtron/pkg/bulkAction/service"
 	"github.com/devtron-labs/devtron/pkg/cluster/environment"
+	deploymentWindowBean "github.com/devtron-labs/devtron/pkg/policyGovernance/deploymentWindow/bean"
 	"github.com/devtron-labs/devtron/util"
 	"net/http"
 	"strconv"
@@ -69,6 +71,16 @@ type BulkEditV1Beta1RestHandler interface {
 	GetBulkEditConfig(w http.ResponseWriter, r *http.Request)
 	DryRunBulkEdit(w http.ResponseWriter, r *http.Request)
 	BulkEdit(w http.ResponseWriter, r *http.Request)
+	BulkEditJobRestHandler
+}
+
+// BulkEditJobRestHandler defines the bulk edits persisted as jobs, which can be scheduled and rolled back
+type BulkEditJobRestHandler interface {
+	CreateBulkEditJob(w http.ResponseWriter, r *http.Request)
+	GetBulkEditJobs(w http.ResponseWriter, r *http.Request)
+	GetBulkEditJob(w http.ResponseWriter, r *http.Request)
+	CancelBulkEditJob(w http.ResponseWriter, r *http.Request)
+	RollbackBulkEditJob(w http.ResponseWriter, r *http.Request)
 }
 
 type BulkUpdateRestHandlerImpl struct {
@@ -92,6 +104,7 @@ type BulkUpdateRestHandlerImpl struct {
 	cdHandler               pipeline.CdHandler
 	appCloneService         appClone.AppCloneService
 	materialRepository      repository.MaterialRepository
+	bulkEditJobService      service.BulkEditJobService
 }
 
 func NewBulkUpdateRestHandlerImpl(pipelineBuilder pipeline.PipelineBuilder, logger *zap.SugaredLogger,
@@ -110,6 +123,7 @@ func NewBulkUpdateRestHandlerImpl(pipelineBuilder pipeline.PipelineBuilder, logg
 	appCloneService appClone.AppCloneService,
 	appWorkflowService appWorkflow.AppWorkflowService,
 	materialRepository repository.MaterialRepository,
+	bulkEditJobService service.BulkEditJobService,
 ) *BulkUpdateRestHandlerImpl {
 	return &BulkUpdateRestHandlerImpl{
 		pipelineBuilder:         pipelineBuilder,
@@ -132,6 +146,7 @@ func NewBulkUpdateRestHandlerImpl(pipelineBuilder pipeline.PipelineBuilder, logg
 		appCloneService:         appCloneService,
 		appWorkflowService:      appWorkflowService,
 		materialRepository:      materialRepository,
+		bulkEditJobService:      bulkEditJobService,
 	}
 }
 
@@ -226,6 +241,27 @@ func (handler BulkUpdateRestHandlerImpl) CheckAuthForBulkUpdate(AppId int, EnvId
 
 }
 
+// checkAuthForBulkEdit checks the update access of the user on all the objects impacted by a bulk edit
+func (handler BulkUpdateRestHandlerImpl) checkAuthForBulkEdit(impactedObjects *bean.ImpactedObjectsResponse, token string) bool {
+	rbacObjects := handler.enforcerUtil.GetRbacObjectsForAllApps(helper.CustomApp)
+	for _, deploymentTemplateImpactedApp := range impactedObjects.DeploymentTemplate {
+		if ok := handler.CheckAuthForBulkUpdate(deploymentTemplateImpactedApp.AppId, deploymentTemplateImpactedApp.EnvId, deploymentTemplateImpactedApp.AppName, rbacObjects, token); !ok {
+			return false
+		}
+	}
+	for _, impactedConfigMap := range impactedObjects.ConfigMap {
+		if ok := handler.CheckAuthForBulkUpdate(impactedConfigMap.AppId, impactedConfigMap.EnvId, impactedConfigMap.AppName, rbacObjects, token); !ok {
+			return false
+		}
+	}
+	for _, impactedSecret := range impactedObjects.Secret {
+		if ok := handler.CheckAuthForBulkUpdate(impactedSecret.AppId, impactedSecret.EnvId, impactedSecret.AppName, rbacObjects, token); !ok {
+			return false
+		}
+	}
+	return true
+}
+
 func (handler BulkUpdateRestHandlerImpl) BulkEdit(w http.ResponseWriter, r *http.Request) {
 	userId, err := handler.userAuthService.GetLoggedInUser(r)
 	if userId == 0 || err != nil {
@@ -251,27 +287,9 @@ func (handler BulkUpdateRestHandlerImpl) BulkEdit(w http.ResponseWriter, r *http
 		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
 		return
 	}
-	rbacObjects := handler.enforcerUtil.GetRbacObjectsForAllApps(helper.CustomApp)
-	for _, deploymentTemplateImpactedApp := range impactedObjects.DeploymentTemplate {
-		ok := handler.CheckAuthForBulkUpdate(deploymentTemplateImpactedApp.AppId, deploymentTemplateImpactedApp.EnvId, deploymentTemplateImpactedApp.AppName, rbacObjects, token)
-		if !ok {
-			common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
-			return
-		}
-	}
-	for _, impactedConfigMap := range impactedObjects.ConfigMap {
-		ok := handler.CheckAuthForBulkUpdate(impactedConfigMap.AppId, impactedConfigMap.EnvId, impactedConfigMap.AppName, rbacObjects, token)
-		if !ok {
-			common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
-			return
-		}
-	}
-	for _, impactedSecret := range impactedObjects.Secret {
-		ok := handler.CheckAuthForBulkUpdate(impactedSecret.AppId, impactedSecret.EnvId, impactedSecret.AppName, rbacObjects, token)
-		if !ok {
-			common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
-			return
-		}
+	if ok := handler.checkAuthForBulkEdit(impactedObjects, token); !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
 	}
 	isSuperAdmin := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionCreate, "*")
 	userMetadata := util2.GetUserMetadata(r.Context(), userId, isSuperAdmin)
@@ -356,10 +374,14 @@ func (handler BulkUpdateRestHandlerImpl) BulkDeploy(w http.ResponseWriter, r *ht
 		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
 		return
 	}
+	if request.DeploymentWindowOverride && len(request.DeploymentWindowOverrideReason) == 0 {
+		common.WriteJsonResp(w, errors.New(deploymentWindowBean.OverrideReasonRequired), nil, http.StatusBadRequest)
+		return
+	}
 	ctx := util.NewRequestCtx(r.Context())
 	isSuperAdmin := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionCreate, "*")
 	userMetadata := util2.GetUserMetadata(ctx, userId, isSuperAdmin)
-	response, err := handler.bulkUpdateService.BulkDeploy(ctx, &request, handler.checkAuthBatch, userMetadata)
+	response, err := handler.bulkUpdateService.BulkDeploy(ctx, &request, handler.checkAuthBatch, handler.checkDeploymentWindowOverrideAuthBatch, userMetadata)
 	if err != nil {
 		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
 		return
@@ -495,3 +517,11 @@ func (handler BulkUpdateRestHandlerImpl) checkAuthBatch(token string, appObject
 	}
 	return appResult, envResult
 }
+
+func (handler BulkUpdateRestHandlerImpl) checkDeploymentWindowOverrideAuthBatch(token string, envObject []string) map[string]bool {
+	if len(envObject) == 0 {
+		return nil
+	}
+	// overriding deployment windows requires edit access (admin/manager) on the app and environment
+	return handler.enforcer.EnforceInBatch(token, casbin.ResourceEnvironment, casbin.ActionUpdate, envObject)
+}
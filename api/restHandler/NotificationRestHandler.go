@@ -44,10 +44,11 @@ import (
 )
 
 const (
-	SLACK_CONFIG_DELETE_SUCCESS_RESP   = "Slack config deleted successfully."
-	WEBHOOK_CONFIG_DELETE_SUCCESS_RESP = "Webhook config deleted successfully."
-	SES_CONFIG_DELETE_SUCCESS_RESP     = "SES config deleted successfully."
-	SMTP_CONFIG_DELETE_SUCCESS_RESP    = "SMTP config deleted successfully."
+	SLACK_CONFIG_DELETE_SUCCESS_RESP    = "Slack config deleted successfully."
+	WEBHOOK_CONFIG_DELETE_SUCCESS_RESP  = "Webhook config deleted successfully."
+	SES_CONFIG_DELETE_SUCCESS_RESP      = "SES config deleted successfully."
+	SMTP_CONFIG_DELETE_SUCCESS_RESP     = "SMTP config deleted successfully."
+	MS_TEAMS_CONFIG_DELETE_SUCCESS_RESP = "MS Teams config deleted successfully."
 )
 
 type NotificationRestHandler interface {
@@ -58,6 +59,7 @@ type NotificationRestHandler interface {
 	FindSlackConfig(w http.ResponseWriter, r *http.Request)
 	FindSMTPConfig(w http.ResponseWriter, r *http.Request)
 	FindWebhookConfig(w http.ResponseWriter, r *http.Request)
+	FindMsTeamsConfig(w http.ResponseWriter, r *http.Request)
 	GetWebhookVariables(w http.ResponseWriter, r *http.Request)
 	FindAllNotificationConfig(w http.ResponseWriter, r *http.Request)
 	GetAllNotificationSettings(w http.ResponseWriter, r *http.Request)
@@ -79,6 +81,7 @@ type NotificationRestHandlerImpl struct {
 	webhookService       notifier.WebhookNotificationService
 	sesService           notifier.SESNotificationService
 	smtpService          notifier.SMTPNotificationService
+	msTeamsService       notifier.MsTeamsNotificationService
 	enforcer             casbin.Enforcer
 	environmentService   environment.EnvironmentService
 	pipelineBuilder      pipeline.PipelineBuilder
@@ -97,7 +100,7 @@ func NewNotificationRestHandlerImpl(dockerRegistryConfig pipeline.DockerRegistry
 	slackService notifier.SlackNotificationService, webhookService notifier.WebhookNotificationService, sesService notifier.SESNotificationService, smtpService notifier.SMTPNotificationService,
 	enforcer casbin.Enforcer, environmentService environment.EnvironmentService, pipelineBuilder pipeline.PipelineBuilder,
 	enforcerUtil rbac.EnforcerUtil,
-	teamReadService read.TeamReadService) *NotificationRestHandlerImpl {
+	teamReadService read.TeamReadService, msTeamsService notifier.MsTeamsNotificationService) *NotificationRestHandlerImpl {
 	return &NotificationRestHandlerImpl{
 		dockerRegistryConfig: dockerRegistryConfig,
 		logger:               logger,
@@ -109,6 +112,7 @@ func NewNotificationRestHandlerImpl(dockerRegistryConfig pipeline.DockerRegistry
 		webhookService:       webhookService,
 		sesService:           sesService,
 		smtpService:          smtpService,
+		msTeamsService:       msTeamsService,
 		enforcer:             enforcer,
 		environmentService:   environmentService,
 		pipelineBuilder:      pipelineBuilder,
@@ -416,6 +420,48 @@ func (impl NotificationRestHandlerImpl) SaveNotificationChannelConfig(w http.Res
 		}
 		w.Header().Set("Content-Type", "application/json")
 		common.WriteJsonResp(w, nil, res, http.StatusOK)
+	} else if util.MsTeams == channelReq.Channel {
+		var msTeamsReq *beans.MsTeamsChannelConfig
+		err = json.NewDecoder(ioutil.NopCloser(bytes.NewBuffer(data))).Decode(&msTeamsReq)
+		if err != nil {
+			impl.logger.Errorw("request err, SaveNotificationChannelConfig", "err", err, "msTeamsReq", msTeamsReq)
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return
+		}
+
+		err = impl.validator.Struct(msTeamsReq)
+		if err != nil {
+			impl.logger.Errorw("validation err, SaveNotificationChannelConfig", "err", err, "msTeamsReq", msTeamsReq)
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return
+		}
+
+		//RBAC
+		var teamIds []*int
+		for _, item := range msTeamsReq.MsTeamsConfigDtos {
+			teamIds = append(teamIds, &item.TeamId)
+		}
+		teams, err := impl.teamReadService.FindByIds(teamIds)
+		if err != nil {
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return
+		}
+		for _, item := range teams {
+			if ok := impl.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionCreate, fmt.Sprintf("%s/*", item.Name)); !ok {
+				common.WriteJsonResp(w, err, "Unauthorized User", http.StatusForbidden)
+				return
+			}
+		}
+		//RBAC
+
+		res, cErr := impl.msTeamsService.SaveOrEditNotificationConfig(msTeamsReq.MsTeamsConfigDtos, userId)
+		if cErr != nil {
+			impl.logger.Errorw("service err, SaveNotificationChannelConfig", "err", cErr, "msTeamsReq", msTeamsReq)
+			common.WriteJsonResp(w, cErr, nil, http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		common.WriteJsonResp(w, nil, res, http.StatusOK)
 	}
 }
 
@@ -424,6 +470,7 @@ type ChannelResponseDTO struct {
 	WebhookConfigs []*beans.WebhookConfigDto `json:"webhookConfigs"`
 	SESConfigs     []*beans.SESConfigDto     `json:"sesConfigs"`
 	SMTPConfigs    []*beans.SMTPConfigDto    `json:"smtpConfigs"`
+	MsTeamsConfigs []*beans.MsTeamsConfigDto `json:"msTeamsConfigs"`
 }
 
 func (impl NotificationRestHandlerImpl) FindAllNotificationConfig(w http.ResponseWriter, r *http.Request) {
@@ -510,6 +557,26 @@ func (impl NotificationRestHandlerImpl) FindAllNotificationConfig(w http.Respons
 	if pass {
 		channelsResponse.SMTPConfigs = smtpConfigs
 	}
+
+	msTeamsConfigs, err := impl.msTeamsService.FetchAllMsTeamsNotificationConfig()
+	if err != nil {
+		impl.logger.Errorw("service err, FindAllNotificationConfig", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	//RBAC, ms teams configs are filtered on the project access like slack configs
+	channelsResponse.MsTeamsConfigs = make([]*beans.MsTeamsConfigDto, 0, len(msTeamsConfigs))
+	for _, item := range msTeamsConfigs {
+		team, err := impl.teamReadService.FindOne(item.TeamId)
+		if err != nil {
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return
+		}
+		if ok := impl.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionGet, fmt.Sprintf("%s/*", team.Name)); ok {
+			channelsResponse.MsTeamsConfigs = append(channelsResponse.MsTeamsConfigs, item)
+		}
+	}
+	//RBAC
 	w.Header().Set("Content-Type", "application/json")
 	common.WriteJsonResp(w, fErr, channelsResponse, http.StatusOK)
 }
@@ -619,6 +686,35 @@ func (impl NotificationRestHandlerImpl) FindWebhookConfig(w http.ResponseWriter,
 	w.Header().Set("Content-Type", "application/json")
 	common.WriteJsonResp(w, fErr, webhookConfig, http.StatusOK)
 }
+func (impl NotificationRestHandlerImpl) FindMsTeamsConfig(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.HandleUnauthorized(w, r)
+		return
+	}
+	id, err := common.ExtractIntPathParamWithContext(w, r, "id")
+	if err != nil {
+		return
+	}
+	msTeamsConfig, fErr := impl.msTeamsService.FetchMsTeamsNotificationConfigById(id)
+	if fErr != nil {
+		impl.logger.Errorw("service err, FindMsTeamsConfig, cannot find ms teams config", "err", fErr, "id", id)
+		common.WriteJsonResp(w, fErr, nil, http.StatusInternalServerError)
+		return
+	}
+	token := r.Header.Get("token")
+	team, err := impl.teamReadService.FindOne(msTeamsConfig.TeamId)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionGet, fmt.Sprintf("%s/*", team.Name)); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	common.WriteJsonResp(w, nil, msTeamsConfig, http.StatusOK)
+}
+
 func (impl NotificationRestHandlerImpl) GetWebhookVariables(w http.ResponseWriter, r *http.Request) {
 	userId, err := impl.userAuthService.GetLoggedInUser(r)
 	if userId == 0 || err != nil {
@@ -702,6 +798,23 @@ func (impl NotificationRestHandlerImpl) FindAllNotificationConfigAutocomplete(w
 			}
 		}
 
+	} else if cType == string(util.MsTeams) {
+		channelsResponseAll, err := impl.msTeamsService.FetchAllMsTeamsNotificationConfigAutocomplete()
+		if err != nil {
+			impl.logger.Errorw("service err, FindAllNotificationConfigAutocomplete", "err", err)
+			common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+			return
+		}
+		for _, item := range channelsResponseAll {
+			team, err := impl.teamReadService.FindOne(item.TeamId)
+			if err != nil {
+				common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+				return
+			}
+			if ok := impl.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionGet, fmt.Sprintf("%s/*", team.Name)); ok {
+				channelsResponse = append(channelsResponse, item)
+			}
+		}
 	} else if cType == string(util.Webhook) {
 		if ok := impl.enforcer.Enforce(token, casbin.ResourceNotification, casbin.ActionGet, "*"); !ok {
 			response.WriteResponse(http.StatusForbidden, "FORBIDDEN", w, errors.New("unauthorized"))
@@ -857,6 +970,37 @@ func (impl NotificationRestHandlerImpl) DeleteNotificationChannelConfig(w http.R
 			return
 		}
 		common.WriteJsonResp(w, nil, WEBHOOK_CONFIG_DELETE_SUCCESS_RESP, http.StatusOK)
+	} else if util.MsTeams == channelReq.Channel {
+		var deleteReq *beans.MsTeamsConfigDto
+		err = json.NewDecoder(ioutil.NopCloser(bytes.NewBuffer(data))).Decode(&deleteReq)
+		if err != nil {
+			impl.logger.Errorw("request err, DeleteNotificationChannelConfig", "err", err, "deleteReq", deleteReq)
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return
+		}
+
+		err = impl.validator.Struct(deleteReq)
+		if err != nil {
+			impl.logger.Errorw("validation err, DeleteNotificationChannelConfig", "err", err, "deleteReq", deleteReq)
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return
+		}
+
+		// RBAC enforcer applying
+		token := r.Header.Get("token")
+		if ok := impl.enforcer.Enforce(token, casbin.ResourceNotification, casbin.ActionCreate, "*"); !ok {
+			response.WriteResponse(http.StatusForbidden, "FORBIDDEN", w, errors.New("unauthorized"))
+			return
+		}
+		//RBAC enforcer Ends
+
+		cErr := impl.msTeamsService.DeleteNotificationConfig(deleteReq, userId)
+		if cErr != nil {
+			impl.logger.Errorw("service err, DeleteNotificationChannelConfig", "err", cErr, "deleteReq", deleteReq)
+			common.WriteJsonResp(w, cErr, nil, http.StatusInternalServerError)
+			return
+		}
+		common.WriteJsonResp(w, nil, MS_TEAMS_CONFIG_DELETE_SUCCESS_RESP, http.StatusOK)
 	} else if util.SES == channelReq.Channel {
 		var deleteReq *beans.SESConfigDto
 		err = json.NewDecoder(ioutil.NopCloser(bytes.NewBuffer(data))).Decode(&deleteReq)
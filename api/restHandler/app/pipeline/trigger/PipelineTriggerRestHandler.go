@@ -28,6 +28,7 @@ import (
 	bean3 "github.com/devtron-labs/devtron/pkg/deployment/trigger/devtronApps/bean"
 	"github.com/devtron-labs/devtron/pkg/eventProcessor/out"
 	bean4 "github.com/devtron-labs/devtron/pkg/eventProcessor/out/bean"
+	deploymentWindowBean "github.com/devtron-labs/devtron/pkg/policyGovernance/deploymentWindow/bean"
 
 	"github.com/devtron-labs/devtron/pkg/app"
 	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
@@ -111,6 +112,22 @@ func (handler PipelineTriggerRestHandlerImpl) validateCdTriggerRBAC(token string
 	return nil
 }
 
+func (handler PipelineTriggerRestHandlerImpl) validateDeploymentWindowOverrideRBAC(token string, overrideRequest *bean.ValuesOverrideRequest) error {
+	if !overrideRequest.DeploymentWindowOverride {
+		return nil
+	}
+	if len(overrideRequest.DeploymentWindowOverrideReason) == 0 {
+		return util2.NewApiError(http.StatusBadRequest, deploymentWindowBean.OverrideReasonRequired, deploymentWindowBean.OverrideReasonRequired)
+	}
+	// overriding deployment windows requires edit access (admin/manager) on the app and environment
+	object := handler.enforcerUtil.GetAppRBACByAppIdAndPipelineId(overrideRequest.AppId, overrideRequest.PipelineId)
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceEnvironment, casbin.ActionUpdate, object); !ok {
+		return util2.NewApiError(http.StatusForbidden, deploymentWindowBean.OverrideForbiddenMessage, "forbidden").
+			WithCode("11011")
+	}
+	return nil
+}
+
 func (handler PipelineTriggerRestHandlerImpl) OverrideConfig(w http.ResponseWriter, r *http.Request) {
 	// 1. Authentication check
 	userId, err := handler.userAuthService.GetLoggedInUser(r)
@@ -144,6 +161,10 @@ func (handler PipelineTriggerRestHandlerImpl) OverrideConfig(w http.ResponseWrit
 		common.WriteJsonResp(w, rbacErr, nil, rbacErr.(*util2.ApiError).HttpStatusCode)
 		return
 	}
+	if rbacErr := handler.validateDeploymentWindowOverrideRBAC(token, &overrideRequest); rbacErr != nil {
+		common.WriteJsonResp(w, rbacErr, nil, rbacErr.(*util2.ApiError).HttpStatusCode)
+		return
+	}
 	// 5. Service call with enhanced error handling
 	ctx := r.Context()
 	_, span := otel.Tracer("orchestrator").Start(ctx, "workflowDagExecutor.ManualCdTrigger")
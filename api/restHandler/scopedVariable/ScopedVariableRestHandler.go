@@ -42,31 +42,38 @@ type ScopedVariableRestHandler interface {
 	CreateVariables(w http.ResponseWriter, r *http.Request)
 	GetScopedVariables(w http.ResponseWriter, r *http.Request)
 	GetJsonForVariables(w http.ResponseWriter, r *http.Request)
+	GetVariableVersions(w http.ResponseWriter, r *http.Request)
+	GetVariableVersion(w http.ResponseWriter, r *http.Request)
+	DiffVariableVersions(w http.ResponseWriter, r *http.Request)
+	RollbackVariables(w http.ResponseWriter, r *http.Request)
 }
 
 type ScopedVariableRestHandlerImpl struct {
-	logger                *zap.SugaredLogger
-	userAuthService       user.UserService
-	validator             *validator.Validate
-	pipelineBuilder       pipeline.PipelineBuilder
-	enforcerUtil          rbac.EnforcerUtil
-	enforcer              casbin.Enforcer
-	scopedVariableService variables.ScopedVariableService
+	logger                 *zap.SugaredLogger
+	userAuthService        user.UserService
+	validator              *validator.Validate
+	pipelineBuilder        pipeline.PipelineBuilder
+	enforcerUtil           rbac.EnforcerUtil
+	enforcer               casbin.Enforcer
+	scopedVariableService  variables.ScopedVariableService
+	variableVersionService variables.ScopedVariableVersionService
 }
 type JsonResponse struct {
 	Manifest   *models.ScopedVariableManifest `json:"manifest"`
 	JsonSchema string                         `json:"jsonSchema"`
 }
 
-func NewScopedVariableRestHandlerImpl(logger *zap.SugaredLogger, userAuthService user.UserService, validator *validator.Validate, pipelineBuilder pipeline.PipelineBuilder, enforcerUtil rbac.EnforcerUtil, enforcer casbin.Enforcer, scopedVariableService variables.ScopedVariableService) *ScopedVariableRestHandlerImpl {
+func NewScopedVariableRestHandlerImpl(logger *zap.SugaredLogger, userAuthService user.UserService, validator *validator.Validate, pipelineBuilder pipeline.PipelineBuilder, enforcerUtil rbac.EnforcerUtil, enforcer casbin.Enforcer, scopedVariableService variables.ScopedVariableService,
+	variableVersionService variables.ScopedVariableVersionService) *ScopedVariableRestHandlerImpl {
 	return &ScopedVariableRestHandlerImpl{
-		logger:                logger,
-		userAuthService:       userAuthService,
-		validator:             validator,
-		pipelineBuilder:       pipelineBuilder,
-		enforcerUtil:          enforcerUtil,
-		enforcer:              enforcer,
-		scopedVariableService: scopedVariableService,
+		logger:                 logger,
+		userAuthService:        userAuthService,
+		validator:              validator,
+		pipelineBuilder:        pipelineBuilder,
+		enforcerUtil:           enforcerUtil,
+		enforcer:               enforcer,
+		scopedVariableService:  scopedVariableService,
+		variableVersionService: variableVersionService,
 	}
 }
 func (handler *ScopedVariableRestHandlerImpl) CreateVariables(w http.ResponseWriter, r *http.Request) {
@@ -206,3 +213,112 @@ func (handler *ScopedVariableRestHandlerImpl) GetJsonForVariables(w http.Respons
 	}
 	common.WriteJsonResp(w, nil, jsonResponse, http.StatusOK)
 }
+
+func (handler *ScopedVariableRestHandlerImpl) GetVariableVersions(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.HandleUnauthorized(w, r)
+		return
+	}
+	token := r.Header.Get("token")
+	if isSuperAdmin := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionGet, "*"); !isSuperAdmin {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	offset, err := common.ExtractIntQueryParam(w, r, "offset", 0)
+	if err != nil {
+		return
+	}
+	size, err := common.ExtractIntQueryParam(w, r, "size", 20)
+	if err != nil {
+		return
+	}
+	versions, err := handler.variableVersionService.GetVersions(offset, size)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, versions, http.StatusOK)
+}
+
+func (handler *ScopedVariableRestHandlerImpl) GetVariableVersion(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.HandleUnauthorized(w, r)
+		return
+	}
+	version, err := common.ExtractIntPathParamWithContext(w, r, "version")
+	if err != nil {
+		return
+	}
+	token := r.Header.Get("token")
+	if isSuperAdmin := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionGet, "*"); !isSuperAdmin {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	variableVersion, err := handler.variableVersionService.GetVersion(version)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, variableVersion, http.StatusOK)
+}
+
+func (handler *ScopedVariableRestHandlerImpl) DiffVariableVersions(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.HandleUnauthorized(w, r)
+		return
+	}
+	fromVersion, err := common.ExtractIntQueryParam(w, r, "from", 0)
+	if err != nil {
+		return
+	}
+	toVersion, err := common.ExtractIntQueryParam(w, r, "to", 0)
+	if err != nil {
+		return
+	}
+	if fromVersion <= 0 || toVersion <= 0 {
+		common.WriteJsonResp(w, errors.New("from and to versions are required"), nil, http.StatusBadRequest)
+		return
+	}
+	token := r.Header.Get("token")
+	if isSuperAdmin := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionGet, "*"); !isSuperAdmin {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	diff, err := handler.variableVersionService.DiffVersions(fromVersion, toVersion)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, diff, http.StatusOK)
+}
+
+func (handler *ScopedVariableRestHandlerImpl) RollbackVariables(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.HandleUnauthorized(w, r)
+		return
+	}
+	version, err := common.ExtractIntPathParamWithContext(w, r, "version")
+	if err != nil {
+		return
+	}
+	handler.logger.Infow("request payload, RollbackVariables", "version", version, "userId", userId)
+	token := r.Header.Get("token")
+	if isSuperAdmin := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionCreate, "*"); !isSuperAdmin {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	err = handler.variableVersionService.RollbackToVersion(version, userId)
+	if err != nil {
+		if errors.As(err, &models.ValidationError{}) {
+			common.WriteJsonResp(w, err, nil, http.StatusNotAcceptable)
+		} else {
+			common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		}
+		return
+	}
+	common.WriteJsonResp(w, nil, nil, http.StatusOK)
+}
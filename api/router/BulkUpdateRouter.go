@@ -49,6 +49,11 @@ func (router BulkUpdateRouterImpl) initBulkUpdateRouter(bulkRouter *mux.Router)
 func (router BulkUpdateRouterImpl) initV1beta1Router(bulkRouter *mux.Router) {
 	bulkRouter.Path("/v1beta1/application/dryrun").HandlerFunc(router.restHandler.DryRunBulkEdit).Methods("POST")
 	bulkRouter.Path("/v1beta1/application").HandlerFunc(router.restHandler.BulkEdit).Methods("POST")
+	bulkRouter.Path("/v1beta1/application/job").HandlerFunc(router.restHandler.CreateBulkEditJob).Methods("POST")
+	bulkRouter.Path("/v1beta1/application/job").HandlerFunc(router.restHandler.GetBulkEditJobs).Methods("GET")
+	bulkRouter.Path("/v1beta1/application/job/{id}").HandlerFunc(router.restHandler.GetBulkEditJob).Methods("GET")
+	bulkRouter.Path("/v1beta1/application/job/{id}").HandlerFunc(router.restHandler.CancelBulkEditJob).Methods("DELETE")
+	bulkRouter.Path("/v1beta1/application/job/{id}/rollback").HandlerFunc(router.restHandler.RollbackBulkEditJob).Methods("POST")
 
 	bulkRouter.Path("/v1beta1/hibernate").HandlerFunc(router.restHandler.BulkHibernate).Methods("POST")
 	bulkRouter.Path("/v1beta1/unhibernate").HandlerFunc(router.restHandler.BulkUnHibernate).Methods("POST")
@@ -46,5 +46,17 @@ func (impl ScopedVariableRouterImpl) InitScopedVariableRouter(router *mux.Router
 	router.Path("/variables/detail").
 		HandlerFunc(impl.scopedVariableRestHandler.GetJsonForVariables).
 		Methods("GET")
+	router.Path("/variables/versions").
+		HandlerFunc(impl.scopedVariableRestHandler.GetVariableVersions).
+		Methods("GET")
+	router.Path("/variables/versions/diff").
+		HandlerFunc(impl.scopedVariableRestHandler.DiffVariableVersions).
+		Methods("GET")
+	router.Path("/variables/versions/{version:[0-9]+}").
+		HandlerFunc(impl.scopedVariableRestHandler.GetVariableVersion).
+		Methods("GET")
+	router.Path("/variables/versions/{version:[0-9]+}/rollback").
+		HandlerFunc(impl.scopedVariableRestHandler.RollbackVariables).
+		Methods("POST")
 
 }
@@ -25,25 +25,32 @@ import (
 	"github.com/devtron-labs/devtron/api/appStore/chartGroup"
 	appStoreDeployment "github.com/devtron-labs/devtron/api/appStore/deployment"
 	"github.com/devtron-labs/devtron/api/argoApplication"
+	"github.com/devtron-labs/devtron/api/auth/accessGrant"
+	"github.com/devtron-labs/devtron/api/auth/accessReview"
 	"github.com/devtron-labs/devtron/api/auth/authorisation/globalConfig"
 	"github.com/devtron-labs/devtron/api/auth/sso"
 	"github.com/devtron-labs/devtron/api/auth/user"
+	"github.com/devtron-labs/devtron/api/celPolicy"
 	"github.com/devtron-labs/devtron/api/chartRepo"
 	"github.com/devtron-labs/devtron/api/cluster"
 	"github.com/devtron-labs/devtron/api/dashboardEvent"
 	"github.com/devtron-labs/devtron/api/deployment"
+	"github.com/devtron-labs/devtron/api/deploymentWindow"
 	"github.com/devtron-labs/devtron/api/devtronResource"
 	"github.com/devtron-labs/devtron/api/externalLink"
 	fluxApplication2 "github.com/devtron-labs/devtron/api/fluxApplication"
 	client "github.com/devtron-labs/devtron/api/helm-app"
 	"github.com/devtron-labs/devtron/api/infraConfig"
+	"github.com/devtron-labs/devtron/api/jira"
 	"github.com/devtron-labs/devtron/api/k8s/application"
 	"github.com/devtron-labs/devtron/api/k8s/capacity"
 	"github.com/devtron-labs/devtron/api/module"
+	"github.com/devtron-labs/devtron/api/releaseTrain"
 	"github.com/devtron-labs/devtron/api/resourceScan"
 	"github.com/devtron-labs/devtron/api/restHandler/common"
 	"github.com/devtron-labs/devtron/api/router/app"
 	"github.com/devtron-labs/devtron/api/router/app/configDiff"
+	"github.com/devtron-labs/devtron/api/scim"
 	"github.com/devtron-labs/devtron/api/server"
 	"github.com/devtron-labs/devtron/api/team"
 	terminal2 "github.com/devtron-labs/devtron/api/terminal"
@@ -53,6 +60,7 @@ import (
 	"github.com/devtron-labs/devtron/client/dashboard"
 	"github.com/devtron-labs/devtron/client/proxy"
 	"github.com/devtron-labs/devtron/client/telemetry"
+	"github.com/devtron-labs/devtron/pkg/overview"
 	"github.com/devtron-labs/devtron/pkg/terminal"
 	"github.com/devtron-labs/devtron/util"
 	"github.com/gorilla/mux"
@@ -114,11 +122,14 @@ type MuxRouter struct {
 	webhookHelmRouter                  webhookHelm.WebhookHelmRouter
 	globalCMCSRouter                   GlobalCMCSRouter
 	userTerminalAccessRouter           terminal2.UserTerminalAccessRouter
+	terminalSessionRecordingRouter     terminal2.TerminalSessionRecordingRouter
 	ciStatusUpdateCron                 cron.CiStatusUpdateCron
 	resourceGroupingRouter             ResourceGroupingRouter
 	rbacRoleRouter                     user.RbacRoleRouter
 	scopedVariableRouter               ScopedVariableRouter
 	ciTriggerCron                      cron.CiTriggerCron
+	tektonWorkflowStatusCron           cron.TektonWorkflowStatusCron
+	metricsExporterService             overview.MetricsExporterService
 	deploymentConfigurationRouter      configDiff.DeploymentConfigurationRouter
 	infraConfigRouter                  infraConfig.InfraConfigRouter
 	argoApplicationRouter              argoApplication.ArgoApplicationRouter
@@ -128,6 +139,13 @@ type MuxRouter struct {
 	userResourceRouter                 userResource.Router
 	overviewRouter                     OverviewRouter
 	globalAuthorisationConfigRouter    globalConfig.AuthorisationConfigRouter
+	celPolicyRouter                    celPolicy.CelPolicyRouter
+	deploymentWindowRouter             deploymentWindow.DeploymentWindowRouter
+	releaseTrainRouter                 releaseTrain.ReleaseTrainRouter
+	jiraIntegrationRouter              jira.JiraIntegrationRouter
+	scimRouter                         scim.ScimRouter
+	accessGrantRouter                  accessGrant.AccessGrantRouter
+	accessReviewRouter                 accessReview.AccessReviewRouter
 }
 
 func NewMuxRouter(logger *zap.SugaredLogger,
@@ -151,10 +169,13 @@ func NewMuxRouter(logger *zap.SugaredLogger,
 	helmApplicationStatusUpdateHandler cron.CdApplicationStatusUpdateHandler, k8sCapacityRouter capacity.K8sCapacityRouter,
 	webhookHelmRouter webhookHelm.WebhookHelmRouter, globalCMCSRouter GlobalCMCSRouter,
 	userTerminalAccessRouter terminal2.UserTerminalAccessRouter,
+	terminalSessionRecordingRouter terminal2.TerminalSessionRecordingRouter,
 	jobRouter JobRouter, ciStatusUpdateCron cron.CiStatusUpdateCron, resourceGroupingRouter ResourceGroupingRouter,
 	rbacRoleRouter user.RbacRoleRouter,
 	scopedVariableRouter ScopedVariableRouter,
 	ciTriggerCron cron.CiTriggerCron,
+	tektonWorkflowStatusCron cron.TektonWorkflowStatusCron,
+	metricsExporterService overview.MetricsExporterService,
 	proxyRouter proxy.ProxyRouter,
 	deploymentConfigurationRouter configDiff.DeploymentConfigurationRouter,
 	infraConfigRouter infraConfig.InfraConfigRouter,
@@ -165,6 +186,13 @@ func NewMuxRouter(logger *zap.SugaredLogger,
 	userResourceRouter userResource.Router,
 	overviewRouter OverviewRouter,
 	globalAuthorisationConfigRouter globalConfig.AuthorisationConfigRouter,
+	celPolicyRouter celPolicy.CelPolicyRouter,
+	deploymentWindowRouter deploymentWindow.DeploymentWindowRouter,
+	releaseTrainRouter releaseTrain.ReleaseTrainRouter,
+	jiraIntegrationRouter jira.JiraIntegrationRouter,
+	scimRouter scim.ScimRouter,
+	accessGrantRouter accessGrant.AccessGrantRouter,
+	accessReviewRouter accessReview.AccessReviewRouter,
 ) *MuxRouter {
 	r := &MuxRouter{
 		Router:                             mux.NewRouter(),
@@ -219,12 +247,15 @@ func NewMuxRouter(logger *zap.SugaredLogger,
 		webhookHelmRouter:                  webhookHelmRouter,
 		globalCMCSRouter:                   globalCMCSRouter,
 		userTerminalAccessRouter:           userTerminalAccessRouter,
+		terminalSessionRecordingRouter:     terminalSessionRecordingRouter,
 		ciStatusUpdateCron:                 ciStatusUpdateCron,
 		JobRouter:                          jobRouter,
 		resourceGroupingRouter:             resourceGroupingRouter,
 		rbacRoleRouter:                     rbacRoleRouter,
 		scopedVariableRouter:               scopedVariableRouter,
 		ciTriggerCron:                      ciTriggerCron,
+		tektonWorkflowStatusCron:           tektonWorkflowStatusCron,
+		metricsExporterService:             metricsExporterService,
 		deploymentConfigurationRouter:      deploymentConfigurationRouter,
 		infraConfigRouter:                  infraConfigRouter,
 		argoApplicationRouter:              argoApplicationRouter,
@@ -234,6 +265,13 @@ func NewMuxRouter(logger *zap.SugaredLogger,
 		userResourceRouter:                 userResourceRouter,
 		overviewRouter:                     overviewRouter,
 		globalAuthorisationConfigRouter:    globalAuthorisationConfigRouter,
+		celPolicyRouter:                    celPolicyRouter,
+		deploymentWindowRouter:             deploymentWindowRouter,
+		releaseTrainRouter:                 releaseTrainRouter,
+		jiraIntegrationRouter:              jiraIntegrationRouter,
+		scimRouter:                         scimRouter,
+		accessGrantRouter:                  accessGrantRouter,
+		accessReviewRouter:                 accessReviewRouter,
 	}
 	return r
 }
@@ -437,6 +475,9 @@ func (r MuxRouter) Init() {
 	userTerminalAccessRouter := r.Router.PathPrefix("/orchestrator/user/terminal").Subrouter()
 	r.userTerminalAccessRouter.InitTerminalAccessRouter(userTerminalAccessRouter)
 
+	terminalSessionRecordingRouter := r.Router.PathPrefix("/orchestrator/terminal/recording").Subrouter()
+	r.terminalSessionRecordingRouter.InitTerminalSessionRecordingRouter(terminalSessionRecordingRouter)
+
 	rbacRoleRouter := r.Router.PathPrefix("/orchestrator/rbac/role").Subrouter()
 	r.rbacRoleRouter.InitRbacRoleRouter(rbacRoleRouter)
 
@@ -449,6 +490,27 @@ func (r MuxRouter) Init() {
 	infraConfigRouter := r.Router.PathPrefix("/orchestrator/infra-config").Subrouter()
 	r.infraConfigRouter.InitInfraConfigRouter(infraConfigRouter)
 
+	celPolicyRouter := r.Router.PathPrefix("/orchestrator/policy/cel").Subrouter()
+	r.celPolicyRouter.InitCelPolicyRouter(celPolicyRouter)
+
+	deploymentWindowRouter := r.Router.PathPrefix("/orchestrator/deployment-window").Subrouter()
+	r.deploymentWindowRouter.InitDeploymentWindowRouter(deploymentWindowRouter)
+
+	releaseTrainRouter := r.Router.PathPrefix("/orchestrator/release-train").Subrouter()
+	r.releaseTrainRouter.InitReleaseTrainRouter(releaseTrainRouter)
+
+	jiraIntegrationRouter := r.Router.PathPrefix("/orchestrator/jira").Subrouter()
+	r.jiraIntegrationRouter.InitJiraIntegrationRouter(jiraIntegrationRouter)
+
+	scimRouter := r.Router.PathPrefix("/orchestrator/scim").Subrouter()
+	r.scimRouter.InitScimRouter(scimRouter)
+
+	accessGrantRouter := r.Router.PathPrefix("/orchestrator/access-grant").Subrouter()
+	r.accessGrantRouter.InitAccessGrantRouter(accessGrantRouter)
+
+	accessReviewRouter := r.Router.PathPrefix("/orchestrator/access-review").Subrouter()
+	r.accessReviewRouter.InitAccessReviewRouter(accessReviewRouter)
+
 	argoApplicationRouter := r.Router.PathPrefix("/orchestrator/argo-application").Subrouter()
 	r.argoApplicationRouter.InitArgoApplicationRouter(argoApplicationRouter)
 
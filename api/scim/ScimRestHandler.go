@@ -0,0 +1,454 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package scim
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/auth/scim"
+	"github.com/devtron-labs/devtron/pkg/auth/scim/bean"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type ScimRestHandler interface {
+	GetScimToken(w http.ResponseWriter, r *http.Request)
+	GenerateScimToken(w http.ResponseWriter, r *http.Request)
+	DeleteScimToken(w http.ResponseWriter, r *http.Request)
+
+	GetServiceProviderConfig(w http.ResponseWriter, r *http.Request)
+	ListUsers(w http.ResponseWriter, r *http.Request)
+	GetUser(w http.ResponseWriter, r *http.Request)
+	CreateUser(w http.ResponseWriter, r *http.Request)
+	ReplaceUser(w http.ResponseWriter, r *http.Request)
+	PatchUser(w http.ResponseWriter, r *http.Request)
+	DeleteUser(w http.ResponseWriter, r *http.Request)
+	ListGroups(w http.ResponseWriter, r *http.Request)
+	GetGroup(w http.ResponseWriter, r *http.Request)
+	CreateGroup(w http.ResponseWriter, r *http.Request)
+	ReplaceGroup(w http.ResponseWriter, r *http.Request)
+	PatchGroup(w http.ResponseWriter, r *http.Request)
+	DeleteGroup(w http.ResponseWriter, r *http.Request)
+}
+
+type ScimRestHandlerImpl struct {
+	logger      *zap.SugaredLogger
+	userService user.UserService
+	scimService scim.ScimService
+	enforcer    casbin.Enforcer
+}
+
+func NewScimRestHandlerImpl(logger *zap.SugaredLogger,
+	userService user.UserService,
+	scimService scim.ScimService,
+	enforcer casbin.Enforcer) *ScimRestHandlerImpl {
+	return &ScimRestHandlerImpl{
+		logger:      logger,
+		userService: userService,
+		scimService: scimService,
+		enforcer:    enforcer,
+	}
+}
+
+func (handler *ScimRestHandlerImpl) GetScimToken(w http.ResponseWriter, r *http.Request) {
+	if ok := handler.enforcer.Enforce(r.Header.Get("token"), casbin.ResourceGlobal, casbin.ActionGet, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	token, err := handler.scimService.GetToken()
+	if err != nil {
+		handler.logger.Errorw("service err, GetScimToken", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, token, http.StatusOK)
+}
+
+func (handler *ScimRestHandlerImpl) GenerateScimToken(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.HandleUnauthorized(w, r)
+		return
+	}
+	// the token provisions users and groups, so it is managed by super admins only
+	if ok := handler.enforcer.Enforce(r.Header.Get("token"), casbin.ResourceGlobal, casbin.ActionUpdate, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	token, err := handler.scimService.GenerateToken(userId)
+	if err != nil {
+		handler.logger.Errorw("service err, GenerateScimToken", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, token, http.StatusOK)
+}
+
+func (handler *ScimRestHandlerImpl) DeleteScimToken(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.HandleUnauthorized(w, r)
+		return
+	}
+	if ok := handler.enforcer.Enforce(r.Header.Get("token"), casbin.ResourceGlobal, casbin.ActionDelete, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	err = handler.scimService.DeleteToken(userId)
+	if err != nil {
+		handler.logger.Errorw("service err, DeleteScimToken", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, nil, http.StatusOK)
+}
+
+func (handler *ScimRestHandlerImpl) GetServiceProviderConfig(w http.ResponseWriter, r *http.Request) {
+	if !handler.authenticate(w, r) {
+		return
+	}
+	writeScimResponse(w, handler.scimService.GetServiceProviderConfig(), http.StatusOK)
+}
+
+func (handler *ScimRestHandlerImpl) ListUsers(w http.ResponseWriter, r *http.Request) {
+	if !handler.authenticate(w, r) {
+		return
+	}
+	request, err := getListRequest(r)
+	if err != nil {
+		writeScimError(w, err)
+		return
+	}
+	users, err := handler.scimService.ListUsers(request)
+	if err != nil {
+		handler.logger.Errorw("service err, ListUsers", "filter", request.Filter, "err", err)
+		writeScimError(w, err)
+		return
+	}
+	writeScimResponse(w, users, http.StatusOK)
+}
+
+func (handler *ScimRestHandlerImpl) GetUser(w http.ResponseWriter, r *http.Request) {
+	if !handler.authenticate(w, r) {
+		return
+	}
+	id, err := getResourceId(r)
+	if err != nil {
+		writeScimError(w, err)
+		return
+	}
+	scimUser, err := handler.scimService.GetUser(id)
+	if err != nil {
+		handler.logger.Errorw("service err, GetUser", "id", id, "err", err)
+		writeScimError(w, err)
+		return
+	}
+	writeScimResponse(w, scimUser, http.StatusOK)
+}
+
+func (handler *ScimRestHandlerImpl) CreateUser(w http.ResponseWriter, r *http.Request) {
+	if !handler.authenticate(w, r) {
+		return
+	}
+	scimUser := &bean.User{}
+	if err := decodeScimRequest(r, scimUser); err != nil {
+		writeScimError(w, err)
+		return
+	}
+	scimUser, err := handler.scimService.CreateUser(scimUser)
+	if err != nil {
+		handler.logger.Errorw("service err, CreateUser", "err", err)
+		writeScimError(w, err)
+		return
+	}
+	writeScimResponse(w, scimUser, http.StatusCreated)
+}
+
+func (handler *ScimRestHandlerImpl) ReplaceUser(w http.ResponseWriter, r *http.Request) {
+	if !handler.authenticate(w, r) {
+		return
+	}
+	id, err := getResourceId(r)
+	if err != nil {
+		writeScimError(w, err)
+		return
+	}
+	scimUser := &bean.User{}
+	if err = decodeScimRequest(r, scimUser); err != nil {
+		writeScimError(w, err)
+		return
+	}
+	scimUser, err = handler.scimService.ReplaceUser(id, scimUser)
+	if err != nil {
+		handler.logger.Errorw("service err, ReplaceUser", "id", id, "err", err)
+		writeScimError(w, err)
+		return
+	}
+	writeScimResponse(w, scimUser, http.StatusOK)
+}
+
+func (handler *ScimRestHandlerImpl) PatchUser(w http.ResponseWriter, r *http.Request) {
+	if !handler.authenticate(w, r) {
+		return
+	}
+	id, err := getResourceId(r)
+	if err != nil {
+		writeScimError(w, err)
+		return
+	}
+	request := &bean.PatchRequest{}
+	if err = decodeScimRequest(r, request); err != nil {
+		writeScimError(w, err)
+		return
+	}
+	scimUser, err := handler.scimService.PatchUser(id, request)
+	if err != nil {
+		handler.logger.Errorw("service err, PatchUser", "id", id, "err", err)
+		writeScimError(w, err)
+		return
+	}
+	writeScimResponse(w, scimUser, http.StatusOK)
+}
+
+func (handler *ScimRestHandlerImpl) DeleteUser(w http.ResponseWriter, r *http.Request) {
+	if !handler.authenticate(w, r) {
+		return
+	}
+	id, err := getResourceId(r)
+	if err != nil {
+		writeScimError(w, err)
+		return
+	}
+	err = handler.scimService.DeleteUser(id)
+	if err != nil {
+		handler.logger.Errorw("service err, DeleteUser", "id", id, "err", err)
+		writeScimError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (handler *ScimRestHandlerImpl) ListGroups(w http.ResponseWriter, r *http.Request) {
+	if !handler.authenticate(w, r) {
+		return
+	}
+	request, err := getListRequest(r)
+	if err != nil {
+		writeScimError(w, err)
+		return
+	}
+	groups, err := handler.scimService.ListGroups(request)
+	if err != nil {
+		handler.logger.Errorw("service err, ListGroups", "filter", request.Filter, "err", err)
+		writeScimError(w, err)
+		return
+	}
+	writeScimResponse(w, groups, http.StatusOK)
+}
+
+func (handler *ScimRestHandlerImpl) GetGroup(w http.ResponseWriter, r *http.Request) {
+	if !handler.authenticate(w, r) {
+		return
+	}
+	id, err := getResourceId(r)
+	if err != nil {
+		writeScimError(w, err)
+		return
+	}
+	group, err := handler.scimService.GetGroup(id)
+	if err != nil {
+		handler.logger.Errorw("service err, GetGroup", "id", id, "err", err)
+		writeScimError(w, err)
+		return
+	}
+	writeScimResponse(w, group, http.StatusOK)
+}
+
+func (handler *ScimRestHandlerImpl) CreateGroup(w http.ResponseWriter, r *http.Request) {
+	if !handler.authenticate(w, r) {
+		return
+	}
+	group := &bean.Group{}
+	if err := decodeScimRequest(r, group); err != nil {
+		writeScimError(w, err)
+		return
+	}
+	group, err := handler.scimService.CreateGroup(group)
+	if err != nil {
+		handler.logger.Errorw("service err, CreateGroup", "err", err)
+		writeScimError(w, err)
+		return
+	}
+	writeScimResponse(w, group, http.StatusCreated)
+}
+
+func (handler *ScimRestHandlerImpl) ReplaceGroup(w http.ResponseWriter, r *http.Request) {
+	if !handler.authenticate(w, r) {
+		return
+	}
+	id, err := getResourceId(r)
+	if err != nil {
+		writeScimError(w, err)
+		return
+	}
+	group := &bean.Group{}
+	if err = decodeScimRequest(r, group); err != nil {
+		writeScimError(w, err)
+		return
+	}
+	group, err = handler.scimService.ReplaceGroup(id, group)
+	if err != nil {
+		handler.logger.Errorw("service err, ReplaceGroup", "id", id, "err", err)
+		writeScimError(w, err)
+		return
+	}
+	writeScimResponse(w, group, http.StatusOK)
+}
+
+func (handler *ScimRestHandlerImpl) PatchGroup(w http.ResponseWriter, r *http.Request) {
+	if !handler.authenticate(w, r) {
+		return
+	}
+	id, err := getResourceId(r)
+	if err != nil {
+		writeScimError(w, err)
+		return
+	}
+	request := &bean.PatchRequest{}
+	if err = decodeScimRequest(r, request); err != nil {
+		writeScimError(w, err)
+		return
+	}
+	group, err := handler.scimService.PatchGroup(id, request)
+	if err != nil {
+		handler.logger.Errorw("service err, PatchGroup", "id", id, "err", err)
+		writeScimError(w, err)
+		return
+	}
+	writeScimResponse(w, group, http.StatusOK)
+}
+
+func (handler *ScimRestHandlerImpl) DeleteGroup(w http.ResponseWriter, r *http.Request) {
+	if !handler.authenticate(w, r) {
+		return
+	}
+	id, err := getResourceId(r)
+	if err != nil {
+		writeScimError(w, err)
+		return
+	}
+	err = handler.scimService.DeleteGroup(id)
+	if err != nil {
+		handler.logger.Errorw("service err, DeleteGroup", "id", id, "err", err)
+		writeScimError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// authenticate verifies the scim token sent as bearer token, the scim endpoints are not authenticated by the
+// devtron token of a user
+func (handler *ScimRestHandlerImpl) authenticate(w http.ResponseWriter, r *http.Request) bool {
+	token := strings.TrimSpace(r.Header.Get("Authorization"))
+	if len(token) > len("bearer ") && strings.EqualFold(token[:len("bearer ")], "bearer ") {
+		token = strings.TrimSpace(token[len("bearer "):])
+	}
+	ok, err := handler.scimService.VerifyToken(token)
+	if err != nil {
+		handler.logger.Errorw("error in verifying scim token", "err", err)
+		writeScimError(w, err)
+		return false
+	}
+	if !ok {
+		writeScimError(w, bean.NewScimError(http.StatusUnauthorized, "", "invalid scim token"))
+		return false
+	}
+	return true
+}
+
+func getResourceId(r *http.Request) (int32, error) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		return 0, bean.NewScimError(http.StatusNotFound, "", fmt.Sprintf("resource %s not found", mux.Vars(r)["id"]))
+	}
+	return int32(id), nil
+}
+
+func getListRequest(r *http.Request) (*bean.ListRequest, error) {
+	query := r.URL.Query()
+	request := &bean.ListRequest{
+		Filter:     query.Get("filter"),
+		StartIndex: 1,
+		Count:      bean.DefaultPageSize,
+	}
+	var err error
+	if startIndex := query.Get("startIndex"); len(startIndex) != 0 {
+		if request.StartIndex, err = strconv.Atoi(startIndex); err != nil {
+			return nil, bean.NewScimError(http.StatusBadRequest, bean.ScimTypeInvalidValue, "startIndex must be a number")
+		}
+	}
+	if count := query.Get("count"); len(count) != 0 {
+		if request.Count, err = strconv.Atoi(count); err != nil {
+			return nil, bean.NewScimError(http.StatusBadRequest, bean.ScimTypeInvalidValue, "count must be a number")
+		}
+	}
+	if excludedAttributes := query.Get("excludedAttributes"); len(excludedAttributes) != 0 {
+		request.ExcludedAttributes = strings.Split(excludedAttributes, ",")
+	}
+	return request, nil
+}
+
+func decodeScimRequest(r *http.Request, request interface{}) error {
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+		return bean.NewScimError(http.StatusBadRequest, bean.ScimTypeInvalidSyntax, err.Error())
+	}
+	return nil
+}
+
+func writeScimResponse(w http.ResponseWriter, response interface{}, status int) {
+	w.Header().Set("Content-Type", bean.ContentType)
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+// writeScimError writes the error as a scim error response (rfc7644 section 3.12)
+func writeScimError(w http.ResponseWriter, err error) {
+	errorResponse := &bean.ErrorResponse{
+		Schemas: []string{bean.ErrorSchema},
+		Detail:  err.Error(),
+	}
+	status := http.StatusInternalServerError
+	scimErr := &bean.ScimError{}
+	apiErr := &util.ApiError{}
+	if errors.As(err, &scimErr) {
+		status = scimErr.Status
+		errorResponse.ScimType = scimErr.ScimType
+	} else if errors.As(err, &apiErr) && apiErr.HttpStatusCode != 0 {
+		status = apiErr.HttpStatusCode
+		errorResponse.Detail = fmt.Sprint(apiErr.UserMessage)
+	}
+	errorResponse.Status = strconv.Itoa(status)
+	writeScimResponse(w, errorResponse, status)
+}
@@ -0,0 +1,100 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package scim
+
+import "github.com/gorilla/mux"
+
+type ScimRouter interface {
+	InitScimRouter(scimRouter *mux.Router)
+}
+
+type ScimRouterImpl struct {
+	scimRestHandler ScimRestHandler
+}
+
+func NewScimRouterImpl(scimRestHandler ScimRestHandler) *ScimRouterImpl {
+	return &ScimRouterImpl{
+		scimRestHandler: scimRestHandler,
+	}
+}
+
+func (impl *ScimRouterImpl) InitScimRouter(scimRouter *mux.Router) {
+	scimRouter.Path("/token").
+		HandlerFunc(impl.scimRestHandler.GetScimToken).
+		Methods("GET")
+
+	scimRouter.Path("/token").
+		HandlerFunc(impl.scimRestHandler.GenerateScimToken).
+		Methods("POST")
+
+	scimRouter.Path("/token").
+		HandlerFunc(impl.scimRestHandler.DeleteScimToken).
+		Methods("DELETE")
+
+	// scim 2.0 endpoints called by the identity provider, authenticated by the scim token
+	scimRouter.Path("/v2/ServiceProviderConfig").
+		HandlerFunc(impl.scimRestHandler.GetServiceProviderConfig).
+		Methods("GET")
+
+	scimRouter.Path("/v2/Users").
+		HandlerFunc(impl.scimRestHandler.ListUsers).
+		Methods("GET")
+
+	scimRouter.Path("/v2/Users").
+		HandlerFunc(impl.scimRestHandler.CreateUser).
+		Methods("POST")
+
+	scimRouter.Path("/v2/Users/{id}").
+		HandlerFunc(impl.scimRestHandler.GetUser).
+		Methods("GET")
+
+	scimRouter.Path("/v2/Users/{id}").
+		HandlerFunc(impl.scimRestHandler.ReplaceUser).
+		Methods("PUT")
+
+	scimRouter.Path("/v2/Users/{id}").
+		HandlerFunc(impl.scimRestHandler.PatchUser).
+		Methods("PATCH")
+
+	scimRouter.Path("/v2/Users/{id}").
+		HandlerFunc(impl.scimRestHandler.DeleteUser).
+		Methods("DELETE")
+
+	scimRouter.Path("/v2/Groups").
+		HandlerFunc(impl.scimRestHandler.ListGroups).
+		Methods("GET")
+
+	scimRouter.Path("/v2/Groups").
+		HandlerFunc(impl.scimRestHandler.CreateGroup).
+		Methods("POST")
+
+	scimRouter.Path("/v2/Groups/{id}").
+		HandlerFunc(impl.scimRestHandler.GetGroup).
+		Methods("GET")
+
+	scimRouter.Path("/v2/Groups/{id}").
+		HandlerFunc(impl.scimRestHandler.ReplaceGroup).
+		Methods("PUT")
+
+	scimRouter.Path("/v2/Groups/{id}").
+		HandlerFunc(impl.scimRestHandler.PatchGroup).
+		Methods("PATCH")
+
+	scimRouter.Path("/v2/Groups/{id}").
+		HandlerFunc(impl.scimRestHandler.DeleteGroup).
+		Methods("DELETE")
+}
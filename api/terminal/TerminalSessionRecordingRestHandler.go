@@ -0,0 +1,150 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package terminal
+
+import (
+	"errors"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	"github.com/devtron-labs/devtron/pkg/terminal/recording"
+	"github.com/devtron-labs/devtron/pkg/terminal/recording/bean"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+type TerminalSessionRecordingRestHandler interface {
+	GetRecordings(w http.ResponseWriter, r *http.Request)
+	GetRecording(w http.ResponseWriter, r *http.Request)
+	GetRecordingCast(w http.ResponseWriter, r *http.Request)
+}
+
+// TerminalSessionRecordingRestHandlerImpl serves the recordings of the terminal sessions, super admins can view all the
+// recordings while the other users can view the recordings of their own sessions only
+type TerminalSessionRecordingRestHandlerImpl struct {
+	logger                          *zap.SugaredLogger
+	terminalSessionRecordingService recording.TerminalSessionRecordingService
+	enforcer                        casbin.Enforcer
+	userService                     user.UserService
+}
+
+func NewTerminalSessionRecordingRestHandlerImpl(logger *zap.SugaredLogger,
+	terminalSessionRecordingService recording.TerminalSessionRecordingService,
+	enforcer casbin.Enforcer, userService user.UserService) *TerminalSessionRecordingRestHandlerImpl {
+	return &TerminalSessionRecordingRestHandlerImpl{
+		logger:                          logger,
+		terminalSessionRecordingService: terminalSessionRecordingService,
+		enforcer:                        enforcer,
+		userService:                     userService,
+	}
+}
+
+func (handler *TerminalSessionRecordingRestHandlerImpl) GetRecordings(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.HandleUnauthorized(w, r)
+		return
+	}
+	filterUserId, err := common.ExtractIntQueryParam(w, r, "userId", 0)
+	if err != nil {
+		return
+	}
+	clusterId, err := common.ExtractIntQueryParam(w, r, "clusterId", 0)
+	if err != nil {
+		return
+	}
+	offset, err := common.ExtractIntQueryParam(w, r, "offset", 0)
+	if err != nil {
+		return
+	}
+	size, err := common.ExtractIntQueryParam(w, r, "size", 20)
+	if err != nil {
+		return
+	}
+	filter := &bean.RecordingFilter{
+		UserId:    int32(filterUserId),
+		ClusterId: clusterId,
+		Namespace: r.URL.Query().Get("namespace"),
+		PodName:   r.URL.Query().Get("podName"),
+		Offset:    offset,
+		Size:      size,
+	}
+	if !handler.isSuperAdmin(r) {
+		filter.UserId = userId
+	}
+	recordings, err := handler.terminalSessionRecordingService.GetRecordings(filter)
+	if err != nil {
+		handler.logger.Errorw("service err, GetRecordings", "filter", filter, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, recordings, http.StatusOK)
+}
+
+func (handler *TerminalSessionRecordingRestHandlerImpl) GetRecording(w http.ResponseWriter, r *http.Request) {
+	recordingDto, ok := handler.getAuthorisedRecording(w, r)
+	if !ok {
+		return
+	}
+	common.WriteJsonResp(w, nil, recordingDto, http.StatusOK)
+}
+
+// GetRecordingCast returns the recording in asciicast v2 format, playable by asciinema compatible players
+func (handler *TerminalSessionRecordingRestHandlerImpl) GetRecordingCast(w http.ResponseWriter, r *http.Request) {
+	recordingDto, ok := handler.getAuthorisedRecording(w, r)
+	if !ok {
+		return
+	}
+	cast, err := handler.terminalSessionRecordingService.GetRecordingCast(recordingDto.Id)
+	if err != nil {
+		handler.logger.Errorw("service err, GetRecordingCast", "id", recordingDto.Id, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", bean.AsciicastContentType)
+	w.WriteHeader(http.StatusOK)
+	if _, err = w.Write([]byte(cast)); err != nil {
+		handler.logger.Errorw("error in writing terminal session recording", "id", recordingDto.Id, "err", err)
+	}
+}
+
+func (handler *TerminalSessionRecordingRestHandlerImpl) getAuthorisedRecording(w http.ResponseWriter, r *http.Request) (*bean.TerminalSessionRecordingDto, bool) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.HandleUnauthorized(w, r)
+		return nil, false
+	}
+	id, err := common.ExtractIntPathParamWithContext(w, r, "id")
+	if err != nil {
+		return nil, false
+	}
+	recordingDto, err := handler.terminalSessionRecordingService.GetRecording(id)
+	if err != nil {
+		handler.logger.Errorw("service err, GetRecording", "id", id, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return nil, false
+	}
+	if recordingDto.UserId != userId && !handler.isSuperAdmin(r) {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return nil, false
+	}
+	return recordingDto, true
+}
+
+func (handler *TerminalSessionRecordingRestHandlerImpl) isSuperAdmin(r *http.Request) bool {
+	return handler.enforcer.Enforce(r.Header.Get("token"), casbin.ResourceGlobal, casbin.ActionGet, "*")
+}
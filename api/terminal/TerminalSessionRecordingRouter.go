@@ -0,0 +1,44 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package terminal
+
+import (
+	"github.com/gorilla/mux"
+)
+
+type TerminalSessionRecordingRouter interface {
+	InitTerminalSessionRecordingRouter(recordingRouter *mux.Router)
+}
+
+type TerminalSessionRecordingRouterImpl struct {
+	terminalSessionRecordingRestHandler TerminalSessionRecordingRestHandler
+}
+
+func NewTerminalSessionRecordingRouterImpl(terminalSessionRecordingRestHandler TerminalSessionRecordingRestHandler) *TerminalSessionRecordingRouterImpl {
+	return &TerminalSessionRecordingRouterImpl{
+		terminalSessionRecordingRestHandler: terminalSessionRecordingRestHandler,
+	}
+}
+
+func (router TerminalSessionRecordingRouterImpl) InitTerminalSessionRecordingRouter(recordingRouter *mux.Router) {
+	recordingRouter.Path("").
+		HandlerFunc(router.terminalSessionRecordingRestHandler.GetRecordings).Methods("GET")
+	recordingRouter.Path("/{id:[0-9]+}").
+		HandlerFunc(router.terminalSessionRecordingRestHandler.GetRecording).Methods("GET")
+	recordingRouter.Path("/{id:[0-9]+}/cast").
+		HandlerFunc(router.terminalSessionRecordingRestHandler.GetRecordingCast).Methods("GET")
+}
@@ -19,6 +19,8 @@ package terminal
 import (
 	"github.com/devtron-labs/devtron/internal/sql/repository"
 	"github.com/devtron-labs/devtron/pkg/clusterTerminalAccess"
+	"github.com/devtron-labs/devtron/pkg/terminal/recording"
+	recordingRepository "github.com/devtron-labs/devtron/pkg/terminal/recording/repository"
 	"github.com/google/wire"
 )
 
@@ -32,4 +34,13 @@ var TerminalWireSet = wire.NewSet(
 	wire.Bind(new(clusterTerminalAccess.UserTerminalAccessService), new(*clusterTerminalAccess.UserTerminalAccessServiceImpl)),
 	repository.NewTerminalAccessRepositoryImpl,
 	wire.Bind(new(repository.TerminalAccessRepository), new(*repository.TerminalAccessRepositoryImpl)),
+
+	NewTerminalSessionRecordingRouterImpl,
+	wire.Bind(new(TerminalSessionRecordingRouter), new(*TerminalSessionRecordingRouterImpl)),
+	NewTerminalSessionRecordingRestHandlerImpl,
+	wire.Bind(new(TerminalSessionRecordingRestHandler), new(*TerminalSessionRecordingRestHandlerImpl)),
+	recording.NewTerminalSessionRecordingServiceImpl,
+	wire.Bind(new(recording.TerminalSessionRecordingService), new(*recording.TerminalSessionRecordingServiceImpl)),
+	recordingRepository.NewTerminalSessionRecordingRepositoryImpl,
+	wire.Bind(new(recordingRepository.TerminalSessionRecordingRepository), new(*recordingRepository.TerminalSessionRecordingRepositoryImpl)),
 )
@@ -25,6 +25,17 @@ const ContainerRepo ParamName = "containerRepository"
 const ContainerImage ParamName = "containerImage"
 const ContainerImageTag ParamName = "containerImageTag"
 const ImageLabels ParamName = "imageLabels"
+const CiPipelineName ParamName = "ciPipelineName"
+const GitBranch ParamName = "gitBranch"
+const GitBranches ParamName = "gitBranches"
+const ChartName ParamName = "chartName"
+const ChartVersion ParamName = "chartVersion"
+const CriticalVulnerabilityCount ParamName = "criticalVulnerabilityCount"
+const HighVulnerabilityCount ParamName = "highVulnerabilityCount"
+const MediumVulnerabilityCount ParamName = "mediumVulnerabilityCount"
+const LowVulnerabilityCount ParamName = "lowVulnerabilityCount"
+const DeploymentTemplateValues ParamName = "values"
+const TriggeredBy ParamName = "triggeredBy"
 
 type Request struct {
 	Expression         string             `json:"expression"`
@@ -0,0 +1,199 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cron
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/argoproj/argo-workflows/v3/pkg/apis/workflow/v1alpha1"
+	"github.com/caarlos0/env"
+	pubsub "github.com/devtron-labs/common-lib/pubsub-lib"
+	"github.com/devtron-labs/common-lib/utils/k8s"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig/bean/workflow/cdWorkflow"
+	"github.com/devtron-labs/devtron/pkg/cluster/adapter"
+	"github.com/devtron-labs/devtron/pkg/cluster/environment/repository"
+	"github.com/devtron-labs/devtron/pkg/pipeline/bean"
+	"github.com/devtron-labs/devtron/pkg/pipeline/executors"
+	cron2 "github.com/devtron-labs/devtron/util/cron"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+	"k8s.io/client-go/rest"
+	"strings"
+)
+
+type TektonWorkflowStatusCron interface {
+	SyncTektonWorkflowStatus()
+}
+
+// TektonWorkflowStatusCronImpl publishes the status of the tekton pipeline runs on the workflow status update topics,
+// kubewatch publishes it for the argo workflows and the system executor but does not watch the pipeline runs
+type TektonWorkflowStatusCronImpl struct {
+	logger                 *zap.SugaredLogger
+	cron                   *cron.Cron
+	ciWorkflowRepository   pipelineConfig.CiWorkflowRepository
+	cdWorkflowRepository   pipelineConfig.CdWorkflowRepository
+	envRepository          repository.EnvironmentRepository
+	tektonWorkflowExecutor executors.TektonWorkflowExecutor
+	k8sUtil                *k8s.K8sServiceImpl
+	pubSubClient           *pubsub.PubSubClientServiceImpl
+}
+
+func NewTektonWorkflowStatusCronImpl(logger *zap.SugaredLogger, cfg *TektonWorkflowStatusCronConfig,
+	ciWorkflowRepository pipelineConfig.CiWorkflowRepository, cdWorkflowRepository pipelineConfig.CdWorkflowRepository,
+	envRepository repository.EnvironmentRepository, tektonWorkflowExecutor executors.TektonWorkflowExecutor,
+	k8sUtil *k8s.K8sServiceImpl, pubSubClient *pubsub.PubSubClientServiceImpl,
+	cronLogger *cron2.CronLoggerImpl) *TektonWorkflowStatusCronImpl {
+	cron := cron.New(
+		cron.WithChain(cron.Recover(cronLogger)))
+	cron.Start()
+	impl := &TektonWorkflowStatusCronImpl{
+		logger:                 logger,
+		cron:                   cron,
+		ciWorkflowRepository:   ciWorkflowRepository,
+		cdWorkflowRepository:   cdWorkflowRepository,
+		envRepository:          envRepository,
+		tektonWorkflowExecutor: tektonWorkflowExecutor,
+		k8sUtil:                k8sUtil,
+		pubSubClient:           pubSubClient,
+	}
+
+	_, err := cron.AddFunc(fmt.Sprintf("@every %ds", cfg.TektonWorkflowStatusSyncInterval), impl.SyncTektonWorkflowStatus)
+	if err != nil {
+		logger.Errorw("error while configure cron job for tekton workflow status sync", "err", err)
+		return impl
+	}
+	return impl
+}
+
+type TektonWorkflowStatusCronConfig struct {
+	TektonWorkflowStatusSyncInterval int `env:"TEKTON_WORKFLOW_STATUS_SYNC_INTERVAL" envDefault:"30" description:"Interval in seconds at which the status of the tekton pipeline runs is synced into the ci and pre/post cd workflows"`
+}
+
+func GetTektonWorkflowStatusCronConfig() (*TektonWorkflowStatusCronConfig, error) {
+	cfg := &TektonWorkflowStatusCronConfig{}
+	err := env.Parse(cfg)
+	if err != nil {
+		fmt.Println("failed to parse tekton workflow status cron config: " + err.Error())
+		return nil, err
+	}
+	return cfg, nil
+}
+
+var tektonWorkflowActiveStatuses = []string{cdWorkflow.WorkflowStarting, string(v1alpha1.WorkflowPending), string(v1alpha1.WorkflowRunning)}
+
+// tektonWorkflowGroup is the pipeline runs listed together, the runs of a workflow type in a namespace,
+// envId is 0 for the runs in the default cluster
+type tektonWorkflowGroup struct {
+	envId        int
+	namespace    string
+	workflowType string
+}
+
+type tektonWorkflow struct {
+	id        int
+	status    string
+	podStatus string
+}
+
+// SyncTektonWorkflowStatus publishes the status of the running tekton workflows whose pipeline run status has changed
+func (impl *TektonWorkflowStatusCronImpl) SyncTektonWorkflowStatus() {
+	workflowGroups := make(map[tektonWorkflowGroup][]tektonWorkflow)
+	ciWorkflows, err := impl.ciWorkflowRepository.FindByExecutorTypeAndStatusesIn(cdWorkflow.WORKFLOW_EXECUTOR_TYPE_TEKTON, tektonWorkflowActiveStatuses)
+	if err != nil {
+		impl.logger.Errorw("error in fetching running tekton ci workflows", "err", err)
+		return
+	}
+	for _, ciWorkflow := range ciWorkflows {
+		// the environment is set only for the jobs running in the environment
+		group := tektonWorkflowGroup{envId: ciWorkflow.EnvironmentId, namespace: ciWorkflow.Namespace, workflowType: bean.CI_WORKFLOW_NAME}
+		workflowGroups[group] = append(workflowGroups[group], tektonWorkflow{id: ciWorkflow.Id, status: ciWorkflow.Status, podStatus: ciWorkflow.PodStatus})
+	}
+	runners, err := impl.cdWorkflowRepository.FindPreOrPostCdWorkflowRunnersByExecutorTypeAndStatusesIn(cdWorkflow.WORKFLOW_EXECUTOR_TYPE_TEKTON, tektonWorkflowActiveStatuses)
+	if err != nil {
+		impl.logger.Errorw("error in fetching running tekton cd workflow runners", "err", err)
+		return
+	}
+	for _, runner := range runners {
+		group := tektonWorkflowGroup{namespace: runner.Namespace, workflowType: bean.CD_WORKFLOW_NAME}
+		if runner.CdWorkflow != nil && runner.CdWorkflow.Pipeline != nil && runner.CdWorkflow.Pipeline.IsStageRunInEnv(runner.WorkflowType) {
+			group.envId = runner.CdWorkflow.Pipeline.EnvironmentId
+		}
+		workflowGroups[group] = append(workflowGroups[group], tektonWorkflow{id: runner.Id, status: runner.Status, podStatus: runner.PodStatus})
+	}
+	restConfigs := make(map[int]*rest.Config)
+	for group, workflows := range workflowGroups {
+		restConfig, ok := restConfigs[group.envId]
+		if !ok {
+			restConfig, err = impl.getRestConfig(group.envId)
+			if err != nil {
+				impl.logger.Errorw("error in getting rest config for tekton workflows", "envId", group.envId, "err", err)
+				continue
+			}
+			restConfigs[group.envId] = restConfig
+		}
+		impl.syncWorkflowGroupStatus(group, workflows, restConfig)
+	}
+}
+
+func (impl *TektonWorkflowStatusCronImpl) syncWorkflowGroupStatus(group tektonWorkflowGroup, workflows []tektonWorkflow, restConfig *rest.Config) {
+	workflowStatuses, err := impl.tektonWorkflowExecutor.ListWorkflowStatus(group.workflowType, group.namespace, restConfig)
+	if err != nil {
+		impl.logger.Errorw("error in listing tekton workflow status", "envId", group.envId, "namespace", group.namespace, "workflowType", group.workflowType, "err", err)
+		return
+	}
+	topic := pubsub.WORKFLOW_STATUS_UPDATE_TOPIC
+	if group.workflowType == bean.CD_WORKFLOW_NAME {
+		topic = pubsub.CD_WORKFLOW_STATUS_UPDATE
+	}
+	for _, workflow := range workflows {
+		// the pipeline run is generated from the name prefix of the workflow which starts with its id
+		namePrefix := fmt.Sprintf("%d-", workflow.id)
+		for name, workflowStatus := range workflowStatuses {
+			if !strings.HasPrefix(name, namePrefix) {
+				continue
+			}
+			phase := string(workflowStatus.Phase)
+			if phase == workflow.status && phase == workflow.podStatus {
+				break
+			}
+			data, err := json.Marshal(workflowStatus)
+			if err != nil {
+				impl.logger.Errorw("error in marshalling tekton workflow status", "workflowId", workflow.id, "err", err)
+				break
+			}
+			err = impl.pubSubClient.Publish(topic, string(data))
+			if err != nil {
+				impl.logger.Errorw("error in publishing tekton workflow status", "workflowId", workflow.id, "topic", topic, "err", err)
+			}
+			break
+		}
+	}
+}
+
+func (impl *TektonWorkflowStatusCronImpl) getRestConfig(envId int) (*rest.Config, error) {
+	if envId == 0 {
+		return impl.k8sUtil.GetK8sInClusterRestConfig()
+	}
+	env, err := impl.envRepository.FindById(envId)
+	if err != nil {
+		impl.logger.Errorw("could not fetch stage env", "envId", envId, "err", err)
+		return nil, err
+	}
+	clusterBean := adapter.GetClusterBean(*env.Cluster)
+	return impl.k8sUtil.GetRestConfigByCluster(clusterBean.GetClusterConfig())
+}
@@ -23,6 +23,7 @@ import (
 	"errors"
 	"fmt"
 	"github.com/caarlos0/env"
+	"github.com/devtron-labs/common-lib/async"
 	pubsub "github.com/devtron-labs/common-lib/pubsub-lib"
 	"github.com/devtron-labs/devtron/api/bean"
 	"github.com/devtron-labs/devtron/internal/sql/repository"
@@ -31,14 +32,17 @@ import (
 	buildBean "github.com/devtron-labs/devtron/pkg/build/pipeline/bean"
 	"github.com/devtron-labs/devtron/pkg/module"
 	bean3 "github.com/devtron-labs/devtron/pkg/module/bean"
+	cron2 "github.com/devtron-labs/devtron/util/cron"
 	util "github.com/devtron-labs/devtron/util/event"
+	"github.com/robfig/cron/v3"
 	"go.uber.org/zap"
 	"net/http"
 )
 
 type EventClientConfig struct {
-	DestinationURL     string             `env:"EVENT_URL" envDefault:"http://localhost:3000/notify" description:"Notifier service url"`
-	NotificationMedium NotificationMedium `env:"NOTIFICATION_MEDIUM" envDefault:"rest" description:"notification medium"`
+	DestinationURL         string             `env:"EVENT_URL" envDefault:"http://localhost:3000/notify" description:"Notifier service url"`
+	NotificationMedium     NotificationMedium `env:"NOTIFICATION_MEDIUM" envDefault:"rest" description:"notification medium"`
+	NotificationDigestCron string             `env:"NOTIFICATION_DIGEST_CRON" envDefault:"* * * * *" description:"Cron schedule to send the digests of the notification settings in digest mode or in quiet hours"`
 }
 type NotificationMedium string
 
@@ -94,28 +98,47 @@ type Payload struct {
 	BuildHistoryLink      string                         `json:"buildHistoryLink"`
 	MaterialTriggerInfo   *buildBean.MaterialTriggerInfo `json:"material"`
 	FailureReason         string                         `json:"failureReason"`
+	Digest                *DigestSummary                 `json:"digest,omitempty"`
 }
 
 type EventRESTClientImpl struct {
-	logger                         *zap.SugaredLogger
-	client                         *http.Client
-	config                         *EventClientConfig
-	pubsubClient                   *pubsub.PubSubClientServiceImpl
-	ciPipelineRepository           pipelineConfig.CiPipelineRepository
-	pipelineRepository             pipelineConfig.PipelineRepository
-	attributesRepository           repository.AttributesRepository
-	moduleService                  module.ModuleService
-	notificationSettingsRepository repository.NotificationSettingsRepository
+	logger                            *zap.SugaredLogger
+	client                            *http.Client
+	config                            *EventClientConfig
+	pubsubClient                      *pubsub.PubSubClientServiceImpl
+	ciPipelineRepository              pipelineConfig.CiPipelineRepository
+	pipelineRepository                pipelineConfig.PipelineRepository
+	attributesRepository              repository.AttributesRepository
+	moduleService                     module.ModuleService
+	notificationSettingsRepository    repository.NotificationSettingsRepository
+	msTeamsRepository                 repository.MsTeamsNotificationRepository
+	notificationDigestEventRepository repository.NotificationDigestEventRepository
+	cron                              *cron.Cron
+	asyncRunnable                     *async.Runnable
 }
 
 func NewEventRESTClientImpl(logger *zap.SugaredLogger, client *http.Client, config *EventClientConfig, pubsubClient *pubsub.PubSubClientServiceImpl,
 	ciPipelineRepository pipelineConfig.CiPipelineRepository, pipelineRepository pipelineConfig.PipelineRepository,
 	attributesRepository repository.AttributesRepository, moduleService module.ModuleService,
-	notificationSettingsRepository repository.NotificationSettingsRepository) *EventRESTClientImpl {
-	return &EventRESTClientImpl{logger: logger, client: client, config: config, pubsubClient: pubsubClient,
+	notificationSettingsRepository repository.NotificationSettingsRepository,
+	msTeamsRepository repository.MsTeamsNotificationRepository,
+	notificationDigestEventRepository repository.NotificationDigestEventRepository,
+	cronLogger *cron2.CronLoggerImpl, asyncRunnable *async.Runnable) *EventRESTClientImpl {
+	cron := cron.New(
+		cron.WithChain(cron.Recover(cronLogger)))
+	cron.Start()
+	impl := &EventRESTClientImpl{logger: logger, client: client, config: config, pubsubClient: pubsubClient,
 		ciPipelineRepository: ciPipelineRepository, pipelineRepository: pipelineRepository,
 		attributesRepository: attributesRepository, moduleService: moduleService,
-		notificationSettingsRepository: notificationSettingsRepository}
+		notificationSettingsRepository: notificationSettingsRepository, msTeamsRepository: msTeamsRepository,
+		notificationDigestEventRepository: notificationDigestEventRepository, cron: cron, asyncRunnable: asyncRunnable}
+	// execute periodically, send the digests of the held back notification events
+	_, err := cron.AddFunc(config.NotificationDigestCron, impl.SendNotificationDigests)
+	if err != nil {
+		logger.Errorw("error while configure cron job for notification digest", "err", err)
+		return impl
+	}
+	return impl
 }
 
 func (impl *EventRESTClientImpl) buildFinalPayload(event Event, cdPipeline *pipelineConfig.Pipeline, ciPipeline *pipelineConfig.CiPipeline) *Payload {
@@ -242,16 +265,19 @@ func (impl *EventRESTClientImpl) sendEvent(event Event) (bool, error) {
 	impl.logger.Debugw("event before send", "event", event)
 
 	// Step 1: Create payload and destination URL based on config
-	bodyBytes, destinationUrl, err := impl.createV2PayloadAndDestination(event)
+	bodyBytes, destinationUrl, msTeamsConfigIds, err := impl.createV2PayloadAndDestination(event)
 	if err != nil {
 		return false, err
 	}
 
+	// ms teams adaptive cards are posted by the orchestrator, the notifier does not support this channel
+	impl.sendMsTeamsNotifications(event, msTeamsConfigIds)
+
 	// Step 2: Send via appropriate medium (NATS or REST)
 	return impl.deliverEvent(bodyBytes, destinationUrl)
 }
 
-func (impl *EventRESTClientImpl) createV2PayloadAndDestination(event Event) ([]byte, string, error) {
+func (impl *EventRESTClientImpl) createV2PayloadAndDestination(event Event) ([]byte, string, []int, error) {
 	destinationUrl := impl.config.DestinationURL + "/v2"
 
 	// Fetch notification settings
@@ -270,14 +296,20 @@ func (impl *EventRESTClientImpl) createV2PayloadAndDestination(event Event) ([]b
 	)
 	if err != nil {
 		impl.logger.Errorw("error while fetching notification settings", "err", err)
-		return nil, "", err
+		return nil, "", nil, err
 	}
 
 	// Process notification settings into beans
 	notificationSettingsBean, err := impl.processNotificationSettings(notificationSettings)
 	if err != nil {
-		return nil, "", err
+		return nil, "", nil, err
 	}
+	notificationSettingsBean, err = impl.holdBackDigestEvents(event, notificationSettings, notificationSettingsBean)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	msTeamsConfigIds := splitMsTeamsConfigs(notificationSettingsBean)
 
 	// Create combined payload
 	combinedPayload := map[string]interface{}{
@@ -288,10 +320,10 @@ func (impl *EventRESTClientImpl) createV2PayloadAndDestination(event Event) ([]b
 	bodyBytes, err := json.Marshal(combinedPayload)
 	if err != nil {
 		impl.logger.Errorw("error while marshaling combined event request", "err", err)
-		return nil, "", err
+		return nil, "", nil, err
 	}
 
-	return bodyBytes, destinationUrl, nil
+	return bodyBytes, destinationUrl, msTeamsConfigIds, nil
 }
 
 func (impl *EventRESTClientImpl) processNotificationSettings(notificationSettings []repository.NotificationSettings) ([]*repository.NotificationSettingsBean, error) {
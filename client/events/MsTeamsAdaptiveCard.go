@@ -0,0 +1,266 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	util "github.com/devtron-labs/devtron/util/event"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	adaptiveCardContentType = "application/vnd.microsoft.card.adaptive"
+	adaptiveCardSchema      = "http://adaptivecards.io/schemas/adaptive-card.json"
+	adaptiveCardVersion     = "1.4"
+
+	msTeamsMaxAttempts   = 3
+	msTeamsRetryInterval = 2 * time.Second
+)
+
+// MsTeamsMessage is the payload accepted by the MS Teams incoming webhooks and workflows
+type MsTeamsMessage struct {
+	Type        string                   `json:"type"`
+	Attachments []*MsTeamsCardAttachment `json:"attachments"`
+}
+
+type MsTeamsCardAttachment struct {
+	ContentType string        `json:"contentType"`
+	Content     *AdaptiveCard `json:"content"`
+}
+
+type AdaptiveCard struct {
+	Schema  string                 `json:"$schema"`
+	Type    string                 `json:"type"`
+	Version string                 `json:"version"`
+	Body    []*AdaptiveCardElement `json:"body"`
+	Actions []*AdaptiveCardAction  `json:"actions,omitempty"`
+}
+
+// AdaptiveCardElement covers the TextBlock and FactSet elements used in the notification cards
+type AdaptiveCardElement struct {
+	Type   string              `json:"type"`
+	Text   string              `json:"text,omitempty"`
+	Size   string              `json:"size,omitempty"`
+	Weight string              `json:"weight,omitempty"`
+	Color  string              `json:"color,omitempty"`
+	Wrap   bool                `json:"wrap,omitempty"`
+	Facts  []*AdaptiveCardFact `json:"facts,omitempty"`
+}
+
+type AdaptiveCardFact struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+}
+
+type AdaptiveCardAction struct {
+	Type  string `json:"type"`
+	Title string `json:"title"`
+	Url   string `json:"url"`
+}
+
+// BuildMsTeamsMessage builds the adaptive card message for trigger, success, fail and approval events
+func BuildMsTeamsMessage(event Event) *MsTeamsMessage {
+	payload := event.Payload
+	if payload == nil {
+		payload = &Payload{}
+	}
+	if payload.Digest != nil {
+		return buildMsTeamsDigestMessage(payload.Digest)
+	}
+	title, color := getMsTeamsCardTitleAndColor(event)
+	body := []*AdaptiveCardElement{
+		{Type: "TextBlock", Text: title, Size: "Large", Weight: "Bolder", Color: color, Wrap: true},
+	}
+	facts := make([]*AdaptiveCardFact, 0)
+	addFact := func(title, value string) {
+		if len(value) > 0 {
+			facts = append(facts, &AdaptiveCardFact{Title: title, Value: value})
+		}
+	}
+	addFact("Application", payload.AppName)
+	addFact("Environment", payload.EnvName)
+	addFact("Pipeline", payload.PipelineName)
+	addFact("Stage", payload.Stage)
+	addFact("Triggered by", payload.TriggeredBy)
+	addFact("Image", payload.DockerImageUrl)
+	addFact("Time", event.EventTime)
+	body = append(body, &AdaptiveCardElement{Type: "FactSet", Facts: facts})
+	if event.EventTypeId == int(util.Fail) && len(payload.FailureReason) > 0 {
+		body = append(body, &AdaptiveCardElement{Type: "TextBlock", Text: payload.FailureReason, Color: "Attention", Wrap: true})
+	}
+
+	actions := make([]*AdaptiveCardAction, 0)
+	addAction := func(title, link string) {
+		if len(link) > 0 && len(event.BaseUrl) > 0 {
+			actions = append(actions, &AdaptiveCardAction{Type: "Action.OpenUrl", Title: title, Url: strings.TrimSuffix(event.BaseUrl, "/") + link})
+		}
+	}
+	if event.PipelineType == string(util.CI) {
+		addAction("View Build", payload.BuildHistoryLink)
+	} else {
+		addAction("View Deployment", payload.DeploymentHistoryLink)
+		addAction("App Details", payload.AppDetailLink)
+	}
+
+	return newMsTeamsMessage(body, actions)
+}
+
+// buildMsTeamsDigestMessage lists the digest items, one line per item with the link to the build/deployment history
+func buildMsTeamsDigestMessage(digest *DigestSummary) *MsTeamsMessage {
+	body := []*AdaptiveCardElement{
+		{Type: "TextBlock", Text: "Pipeline notifications digest", Size: "Large", Weight: "Bolder", Wrap: true},
+		{Type: "FactSet", Facts: []*AdaptiveCardFact{
+			{Title: "Triggered", Value: fmt.Sprint(digest.Triggered)},
+			{Title: "Succeeded", Value: fmt.Sprint(digest.Succeeded)},
+			{Title: "Failed", Value: fmt.Sprint(digest.Failed)},
+			{Title: "Period", Value: fmt.Sprintf("%s - %s", digest.From, digest.To)},
+		}},
+	}
+	for _, item := range digest.Items {
+		text := item.AppName
+		if len(item.EnvName) > 0 {
+			text = fmt.Sprintf("%s / %s", text, item.EnvName)
+		}
+		text = fmt.Sprintf("%s (%s): %s", text, item.PipelineName, item.Status)
+		if item.Count > 1 {
+			text = fmt.Sprintf("%s x%d", text, item.Count)
+		}
+		if len(item.Link) > 0 {
+			text = fmt.Sprintf("%s [view](%s)", text, item.Link)
+		}
+		color := "Default"
+		if item.EventTypeId == int(util.Fail) {
+			color = "Attention"
+		}
+		body = append(body, &AdaptiveCardElement{Type: "TextBlock", Text: text, Color: color, Wrap: true})
+	}
+	return newMsTeamsMessage(body, nil)
+}
+
+func newMsTeamsMessage(body []*AdaptiveCardElement, actions []*AdaptiveCardAction) *MsTeamsMessage {
+	return &MsTeamsMessage{
+		Type: "message",
+		Attachments: []*MsTeamsCardAttachment{
+			{
+				ContentType: adaptiveCardContentType,
+				Content: &AdaptiveCard{
+					Schema:  adaptiveCardSchema,
+					Type:    "AdaptiveCard",
+					Version: adaptiveCardVersion,
+					Body:    body,
+					Actions: actions,
+				},
+			},
+		},
+	}
+}
+
+func getMsTeamsCardTitleAndColor(event Event) (string, string) {
+	pipelineType := "Deployment"
+	if event.PipelineType == string(util.CI) {
+		pipelineType = "Build"
+	}
+	switch util.EventType(event.EventTypeId) {
+	case util.Trigger:
+		return fmt.Sprintf("%s pipeline triggered", pipelineType), "Accent"
+	case util.Success:
+		return fmt.Sprintf("%s pipeline succeeded", pipelineType), "Good"
+	case util.Fail:
+		return fmt.Sprintf("%s pipeline failed", pipelineType), "Attention"
+	case util.Approval:
+		return "Deployment approval requested", "Warning"
+	default:
+		return fmt.Sprintf("%s pipeline event", pipelineType), "Default"
+	}
+}
+
+// splitMsTeamsConfigs removes the ms teams entries from the notification settings, ms teams messages are sent by the
+// orchestrator itself and not by the notifier. Returns the ms teams config ids used in the settings.
+func splitMsTeamsConfigs(notificationSettingsBean []*repository.NotificationSettingsBean) []int {
+	msTeamsConfigIds := make([]int, 0)
+	seen := make(map[int]bool)
+	for _, setting := range notificationSettingsBean {
+		configs := make([]repository.ConfigEntry, 0, len(setting.Config))
+		for _, config := range setting.Config {
+			if config.Dest != string(util.MsTeams) {
+				configs = append(configs, config)
+				continue
+			}
+			if !seen[config.ConfigId] {
+				seen[config.ConfigId] = true
+				msTeamsConfigIds = append(msTeamsConfigIds, config.ConfigId)
+			}
+		}
+		setting.Config = configs
+	}
+	return msTeamsConfigIds
+}
+
+// sendMsTeamsNotifications posts the adaptive card of the event to the ms teams webhooks in the background, the
+// caller is not blocked by slow or failing webhooks
+func (impl *EventRESTClientImpl) sendMsTeamsNotifications(event Event, msTeamsConfigIds []int) {
+	if len(msTeamsConfigIds) == 0 {
+		return
+	}
+	body, err := json.Marshal(BuildMsTeamsMessage(event))
+	if err != nil {
+		impl.logger.Errorw("error while marshaling ms teams message", "err", err)
+		return
+	}
+	impl.asyncRunnable.Execute(func() {
+		msTeamsConfigs, err := impl.msTeamsRepository.FindByIdsIn(msTeamsConfigIds)
+		if err != nil {
+			impl.logger.Errorw("error while fetching ms teams configs", "ids", msTeamsConfigIds, "err", err)
+			return
+		}
+		for _, msTeamsConfig := range msTeamsConfigs {
+			err = impl.postMsTeamsMessage(msTeamsConfig.WebHookUrl, body)
+			if err != nil {
+				impl.logger.Errorw("error while sending ms teams notification", "configId", msTeamsConfig.Id, "err", err)
+			}
+		}
+	})
+}
+
+// postMsTeamsMessage posts the message to the webhook, retrying on connection errors, throttling and server errors
+func (impl *EventRESTClientImpl) postMsTeamsMessage(webHookUrl string, body []byte) error {
+	var err error
+	for attempt := 1; attempt <= msTeamsMaxAttempts; attempt++ {
+		if attempt > 1 {
+			time.Sleep(time.Duration(attempt-1) * msTeamsRetryInterval)
+		}
+		var resp *http.Response
+		resp, err = impl.client.Post(webHookUrl, "application/json", bytes.NewBuffer(body))
+		if err != nil {
+			continue
+		}
+		resp.Body.Close()
+		if resp.StatusCode < http.StatusMultipleChoices {
+			return nil
+		}
+		err = fmt.Errorf("unexpected response code: %d", resp.StatusCode)
+		if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode < http.StatusInternalServerError {
+			return err
+		}
+	}
+	return err
+}
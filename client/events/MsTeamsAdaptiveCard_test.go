@@ -0,0 +1,76 @@
+package client
+
+import (
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	util "github.com/devtron-labs/devtron/util/event"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestBuildMsTeamsMessage(t *testing.T) {
+	event := Event{
+		EventTypeId:  int(util.Fail),
+		PipelineType: string(util.CD),
+		BaseUrl:      "https://devtron.example.com/",
+		Payload: &Payload{
+			AppName:               "payments",
+			EnvName:               "prod",
+			TriggeredBy:           "admin",
+			FailureReason:         "image pull back off",
+			DeploymentHistoryLink: "/dashboard/app/1/cd-details/2/3/4/source-code",
+			AppDetailLink:         "/dashboard/app/1/details/2/pod",
+		},
+	}
+	message := BuildMsTeamsMessage(event)
+	assert.Equal(t, "message", message.Type)
+	assert.Len(t, message.Attachments, 1)
+	card := message.Attachments[0].Content
+	assert.Equal(t, adaptiveCardContentType, message.Attachments[0].ContentType)
+	assert.Equal(t, "Deployment pipeline failed", card.Body[0].Text)
+	assert.Equal(t, []*AdaptiveCardFact{{Title: "Application", Value: "payments"}, {Title: "Environment", Value: "prod"},
+		{Title: "Triggered by", Value: "admin"}}, card.Body[1].Facts)
+	assert.Equal(t, "image pull back off", card.Body[2].Text)
+	assert.Len(t, card.Actions, 2)
+	assert.Equal(t, "https://devtron.example.com/dashboard/app/1/cd-details/2/3/4/source-code", card.Actions[0].Url)
+
+	event.EventTypeId = int(util.Approval)
+	event.BaseUrl = ""
+	message = BuildMsTeamsMessage(event)
+	card = message.Attachments[0].Content
+	assert.Equal(t, "Deployment approval requested", card.Body[0].Text)
+	assert.Len(t, card.Body, 2)
+	assert.Empty(t, card.Actions)
+}
+
+func TestSplitMsTeamsConfigs(t *testing.T) {
+	settings := []*repository.NotificationSettingsBean{
+		{Id: 1, Config: []repository.ConfigEntry{{Dest: "slack", ConfigId: 1}, {Dest: "msteams", ConfigId: 2}}},
+		{Id: 2, Config: []repository.ConfigEntry{{Dest: "msteams", ConfigId: 2}, {Dest: "msteams", ConfigId: 3}}},
+	}
+	ids := splitMsTeamsConfigs(settings)
+	assert.Equal(t, []int{2, 3}, ids)
+	assert.Equal(t, []repository.ConfigEntry{{Dest: "slack", ConfigId: 1}}, settings[0].Config)
+	assert.Empty(t, settings[1].Config)
+}
+
+func TestPostMsTeamsMessage(t *testing.T) {
+	statuses := []int{http.StatusServiceUnavailable, http.StatusOK}
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(statuses[requests])
+		requests++
+	}))
+	defer server.Close()
+	impl := &EventRESTClientImpl{client: server.Client()}
+
+	// server errors are retried
+	assert.NoError(t, impl.postMsTeamsMessage(server.URL, []byte("{}")))
+	assert.Equal(t, 2, requests)
+
+	// client errors are not
+	statuses, requests = []int{http.StatusBadRequest}, 0
+	assert.Error(t, impl.postMsTeamsMessage(server.URL, []byte("{}")))
+	assert.Equal(t, 1, requests)
+}
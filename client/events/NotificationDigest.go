@@ -0,0 +1,347 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	eventBean "github.com/devtron-labs/devtron/client/events/bean"
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	"github.com/devtron-labs/devtron/pkg/bean"
+	util "github.com/devtron-labs/devtron/util/event"
+	"strings"
+	"time"
+)
+
+// dispatchedDigestEventsRetention is the duration for which the dispatched digest events are kept
+const dispatchedDigestEventsRetention = 7 * 24 * time.Hour
+
+const DigestEventName = "DIGEST"
+
+// DigestSummary is the payload of a digest event, it aggregates the events held back for a channel of a notification setting
+type DigestSummary struct {
+	Title       string        `json:"title"`
+	From        string        `json:"from"`
+	To          string        `json:"to"`
+	TotalEvents int           `json:"totalEvents"`
+	Triggered   int           `json:"triggered"`
+	Succeeded   int           `json:"succeeded"`
+	Failed      int           `json:"failed"`
+	Items       []*DigestItem `json:"items"`
+}
+
+type DigestItem struct {
+	AppName       string `json:"appName"`
+	EnvName       string `json:"envName"`
+	PipelineName  string `json:"pipelineName"`
+	PipelineType  string `json:"pipelineType"`
+	EventTypeId   int    `json:"eventTypeId"`
+	Status        string `json:"status"`
+	Count         int    `json:"count"`
+	LastEventTime string `json:"lastEventTime"`
+	FailureReason string `json:"failureReason,omitempty"`
+	Link          string `json:"link,omitempty"`
+}
+
+// isDigestSupported only the pipeline trigger, success and fail events are aggregated, the other events
+// (e.g. approval requests) need an action and are always sent right away
+func isDigestSupported(eventTypeId int) bool {
+	switch util.EventType(eventTypeId) {
+	case util.Trigger, util.Success, util.Fail:
+		return true
+	}
+	return false
+}
+
+// holdBackDigestEvents saves the channels of the notification settings which are in digest mode or in quiet hours
+// as digest events and returns the notification settings with the rest of the channels, to be notified right away
+func (impl *EventRESTClientImpl) holdBackDigestEvents(event Event, notificationSettings []repository.NotificationSettings,
+	notificationSettingsBean []*repository.NotificationSettingsBean) ([]*repository.NotificationSettingsBean, error) {
+	if !isDigestSupported(event.EventTypeId) {
+		return notificationSettingsBean, nil
+	}
+	digestConfigs := make(map[int]*eventBean.NotificationDigestConfig)
+	for _, setting := range notificationSettings {
+		if len(setting.DigestConfig) == 0 {
+			continue
+		}
+		digestConfig := &eventBean.NotificationDigestConfig{}
+		if err := json.Unmarshal([]byte(setting.DigestConfig), digestConfig); err != nil {
+			impl.logger.Errorw("error while unmarshalling digest config, sending notification right away", "notificationSettingId", setting.Id, "err", err)
+			continue
+		}
+		digestConfigs[setting.Id] = digestConfig
+	}
+	if len(digestConfigs) == 0 {
+		return notificationSettingsBean, nil
+	}
+	eventJson, err := json.Marshal(event)
+	if err != nil {
+		impl.logger.Errorw("error while marshaling event for digest", "err", err)
+		return nil, err
+	}
+	now := time.Now()
+	digestEvents := make([]*repository.NotificationDigestEvent, 0)
+	immediateSettingsBean := make([]*repository.NotificationSettingsBean, 0, len(notificationSettingsBean))
+	for _, setting := range notificationSettingsBean {
+		digestConfig, ok := digestConfigs[setting.Id]
+		if !ok {
+			immediateSettingsBean = append(immediateSettingsBean, setting)
+			continue
+		}
+		digestConfigJson, err := json.Marshal(digestConfig)
+		if err != nil {
+			return nil, err
+		}
+		heldBack := make(map[string][]repository.ConfigEntry)
+		configs := make([]repository.ConfigEntry, 0, len(setting.Config))
+		for _, config := range setting.Config {
+			if digestConfig.IsBufferingRequired(util.Channel(config.Dest), now) {
+				heldBack[config.Dest] = append(heldBack[config.Dest], config)
+			} else {
+				configs = append(configs, config)
+			}
+		}
+		for channel, channelConfigs := range heldBack {
+			channelConfigJson, err := json.Marshal(channelConfigs)
+			if err != nil {
+				return nil, err
+			}
+			digestEvents = append(digestEvents, &repository.NotificationDigestEvent{
+				NotificationSettingId: setting.Id,
+				Channel:               channel,
+				Config:                string(channelConfigJson),
+				DigestConfig:          string(digestConfigJson),
+				EventTypeId:           event.EventTypeId,
+				PipelineType:          event.PipelineType,
+				PipelineId:            event.PipelineId,
+				Event:                 string(eventJson),
+				CreatedOn:             now,
+			})
+		}
+		// copy the setting, the fetched settings are not modified
+		immediateSetting := *setting
+		immediateSetting.Config = configs
+		immediateSettingsBean = append(immediateSettingsBean, &immediateSetting)
+	}
+	err = impl.notificationDigestEventRepository.SaveAll(digestEvents)
+	if err != nil {
+		impl.logger.Errorw("error while saving digest events", "err", err)
+		return nil, err
+	}
+	return immediateSettingsBean, nil
+}
+
+// SendNotificationDigests sends the digests of the held back events whose window has elapsed and whose channel is
+// not in quiet hours, executed periodically
+func (impl *EventRESTClientImpl) SendNotificationDigests() {
+	pendingEvents, err := impl.notificationDigestEventRepository.FindAllPending()
+	if err != nil {
+		impl.logger.Errorw("error while fetching pending digest events", "err", err)
+		return
+	}
+	now := time.Now()
+	for _, group := range groupDigestEvents(pendingEvents) {
+		// latest digest config of the setting is honoured
+		digestConfig := &eventBean.NotificationDigestConfig{}
+		if err = json.Unmarshal([]byte(group[len(group)-1].DigestConfig), digestConfig); err != nil {
+			impl.logger.Errorw("error while unmarshalling digest config", "notificationSettingId", group[0].NotificationSettingId, "err", err)
+			continue
+		}
+		if !isDigestDue(group, digestConfig, now) {
+			continue
+		}
+		ids := make([]int, 0, len(group))
+		for _, digestEvent := range group {
+			ids = append(ids, digestEvent.Id)
+		}
+		claimedEvents, err := impl.notificationDigestEventRepository.MarkDispatched(ids)
+		if err != nil {
+			impl.logger.Errorw("error while marking digest events dispatched", "ids", ids, "err", err)
+			continue
+		}
+		if len(claimedEvents) == 0 {
+			// already sent by another replica
+			continue
+		}
+		err = impl.sendDigest(claimedEvents, digestConfig)
+		if err != nil {
+			impl.logger.Errorw("error while sending notification digest", "notificationSettingId", group[0].NotificationSettingId, "channel", group[0].Channel, "err", err)
+		}
+	}
+	err = impl.notificationDigestEventRepository.DeleteDispatchedBefore(now.Add(-dispatchedDigestEventsRetention))
+	if err != nil {
+		impl.logger.Errorw("error while deleting dispatched digest events", "err", err)
+	}
+}
+
+// groupDigestEvents groups the events by notification setting and channel, preserving the order of the events
+func groupDigestEvents(digestEvents []*repository.NotificationDigestEvent) [][]*repository.NotificationDigestEvent {
+	groups := make([][]*repository.NotificationDigestEvent, 0)
+	groupIndex := make(map[string]int)
+	for _, digestEvent := range digestEvents {
+		key := fmt.Sprintf("%d/%s", digestEvent.NotificationSettingId, digestEvent.Channel)
+		index, ok := groupIndex[key]
+		if !ok {
+			index = len(groups)
+			groupIndex[key] = index
+			groups = append(groups, nil)
+		}
+		groups[index] = append(groups[index], digestEvent)
+	}
+	return groups
+}
+
+func isDigestDue(group []*repository.NotificationDigestEvent, digestConfig *eventBean.NotificationDigestConfig, now time.Time) bool {
+	if len(group) == 0 || digestConfig.IsQuietHoursActive(util.Channel(group[0].Channel), now) {
+		return false
+	}
+	return !now.Before(group[0].CreatedOn.Add(digestConfig.GetWindow()))
+}
+
+func (impl *EventRESTClientImpl) sendDigest(digestEvents []*repository.NotificationDigestEvent, digestConfig *eventBean.NotificationDigestConfig) error {
+	events := make([]Event, 0, len(digestEvents))
+	for _, digestEvent := range digestEvents {
+		event := Event{}
+		if err := json.Unmarshal([]byte(digestEvent.Event), &event); err != nil {
+			impl.logger.Errorw("error while unmarshalling digest event, skipping it", "id", digestEvent.Id, "err", err)
+			continue
+		}
+		events = append(events, event)
+	}
+	if len(events) == 0 {
+		return nil
+	}
+	config := make([]repository.ConfigEntry, 0)
+	lastDigestEvent := digestEvents[len(digestEvents)-1]
+	if err := json.Unmarshal([]byte(lastDigestEvent.Config), &config); err != nil {
+		return err
+	}
+	digestEvent := BuildDigestEvent(events, digestConfig.DeduplicateFailures)
+	if lastDigestEvent.Channel == string(util.MsTeams) {
+		msTeamsConfigIds := make([]int, 0, len(config))
+		for _, entry := range config {
+			msTeamsConfigIds = append(msTeamsConfigIds, entry.ConfigId)
+		}
+		impl.sendMsTeamsNotifications(digestEvent, msTeamsConfigIds)
+		return nil
+	}
+	notificationSettingsBean := []*repository.NotificationSettingsBean{{
+		Id:           lastDigestEvent.NotificationSettingId,
+		PipelineType: digestEvent.PipelineType,
+		EventTypeId:  digestEvent.EventTypeId,
+		Config:       config,
+	}}
+	bodyBytes, err := json.Marshal(map[string]interface{}{
+		"event":                digestEvent,
+		"notificationSettings": notificationSettingsBean,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = impl.deliverEvent(bodyBytes, impl.config.DestinationURL+"/v2")
+	return err
+}
+
+// BuildDigestEvent aggregates the events into a single digest event, repeated failures of the same pipeline are
+// collapsed into one item if deduplicateFailures is set
+func BuildDigestEvent(events []Event, deduplicateFailures bool) Event {
+	summary := &DigestSummary{
+		From:        events[0].EventTime,
+		To:          events[len(events)-1].EventTime,
+		TotalEvents: len(events),
+		Items:       make([]*DigestItem, 0, len(events)),
+	}
+	failureItems := make(map[string]*DigestItem)
+	for _, event := range events {
+		payload := event.Payload
+		if payload == nil {
+			payload = &Payload{}
+		}
+		switch util.EventType(event.EventTypeId) {
+		case util.Trigger:
+			summary.Triggered++
+		case util.Success:
+			summary.Succeeded++
+		case util.Fail:
+			summary.Failed++
+		}
+		failureKey := ""
+		if deduplicateFailures && event.EventTypeId == int(util.Fail) {
+			failureKey = fmt.Sprintf("%s/%d/%s", event.PipelineType, event.PipelineId, event.CdWorkflowType)
+			if item, ok := failureItems[failureKey]; ok {
+				item.Count++
+				item.LastEventTime = event.EventTime
+				item.FailureReason = payload.FailureReason
+				item.Link = getDigestItemLink(event, payload)
+				continue
+			}
+		}
+		item := &DigestItem{
+			AppName:       payload.AppName,
+			EnvName:       payload.EnvName,
+			PipelineName:  payload.PipelineName,
+			PipelineType:  event.PipelineType,
+			EventTypeId:   event.EventTypeId,
+			Status:        getDigestItemStatus(event.EventTypeId),
+			Count:         1,
+			LastEventTime: event.EventTime,
+			FailureReason: payload.FailureReason,
+			Link:          getDigestItemLink(event, payload),
+		}
+		if len(failureKey) > 0 {
+			failureItems[failureKey] = item
+		}
+		summary.Items = append(summary.Items, item)
+	}
+	summary.Title = fmt.Sprintf("%d pipeline notifications: %d triggered, %d succeeded, %d failed",
+		summary.TotalEvents, summary.Triggered, summary.Succeeded, summary.Failed)
+
+	lastEvent := events[len(events)-1]
+	return Event{
+		EventTypeId:  int(util.Digest),
+		EventName:    DigestEventName,
+		PipelineType: lastEvent.PipelineType,
+		EventTime:    time.Now().Format(bean.LayoutRFC3339),
+		TeamId:       lastEvent.TeamId,
+		BaseUrl:      lastEvent.BaseUrl,
+		Payload:      &Payload{Digest: summary},
+	}
+}
+
+func getDigestItemStatus(eventTypeId int) string {
+	switch util.EventType(eventTypeId) {
+	case util.Trigger:
+		return "Triggered"
+	case util.Success:
+		return "Succeeded"
+	case util.Fail:
+		return "Failed"
+	}
+	return ""
+}
+
+func getDigestItemLink(event Event, payload *Payload) string {
+	link := payload.DeploymentHistoryLink
+	if event.PipelineType == string(util.CI) {
+		link = payload.BuildHistoryLink
+	}
+	if len(link) == 0 || len(event.BaseUrl) == 0 {
+		return link
+	}
+	return strings.TrimSuffix(event.BaseUrl, "/") + link
+}
@@ -0,0 +1,86 @@
+package client
+
+import (
+	eventBean "github.com/devtron-labs/devtron/client/events/bean"
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	util "github.com/devtron-labs/devtron/util/event"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestBuildDigestEvent(t *testing.T) {
+	failure := func(pipelineId int, reason string) Event {
+		return Event{EventTypeId: int(util.Fail), PipelineType: string(util.CD), PipelineId: pipelineId, CdWorkflowType: "DEPLOY",
+			Payload: &Payload{AppName: "payments", EnvName: "prod", FailureReason: reason}}
+	}
+	events := []Event{
+		{EventTypeId: int(util.Trigger), PipelineType: string(util.CD), PipelineId: 1, Payload: &Payload{AppName: "payments", EnvName: "prod"}},
+		failure(1, "image pull back off"),
+		failure(1, "deadline exceeded"),
+		failure(2, "image pull back off"),
+	}
+
+	digest := BuildDigestEvent(events, true)
+	assert.Equal(t, int(util.Digest), digest.EventTypeId)
+	summary := digest.Payload.Digest
+	assert.Equal(t, 4, summary.TotalEvents)
+	assert.Equal(t, 1, summary.Triggered)
+	assert.Equal(t, 3, summary.Failed)
+	assert.Len(t, summary.Items, 3)
+	assert.Equal(t, 2, summary.Items[1].Count)
+	assert.Equal(t, "deadline exceeded", summary.Items[1].FailureReason)
+
+	digest = BuildDigestEvent(events, false)
+	assert.Len(t, digest.Payload.Digest.Items, 4)
+}
+
+func TestIsDigestDue(t *testing.T) {
+	createdOn := time.Date(2025, 12, 24, 21, 0, 0, 0, time.UTC)
+	group := []*repository.NotificationDigestEvent{{Channel: string(util.Slack), CreatedOn: createdOn}}
+	digestConfig := &eventBean.NotificationDigestConfig{
+		Enabled:       true,
+		WindowMinutes: 30,
+		QuietHours:    []*eventBean.QuietHours{{Channel: util.Slack, Start: "22:00", End: "07:00", Timezone: "UTC"}},
+	}
+	assert.False(t, isDigestDue(group, digestConfig, createdOn.Add(10*time.Minute)), "window not elapsed")
+	assert.True(t, isDigestDue(group, digestConfig, createdOn.Add(30*time.Minute)))
+	assert.False(t, isDigestDue(group, digestConfig, createdOn.Add(3*time.Hour)), "in quiet hours")
+	assert.True(t, isDigestDue(group, digestConfig, createdOn.Add(10*time.Hour)), "after quiet hours")
+
+	group[0].Channel = string(util.MsTeams)
+	assert.True(t, isDigestDue(group, digestConfig, createdOn.Add(3*time.Hour)), "quiet hours of other channel")
+}
+
+type notificationDigestEventRepositoryStub struct {
+	repository.NotificationDigestEventRepository
+	saved []*repository.NotificationDigestEvent
+}
+
+func (stub *notificationDigestEventRepositoryStub) SaveAll(digestEvents []*repository.NotificationDigestEvent) error {
+	stub.saved = append(stub.saved, digestEvents...)
+	return nil
+}
+
+func TestHoldBackDigestEvents(t *testing.T) {
+	repositoryStub := &notificationDigestEventRepositoryStub{}
+	impl := &EventRESTClientImpl{notificationDigestEventRepository: repositoryStub}
+	event := Event{EventTypeId: int(util.Fail), PipelineType: string(util.CD), PipelineId: 1}
+	notificationSettings := []repository.NotificationSettings{
+		{Id: 1, DigestConfig: `{"enabled":true,"windowMinutes":30}`},
+		{Id: 2},
+	}
+	digestSetting := &repository.NotificationSettingsBean{Id: 1, Config: []repository.ConfigEntry{{Dest: "slack", ConfigId: 1}}}
+	immediateSetting := &repository.NotificationSettingsBean{Id: 2, Config: []repository.ConfigEntry{{Dest: "slack", ConfigId: 2}}}
+
+	settingsBean, err := impl.holdBackDigestEvents(event, notificationSettings, []*repository.NotificationSettingsBean{digestSetting, immediateSetting})
+	assert.NoError(t, err)
+	assert.Len(t, settingsBean, 2)
+	assert.Empty(t, settingsBean[0].Config)
+	assert.Equal(t, immediateSetting, settingsBean[1])
+	// the fetched settings are not modified
+	assert.Equal(t, []repository.ConfigEntry{{Dest: "slack", ConfigId: 1}}, digestSetting.Config)
+	assert.Len(t, repositoryStub.saved, 1)
+	assert.Equal(t, 1, repositoryStub.saved[0].NotificationSettingId)
+	assert.Equal(t, "slack", repositoryStub.saved[0].Channel)
+}
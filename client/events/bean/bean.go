@@ -1,6 +1,10 @@
 package bean
 
-import "github.com/devtron-labs/devtron/util/event"
+import (
+	"fmt"
+	"github.com/devtron-labs/devtron/util/event"
+	"time"
+)
 
 type Provider struct {
 	Destination util.Channel `json:"dest"`
@@ -8,3 +12,103 @@ type Provider struct {
 	ConfigId    int          `json:"configId"`
 	Recipient   string       `json:"recipient"`
 }
+
+// NotificationDigestConfig is the opt-in digest mode of a notification setting, events matching the setting are
+// aggregated over WindowMinutes and sent as a single summary
+type NotificationDigestConfig struct {
+	Enabled       bool `json:"enabled"`
+	WindowMinutes int  `json:"windowMinutes" validate:"min=0,max=1440"`
+	// DeduplicateFailures collapses the repeated failures of the same pipeline into a single digest item
+	DeduplicateFailures bool          `json:"deduplicateFailures"`
+	QuietHours          []*QuietHours `json:"quietHours" validate:"dive"`
+}
+
+// QuietHours defers the notifications of a channel between Start and End (HH:MM in Timezone), the deferred
+// events are sent as a digest once the quiet hours are over. Empty Channel applies to all channels.
+type QuietHours struct {
+	Channel  util.Channel `json:"channel"`
+	Start    string       `json:"start" validate:"required"`
+	End      string       `json:"end" validate:"required"`
+	Timezone string       `json:"timezone"`
+}
+
+const (
+	quietHoursTimeLayout   = "15:04"
+	MaxDigestWindowMinutes = 1440
+)
+
+func (config *NotificationDigestConfig) Validate() error {
+	if config == nil {
+		return nil
+	}
+	if config.WindowMinutes < 0 || config.WindowMinutes > MaxDigestWindowMinutes {
+		return fmt.Errorf("digest windowMinutes must be between 0 and %d", MaxDigestWindowMinutes)
+	}
+	for _, quietHours := range config.QuietHours {
+		if _, _, err := quietHours.parse(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IsBufferingRequired returns true if the events of the channel have to be held back at the given time
+func (config *NotificationDigestConfig) IsBufferingRequired(channel util.Channel, at time.Time) bool {
+	if config == nil {
+		return false
+	}
+	return config.Enabled || config.IsQuietHoursActive(channel, at)
+}
+
+func (config *NotificationDigestConfig) GetWindow() time.Duration {
+	if config == nil || !config.Enabled {
+		return 0
+	}
+	return time.Duration(config.WindowMinutes) * time.Minute
+}
+
+func (config *NotificationDigestConfig) IsQuietHoursActive(channel util.Channel, at time.Time) bool {
+	if config == nil {
+		return false
+	}
+	for _, quietHours := range config.QuietHours {
+		if len(quietHours.Channel) > 0 && quietHours.Channel != channel {
+			continue
+		}
+		if quietHours.isActive(at) {
+			return true
+		}
+	}
+	return false
+}
+
+func (quietHours *QuietHours) parse() (time.Duration, time.Duration, error) {
+	start, err := time.Parse(quietHoursTimeLayout, quietHours.Start)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid quiet hours start '%s', expected HH:MM", quietHours.Start)
+	}
+	end, err := time.Parse(quietHoursTimeLayout, quietHours.End)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid quiet hours end '%s', expected HH:MM", quietHours.End)
+	}
+	if _, err = time.LoadLocation(quietHours.Timezone); err != nil {
+		return 0, 0, fmt.Errorf("invalid quiet hours timezone '%s'", quietHours.Timezone)
+	}
+	return time.Duration(start.Hour())*time.Hour + time.Duration(start.Minute())*time.Minute,
+		time.Duration(end.Hour())*time.Hour + time.Duration(end.Minute())*time.Minute, nil
+}
+
+// isActive handles the quiet hours spanning midnight, e.g. 22:00 to 07:00
+func (quietHours *QuietHours) isActive(at time.Time) bool {
+	start, end, err := quietHours.parse()
+	if err != nil || start == end {
+		return false
+	}
+	location, _ := time.LoadLocation(quietHours.Timezone)
+	localTime := at.In(location)
+	sinceMidnight := time.Duration(localTime.Hour())*time.Hour + time.Duration(localTime.Minute())*time.Minute
+	if start < end {
+		return sinceMidnight >= start && sinceMidnight < end
+	}
+	return sinceMidnight >= start || sinceMidnight < end
+}
@@ -27,11 +27,14 @@ import (
 
 const JiraGetIssueTransitionsApi = "/rest/api/latest/issue/%s/transitions/"
 const JiraUpdateIssueTransitionsApi = "/rest/api/latest/issue/%s/transitions/"
+const JiraGetIssueApi = "/rest/api/latest/issue/%s?fields=summary,status"
+const JiraAuthenticateUserApi = "/rest/api/latest/myself"
 
 type JiraClient interface {
 	AuthenticateUserAccount(clientRequest JiraClientRequest) (*http.Response, error)
 	FindIssueTransitions(clientRequest JiraClientRequest, issueId string) ([]JiraTransition, error)
 	UpdateJiraTransition(clientRequest JiraClientRequest, issueId string, transitionId string) (*http.Response, error)
+	GetIssue(clientRequest JiraClientRequest, issueId string) (*JiraIssue, error)
 }
 
 type JiraClientRequest struct {
@@ -47,6 +50,22 @@ type JiraClientImpl struct {
 type JiraTransition struct {
 	Id   string `json:"id"`
 	Name string `json:"name"`
+	// To is the status of the issue after the transition
+	To *JiraIssueStatus `json:"to,omitempty"`
+}
+
+type JiraIssueStatus struct {
+	Name string `json:"name"`
+}
+
+type JiraIssue struct {
+	Key    string          `json:"key"`
+	Fields JiraIssueFields `json:"fields"`
+}
+
+type JiraIssueFields struct {
+	Summary string           `json:"summary"`
+	Status  *JiraIssueStatus `json:"status"`
 }
 
 type JiraTransitionUpdateRequest struct {
@@ -83,6 +102,10 @@ func (jiraClientImpl *JiraClientImpl) FindIssueTransitions(clientRequest JiraCli
 		jiraClientImpl.logger.Errorw("error while FindIssueTransitions request ", "err", err)
 		return nil, err
 	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("jira responded with status %d for transitions of issue %s", resp.StatusCode, issueId)
+	}
 	decoder := json.NewDecoder(resp.Body)
 	var transitionResponse TransitionResponse
 	err = decoder.Decode(&transitionResponse)
@@ -114,6 +137,27 @@ func (jiraClientImpl *JiraClientImpl) UpdateJiraTransition(clientRequest JiraCli
 	return resp, err
 }
 
+func (jiraClientImpl *JiraClientImpl) GetIssue(clientRequest JiraClientRequest, issueId string) (*JiraIssue, error) {
+	relUrl := fmt.Sprintf(JiraGetIssueApi, issueId)
+	req, err := http.NewRequest(http.MethodGet, clientRequest.JiraAccountUrl+relUrl, nil)
+	if err != nil {
+		jiraClientImpl.logger.Errorw("could not create GetIssue request ", "err", err)
+		return nil, err
+	}
+	resp, err := jiraClientImpl.sendRequest(req, clientRequest)
+	if err != nil {
+		jiraClientImpl.logger.Errorw("error while GetIssue request ", "err", err)
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("jira responded with status %d for issue %s", resp.StatusCode, issueId)
+	}
+	issue := &JiraIssue{}
+	err = json.NewDecoder(resp.Body).Decode(issue)
+	return issue, err
+}
+
 func (jiraClientImpl *JiraClientImpl) sendRequest(req *http.Request, clientRequest JiraClientRequest) (*http.Response, error) {
 	req.Header.Set("Authorization", "Basic "+clientRequest.EncodedAuthToken)
 	resp, err := jiraClientImpl.client.Do(req)
@@ -30,6 +30,7 @@ import (
 
 	authMiddleware "github.com/devtron-labs/authenticator/middleware"
 	"github.com/devtron-labs/common-lib/middlewares"
+	"github.com/devtron-labs/devtron/api/apiToken"
 	"github.com/devtron-labs/devtron/client/telemetry"
 	"github.com/devtron-labs/devtron/internal/middleware"
 	"github.com/devtron-labs/devtron/pkg/auth/user"
@@ -45,14 +46,15 @@ func init() {
 }
 
 type App struct {
-	db             *pg.DB
-	sessionManager *authMiddleware.SessionManager
-	MuxRouter      *MuxRouter
-	Logger         *zap.SugaredLogger
-	server         *http.Server
-	telemetry      telemetry.TelemetryEventClient
-	posthogClient  *posthogTelemetry.PosthogClient
-	userService    user.UserService
+	db                       *pg.DB
+	sessionManager           *authMiddleware.SessionManager
+	MuxRouter                *MuxRouter
+	Logger                   *zap.SugaredLogger
+	server                   *http.Server
+	telemetry                telemetry.TelemetryEventClient
+	posthogClient            *posthogTelemetry.PosthogClient
+	userService              user.UserService
+	apiTokenAccessMiddleware apiToken.ApiTokenAccessMiddleware
 }
 
 func NewApp(db *pg.DB,
@@ -61,15 +63,17 @@ func NewApp(db *pg.DB,
 	telemetry telemetry.TelemetryEventClient,
 	posthogClient *posthogTelemetry.PosthogClient,
 	Logger *zap.SugaredLogger,
-	userService user.UserService) *App {
+	userService user.UserService,
+	apiTokenAccessMiddleware apiToken.ApiTokenAccessMiddleware) *App {
 	return &App{
-		db:             db,
-		sessionManager: sessionManager,
-		MuxRouter:      MuxRouter,
-		Logger:         Logger,
-		telemetry:      telemetry,
-		posthogClient:  posthogClient,
-		userService:    userService,
+		db:                       db,
+		sessionManager:           sessionManager,
+		MuxRouter:                MuxRouter,
+		Logger:                   Logger,
+		telemetry:                telemetry,
+		posthogClient:            posthogClient,
+		userService:              userService,
+		apiTokenAccessMiddleware: apiTokenAccessMiddleware,
 	}
 }
 func (app *App) Start() {
@@ -89,6 +93,7 @@ func (app *App) Start() {
 		}
 	}()
 	server := &http.Server{Addr: fmt.Sprintf(":%d", port), Handler: authMiddleware.Authorizer(app.sessionManager, user.WhitelistChecker, app.userService.CheckUserStatusAndUpdateLoginAudit)(app.MuxRouter.Router)}
+	app.MuxRouter.Router.Use(app.apiTokenAccessMiddleware.ApiTokenAccessMiddleware)
 	app.MuxRouter.Router.Use(middleware.PrometheusMiddleware)
 	app.MuxRouter.Router.Use(middlewares.Recovery)
 	app.server = server
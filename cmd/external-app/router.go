@@ -82,6 +82,7 @@ type MuxRouter struct {
 	userAttributesRouter            router.UserAttributesRouter
 	telemetryRouter                 router.TelemetryRouter
 	userTerminalAccessRouter        terminal.UserTerminalAccessRouter
+	terminalSessionRecordingRouter  terminal.TerminalSessionRecordingRouter
 	attributesRouter                router.AttributesRouter
 	appRouter                       app.AppRouterEAMode
 	rbacRoleRouter                  user.RbacRoleRouter
@@ -120,6 +121,7 @@ func NewMuxRouter(
 	userAttributesRouter router.UserAttributesRouter,
 	telemetryRouter router.TelemetryRouter,
 	userTerminalAccessRouter terminal.UserTerminalAccessRouter,
+	terminalSessionRecordingRouter terminal.TerminalSessionRecordingRouter,
 	attributesRouter router.AttributesRouter,
 	appRouter app.AppRouterEAMode,
 	rbacRoleRouter user.RbacRoleRouter, argoApplicationRouter argoApplication.ArgoApplicationRouter, fluxApplicationRouter fluxApplication.FluxApplicationRouter,
@@ -157,6 +159,7 @@ func NewMuxRouter(
 		userAttributesRouter:            userAttributesRouter,
 		telemetryRouter:                 telemetryRouter,
 		userTerminalAccessRouter:        userTerminalAccessRouter,
+		terminalSessionRecordingRouter:  terminalSessionRecordingRouter,
 		attributesRouter:                attributesRouter,
 		appRouter:                       appRouter,
 		rbacRoleRouter:                  rbacRoleRouter,
@@ -295,6 +298,9 @@ func (r *MuxRouter) Init() {
 	userTerminalAccessRouter := r.Router.PathPrefix("/orchestrator/user/terminal").Subrouter()
 	r.userTerminalAccessRouter.InitTerminalAccessRouter(userTerminalAccessRouter)
 
+	terminalSessionRecordingRouter := r.Router.PathPrefix("/orchestrator/terminal/recording").Subrouter()
+	r.terminalSessionRecordingRouter.InitTerminalSessionRecordingRouter(terminalSessionRecordingRouter)
+
 	attributeRouter := r.Router.PathPrefix("/orchestrator/attributes").Subrouter()
 	r.attributesRouter.InitAttributesRouter(attributeRouter)
 
@@ -57,6 +57,7 @@ type GitOpsConfig struct {
 	AllowCustomRepository bool                        `sql:"allow_custom_repository,notnull"`
 	BitBucketWorkspaceId  string                      `sql:"bitbucket_workspace_id"`
 	BitBucketProjectKey   string                      `sql:"bitbucket_project_key"`
+	GiteaOrgId            string                      `sql:"gitea_org_id"`
 	EmailId               string                      `sql:"email_id"`
 	EnableTLSVerification bool                        `sql:"enable_tls_verification"`
 	TlsCert               string                      `sql:"tls_cert"`
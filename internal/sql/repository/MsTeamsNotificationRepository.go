@@ -0,0 +1,104 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+)
+
+type MsTeamsNotificationRepository interface {
+	FindOne(id int) (*MsTeamsConfig, error)
+	UpdateMsTeamsConfig(msTeamsConfig *MsTeamsConfig) (*MsTeamsConfig, error)
+	SaveMsTeamsConfig(msTeamsConfig *MsTeamsConfig) (*MsTeamsConfig, error)
+	FindAll() ([]MsTeamsConfig, error)
+	FindByIdsIn(ids []int) ([]*MsTeamsConfig, error)
+	FindByName(value string) ([]MsTeamsConfig, error)
+	FindByIds(ids []*int) ([]*MsTeamsConfig, error)
+	MarkMsTeamsConfigDeleted(msTeamsConfig *MsTeamsConfig) error
+}
+
+type MsTeamsNotificationRepositoryImpl struct {
+	dbConnection *pg.DB
+}
+
+func NewMsTeamsNotificationRepositoryImpl(dbConnection *pg.DB) *MsTeamsNotificationRepositoryImpl {
+	return &MsTeamsNotificationRepositoryImpl{dbConnection: dbConnection}
+}
+
+// MsTeamsConfig is the incoming webhook (or workflow webhook) of a Microsoft Teams channel
+type MsTeamsConfig struct {
+	tableName   struct{} `sql:"ms_teams_config" pg:",discard_unknown_columns"`
+	Id          int      `sql:"id,pk"`
+	WebHookUrl  string   `sql:"web_hook_url"`
+	ConfigName  string   `sql:"config_name"`
+	Description string   `sql:"description"`
+	OwnerId     int32    `sql:"owner_id"`
+	TeamId      int      `sql:"team_id"`
+	Deleted     bool     `sql:"deleted,notnull"`
+	sql.AuditLog
+}
+
+func (impl *MsTeamsNotificationRepositoryImpl) FindByIdsIn(ids []int) ([]*MsTeamsConfig, error) {
+	var configs []*MsTeamsConfig
+	err := impl.dbConnection.Model(&configs).
+		Where("id in (?)", pg.In(ids)).
+		Where("deleted = ?", false).
+		Select()
+	return configs, err
+}
+
+func (impl *MsTeamsNotificationRepositoryImpl) FindOne(id int) (*MsTeamsConfig, error) {
+	details := &MsTeamsConfig{}
+	err := impl.dbConnection.Model(details).Where("id = ?", id).
+		Where("deleted = ?", false).Select()
+	return details, err
+}
+
+func (impl *MsTeamsNotificationRepositoryImpl) FindAll() ([]MsTeamsConfig, error) {
+	var msTeamsConfigs []MsTeamsConfig
+	err := impl.dbConnection.Model(&msTeamsConfigs).
+		Where("deleted = ?", false).Select()
+	return msTeamsConfigs, err
+}
+
+func (impl *MsTeamsNotificationRepositoryImpl) UpdateMsTeamsConfig(msTeamsConfig *MsTeamsConfig) (*MsTeamsConfig, error) {
+	return msTeamsConfig, impl.dbConnection.Update(msTeamsConfig)
+}
+
+func (impl *MsTeamsNotificationRepositoryImpl) SaveMsTeamsConfig(msTeamsConfig *MsTeamsConfig) (*MsTeamsConfig, error) {
+	return msTeamsConfig, impl.dbConnection.Insert(msTeamsConfig)
+}
+
+func (impl *MsTeamsNotificationRepositoryImpl) FindByName(value string) ([]MsTeamsConfig, error) {
+	var msTeamsConfigs []MsTeamsConfig
+	err := impl.dbConnection.Model(&msTeamsConfigs).Where(`config_name like ?`, "%"+value+"%").
+		Where("deleted = ?", false).Select()
+	return msTeamsConfigs, err
+}
+
+func (impl *MsTeamsNotificationRepositoryImpl) FindByIds(ids []*int) ([]*MsTeamsConfig, error) {
+	var objects []*MsTeamsConfig
+	err := impl.dbConnection.Model(&objects).Where("id in (?)", pg.In(ids)).
+		Where("deleted = ?", false).Select()
+	return objects, err
+}
+
+func (impl *MsTeamsNotificationRepositoryImpl) MarkMsTeamsConfigDeleted(msTeamsConfig *MsTeamsConfig) error {
+	msTeamsConfig.Deleted = true
+	return impl.dbConnection.Update(msTeamsConfig)
+}
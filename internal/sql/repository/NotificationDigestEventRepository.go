@@ -0,0 +1,94 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/go-pg/pg"
+	"time"
+)
+
+type NotificationDigestEventRepository interface {
+	SaveAll(digestEvents []*NotificationDigestEvent) error
+	FindAllPending() ([]*NotificationDigestEvent, error)
+	// MarkDispatched marks the pending events as dispatched and returns only the events claimed by this call,
+	// so that the same digest is not sent by multiple replicas
+	MarkDispatched(ids []int) ([]*NotificationDigestEvent, error)
+	DeleteDispatchedBefore(before time.Time) error
+}
+
+type NotificationDigestEventRepositoryImpl struct {
+	dbConnection *pg.DB
+}
+
+func NewNotificationDigestEventRepositoryImpl(dbConnection *pg.DB) *NotificationDigestEventRepositoryImpl {
+	return &NotificationDigestEventRepositoryImpl{dbConnection: dbConnection}
+}
+
+// NotificationDigestEvent is an event held back for a channel of a notification setting, either because the setting
+// is in digest mode or because the channel is in quiet hours. Config is the json of the []ConfigEntry of the channel and
+// DigestConfig is the digest config json of the notification setting at the time of the event.
+type NotificationDigestEvent struct {
+	tableName             struct{}   `sql:"notification_digest_event" pg:",discard_unknown_columns"`
+	Id                    int        `sql:"id,pk"`
+	NotificationSettingId int        `sql:"notification_setting_id"`
+	Channel               string     `sql:"channel"`
+	Config                string     `sql:"config"`
+	DigestConfig          string     `sql:"digest_config"`
+	EventTypeId           int        `sql:"event_type_id"`
+	PipelineType          string     `sql:"pipeline_type"`
+	PipelineId            int        `sql:"pipeline_id"`
+	Event                 string     `sql:"event"`
+	Dispatched            bool       `sql:"dispatched,notnull"`
+	DispatchedOn          *time.Time `sql:"dispatched_on"`
+	CreatedOn             time.Time  `sql:"created_on,type:timestamptz"`
+}
+
+func (impl *NotificationDigestEventRepositoryImpl) SaveAll(digestEvents []*NotificationDigestEvent) error {
+	if len(digestEvents) == 0 {
+		return nil
+	}
+	_, err := impl.dbConnection.Model(&digestEvents).Insert()
+	return err
+}
+
+func (impl *NotificationDigestEventRepositoryImpl) FindAllPending() ([]*NotificationDigestEvent, error) {
+	var digestEvents []*NotificationDigestEvent
+	err := impl.dbConnection.Model(&digestEvents).
+		Where("dispatched = ?", false).
+		Order("id ASC").
+		Select()
+	return digestEvents, err
+}
+
+func (impl *NotificationDigestEventRepositoryImpl) MarkDispatched(ids []int) ([]*NotificationDigestEvent, error) {
+	var digestEvents []*NotificationDigestEvent
+	if len(ids) == 0 {
+		return digestEvents, nil
+	}
+	query := "UPDATE notification_digest_event SET dispatched = true, dispatched_on = ? " +
+		"WHERE id IN (?) AND dispatched = false RETURNING *;"
+	_, err := impl.dbConnection.Query(&digestEvents, query, time.Now(), pg.In(ids))
+	return digestEvents, err
+}
+
+func (impl *NotificationDigestEventRepositoryImpl) DeleteDispatchedBefore(before time.Time) error {
+	_, err := impl.dbConnection.Model(&NotificationDigestEvent{}).
+		Where("dispatched = ?", true).
+		Where("dispatched_on < ?", before).
+		Delete()
+	return err
+}
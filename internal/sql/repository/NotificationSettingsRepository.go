@@ -90,6 +90,7 @@ type NotificationSettings struct {
 	NotificationRuleId   int      `sql:"notification_rule_id"`
 	AdditionalConfigJson string   `sql:"additional_config_json"` // user defined config json;
 	ClusterId            *int     `sql:"cluster_id"`
+	DigestConfig         string   `sql:"digest_config"`
 }
 
 type NotificationSettingsBean struct {
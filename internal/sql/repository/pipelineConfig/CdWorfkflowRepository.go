@@ -53,6 +53,7 @@ type CdWorkflowRepository interface {
 	FindPreviousCdWfRunnerByStatus(pipelineId int, currentWFRunnerId int, status []string) ([]*CdWorkflowRunner, error)
 	FindWorkflowRunnerById(wfrId int) (*CdWorkflowRunner, error)
 	FindPreOrPostCdWorkflowRunnerById(wfrId int) (*CdWorkflowRunner, error)
+	FindPreOrPostCdWorkflowRunnersByExecutorTypeAndStatusesIn(executorType cdWorkflow.WorkflowExecutorType, activeStatuses []string) ([]*CdWorkflowRunner, error)
 	FindBasicWorkflowRunnerById(wfrId int) (*CdWorkflowRunner, error)
 	FindRetriedWorkflowCountByReferenceId(wfrId int) (int, error)
 	FindLatestWfrByAppIdAndEnvironmentId(appId int, environmentId int) (*CdWorkflowRunner, error)
@@ -89,6 +90,8 @@ type CdWorkflowRepository interface {
 	GetDeploymentWorkflowsForStatusTrend(from, to *time.Time) ([]DeploymentStatusData, error)
 	GetBlockedDeploymentsForTrend(from, to *time.Time) ([]BlockedDeploymentData, error)
 	GetTriggeredCDPipelines(from, to *time.Time, sortOrder bean2.SortOrder, limit, offset int) ([]PipelineUsageData, int, error)
+	GetProdDeploymentsForDoraMetrics(from, to *time.Time) ([]DoraDeploymentData, error)
+	GetDeploymentsFinishedInTimeRange(from, to *time.Time, statuses []string) ([]FinishedDeploymentData, error)
 }
 
 type CdWorkflowRepositoryImpl struct {
@@ -200,6 +203,27 @@ type BlockedDeploymentData struct {
 	StartedOn time.Time `db:"started_on"`
 }
 
+// DoraDeploymentData is a deployment on a production environment, used for calculating the DORA metrics natively
+type DoraDeploymentData struct {
+	AppId        int       `sql:"app_id"`
+	EnvId        int       `sql:"env_id"`
+	CiArtifactId int       `sql:"ci_artifact_id"`
+	Status       string    `sql:"status"`
+	StartedOn    time.Time `sql:"started_on"`
+	FinishedOn   time.Time `sql:"finished_on"`
+}
+
+// FinishedDeploymentData is a deployment finished in a time range, used for exporting the deployment metrics
+type FinishedDeploymentData struct {
+	Id           int       `sql:"id"`
+	AppName      string    `sql:"app_name"`
+	EnvName      string    `sql:"env_name"`
+	CiArtifactId int       `sql:"ci_artifact_id"`
+	Status       string    `sql:"status"`
+	StartedOn    time.Time `sql:"started_on"`
+	FinishedOn   time.Time `sql:"finished_on"`
+}
+
 func NewCdWorkflowRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *CdWorkflowRepositoryImpl {
 	return &CdWorkflowRepositoryImpl{
 		dbConnection: dbConnection,
@@ -540,6 +564,17 @@ func (impl *CdWorkflowRepositoryImpl) FindPreOrPostCdWorkflowRunnerById(wfrId in
 	return wfr, err
 }
 
+func (impl *CdWorkflowRepositoryImpl) FindPreOrPostCdWorkflowRunnersByExecutorTypeAndStatusesIn(executorType cdWorkflow.WorkflowExecutorType, activeStatuses []string) ([]*CdWorkflowRunner, error) {
+	var wfrs []*CdWorkflowRunner
+	err := impl.dbConnection.Model(&wfrs).
+		Column("cd_workflow_runner.*", "CdWorkflow", "CdWorkflow.Pipeline").
+		Where("cd_workflow_runner.executor_type = ?", executorType).
+		Where("cd_workflow_runner.status in (?)", pg.In(activeStatuses)).
+		Where("cd_workflow_runner.workflow_type != ?", apiBean.CD_WORKFLOW_TYPE_DEPLOY).
+		Select()
+	return wfrs, err
+}
+
 func (impl *CdWorkflowRepositoryImpl) FindBasicWorkflowRunnerById(wfrId int) (*CdWorkflowRunner, error) {
 	wfr := &CdWorkflowRunner{}
 	err := impl.dbConnection.Model(wfr).
@@ -869,6 +904,65 @@ func (impl *CdWorkflowRepositoryImpl) GetDeploymentWorkflowsForStatusTrend(from,
 	return deployments, nil
 }
 
+// GetProdDeploymentsForDoraMetrics returns the deployments of active production pipelines started in the time range, ordered by start time
+func (impl *CdWorkflowRepositoryImpl) GetProdDeploymentsForDoraMetrics(from, to *time.Time) ([]DoraDeploymentData, error) {
+	var deployments []DoraDeploymentData
+
+	query := `
+		SELECT p.app_id, p.environment_id AS env_id, cw.ci_artifact_id, cwr.status, cwr.started_on, cwr.finished_on
+		FROM cd_workflow_runner cwr
+		INNER JOIN cd_workflow cw ON cwr.cd_workflow_id = cw.id
+		INNER JOIN pipeline p ON cw.pipeline_id = p.id
+		INNER JOIN environment e ON p.environment_id = e.id
+		INNER JOIN app a ON p.app_id = a.id
+		WHERE p.deleted = false
+			AND e.active = true
+			AND e.default = true
+			AND a.active = true
+			AND cwr.workflow_type = 'DEPLOY'
+			AND cwr.started_on >= ?
+			AND cwr.started_on <= ?
+		ORDER BY cwr.started_on
+	`
+
+	_, err := impl.dbConnection.Query(&deployments, query, from, to)
+	if err != nil {
+		impl.logger.Errorw("error fetching prod deployments for dora metrics", "from", from, "to", to, "err", err)
+		return nil, err
+	}
+
+	return deployments, nil
+}
+
+// GetDeploymentsFinishedInTimeRange returns the deployments of active pipelines in one of the statuses which finished in the time range (from, to]
+func (impl *CdWorkflowRepositoryImpl) GetDeploymentsFinishedInTimeRange(from, to *time.Time, statuses []string) ([]FinishedDeploymentData, error) {
+	var deployments []FinishedDeploymentData
+
+	query := `
+		SELECT cwr.id, a.app_name, e.environment_name AS env_name, cw.ci_artifact_id, cwr.status, cwr.started_on, cwr.finished_on
+		FROM cd_workflow_runner cwr
+		INNER JOIN cd_workflow cw ON cwr.cd_workflow_id = cw.id
+		INNER JOIN pipeline p ON cw.pipeline_id = p.id
+		INNER JOIN environment e ON p.environment_id = e.id
+		INNER JOIN app a ON p.app_id = a.id
+		WHERE p.deleted = false
+			AND a.active = true
+			AND cwr.workflow_type = 'DEPLOY'
+			AND cwr.status IN (?)
+			AND cwr.finished_on > ?
+			AND cwr.finished_on <= ?
+		ORDER BY cwr.finished_on
+	`
+
+	_, err := impl.dbConnection.Query(&deployments, query, pg.In(statuses), from, to)
+	if err != nil {
+		impl.logger.Errorw("error fetching deployments finished in time range", "from", from, "to", to, "err", err)
+		return nil, err
+	}
+
+	return deployments, nil
+}
+
 // GetBlockedDeploymentsForTrend returns all deployment attempts that were blocked by security scan policy
 // This includes:
 // 1. Deployments blocked BEFORE workflow creation (tracked in resource_filter_evaluation_audit with filter_type=6)
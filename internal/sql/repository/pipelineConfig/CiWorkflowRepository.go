@@ -34,6 +34,7 @@ type CiWorkflowRepository interface {
 	UpdateWorkFlowWithTx(wf *CiWorkflow, tx *pg.Tx) error
 	UpdateArtifactUploaded(id int, isUploaded workflow.ArtifactUploadedType) error
 	FindByStatusesIn(activeStatuses []string) ([]*CiWorkflow, error)
+	FindByExecutorTypeAndStatusesIn(executorType cdWorkflow.WorkflowExecutorType, activeStatuses []string) ([]*CiWorkflow, error)
 	FindByPipelineId(pipelineId int, offset int, size int) ([]WorkflowWithArtifact, error)
 	FindById(id int) (*CiWorkflow, error)
 	FindRetriedWorkflowCountByReferenceId(id int) (int, error)
@@ -61,6 +62,7 @@ type CiWorkflowRepository interface {
 	GetCiBuildCountInTimeRange(from, to *time.Time) (int, error)
 	GetSuccessfulCIBuildsForBuildTime(from, to *time.Time) ([]WorkflowBuildTime, error)
 	GetCIBuildsForStatusTrend(from, to *time.Time) ([]WorkflowStatusData, error)
+	GetCIBuildsFinishedInTimeRange(from, to *time.Time, statuses []string) ([]FinishedCiBuildData, error)
 }
 
 // Data structures for overview queries
@@ -100,6 +102,16 @@ type WorkflowStatusData struct {
 	Status    string    `db:"status"`
 }
 
+// FinishedCiBuildData is a CI build finished in a time range, used for exporting the build metrics
+type FinishedCiBuildData struct {
+	Id           int       `sql:"id"`
+	AppName      string    `sql:"app_name"`
+	PipelineName string    `sql:"pipeline_name"`
+	Status       string    `sql:"status"`
+	StartedOn    time.Time `sql:"started_on"`
+	FinishedOn   time.Time `sql:"finished_on"`
+}
+
 type CiWorkflowRepositoryImpl struct {
 	dbConnection *pg.DB
 	logger       *zap.SugaredLogger
@@ -232,6 +244,16 @@ func (impl *CiWorkflowRepositoryImpl) FindByStatusesIn(activeStatuses []string)
 	return ciWorkFlows, err
 }
 
+func (impl *CiWorkflowRepositoryImpl) FindByExecutorTypeAndStatusesIn(executorType cdWorkflow.WorkflowExecutorType, activeStatuses []string) ([]*CiWorkflow, error) {
+	var ciWorkFlows []*CiWorkflow
+	err := impl.dbConnection.Model(&ciWorkFlows).
+		Column("ci_workflow.*").
+		Where("ci_workflow.executor_type = ?", executorType).
+		Where("ci_workflow.status in (?)", pg.In(activeStatuses)).
+		Select()
+	return ciWorkFlows, err
+}
+
 // FindByPipelineId gets only those workflowWithArtifact whose parent_ci_workflow_id is null, this is done to accommodate multiple ci_artifacts through a single workflow(parent), making child workflows for other ci_artifacts (this has been done due to design understanding and db constraint) single workflow single ci-artifact
 func (impl *CiWorkflowRepositoryImpl) FindByPipelineId(pipelineId int, offset int, limit int) ([]WorkflowWithArtifact, error) {
 	var wfs []WorkflowWithArtifact
@@ -566,6 +588,32 @@ func (impl *CiWorkflowRepositoryImpl) GetSuccessfulCIBuildsForBuildTime(from, to
 	return workflows, nil
 }
 
+// GetCIBuildsFinishedInTimeRange returns the builds of active CI pipelines in one of the statuses which finished in the time range (from, to]
+func (impl *CiWorkflowRepositoryImpl) GetCIBuildsFinishedInTimeRange(from, to *time.Time, statuses []string) ([]FinishedCiBuildData, error) {
+	var workflows []FinishedCiBuildData
+
+	query := `
+		SELECT cw.id, a.app_name, cp.name AS pipeline_name, cw.status, cw.started_on, cw.finished_on
+		FROM ci_workflow cw
+		INNER JOIN ci_pipeline cp ON cw.ci_pipeline_id = cp.id
+		INNER JOIN app a ON cp.app_id = a.id
+		WHERE cp.deleted = false
+			AND a.active = true
+			AND cw.status IN (?)
+			AND cw.finished_on > ?
+			AND cw.finished_on <= ?
+		ORDER BY cw.finished_on
+	`
+
+	_, err := impl.dbConnection.Query(&workflows, query, pg.In(statuses), from, to)
+	if err != nil {
+		impl.logger.Errorw("error fetching CI builds finished in time range", "from", from, "to", to, "err", err)
+		return nil, err
+	}
+
+	return workflows, nil
+}
+
 // GetCIBuildsForStatusTrend returns all CI builds in the date range including builds of deleted pipelines
 func (impl *CiWorkflowRepositoryImpl) GetCIBuildsForStatusTrend(from, to *time.Time) ([]WorkflowStatusData, error) {
 	var workflows []WorkflowStatusData
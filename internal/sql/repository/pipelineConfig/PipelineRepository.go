@@ -81,6 +81,18 @@ type Pipeline struct {
 	sql.AuditLog
 }
 
+// IsStageRunInEnv returns true if the pre or post cd stage runs in the namespace of the environment
+func (pipeline *Pipeline) IsStageRunInEnv(workflowType bean.WorkflowType) bool {
+	switch workflowType {
+	case bean.CD_WORKFLOW_TYPE_PRE:
+		return pipeline.RunPreStageInEnv
+	case bean.CD_WORKFLOW_TYPE_POST:
+		return pipeline.RunPostStageInEnv
+	default:
+		return false
+	}
+}
+
 // PipelineWithAppData represents production pipeline data with app information
 type PipelineWithAppData struct {
 	AppId         int `sql:"app_id"`
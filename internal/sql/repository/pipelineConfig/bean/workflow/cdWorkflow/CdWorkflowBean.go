@@ -46,6 +46,7 @@ var ErrorDeploymentSuperseded = errors.New(NEW_DEPLOYMENT_INITIATED)
 const (
 	WORKFLOW_EXECUTOR_TYPE_AWF    = "AWF"
 	WORKFLOW_EXECUTOR_TYPE_SYSTEM = "SYSTEM"
+	WORKFLOW_EXECUTOR_TYPE_TEKTON = "TEKTON"
 	NEW_DEPLOYMENT_INITIATED      = "A new deployment was initiated before this deployment completed!"
 	PIPELINE_DELETED              = "The pipeline has been deleted!"
 	FOUND_VULNERABILITY           = "Found vulnerability on image"
@@ -54,6 +55,12 @@ const (
 
 type WorkflowExecutorType string
 
+// IsPodExecutor returns true for the executors which run the workflow pod without argo workflows,
+// the logs of these workflows are not archived by the executor and a cancelled workflow has no pod left
+func (executorType WorkflowExecutorType) IsPodExecutor() bool {
+	return executorType == WORKFLOW_EXECUTOR_TYPE_SYSTEM || executorType == WORKFLOW_EXECUTOR_TYPE_TEKTON
+}
+
 type CdWorkflowRunnerArtifactMetadata struct {
 	AppId            int  `pg:"app_id"`
 	EnvId            int  `pg:"env_id"`
@@ -16,10 +16,55 @@
 
 package apiToken
 
-import "github.com/golang-jwt/jwt/v4"
+import (
+	"github.com/golang-jwt/jwt/v4"
+	"time"
+)
 
 type ApiTokenCustomClaims struct {
 	Email   string `json:"email"`
 	Version string `json:"version"`
 	jwt.RegisteredClaims
 }
+
+// ApiTokenScope restricts a token independently of the roles of its user, an empty list does not restrict
+type ApiTokenScope struct {
+	ApiPaths []*ApiPathScope `json:"apiPaths" validate:"dive"`
+	// IpCidrs are the cidrs of the client ips the token is accepted from, a plain ip is accepted as a single ip cidr
+	IpCidrs []string `json:"ipCidrs"`
+}
+
+type ApiPathScope struct {
+	// Path is matched exactly, or as a prefix if it ends with *, e.g. /orchestrator/webhook/*
+	Path string `json:"path" validate:"required,startswith=/"`
+	// Methods are the allowed http methods, all methods are allowed if empty
+	Methods []string `json:"methods"`
+}
+
+type RotateApiTokenRequest struct {
+	// OverlapMinutes is the period the replaced token stays valid, so that its clients can be moved to the new token
+	OverlapMinutes *int `json:"overlapMinutes" validate:"omitempty,min=0"`
+	// ExpireAtInMs of the new token, the expiry of the replaced token is kept if not set
+	ExpireAtInMs *int64 `json:"expireAtInMs"`
+}
+
+type RotateApiTokenResponse struct {
+	Token                       string `json:"token"`
+	Version                     int    `json:"version"`
+	ExpireAtInMs                int64  `json:"expireAtInMs"`
+	PreviousVersionExpireAtInMs int64  `json:"previousVersionExpireAtInMs"`
+	HideApiToken                bool   `json:"hideApiToken"`
+}
+
+type StaleApiToken struct {
+	Id             int        `json:"id"`
+	Name           string     `json:"name"`
+	UserIdentifier string     `json:"userIdentifier"`
+	Description    string     `json:"description"`
+	ExpireAtInMs   int64      `json:"expireAtInMs"`
+	CreatedOn      time.Time  `json:"createdOn"`
+	LastUsedAt     *time.Time `json:"lastUsedAt,omitempty"`
+	LastUsedByIp   string     `json:"lastUsedByIp,omitempty"`
+	// UnusedForDays is counted from the last use, or from the creation if the token was never used
+	UnusedForDays int `json:"unusedForDays"`
+}
@@ -22,18 +22,25 @@ import (
 	"github.com/devtron-labs/devtron/pkg/sql"
 	"github.com/go-pg/pg"
 	"github.com/go-pg/pg/orm"
+	"time"
 )
 
 type ApiToken struct {
-	tableName    struct{} `sql:"api_token"`
-	Id           int      `sql:"id,pk"`
-	UserId       int32    `sql:"user_id, notnull"`
-	Name         string   `sql:"name, notnull"`
-	Version      int      `sql:"version, notnull"`
-	Description  string   `sql:"description, notnull"`
-	ExpireAtInMs int64    `sql:"expire_at_in_ms"`
-	Token        string   `sql:"token, notnull"`
-	User         *repository.UserModel
+	tableName    struct{}  `sql:"api_token"`
+	Id           int       `sql:"id,pk"`
+	UserId       int32     `sql:"user_id, notnull"`
+	Name         string    `sql:"name, notnull"`
+	Version      int       `sql:"version, notnull"`
+	Description  string    `sql:"description, notnull"`
+	ExpireAtInMs int64     `sql:"expire_at_in_ms"`
+	Token        string    `sql:"token, notnull"`
+	LastUsedAt   time.Time `sql:"last_used_at"`
+	LastUsedByIp string    `sql:"last_used_by_ip"`
+	// PreviousVersion is the version replaced by the last rotation, valid until PreviousVersionExpireAtInMs
+	PreviousVersion             int    `sql:"previous_version"`
+	PreviousVersionExpireAtInMs int64  `sql:"previous_version_expire_at_in_ms"`
+	Scope                       string `sql:"scope"`
+	User                        *repository.UserModel
 	sql.AuditLog
 }
 
@@ -44,6 +51,7 @@ type ApiTokenRepository interface {
 	FindActiveById(id int) (*ApiToken, error)
 	FindByName(name string) (*ApiToken, error)
 	UpdateIf(apiToken *ApiToken, previousTokenVersion int) error
+	UpdateLastUsed(id int, lastUsedAt time.Time, lastUsedByIp string) error
 }
 
 type ApiTokenRepositoryImpl struct {
@@ -107,3 +115,12 @@ func (impl ApiTokenRepositoryImpl) FindByName(name string) (*ApiToken, error) {
 		Select()
 	return apiToken, err
 }
+
+func (impl ApiTokenRepositoryImpl) UpdateLastUsed(id int, lastUsedAt time.Time, lastUsedByIp string) error {
+	_, err := impl.dbConnection.Model(&ApiToken{}).
+		Set("last_used_at = ?", lastUsedAt).
+		Set("last_used_by_ip = ?", lastUsedByIp).
+		Where("id = ?", id).
+		Update()
+	return err
+}
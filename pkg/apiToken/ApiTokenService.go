@@ -17,15 +17,21 @@
 package apiToken
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net"
+	"net/http"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/caarlos0/env"
+	"github.com/devtron-labs/devtron/internal/util"
 	userBean "github.com/devtron-labs/devtron/pkg/auth/user/bean"
+	userHelper "github.com/devtron-labs/devtron/pkg/auth/user/helper"
 
 	"github.com/devtron-labs/authenticator/middleware"
 	openapi "github.com/devtron-labs/devtron/api/openapi/openapiClient"
@@ -33,6 +39,7 @@ import (
 	"github.com/devtron-labs/devtron/pkg/sql"
 	"github.com/go-pg/pg"
 	"github.com/golang-jwt/jwt/v4"
+	"github.com/patrickmn/go-cache"
 	"go.uber.org/zap"
 )
 
@@ -42,6 +49,15 @@ type ApiTokenService interface {
 	UpdateApiToken(apiTokenId int, request *openapi.UpdateApiTokenRequest, updatedBy int32) (*openapi.UpdateApiTokenResponse, error)
 	DeleteApiToken(apiTokenId int, deletedBy int32) (*openapi.ActionResponse, error)
 	GetAllApiTokensForWebhook(projectName string, environmentName string, appName string, auth func(token string, projectObject string, envObject string) bool) ([]*openapi.ApiToken, error)
+	// RotateApiToken issues a new token, the replaced token stays valid for the overlap period
+	RotateApiToken(apiTokenId int, request *RotateApiTokenRequest, updatedBy int32) (*RotateApiTokenResponse, error)
+	GetApiTokenScope(apiTokenId int) (*ApiTokenScope, error)
+	UpdateApiTokenScope(apiTokenId int, scope *ApiTokenScope, updatedBy int32) (*ApiTokenScope, error)
+	// GetStaleApiTokens returns the active tokens not used for the given days, the configured days are used if not set
+	GetStaleApiTokens(unusedForDays int) ([]*StaleApiToken, error)
+	// AuthorizeApiTokenRequest checks a request made with the token of the api token user against the scope of the
+	// token and records the use of the token. The reason of a denied request is returned.
+	AuthorizeApiTokenRequest(email string, r *http.Request) (bool, string, error)
 }
 
 type ApiTokenServiceImpl struct {
@@ -51,6 +67,12 @@ type ApiTokenServiceImpl struct {
 	userAuditService      user2.UserAuditService
 	apiTokenRepository    ApiTokenRepository
 	TokenVariableConfig   *TokenVariableConfig
+	// apiTokenCache caches the tokens by name for the scope checks of requests
+	apiTokenCache *cache.Cache
+	// usageCache throttles the updates of the last use of the tokens by name
+	usageCache *cache.Cache
+	// trustedProxies are the proxies whose X-Forwarded-For header is used for the client ip of the requests
+	trustedProxies []*net.IPNet
 }
 
 func NewApiTokenServiceImpl(logger *zap.SugaredLogger,
@@ -65,6 +87,8 @@ func NewApiTokenServiceImpl(logger *zap.SugaredLogger,
 		userService:           userService,
 		userAuditService:      userAuditService,
 		apiTokenRepository:    apiTokenRepository,
+		apiTokenCache:         cache.New(apiTokenCacheExpiry, 5*time.Minute),
+		usageCache:            cache.New(usageRecordInterval, 5*time.Minute),
 	}
 
 	cfg, err := GetTokenConfig()
@@ -73,6 +97,11 @@ func NewApiTokenServiceImpl(logger *zap.SugaredLogger,
 		return nil, err
 	}
 	apiTokenService.TokenVariableConfig = cfg
+	apiTokenService.trustedProxies, err = parseTrustedProxies(cfg.TrustedProxyCidrs)
+	if err != nil {
+		apiTokenService.logger.Errorw("error while parsing trusted proxies of api token requests", "trustedProxyCidrs", cfg.TrustedProxyCidrs, "error", err)
+		return nil, err
+	}
 
 	return apiTokenService, nil
 }
@@ -84,7 +113,10 @@ func GetTokenConfig() (*TokenVariableConfig, error) {
 }
 
 type TokenVariableConfig struct {
-	HideApiTokens bool `env:"HIDE_API_TOKENS" envDefault:"false" description:"Boolean flag for should the api tokens generated be hidden from the UI"`
+	HideApiTokens          bool     `env:"HIDE_API_TOKENS" envDefault:"false" description:"Boolean flag for should the api tokens generated be hidden from the UI"`
+	RotationOverlapMinutes int      `env:"API_TOKEN_ROTATION_OVERLAP_MINUTES" envDefault:"60" description:"Default period in minutes for which the replaced api token stays valid after a rotation"`
+	StaleAfterDays         int      `env:"API_TOKEN_STALE_AFTER_DAYS" envDefault:"90" description:"Default days without use after which an api token is reported as stale"`
+	TrustedProxyCidrs      []string `env:"API_TOKEN_TRUSTED_PROXY_CIDRS" envDefault:"" envSeparator:"," description:"Comma separated cidrs of the proxies in front of devtron, the X-Forwarded-For header is used for the client ip of api token requests only if the request comes from these proxies" example:"10.0.0.0/8,192.168.1.10"`
 }
 
 var invalidCharsInApiTokenName = regexp.MustCompile("[,\\s]")
@@ -95,6 +127,13 @@ const (
 	TokenVersionMismatch          = "token version mismatch"
 )
 
+const (
+	// apiTokenCacheExpiry is the delay after which changes of the scope of a token made by other pods are applied
+	apiTokenCacheExpiry = 30 * time.Second
+	usageRecordInterval = time.Minute
+	maxClientIpLength   = 250
+)
+
 func (impl ApiTokenServiceImpl) GetAllApiTokensForWebhook(projectName string, environmentName string, appName string, auth func(token string, projectObject string, envObject string) bool) ([]*openapi.ApiToken, error) {
 	impl.logger.Info("Getting active api tokens")
 	apiTokensFromDb, err := impl.apiTokenRepository.FindAllActive()
@@ -173,7 +212,11 @@ func (impl ApiTokenServiceImpl) GetAllActiveApiTokens() ([]*openapi.ApiToken, er
 		if !impl.TokenVariableConfig.HideApiTokens {
 			apiToken.Token = &apiTokenFromDb.Token
 		}
-		if latestAuditLog != nil {
+		if !apiTokenFromDb.LastUsedAt.IsZero() {
+			lastUsedAtStr := apiTokenFromDb.LastUsedAt.String()
+			apiToken.LastUsedAt = &lastUsedAtStr
+			apiToken.LastUsedByIp = &apiTokenFromDb.LastUsedByIp
+		} else if latestAuditLog != nil {
 			lastUsedAtStr := latestAuditLog.CreatedOn.String()
 			apiToken.LastUsedAt = &lastUsedAtStr
 			apiToken.LastUsedByIp = &latestAuditLog.ClientIp
@@ -294,6 +337,8 @@ func (impl ApiTokenServiceImpl) CreateApiToken(request *openapi.CreateApiTokenRe
 		return nil, err
 	}
 
+	impl.apiTokenCache.Delete(name)
+
 	success := true
 	return &openapi.CreateApiTokenResponse{
 		Success:        &success,
@@ -342,6 +387,7 @@ func (impl ApiTokenServiceImpl) UpdateApiToken(apiTokenId int, request *openapi.
 		impl.logger.Errorw("error while updating api-token", "apiTokenId", apiTokenId, "error", err)
 		return nil, err
 	}
+	impl.apiTokenCache.Delete(apiToken.Name)
 
 	success := true
 	return &openapi.UpdateApiTokenResponse{
@@ -365,11 +411,14 @@ func (impl ApiTokenServiceImpl) DeleteApiToken(apiTokenId int, deletedBy int32)
 	}
 
 	apiToken.ExpireAtInMs = time.Now().UnixMilli()
+	apiToken.PreviousVersion = 0
+	apiToken.PreviousVersionExpireAtInMs = 0
 	err = impl.apiTokenRepository.Update(apiToken)
 	if err != nil && err != pg.ErrNoRows {
 		impl.logger.Errorw("error while getting api token by id", "apiTokenId", apiTokenId, "error", err)
 		return nil, err
 	}
+	impl.apiTokenCache.Delete(apiToken.Name)
 
 	// step-2 inactivate user corresponds to this api-token
 	deleteUserRequest := userBean.UserInfo{
@@ -391,6 +440,221 @@ func (impl ApiTokenServiceImpl) DeleteApiToken(apiTokenId int, deletedBy int32)
 
 }
 
+func (impl ApiTokenServiceImpl) RotateApiToken(apiTokenId int, request *RotateApiTokenRequest, updatedBy int32) (*RotateApiTokenResponse, error) {
+	impl.logger.Infow("Rotating API token", "request", request, "updatedBy", updatedBy, "apiTokenId", apiTokenId)
+	apiToken, err := impl.apiTokenRepository.FindActiveById(apiTokenId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error while getting api token by id", "apiTokenId", apiTokenId, "error", err)
+		return nil, err
+	}
+	if apiToken == nil || apiToken.Id == 0 {
+		return nil, pg.ErrNoRows
+	}
+	now := time.Now()
+	overlapMinutes := impl.TokenVariableConfig.RotationOverlapMinutes
+	if request.OverlapMinutes != nil {
+		overlapMinutes = *request.OverlapMinutes
+	}
+	expireAtInMs := apiToken.ExpireAtInMs
+	if request.ExpireAtInMs != nil {
+		expireAtInMs = *request.ExpireAtInMs
+	}
+	if expireAtInMs > 0 && expireAtInMs <= now.UnixMilli() {
+		return nil, util.NewApiError(http.StatusBadRequest, "expiry of the rotated token must be in the future", "expireAtInMs in the past")
+	}
+
+	previousTokenVersion := apiToken.Version
+	token, err := impl.createApiJwtToken(apiToken.User.EmailId, previousTokenVersion+1, expireAtInMs)
+	if err != nil {
+		return nil, err
+	}
+	apiToken.Token = token
+	apiToken.Version = previousTokenVersion + 1
+	apiToken.ExpireAtInMs = expireAtInMs
+	apiToken.PreviousVersion = 0
+	apiToken.PreviousVersionExpireAtInMs = 0
+	if overlapMinutes > 0 {
+		apiToken.PreviousVersion = previousTokenVersion
+		apiToken.PreviousVersionExpireAtInMs = now.Add(time.Duration(overlapMinutes) * time.Minute).UnixMilli()
+	}
+	apiToken.UpdatedBy = updatedBy
+	apiToken.UpdatedOn = now
+	err = impl.apiTokenRepository.UpdateIf(apiToken, previousTokenVersion)
+	if err != nil {
+		impl.logger.Errorw("error while rotating api-token", "apiTokenId", apiTokenId, "error", err)
+		if err.Error() == TokenVersionMismatch {
+			return nil, fmt.Errorf(ConcurrentTokenUpdateRequest)
+		}
+		return nil, err
+	}
+	impl.apiTokenCache.Delete(apiToken.Name)
+	return &RotateApiTokenResponse{
+		Token:                       token,
+		Version:                     apiToken.Version,
+		ExpireAtInMs:                expireAtInMs,
+		PreviousVersionExpireAtInMs: apiToken.PreviousVersionExpireAtInMs,
+		HideApiToken:                impl.TokenVariableConfig.HideApiTokens,
+	}, nil
+}
+
+func (impl ApiTokenServiceImpl) GetApiTokenScope(apiTokenId int) (*ApiTokenScope, error) {
+	apiToken, err := impl.apiTokenRepository.FindActiveById(apiTokenId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error while getting api token by id", "apiTokenId", apiTokenId, "error", err)
+		return nil, err
+	}
+	if apiToken == nil || apiToken.Id == 0 {
+		return nil, pg.ErrNoRows
+	}
+	return getScope(apiToken)
+}
+
+func (impl ApiTokenServiceImpl) UpdateApiTokenScope(apiTokenId int, scope *ApiTokenScope, updatedBy int32) (*ApiTokenScope, error) {
+	impl.logger.Infow("Updating API token scope", "scope", scope, "updatedBy", updatedBy, "apiTokenId", apiTokenId)
+	if err := validateScope(scope); err != nil {
+		return nil, util.NewApiError(http.StatusBadRequest, err.Error(), err.Error())
+	}
+	apiToken, err := impl.apiTokenRepository.FindActiveById(apiTokenId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error while getting api token by id", "apiTokenId", apiTokenId, "error", err)
+		return nil, err
+	}
+	if apiToken == nil || apiToken.Id == 0 {
+		return nil, pg.ErrNoRows
+	}
+	apiToken.Scope = ""
+	if !isScopeEmpty(scope) {
+		scopeJson, err := json.Marshal(scope)
+		if err != nil {
+			return nil, err
+		}
+		apiToken.Scope = string(scopeJson)
+	}
+	apiToken.UpdatedBy = updatedBy
+	apiToken.UpdatedOn = time.Now()
+	err = impl.apiTokenRepository.UpdateIf(apiToken, apiToken.Version)
+	if err != nil {
+		impl.logger.Errorw("error while updating api-token scope", "apiTokenId", apiTokenId, "error", err)
+		if err.Error() == TokenVersionMismatch {
+			return nil, fmt.Errorf(ConcurrentTokenUpdateRequest)
+		}
+		return nil, err
+	}
+	impl.apiTokenCache.Delete(apiToken.Name)
+	return scope, nil
+}
+
+func (impl ApiTokenServiceImpl) GetStaleApiTokens(unusedForDays int) ([]*StaleApiToken, error) {
+	if unusedForDays <= 0 {
+		unusedForDays = impl.TokenVariableConfig.StaleAfterDays
+	}
+	apiTokensFromDb, err := impl.apiTokenRepository.FindAllActive()
+	if err != nil {
+		impl.logger.Errorw("error while getting all active api tokens from DB", "error", err)
+		return nil, err
+	}
+	now := time.Now()
+	staleApiTokens := make([]*StaleApiToken, 0)
+	for _, apiTokenFromDb := range apiTokensFromDb {
+		if apiTokenFromDb.User == nil {
+			continue
+		}
+		lastUsedAt, lastUsedByIp := apiTokenFromDb.LastUsedAt, apiTokenFromDb.LastUsedByIp
+		if lastUsedAt.IsZero() {
+			// tokens used before the last use was recorded on the token have it in the user audit
+			latestAuditLog, err := impl.userAuditService.GetLatestByUserId(apiTokenFromDb.UserId)
+			if err != nil {
+				impl.logger.Errorw("error while getting latest audit log", "userId", apiTokenFromDb.UserId, "error", err)
+				return nil, err
+			}
+			if latestAuditLog != nil {
+				lastUsedAt, lastUsedByIp = latestAuditLog.CreatedOn, latestAuditLog.ClientIp
+			}
+		}
+		unusedSince := apiTokenFromDb.CreatedOn
+		if !lastUsedAt.IsZero() {
+			unusedSince = lastUsedAt
+		}
+		unusedDays := int(now.Sub(unusedSince).Hours() / 24)
+		if unusedDays < unusedForDays {
+			continue
+		}
+		staleApiToken := &StaleApiToken{
+			Id:             apiTokenFromDb.Id,
+			Name:           apiTokenFromDb.Name,
+			UserIdentifier: apiTokenFromDb.User.EmailId,
+			Description:    apiTokenFromDb.Description,
+			ExpireAtInMs:   apiTokenFromDb.ExpireAtInMs,
+			CreatedOn:      apiTokenFromDb.CreatedOn,
+			LastUsedByIp:   lastUsedByIp,
+			UnusedForDays:  unusedDays,
+		}
+		if !lastUsedAt.IsZero() {
+			staleApiToken.LastUsedAt = &lastUsedAt
+		}
+		staleApiTokens = append(staleApiTokens, staleApiToken)
+	}
+	sort.SliceStable(staleApiTokens, func(i, j int) bool {
+		return staleApiTokens[i].UnusedForDays > staleApiTokens[j].UnusedForDays
+	})
+	return staleApiTokens, nil
+}
+
+func (impl ApiTokenServiceImpl) AuthorizeApiTokenRequest(email string, r *http.Request) (bool, string, error) {
+	name, err := userHelper.ExtractTokenNameFromEmail(email)
+	if err != nil {
+		return false, "invalid api token", nil
+	}
+	apiToken, err := impl.getApiTokenByName(name)
+	if util.IsErrNoRows(err) {
+		// unknown tokens are rejected by the authentication of the request
+		return true, "", nil
+	} else if err != nil {
+		impl.logger.Errorw("error while getting api token by name", "name", name, "error", err)
+		return false, "", err
+	}
+	scope, err := getScope(apiToken)
+	if err != nil {
+		impl.logger.Errorw("error while getting api token scope", "name", name, "error", err)
+		return false, "", err
+	}
+	clientIp := getClientIp(r, impl.trustedProxies)
+	allowed, reason := isRequestInScope(scope, r.URL.Path, r.Method, clientIp)
+	if allowed {
+		impl.recordUsage(apiToken, clientIp)
+	}
+	return allowed, reason, nil
+}
+
+func (impl ApiTokenServiceImpl) getApiTokenByName(name string) (*ApiToken, error) {
+	if apiToken, found := impl.apiTokenCache.Get(name); found {
+		return apiToken.(*ApiToken), nil
+	}
+	apiToken, err := impl.apiTokenRepository.FindByName(name)
+	if err != nil {
+		return nil, err
+	}
+	impl.apiTokenCache.SetDefault(name, apiToken)
+	return apiToken, nil
+}
+
+// recordUsage updates the last use of the token at most once per usageRecordInterval
+func (impl ApiTokenServiceImpl) recordUsage(apiToken *ApiToken, clientIp string) {
+	if err := impl.usageCache.Add(apiToken.Name, true, cache.DefaultExpiration); err != nil {
+		// already recorded in the interval
+		return
+	}
+	if len(clientIp) > maxClientIpLength {
+		clientIp = clientIp[:maxClientIpLength]
+	}
+	go func() {
+		err := impl.apiTokenRepository.UpdateLastUsed(apiToken.Id, time.Now(), clientIp)
+		if err != nil {
+			impl.logger.Errorw("error while updating last use of api token", "name", apiToken.Name, "error", err)
+		}
+	}()
+}
+
 func (impl ApiTokenServiceImpl) createApiJwtToken(email string, tokenVersion int, expireAtInMs int64) (string, error) {
 	registeredClaims, secretByteArr, err := impl.setRegisteredClaims(expireAtInMs)
 	if err != nil {
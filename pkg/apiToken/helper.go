@@ -0,0 +1,195 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package apiToken
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+var validScopeMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodPost:    true,
+	http.MethodPut:     true,
+	http.MethodPatch:   true,
+	http.MethodDelete:  true,
+	http.MethodOptions: true,
+}
+
+// validateScope normalises the methods and cidrs of the scope and returns an error for invalid values
+func validateScope(scope *ApiTokenScope) error {
+	for _, apiPath := range scope.ApiPaths {
+		if !strings.HasPrefix(apiPath.Path, "/") {
+			return fmt.Errorf("api path %q must start with /", apiPath.Path)
+		}
+		for i, method := range apiPath.Methods {
+			method = strings.ToUpper(method)
+			if !validScopeMethods[method] {
+				return fmt.Errorf("invalid method %q for api path %q", apiPath.Methods[i], apiPath.Path)
+			}
+			apiPath.Methods[i] = method
+		}
+	}
+	for i, ipCidr := range scope.IpCidrs {
+		if _, _, err := net.ParseCIDR(ipCidr); err == nil {
+			continue
+		}
+		ip := net.ParseIP(ipCidr)
+		if ip == nil {
+			return fmt.Errorf("invalid ip cidr %q", ipCidr)
+		}
+		if ip.To4() != nil {
+			scope.IpCidrs[i] = ipCidr + "/32"
+		} else {
+			scope.IpCidrs[i] = ipCidr + "/128"
+		}
+	}
+	return nil
+}
+
+func getScope(apiToken *ApiToken) (*ApiTokenScope, error) {
+	scope := &ApiTokenScope{}
+	if len(apiToken.Scope) == 0 {
+		return scope, nil
+	}
+	if err := json.Unmarshal([]byte(apiToken.Scope), scope); err != nil {
+		return nil, fmt.Errorf("invalid scope of api token %s: %w", apiToken.Name, err)
+	}
+	return scope, nil
+}
+
+func isScopeEmpty(scope *ApiTokenScope) bool {
+	return len(scope.ApiPaths) == 0 && len(scope.IpCidrs) == 0
+}
+
+// isRequestInScope checks the request path, method and client ip against the scope of the token
+func isRequestInScope(scope *ApiTokenScope, path string, method string, clientIp string) (bool, string) {
+	if len(scope.ApiPaths) != 0 && !isApiPathInScope(scope.ApiPaths, path, method) {
+		return false, fmt.Sprintf("%s %s is not in the scope of the api token", method, path)
+	}
+	if len(scope.IpCidrs) != 0 && !isIpInScope(scope.IpCidrs, clientIp) {
+		return false, fmt.Sprintf("client ip %s is not in the scope of the api token", clientIp)
+	}
+	return true, ""
+}
+
+func isApiPathInScope(apiPaths []*ApiPathScope, path string, method string) bool {
+	for _, apiPath := range apiPaths {
+		if strings.HasSuffix(apiPath.Path, "*") {
+			if !strings.HasPrefix(path, strings.TrimSuffix(apiPath.Path, "*")) {
+				continue
+			}
+		} else if strings.TrimSuffix(path, "/") != strings.TrimSuffix(apiPath.Path, "/") {
+			continue
+		}
+		if len(apiPath.Methods) == 0 {
+			return true
+		}
+		for _, allowedMethod := range apiPath.Methods {
+			if strings.EqualFold(allowedMethod, method) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isIpInScope(ipCidrs []string, clientIp string) bool {
+	ip := net.ParseIP(clientIp)
+	if ip == nil {
+		return false
+	}
+	for _, ipCidr := range ipCidrs {
+		_, ipNet, err := net.ParseCIDR(ipCidr)
+		if err == nil && ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+const xForwardedForHeader = "X-Forwarded-For"
+
+// parseTrustedProxies parses the cidrs of the proxies in front of devtron, an ip is taken as a single address cidr
+func parseTrustedProxies(cidrs []string) ([]*net.IPNet, error) {
+	trustedProxies := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		cidr = strings.TrimSpace(cidr)
+		if len(cidr) == 0 {
+			continue
+		}
+		if ip := net.ParseIP(cidr); ip != nil {
+			trustedProxies = append(trustedProxies, &net.IPNet{IP: ip, Mask: net.CIDRMask(len(ip)*8, len(ip)*8)})
+			continue
+		}
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy cidr %q", cidr)
+		}
+		trustedProxies = append(trustedProxies, ipNet)
+	}
+	return trustedProxies, nil
+}
+
+// getClientIp returns the ip of the client of the request. X-Forwarded-For is considered only for the requests coming
+// from a trusted proxy, the client is then the right most address of the header which is not a trusted proxy, as the
+// addresses left of it are set by the client. An empty ip is returned if the client can not be determined.
+func getClientIp(r *http.Request, trustedProxies []*net.IPNet) string {
+	clientIp := parseIp(r.RemoteAddr)
+	if clientIp == nil {
+		return ""
+	}
+	if !isTrustedProxy(clientIp, trustedProxies) {
+		return clientIp.String()
+	}
+	hops := strings.Split(strings.Join(r.Header.Values(xForwardedForHeader), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if len(hop) == 0 {
+			continue
+		}
+		clientIp = parseIp(hop)
+		if clientIp == nil {
+			return ""
+		}
+		if !isTrustedProxy(clientIp, trustedProxies) {
+			break
+		}
+	}
+	return clientIp.String()
+}
+
+func isTrustedProxy(ip net.IP, trustedProxies []*net.IPNet) bool {
+	for _, trustedProxy := range trustedProxies {
+		if trustedProxy.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseIp parses an address with or without its port
+func parseIp(address string) net.IP {
+	if host, _, err := net.SplitHostPort(address); err == nil {
+		address = host
+	}
+	return net.ParseIP(address)
+}
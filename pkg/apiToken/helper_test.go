@@ -0,0 +1,150 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package apiToken
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestValidateScope(t *testing.T) {
+	scope := &ApiTokenScope{
+		ApiPaths: []*ApiPathScope{{Path: "/orchestrator/webhook/*", Methods: []string{"post"}}},
+		IpCidrs:  []string{"10.0.0.0/8", "192.168.1.10", "2001:db8::1"},
+	}
+	if err := validateScope(scope); err != nil {
+		t.Fatalf("validateScope() error = %v", err)
+	}
+	if !reflect.DeepEqual(scope.ApiPaths[0].Methods, []string{"POST"}) {
+		t.Errorf("validateScope() methods = %v, want [POST]", scope.ApiPaths[0].Methods)
+	}
+	wantCidrs := []string{"10.0.0.0/8", "192.168.1.10/32", "2001:db8::1/128"}
+	if !reflect.DeepEqual(scope.IpCidrs, wantCidrs) {
+		t.Errorf("validateScope() ip cidrs = %v, want %v", scope.IpCidrs, wantCidrs)
+	}
+
+	invalidScopes := []*ApiTokenScope{
+		{ApiPaths: []*ApiPathScope{{Path: "orchestrator/app"}}},
+		{ApiPaths: []*ApiPathScope{{Path: "/orchestrator/app", Methods: []string{"TRACE"}}}},
+		{IpCidrs: []string{"10.0.0.0/33"}},
+		{IpCidrs: []string{"not-an-ip"}},
+	}
+	for _, invalidScope := range invalidScopes {
+		if err := validateScope(invalidScope); err == nil {
+			t.Errorf("validateScope(%+v) expected error", invalidScope)
+		}
+	}
+}
+
+func TestIsRequestInScope(t *testing.T) {
+	scope := &ApiTokenScope{
+		ApiPaths: []*ApiPathScope{
+			{Path: "/orchestrator/webhook/*", Methods: []string{"POST"}},
+			{Path: "/orchestrator/app/list"},
+		},
+		IpCidrs: []string{"10.0.0.0/8"},
+	}
+	tests := []struct {
+		name     string
+		path     string
+		method   string
+		clientIp string
+		want     bool
+	}{
+		{name: "prefix path and method", path: "/orchestrator/webhook/ext-ci/12", method: "POST", clientIp: "10.1.2.3", want: true},
+		{name: "method not allowed", path: "/orchestrator/webhook/ext-ci/12", method: "GET", clientIp: "10.1.2.3"},
+		{name: "exact path any method", path: "/orchestrator/app/list/", method: "GET", clientIp: "10.1.2.3", want: true},
+		{name: "path not allowed", path: "/orchestrator/app/list/extra", method: "GET", clientIp: "10.1.2.3"},
+		{name: "ipv6 not in cidr", path: "/orchestrator/app/list", method: "GET", clientIp: "2001:db8::1"},
+		{name: "ip not in cidr", path: "/orchestrator/app/list", method: "GET", clientIp: "172.16.0.1"},
+		{name: "unknown ip", path: "/orchestrator/app/list", method: "GET", clientIp: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, reason := isRequestInScope(scope, tt.path, tt.method, tt.clientIp); got != tt.want {
+				t.Errorf("isRequestInScope() = %v (%s), want %v", got, reason, tt.want)
+			}
+		})
+	}
+	if got, _ := isRequestInScope(&ApiTokenScope{}, "/orchestrator/app", "DELETE", "172.16.0.1"); !got {
+		t.Errorf("isRequestInScope() denied request for empty scope")
+	}
+}
+
+func TestGetClientIp(t *testing.T) {
+	trustedProxies, err := parseTrustedProxies([]string{"192.168.0.0/16", " 172.16.0.1 ", ""})
+	if err != nil {
+		t.Fatalf("parseTrustedProxies() error = %v", err)
+	}
+	if _, err = parseTrustedProxies([]string{"192.168.0.0/33"}); err == nil {
+		t.Errorf("parseTrustedProxies() expected error for invalid cidr")
+	}
+	tests := []struct {
+		name         string
+		remoteAddr   string
+		forwardedFor []string
+		want         string
+	}{
+		{name: "direct request", remoteAddr: "10.1.2.3:5432", want: "10.1.2.3"},
+		{name: "forwarded for ignored from untrusted remote", remoteAddr: "172.20.0.1:5432", forwardedFor: []string{"10.1.2.3"}, want: "172.20.0.1"},
+		{name: "forwarded by trusted proxy", remoteAddr: "172.16.0.1:5432", forwardedFor: []string{"10.1.2.3"}, want: "10.1.2.3"},
+		{name: "right most untrusted hop", remoteAddr: "192.168.1.1:5432", forwardedFor: []string{"10.1.2.3, 172.20.0.1", "192.168.1.2"}, want: "172.20.0.1"},
+		{name: "only trusted hops", remoteAddr: "192.168.1.1:5432", forwardedFor: []string{"192.168.1.3, 172.16.0.1"}, want: "192.168.1.3"},
+		{name: "no forwarded for from trusted proxy", remoteAddr: "192.168.1.1:5432", want: "192.168.1.1"},
+		{name: "invalid hop", remoteAddr: "192.168.1.1:5432", forwardedFor: []string{"10.1.2.3, unknown"}, want: ""},
+		{name: "invalid remote address", remoteAddr: "unknown", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/orchestrator/app/list", nil)
+			r.RemoteAddr = tt.remoteAddr
+			for _, forwardedFor := range tt.forwardedFor {
+				r.Header.Add(xForwardedForHeader, forwardedFor)
+			}
+			if got := getClientIp(r, trustedProxies); got != tt.want {
+				t.Errorf("getClientIp() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSpoofedForwardedForIsRejected(t *testing.T) {
+	scope := &ApiTokenScope{IpCidrs: []string{"10.0.0.0/8"}}
+	trustedProxies, _ := parseTrustedProxies([]string{"192.168.0.0/16"})
+
+	// the client sets X-Forwarded-For itself to an address in the scope of the token
+	r := httptest.NewRequest(http.MethodGet, "/orchestrator/app/list", nil)
+	r.RemoteAddr = "172.20.0.1:5432"
+	r.Header.Set(xForwardedForHeader, "10.1.2.3")
+	if got, _ := isRequestInScope(scope, r.URL.Path, r.Method, getClientIp(r, trustedProxies)); got {
+		t.Errorf("isRequestInScope() allowed request with spoofed X-Forwarded-For from untrusted remote")
+	}
+
+	// the spoofed address is prepended to the header appended by the trusted proxy
+	r.RemoteAddr = "192.168.1.1:5432"
+	r.Header.Set(xForwardedForHeader, "10.1.2.3, 172.20.0.1")
+	if got, _ := isRequestInScope(scope, r.URL.Path, r.Method, getClientIp(r, trustedProxies)); got {
+		t.Errorf("isRequestInScope() allowed request with spoofed X-Forwarded-For behind trusted proxy")
+	}
+
+	r.Header.Set(xForwardedForHeader, "10.1.2.3")
+	if got, reason := isRequestInScope(scope, r.URL.Path, r.Method, getClientIp(r, trustedProxies)); !got {
+		t.Errorf("isRequestInScope() denied request forwarded by trusted proxy: %s", reason)
+	}
+}
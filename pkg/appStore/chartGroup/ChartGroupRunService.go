@@ -0,0 +1,664 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chartGroup
+
+import (
+	"context"
+	"fmt"
+	"github.com/caarlos0/env"
+	"github.com/devtron-labs/devtron/internal/util"
+	appStoreBean "github.com/devtron-labs/devtron/pkg/appStore/bean"
+	repository2 "github.com/devtron-labs/devtron/pkg/appStore/chartGroup/repository"
+	appStoreDiscoverRepository "github.com/devtron-labs/devtron/pkg/appStore/discover/repository"
+	"github.com/devtron-labs/devtron/pkg/appStore/installedApp/repository"
+	service2 "github.com/devtron-labs/devtron/pkg/appStore/installedApp/service"
+	"github.com/devtron-labs/devtron/pkg/appStore/installedApp/service/FullMode"
+	"github.com/devtron-labs/devtron/pkg/appStore/values/service"
+	"github.com/devtron-labs/devtron/pkg/eventProcessor/out"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	cron2 "github.com/devtron-labs/devtron/util/cron"
+	"github.com/go-pg/pg"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+	"net/http"
+	"sort"
+	"time"
+)
+
+type ChartGroupRunConfig struct {
+	ChartGroupRunCron               string `env:"CHART_GROUP_RUN_CRON" envDefault:"* * * * *" description:"Cron schedule for progressing the ordered installs and upgrades of chart groups"`
+	ChartGroupRunStepTimeoutMinutes int    `env:"CHART_GROUP_RUN_STEP_TIMEOUT_MINUTES" envDefault:"30" description:"Minutes an app of an ordered chart group install or upgrade has for becoming healthy before the run fails"`
+}
+
+type ChartGroupRunService interface {
+	// GetUpgradePlan returns the version changes of the apps of a group installation for matching the current entries
+	// of the group, the latest installation of the group is used if groupInstallationId is empty
+	GetUpgradePlan(chartGroupId int, groupInstallationId string) (*ChartGroupUpgradePlan, error)
+	// StartUpgradeRun upgrades the apps of a group installation one after the other in the order of the dependencies
+	// of their entries, the next app is upgraded only once the previous one is healthy
+	StartUpgradeRun(request *ChartGroupUpgradeRequest) (*ChartGroupRunDto, error)
+	// HasDependencies returns true if any entry of the group depends on another chart of the group
+	HasDependencies(chartGroupId int) (bool, error)
+	// CreateInstallRun saves an install run for the apps of a new group installation in the given transaction,
+	// the run is progressed by ProcessRun once the transaction is committed
+	CreateInstallRun(chartGroupId int, groupInstallationId string, installAppVersions []*appStoreBean.InstallAppVersionDTO, userId int32, tx *pg.Tx) (int, error)
+	// ProcessRun progresses the run as far as possible
+	ProcessRun(runId int)
+	GetRun(runId int) (*ChartGroupRunDto, error)
+	GetRuns(chartGroupId int, offset, limit int) ([]*ChartGroupRunDto, error)
+	// ProcessRunningRuns progresses all the running runs, called periodically
+	ProcessRunningRuns()
+}
+
+type ChartGroupRunServiceImpl struct {
+	logger                               *zap.SugaredLogger
+	chartGroupRunRepository              repository2.ChartGroupRunRepository
+	chartGroupRepository                 repository2.ChartGroupReposotory
+	chartGroupEntriesRepository          repository2.ChartGroupEntriesRepository
+	chartGroupDeploymentRepository       repository2.ChartGroupDeploymentRepository
+	installedAppRepository               repository.InstalledAppRepository
+	installedAppRepositoryHistory        repository.InstalledAppVersionHistoryRepository
+	appStoreApplicationVersionRepository appStoreDiscoverRepository.AppStoreApplicationVersionRepository
+	appStoreValuesService                service.AppStoreValuesService
+	appStoreDeploymentService            service2.AppStoreDeploymentService
+	appStoreDeploymentDBService          service2.AppStoreDeploymentDBService
+	installAppService                    FullMode.InstalledAppDBExtendedService
+	appStoreAppsEventPublishService      out.AppStoreAppsEventPublishService
+	stepTimeout                          time.Duration
+	cron                                 *cron.Cron
+}
+
+func NewChartGroupRunServiceImpl(logger *zap.SugaredLogger,
+	chartGroupRunRepository repository2.ChartGroupRunRepository,
+	chartGroupRepository repository2.ChartGroupReposotory,
+	chartGroupEntriesRepository repository2.ChartGroupEntriesRepository,
+	chartGroupDeploymentRepository repository2.ChartGroupDeploymentRepository,
+	installedAppRepository repository.InstalledAppRepository,
+	installedAppRepositoryHistory repository.InstalledAppVersionHistoryRepository,
+	appStoreApplicationVersionRepository appStoreDiscoverRepository.AppStoreApplicationVersionRepository,
+	appStoreValuesService service.AppStoreValuesService,
+	appStoreDeploymentService service2.AppStoreDeploymentService,
+	appStoreDeploymentDBService service2.AppStoreDeploymentDBService,
+	installAppService FullMode.InstalledAppDBExtendedService,
+	appStoreAppsEventPublishService out.AppStoreAppsEventPublishService,
+	cronLogger *cron2.CronLoggerImpl,
+) *ChartGroupRunServiceImpl {
+	impl := &ChartGroupRunServiceImpl{
+		logger:                               logger,
+		chartGroupRunRepository:              chartGroupRunRepository,
+		chartGroupRepository:                 chartGroupRepository,
+		chartGroupEntriesRepository:          chartGroupEntriesRepository,
+		chartGroupDeploymentRepository:       chartGroupDeploymentRepository,
+		installedAppRepository:               installedAppRepository,
+		installedAppRepositoryHistory:        installedAppRepositoryHistory,
+		appStoreApplicationVersionRepository: appStoreApplicationVersionRepository,
+		appStoreValuesService:                appStoreValuesService,
+		appStoreDeploymentService:            appStoreDeploymentService,
+		appStoreDeploymentDBService:          appStoreDeploymentDBService,
+		installAppService:                    installAppService,
+		appStoreAppsEventPublishService:      appStoreAppsEventPublishService,
+	}
+	cfg := &ChartGroupRunConfig{}
+	if err := env.Parse(cfg); err != nil {
+		logger.Errorw("error in parsing chart group run config", "err", err)
+		return impl
+	}
+	impl.stepTimeout = time.Duration(cfg.ChartGroupRunStepTimeoutMinutes) * time.Minute
+	impl.cron = cron.New(cron.WithChain(cron.Recover(cronLogger)))
+	impl.cron.Start()
+	_, err := impl.cron.AddFunc(cfg.ChartGroupRunCron, impl.ProcessRunningRuns)
+	if err != nil {
+		logger.Errorw("error in starting chart group run cron", "cron", cfg.ChartGroupRunCron, "err", err)
+	}
+	return impl
+}
+
+// getOrderedEntries returns the entries of the group in the order they are deployed
+func (impl *ChartGroupRunServiceImpl) getOrderedEntries(chartGroupId int) ([]*repository2.ChartGroupEntry, error) {
+	entries, err := impl.chartGroupEntriesRepository.FindEntriesWithChartMetaByChartGroupId([]int{chartGroupId})
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching chart group entries", "chartGroupId", chartGroupId, "err", err)
+		return nil, err
+	}
+	nodes := make([]*chartGroupEntryNode, 0, len(entries))
+	for _, entry := range entries {
+		nodes = append(nodes, &chartGroupEntryNode{
+			AppStoreId:           entry.AppStoreApplicationVersion.AppStoreId,
+			DependsOnAppStoreIds: entry.DependsOnAppStoreIds,
+		})
+	}
+	order, err := orderChartGroupEntries(nodes)
+	if err != nil {
+		return nil, util.NewApiError(http.StatusBadRequest, fmt.Sprintf("invalid dependencies between the charts of the group, %s", err.Error()), err.Error())
+	}
+	orderedEntries := make([]*repository2.ChartGroupEntry, 0, len(entries))
+	for _, index := range order {
+		orderedEntries = append(orderedEntries, entries[index])
+	}
+	return orderedEntries, nil
+}
+
+func (impl *ChartGroupRunServiceImpl) HasDependencies(chartGroupId int) (bool, error) {
+	entries, err := impl.chartGroupEntriesRepository.FindEntriesWithChartMetaByChartGroupId([]int{chartGroupId})
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching chart group entries", "chartGroupId", chartGroupId, "err", err)
+		return false, err
+	}
+	for _, entry := range entries {
+		if len(entry.DependsOnAppStoreIds) > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (impl *ChartGroupRunServiceImpl) GetUpgradePlan(chartGroupId int, groupInstallationId string) (*ChartGroupUpgradePlan, error) {
+	_, err := impl.chartGroupRepository.FindById(chartGroupId)
+	if err != nil {
+		if util.IsErrNoRows(err) {
+			return nil, util.NewApiError(http.StatusNotFound, "chart group not found", err.Error())
+		}
+		impl.logger.Errorw("error in fetching chart group", "chartGroupId", chartGroupId, "err", err)
+		return nil, err
+	}
+	deployments, err := impl.chartGroupDeploymentRepository.FindByChartGroupId(chartGroupId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching chart group deployments", "chartGroupId", chartGroupId, "err", err)
+		return nil, err
+	}
+	if len(groupInstallationId) == 0 {
+		var latestInstalledOn time.Time
+		for _, deployment := range deployments {
+			if deployment.CreatedOn.After(latestInstalledOn) {
+				latestInstalledOn = deployment.CreatedOn
+				groupInstallationId = deployment.GroupInstallationId
+			}
+		}
+	}
+	deploymentsByEntryId := make(map[int][]*repository2.ChartGroupDeployment)
+	for _, deployment := range deployments {
+		if deployment.GroupInstallationId == groupInstallationId {
+			deploymentsByEntryId[deployment.ChartGroupEntryId] = append(deploymentsByEntryId[deployment.ChartGroupEntryId], deployment)
+		}
+	}
+	if len(deploymentsByEntryId) == 0 {
+		return nil, util.NewApiError(http.StatusNotFound, "installation of the chart group not found", fmt.Sprintf("no deployments for chart group %d and installation %q", chartGroupId, groupInstallationId))
+	}
+	entries, err := impl.getOrderedEntries(chartGroupId)
+	if err != nil {
+		return nil, err
+	}
+	plan := &ChartGroupUpgradePlan{
+		ChartGroupId:        chartGroupId,
+		GroupInstallationId: groupInstallationId,
+		Items:               make([]*ChartGroupUpgradePlanItem, 0, len(entries)),
+	}
+	for _, entry := range entries {
+		entryDeployments := deploymentsByEntryId[entry.Id]
+		if len(entryDeployments) == 0 {
+			plan.Items = append(plan.Items, &ChartGroupUpgradePlanItem{
+				ChartGroupEntryId:                  entry.Id,
+				ChartName:                          entry.AppStoreApplicationVersion.Name,
+				TargetAppStoreApplicationVersionId: entry.AppStoreApplicationVersionId,
+				TargetVersion:                      entry.AppStoreApplicationVersion.Version,
+				AppStoreValuesVersionId:            entry.AppStoreValuesVersionId,
+				Action:                             ChartGroupUpgradeActionNotInstalled,
+			})
+			continue
+		}
+		for _, deployment := range entryDeployments {
+			installedAppVersion, err := impl.installedAppRepository.GetActiveInstalledAppVersionByInstalledAppId(deployment.InstalledAppId)
+			if err != nil {
+				if util.IsErrNoRows(err) {
+					// the app has been deleted since the group was installed
+					continue
+				}
+				impl.logger.Errorw("error in fetching active installed app version", "installedAppId", deployment.InstalledAppId, "err", err)
+				return nil, err
+			}
+			action := ChartGroupUpgradeActionNoChange
+			if installedAppVersion.AppStoreApplicationVersionId != entry.AppStoreApplicationVersionId {
+				action = ChartGroupUpgradeActionUpgrade
+			}
+			plan.Items = append(plan.Items, &ChartGroupUpgradePlanItem{
+				ChartGroupEntryId:                   entry.Id,
+				ChartName:                           entry.AppStoreApplicationVersion.Name,
+				InstalledAppId:                      deployment.InstalledAppId,
+				AppId:                               installedAppVersion.InstalledApp.AppId,
+				AppName:                             installedAppVersion.InstalledApp.App.AppName,
+				EnvironmentId:                       installedAppVersion.InstalledApp.EnvironmentId,
+				EnvironmentName:                     installedAppVersion.InstalledApp.Environment.Name,
+				CurrentAppStoreApplicationVersionId: installedAppVersion.AppStoreApplicationVersionId,
+				CurrentVersion:                      installedAppVersion.AppStoreApplicationVersion.Version,
+				TargetAppStoreApplicationVersionId:  entry.AppStoreApplicationVersionId,
+				TargetVersion:                       entry.AppStoreApplicationVersion.Version,
+				AppStoreValuesVersionId:             entry.AppStoreValuesVersionId,
+				Action:                              action,
+			})
+		}
+	}
+	return plan, nil
+}
+
+func (impl *ChartGroupRunServiceImpl) StartUpgradeRun(request *ChartGroupUpgradeRequest) (*ChartGroupRunDto, error) {
+	plan, err := impl.GetUpgradePlan(request.ChartGroupId, request.GroupInstallationId)
+	if err != nil {
+		return nil, err
+	}
+	runningRun, err := impl.chartGroupRunRepository.FindRunByGroupInstallationIdAndStatus(plan.GroupInstallationId, ChartGroupRunRunning.String())
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching running chart group run", "groupInstallationId", plan.GroupInstallationId, "err", err)
+		return nil, err
+	}
+	if runningRun != nil && runningRun.Id > 0 {
+		return nil, util.NewApiError(http.StatusConflict, fmt.Sprintf("run %d of the chart group installation is already in progress", runningRun.Id), "chart group run already running")
+	}
+	now := time.Now()
+	var steps []*repository2.ChartGroupRunStep
+	for _, item := range plan.Items {
+		if item.Action != ChartGroupUpgradeActionUpgrade {
+			continue
+		}
+		steps = append(steps, &repository2.ChartGroupRunStep{
+			StepOrder:                        len(steps) + 1,
+			ChartGroupEntryId:                item.ChartGroupEntryId,
+			InstalledAppId:                   item.InstalledAppId,
+			FromAppStoreApplicationVersionId: item.CurrentAppStoreApplicationVersionId,
+			ToAppStoreApplicationVersionId:   item.TargetAppStoreApplicationVersionId,
+			AppStoreValuesVersionId:          item.AppStoreValuesVersionId,
+			Status:                           ChartGroupRunStepPending.String(),
+			AuditLog:                         sql.NewDefaultAuditLog(request.UserId),
+		})
+	}
+	if len(steps) == 0 {
+		return nil, util.NewApiError(http.StatusBadRequest, "all the apps of the chart group installation are already on the versions of the group", "nothing to upgrade")
+	}
+	run := &repository2.ChartGroupRun{
+		ChartGroupId:        plan.ChartGroupId,
+		GroupInstallationId: plan.GroupInstallationId,
+		RunType:             ChartGroupRunUpgrade.String(),
+		Status:              ChartGroupRunRunning.String(),
+		StartedOn:           now,
+		AuditLog:            sql.NewDefaultAuditLog(request.UserId),
+	}
+	tx, err := impl.chartGroupRunRepository.StartTx()
+	if err != nil {
+		impl.logger.Errorw("error in starting transaction", "err", err)
+		return nil, err
+	}
+	defer impl.chartGroupRunRepository.RollbackTx(tx)
+	if err = impl.saveRun(run, steps, tx); err != nil {
+		return nil, err
+	}
+	if err = impl.chartGroupRunRepository.CommitTx(tx); err != nil {
+		impl.logger.Errorw("error in committing transaction", "err", err)
+		return nil, err
+	}
+	impl.processRun(run)
+	return impl.GetRun(run.Id)
+}
+
+func (impl *ChartGroupRunServiceImpl) CreateInstallRun(chartGroupId int, groupInstallationId string, installAppVersions []*appStoreBean.InstallAppVersionDTO, userId int32, tx *pg.Tx) (int, error) {
+	entries, err := impl.getOrderedEntries(chartGroupId)
+	if err != nil {
+		return 0, err
+	}
+	entryRanks := make(map[int]int, len(entries))
+	for rank, entry := range entries {
+		entryRanks[entry.Id] = rank
+	}
+	getRank := func(installAppVersion *appStoreBean.InstallAppVersionDTO) int {
+		if rank, ok := entryRanks[installAppVersion.ChartGroupEntryId]; ok {
+			return rank
+		}
+		// charts installed along with the group without being an entry of it do not wait for anything
+		return -1
+	}
+	orderedInstallAppVersions := make([]*appStoreBean.InstallAppVersionDTO, len(installAppVersions))
+	copy(orderedInstallAppVersions, installAppVersions)
+	sort.SliceStable(orderedInstallAppVersions, func(i, j int) bool {
+		return getRank(orderedInstallAppVersions[i]) < getRank(orderedInstallAppVersions[j])
+	})
+	steps := make([]*repository2.ChartGroupRunStep, 0, len(orderedInstallAppVersions))
+	for i, installAppVersion := range orderedInstallAppVersions {
+		steps = append(steps, &repository2.ChartGroupRunStep{
+			StepOrder:                      i + 1,
+			ChartGroupEntryId:              installAppVersion.ChartGroupEntryId,
+			InstalledAppId:                 installAppVersion.InstalledAppId,
+			InstalledAppVersionId:          installAppVersion.InstalledAppVersionId,
+			InstalledAppVersionHistoryId:   installAppVersion.InstalledAppVersionHistoryId,
+			ToAppStoreApplicationVersionId: installAppVersion.AppStoreVersion,
+			Status:                         ChartGroupRunStepPending.String(),
+			AuditLog:                       sql.NewDefaultAuditLog(userId),
+		})
+	}
+	run := &repository2.ChartGroupRun{
+		ChartGroupId:        chartGroupId,
+		GroupInstallationId: groupInstallationId,
+		RunType:             ChartGroupRunInstall.String(),
+		Status:              ChartGroupRunRunning.String(),
+		StartedOn:           time.Now(),
+		AuditLog:            sql.NewDefaultAuditLog(userId),
+	}
+	if err = impl.saveRun(run, steps, tx); err != nil {
+		return 0, err
+	}
+	return run.Id, nil
+}
+
+func (impl *ChartGroupRunServiceImpl) saveRun(run *repository2.ChartGroupRun, steps []*repository2.ChartGroupRunStep, tx *pg.Tx) error {
+	if err := impl.chartGroupRunRepository.SaveRun(run, tx); err != nil {
+		impl.logger.Errorw("error in saving chart group run", "chartGroupId", run.ChartGroupId, "err", err)
+		return err
+	}
+	for _, step := range steps {
+		step.RunId = run.Id
+	}
+	if err := impl.chartGroupRunRepository.SaveRunSteps(steps, tx); err != nil {
+		impl.logger.Errorw("error in saving chart group run steps", "runId", run.Id, "err", err)
+		return err
+	}
+	return nil
+}
+
+func (impl *ChartGroupRunServiceImpl) ProcessRun(runId int) {
+	run, err := impl.chartGroupRunRepository.FindRunById(runId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching chart group run", "runId", runId, "err", err)
+		return
+	}
+	if run.Status == ChartGroupRunRunning.String() {
+		impl.processRun(run)
+	}
+}
+
+func (impl *ChartGroupRunServiceImpl) ProcessRunningRuns() {
+	runs, err := impl.chartGroupRunRepository.FindRunsByStatus(ChartGroupRunRunning.String())
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching running chart group runs", "err", err)
+		return
+	}
+	for _, run := range runs {
+		impl.processRun(run)
+	}
+}
+
+// processRun progresses the run through its steps as far as possible: a step is deployed only once all the
+// previous steps have succeeded, and the next step is deployed in the same call if the current one is already healthy
+func (impl *ChartGroupRunServiceImpl) processRun(run *repository2.ChartGroupRun) {
+	steps, err := impl.chartGroupRunRepository.FindRunStepsByRunId(run.Id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching chart group run steps", "runId", run.Id, "err", err)
+		return
+	}
+	for _, step := range steps {
+		if step.Status == ChartGroupRunStepSucceeded.String() {
+			continue
+		}
+		if step.Status == ChartGroupRunStepPending.String() {
+			// the step is claimed with its start time, so that a step whose deployment was never saved still times out
+			startedOn := time.Now()
+			claimed, err := impl.chartGroupRunRepository.ClaimRunStep(step.Id, ChartGroupRunStepPending.String(), ChartGroupRunStepDeploying.String(), startedOn, run.UpdatedBy)
+			if err != nil {
+				impl.logger.Errorw("error in claiming chart group run step", "runId", run.Id, "stepId", step.Id, "err", err)
+				return
+			}
+			if !claimed {
+				// the step is being deployed by another replica
+				return
+			}
+			step.StartedOn = &startedOn
+			if err = impl.triggerRunStep(run, step); err != nil {
+				impl.logger.Errorw("error in deploying chart group run step", "runId", run.Id, "stepId", step.Id, "err", err)
+				impl.failRun(run, step, fmt.Sprintf("deployment could not be triggered, %s", err.Error()))
+				return
+			}
+		}
+		if step.Status != ChartGroupRunStepDeploying.String() {
+			return
+		}
+		status, message, err := impl.getDeployingStepStatus(step)
+		if err != nil {
+			impl.logger.Errorw("error in fetching status of chart group run step", "runId", run.Id, "stepId", step.Id, "err", err)
+			return
+		}
+		switch status {
+		case ChartGroupRunStepFailed:
+			impl.failRun(run, step, message)
+			return
+		case ChartGroupRunStepDeploying:
+			startedOn := step.UpdatedOn
+			if step.StartedOn != nil {
+				startedOn = *step.StartedOn
+			}
+			if impl.stepTimeout > 0 && time.Since(startedOn) > impl.stepTimeout {
+				impl.failRun(run, step, fmt.Sprintf("app did not become healthy within %s", impl.stepTimeout.String()))
+			}
+			return
+		}
+		now := time.Now()
+		step.Status = ChartGroupRunStepSucceeded.String()
+		step.FinishedOn = &now
+		step.UpdateAuditLog(run.UpdatedBy)
+		if err = impl.chartGroupRunRepository.UpdateRunStep(step); err != nil {
+			impl.logger.Errorw("error in updating chart group run step", "stepId", step.Id, "err", err)
+			return
+		}
+	}
+	now := time.Now()
+	if _, err = impl.chartGroupRunRepository.UpdateRunStatus(run.Id, ChartGroupRunRunning.String(), ChartGroupRunSucceeded.String(), "", &now, run.UpdatedBy); err != nil {
+		impl.logger.Errorw("error in updating chart group run status", "runId", run.Id, "err", err)
+	}
+}
+
+// triggerRunStep deploys the app of a claimed step and marks the step deploying
+func (impl *ChartGroupRunServiceImpl) triggerRunStep(run *repository2.ChartGroupRun, step *repository2.ChartGroupRunStep) error {
+	step.Status = ChartGroupRunStepDeploying.String()
+	var err error
+	if run.RunType == ChartGroupRunInstall.String() {
+		err = impl.triggerInstallStep(step)
+	} else {
+		err = impl.triggerUpgradeStep(step, run.UpdatedBy)
+	}
+	if err != nil {
+		return err
+	}
+	step.UpdateAuditLog(run.UpdatedBy)
+	return impl.chartGroupRunRepository.UpdateRunStep(step)
+}
+
+// triggerInstallStep publishes the deploy event of an app saved by the bulk install of the group
+func (impl *ChartGroupRunServiceImpl) triggerInstallStep(step *repository2.ChartGroupRunStep) error {
+	installAppVersion := &appStoreBean.InstallAppVersionDTO{
+		InstalledAppId:               step.InstalledAppId,
+		InstalledAppVersionId:        step.InstalledAppVersionId,
+		InstalledAppVersionHistoryId: step.InstalledAppVersionHistoryId,
+	}
+	publishErrMap := impl.appStoreAppsEventPublishService.PublishBulkDeployEvent([]*appStoreBean.InstallAppVersionDTO{installAppVersion})
+	publishErr, ok := publishErrMap[step.InstalledAppVersionId]
+	status := appStoreBean.ENQUEUED
+	if !ok || publishErr != nil {
+		status = appStoreBean.QUE_ERROR
+	}
+	if _, err := impl.appStoreDeploymentDBService.AppStoreDeployOperationStatusUpdate(step.InstalledAppId, status); err != nil {
+		impl.logger.Errorw("error in updating installed app status", "installedAppId", step.InstalledAppId, "status", status, "err", err)
+	}
+	if !ok {
+		return fmt.Errorf("deploy event not published")
+	}
+	return publishErr
+}
+
+// triggerUpgradeStep upgrades the app to the version of the step with the values of the entry, the current values
+// of the app are kept if the entry uses the default values and the app was not deployed with the default values
+func (impl *ChartGroupRunServiceImpl) triggerUpgradeStep(step *repository2.ChartGroupRunStep, userId int32) error {
+	installedAppVersion, err := impl.installedAppRepository.GetActiveInstalledAppVersionByInstalledAppId(step.InstalledAppId)
+	if err != nil {
+		return err
+	}
+	upgradeRequest, err := impl.installAppService.GetInstalledAppVersion(installedAppVersion.Id, userId)
+	if err != nil {
+		return err
+	}
+	targetVersion, err := impl.appStoreApplicationVersionRepository.FindById(step.ToAppStoreApplicationVersionId)
+	if err != nil {
+		return err
+	}
+	isChartChanged := targetVersion.AppStoreId != installedAppVersion.AppStoreApplicationVersion.AppStoreId
+	if isChartChanged {
+		upgradeRequest.Id = 0
+	}
+	upgradeRequest.AppStoreVersion = step.ToAppStoreApplicationVersionId
+	if step.AppStoreValuesVersionId > 0 {
+		values, err := impl.appStoreValuesService.FindValuesByIdAndKind(step.AppStoreValuesVersionId, appStoreBean.REFERENCE_TYPE_TEMPLATE)
+		if err != nil {
+			return err
+		}
+		upgradeRequest.ValuesOverrideYaml = values.Values
+		upgradeRequest.ReferenceValueId = step.AppStoreValuesVersionId
+		upgradeRequest.ReferenceValueKind = appStoreBean.REFERENCE_TYPE_TEMPLATE
+	} else if isChartChanged || upgradeRequest.ReferenceValueKind == appStoreBean.REFERENCE_TYPE_DEFAULT {
+		values, err := impl.appStoreValuesService.FindValuesByIdAndKind(step.ToAppStoreApplicationVersionId, appStoreBean.REFERENCE_TYPE_DEFAULT)
+		if err != nil {
+			return err
+		}
+		upgradeRequest.ValuesOverrideYaml = values.Values
+		upgradeRequest.ReferenceValueId = step.ToAppStoreApplicationVersionId
+		upgradeRequest.ReferenceValueKind = appStoreBean.REFERENCE_TYPE_DEFAULT
+	}
+	upgradeRequest.UserId = userId
+	res, err := impl.appStoreDeploymentService.UpdateInstalledApp(context.Background(), upgradeRequest)
+	if err != nil {
+		return err
+	}
+	step.InstalledAppVersionId = res.InstalledAppVersionId
+	step.InstalledAppVersionHistoryId = res.InstalledAppVersionHistoryId
+	return nil
+}
+
+func (impl *ChartGroupRunServiceImpl) getDeployingStepStatus(step *repository2.ChartGroupRunStep) (ChartGroupRunStepStatus, string, error) {
+	installedApp, err := impl.installedAppRepository.GetInstalledApp(step.InstalledAppId)
+	if err != nil {
+		if util.IsErrNoRows(err) {
+			return ChartGroupRunStepFailed, "app has been deleted", nil
+		}
+		return "", "", err
+	}
+	history, err := impl.installedAppRepositoryHistory.GetInstalledAppVersionHistory(step.InstalledAppVersionHistoryId)
+	if err != nil {
+		return "", "", err
+	}
+	status, message := getRunStepStatus(history.Status, installedApp.Status)
+	return status, message, nil
+}
+
+// failRun fails the step and the run, the steps not deployed yet are skipped
+func (impl *ChartGroupRunServiceImpl) failRun(run *repository2.ChartGroupRun, step *repository2.ChartGroupRunStep, message string) {
+	now := time.Now()
+	step.Status = ChartGroupRunStepFailed.String()
+	step.Message = message
+	step.FinishedOn = &now
+	step.UpdateAuditLog(run.UpdatedBy)
+	if err := impl.chartGroupRunRepository.UpdateRunStep(step); err != nil {
+		impl.logger.Errorw("error in updating chart group run step", "stepId", step.Id, "err", err)
+	}
+	if err := impl.chartGroupRunRepository.UpdatePendingRunStepsStatus(run.Id, ChartGroupRunStepPending.String(), ChartGroupRunStepSkipped.String(), run.UpdatedBy); err != nil {
+		impl.logger.Errorw("error in skipping pending chart group run steps", "runId", run.Id, "err", err)
+	}
+	runMessage := fmt.Sprintf("step %d failed, %s", step.StepOrder, message)
+	if _, err := impl.chartGroupRunRepository.UpdateRunStatus(run.Id, ChartGroupRunRunning.String(), ChartGroupRunFailed.String(), runMessage, &now, run.UpdatedBy); err != nil {
+		impl.logger.Errorw("error in updating chart group run status", "runId", run.Id, "err", err)
+	}
+}
+
+func (impl *ChartGroupRunServiceImpl) GetRun(runId int) (*ChartGroupRunDto, error) {
+	run, err := impl.chartGroupRunRepository.FindRunById(runId)
+	if err != nil {
+		if util.IsErrNoRows(err) {
+			return nil, util.NewApiError(http.StatusNotFound, "chart group run not found", err.Error())
+		}
+		impl.logger.Errorw("error in fetching chart group run", "runId", runId, "err", err)
+		return nil, err
+	}
+	steps, err := impl.chartGroupRunRepository.FindRunStepsByRunId(runId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching chart group run steps", "runId", runId, "err", err)
+		return nil, err
+	}
+	installedAppIds := make([]int, 0, len(steps))
+	for _, step := range steps {
+		installedAppIds = append(installedAppIds, step.InstalledAppId)
+	}
+	installedAppsById := make(map[int]*repository.InstalledApps)
+	if len(installedAppIds) > 0 {
+		installedApps, err := impl.installedAppRepository.FindInstalledAppByIds(installedAppIds)
+		if err != nil && !util.IsErrNoRows(err) {
+			impl.logger.Errorw("error in fetching installed apps", "installedAppIds", installedAppIds, "err", err)
+			return nil, err
+		}
+		for _, installedApp := range installedApps {
+			installedAppsById[installedApp.Id] = installedApp
+		}
+	}
+	runDto := toChartGroupRunDto(run)
+	for _, step := range steps {
+		stepDto := &ChartGroupRunStepDto{
+			Id:                               step.Id,
+			StepOrder:                        step.StepOrder,
+			ChartGroupEntryId:                step.ChartGroupEntryId,
+			InstalledAppId:                   step.InstalledAppId,
+			FromAppStoreApplicationVersionId: step.FromAppStoreApplicationVersionId,
+			ToAppStoreApplicationVersionId:   step.ToAppStoreApplicationVersionId,
+			Status:                           ChartGroupRunStepStatus(step.Status),
+			Message:                          step.Message,
+			StartedOn:                        step.StartedOn,
+			FinishedOn:                       step.FinishedOn,
+		}
+		if installedApp, ok := installedAppsById[step.InstalledAppId]; ok {
+			stepDto.AppName = installedApp.App.AppName
+			stepDto.EnvironmentName = installedApp.Environment.Name
+		}
+		runDto.Steps = append(runDto.Steps, stepDto)
+	}
+	return runDto, nil
+}
+
+func (impl *ChartGroupRunServiceImpl) GetRuns(chartGroupId int, offset, limit int) ([]*ChartGroupRunDto, error) {
+	runs, err := impl.chartGroupRunRepository.FindRunsByChartGroupId(chartGroupId, offset, limit)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching chart group runs", "chartGroupId", chartGroupId, "err", err)
+		return nil, err
+	}
+	runDtos := make([]*ChartGroupRunDto, 0, len(runs))
+	for _, run := range runs {
+		runDtos = append(runDtos, toChartGroupRunDto(run))
+	}
+	return runDtos, nil
+}
+
+func toChartGroupRunDto(run *repository2.ChartGroupRun) *ChartGroupRunDto {
+	return &ChartGroupRunDto{
+		Id:                  run.Id,
+		ChartGroupId:        run.ChartGroupId,
+		GroupInstallationId: run.GroupInstallationId,
+		RunType:             ChartGroupRunType(run.RunType),
+		Status:              ChartGroupRunStatus(run.Status),
+		Message:             run.Message,
+		StartedOn:           run.StartedOn,
+		FinishedOn:          run.FinishedOn,
+	}
+}
@@ -42,6 +42,7 @@ import (
 	"github.com/devtron-labs/devtron/pkg/team/read"
 	repository3 "github.com/devtron-labs/devtron/pkg/team/repository"
 	"io/ioutil"
+	"net/http"
 	"os"
 	"strconv"
 	"strings"
@@ -81,6 +82,7 @@ type ChartGroupServiceImpl struct {
 	installAppService                    FullMode.InstalledAppDBExtendedService
 	appStoreAppsEventPublishService      out.AppStoreAppsEventPublishService
 	teamReadService                      read.TeamReadService
+	chartGroupRunService                 ChartGroupRunService
 }
 
 func NewChartGroupServiceImpl(logger *zap.SugaredLogger,
@@ -104,7 +106,8 @@ func NewChartGroupServiceImpl(logger *zap.SugaredLogger,
 	gitOperationService git.GitOperationService,
 	installAppService FullMode.InstalledAppDBExtendedService,
 	appStoreAppsEventPublishService out.AppStoreAppsEventPublishService,
-	teamReadService read.TeamReadService) (*ChartGroupServiceImpl, error) {
+	teamReadService read.TeamReadService,
+	chartGroupRunService ChartGroupRunService) (*ChartGroupServiceImpl, error) {
 	impl := &ChartGroupServiceImpl{
 		logger:                               logger,
 		chartGroupEntriesRepository:          chartGroupEntriesRepository,
@@ -128,6 +131,7 @@ func NewChartGroupServiceImpl(logger *zap.SugaredLogger,
 		appStoreAppsEventPublishService:      appStoreAppsEventPublishService,
 		appStoreRepository:                   appStoreRepository,
 		teamReadService:                      teamReadService,
+		chartGroupRunService:                 chartGroupRunService,
 	}
 	return impl, nil
 }
@@ -169,6 +173,8 @@ type ChartGroupEntryBean struct {
 	AppStoreApplicationVersionId int            `json:"appStoreApplicationVersionId,omitempty"` //AppStoreApplicationVersionId
 	ChartMetaData                *ChartMetaData `json:"chartMetaData,omitempty"`
 	ReferenceType                string         `json:"referenceType, omitempty"`
+	// DependsOnAppStoreIds are the charts of the group which are installed and healthy before this chart is deployed
+	DependsOnAppStoreIds []int `json:"dependsOnAppStoreIds,omitempty"`
 }
 
 type ChartMetaData struct {
@@ -183,8 +189,9 @@ type ChartMetaData struct {
 }
 
 type InstalledChartData struct {
-	InstallationTime time.Time         `json:"installationTime,omitempty"`
-	InstalledCharts  []*InstalledChart `json:"installedCharts,omitempty"`
+	GroupInstallationId string            `json:"groupInstallationId,omitempty"`
+	InstallationTime    time.Time         `json:"installationTime,omitempty"`
+	InstalledCharts     []*InstalledChart `json:"installedCharts,omitempty"`
 }
 
 type InstalledChart struct {
@@ -270,6 +277,7 @@ func (impl *ChartGroupServiceImpl) SaveChartGroupEntries(req *ChartGroupBean) (*
 			//update
 			existingEntry.AppStoreApplicationVersionId = entry.AppStoreApplicationVersionId
 			existingEntry.AppStoreValuesVersionId = entry.AppStoreValuesVersionId
+			existingEntry.DependsOnAppStoreIds = entry.DependsOnAppStoreIds
 		} else {
 			//delete
 			existingEntry.Deleted = true
@@ -285,6 +293,7 @@ func (impl *ChartGroupServiceImpl) SaveChartGroupEntries(req *ChartGroupBean) (*
 			AppStoreValuesVersionId:      entryBean.AppStoreValuesVersionId,
 			AppStoreApplicationVersionId: entryBean.AppStoreApplicationVersionId,
 			ChartGroupId:                 group.Id,
+			DependsOnAppStoreIds:         entryBean.DependsOnAppStoreIds,
 			Deleted:                      false,
 			AuditLog: sql.AuditLog{
 				CreatedOn: time.Now(),
@@ -295,6 +304,9 @@ func (impl *ChartGroupServiceImpl) SaveChartGroupEntries(req *ChartGroupBean) (*
 		}
 		createEntries = append(createEntries, entry)
 	}
+	if err = impl.validateEntryDependencies(req.ChartGroupEntries); err != nil {
+		return nil, err
+	}
 	finalEntries, err := impl.chartGroupEntriesRepository.SaveAndUpdateInTransaction(createEntries, updateEntries)
 	if err != nil {
 		impl.logger.Errorw("error in adding entries", "err", err)
@@ -304,6 +316,37 @@ func (impl *ChartGroupServiceImpl) SaveChartGroupEntries(req *ChartGroupBean) (*
 	return impl.GetChartGroupWithChartMetaData(req.Id)
 }
 
+// validateEntryDependencies checks that the entries depend only on the other charts of the group and without a cycle
+func (impl *ChartGroupServiceImpl) validateEntryDependencies(entries []*ChartGroupEntryBean) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	appStoreApplicationVersionIds := make([]int, 0, len(entries))
+	for _, entry := range entries {
+		appStoreApplicationVersionIds = append(appStoreApplicationVersionIds, entry.AppStoreApplicationVersionId)
+	}
+	appStoreApplicationVersions, err := impl.appStoreApplicationVersionRepository.FindByIds(appStoreApplicationVersionIds)
+	if err != nil {
+		impl.logger.Errorw("error in fetching app store application versions", "ids", appStoreApplicationVersionIds, "err", err)
+		return err
+	}
+	appStoreIds := make(map[int]int, len(appStoreApplicationVersions))
+	for _, appStoreApplicationVersion := range appStoreApplicationVersions {
+		appStoreIds[appStoreApplicationVersion.Id] = appStoreApplicationVersion.AppStoreId
+	}
+	nodes := make([]*chartGroupEntryNode, 0, len(entries))
+	for _, entry := range entries {
+		nodes = append(nodes, &chartGroupEntryNode{
+			AppStoreId:           appStoreIds[entry.AppStoreApplicationVersionId],
+			DependsOnAppStoreIds: entry.DependsOnAppStoreIds,
+		})
+	}
+	if _, err = orderChartGroupEntries(nodes); err != nil {
+		return util.NewApiError(http.StatusBadRequest, fmt.Sprintf("invalid dependencies between the charts of the group, %s", err.Error()), err.Error())
+	}
+	return nil
+}
+
 func (impl *ChartGroupServiceImpl) GetChartGroupWithChartMetaData(chartGroupId int) (*ChartGroupBean, error) {
 	chartGroup, err := impl.chartGroupRepository.FindById(chartGroupId)
 	if err != nil {
@@ -363,6 +406,7 @@ func (impl *ChartGroupServiceImpl) charterEntryAdopter(chartGroupEntry *reposito
 		ReferenceType:                referenceType,
 		AppStoreValuesVersionName:    valueVersionName,
 		AppStoreValuesChartVersion:   appStoreValuesChartVersion,
+		DependsOnAppStoreIds:         chartGroupEntry.DependsOnAppStoreIds,
 		ChartMetaData: &ChartMetaData{
 			ChartName:                  chartGroupEntry.AppStoreApplicationVersion.Name,
 			ChartRepoName:              chartRepoName,
@@ -428,8 +472,8 @@ func (impl *ChartGroupServiceImpl) GetChartGroupWithInstallationDetail(chartGrou
 	for _, deployment := range deployments {
 		groupDeploymentMap[deployment.GroupInstallationId] = append(groupDeploymentMap[deployment.GroupInstallationId], deployment)
 	}
-	for _, groupDeployments := range groupDeploymentMap {
-		installedChartData := &InstalledChartData{}
+	for groupInstallationId, groupDeployments := range groupDeploymentMap {
+		installedChartData := &InstalledChartData{GroupInstallationId: groupInstallationId}
 		//installedChartData.InstallationTime
 		for _, deployment := range groupDeployments {
 			installedChartData.InstallationTime = deployment.CreatedOn
@@ -575,12 +619,21 @@ func (impl *ChartGroupServiceImpl) DeployBulk(chartGroupInstallRequest *ChartGro
 		}
 		installAppVersionDTOList = append(installAppVersionDTOList, installAppVersionDTO)
 	}
+	isOrderedInstall := false
+	if chartGroupInstallRequest.ChartGroupId > 0 {
+		var err error
+		isOrderedInstall, err = impl.chartGroupRunService.HasDependencies(chartGroupInstallRequest.ChartGroupId)
+		if err != nil {
+			return nil, err
+		}
+	}
 	dbConnection := impl.installedAppRepository.GetConnection()
 	tx, err := dbConnection.Begin()
 	if err != nil {
 		return nil, err
 	}
 	var installAppVersions []*appStoreBean.InstallAppVersionDTO
+	var runId int
 	// Rollback tx on error.
 	defer tx.Rollback()
 	for _, installAppVersionDTO := range installAppVersionDTOList {
@@ -604,6 +657,13 @@ func (impl *ChartGroupServiceImpl) DeployBulk(chartGroupInstallRequest *ChartGro
 				return nil, err
 			}
 		}
+		if isOrderedInstall {
+			runId, err = impl.chartGroupRunService.CreateInstallRun(chartGroupInstallRequest.ChartGroupId, groupINstallationId, installAppVersions, chartGroupInstallRequest.UserId, tx)
+			if err != nil {
+				impl.logger.Errorw("DeployBulk, error in creating chart group install run", "err", err)
+				return nil, err
+			}
+		}
 	}
 	//commit transaction
 	err = tx.Commit()
@@ -611,6 +671,11 @@ func (impl *ChartGroupServiceImpl) DeployBulk(chartGroupInstallRequest *ChartGro
 		impl.logger.Errorw("DeployBulk, error in tx commit", "err", err)
 		return nil, err
 	}
+	if isOrderedInstall {
+		// the apps are deployed one after the other by the run instead of all at once
+		impl.chartGroupRunService.ProcessRun(runId)
+		return &ChartGroupInstallAppRes{RunId: runId}, nil
+	}
 	//nats event
 	impl.TriggerDeploymentEventAndHandleStatusUpdate(installAppVersions)
 	// TODO refactoring: why empty obj ??
@@ -16,6 +16,8 @@
 
 package chartGroup
 
+import "time"
+
 // / bean for v2
 type ChartGroupInstallRequest struct {
 	ProjectId                     int                              `json:"projectId"  validate:"required,number"`
@@ -42,6 +44,8 @@ type ChartGroupInstallMetadata struct {
 type ChartGroupInstallAppRes struct {
 	ChartGroupInstallMetadata []ChartGroupInstallMetadata `json:"chartGroupInstallMetadata"`
 	Summary                   string                      `json:"summary"`
+	// RunId is set when the charts are installed one after the other in the order of their dependencies
+	RunId int `json:"runId,omitempty"`
 }
 type TriggerStatus string
 type Reason string
@@ -53,3 +57,107 @@ const (
 	ReasonNotAuthorize Reason        = "not authorized"
 	ReasonTriggered    Reason        = "triggered"
 )
+
+type ChartGroupRunType string
+
+const (
+	ChartGroupRunInstall ChartGroupRunType = "INSTALL"
+	ChartGroupRunUpgrade ChartGroupRunType = "UPGRADE"
+)
+
+func (t ChartGroupRunType) String() string {
+	return string(t)
+}
+
+type ChartGroupRunStatus string
+
+const (
+	ChartGroupRunRunning   ChartGroupRunStatus = "RUNNING"
+	ChartGroupRunSucceeded ChartGroupRunStatus = "SUCCEEDED"
+	ChartGroupRunFailed    ChartGroupRunStatus = "FAILED"
+)
+
+func (s ChartGroupRunStatus) String() string {
+	return string(s)
+}
+
+type ChartGroupRunStepStatus string
+
+const (
+	ChartGroupRunStepPending   ChartGroupRunStepStatus = "PENDING"
+	ChartGroupRunStepDeploying ChartGroupRunStepStatus = "DEPLOYING"
+	ChartGroupRunStepSucceeded ChartGroupRunStepStatus = "SUCCEEDED"
+	ChartGroupRunStepFailed    ChartGroupRunStepStatus = "FAILED"
+	// ChartGroupRunStepSkipped is for the steps not deployed because a previous step failed
+	ChartGroupRunStepSkipped ChartGroupRunStepStatus = "SKIPPED"
+)
+
+func (s ChartGroupRunStepStatus) String() string {
+	return string(s)
+}
+
+type ChartGroupUpgradeAction string
+
+const (
+	ChartGroupUpgradeActionUpgrade  ChartGroupUpgradeAction = "UPGRADE"
+	ChartGroupUpgradeActionNoChange ChartGroupUpgradeAction = "NO_CHANGE"
+	// ChartGroupUpgradeActionNotInstalled is for the entries added to the group after it was installed
+	ChartGroupUpgradeActionNotInstalled ChartGroupUpgradeAction = "NOT_INSTALLED"
+)
+
+type ChartGroupUpgradePlan struct {
+	ChartGroupId        int                          `json:"chartGroupId"`
+	GroupInstallationId string                       `json:"groupInstallationId"`
+	Items               []*ChartGroupUpgradePlanItem `json:"items"`
+}
+
+// ChartGroupUpgradePlanItem is the change of an entry of the group, the items are in the order they are upgraded
+type ChartGroupUpgradePlanItem struct {
+	ChartGroupEntryId                   int                     `json:"chartGroupEntryId"`
+	ChartName                           string                  `json:"chartName"`
+	InstalledAppId                      int                     `json:"installedAppId,omitempty"`
+	AppId                               int                     `json:"appId,omitempty"`
+	AppName                             string                  `json:"appName,omitempty"`
+	EnvironmentId                       int                     `json:"environmentId,omitempty"`
+	EnvironmentName                     string                  `json:"environmentName,omitempty"`
+	CurrentAppStoreApplicationVersionId int                     `json:"currentAppStoreApplicationVersionId,omitempty"`
+	CurrentVersion                      string                  `json:"currentVersion,omitempty"`
+	TargetAppStoreApplicationVersionId  int                     `json:"targetAppStoreApplicationVersionId"`
+	TargetVersion                       string                  `json:"targetVersion"`
+	AppStoreValuesVersionId             int                     `json:"appStoreValuesVersionId,omitempty"`
+	Action                              ChartGroupUpgradeAction `json:"action"`
+}
+
+type ChartGroupUpgradeRequest struct {
+	ChartGroupId int `json:"chartGroupId" validate:"required,number"`
+	// GroupInstallationId is the installation of the group to upgrade, the latest installation if not set
+	GroupInstallationId string `json:"groupInstallationId"`
+	UserId              int32  `json:"-"`
+}
+
+type ChartGroupRunDto struct {
+	Id                  int                     `json:"id"`
+	ChartGroupId        int                     `json:"chartGroupId"`
+	GroupInstallationId string                  `json:"groupInstallationId"`
+	RunType             ChartGroupRunType       `json:"runType"`
+	Status              ChartGroupRunStatus     `json:"status"`
+	Message             string                  `json:"message,omitempty"`
+	StartedOn           time.Time               `json:"startedOn"`
+	FinishedOn          *time.Time              `json:"finishedOn,omitempty"`
+	Steps               []*ChartGroupRunStepDto `json:"steps,omitempty"`
+}
+
+type ChartGroupRunStepDto struct {
+	Id                               int                     `json:"id"`
+	StepOrder                        int                     `json:"stepOrder"`
+	ChartGroupEntryId                int                     `json:"chartGroupEntryId,omitempty"`
+	InstalledAppId                   int                     `json:"installedAppId"`
+	AppName                          string                  `json:"appName"`
+	EnvironmentName                  string                  `json:"environmentName"`
+	FromAppStoreApplicationVersionId int                     `json:"fromAppStoreApplicationVersionId,omitempty"`
+	ToAppStoreApplicationVersionId   int                     `json:"toAppStoreApplicationVersionId"`
+	Status                           ChartGroupRunStepStatus `json:"status"`
+	Message                          string                  `json:"message,omitempty"`
+	StartedOn                        *time.Time              `json:"startedOn,omitempty"`
+	FinishedOn                       *time.Time              `json:"finishedOn,omitempty"`
+}
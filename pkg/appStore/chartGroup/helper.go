@@ -0,0 +1,119 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chartGroup
+
+import (
+	"fmt"
+	"github.com/devtron-labs/common-lib/utils/k8s/health"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig/bean/workflow/cdWorkflow"
+	appStoreBean "github.com/devtron-labs/devtron/pkg/appStore/bean"
+	"strings"
+)
+
+// chartGroupEntryNode is an entry of a chart group in its dependency graph
+type chartGroupEntryNode struct {
+	AppStoreId           int
+	DependsOnAppStoreIds []int
+}
+
+// orderChartGroupEntries returns the indexes of the entries in the order they are deployed, every entry comes after
+// the entries of the charts it depends on and the entries without an order between them keep their given order.
+// Returns an error if an entry depends on a chart which is not in the group, on its own chart or on a cycle.
+func orderChartGroupEntries(entries []*chartGroupEntryNode) ([]int, error) {
+	entryIndexesByAppStoreId := make(map[int][]int)
+	for i, entry := range entries {
+		entryIndexesByAppStoreId[entry.AppStoreId] = append(entryIndexesByAppStoreId[entry.AppStoreId], i)
+	}
+	for _, entry := range entries {
+		for _, appStoreId := range entry.DependsOnAppStoreIds {
+			if appStoreId == entry.AppStoreId {
+				return nil, fmt.Errorf("chart %d cannot depend on itself", appStoreId)
+			}
+			if _, ok := entryIndexesByAppStoreId[appStoreId]; !ok {
+				return nil, fmt.Errorf("chart %d depends on chart %d which is not in the group", entry.AppStoreId, appStoreId)
+			}
+		}
+	}
+	order := make([]int, 0, len(entries))
+	ordered := make([]bool, len(entries))
+	for len(order) < len(entries) {
+		progressed := false
+		for i, entry := range entries {
+			if ordered[i] || !areDependenciesOrdered(entry, entryIndexesByAppStoreId, ordered) {
+				continue
+			}
+			ordered[i] = true
+			order = append(order, i)
+			progressed = true
+		}
+		if !progressed {
+			var cyclicAppStoreIds []string
+			for i, entry := range entries {
+				if !ordered[i] {
+					cyclicAppStoreIds = append(cyclicAppStoreIds, fmt.Sprint(entry.AppStoreId))
+				}
+			}
+			return nil, fmt.Errorf("dependency cycle between charts %s", strings.Join(cyclicAppStoreIds, ", "))
+		}
+	}
+	return order, nil
+}
+
+func areDependenciesOrdered(entry *chartGroupEntryNode, entryIndexesByAppStoreId map[int][]int, ordered []bool) bool {
+	for _, appStoreId := range entry.DependsOnAppStoreIds {
+		for _, index := range entryIndexesByAppStoreId[appStoreId] {
+			if !ordered[index] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func hasDependencies(entries []*chartGroupEntryNode) bool {
+	for _, entry := range entries {
+		if len(entry.DependsOnAppStoreIds) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// failedInstalledAppStatuses are the statuses of an installed app for which its deployment was never started
+var failedInstalledAppStatuses = map[appStoreBean.AppstoreDeploymentStatus]bool{
+	appStoreBean.QUE_ERROR:     true,
+	appStoreBean.DEQUE_ERROR:   true,
+	appStoreBean.TRIGGER_ERROR: true,
+	appStoreBean.GIT_ERROR:     true,
+	appStoreBean.ACD_ERROR:     true,
+	appStoreBean.HELM_ERROR:    true,
+}
+
+// getRunStepStatus resolves the status of a deploying step from the status of its deployment history and of the
+// installed app, the step succeeds once the deployment is healthy
+func getRunStepStatus(historyStatus string, installedAppStatus appStoreBean.AppstoreDeploymentStatus) (ChartGroupRunStepStatus, string) {
+	if failedInstalledAppStatuses[installedAppStatus] {
+		return ChartGroupRunStepFailed, fmt.Sprintf("deployment could not be triggered, %s", installedAppStatus.String())
+	}
+	switch historyStatus {
+	case cdWorkflow.WorkflowSucceeded, string(health.HealthStatusHealthy):
+		return ChartGroupRunStepSucceeded, ""
+	case cdWorkflow.WorkflowFailed, cdWorkflow.WorkflowAborted, cdWorkflow.WorkflowTimedOut, string(health.HealthStatusDegraded):
+		return ChartGroupRunStepFailed, fmt.Sprintf("deployment is %s", historyStatus)
+	}
+	return ChartGroupRunStepDeploying, ""
+}
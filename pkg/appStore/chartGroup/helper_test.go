@@ -0,0 +1,83 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chartGroup
+
+import (
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig/bean/workflow/cdWorkflow"
+	appStoreBean "github.com/devtron-labs/devtron/pkg/appStore/bean"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestOrderChartGroupEntries(t *testing.T) {
+	t.Run("dependencies are deployed first", func(t *testing.T) {
+		order, err := orderChartGroupEntries([]*chartGroupEntryNode{
+			{AppStoreId: 1, DependsOnAppStoreIds: []int{2, 3}},
+			{AppStoreId: 2, DependsOnAppStoreIds: []int{3}},
+			{AppStoreId: 3},
+			{AppStoreId: 4},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []int{2, 3, 1, 0}, order)
+	})
+	t.Run("entries without dependencies keep their order", func(t *testing.T) {
+		order, err := orderChartGroupEntries([]*chartGroupEntryNode{{AppStoreId: 5}, {AppStoreId: 3}, {AppStoreId: 4}})
+		assert.NoError(t, err)
+		assert.Equal(t, []int{0, 1, 2}, order)
+	})
+	t.Run("all the entries of a chart are deployed before its dependents", func(t *testing.T) {
+		order, err := orderChartGroupEntries([]*chartGroupEntryNode{
+			{AppStoreId: 1, DependsOnAppStoreIds: []int{2}},
+			{AppStoreId: 2},
+			{AppStoreId: 2},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []int{1, 2, 0}, order)
+	})
+	t.Run("cycle", func(t *testing.T) {
+		_, err := orderChartGroupEntries([]*chartGroupEntryNode{
+			{AppStoreId: 1, DependsOnAppStoreIds: []int{2}},
+			{AppStoreId: 2, DependsOnAppStoreIds: []int{1}},
+			{AppStoreId: 3},
+		})
+		assert.EqualError(t, err, "dependency cycle between charts 1, 2")
+	})
+	t.Run("dependency outside the group", func(t *testing.T) {
+		_, err := orderChartGroupEntries([]*chartGroupEntryNode{{AppStoreId: 1, DependsOnAppStoreIds: []int{9}}})
+		assert.Error(t, err)
+	})
+	t.Run("dependency on itself", func(t *testing.T) {
+		_, err := orderChartGroupEntries([]*chartGroupEntryNode{{AppStoreId: 1, DependsOnAppStoreIds: []int{1}}})
+		assert.Error(t, err)
+	})
+}
+
+func TestGetRunStepStatus(t *testing.T) {
+	status, _ := getRunStepStatus(cdWorkflow.WorkflowSucceeded, appStoreBean.DEPLOY_SUCCESS)
+	assert.Equal(t, ChartGroupRunStepSucceeded, status)
+	status, _ = getRunStepStatus("Healthy", appStoreBean.DEPLOY_SUCCESS)
+	assert.Equal(t, ChartGroupRunStepSucceeded, status)
+	status, _ = getRunStepStatus("Progressing", appStoreBean.DEPLOY_SUCCESS)
+	assert.Equal(t, ChartGroupRunStepDeploying, status)
+	status, _ = getRunStepStatus(cdWorkflow.WorkflowInProgress, appStoreBean.ENQUEUED)
+	assert.Equal(t, ChartGroupRunStepDeploying, status)
+	status, message := getRunStepStatus("Degraded", appStoreBean.DEPLOY_SUCCESS)
+	assert.Equal(t, ChartGroupRunStepFailed, status)
+	assert.Equal(t, "deployment is Degraded", message)
+	status, _ = getRunStepStatus(cdWorkflow.WorkflowInProgress, appStoreBean.QUE_ERROR)
+	assert.Equal(t, ChartGroupRunStepFailed, status)
+}
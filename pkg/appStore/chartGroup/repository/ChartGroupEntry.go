@@ -32,6 +32,8 @@ type ChartGroupEntry struct {
 	AppStoreApplicationVersionId int      `sql:"app_store_application_version_id"` //AppStoreApplicationVersionId
 	ChartGroupId                 int      `sql:"chart_group_id"`
 	Deleted                      bool     `sql:"deleted,notnull"`
+	// DependsOnAppStoreIds are the charts of the group which are deployed before the chart of this entry
+	DependsOnAppStoreIds []int `sql:"depends_on_app_store_ids" pg:",array"`
 	sql.AuditLog
 	AppStoreApplicationVersion *appStoreDiscoverRepository.AppStoreApplicationVersion
 	AppStoreValuesVersion      *appStoreValuesRepository.AppStoreVersionValues
@@ -0,0 +1,210 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+// ChartGroupRun is an ordered install or upgrade of the apps of a chart group installation.
+// The whole state of the run is persisted so that it is progressed by any replica.
+type ChartGroupRun struct {
+	TableName           struct{}   `sql:"chart_group_run" pg:",discard_unknown_columns"`
+	Id                  int        `sql:"id,pk"`
+	ChartGroupId        int        `sql:"chart_group_id,notnull"`
+	GroupInstallationId string     `sql:"group_installation_id,notnull"`
+	RunType             string     `sql:"run_type,notnull"`
+	Status              string     `sql:"status,notnull"`
+	Message             string     `sql:"message"`
+	StartedOn           time.Time  `sql:"started_on,notnull"`
+	FinishedOn          *time.Time `sql:"finished_on"`
+	sql.AuditLog
+}
+
+// ChartGroupRunStep is the deployment of an installed app in a run, the target version and values of the entry are
+// copied when the run starts so that editing the group does not impact the runs in progress
+type ChartGroupRunStep struct {
+	TableName                        struct{}   `sql:"chart_group_run_step" pg:",discard_unknown_columns"`
+	Id                               int        `sql:"id,pk"`
+	RunId                            int        `sql:"run_id,notnull"`
+	StepOrder                        int        `sql:"step_order,notnull"`
+	ChartGroupEntryId                int        `sql:"chart_group_entry_id"`
+	InstalledAppId                   int        `sql:"installed_app_id,notnull"`
+	InstalledAppVersionId            int        `sql:"installed_app_version_id"`
+	InstalledAppVersionHistoryId     int        `sql:"installed_app_version_history_id"`
+	FromAppStoreApplicationVersionId int        `sql:"from_app_store_application_version_id"`
+	ToAppStoreApplicationVersionId   int        `sql:"to_app_store_application_version_id,notnull"`
+	AppStoreValuesVersionId          int        `sql:"app_store_values_version_id"`
+	Status                           string     `sql:"status,notnull"`
+	Message                          string     `sql:"message"`
+	StartedOn                        *time.Time `sql:"started_on"`
+	FinishedOn                       *time.Time `sql:"finished_on"`
+	sql.AuditLog
+}
+
+type ChartGroupRunRepository interface {
+	//transaction util funcs
+	sql.TransactionWrapper
+	SaveRun(run *ChartGroupRun, tx *pg.Tx) error
+	// UpdateRunStatus moves the run to status only if it is in fromStatus, returns false if it was not
+	UpdateRunStatus(id int, fromStatus string, status string, message string, finishedOn *time.Time, userId int32) (bool, error)
+	FindRunById(id int) (*ChartGroupRun, error)
+	FindRunsByStatus(status string) ([]*ChartGroupRun, error)
+	FindRunsByChartGroupId(chartGroupId int, offset, limit int) ([]*ChartGroupRun, error)
+	// FindRunByGroupInstallationIdAndStatus returns the latest run of the group installation in the given status
+	FindRunByGroupInstallationIdAndStatus(groupInstallationId string, status string) (*ChartGroupRun, error)
+
+	SaveRunSteps(steps []*ChartGroupRunStep, tx *pg.Tx) error
+	UpdateRunStep(step *ChartGroupRunStep) error
+	// ClaimRunStep moves the step to status and sets its start time only if it is in fromStatus, returns false if it was not.
+	// Used for claiming the deployment of a step so that the same app is not deployed twice by different replicas.
+	ClaimRunStep(id int, fromStatus string, status string, startedOn time.Time, userId int32) (bool, error)
+	// UpdatePendingRunStepsStatus moves all the pending steps of the run to status
+	UpdatePendingRunStepsStatus(runId int, pendingStatus string, status string, userId int32) error
+	FindRunStepsByRunId(runId int) ([]*ChartGroupRunStep, error)
+}
+
+type ChartGroupRunRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+	*sql.TransactionUtilImpl
+}
+
+func NewChartGroupRunRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger, transactionUtilImpl *sql.TransactionUtilImpl) *ChartGroupRunRepositoryImpl {
+	return &ChartGroupRunRepositoryImpl{
+		dbConnection:        dbConnection,
+		logger:              logger,
+		TransactionUtilImpl: transactionUtilImpl,
+	}
+}
+
+func (repo *ChartGroupRunRepositoryImpl) SaveRun(run *ChartGroupRun, tx *pg.Tx) error {
+	return tx.Insert(run)
+}
+
+func (repo *ChartGroupRunRepositoryImpl) UpdateRunStatus(id int, fromStatus string, status string, message string, finishedOn *time.Time, userId int32) (bool, error) {
+	query := repo.dbConnection.Model(&ChartGroupRun{}).
+		Set("status = ?", status).
+		Set("message = ?", message).
+		Set("updated_on = ?", time.Now()).
+		Set("updated_by = ?", userId)
+	if finishedOn != nil {
+		query = query.Set("finished_on = ?", finishedOn)
+	}
+	result, err := query.
+		Where("id = ?", id).
+		Where("status = ?", fromStatus).
+		Update()
+	if err != nil {
+		repo.logger.Errorw("error in updating chart group run status", "id", id, "status", status, "err", err)
+		return false, err
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+func (repo *ChartGroupRunRepositoryImpl) FindRunById(id int) (*ChartGroupRun, error) {
+	run := &ChartGroupRun{}
+	err := repo.dbConnection.Model(run).
+		Where("id = ?", id).
+		Select()
+	return run, err
+}
+
+func (repo *ChartGroupRunRepositoryImpl) FindRunsByStatus(status string) ([]*ChartGroupRun, error) {
+	var runs []*ChartGroupRun
+	err := repo.dbConnection.Model(&runs).
+		Where("status = ?", status).
+		Order("id ASC").
+		Select()
+	return runs, err
+}
+
+func (repo *ChartGroupRunRepositoryImpl) FindRunsByChartGroupId(chartGroupId int, offset, limit int) ([]*ChartGroupRun, error) {
+	var runs []*ChartGroupRun
+	err := repo.dbConnection.Model(&runs).
+		Where("chart_group_id = ?", chartGroupId).
+		Order("id DESC").
+		Offset(offset).
+		Limit(limit).
+		Select()
+	return runs, err
+}
+
+func (repo *ChartGroupRunRepositoryImpl) FindRunByGroupInstallationIdAndStatus(groupInstallationId string, status string) (*ChartGroupRun, error) {
+	run := &ChartGroupRun{}
+	err := repo.dbConnection.Model(run).
+		Where("group_installation_id = ?", groupInstallationId).
+		Where("status = ?", status).
+		Order("id DESC").
+		Limit(1).
+		Select()
+	return run, err
+}
+
+func (repo *ChartGroupRunRepositoryImpl) SaveRunSteps(steps []*ChartGroupRunStep, tx *pg.Tx) error {
+	if len(steps) == 0 {
+		return nil
+	}
+	_, err := tx.Model(&steps).Insert()
+	return err
+}
+
+func (repo *ChartGroupRunRepositoryImpl) UpdateRunStep(step *ChartGroupRunStep) error {
+	return repo.dbConnection.Update(step)
+}
+
+func (repo *ChartGroupRunRepositoryImpl) ClaimRunStep(id int, fromStatus string, status string, startedOn time.Time, userId int32) (bool, error) {
+	result, err := repo.dbConnection.Model(&ChartGroupRunStep{}).
+		Set("status = ?", status).
+		Set("started_on = ?", startedOn).
+		Set("updated_on = ?", time.Now()).
+		Set("updated_by = ?", userId).
+		Where("id = ?", id).
+		Where("status = ?", fromStatus).
+		Update()
+	if err != nil {
+		repo.logger.Errorw("error in updating chart group run step status", "id", id, "status", status, "err", err)
+		return false, err
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+func (repo *ChartGroupRunRepositoryImpl) UpdatePendingRunStepsStatus(runId int, pendingStatus string, status string, userId int32) error {
+	_, err := repo.dbConnection.Model(&ChartGroupRunStep{}).
+		Set("status = ?", status).
+		Set("updated_on = ?", time.Now()).
+		Set("updated_by = ?", userId).
+		Where("run_id = ?", runId).
+		Where("status = ?", pendingStatus).
+		Update()
+	if err != nil {
+		repo.logger.Errorw("error in updating pending chart group run steps status", "runId", runId, "status", status, "err", err)
+	}
+	return err
+}
+
+func (repo *ChartGroupRunRepositoryImpl) FindRunStepsByRunId(runId int) ([]*ChartGroupRunStep, error) {
+	var steps []*ChartGroupRunStep
+	err := repo.dbConnection.Model(&steps).
+		Where("run_id = ?", runId).
+		Order("step_order ASC").
+		Select()
+	return steps, err
+}
@@ -0,0 +1,118 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+// InstalledAppDrift is the latest drift check of an installed app, Diff is the json of the values and the resources
+// which differ from the deployment InstalledAppVersionHistoryId
+type InstalledAppDrift struct {
+	tableName                    struct{}   `sql:"installed_app_drift" pg:",discard_unknown_columns"`
+	Id                           int        `sql:"id,pk"`
+	InstalledAppId               int        `sql:"installed_app_id,notnull"`
+	InstalledAppVersionHistoryId int        `sql:"installed_app_version_history_id"`
+	Status                       string     `sql:"status,notnull"`
+	ValuesDrifted                bool       `sql:"values_drifted,notnull"`
+	DriftedResourceCount         int        `sql:"drifted_resource_count,notnull"`
+	Diff                         string     `sql:"diff"`
+	Message                      string     `sql:"message"`
+	CheckedOn                    time.Time  `sql:"checked_on,notnull"`
+	DetectedOn                   *time.Time `sql:"detected_on"`
+	ReconciledOn                 *time.Time `sql:"reconciled_on"`
+	sql.AuditLog
+}
+
+type InstalledAppDriftRepository interface {
+	Save(drift *InstalledAppDrift) error
+	Update(drift *InstalledAppDrift) error
+	FindByInstalledAppId(installedAppId int) (*InstalledAppDrift, error)
+	// UpdateStatus moves the drift to status only if it is in fromStatus, returns false if it was not.
+	// Used for claiming the reconciliation so that the same drift is not reconciled twice by different replicas.
+	UpdateStatus(id int, fromStatus string, status string, userId int32) (bool, error)
+	// FindActiveInstalledAppIds returns the ids of the installed apps deployed on non virtual environments
+	FindActiveInstalledAppIds() ([]int, error)
+}
+
+type InstalledAppDriftRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewInstalledAppDriftRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *InstalledAppDriftRepositoryImpl {
+	return &InstalledAppDriftRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (repo *InstalledAppDriftRepositoryImpl) Save(drift *InstalledAppDrift) error {
+	return repo.dbConnection.Insert(drift)
+}
+
+func (repo *InstalledAppDriftRepositoryImpl) Update(drift *InstalledAppDrift) error {
+	return repo.dbConnection.Update(drift)
+}
+
+func (repo *InstalledAppDriftRepositoryImpl) FindByInstalledAppId(installedAppId int) (*InstalledAppDrift, error) {
+	drift := &InstalledAppDrift{}
+	err := repo.dbConnection.Model(drift).
+		Where("installed_app_id = ?", installedAppId).
+		Select()
+	return drift, err
+}
+
+func (repo *InstalledAppDriftRepositoryImpl) UpdateStatus(id int, fromStatus string, status string, userId int32) (bool, error) {
+	result, err := repo.dbConnection.Model(&InstalledAppDrift{}).
+		Set("status = ?", status).
+		Set("updated_on = ?", time.Now()).
+		Set("updated_by = ?", userId).
+		Where("id = ?", id).
+		Where("status = ?", fromStatus).
+		Update()
+	if err != nil {
+		repo.logger.Errorw("error in updating installed app drift status", "id", id, "status", status, "err", err)
+		return false, err
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+func (repo *InstalledAppDriftRepositoryImpl) FindActiveInstalledAppIds() ([]int, error) {
+	var installedAppIds []int
+	query := `
+		SELECT ia.id
+		FROM installed_apps ia
+		INNER JOIN app a ON a.id = ia.app_id
+		INNER JOIN environment e ON e.id = ia.environment_id
+		WHERE ia.active = true
+			AND a.active = true
+			AND e.active = true
+			AND e.is_virtual_environment = false
+			AND ia.deployment_app_delete_request = false
+		ORDER BY ia.id
+	`
+	_, err := repo.dbConnection.Query(&installedAppIds, query)
+	if err != nil {
+		repo.logger.Errorw("error in fetching active installed app ids", "err", err)
+		return nil, err
+	}
+	return installedAppIds, nil
+}
@@ -0,0 +1,440 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package drift
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/caarlos0/env"
+	k8sUtil "github.com/devtron-labs/common-lib/utils/k8s"
+	"github.com/devtron-labs/common-lib/utils/k8s/health"
+	yamlUtil "github.com/devtron-labs/common-lib/utils/yaml"
+	"github.com/devtron-labs/devtron/api/helm-app/service"
+	helmBean "github.com/devtron-labs/devtron/api/helm-app/service/bean"
+	openapi2 "github.com/devtron-labs/devtron/api/openapi/openapiClient"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig/bean/workflow/cdWorkflow"
+	"github.com/devtron-labs/devtron/internal/util"
+	appStoreBean "github.com/devtron-labs/devtron/pkg/appStore/bean"
+	"github.com/devtron-labs/devtron/pkg/appStore/installedApp/repository"
+	installedAppService "github.com/devtron-labs/devtron/pkg/appStore/installedApp/service"
+	"github.com/devtron-labs/devtron/pkg/appStore/installedApp/service/EAMode"
+	"github.com/devtron-labs/devtron/pkg/appStore/installedApp/service/FullMode/drift/bean"
+	appStoreDeploymentCommon "github.com/devtron-labs/devtron/pkg/appStore/installedApp/service/common"
+	userBean "github.com/devtron-labs/devtron/pkg/auth/user/bean"
+	"github.com/devtron-labs/devtron/pkg/k8s"
+	k8sBean "github.com/devtron-labs/devtron/pkg/k8s/bean"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	cron2 "github.com/devtron-labs/devtron/util/cron"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"net/http"
+	"time"
+)
+
+type InstalledAppDriftConfig struct {
+	DriftCheckEnabled    bool   `env:"INSTALLED_APP_DRIFT_CHECK_ENABLED" envDefault:"false" description:"Periodically check the chart store apps for drift of their live values and resources from the last successful deployment"`
+	DriftCheckCron       string `env:"INSTALLED_APP_DRIFT_CHECK_CRON" envDefault:"*/30 * * * *" description:"Cron schedule for checking the chart store apps for drift"`
+	AutoReconcile        bool   `env:"INSTALLED_APP_DRIFT_AUTO_RECONCILE" envDefault:"false" description:"Re-deploy the last successful deployment of a chart store app once when drift is detected"`
+	IgnoredFields        string `env:"INSTALLED_APP_DRIFT_IGNORED_FIELDS" envDefault:"" description:"Comma separated resource field paths not compared for drift, eg. spec.replicas for apps scaled by an HPA"`
+	ReconcileTimeoutMins int    `env:"INSTALLED_APP_DRIFT_RECONCILE_TIMEOUT_MINS" envDefault:"30" description:"Minutes after which a chart store app still marked as reconciling is checked for drift again"`
+}
+
+type InstalledAppDriftService interface {
+	// GetDrift returns the result of the last drift check of the installed app, nil if it was never checked
+	GetDrift(installedAppId int) (*bean.InstalledAppDriftDto, error)
+	// CheckDrift compares the live values and resources of the installed app with its last successful deployment
+	CheckDrift(ctx context.Context, installedAppId int) (*bean.InstalledAppDriftDto, error)
+	// ReconcileDrift re-deploys the last successful deployment of a drifted installed app
+	ReconcileDrift(ctx context.Context, installedAppId int, userId int32) (*bean.InstalledAppDriftDto, error)
+	// CheckAllInstalledApps checks all the active installed apps for drift, called periodically
+	CheckAllInstalledApps()
+}
+
+type InstalledAppDriftServiceImpl struct {
+	logger                          *zap.SugaredLogger
+	installedAppDriftRepository     repository.InstalledAppDriftRepository
+	installedAppRepositoryHistory   repository.InstalledAppVersionHistoryRepository
+	installedAppDBService           EAMode.InstalledAppDBService
+	appStoreDeploymentCommonService appStoreDeploymentCommon.AppStoreDeploymentCommonService
+	appStoreDeploymentService       installedAppService.AppStoreDeploymentService
+	helmAppService                  service.HelmAppService
+	k8sCommonService                k8s.K8sCommonService
+	config                          *InstalledAppDriftConfig
+	ignoredPaths                    []string
+	cron                            *cron.Cron
+}
+
+func NewInstalledAppDriftServiceImpl(logger *zap.SugaredLogger,
+	installedAppDriftRepository repository.InstalledAppDriftRepository,
+	installedAppRepositoryHistory repository.InstalledAppVersionHistoryRepository,
+	installedAppDBService EAMode.InstalledAppDBService,
+	appStoreDeploymentCommonService appStoreDeploymentCommon.AppStoreDeploymentCommonService,
+	appStoreDeploymentService installedAppService.AppStoreDeploymentService,
+	helmAppService service.HelmAppService,
+	k8sCommonService k8s.K8sCommonService,
+	cronLogger *cron2.CronLoggerImpl,
+) *InstalledAppDriftServiceImpl {
+	impl := &InstalledAppDriftServiceImpl{
+		logger:                          logger,
+		installedAppDriftRepository:     installedAppDriftRepository,
+		installedAppRepositoryHistory:   installedAppRepositoryHistory,
+		installedAppDBService:           installedAppDBService,
+		appStoreDeploymentCommonService: appStoreDeploymentCommonService,
+		appStoreDeploymentService:       appStoreDeploymentService,
+		helmAppService:                  helmAppService,
+		k8sCommonService:                k8sCommonService,
+		config:                          &InstalledAppDriftConfig{},
+	}
+	if err := env.Parse(impl.config); err != nil {
+		logger.Errorw("error in parsing installed app drift config", "err", err)
+		return impl
+	}
+	impl.ignoredPaths = parseIgnoredFields(impl.config.IgnoredFields)
+	if !impl.config.DriftCheckEnabled {
+		return impl
+	}
+	impl.cron = cron.New(cron.WithChain(cron.Recover(cronLogger)))
+	impl.cron.Start()
+	_, err := impl.cron.AddFunc(impl.config.DriftCheckCron, impl.CheckAllInstalledApps)
+	if err != nil {
+		logger.Errorw("error in starting installed app drift check cron", "cron", impl.config.DriftCheckCron, "err", err)
+	}
+	return impl
+}
+
+func (impl *InstalledAppDriftServiceImpl) GetDrift(installedAppId int) (*bean.InstalledAppDriftDto, error) {
+	drift, err := impl.installedAppDriftRepository.FindByInstalledAppId(installedAppId)
+	if err != nil {
+		if util.IsErrNoRows(err) {
+			return nil, nil
+		}
+		impl.logger.Errorw("error in fetching installed app drift", "installedAppId", installedAppId, "err", err)
+		return nil, err
+	}
+	return toDriftDto(drift), nil
+}
+
+func (impl *InstalledAppDriftServiceImpl) CheckAllInstalledApps() {
+	installedAppIds, err := impl.installedAppDriftRepository.FindActiveInstalledAppIds()
+	if err != nil {
+		impl.logger.Errorw("error in fetching active installed apps for drift check", "err", err)
+		return
+	}
+	for _, installedAppId := range installedAppIds {
+		drift, err := impl.CheckDrift(context.Background(), installedAppId)
+		if err != nil {
+			impl.logger.Errorw("error in checking installed app drift", "installedAppId", installedAppId, "err", err)
+			continue
+		}
+		if impl.config.AutoReconcile && drift.Status == bean.DriftStatusDrifted && isNotReconciled(drift) {
+			_, err = impl.ReconcileDrift(context.Background(), installedAppId, userBean.SystemUserId)
+			if err != nil {
+				impl.logger.Errorw("error in reconciling installed app drift", "installedAppId", installedAppId, "err", err)
+			}
+		}
+	}
+}
+
+// isNotReconciled returns true if the drift was not reconciled since it was detected, a drift which persists after
+// reconciling is left to the user instead of re-deploying on every check
+func isNotReconciled(drift *bean.InstalledAppDriftDto) bool {
+	return drift.ReconciledOn == nil || (drift.DetectedOn != nil && drift.ReconciledOn.Before(*drift.DetectedOn))
+}
+
+// isReconcileExpired returns true if the drift is claimed for reconciling for longer than the reconcile timeout
+func (impl *InstalledAppDriftServiceImpl) isReconcileExpired(drift *repository.InstalledAppDrift) bool {
+	return time.Since(drift.UpdatedOn) > time.Duration(impl.config.ReconcileTimeoutMins)*time.Minute
+}
+
+func (impl *InstalledAppDriftServiceImpl) CheckDrift(ctx context.Context, installedAppId int) (*bean.InstalledAppDriftDto, error) {
+	existingDrift, err := impl.installedAppDriftRepository.FindByInstalledAppId(installedAppId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching installed app drift", "installedAppId", installedAppId, "err", err)
+		return nil, err
+	}
+	if util.IsErrNoRows(err) {
+		existingDrift = nil
+	}
+	if existingDrift != nil && existingDrift.Status == bean.DriftStatusReconciling.String() {
+		if !impl.isReconcileExpired(existingDrift) {
+			return toDriftDto(existingDrift), nil
+		}
+		impl.logger.Warnw("installed app drift reconcile did not complete in time, checking drift again", "installedAppId", installedAppId, "reconcilingSince", existingDrift.UpdatedOn)
+	}
+	result, err := impl.getDrift(ctx, installedAppId)
+	if err != nil {
+		return nil, err
+	}
+	drift, err := impl.saveDrift(existingDrift, installedAppId, result)
+	if err != nil {
+		return nil, err
+	}
+	return toDriftDto(drift), nil
+}
+
+func (impl *InstalledAppDriftServiceImpl) ReconcileDrift(ctx context.Context, installedAppId int, userId int32) (*bean.InstalledAppDriftDto, error) {
+	drift, err := impl.installedAppDriftRepository.FindByInstalledAppId(installedAppId)
+	if err != nil {
+		if util.IsErrNoRows(err) {
+			return nil, util.NewApiError(http.StatusNotFound, "drift of the app has not been checked yet", err.Error())
+		}
+		impl.logger.Errorw("error in fetching installed app drift", "installedAppId", installedAppId, "err", err)
+		return nil, err
+	}
+	if drift.Status != bean.DriftStatusDrifted.String() {
+		return nil, util.NewApiError(http.StatusConflict, fmt.Sprintf("app is not drifted, drift status is %s", drift.Status), "drift status is "+drift.Status)
+	}
+	claimed, err := impl.installedAppDriftRepository.UpdateStatus(drift.Id, bean.DriftStatusDrifted.String(), bean.DriftStatusReconciling.String(), userId)
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return nil, util.NewApiError(http.StatusConflict, "drift of the app is already being reconciled", "drift status changed concurrently")
+	}
+	installedAppDto, err := impl.installedAppDBService.GetInstalledAppByInstalledAppId(installedAppId)
+	if err == nil {
+		installedAppDto.UserId = userId
+		request := &openapi2.RollbackReleaseRequest{}
+		request.SetInstalledAppId(int32(installedAppId))
+		request.SetVersion(int32(drift.InstalledAppVersionHistoryId))
+		_, err = impl.appStoreDeploymentService.RollbackApplication(ctx, request, installedAppDto, userId)
+	}
+	now := time.Now()
+	if err != nil {
+		impl.logger.Errorw("error in re-deploying installed app to reconcile drift", "installedAppId", installedAppId, "installedAppVersionHistoryId", drift.InstalledAppVersionHistoryId, "err", err)
+		drift.Status = bean.DriftStatusDrifted.String()
+		drift.Message = fmt.Sprintf("reconcile failed: %s", err.Error())
+	} else {
+		drift.Status = bean.DriftStatusUnknown.String()
+		drift.Message = "reconciled by re-deploying the last successful deployment, drift will be checked again after the deployment"
+	}
+	drift.ReconciledOn = &now
+	drift.UpdateAuditLog(userId)
+	if updateErr := impl.installedAppDriftRepository.Update(drift); updateErr != nil {
+		impl.logger.Errorw("error in updating installed app drift", "installedAppId", installedAppId, "err", updateErr)
+		return nil, updateErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	return toDriftDto(drift), nil
+}
+
+// driftResult is the outcome of a drift check, an unknown result carries only the reason in its message
+type driftResult struct {
+	status                       bean.DriftStatus
+	installedAppVersionHistoryId int
+	diff                         *bean.DriftDiff
+	message                      string
+}
+
+func newUnknownDriftResult(message string) *driftResult {
+	return &driftResult{status: bean.DriftStatusUnknown, message: message}
+}
+
+func (impl *InstalledAppDriftServiceImpl) getDrift(ctx context.Context, installedAppId int) (*driftResult, error) {
+	installedAppDto, err := impl.installedAppDBService.GetInstalledAppByInstalledAppId(installedAppId)
+	if err != nil {
+		if util.IsErrNoRows(err) {
+			return nil, util.NewApiError(http.StatusNotFound, "installed app not found", err.Error())
+		}
+		impl.logger.Errorw("error in fetching installed app", "installedAppId", installedAppId, "err", err)
+		return nil, err
+	}
+	if installedAppDto.IsVirtualEnvironment || util.IsManifestDownload(installedAppDto.DeploymentAppType) {
+		return newUnknownDriftResult("drift is not checked for apps deployed on virtual environments"), nil
+	}
+	history, err := impl.installedAppRepositoryHistory.GetLatestInstalledAppVersionHistoryByInstalledAppId(installedAppId)
+	if err != nil {
+		if util.IsErrNoRows(err) {
+			return newUnknownDriftResult("app has not been deployed yet"), nil
+		}
+		impl.logger.Errorw("error in fetching latest installed app version history", "installedAppId", installedAppId, "err", err)
+		return nil, err
+	}
+	if history.Status != cdWorkflow.WorkflowSucceeded && history.Status != string(health.HealthStatusHealthy) {
+		return newUnknownDriftResult(fmt.Sprintf("latest deployment is %s, drift is checked only after a successful deployment", history.Status)), nil
+	}
+	result := &driftResult{installedAppVersionHistoryId: history.Id, diff: &bean.DriftDiff{}}
+
+	if util.IsHelmApp(installedAppDto.DeploymentAppType) {
+		releaseInfo, err := impl.helmAppService.GetValuesYaml(ctx, &helmBean.AppIdentifier{
+			ClusterId:   installedAppDto.ClusterId,
+			Namespace:   installedAppDto.Namespace,
+			ReleaseName: installedAppDto.AppName,
+		})
+		if err != nil {
+			impl.logger.Errorw("error in fetching live values of helm release", "installedAppId", installedAppId, "err", err)
+			return newUnknownDriftResult(fmt.Sprintf("could not fetch the live values of the release: %s", err.Error())), nil
+		}
+		result.diff.ValuesDiffs, err = getValuesDiffs(history.ValuesYamlRaw, releaseInfo.GetOverrideValues())
+		if err != nil {
+			return newUnknownDriftResult(err.Error()), nil
+		}
+	}
+
+	manifestDetail, err := impl.appStoreDeploymentCommonService.GetDeploymentHistoryInfoFromDB(ctx, installedAppDto, int32(history.Id))
+	if err != nil {
+		impl.logger.Errorw("error in fetching deployed manifest", "installedAppId", installedAppId, "installedAppVersionHistoryId", history.Id, "err", err)
+		return nil, err
+	}
+	if manifestDetail.Manifest == nil {
+		return newUnknownDriftResult("could not render the manifest of the last successful deployment"), nil
+	}
+	desiredObjects, err := yamlUtil.SplitYAMLs([]byte(*manifestDetail.Manifest))
+	if err != nil {
+		return newUnknownDriftResult(fmt.Sprintf("invalid manifest of the last successful deployment: %s", err.Error())), nil
+	}
+	for _, desiredObject := range desiredObjects {
+		if isHookResource(desiredObject) {
+			continue
+		}
+		resourceDrift, err := impl.getResourceDrift(ctx, installedAppDto, desiredObject)
+		if err != nil {
+			return newUnknownDriftResult(fmt.Sprintf("could not fetch live %s %s: %s", desiredObject.GetKind(), desiredObject.GetName(), err.Error())), nil
+		}
+		if resourceDrift != nil {
+			result.diff.Resources = append(result.diff.Resources, resourceDrift)
+		}
+	}
+
+	result.status = bean.DriftStatusInSync
+	if len(result.diff.ValuesDiffs) > 0 || len(result.diff.Resources) > 0 {
+		result.status = bean.DriftStatusDrifted
+	}
+	return result, nil
+}
+
+// getResourceDrift returns nil if the live resource matches the desired resource
+func (impl *InstalledAppDriftServiceImpl) getResourceDrift(ctx context.Context, installedAppDto *appStoreBean.InstallAppVersionDTO, desiredObject unstructured.Unstructured) (*bean.ResourceDrift, error) {
+	gvk := desiredObject.GroupVersionKind()
+	namespace := desiredObject.GetNamespace()
+	isDefaultNamespace := len(namespace) == 0
+	if isDefaultNamespace {
+		namespace = installedAppDto.Namespace
+	}
+	resourceDrift := &bean.ResourceDrift{
+		Group:     gvk.Group,
+		Version:   gvk.Version,
+		Kind:      gvk.Kind,
+		Name:      desiredObject.GetName(),
+		Namespace: namespace,
+	}
+	live, err := impl.getLiveResource(ctx, installedAppDto.ClusterId, namespace, desiredObject)
+	if err != nil && k8sErrors.IsNotFound(err) && isDefaultNamespace {
+		// cluster scoped resources are not found in the release namespace
+		live, err = impl.getLiveResource(ctx, installedAppDto.ClusterId, "", desiredObject)
+		if err == nil {
+			resourceDrift.Namespace = ""
+		}
+	}
+	if err != nil {
+		if k8sErrors.IsNotFound(err) {
+			resourceDrift.Missing = true
+			return resourceDrift, nil
+		}
+		return nil, err
+	}
+	resourceDrift.Diffs = getResourceDiffs(desiredObject.Object, live.Object, impl.ignoredPaths)
+	if len(resourceDrift.Diffs) == 0 {
+		return nil, nil
+	}
+	return resourceDrift, nil
+}
+
+func (impl *InstalledAppDriftServiceImpl) getLiveResource(ctx context.Context, clusterId int, namespace string, desiredObject unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	resp, err := impl.k8sCommonService.GetResource(ctx, &k8sBean.ResourceRequestBean{
+		ClusterId: clusterId,
+		K8sRequest: &k8sUtil.K8sRequestBean{
+			ResourceIdentifier: k8sUtil.ResourceIdentifier{
+				Name:             desiredObject.GetName(),
+				Namespace:        namespace,
+				GroupVersionKind: desiredObject.GroupVersionKind(),
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &resp.ManifestResponse.Manifest, nil
+}
+
+func (impl *InstalledAppDriftServiceImpl) saveDrift(drift *repository.InstalledAppDrift, installedAppId int, result *driftResult) (*repository.InstalledAppDrift, error) {
+	now := time.Now()
+	isNew := drift == nil
+	if isNew {
+		drift = &repository.InstalledAppDrift{
+			InstalledAppId: installedAppId,
+			AuditLog:       sql.NewDefaultAuditLog(userBean.SystemUserId),
+		}
+	}
+	drift.Status = result.status.String()
+	drift.Message = result.message
+	drift.CheckedOn = now
+	// an unknown result keeps the diff of the last completed check
+	if result.status != bean.DriftStatusUnknown {
+		diff, err := json.Marshal(result.diff)
+		if err != nil {
+			impl.logger.Errorw("error in marshalling installed app drift diff", "installedAppId", installedAppId, "err", err)
+			return nil, err
+		}
+		drift.InstalledAppVersionHistoryId = result.installedAppVersionHistoryId
+		drift.Diff = string(diff)
+		drift.ValuesDrifted = len(result.diff.ValuesDiffs) > 0
+		drift.DriftedResourceCount = len(result.diff.Resources)
+		if result.status == bean.DriftStatusInSync {
+			drift.DetectedOn = nil
+		} else if drift.DetectedOn == nil {
+			drift.DetectedOn = &now
+		}
+	}
+	drift.UpdateAuditLog(userBean.SystemUserId)
+	var err error
+	if isNew {
+		err = impl.installedAppDriftRepository.Save(drift)
+	} else {
+		err = impl.installedAppDriftRepository.Update(drift)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in saving installed app drift", "installedAppId", installedAppId, "err", err)
+		return nil, err
+	}
+	return drift, nil
+}
+
+func toDriftDto(drift *repository.InstalledAppDrift) *bean.InstalledAppDriftDto {
+	dto := &bean.InstalledAppDriftDto{
+		InstalledAppId:               drift.InstalledAppId,
+		InstalledAppVersionHistoryId: drift.InstalledAppVersionHistoryId,
+		Status:                       bean.DriftStatus(drift.Status),
+		ValuesDrifted:                drift.ValuesDrifted,
+		DriftedResourceCount:         drift.DriftedResourceCount,
+		Message:                      drift.Message,
+		CheckedOn:                    drift.CheckedOn,
+		DetectedOn:                   drift.DetectedOn,
+		ReconciledOn:                 drift.ReconciledOn,
+	}
+	diff := &bean.DriftDiff{}
+	if len(drift.Diff) > 0 && json.Unmarshal([]byte(drift.Diff), diff) == nil {
+		dto.ValuesDiffs = diff.ValuesDiffs
+		dto.Resources = diff.Resources
+	}
+	return dto
+}
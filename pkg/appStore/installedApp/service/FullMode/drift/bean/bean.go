@@ -0,0 +1,74 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import "time"
+
+type DriftStatus string
+
+const (
+	// DriftStatusInSync means the live state matches the last successful deployment
+	DriftStatusInSync DriftStatus = "IN_SYNC"
+	// DriftStatusDrifted means the live values or resources differ from the last successful deployment
+	DriftStatusDrifted DriftStatus = "DRIFTED"
+	// DriftStatusUnknown means the drift could not be checked, the reason is set in the message
+	DriftStatusUnknown DriftStatus = "UNKNOWN"
+	// DriftStatusReconciling means the last successful deployment is being re-deployed to reconcile the drift
+	DriftStatusReconciling DriftStatus = "RECONCILING"
+)
+
+func (s DriftStatus) String() string {
+	return string(s)
+}
+
+// FieldDiff is a field whose live value differs from the desired value, Desired and Live are not set for secrets
+type FieldDiff struct {
+	Path    string      `json:"path"`
+	Desired interface{} `json:"desired,omitempty"`
+	Live    interface{} `json:"live,omitempty"`
+}
+
+// ResourceDrift is a resource of the deployed manifest which is missing from the cluster or differs from the manifest
+type ResourceDrift struct {
+	Group     string       `json:"group"`
+	Version   string       `json:"version"`
+	Kind      string       `json:"kind"`
+	Name      string       `json:"name"`
+	Namespace string       `json:"namespace"`
+	Missing   bool         `json:"missing"`
+	Diffs     []*FieldDiff `json:"diffs,omitempty"`
+}
+
+// DriftDiff is the stored diff of a drift check
+type DriftDiff struct {
+	ValuesDiffs []*FieldDiff     `json:"valuesDiffs,omitempty"`
+	Resources   []*ResourceDrift `json:"resources,omitempty"`
+}
+
+type InstalledAppDriftDto struct {
+	InstalledAppId               int              `json:"installedAppId"`
+	InstalledAppVersionHistoryId int              `json:"installedAppVersionHistoryId"`
+	Status                       DriftStatus      `json:"status"`
+	ValuesDrifted                bool             `json:"valuesDrifted"`
+	DriftedResourceCount         int              `json:"driftedResourceCount"`
+	ValuesDiffs                  []*FieldDiff     `json:"valuesDiffs,omitempty"`
+	Resources                    []*ResourceDrift `json:"resources,omitempty"`
+	Message                      string           `json:"message,omitempty"`
+	CheckedOn                    time.Time        `json:"checkedOn"`
+	DetectedOn                   *time.Time       `json:"detectedOn,omitempty"`
+	ReconciledOn                 *time.Time       `json:"reconciledOn,omitempty"`
+}
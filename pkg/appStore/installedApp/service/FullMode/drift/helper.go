@@ -0,0 +1,295 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package drift
+
+import (
+	"encoding/base64"
+	"fmt"
+	"github.com/devtron-labs/devtron/pkg/appStore/installedApp/service/FullMode/drift/bean"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+	"sort"
+	"strings"
+)
+
+const (
+	secretKind      = "Secret"
+	nameField       = "name"
+	statusField     = "status"
+	dataField       = "data"
+	stringDataField = "stringData"
+)
+
+// hookAnnotations mark the resources which are created only while deploying and are not expected to be live afterwards
+var hookAnnotations = []string{"helm.sh/hook", "argocd.argoproj.io/hook"}
+
+// parseIgnoredFields splits the comma separated field paths which are not compared
+func parseIgnoredFields(ignoredFields string) []string {
+	var paths []string
+	for _, path := range strings.Split(ignoredFields, ",") {
+		path = strings.TrimSpace(path)
+		if len(path) > 0 {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+func isIgnoredPath(path string, ignoredPaths []string) bool {
+	for _, ignoredPath := range ignoredPaths {
+		if path == ignoredPath || strings.HasPrefix(path, ignoredPath+".") || strings.HasPrefix(path, ignoredPath+"[") {
+			return true
+		}
+	}
+	return false
+}
+
+func isHookResource(obj unstructured.Unstructured) bool {
+	annotations := obj.GetAnnotations()
+	for _, annotation := range hookAnnotations {
+		if _, ok := annotations[annotation]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// getValuesDiffs compares the deployed values with the live values of the release, both ways
+func getValuesDiffs(desiredValuesYaml, liveValuesYaml string) ([]*bean.FieldDiff, error) {
+	desired := make(map[string]interface{})
+	if err := yaml.Unmarshal([]byte(desiredValuesYaml), &desired); err != nil {
+		return nil, fmt.Errorf("invalid deployed values: %w", err)
+	}
+	live := make(map[string]interface{})
+	if err := yaml.Unmarshal([]byte(liveValuesYaml), &live); err != nil {
+		return nil, fmt.Errorf("invalid live values: %w", err)
+	}
+	var diffs []*bean.FieldDiff
+	compareValues("", desired, live, &diffs)
+	return diffs, nil
+}
+
+func compareValues(path string, desired, live interface{}, diffs *[]*bean.FieldDiff) {
+	if isEmpty(desired) && isEmpty(live) {
+		return
+	}
+	desiredMap, desiredIsMap := desired.(map[string]interface{})
+	liveMap, liveIsMap := live.(map[string]interface{})
+	if desiredIsMap && liveIsMap {
+		keys := make(map[string]bool)
+		for key := range desiredMap {
+			keys[key] = true
+		}
+		for key := range liveMap {
+			keys[key] = true
+		}
+		for _, key := range sortedKeys(keys) {
+			compareValues(joinPath(path, key), desiredMap[key], liveMap[key], diffs)
+		}
+		return
+	}
+	desiredList, desiredIsList := desired.([]interface{})
+	liveList, liveIsList := live.([]interface{})
+	if desiredIsList && liveIsList {
+		for i := 0; i < len(desiredList) || i < len(liveList); i++ {
+			var desiredElement, liveElement interface{}
+			if i < len(desiredList) {
+				desiredElement = desiredList[i]
+			}
+			if i < len(liveList) {
+				liveElement = liveList[i]
+			}
+			compareValues(fmt.Sprintf("%s[%d]", path, i), desiredElement, liveElement, diffs)
+		}
+		return
+	}
+	if !isEqualScalar(desired, live) {
+		*diffs = append(*diffs, &bean.FieldDiff{Path: path, Desired: desired, Live: live})
+	}
+}
+
+// getResourceDiffs compares the fields set in the desired resource with the live resource, the fields set only in the
+// live resource are defaulted or managed by the cluster and are not considered as drift
+func getResourceDiffs(desired, live map[string]interface{}, ignoredPaths []string) []*bean.FieldDiff {
+	isSecret := desired["kind"] == secretKind
+	if isSecret {
+		desired = getSecretWithEncodedData(desired)
+	}
+	var diffs []*bean.FieldDiff
+	for _, key := range sortedKeys(toKeySet(desired)) {
+		if key == statusField {
+			continue
+		}
+		compareDesiredFields(key, desired[key], live[key], ignoredPaths, &diffs)
+	}
+	if isSecret {
+		// secret values are never exposed in the drift
+		for _, diff := range diffs {
+			diff.Desired, diff.Live = nil, nil
+		}
+	}
+	return diffs
+}
+
+func compareDesiredFields(path string, desired, live interface{}, ignoredPaths []string, diffs *[]*bean.FieldDiff) {
+	if isIgnoredPath(path, ignoredPaths) {
+		return
+	}
+	if live == nil && isEmpty(desired) {
+		return
+	}
+	switch desiredValue := desired.(type) {
+	case map[string]interface{}:
+		liveMap, ok := live.(map[string]interface{})
+		if !ok {
+			*diffs = append(*diffs, &bean.FieldDiff{Path: path, Desired: desired, Live: live})
+			return
+		}
+		for _, key := range sortedKeys(toKeySet(desiredValue)) {
+			compareDesiredFields(joinPath(path, key), desiredValue[key], liveMap[key], ignoredPaths, diffs)
+		}
+	case []interface{}:
+		liveList, ok := live.([]interface{})
+		if !ok {
+			*diffs = append(*diffs, &bean.FieldDiff{Path: path, Desired: desired, Live: live})
+			return
+		}
+		if isNamedList(desiredValue) {
+			liveByName := make(map[string]interface{}, len(liveList))
+			for _, element := range liveList {
+				if elementMap, ok := element.(map[string]interface{}); ok {
+					liveByName[fmt.Sprint(elementMap[nameField])] = element
+				}
+			}
+			for _, element := range desiredValue {
+				name := fmt.Sprint(element.(map[string]interface{})[nameField])
+				compareDesiredFields(fmt.Sprintf("%s[%s=%s]", path, nameField, name), element, liveByName[name], ignoredPaths, diffs)
+			}
+			return
+		}
+		for i, element := range desiredValue {
+			var liveElement interface{}
+			if i < len(liveList) {
+				liveElement = liveList[i]
+			}
+			compareDesiredFields(fmt.Sprintf("%s[%d]", path, i), element, liveElement, ignoredPaths, diffs)
+		}
+	default:
+		if !isEqualScalar(desired, live) {
+			*diffs = append(*diffs, &bean.FieldDiff{Path: path, Desired: desired, Live: live})
+		}
+	}
+}
+
+// getSecretWithEncodedData moves the stringData of a secret to its data as the api server does, so that it can be
+// compared with the live secret
+func getSecretWithEncodedData(secret map[string]interface{}) map[string]interface{} {
+	stringData, ok := secret[stringDataField].(map[string]interface{})
+	if !ok {
+		return secret
+	}
+	encoded := make(map[string]interface{}, len(secret))
+	for key, value := range secret {
+		encoded[key] = value
+	}
+	data := make(map[string]interface{})
+	if existingData, ok := secret[dataField].(map[string]interface{}); ok {
+		for key, value := range existingData {
+			data[key] = value
+		}
+	}
+	for key, value := range stringData {
+		data[key] = base64.StdEncoding.EncodeToString([]byte(fmt.Sprint(value)))
+	}
+	encoded[dataField] = data
+	delete(encoded, stringDataField)
+	return encoded
+}
+
+// isNamedList returns true if the elements are objects identified by their name, like the containers of a pod
+func isNamedList(list []interface{}) bool {
+	if len(list) == 0 {
+		return false
+	}
+	for _, element := range list {
+		elementMap, ok := element.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		if _, ok := elementMap[nameField].(string); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// isEqualScalar compares the values by their string form, quantities like 1Gi and 1024Mi or 500m and 0.5 are equal
+func isEqualScalar(desired, live interface{}) bool {
+	if desired == nil || live == nil {
+		return desired == nil && live == nil
+	}
+	desiredString, liveString := fmt.Sprint(desired), fmt.Sprint(live)
+	if desiredString == liveString {
+		return true
+	}
+	desiredQuantity, err := resource.ParseQuantity(desiredString)
+	if err != nil {
+		return false
+	}
+	liveQuantity, err := resource.ParseQuantity(liveString)
+	if err != nil {
+		return false
+	}
+	return desiredQuantity.Cmp(liveQuantity) == 0
+}
+
+func isEmpty(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case map[string]interface{}:
+		return len(v) == 0
+	case []interface{}:
+		return len(v) == 0
+	}
+	return false
+}
+
+func joinPath(path, key string) string {
+	if len(path) == 0 {
+		return key
+	}
+	return path + "." + key
+}
+
+func toKeySet(m map[string]interface{}) map[string]bool {
+	keys := make(map[string]bool, len(m))
+	for key := range m {
+		keys[key] = true
+	}
+	return keys
+}
+
+func sortedKeys(keys map[string]bool) []string {
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+	return sorted
+}
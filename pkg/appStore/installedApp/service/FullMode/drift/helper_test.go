@@ -0,0 +1,134 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package drift
+
+import (
+	"github.com/devtron-labs/devtron/pkg/appStore/installedApp/service/FullMode/drift/bean"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/yaml"
+	"testing"
+)
+
+func toObject(t *testing.T, manifest string) map[string]interface{} {
+	obj := make(map[string]interface{})
+	assert.NoError(t, yaml.Unmarshal([]byte(manifest), &obj))
+	return obj
+}
+
+func getDiffPaths(diffs []*bean.FieldDiff) []string {
+	var paths []string
+	for _, diff := range diffs {
+		paths = append(paths, diff.Path)
+	}
+	return paths
+}
+
+func TestGetResourceDiffs(t *testing.T) {
+	desired := toObject(t, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  labels:
+    app: app
+spec:
+  replicas: 2
+  template:
+    spec:
+      volumes: []
+      containers:
+      - name: app
+        image: nginx:1.25
+        resources:
+          limits:
+            memory: 1Gi
+            cpu: 500m
+      - name: sidecar
+        image: envoy:1.0
+`)
+	live := toObject(t, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  namespace: default
+  uid: 123
+  labels:
+    app: app
+    extra: label
+spec:
+  replicas: 5
+  template:
+    spec:
+      containers:
+      - name: sidecar
+        image: envoy:1.0
+      - name: app
+        image: nginx:1.26
+        resources:
+          limits:
+            memory: 1024Mi
+            cpu: "0.5"
+status:
+  replicas: 5
+`)
+	diffs := getResourceDiffs(desired, live, nil)
+	assert.Equal(t, []string{"spec.replicas", "spec.template.spec.containers[name=app].image"}, getDiffPaths(diffs))
+	assert.Equal(t, "nginx:1.25", diffs[1].Desired)
+	assert.Equal(t, "nginx:1.26", diffs[1].Live)
+
+	diffs = getResourceDiffs(desired, live, parseIgnoredFields(" spec.replicas, spec.template.spec.containers "))
+	assert.Empty(t, diffs)
+}
+
+func TestGetResourceDiffsOfSecret(t *testing.T) {
+	desired := toObject(t, `
+apiVersion: v1
+kind: Secret
+metadata:
+  name: creds
+stringData:
+  password: changed
+  user: admin
+`)
+	live := toObject(t, `
+apiVersion: v1
+kind: Secret
+metadata:
+  name: creds
+data:
+  password: c2VjcmV0
+  user: YWRtaW4=
+`)
+	diffs := getResourceDiffs(desired, live, nil)
+	assert.Equal(t, []string{"data.password"}, getDiffPaths(diffs))
+	assert.Nil(t, diffs[0].Desired)
+	assert.Nil(t, diffs[0].Live)
+}
+
+func TestGetValuesDiffs(t *testing.T) {
+	diffs, err := getValuesDiffs("replicaCount: 1\nimage:\n  tag: \"1.0\"\nempty: {}\nports: [80, 443]\n", "replicaCount: 3\nimage:\n  tag: \"1.0\"\nports: [80]\nextra: true\n")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"extra", "ports[1]", "replicaCount"}, getDiffPaths(diffs))
+
+	diffs, err = getValuesDiffs("", "")
+	assert.NoError(t, err)
+	assert.Empty(t, diffs)
+
+	_, err = getValuesDiffs("a: [", "")
+	assert.Error(t, err)
+}
@@ -27,6 +27,7 @@ import (
 	util2 "github.com/devtron-labs/devtron/internal/util"
 	"github.com/devtron-labs/devtron/pkg/argoApplication/bean"
 	"github.com/devtron-labs/devtron/pkg/argoApplication/helper"
+	"github.com/devtron-labs/devtron/pkg/argoApplication/read"
 	"github.com/devtron-labs/devtron/pkg/argoApplication/read/config"
 	"github.com/devtron-labs/devtron/pkg/cluster/adapter"
 	clusterRepository "github.com/devtron-labs/devtron/pkg/cluster/repository"
@@ -46,6 +47,14 @@ type ArgoApplicationService interface {
 	HibernateArgoApplication(ctx context.Context, app *bean.ArgoAppIdentifier, hibernateRequest *openapi.HibernateRequest) ([]*openapi.HibernateStatus, error)
 	UnHibernateArgoApplication(ctx context.Context, app *bean.ArgoAppIdentifier, hibernateRequest *openapi.HibernateRequest) ([]*openapi.HibernateStatus, error)
 
+	ListApplicationSets(clusterIds []int) ([]*bean.ArgoApplicationSetListDto, error)
+	GetApplicationSetDetail(ctx context.Context, applicationSet *bean.ArgoApplicationSetIdentifier) (*bean.ArgoApplicationSetDetailDto, error)
+	// GetApplicationSetTree returns the resource tree of every application generated by the application set
+	GetApplicationSetTree(ctx context.Context, applicationSet *bean.ArgoApplicationSetIdentifier) (*bean.ArgoApplicationSetTreeDto, error)
+	HibernateApplicationSet(ctx context.Context, applicationSet *bean.ArgoApplicationSetIdentifier) (*bean.ArgoApplicationSetActionResponse, error)
+	UnHibernateApplicationSet(ctx context.Context, applicationSet *bean.ArgoApplicationSetIdentifier) (*bean.ArgoApplicationSetActionResponse, error)
+	SyncApplicationSet(ctx context.Context, applicationSet *bean.ArgoApplicationSetIdentifier, userEmail string) (*bean.ArgoApplicationSetActionResponse, error)
+
 	//FUll mode
 	// ResourceTree	returns the status for all Apps deployed via ArgoCd
 	GetResourceTree(ctx context.Context, acdQueryRequest *bean.AcdClientQueryRequest) (*argoApplication.ResourceTreeResponse, error)
@@ -60,6 +69,7 @@ type ArgoApplicationServiceImpl struct {
 	k8sApplicationService        application.K8sApplicationService
 	argoApplicationConfigService config.ArgoApplicationConfigService
 	deploymentConfigService      common.DeploymentConfigService
+	argoApplicationReadService   read.ArgoApplicationReadService
 }
 
 func NewArgoApplicationServiceImpl(logger *zap.SugaredLogger,
@@ -69,7 +79,8 @@ func NewArgoApplicationServiceImpl(logger *zap.SugaredLogger,
 	helmAppService service.HelmAppService,
 	k8sApplicationService application.K8sApplicationService,
 	argoApplicationConfigService config.ArgoApplicationConfigService,
-	deploymentConfigService common.DeploymentConfigService) *ArgoApplicationServiceImpl {
+	deploymentConfigService common.DeploymentConfigService,
+	argoApplicationReadService read.ArgoApplicationReadService) *ArgoApplicationServiceImpl {
 	return &ArgoApplicationServiceImpl{
 		logger:                       logger,
 		clusterRepository:            clusterRepository,
@@ -79,6 +90,7 @@ func NewArgoApplicationServiceImpl(logger *zap.SugaredLogger,
 		k8sApplicationService:        k8sApplicationService,
 		argoApplicationConfigService: argoApplicationConfigService,
 		deploymentConfigService:      deploymentConfigService,
+		argoApplicationReadService:   argoApplicationReadService,
 	}
 
 }
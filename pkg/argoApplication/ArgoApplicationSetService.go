@@ -0,0 +1,320 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package argoApplication
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	k8sCommonBean "github.com/devtron-labs/common-lib/utils/k8s/commonBean"
+	openapi "github.com/devtron-labs/devtron/api/helm-app/openapiClient"
+	util2 "github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/argoApplication/bean"
+	"github.com/devtron-labs/devtron/pkg/argoApplication/helper"
+	"github.com/devtron-labs/devtron/pkg/cluster/adapter"
+	clusterRepository "github.com/devtron-labs/devtron/pkg/cluster/repository"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"net/http"
+)
+
+const argoOperationPhaseRunning = "Running"
+
+func (impl *ArgoApplicationServiceImpl) ListApplicationSets(clusterIds []int) ([]*bean.ArgoApplicationSetListDto, error) {
+	var clusters []clusterRepository.Cluster
+	var err error
+	if len(clusterIds) > 0 {
+		clusters, err = impl.clusterRepository.FindByIds(clusterIds)
+		if err != nil {
+			impl.logger.Errorw("error in getting clusters by ids", "err", err, "clusterIds", clusterIds)
+			return nil, err
+		}
+	} else {
+		clusters, err = impl.clusterRepository.FindAllActive()
+		if err != nil {
+			impl.logger.Errorw("error in getting all active clusters", "err", err)
+			return nil, err
+		}
+	}
+	applicationSetListFinal := make([]*bean.ArgoApplicationSetListDto, 0)
+	for _, cluster := range clusters {
+		clusterObj := cluster
+		if clusterObj.IsVirtualCluster || len(clusterObj.ErrorInConnecting) != 0 {
+			continue
+		}
+		restConfig, err := impl.k8sUtil.GetRestConfigByCluster(adapter.GetClusterBean(clusterObj).GetClusterConfig())
+		if err != nil {
+			impl.logger.Errorw("error in getting rest config by cluster Id", "err", err, "clusterId", clusterObj.Id)
+			return nil, err
+		}
+		applicationSets, err := impl.listArgoResources(restConfig, bean.GvkForArgoApplicationSet, bean.AllNamespaces)
+		if err != nil {
+			impl.logger.Errorw("error in getting application set list", "err", err, "clusterId", clusterObj.Id)
+			return nil, err
+		}
+		if len(applicationSets) == 0 {
+			continue
+		}
+		applications, err := impl.listArgoResources(restConfig, bean.GvkForArgoApplication, bean.AllNamespaces)
+		if err != nil {
+			impl.logger.Errorw("error in getting argo application list", "err", err, "clusterId", clusterObj.Id)
+			return nil, err
+		}
+		// applications are generated in the namespace of their application set
+		generatedAppCount := make(map[string]int)
+		for i := range applications {
+			if applicationSetName := helper.GetApplicationSetName(&applications[i]); len(applicationSetName) != 0 {
+				generatedAppCount[fmt.Sprintf("%s/%s", applications[i].GetNamespace(), applicationSetName)]++
+			}
+		}
+		for i := range applicationSets {
+			applicationSetDto := helper.GetApplicationSetListDto(&applicationSets[i], clusterObj.ClusterName, clusterObj.Id)
+			applicationSetDto.GeneratedAppCount = generatedAppCount[fmt.Sprintf("%s/%s", applicationSetDto.Namespace, applicationSetDto.Name)]
+			applicationSetListFinal = append(applicationSetListFinal, applicationSetDto)
+		}
+	}
+	return applicationSetListFinal, nil
+}
+
+func (impl *ArgoApplicationServiceImpl) GetApplicationSetDetail(ctx context.Context, applicationSet *bean.ArgoApplicationSetIdentifier) (*bean.ArgoApplicationSetDetailDto, error) {
+	applicationSetObj, generatedApps, clusterObj, _, err := impl.getApplicationSetAndGeneratedApps(ctx, applicationSet)
+	if err != nil {
+		return nil, err
+	}
+	detail := &bean.ArgoApplicationSetDetailDto{
+		ArgoApplicationSetListDto: helper.GetApplicationSetListDto(applicationSetObj, clusterObj.ClusterName, clusterObj.Id),
+		GeneratorSpecs:            make([]interface{}, 0),
+		Conditions:                helper.GetApplicationSetConditions(applicationSetObj),
+		Applications:              make([]*bean.ArgoApplicationListDto, 0, len(generatedApps)),
+		Manifest:                  applicationSetObj.Object,
+	}
+	if generators, found, _ := unstructured.NestedSlice(applicationSetObj.Object, k8sCommonBean.Spec, "generators"); found {
+		detail.GeneratorSpecs = generators
+	}
+	detail.Template, _, _ = unstructured.NestedMap(applicationSetObj.Object, k8sCommonBean.Spec, "template")
+	detail.SyncPolicy, _, _ = unstructured.NestedMap(applicationSetObj.Object, k8sCommonBean.Spec, "syncPolicy")
+	for i := range generatedApps {
+		healthStatus, syncStatus, _, _ := helper.GetHealthSyncStatusDestinationServerAndManagedResourcesForArgoK8sRawObject(generatedApps[i].Object)
+		detail.Applications = append(detail.Applications, &bean.ArgoApplicationListDto{
+			Name:         generatedApps[i].GetName(),
+			ClusterId:    clusterObj.Id,
+			ClusterName:  clusterObj.ClusterName,
+			Namespace:    generatedApps[i].GetNamespace(),
+			HealthStatus: healthStatus,
+			SyncStatus:   syncStatus,
+		})
+	}
+	detail.GeneratedAppCount = len(generatedApps)
+	return detail, nil
+}
+
+func (impl *ArgoApplicationServiceImpl) GetApplicationSetTree(ctx context.Context, applicationSet *bean.ArgoApplicationSetIdentifier) (*bean.ArgoApplicationSetTreeDto, error) {
+	applicationSetObj, generatedApps, clusterObj, _, err := impl.getApplicationSetAndGeneratedApps(ctx, applicationSet)
+	if err != nil {
+		return nil, err
+	}
+	tree := &bean.ArgoApplicationSetTreeDto{
+		ArgoApplicationSetListDto: helper.GetApplicationSetListDto(applicationSetObj, clusterObj.ClusterName, clusterObj.Id),
+		Applications:              make([]*bean.ArgoApplicationDetailDto, 0, len(generatedApps)),
+	}
+	tree.GeneratedAppCount = len(generatedApps)
+	for i := range generatedApps {
+		appDetail, err := impl.argoApplicationReadService.GetAppDetailEA(ctx, generatedApps[i].GetName(), generatedApps[i].GetNamespace(), clusterObj.Id)
+		if err != nil {
+			impl.logger.Errorw("error in getting generated application detail", "err", err, "applicationSet", applicationSet, "appName", generatedApps[i].GetName())
+			return nil, err
+		}
+		tree.Applications = append(tree.Applications, appDetail)
+	}
+	return tree, nil
+}
+
+func (impl *ArgoApplicationServiceImpl) HibernateApplicationSet(ctx context.Context, applicationSet *bean.ArgoApplicationSetIdentifier) (*bean.ArgoApplicationSetActionResponse, error) {
+	return impl.scaleApplicationSet(ctx, applicationSet, impl.HibernateArgoApplication)
+}
+
+func (impl *ArgoApplicationServiceImpl) UnHibernateApplicationSet(ctx context.Context, applicationSet *bean.ArgoApplicationSetIdentifier) (*bean.ArgoApplicationSetActionResponse, error) {
+	return impl.scaleApplicationSet(ctx, applicationSet, impl.UnHibernateArgoApplication)
+}
+
+// scaleApplicationSet hibernates or un-hibernates the scalable resources of every application generated by the set
+// on the cluster the application is deployed to
+func (impl *ArgoApplicationServiceImpl) scaleApplicationSet(ctx context.Context, applicationSet *bean.ArgoApplicationSetIdentifier,
+	scaleFunc func(ctx context.Context, app *bean.ArgoAppIdentifier, hibernateRequest *openapi.HibernateRequest) ([]*openapi.HibernateStatus, error)) (*bean.ArgoApplicationSetActionResponse, error) {
+	_, generatedApps, clusterObj, _, err := impl.getApplicationSetAndGeneratedApps(ctx, applicationSet)
+	if err != nil {
+		return nil, err
+	}
+	clusters, err := impl.clusterRepository.FindAllActive()
+	if err != nil {
+		impl.logger.Errorw("error in getting all active clusters", "err", err)
+		return nil, err
+	}
+	clusterServerUrlIdMap := make(map[string]int, len(clusters))
+	for _, cluster := range clusters {
+		clusterServerUrlIdMap[cluster.ServerUrl] = cluster.Id
+	}
+	response := &bean.ArgoApplicationSetActionResponse{
+		ArgoApplicationSetIdentifier: applicationSet,
+		Results:                      make([]*bean.ArgoApplicationSetActionResult, 0, len(generatedApps)),
+	}
+	for i := range generatedApps {
+		result := &bean.ArgoApplicationSetActionResult{
+			AppName:   generatedApps[i].GetName(),
+			Namespace: generatedApps[i].GetNamespace(),
+		}
+		response.Results = append(response.Results, result)
+		_, _, destinationServer, argoManagedResources := helper.GetHealthSyncStatusDestinationServerAndManagedResourcesForArgoK8sRawObject(generatedApps[i].Object)
+		targetClusterId := 0
+		if destinationServer == k8sCommonBean.DefaultClusterUrl {
+			targetClusterId = clusterObj.Id
+		} else if clusterId, ok := clusterServerUrlIdMap[destinationServer]; ok {
+			targetClusterId = clusterId
+		}
+		if targetClusterId == 0 {
+			result.ErrorMessage = fmt.Sprintf("destination cluster %s is not added on devtron", destinationServer)
+			continue
+		}
+		targetObjects := helper.GetHibernateTargetObjects(argoManagedResources)
+		if len(targetObjects) == 0 {
+			result.Success = true
+			continue
+		}
+		appIdentifier := &bean.ArgoAppIdentifier{
+			ClusterId: targetClusterId,
+			Namespace: generatedApps[i].GetNamespace(),
+			AppName:   generatedApps[i].GetName(),
+		}
+		hibernateStatus, err := scaleFunc(ctx, appIdentifier, &openapi.HibernateRequest{Resources: &targetObjects})
+		if err != nil {
+			impl.logger.Errorw("error in scaling resources of generated application", "err", err, "applicationSet", applicationSet, "appName", appIdentifier.AppName)
+			result.ErrorMessage = err.Error()
+			continue
+		}
+		result.HibernateStatus = hibernateStatus
+		result.Success = true
+		for _, status := range hibernateStatus {
+			if status != nil && status.Success != nil && !*status.Success {
+				result.Success = false
+			}
+		}
+	}
+	return response, nil
+}
+
+// SyncApplicationSet triggers a sync of every application generated by the set by setting the operation on the
+// Application object, same as the argocd cli does. Applications with a sync already running are left untouched.
+func (impl *ArgoApplicationServiceImpl) SyncApplicationSet(ctx context.Context, applicationSet *bean.ArgoApplicationSetIdentifier, userEmail string) (*bean.ArgoApplicationSetActionResponse, error) {
+	_, generatedApps, _, restConfig, err := impl.getApplicationSetAndGeneratedApps(ctx, applicationSet)
+	if err != nil {
+		return nil, err
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"operation": map[string]interface{}{
+			"initiatedBy": map[string]interface{}{"username": userEmail},
+			"sync": map[string]interface{}{
+				"syncStrategy": map[string]interface{}{"hook": map[string]interface{}{}},
+			},
+		},
+	})
+	if err != nil {
+		impl.logger.Errorw("error in marshalling sync operation", "err", err)
+		return nil, err
+	}
+	response := &bean.ArgoApplicationSetActionResponse{
+		ArgoApplicationSetIdentifier: applicationSet,
+		Results:                      make([]*bean.ArgoApplicationSetActionResult, 0, len(generatedApps)),
+	}
+	for i := range generatedApps {
+		result := &bean.ArgoApplicationSetActionResult{
+			AppName:   generatedApps[i].GetName(),
+			Namespace: generatedApps[i].GetNamespace(),
+		}
+		response.Results = append(response.Results, result)
+		phase, _, _ := unstructured.NestedString(generatedApps[i].Object, k8sCommonBean.K8sClusterResourceStatusKey, "operationState", "phase")
+		if phase == argoOperationPhaseRunning {
+			result.ErrorMessage = "another operation is already in progress"
+			continue
+		}
+		_, err = impl.k8sUtil.PatchResourceRequest(ctx, restConfig, types.MergePatchType, string(patch), result.AppName, result.Namespace, bean.GvkForArgoApplication)
+		if err != nil {
+			impl.logger.Errorw("error in triggering sync of generated application", "err", err, "applicationSet", applicationSet, "appName", result.AppName)
+			result.ErrorMessage = err.Error()
+			continue
+		}
+		result.Success = true
+	}
+	return response, nil
+}
+
+// getApplicationSetAndGeneratedApps returns the application set object along with the applications it has generated,
+// the cluster holding them and its rest config
+func (impl *ArgoApplicationServiceImpl) getApplicationSetAndGeneratedApps(ctx context.Context, applicationSet *bean.ArgoApplicationSetIdentifier) (*unstructured.Unstructured,
+	[]unstructured.Unstructured, clusterRepository.Cluster, *rest.Config, error) {
+	clusterConfig, clusterObj, _, err := impl.argoApplicationConfigService.GetClusterConfigFromAllClusters(applicationSet.ClusterId)
+	if err != nil {
+		impl.logger.Errorw("error in getting the cluster config", "err", err, "clusterId", applicationSet.ClusterId)
+		return nil, nil, clusterObj, nil, err
+	}
+	if clusterObj.Id == 0 {
+		return nil, nil, clusterObj, nil, util2.NewApiError(http.StatusNotFound, "cluster not found", "cluster not found")
+	}
+	restConfig, err := impl.k8sUtil.GetRestConfigByCluster(clusterConfig)
+	if err != nil {
+		impl.logger.Errorw("error in getting rest config by cluster Id", "err", err, "clusterId", applicationSet.ClusterId)
+		return nil, nil, clusterObj, nil, err
+	}
+	resp, err := impl.k8sUtil.GetResource(ctx, applicationSet.Namespace, applicationSet.Name, bean.GvkForArgoApplicationSet, restConfig)
+	if err != nil {
+		impl.logger.Errorw("error in getting application set", "err", err, "applicationSet", applicationSet)
+		if errors.IsNotFound(err) {
+			return nil, nil, clusterObj, nil, util2.NewApiError(http.StatusNotFound, "application set not found", err.Error())
+		}
+		return nil, nil, clusterObj, nil, err
+	}
+	applications, err := impl.listArgoResources(restConfig, bean.GvkForArgoApplication, applicationSet.Namespace)
+	if err != nil {
+		impl.logger.Errorw("error in getting argo application list", "err", err, "applicationSet", applicationSet)
+		return nil, nil, clusterObj, nil, err
+	}
+	generatedApps := make([]unstructured.Unstructured, 0)
+	for i := range applications {
+		if helper.IsOwnedByApplicationSet(&applications[i], applicationSet.Name) {
+			generatedApps = append(generatedApps, applications[i])
+		}
+	}
+	return &resp.Manifest, generatedApps, clusterObj, restConfig, nil
+}
+
+// listArgoResources lists the argo cd objects of the given kind, an empty list is returned if argo cd crds are not installed
+func (impl *ArgoApplicationServiceImpl) listArgoResources(restConfig *rest.Config, gvk schema.GroupVersionKind, namespace string) ([]unstructured.Unstructured, error) {
+	resp, _, err := impl.k8sUtil.GetResourceList(context.Background(), restConfig, gvk, namespace, false, nil)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			impl.logger.Warnw("argo resource not found on cluster", "err", err, "gvk", gvk)
+			return nil, nil
+		}
+		return nil, err
+	}
+	if resp == nil {
+		return nil, nil
+	}
+	return resp.Resources.Items, nil
+}
@@ -21,12 +21,14 @@ import (
 	"github.com/devtron-labs/common-lib/utils/k8s"
 	k8sCommonBean "github.com/devtron-labs/common-lib/utils/k8s/commonBean"
 	"github.com/devtron-labs/devtron/api/helm-app/gRPC"
+	openapi "github.com/devtron-labs/devtron/api/helm-app/openapiClient"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 const (
 	ArgoGroup                    = "argoproj.io"
 	ArgoApplicationKind          = "Application"
+	ArgoApplicationSetKind       = "ApplicationSet"
 	VersionV1Alpha1              = "v1alpha1"
 	AllNamespaces                = ""
 	DevtronCDNamespae            = "devtroncd"
@@ -45,6 +47,12 @@ var GvkForArgoApplication = schema.GroupVersionKind{
 	Version: VersionV1Alpha1,
 }
 
+var GvkForArgoApplicationSet = schema.GroupVersionKind{
+	Group:   ArgoGroup,
+	Kind:    ArgoApplicationSetKind,
+	Version: VersionV1Alpha1,
+}
+
 var GvkForSecret = schema.GroupVersionKind{
 	Kind:    k8sCommonBean.SecretKind,
 	Version: k8sCommonBean.V1VERSION,
@@ -91,6 +99,70 @@ type ArgoClusterConfigObj struct {
 	} `json:"tlsClientConfig"`
 }
 
+type ArgoApplicationSetStatus string
+
+const (
+	ArgoApplicationSetHealthy     ArgoApplicationSetStatus = "Healthy"
+	ArgoApplicationSetProgressing ArgoApplicationSetStatus = "Progressing"
+	ArgoApplicationSetError       ArgoApplicationSetStatus = "Error"
+	ArgoApplicationSetUnknown     ArgoApplicationSetStatus = "Unknown"
+)
+
+type ArgoApplicationSetListDto struct {
+	Name        string `json:"name"`
+	ClusterId   int    `json:"clusterId"`
+	ClusterName string `json:"clusterName"`
+	Namespace   string `json:"namespace"`
+	// Generators are the types of the generators of the set, e.g. list, clusters, git, matrix
+	Generators        []string                 `json:"generators"`
+	Status            ArgoApplicationSetStatus `json:"status"`
+	GeneratedAppCount int                      `json:"generatedAppCount"`
+}
+
+type ArgoApplicationSetCondition struct {
+	Type    string `json:"type"`
+	Status  string `json:"status"`
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+type ArgoApplicationSetDetailDto struct {
+	*ArgoApplicationSetListDto
+	GeneratorSpecs []interface{}                  `json:"generatorSpecs"`
+	Template       map[string]interface{}         `json:"template"`
+	SyncPolicy     map[string]interface{}         `json:"syncPolicy,omitempty"`
+	Conditions     []*ArgoApplicationSetCondition `json:"conditions,omitempty"`
+	Applications   []*ArgoApplicationListDto      `json:"applications"`
+	Manifest       map[string]interface{}         `json:"manifest"`
+}
+
+// ArgoApplicationSetTreeDto is an application set with the resource tree of every application generated by it
+type ArgoApplicationSetTreeDto struct {
+	*ArgoApplicationSetListDto
+	Applications []*ArgoApplicationDetailDto `json:"applications"`
+}
+
+type ArgoApplicationSetIdentifier struct {
+	ClusterId int    `json:"clusterId" validate:"required,number,gt=0"`
+	Namespace string `json:"namespace" validate:"required"`
+	Name      string `json:"name" validate:"required"`
+}
+
+// ArgoApplicationSetActionResponse is the outcome of an action on an application set, the action is applied on
+// every generated application independently and the failure of one does not stop the others
+type ArgoApplicationSetActionResponse struct {
+	*ArgoApplicationSetIdentifier
+	Results []*ArgoApplicationSetActionResult `json:"results"`
+}
+
+type ArgoApplicationSetActionResult struct {
+	AppName         string                     `json:"appName"`
+	Namespace       string                     `json:"namespace"`
+	Success         bool                       `json:"success"`
+	ErrorMessage    string                     `json:"errorMessage,omitempty"`
+	HibernateStatus []*openapi.HibernateStatus `json:"hibernateStatus,omitempty"`
+}
+
 type ArgoAppIdentifier struct {
 	ClusterId int    `json:"clusterId"`
 	Namespace string `json:"namespace"`
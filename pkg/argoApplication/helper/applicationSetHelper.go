@@ -0,0 +1,151 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package helper
+
+import (
+	k8sCommonBean "github.com/devtron-labs/common-lib/utils/k8s/commonBean"
+	openapi "github.com/devtron-labs/devtron/api/helm-app/openapiClient"
+	"github.com/devtron-labs/devtron/pkg/argoApplication/bean"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sort"
+)
+
+const (
+	applicationSetConditionErrorOccurred     = "ErrorOccurred"
+	applicationSetConditionResourcesUpToDate = "ResourcesUpToDate"
+	applicationSetConditionStatusTrue        = "True"
+)
+
+// scalableKinds are the kinds hibernated when an application set is hibernated, same as the ones scaled down by
+// the hibernation of a helm release
+var scalableKinds = map[string]bool{
+	k8sCommonBean.DeploymentKind:                true,
+	k8sCommonBean.StatefulSetKind:               true,
+	k8sCommonBean.K8sClusterResourceRolloutKind: true,
+}
+
+// GetApplicationSetListDto converts an ApplicationSet object into its list dto, generated app count is not filled here
+func GetApplicationSetListDto(obj *unstructured.Unstructured, clusterName string, clusterId int) *bean.ArgoApplicationSetListDto {
+	return &bean.ArgoApplicationSetListDto{
+		Name:        obj.GetName(),
+		Namespace:   obj.GetNamespace(),
+		ClusterId:   clusterId,
+		ClusterName: clusterName,
+		Generators:  GetApplicationSetGeneratorTypes(obj),
+		Status:      GetApplicationSetStatus(GetApplicationSetConditions(obj)),
+	}
+}
+
+// GetApplicationSetGeneratorTypes returns the generator types of an application set in the order they are declared,
+// each generator is an object keyed by its type (list, clusters, git, matrix, ...)
+func GetApplicationSetGeneratorTypes(obj *unstructured.Unstructured) []string {
+	generatorTypes := make([]string, 0)
+	generators, _, _ := unstructured.NestedSlice(obj.Object, k8sCommonBean.Spec, "generators")
+	for _, generatorRaw := range generators {
+		generator, ok := generatorRaw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		keys := make([]string, 0, len(generator))
+		for key := range generator {
+			// selector and values are generator options, not generator types
+			if key == "selector" || key == "values" {
+				continue
+			}
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		generatorTypes = append(generatorTypes, keys...)
+	}
+	return generatorTypes
+}
+
+func GetApplicationSetConditions(obj *unstructured.Unstructured) []*bean.ArgoApplicationSetCondition {
+	conditions := make([]*bean.ArgoApplicationSetCondition, 0)
+	conditionsRaw, _, _ := unstructured.NestedSlice(obj.Object, k8sCommonBean.K8sClusterResourceStatusKey, "conditions")
+	for _, conditionRaw := range conditionsRaw {
+		conditionObj, ok := conditionRaw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		condition := &bean.ArgoApplicationSetCondition{}
+		condition.Type, _, _ = unstructured.NestedString(conditionObj, "type")
+		condition.Status, _, _ = unstructured.NestedString(conditionObj, "status")
+		condition.Reason, _, _ = unstructured.NestedString(conditionObj, "reason")
+		condition.Message, _, _ = unstructured.NestedString(conditionObj, "message")
+		conditions = append(conditions, condition)
+	}
+	return conditions
+}
+
+// GetApplicationSetStatus derives the status of an application set from the conditions set by the applicationset controller
+func GetApplicationSetStatus(conditions []*bean.ArgoApplicationSetCondition) bean.ArgoApplicationSetStatus {
+	if len(conditions) == 0 {
+		return bean.ArgoApplicationSetUnknown
+	}
+	status := bean.ArgoApplicationSetProgressing
+	for _, condition := range conditions {
+		if condition.Status != applicationSetConditionStatusTrue {
+			continue
+		}
+		switch condition.Type {
+		case applicationSetConditionErrorOccurred:
+			return bean.ArgoApplicationSetError
+		case applicationSetConditionResourcesUpToDate:
+			status = bean.ArgoApplicationSetHealthy
+		}
+	}
+	return status
+}
+
+// IsOwnedByApplicationSet checks if an Application object is generated by the application set of given name
+func IsOwnedByApplicationSet(obj *unstructured.Unstructured, applicationSetName string) bool {
+	for _, ownerReference := range obj.GetOwnerReferences() {
+		if ownerReference.Kind == bean.ArgoApplicationSetKind && ownerReference.Name == applicationSetName {
+			return true
+		}
+	}
+	return false
+}
+
+// GetApplicationSetName returns the name of the application set owning an Application object, empty if not generated by one
+func GetApplicationSetName(obj *unstructured.Unstructured) string {
+	for _, ownerReference := range obj.GetOwnerReferences() {
+		if ownerReference.Kind == bean.ArgoApplicationSetKind {
+			return ownerReference.Name
+		}
+	}
+	return ""
+}
+
+// GetHibernateTargetObjects returns the scalable resources out of the resources managed by an argo application
+func GetHibernateTargetObjects(argoManagedResources []*bean.ArgoManagedResource) []openapi.HibernateTargetObject {
+	targetObjects := make([]openapi.HibernateTargetObject, 0)
+	for _, resource := range argoManagedResources {
+		if resource == nil || !scalableKinds[resource.Kind] {
+			continue
+		}
+		targetObjects = append(targetObjects, openapi.HibernateTargetObject{
+			Group:     &resource.Group,
+			Kind:      &resource.Kind,
+			Version:   &resource.Version,
+			Name:      &resource.Name,
+			Namespace: &resource.Namespace,
+		})
+	}
+	return targetObjects
+}
@@ -0,0 +1,79 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package helper
+
+import (
+	"github.com/devtron-labs/devtron/pkg/argoApplication/bean"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"testing"
+)
+
+func TestGetApplicationSetListDto(t *testing.T) {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "guestbook", "namespace": "argocd"},
+		"spec": map[string]interface{}{
+			"generators": []interface{}{
+				map[string]interface{}{"list": map[string]interface{}{}},
+				map[string]interface{}{"matrix": map[string]interface{}{}, "selector": map[string]interface{}{}},
+			},
+		},
+		"status": map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "ErrorOccurred", "status": "False"},
+				map[string]interface{}{"type": "ResourcesUpToDate", "status": "True", "reason": "ApplicationSetUpToDate"},
+			},
+		},
+	}}
+	dto := GetApplicationSetListDto(obj, "default_cluster", 1)
+	assert.Equal(t, "guestbook", dto.Name)
+	assert.Equal(t, "argocd", dto.Namespace)
+	assert.Equal(t, []string{"list", "matrix"}, dto.Generators)
+	assert.Equal(t, bean.ArgoApplicationSetHealthy, dto.Status)
+}
+
+func TestGetApplicationSetStatus(t *testing.T) {
+	assert.Equal(t, bean.ArgoApplicationSetUnknown, GetApplicationSetStatus(nil))
+	assert.Equal(t, bean.ArgoApplicationSetError, GetApplicationSetStatus([]*bean.ArgoApplicationSetCondition{
+		{Type: "ResourcesUpToDate", Status: "True"},
+		{Type: "ErrorOccurred", Status: "True"},
+	}))
+	assert.Equal(t, bean.ArgoApplicationSetProgressing, GetApplicationSetStatus([]*bean.ArgoApplicationSetCondition{
+		{Type: "ResourcesUpToDate", Status: "False"},
+	}))
+}
+
+func TestIsOwnedByApplicationSet(t *testing.T) {
+	app := &unstructured.Unstructured{}
+	app.SetOwnerReferences([]metav1.OwnerReference{{Kind: "ApplicationSet", Name: "guestbook"}})
+	assert.True(t, IsOwnedByApplicationSet(app, "guestbook"))
+	assert.False(t, IsOwnedByApplicationSet(app, "other"))
+	assert.Equal(t, "guestbook", GetApplicationSetName(app))
+	assert.Equal(t, "", GetApplicationSetName(&unstructured.Unstructured{}))
+}
+
+func TestGetHibernateTargetObjects(t *testing.T) {
+	targetObjects := GetHibernateTargetObjects([]*bean.ArgoManagedResource{
+		{Group: "apps", Kind: "Deployment", Version: "v1", Name: "web", Namespace: "default"},
+		{Kind: "Service", Version: "v1", Name: "web", Namespace: "default"},
+		{Group: "argoproj.io", Kind: "Rollout", Version: "v1alpha1", Name: "api", Namespace: "default"},
+	})
+	assert.Len(t, targetObjects, 2)
+	assert.Equal(t, "web", *targetObjects[0].Name)
+	assert.Equal(t, "Rollout", *targetObjects[1].Kind)
+}
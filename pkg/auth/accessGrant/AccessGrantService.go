@@ -0,0 +1,601 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accessGrant
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/caarlos0/env"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/auth/accessGrant/bean"
+	"github.com/devtron-labs/devtron/pkg/auth/accessGrant/repository"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	casbinBean "github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin/bean"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	"github.com/devtron-labs/devtron/pkg/auth/user/adapter"
+	userBean "github.com/devtron-labs/devtron/pkg/auth/user/bean"
+	userRepository "github.com/devtron-labs/devtron/pkg/auth/user/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	cron2 "github.com/devtron-labs/devtron/util/cron"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type AccessGrantConfig struct {
+	AccessGrantExpiryCron         string `env:"ACCESS_GRANT_EXPIRY_CRON" envDefault:"* * * * *" description:"Cron schedule for revoking the expired temporary access grants of users and role groups"`
+	AccessGrantMaxDurationMinutes int    `env:"ACCESS_GRANT_MAX_DURATION_MINUTES" envDefault:"1440" description:"Maximum duration in minutes of a temporary access grant or request"`
+}
+
+type AccessGrantService interface {
+	// GrantAccess grants the role filters to a user or a role group until the grant expires
+	GrantAccess(request *bean.AccessGrantRequest) (*bean.AccessGrantDto, error)
+	// RequestAccess requests the role filters for the requesting user, the access is granted once approved
+	RequestAccess(request *bean.AccessGrantRequest) (*bean.AccessGrantDto, error)
+	ApproveRequest(id int, request *bean.AccessGrantReviewRequest) (*bean.AccessGrantDto, error)
+	RejectRequest(id int, request *bean.AccessGrantReviewRequest) (*bean.AccessGrantDto, error)
+	CancelRequest(id int, userId int32) (*bean.AccessGrantDto, error)
+	RevokeGrant(id int, request *bean.AccessGrantReviewRequest) (*bean.AccessGrantDto, error)
+	GetAccessGrant(id int) (*bean.AccessGrantDto, error)
+	GetAccessGrants(request *bean.AccessGrantListRequest) ([]*bean.AccessGrantDto, error)
+	// RevokeExpiredGrants revokes the roles of the expired grants and syncs casbin, it is run by the expiry cron
+	RevokeExpiredGrants()
+}
+
+type AccessGrantServiceImpl struct {
+	logger                *zap.SugaredLogger
+	accessGrantRepository repository.AccessGrantRepository
+	userService           user.UserService
+	roleGroupService      user.RoleGroupService
+	userAuditService      user.UserAuditService
+	userRepository        userRepository.UserRepository
+	userAuthRepository    userRepository.UserAuthRepository
+	roleGroupRepository   userRepository.RoleGroupRepository
+	maxDuration           time.Duration
+	// grantLock serialises the changes of the roles of the subjects by the api and the expiry cron
+	grantLock sync.Mutex
+	cron      *cron.Cron
+}
+
+func NewAccessGrantServiceImpl(logger *zap.SugaredLogger,
+	accessGrantRepository repository.AccessGrantRepository,
+	userService user.UserService,
+	roleGroupService user.RoleGroupService,
+	userAuditService user.UserAuditService,
+	userRepository userRepository.UserRepository,
+	userAuthRepository userRepository.UserAuthRepository,
+	roleGroupRepository userRepository.RoleGroupRepository,
+	cronLogger *cron2.CronLoggerImpl) *AccessGrantServiceImpl {
+	impl := &AccessGrantServiceImpl{
+		logger:                logger,
+		accessGrantRepository: accessGrantRepository,
+		userService:           userService,
+		roleGroupService:      roleGroupService,
+		userAuditService:      userAuditService,
+		userRepository:        userRepository,
+		userAuthRepository:    userAuthRepository,
+		roleGroupRepository:   roleGroupRepository,
+		maxDuration:           24 * time.Hour,
+	}
+	cfg := &AccessGrantConfig{}
+	if err := env.Parse(cfg); err != nil {
+		logger.Errorw("error in parsing access grant config", "err", err)
+		return impl
+	}
+	if cfg.AccessGrantMaxDurationMinutes > 0 {
+		impl.maxDuration = time.Duration(cfg.AccessGrantMaxDurationMinutes) * time.Minute
+	}
+	impl.cron = cron.New(cron.WithChain(cron.Recover(cronLogger)))
+	impl.cron.Start()
+	_, err := impl.cron.AddFunc(cfg.AccessGrantExpiryCron, impl.RevokeExpiredGrants)
+	if err != nil {
+		logger.Errorw("error in starting access grant expiry cron", "cron", cfg.AccessGrantExpiryCron, "err", err)
+	}
+	return impl
+}
+
+// allowAll is the manager auth of the user and role group updates, the grant is authorised by the rest handler
+func allowAll(resource, token string, object string) bool {
+	return true
+}
+
+func (impl *AccessGrantServiceImpl) GrantAccess(request *bean.AccessGrantRequest) (*bean.AccessGrantDto, error) {
+	if err := impl.validateRequest(request); err != nil {
+		return nil, err
+	}
+	accessGrant, err := impl.saveAccessGrant(request, bean.AccessGrantActive, bean.AccessGrantActionGranted)
+	if err != nil {
+		return nil, err
+	}
+	accessGrant.ReviewedBy = request.UserId
+	accessGrant.ReviewedOn = accessGrant.CreatedOn
+	if err = impl.activate(accessGrant, "", "", request.UserId); err != nil {
+		return nil, err
+	}
+	return impl.GetAccessGrant(accessGrant.Id)
+}
+
+func (impl *AccessGrantServiceImpl) RequestAccess(request *bean.AccessGrantRequest) (*bean.AccessGrantDto, error) {
+	// elevated access is requested by users for themselves
+	request.SubjectType = bean.SubjectTypeUser
+	request.SubjectId = request.UserId
+	if err := impl.validateRequest(request); err != nil {
+		return nil, err
+	}
+	accessGrant, err := impl.saveAccessGrant(request, bean.AccessGrantRequested, bean.AccessGrantActionRequested)
+	if err != nil {
+		return nil, err
+	}
+	return impl.GetAccessGrant(accessGrant.Id)
+}
+
+func (impl *AccessGrantServiceImpl) ApproveRequest(id int, request *bean.AccessGrantReviewRequest) (*bean.AccessGrantDto, error) {
+	accessGrant, err := impl.getAccessGrantInStatus(id, bean.AccessGrantRequested)
+	if err != nil {
+		return nil, err
+	}
+	if accessGrant.RequestedBy == request.UserId {
+		return nil, util.NewApiError(http.StatusForbidden, "access requests cannot be approved by the requester", "self approval of access request")
+	}
+	accessGrant.ReviewedBy = request.UserId
+	accessGrant.ReviewedOn = time.Now()
+	accessGrant.ReviewComment = request.Comment
+	if err = impl.activate(accessGrant, bean.AccessGrantActionApproved, request.Comment, request.UserId); err != nil {
+		return nil, err
+	}
+	return impl.GetAccessGrant(accessGrant.Id)
+}
+
+func (impl *AccessGrantServiceImpl) RejectRequest(id int, request *bean.AccessGrantReviewRequest) (*bean.AccessGrantDto, error) {
+	accessGrant, err := impl.getAccessGrantInStatus(id, bean.AccessGrantRequested)
+	if err != nil {
+		return nil, err
+	}
+	accessGrant.ReviewedBy = request.UserId
+	accessGrant.ReviewedOn = time.Now()
+	accessGrant.ReviewComment = request.Comment
+	if err = impl.updateStatus(accessGrant, bean.AccessGrantRejected, bean.AccessGrantActionRejected, request.Comment, request.UserId); err != nil {
+		return nil, err
+	}
+	return impl.GetAccessGrant(accessGrant.Id)
+}
+
+func (impl *AccessGrantServiceImpl) CancelRequest(id int, userId int32) (*bean.AccessGrantDto, error) {
+	accessGrant, err := impl.getAccessGrantInStatus(id, bean.AccessGrantRequested)
+	if err != nil {
+		return nil, err
+	}
+	if accessGrant.RequestedBy != userId {
+		return nil, util.NewApiError(http.StatusForbidden, "access requests can only be cancelled by the requester", "cancel of access request by other user")
+	}
+	if err = impl.updateStatus(accessGrant, bean.AccessGrantCancelled, bean.AccessGrantActionCancelled, "", userId); err != nil {
+		return nil, err
+	}
+	return impl.GetAccessGrant(accessGrant.Id)
+}
+
+func (impl *AccessGrantServiceImpl) RevokeGrant(id int, request *bean.AccessGrantReviewRequest) (*bean.AccessGrantDto, error) {
+	impl.grantLock.Lock()
+	defer impl.grantLock.Unlock()
+	accessGrant, err := impl.getAccessGrantInStatus(id, bean.AccessGrantActive)
+	if err != nil {
+		return nil, err
+	}
+	if err = impl.revoke(accessGrant, bean.AccessGrantRevoked, bean.AccessGrantActionRevoked, request.Comment, request.UserId); err != nil {
+		return nil, err
+	}
+	return impl.GetAccessGrant(accessGrant.Id)
+}
+
+func (impl *AccessGrantServiceImpl) GetAccessGrant(id int) (*bean.AccessGrantDto, error) {
+	accessGrant, err := impl.accessGrantRepository.FindById(id)
+	if util.IsErrNoRows(err) {
+		return nil, util.NewApiError(http.StatusNotFound, "access grant not found", fmt.Sprintf("access grant %d not found", id))
+	} else if err != nil {
+		impl.logger.Errorw("error in getting access grant", "id", id, "err", err)
+		return nil, err
+	}
+	accessGrantDtos, err := impl.adaptAccessGrants([]*repository.UserAccessGrant{accessGrant})
+	if err != nil {
+		return nil, err
+	}
+	accessGrantDto := accessGrantDtos[0]
+	accessGrantDto.AuditTrail, err = impl.userAuditService.GetAccessGrantAudits(id)
+	if err != nil {
+		return nil, err
+	}
+	return accessGrantDto, nil
+}
+
+func (impl *AccessGrantServiceImpl) GetAccessGrants(request *bean.AccessGrantListRequest) ([]*bean.AccessGrantDto, error) {
+	accessGrants, err := impl.accessGrantRepository.FindAll(string(request.Status), string(request.SubjectType), request.SubjectId, request.RequestedBy)
+	if err != nil {
+		impl.logger.Errorw("error in getting access grants", "request", request, "err", err)
+		return nil, err
+	}
+	return impl.adaptAccessGrants(accessGrants)
+}
+
+func (impl *AccessGrantServiceImpl) RevokeExpiredGrants() {
+	impl.grantLock.Lock()
+	defer impl.grantLock.Unlock()
+	accessGrants, err := impl.accessGrantRepository.FindExpiredByStatus(string(bean.AccessGrantActive), time.Now())
+	if err != nil {
+		impl.logger.Errorw("error in getting expired access grants", "err", err)
+		return
+	}
+	if len(accessGrants) == 0 {
+		return
+	}
+	for _, accessGrant := range accessGrants {
+		err = impl.revoke(accessGrant, bean.AccessGrantExpired, bean.AccessGrantActionExpired, "", userBean.SystemUserId)
+		if err != nil {
+			impl.logger.Errorw("error in revoking expired access grant", "id", accessGrant.Id, "err", err)
+		}
+	}
+	// re-sync casbin with the roles remaining in orchestrator after the revocation
+	if _, err = impl.userService.SyncOrchestratorToCasbin(); err != nil {
+		impl.logger.Errorw("error in syncing orchestrator to casbin after revoking expired access grants", "err", err)
+	}
+}
+
+func (impl *AccessGrantServiceImpl) validateRequest(request *bean.AccessGrantRequest) error {
+	if request.SubjectType != bean.SubjectTypeUser && request.SubjectType != bean.SubjectTypeGroup {
+		return util.NewApiError(http.StatusBadRequest, "subject type must be user or group", "invalid subject type")
+	}
+	if request.SubjectId <= 0 {
+		return util.NewApiError(http.StatusBadRequest, "subject id is required", "invalid subject id")
+	}
+	if len(request.RoleFilters) == 0 {
+		return util.NewApiError(http.StatusBadRequest, "role filters are required", "no role filters in request")
+	}
+	if duration := time.Duration(request.DurationMinutes) * time.Minute; duration <= 0 || duration > impl.maxDuration {
+		return util.NewApiError(http.StatusBadRequest, fmt.Sprintf("duration must be between 1 and %d minutes", int(impl.maxDuration.Minutes())), "invalid duration")
+	}
+	if hasSuperAdminRoleFilter(request.RoleFilters) {
+		return util.NewApiError(http.StatusBadRequest, "super admin access cannot be granted temporarily", "super admin role filter in request")
+	}
+	return nil
+}
+
+func (impl *AccessGrantServiceImpl) saveAccessGrant(request *bean.AccessGrantRequest, status bean.AccessGrantStatus, action bean.AccessGrantAction) (*repository.UserAccessGrant, error) {
+	roleFilters, err := json.Marshal(request.RoleFilters)
+	if err != nil {
+		return nil, err
+	}
+	accessGrant := &repository.UserAccessGrant{
+		SubjectType:     string(request.SubjectType),
+		SubjectId:       request.SubjectId,
+		RoleFilters:     string(roleFilters),
+		DurationMinutes: request.DurationMinutes,
+		Justification:   request.Justification,
+		Status:          string(status),
+		RequestedBy:     request.UserId,
+		AuditLog:        sql.NewDefaultAuditLog(request.UserId),
+	}
+	err = impl.accessGrantRepository.Save(accessGrant)
+	if err != nil {
+		impl.logger.Errorw("error in saving access grant", "subjectType", request.SubjectType, "subjectId", request.SubjectId, "err", err)
+		return nil, err
+	}
+	impl.saveAudit(accessGrant.Id, action, request.Justification, request.UserId)
+	return accessGrant, nil
+}
+
+func (impl *AccessGrantServiceImpl) getAccessGrantInStatus(id int, status bean.AccessGrantStatus) (*repository.UserAccessGrant, error) {
+	accessGrant, err := impl.accessGrantRepository.FindById(id)
+	if util.IsErrNoRows(err) {
+		return nil, util.NewApiError(http.StatusNotFound, "access grant not found", fmt.Sprintf("access grant %d not found", id))
+	} else if err != nil {
+		impl.logger.Errorw("error in getting access grant", "id", id, "err", err)
+		return nil, err
+	}
+	if accessGrant.Status != string(status) {
+		return nil, util.NewApiError(http.StatusConflict, fmt.Sprintf("access grant is %s", accessGrant.Status), fmt.Sprintf("access grant %d is %s, expected %s", id, accessGrant.Status, status))
+	}
+	return accessGrant, nil
+}
+
+// activate starts the grant and adds its role filters to the subject, the grant is marked as failed if the roles
+// could not be added. The grant is started before adding the roles, so that a request cancelled or reviewed
+// concurrently is not left with the roles added.
+func (impl *AccessGrantServiceImpl) activate(accessGrant *repository.UserAccessGrant, action bean.AccessGrantAction, comment string, userId int32) error {
+	impl.grantLock.Lock()
+	defer impl.grantLock.Unlock()
+	roleFilters, err := getRoleFilters(accessGrant)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	accessGrant.StartsOn = now
+	accessGrant.ExpiresOn = now.Add(time.Duration(accessGrant.DurationMinutes) * time.Minute)
+	if err = impl.updateStatus(accessGrant, bean.AccessGrantActive, action, comment, userId); err != nil {
+		return err
+	}
+	grantedRoleIds, err := impl.addRoleFilters(accessGrant.SubjectType, accessGrant.SubjectId, roleFilters, userId)
+	if err != nil {
+		impl.logger.Errorw("error in adding role filters of access grant", "id", accessGrant.Id, "err", err)
+		if updateErr := impl.updateStatus(accessGrant, bean.AccessGrantFailed, bean.AccessGrantActionFailed, err.Error(), userId); updateErr != nil {
+			impl.logger.Errorw("error in marking access grant as failed", "id", accessGrant.Id, "err", updateErr)
+		}
+		return err
+	}
+	accessGrant.GrantedRoleIds = grantedRoleIds
+	if err = impl.accessGrantRepository.Update(accessGrant); err != nil {
+		impl.logger.Errorw("error in saving granted roles of access grant", "id", accessGrant.Id, "err", err)
+		return err
+	}
+	return nil
+}
+
+// revoke removes the roles added by the grant from the subject. The role filters of the other active grants of the
+// subject are applied again, so that the roles shared with the revoked grant are kept until these grants expire.
+func (impl *AccessGrantServiceImpl) revoke(accessGrant *repository.UserAccessGrant, status bean.AccessGrantStatus, action bean.AccessGrantAction, comment string, userId int32) error {
+	if len(accessGrant.GrantedRoleIds) != 0 {
+		if err := impl.removeRoles(accessGrant.SubjectType, accessGrant.SubjectId, accessGrant.GrantedRoleIds); err != nil {
+			impl.logger.Errorw("error in removing roles of access grant", "id", accessGrant.Id, "err", err)
+			return err
+		}
+	}
+	if err := impl.updateStatus(accessGrant, status, action, comment, userId); err != nil {
+		return err
+	}
+	activeGrants, err := impl.accessGrantRepository.FindBySubjectAndStatus(accessGrant.SubjectType, accessGrant.SubjectId, string(bean.AccessGrantActive))
+	if err != nil {
+		impl.logger.Errorw("error in getting active access grants of subject", "subjectType", accessGrant.SubjectType, "subjectId", accessGrant.SubjectId, "err", err)
+		return err
+	}
+	for _, activeGrant := range activeGrants {
+		roleFilters, err := getRoleFilters(activeGrant)
+		if err != nil {
+			return err
+		}
+		restoredRoleIds, err := impl.addRoleFilters(activeGrant.SubjectType, activeGrant.SubjectId, roleFilters, userBean.SystemUserId)
+		if err != nil {
+			impl.logger.Errorw("error in restoring roles of active access grant", "id", activeGrant.Id, "err", err)
+			continue
+		}
+		if len(restoredRoleIds) == 0 {
+			continue
+		}
+		activeGrant.GrantedRoleIds = mergeRoleIds(activeGrant.GrantedRoleIds, restoredRoleIds)
+		activeGrant.UpdatedOn = time.Now()
+		if err = impl.accessGrantRepository.Update(activeGrant); err != nil {
+			impl.logger.Errorw("error in updating roles of active access grant", "id", activeGrant.Id, "err", err)
+		}
+	}
+	return nil
+}
+
+// updateStatus moves the grant to the status only if it is still in the status it was fetched in, a grant
+// approved, rejected or cancelled concurrently is not changed again
+func (impl *AccessGrantServiceImpl) updateStatus(accessGrant *repository.UserAccessGrant, status bean.AccessGrantStatus, action bean.AccessGrantAction, comment string, userId int32) error {
+	currentStatus := accessGrant.Status
+	accessGrant.Status = string(status)
+	accessGrant.UpdatedOn = time.Now()
+	accessGrant.UpdatedBy = userId
+	updated, err := impl.accessGrantRepository.UpdateInStatus(accessGrant, currentStatus)
+	if err != nil {
+		impl.logger.Errorw("error in updating access grant", "id", accessGrant.Id, "status", status, "err", err)
+		return err
+	}
+	if !updated {
+		accessGrant.Status = currentStatus
+		return util.NewApiError(http.StatusConflict, "access grant was changed by another request, please refresh", fmt.Sprintf("access grant %d is not %s anymore", accessGrant.Id, currentStatus))
+	}
+	if len(action) != 0 {
+		impl.saveAudit(accessGrant.Id, action, comment, userId)
+	}
+	return nil
+}
+
+// saveAudit records the action in the audit trail, a failure is logged as the action is already performed
+func (impl *AccessGrantServiceImpl) saveAudit(accessGrantId int, action bean.AccessGrantAction, comment string, userId int32) {
+	err := impl.userAuditService.SaveAccessGrantAudit(&user.AccessGrantAudit{
+		AccessGrantId: accessGrantId,
+		Action:        string(action),
+		Comment:       comment,
+		ActionBy:      userId,
+		ActionOn:      time.Now(),
+	})
+	if err != nil {
+		impl.logger.Errorw("error in saving access grant audit", "accessGrantId", accessGrantId, "action", action, "err", err)
+	}
+}
+
+// addRoleFilters adds the role filters to the existing role filters of the subject and returns the ids of the roles
+// which the subject did not have before
+func (impl *AccessGrantServiceImpl) addRoleFilters(subjectType string, subjectId int32, roleFilters []userBean.RoleFilter, userId int32) ([]int, error) {
+	existingRoleIds, err := impl.getRoleIds(subjectType, subjectId)
+	if err != nil {
+		return nil, err
+	}
+	switch bean.SubjectType(subjectType) {
+	case bean.SubjectTypeUser:
+		userInfo, err := impl.userService.GetByIdWithoutGroupClaims(subjectId)
+		if util.IsErrNoRows(err) {
+			return nil, util.NewApiError(http.StatusBadRequest, "user not found or inactive", fmt.Sprintf("user %d not found", subjectId))
+		} else if err != nil {
+			return nil, err
+		}
+		if userInfo.SuperAdmin {
+			return nil, util.NewApiError(http.StatusBadRequest, "user is a super admin", fmt.Sprintf("user %d is super admin", subjectId))
+		}
+		userInfo.RoleFilters = append(userInfo.RoleFilters, roleFilters...)
+		userInfo.UserId = userId
+		if _, err = impl.userService.UpdateUser(userInfo, "", nil, allowAll); err != nil {
+			return nil, err
+		}
+	case bean.SubjectTypeGroup:
+		roleGroup, err := impl.roleGroupService.FetchRoleGroupsById(subjectId)
+		if util.IsErrNoRows(err) {
+			return nil, util.NewApiError(http.StatusBadRequest, "role group not found", fmt.Sprintf("role group %d not found", subjectId))
+		} else if err != nil {
+			return nil, err
+		}
+		if roleGroup.SuperAdmin {
+			return nil, util.NewApiError(http.StatusBadRequest, "role group has super admin access", fmt.Sprintf("role group %d is super admin", subjectId))
+		}
+		roleGroup.RoleFilters = append(roleGroup.RoleFilters, roleFilters...)
+		roleGroup.UserId = userId
+		if _, err = impl.roleGroupService.UpdateRoleGroup(roleGroup, "", nil, allowAll); err != nil {
+			return nil, err
+		}
+	}
+	roleIds, err := impl.getRoleIds(subjectType, subjectId)
+	if err != nil {
+		return nil, err
+	}
+	return getAddedRoleIds(existingRoleIds, roleIds), nil
+}
+
+func (impl *AccessGrantServiceImpl) getRoleIds(subjectType string, subjectId int32) ([]int, error) {
+	var roles []*userRepository.RoleModel
+	var err error
+	if bean.SubjectType(subjectType) == bean.SubjectTypeGroup {
+		roles, err = impl.userAuthRepository.GetRolesByGroupId(subjectId)
+	} else {
+		roles, err = impl.userAuthRepository.GetRolesByUserId(subjectId)
+	}
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in getting roles of subject", "subjectType", subjectType, "subjectId", subjectId, "err", err)
+		return nil, err
+	}
+	roleIds := make([]int, 0, len(roles))
+	for _, role := range roles {
+		roleIds = append(roleIds, role.Id)
+	}
+	return roleIds, nil
+}
+
+// removeRoles deletes the role mappings of the subject and removes the group policies of the roles from casbin
+func (impl *AccessGrantServiceImpl) removeRoles(subjectType string, subjectId int32, roleIds []int) error {
+	removedRoleIds := make(map[int]bool, len(roleIds))
+	for _, roleId := range roleIds {
+		removedRoleIds[roleId] = true
+	}
+	var casbinSubject string
+	mappingIds := make([]int, 0, len(roleIds))
+	if bean.SubjectType(subjectType) == bean.SubjectTypeGroup {
+		roleGroup, err := impl.roleGroupRepository.GetRoleGroupById(subjectId)
+		if util.IsErrNoRows(err) {
+			// roles of deleted role groups are already removed
+			return nil
+		} else if err != nil {
+			return err
+		}
+		casbinSubject = roleGroup.CasbinName
+		mappings, err := impl.roleGroupRepository.GetRoleGroupRoleMappingByRoleGroupId(subjectId)
+		if err != nil && !util.IsErrNoRows(err) {
+			return err
+		}
+		for _, mapping := range mappings {
+			if removedRoleIds[mapping.RoleId] {
+				mappingIds = append(mappingIds, mapping.Id)
+			}
+		}
+	} else {
+		userModel, err := impl.userRepository.GetByIdIncludeDeleted(subjectId)
+		if err != nil {
+			return err
+		}
+		casbinSubject = userModel.EmailId
+		mappings, err := impl.userAuthRepository.GetUserRoleMappingByUserId(subjectId)
+		if err != nil && !util.IsErrNoRows(err) {
+			return err
+		}
+		for _, mapping := range mappings {
+			if removedRoleIds[mapping.RoleId] {
+				mappingIds = append(mappingIds, mapping.Id)
+			}
+		}
+	}
+	if len(mappingIds) == 0 {
+		return nil
+	}
+	roles, err := impl.userAuthRepository.GetRolesByIds(roleIds)
+	if err != nil {
+		return err
+	}
+	tx, err := impl.roleGroupRepository.StartATransaction()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if bean.SubjectType(subjectType) == bean.SubjectTypeGroup {
+		err = impl.roleGroupRepository.DeleteRoleGroupRoleMappingsByIds(tx, mappingIds)
+	} else {
+		err = impl.userAuthRepository.DeleteUserRoleMappingByIds(mappingIds, tx)
+	}
+	if err != nil {
+		return err
+	}
+	if err = impl.roleGroupRepository.CommitATransaction(tx); err != nil {
+		return err
+	}
+	policies := make([]casbinBean.Policy, 0, len(roles))
+	for _, role := range roles {
+		policies = append(policies, adapter.GetCasbinGroupPolicyForEmailAndRoleOnly(casbinSubject, role.Role))
+	}
+	casbin.RemovePolicy(policies)
+	return nil
+}
+
+func (impl *AccessGrantServiceImpl) adaptAccessGrants(accessGrants []*repository.UserAccessGrant) ([]*bean.AccessGrantDto, error) {
+	userIds := make([]int32, 0)
+	roleGroupIds := make([]int32, 0)
+	for _, accessGrant := range accessGrants {
+		if bean.SubjectType(accessGrant.SubjectType) == bean.SubjectTypeGroup {
+			roleGroupIds = append(roleGroupIds, accessGrant.SubjectId)
+		} else {
+			userIds = append(userIds, accessGrant.SubjectId)
+		}
+	}
+	subjectNames := make(map[string]string)
+	if len(userIds) != 0 {
+		users, err := impl.userRepository.GetByIds(userIds)
+		if err != nil && !util.IsErrNoRows(err) {
+			impl.logger.Errorw("error in getting users of access grants", "userIds", userIds, "err", err)
+			return nil, err
+		}
+		for _, userModel := range users {
+			subjectNames[getSubjectKey(string(bean.SubjectTypeUser), userModel.Id)] = userModel.EmailId
+		}
+	}
+	if len(roleGroupIds) != 0 {
+		roleGroups, err := impl.roleGroupRepository.GetRoleGroupListByIds(roleGroupIds)
+		if err != nil && !util.IsErrNoRows(err) {
+			impl.logger.Errorw("error in getting role groups of access grants", "roleGroupIds", roleGroupIds, "err", err)
+			return nil, err
+		}
+		for _, roleGroup := range roleGroups {
+			subjectNames[getSubjectKey(string(bean.SubjectTypeGroup), roleGroup.Id)] = roleGroup.Name
+		}
+	}
+	accessGrantDtos := make([]*bean.AccessGrantDto, 0, len(accessGrants))
+	for _, accessGrant := range accessGrants {
+		accessGrantDto, err := adaptAccessGrant(accessGrant)
+		if err != nil {
+			return nil, err
+		}
+		accessGrantDto.SubjectName = subjectNames[getSubjectKey(accessGrant.SubjectType, accessGrant.SubjectId)]
+		accessGrantDtos = append(accessGrantDtos, accessGrantDto)
+	}
+	return accessGrantDtos, nil
+}
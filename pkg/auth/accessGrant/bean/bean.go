@@ -0,0 +1,99 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import (
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	userBean "github.com/devtron-labs/devtron/pkg/auth/user/bean"
+	"time"
+)
+
+type SubjectType string
+
+const (
+	SubjectTypeUser  SubjectType = "user"
+	SubjectTypeGroup SubjectType = "group"
+)
+
+type AccessGrantStatus string
+
+const (
+	AccessGrantRequested AccessGrantStatus = "Requested"
+	AccessGrantActive    AccessGrantStatus = "Active"
+	AccessGrantRejected  AccessGrantStatus = "Rejected"
+	AccessGrantCancelled AccessGrantStatus = "Cancelled"
+	AccessGrantRevoked   AccessGrantStatus = "Revoked"
+	AccessGrantExpired   AccessGrantStatus = "Expired"
+	AccessGrantFailed    AccessGrantStatus = "Failed"
+)
+
+// AccessGrantAction is an action recorded in the audit trail of an access grant
+type AccessGrantAction string
+
+const (
+	AccessGrantActionRequested AccessGrantAction = "requested"
+	AccessGrantActionGranted   AccessGrantAction = "granted"
+	AccessGrantActionApproved  AccessGrantAction = "approved"
+	AccessGrantActionRejected  AccessGrantAction = "rejected"
+	AccessGrantActionCancelled AccessGrantAction = "cancelled"
+	AccessGrantActionRevoked   AccessGrantAction = "revoked"
+	AccessGrantActionExpired   AccessGrantAction = "expired"
+	AccessGrantActionFailed    AccessGrantAction = "failed"
+)
+
+// AccessGrantRequest grants role filters to a user or a role group for the given duration. For a request of
+// elevated access, the subject is the requesting user and the grant is applied once it is approved.
+type AccessGrantRequest struct {
+	SubjectType     SubjectType           `json:"subjectType" validate:"omitempty,oneof=user group"`
+	SubjectId       int32                 `json:"subjectId"`
+	RoleFilters     []userBean.RoleFilter `json:"roleFilters" validate:"required,min=1"`
+	DurationMinutes int                   `json:"durationMinutes" validate:"required,min=1"`
+	Justification   string                `json:"justification" validate:"required"`
+	UserId          int32                 `json:"-"`
+}
+
+type AccessGrantReviewRequest struct {
+	Comment string `json:"comment"`
+	UserId  int32  `json:"-"`
+}
+
+type AccessGrantListRequest struct {
+	Status      AccessGrantStatus
+	SubjectType SubjectType
+	SubjectId   int32
+	// RequestedBy lists the grants requested by or granted to the user, set for users who are not super admins
+	RequestedBy int32
+}
+
+type AccessGrantDto struct {
+	Id              int                      `json:"id"`
+	SubjectType     SubjectType              `json:"subjectType"`
+	SubjectId       int32                    `json:"subjectId"`
+	SubjectName     string                   `json:"subjectName"`
+	RoleFilters     []userBean.RoleFilter    `json:"roleFilters"`
+	DurationMinutes int                      `json:"durationMinutes"`
+	Justification   string                   `json:"justification"`
+	Status          AccessGrantStatus        `json:"status"`
+	RequestedBy     int32                    `json:"requestedBy"`
+	RequestedOn     time.Time                `json:"requestedOn"`
+	ReviewedBy      int32                    `json:"reviewedBy,omitempty"`
+	ReviewedOn      *time.Time               `json:"reviewedOn,omitempty"`
+	ReviewComment   string                   `json:"reviewComment,omitempty"`
+	StartsOn        *time.Time               `json:"startsOn,omitempty"`
+	ExpiresOn       *time.Time               `json:"expiresOn,omitempty"`
+	AuditTrail      []*user.AccessGrantAudit `json:"auditTrail,omitempty"`
+}
@@ -0,0 +1,99 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accessGrant
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/devtron-labs/devtron/pkg/auth/accessGrant/bean"
+	"github.com/devtron-labs/devtron/pkg/auth/accessGrant/repository"
+	userBean "github.com/devtron-labs/devtron/pkg/auth/user/bean"
+	"sort"
+	"time"
+)
+
+func getRoleFilters(accessGrant *repository.UserAccessGrant) ([]userBean.RoleFilter, error) {
+	var roleFilters []userBean.RoleFilter
+	if err := json.Unmarshal([]byte(accessGrant.RoleFilters), &roleFilters); err != nil {
+		return nil, fmt.Errorf("invalid role filters of access grant %d: %w", accessGrant.Id, err)
+	}
+	return roleFilters, nil
+}
+
+func hasSuperAdminRoleFilter(roleFilters []userBean.RoleFilter) bool {
+	for _, roleFilter := range roleFilters {
+		if roleFilter.Action == userBean.SUPER_ADMIN {
+			return true
+		}
+	}
+	return false
+}
+
+// getAddedRoleIds returns the role ids which are not in the existing role ids, sorted
+func getAddedRoleIds(existingRoleIds []int, roleIds []int) []int {
+	existing := make(map[int]bool, len(existingRoleIds))
+	for _, roleId := range existingRoleIds {
+		existing[roleId] = true
+	}
+	addedRoleIds := make([]int, 0)
+	for _, roleId := range roleIds {
+		if !existing[roleId] {
+			existing[roleId] = true
+			addedRoleIds = append(addedRoleIds, roleId)
+		}
+	}
+	sort.Ints(addedRoleIds)
+	return addedRoleIds
+}
+
+func mergeRoleIds(roleIds []int, addedRoleIds []int) []int {
+	return append(append([]int{}, roleIds...), getAddedRoleIds(roleIds, addedRoleIds)...)
+}
+
+func getSubjectKey(subjectType string, subjectId int32) string {
+	return fmt.Sprintf("%s/%d", subjectType, subjectId)
+}
+
+func adaptAccessGrant(accessGrant *repository.UserAccessGrant) (*bean.AccessGrantDto, error) {
+	roleFilters, err := getRoleFilters(accessGrant)
+	if err != nil {
+		return nil, err
+	}
+	return &bean.AccessGrantDto{
+		Id:              accessGrant.Id,
+		SubjectType:     bean.SubjectType(accessGrant.SubjectType),
+		SubjectId:       accessGrant.SubjectId,
+		RoleFilters:     roleFilters,
+		DurationMinutes: accessGrant.DurationMinutes,
+		Justification:   accessGrant.Justification,
+		Status:          bean.AccessGrantStatus(accessGrant.Status),
+		RequestedBy:     accessGrant.RequestedBy,
+		RequestedOn:     accessGrant.CreatedOn,
+		ReviewedBy:      accessGrant.ReviewedBy,
+		ReviewedOn:      getTimePtr(accessGrant.ReviewedOn),
+		ReviewComment:   accessGrant.ReviewComment,
+		StartsOn:        getTimePtr(accessGrant.StartsOn),
+		ExpiresOn:       getTimePtr(accessGrant.ExpiresOn),
+	}, nil
+}
+
+func getTimePtr(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,99 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accessGrant
+
+import (
+	"github.com/devtron-labs/devtron/pkg/auth/accessGrant/repository"
+	userBean "github.com/devtron-labs/devtron/pkg/auth/user/bean"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGetAddedRoleIds(t *testing.T) {
+	tests := []struct {
+		name            string
+		existingRoleIds []int
+		roleIds         []int
+		want            []int
+	}{
+		{name: "no existing roles", existingRoleIds: nil, roleIds: []int{3, 1, 2}, want: []int{1, 2, 3}},
+		{name: "roles already present", existingRoleIds: []int{1, 2}, roleIds: []int{1, 2}, want: []int{}},
+		{name: "new roles only", existingRoleIds: []int{1, 2}, roleIds: []int{1, 2, 5, 4}, want: []int{4, 5}},
+		{name: "duplicate roles", existingRoleIds: []int{1}, roleIds: []int{4, 4, 1}, want: []int{4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getAddedRoleIds(tt.existingRoleIds, tt.roleIds); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("getAddedRoleIds() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeRoleIds(t *testing.T) {
+	roleIds := []int{2, 7}
+	got := mergeRoleIds(roleIds, []int{7, 3})
+	if want := []int{2, 7, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("mergeRoleIds() = %v, want %v", got, want)
+	}
+	if want := []int{2, 7}; !reflect.DeepEqual(roleIds, want) {
+		t.Errorf("mergeRoleIds() modified the role ids to %v", roleIds)
+	}
+}
+
+func TestHasSuperAdminRoleFilter(t *testing.T) {
+	if hasSuperAdminRoleFilter([]userBean.RoleFilter{{Entity: "apps", Action: "admin"}}) {
+		t.Errorf("hasSuperAdminRoleFilter() = true for admin role filter")
+	}
+	if !hasSuperAdminRoleFilter([]userBean.RoleFilter{{Entity: "apps", Action: "admin"}, {Action: userBean.SUPER_ADMIN}}) {
+		t.Errorf("hasSuperAdminRoleFilter() = false for super admin role filter")
+	}
+}
+
+func TestAdaptAccessGrant(t *testing.T) {
+	createdOn := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	accessGrant := &repository.UserAccessGrant{
+		Id:              1,
+		SubjectType:     "user",
+		SubjectId:       2,
+		RoleFilters:     `[{"entity":"apps","team":"devtron","environment":"prod","action":"trigger"}]`,
+		DurationMinutes: 60,
+		Justification:   "incident",
+		Status:          "Requested",
+		RequestedBy:     2,
+	}
+	accessGrant.CreatedOn = createdOn
+	accessGrantDto, err := adaptAccessGrant(accessGrant)
+	if err != nil {
+		t.Fatalf("adaptAccessGrant() error = %v", err)
+	}
+	if len(accessGrantDto.RoleFilters) != 1 || accessGrantDto.RoleFilters[0].Action != "trigger" || accessGrantDto.RoleFilters[0].Environment != "prod" {
+		t.Errorf("adaptAccessGrant() role filters = %+v", accessGrantDto.RoleFilters)
+	}
+	if accessGrantDto.StartsOn != nil || accessGrantDto.ExpiresOn != nil || accessGrantDto.ReviewedOn != nil {
+		t.Errorf("adaptAccessGrant() set the times of a requested grant")
+	}
+	if !accessGrantDto.RequestedOn.Equal(createdOn) {
+		t.Errorf("adaptAccessGrant() requested on = %v, want %v", accessGrantDto.RequestedOn, createdOn)
+	}
+
+	accessGrant.RoleFilters = "invalid"
+	if _, err = adaptAccessGrant(accessGrant); err == nil {
+		t.Errorf("adaptAccessGrant() expected error for invalid role filters")
+	}
+}
@@ -0,0 +1,139 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"github.com/go-pg/pg/orm"
+	"go.uber.org/zap"
+	"time"
+)
+
+type UserAccessGrant struct {
+	tableName       struct{}  `sql:"user_access_grant" pg:",discard_unknown_columns"`
+	Id              int       `sql:"id,pk"`
+	SubjectType     string    `sql:"subject_type,notnull"`
+	SubjectId       int32     `sql:"subject_id,notnull"`
+	RoleFilters     string    `sql:"role_filters,notnull"`
+	DurationMinutes int       `sql:"duration_minutes,notnull"`
+	Justification   string    `sql:"justification,notnull"`
+	Status          string    `sql:"status,notnull"`
+	RequestedBy     int32     `sql:"requested_by,notnull"`
+	ReviewedBy      int32     `sql:"reviewed_by"`
+	ReviewedOn      time.Time `sql:"reviewed_on"`
+	ReviewComment   string    `sql:"review_comment"`
+	StartsOn        time.Time `sql:"starts_on"`
+	ExpiresOn       time.Time `sql:"expires_on"`
+	// GrantedRoleIds are the roles added to the subject by the grant, roles the subject already had are not revoked
+	GrantedRoleIds []int `sql:"granted_role_ids,array"`
+	sql.AuditLog
+}
+
+type AccessGrantRepository interface {
+	Save(accessGrant *UserAccessGrant) error
+	Update(accessGrant *UserAccessGrant) error
+	// UpdateInStatus updates the access grant only if it is still in the status, returns false if it is not
+	UpdateInStatus(accessGrant *UserAccessGrant, status string) (bool, error)
+	FindById(id int) (*UserAccessGrant, error)
+	FindAll(status, subjectType string, subjectId, requestedBy int32) ([]*UserAccessGrant, error)
+	FindBySubjectAndStatus(subjectType string, subjectId int32, status string) ([]*UserAccessGrant, error)
+	FindExpiredByStatus(status string, expiresBefore time.Time) ([]*UserAccessGrant, error)
+}
+
+type AccessGrantRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewAccessGrantRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *AccessGrantRepositoryImpl {
+	return &AccessGrantRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (repo *AccessGrantRepositoryImpl) Save(accessGrant *UserAccessGrant) error {
+	return repo.dbConnection.Insert(accessGrant)
+}
+
+func (repo *AccessGrantRepositoryImpl) Update(accessGrant *UserAccessGrant) error {
+	return repo.dbConnection.Update(accessGrant)
+}
+
+func (repo *AccessGrantRepositoryImpl) UpdateInStatus(accessGrant *UserAccessGrant, status string) (bool, error) {
+	result, err := repo.dbConnection.Model(accessGrant).
+		WherePK().
+		Where("status = ?", status).
+		Update()
+	if err != nil {
+		return false, err
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+func (repo *AccessGrantRepositoryImpl) FindById(id int) (*UserAccessGrant, error) {
+	accessGrant := &UserAccessGrant{}
+	err := repo.dbConnection.Model(accessGrant).
+		Where("id = ?", id).
+		Select()
+	return accessGrant, err
+}
+
+func (repo *AccessGrantRepositoryImpl) FindAll(status, subjectType string, subjectId, requestedBy int32) ([]*UserAccessGrant, error) {
+	var accessGrants []*UserAccessGrant
+	query := repo.dbConnection.Model(&accessGrants)
+	if len(status) != 0 {
+		query = query.Where("status = ?", status)
+	}
+	if len(subjectType) != 0 {
+		query = query.Where("subject_type = ?", subjectType)
+	}
+	if subjectId != 0 {
+		query = query.Where("subject_id = ?", subjectId)
+	}
+	if requestedBy != 0 {
+		query = query.WhereGroup(func(q *orm.Query) (*orm.Query, error) {
+			q = q.WhereOr("requested_by = ?", requestedBy).
+				WhereOr("subject_type = ? AND subject_id = ?", "user", requestedBy)
+			return q, nil
+		})
+	}
+	err := query.Order("id DESC").Select()
+	return accessGrants, err
+}
+
+func (repo *AccessGrantRepositoryImpl) FindBySubjectAndStatus(subjectType string, subjectId int32, status string) ([]*UserAccessGrant, error) {
+	var accessGrants []*UserAccessGrant
+	err := repo.dbConnection.Model(&accessGrants).
+		Where("subject_type = ?", subjectType).
+		Where("subject_id = ?", subjectId).
+		Where("status = ?", status).
+		Order("id ASC").
+		Select()
+	return accessGrants, err
+}
+
+func (repo *AccessGrantRepositoryImpl) FindExpiredByStatus(status string, expiresBefore time.Time) ([]*UserAccessGrant, error) {
+	var accessGrants []*UserAccessGrant
+	err := repo.dbConnection.Model(&accessGrants).
+		Where("status = ?", status).
+		Where("expires_on <= ?", expiresBefore).
+		Order("expires_on ASC").
+		Select()
+	return accessGrants, err
+}
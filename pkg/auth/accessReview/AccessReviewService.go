@@ -0,0 +1,280 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accessReview
+
+import (
+	"fmt"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/auth/accessReview/bean"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	userBean "github.com/devtron-labs/devtron/pkg/auth/user/bean"
+	userRepository "github.com/devtron-labs/devtron/pkg/auth/user/repository"
+	"go.uber.org/zap"
+	"net/http"
+	"strings"
+)
+
+// AccessReviewService answers who has access to what from the casbin policies. The access given by the groups of
+// the token claims of sso users is only covered through role groups, as these groups are not known offline.
+type AccessReviewService interface {
+	// WhoCan returns the users, api tokens and role groups allowed the action on the object of the resource
+	WhoCan(request *bean.AccessReviewRequest) ([]*bean.AccessSubject, error)
+	// Explain returns the decision for the user with the roles and role groups allowing or denying it
+	Explain(request *bean.AccessReviewRequest) (*bean.AccessDecision, error)
+	// GetEntitlementReport returns the roles of all users, api tokens and role groups with their role filters
+	GetEntitlementReport() ([]*bean.Entitlement, error)
+}
+
+type AccessReviewServiceImpl struct {
+	logger              *zap.SugaredLogger
+	userRepository      userRepository.UserRepository
+	userAuthRepository  userRepository.UserAuthRepository
+	roleGroupRepository userRepository.RoleGroupRepository
+}
+
+func NewAccessReviewServiceImpl(logger *zap.SugaredLogger,
+	userRepository userRepository.UserRepository,
+	userAuthRepository userRepository.UserAuthRepository,
+	roleGroupRepository userRepository.RoleGroupRepository) *AccessReviewServiceImpl {
+	return &AccessReviewServiceImpl{
+		logger:              logger,
+		userRepository:      userRepository,
+		userAuthRepository:  userAuthRepository,
+		roleGroupRepository: roleGroupRepository,
+	}
+}
+
+func (impl *AccessReviewServiceImpl) WhoCan(request *bean.AccessReviewRequest) ([]*bean.AccessSubject, error) {
+	graph := loadPolicyGraph()
+	matchingPolicies := graph.getMatchingPolicies(request.Resource, request.Action, request.Object)
+	subjectGrants := make(map[string][]*grant)
+	subjects := make([]string, 0)
+	for _, subject := range graph.getSubjects() {
+		allowing, denying := graph.getGrants(subject, matchingPolicies)
+		if len(allowing) == 0 || len(denying) != 0 {
+			continue
+		}
+		subjects = append(subjects, subject)
+		subjectGrants[subject] = allowing
+	}
+	resolver, err := impl.newResolver(subjects, subjectGrants)
+	if err != nil {
+		return nil, err
+	}
+	accessSubjects := make([]*bean.AccessSubject, 0, len(subjects))
+	for _, subject := range subjects {
+		accessSubjects = append(accessSubjects, &bean.AccessSubject{
+			SubjectType: getSubjectType(subject),
+			Subject:     subject,
+			Name:        resolver.getSubjectName(subject),
+			GrantedBy:   resolver.getAccessPaths(subjectGrants[subject]),
+		})
+	}
+	return accessSubjects, nil
+}
+
+func (impl *AccessReviewServiceImpl) Explain(request *bean.AccessReviewRequest) (*bean.AccessDecision, error) {
+	emailId := strings.ToLower(request.EmailId)
+	if _, err := impl.userRepository.FetchActiveUserByEmail(emailId); err != nil {
+		if util.IsErrNoRows(err) {
+			return nil, util.NewApiError(http.StatusNotFound, "user not found", fmt.Sprintf("active user %s not found", emailId))
+		}
+		impl.logger.Errorw("error in getting user by email", "emailId", emailId, "err", err)
+		return nil, err
+	}
+	graph := loadPolicyGraph()
+	matchingPolicies := graph.getMatchingPolicies(request.Resource, request.Action, request.Object)
+	allowing, denying := graph.getGrants(emailId, matchingPolicies)
+	resolver, err := impl.newResolver([]string{emailId}, map[string][]*grant{emailId: append(allowing, denying...)})
+	if err != nil {
+		return nil, err
+	}
+	decision := &bean.AccessDecision{
+		EmailId:  emailId,
+		Resource: request.Resource,
+		Action:   request.Action,
+		Object:   request.Object,
+		Allowed:  len(allowing) != 0 && len(denying) == 0,
+		Allowing: resolver.getAccessPaths(allowing),
+		Denying:  resolver.getAccessPaths(denying),
+	}
+	switch {
+	case len(denying) != 0:
+		decision.Reason = "denied by a policy of the roles of the user"
+	case len(allowing) != 0:
+		decision.Reason = "allowed by a policy of the roles of the user"
+	default:
+		decision.Reason = "no role of the user has a policy allowing the action"
+	}
+	return decision, nil
+}
+
+func (impl *AccessReviewServiceImpl) GetEntitlementReport() ([]*bean.Entitlement, error) {
+	graph := loadPolicyGraph()
+	subjects := graph.getSubjects()
+	subjectRolePaths := make(map[string][][]string, len(subjects))
+	roles := make([]string, 0)
+	for _, subject := range subjects {
+		subjectRolePaths[subject] = graph.getRolePaths(subject)
+		for _, path := range subjectRolePaths[subject] {
+			roles = append(roles, path[len(path)-1])
+		}
+	}
+	resolver, err := impl.newResolverForRoles(subjects, roles)
+	if err != nil {
+		return nil, err
+	}
+	entitlements := make([]*bean.Entitlement, 0)
+	for _, subject := range subjects {
+		for _, path := range subjectRolePaths[subject] {
+			role := path[len(path)-1]
+			entitlement := &bean.Entitlement{
+				SubjectType: getSubjectType(subject),
+				Subject:     subject,
+				RoleGroup:   strings.Join(resolver.getRoleGroupNames(getRoleGroupsInPath(path)), " > "),
+				Role:        role,
+			}
+			if roleModel, ok := resolver.roles[strings.ToLower(role)]; ok {
+				entitlement.Entity = roleModel.Entity
+				entitlement.AccessType = roleModel.AccessType
+				entitlement.Team = roleModel.Team
+				entitlement.Application = roleModel.EntityName
+				entitlement.Environment = roleModel.Environment
+				entitlement.Cluster = roleModel.Cluster
+				entitlement.Namespace = roleModel.Namespace
+				entitlement.Group = roleModel.Group
+				entitlement.Kind = roleModel.Kind
+				entitlement.Resource = roleModel.Resource
+				entitlement.Workflow = roleModel.Workflow
+				entitlement.Action = roleModel.Action
+			}
+			entitlements = append(entitlements, entitlement)
+		}
+	}
+	return entitlements, nil
+}
+
+func loadPolicyGraph() *policyGraph {
+	return newPolicyGraph(casbin.GetAllPolicies(), casbin.GetAllGroupingPolicies())
+}
+
+// resolver resolves the casbin names of role groups and roles to their orchestrator models
+type resolver struct {
+	roleGroupNames map[string]string
+	roles          map[string]*userRepository.RoleModel
+}
+
+func (impl *AccessReviewServiceImpl) newResolver(subjects []string, subjectGrants map[string][]*grant) (*resolver, error) {
+	roles := make([]string, 0)
+	for _, grants := range subjectGrants {
+		for _, g := range grants {
+			roles = append(roles, g.policy.role)
+			subjects = append(subjects, g.path...)
+		}
+	}
+	return impl.newResolverForRoles(subjects, roles)
+}
+
+// newResolverForRoles loads the role groups among the subjects and the roles
+func (impl *AccessReviewServiceImpl) newResolverForRoles(subjects []string, roles []string) (*resolver, error) {
+	r := &resolver{
+		roleGroupNames: make(map[string]string),
+		roles:          make(map[string]*userRepository.RoleModel),
+	}
+	casbinNames := make([]string, 0)
+	for _, subject := range subjects {
+		if getSubjectType(subject) == bean.SubjectTypeGroup {
+			casbinNames = append(casbinNames, subject)
+		}
+	}
+	if len(casbinNames) != 0 {
+		roleGroups, err := impl.roleGroupRepository.GetRoleGroupListByCasbinNames(casbinNames)
+		if err != nil && !util.IsErrNoRows(err) {
+			impl.logger.Errorw("error in getting role groups by casbin names", "err", err)
+			return nil, err
+		}
+		for _, roleGroup := range roleGroups {
+			r.roleGroupNames[strings.ToLower(roleGroup.CasbinName)] = roleGroup.Name
+		}
+	}
+	if len(roles) != 0 {
+		roleModels, err := impl.userAuthRepository.GetRoleByRoles(roles)
+		if err != nil && !util.IsErrNoRows(err) {
+			impl.logger.Errorw("error in getting roles", "err", err)
+			return nil, err
+		}
+		for i := range roleModels {
+			r.roles[strings.ToLower(roleModels[i].Role)] = &roleModels[i]
+		}
+	}
+	return r, nil
+}
+
+func (r *resolver) getSubjectName(subject string) string {
+	switch getSubjectType(subject) {
+	case bean.SubjectTypeGroup:
+		if name, ok := r.roleGroupNames[strings.ToLower(subject)]; ok {
+			return name
+		}
+		return strings.TrimPrefix(subject, groupPrefix)
+	case bean.SubjectTypeApiToken:
+		return subject[len(userBean.API_TOKEN_USER_EMAIL_PREFIX):]
+	}
+	return subject
+}
+
+func (r *resolver) getRoleGroupNames(casbinNames []string) []string {
+	names := make([]string, 0, len(casbinNames))
+	for _, casbinName := range casbinNames {
+		names = append(names, r.getSubjectName(casbinName))
+	}
+	return names
+}
+
+func (r *resolver) getAccessPaths(grants []*grant) []*bean.AccessPath {
+	accessPaths := make([]*bean.AccessPath, 0, len(grants))
+	for _, g := range grants {
+		accessPath := &bean.AccessPath{
+			RoleGroups: r.getRoleGroupNames(getRoleGroupsInPath(g.path)),
+			Role:       g.policy.role,
+			Policy: &bean.Policy{
+				Resource: g.policy.resource,
+				Action:   g.policy.action,
+				Object:   g.policy.object,
+				Effect:   g.policy.effect,
+			},
+		}
+		if roleModel, ok := r.roles[strings.ToLower(g.policy.role)]; ok {
+			accessPath.RoleFilter = &userBean.RoleFilter{
+				Entity:      roleModel.Entity,
+				Team:        roleModel.Team,
+				EntityName:  roleModel.EntityName,
+				Environment: roleModel.Environment,
+				Action:      roleModel.Action,
+				AccessType:  roleModel.AccessType,
+				Cluster:     roleModel.Cluster,
+				Namespace:   roleModel.Namespace,
+				Group:       roleModel.Group,
+				Kind:        roleModel.Kind,
+				Resource:    roleModel.Resource,
+				Workflow:    roleModel.Workflow,
+			}
+		}
+		accessPaths = append(accessPaths, accessPath)
+	}
+	return accessPaths
+}
@@ -0,0 +1,121 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import (
+	userBean "github.com/devtron-labs/devtron/pkg/auth/user/bean"
+	"strings"
+)
+
+type SubjectType string
+
+const (
+	SubjectTypeUser     SubjectType = "user"
+	SubjectTypeApiToken SubjectType = "apiToken"
+	SubjectTypeGroup    SubjectType = "group"
+)
+
+const (
+	EffectAllow = "allow"
+	EffectDeny  = "deny"
+)
+
+// AccessReviewRequest is an action on an object of a casbin resource, as enforced by the rest handlers,
+// e.g. resource "applications", action "trigger" and object "<team>/<app>".
+type AccessReviewRequest struct {
+	Resource string `json:"resource" validate:"required"`
+	Action   string `json:"action" validate:"required"`
+	Object   string `json:"object" validate:"required"`
+	// EmailId is the user whose decision is explained
+	EmailId string `json:"emailId,omitempty"`
+}
+
+type Policy struct {
+	Resource string `json:"resource"`
+	Action   string `json:"action"`
+	Object   string `json:"object"`
+	Effect   string `json:"effect"`
+}
+
+// AccessPath is how a subject gets a role, directly or through role groups, and the policy of the role
+// matching the reviewed action
+type AccessPath struct {
+	// RoleGroups are the names of the role groups from the subject to the role, empty if the role is assigned directly
+	RoleGroups []string             `json:"roleGroups,omitempty"`
+	Role       string               `json:"role"`
+	RoleFilter *userBean.RoleFilter `json:"roleFilter,omitempty"`
+	Policy     *Policy              `json:"policy,omitempty"`
+}
+
+type AccessSubject struct {
+	SubjectType SubjectType `json:"subjectType"`
+	// Subject is the casbin subject, the email id of users and api tokens and the casbin name of role groups
+	Subject   string        `json:"subject"`
+	Name      string        `json:"name"`
+	GrantedBy []*AccessPath `json:"grantedBy"`
+}
+
+type AccessDecision struct {
+	EmailId  string        `json:"emailId"`
+	Resource string        `json:"resource"`
+	Action   string        `json:"action"`
+	Object   string        `json:"object"`
+	Allowed  bool          `json:"allowed"`
+	Reason   string        `json:"reason"`
+	Allowing []*AccessPath `json:"allowing"`
+	Denying  []*AccessPath `json:"denying"`
+}
+
+// Entitlement is a role of a subject in the entitlement report
+type Entitlement struct {
+	SubjectType SubjectType `json:"subjectType"`
+	Subject     string      `json:"subject"`
+	RoleGroup   string      `json:"roleGroup"`
+	Role        string      `json:"role"`
+	Entity      string      `json:"entity"`
+	AccessType  string      `json:"accessType"`
+	Team        string      `json:"team"`
+	Application string      `json:"application"`
+	Environment string      `json:"environment"`
+	Cluster     string      `json:"cluster"`
+	Namespace   string      `json:"namespace"`
+	Group       string      `json:"group"`
+	Kind        string      `json:"kind"`
+	Resource    string      `json:"resource"`
+	Workflow    string      `json:"workflow"`
+	Action      string      `json:"action"`
+}
+
+var EntitlementCsvHeader = []string{"subjectType", "subject", "roleGroup", "role", "entity", "accessType", "team",
+	"application", "environment", "cluster", "namespace", "group", "kind", "resource", "workflow", "action"}
+
+func (entitlement *Entitlement) GetCsvRecord() []string {
+	return []string{string(entitlement.SubjectType), entitlement.Subject, entitlement.RoleGroup, entitlement.Role,
+		entitlement.Entity, entitlement.AccessType, entitlement.Team, entitlement.Application, entitlement.Environment,
+		entitlement.Cluster, entitlement.Namespace, entitlement.Group, entitlement.Kind, entitlement.Resource,
+		entitlement.Workflow, entitlement.Action}
+}
+
+const (
+	ReportFormatJson = "json"
+	ReportFormatCsv  = "csv"
+)
+
+func IsValidReportFormat(format string) bool {
+	format = strings.ToLower(format)
+	return format == ReportFormatJson || format == ReportFormatCsv
+}
@@ -0,0 +1,185 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accessReview
+
+import (
+	"github.com/devtron-labs/devtron/pkg/auth/accessReview/bean"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	userUtil "github.com/devtron-labs/devtron/pkg/auth/user/util"
+	"sort"
+	"strings"
+)
+
+const (
+	rolePrefix  = "role:"
+	groupPrefix = "group:"
+)
+
+type casbinPolicy struct {
+	role     string
+	resource string
+	action   string
+	object   string
+	effect   string
+}
+
+// policyGraph is an in memory view of the casbin policies. The g policies assign roles and role groups to users and
+// role groups to roles, the p policies allow or deny actions to roles.
+type policyGraph struct {
+	policies []*casbinPolicy
+	// parents are the roles and role groups assigned to a subject, sorted
+	parents map[string][]string
+}
+
+func newPolicyGraph(policies [][]string, groupingPolicies [][]string) *policyGraph {
+	graph := &policyGraph{
+		policies: make([]*casbinPolicy, 0, len(policies)),
+		parents:  make(map[string][]string),
+	}
+	for _, policy := range policies {
+		if len(policy) < 4 {
+			continue
+		}
+		effect := bean.EffectAllow
+		if len(policy) > 4 && len(policy[4]) != 0 {
+			effect = strings.ToLower(policy[4])
+		}
+		graph.policies = append(graph.policies, &casbinPolicy{
+			role:     policy[0],
+			resource: policy[1],
+			action:   policy[2],
+			object:   policy[3],
+			effect:   effect,
+		})
+	}
+	for _, groupingPolicy := range groupingPolicies {
+		if len(groupingPolicy) < 2 {
+			continue
+		}
+		graph.parents[groupingPolicy[0]] = append(graph.parents[groupingPolicy[0]], groupingPolicy[1])
+	}
+	for subject := range graph.parents {
+		sort.Strings(graph.parents[subject])
+	}
+	return graph
+}
+
+// getSubjects returns the users, api tokens and role groups having roles, sorted
+func (graph *policyGraph) getSubjects() []string {
+	subjects := make([]string, 0, len(graph.parents))
+	for subject := range graph.parents {
+		if !strings.HasPrefix(subject, rolePrefix) {
+			subjects = append(subjects, subject)
+		}
+	}
+	sort.Strings(subjects)
+	return subjects
+}
+
+// getPaths returns the path from the subject to each role and role group reachable from it, the subject included.
+// The shortest path is returned, as the enforcer only needs one path to match.
+func (graph *policyGraph) getPaths(subject string) map[string][]string {
+	paths := map[string][]string{subject: {subject}}
+	queue := []string{subject}
+	for len(queue) != 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, parent := range graph.parents[current] {
+			if _, ok := paths[parent]; ok {
+				continue
+			}
+			path := make([]string, len(paths[current]), len(paths[current])+1)
+			copy(path, paths[current])
+			paths[parent] = append(path, parent)
+			queue = append(queue, parent)
+		}
+	}
+	return paths
+}
+
+// getMatchingPolicies returns the policies matching the request, the same way the enforcer matches them
+func (graph *policyGraph) getMatchingPolicies(resource, action, object string) []*casbinPolicy {
+	object = strings.ToLower(object)
+	matchingPolicies := make([]*casbinPolicy, 0)
+	for _, policy := range graph.policies {
+		if casbin.MatchKeyByPart(resource, policy.resource) && casbin.MatchKeyByPart(action, policy.action) &&
+			casbin.MatchKeyByPart(object, policy.object) {
+			matchingPolicies = append(matchingPolicies, policy)
+		}
+	}
+	return matchingPolicies
+}
+
+type grant struct {
+	path   []string
+	policy *casbinPolicy
+}
+
+// getGrants returns the matching policies which allow and deny the request for the subject, with the path to the
+// role of the policy. The request is allowed if a policy allows it and none denies it.
+func (graph *policyGraph) getGrants(subject string, matchingPolicies []*casbinPolicy) (allowing []*grant, denying []*grant) {
+	paths := graph.getPaths(subject)
+	for _, policy := range matchingPolicies {
+		path, ok := paths[policy.role]
+		if !ok {
+			continue
+		}
+		if policy.effect == bean.EffectDeny {
+			denying = append(denying, &grant{path: path, policy: policy})
+		} else {
+			allowing = append(allowing, &grant{path: path, policy: policy})
+		}
+	}
+	return allowing, denying
+}
+
+// getRolePaths returns the paths from the subject to each of its roles, sorted by role
+func (graph *policyGraph) getRolePaths(subject string) [][]string {
+	paths := graph.getPaths(subject)
+	roles := make([]string, 0, len(paths))
+	for node := range paths {
+		if strings.HasPrefix(node, rolePrefix) {
+			roles = append(roles, node)
+		}
+	}
+	sort.Strings(roles)
+	rolePaths := make([][]string, 0, len(roles))
+	for _, role := range roles {
+		rolePaths = append(rolePaths, paths[role])
+	}
+	return rolePaths
+}
+
+func getSubjectType(subject string) bean.SubjectType {
+	if strings.HasPrefix(subject, groupPrefix) {
+		return bean.SubjectTypeGroup
+	} else if strings.HasPrefix(strings.ToLower(subject), strings.ToLower(userUtil.ApiTokenPrefix)) {
+		return bean.SubjectTypeApiToken
+	}
+	return bean.SubjectTypeUser
+}
+
+// getRoleGroupsInPath returns the role groups between the subject and the role of the path
+func getRoleGroupsInPath(path []string) []string {
+	roleGroups := make([]string, 0)
+	for i := 1; i < len(path)-1; i++ {
+		if strings.HasPrefix(path[i], groupPrefix) {
+			roleGroups = append(roleGroups, path[i])
+		}
+	}
+	return roleGroups
+}
@@ -0,0 +1,108 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accessReview
+
+import (
+	"github.com/devtron-labs/devtron/pkg/auth/accessReview/bean"
+	"reflect"
+	"testing"
+)
+
+func getTestPolicyGraph() *policyGraph {
+	policies := [][]string{
+		{"role:super-admin___", "*", "*", "*", "allow"},
+		{"role:trigger_devtron_prod_app1", "applications", "trigger", "devtron/app1", "allow"},
+		{"role:trigger_devtron_prod_app1", "environment", "trigger", "prod/app1", "allow"},
+		{"role:admin_devtron__", "applications", "*", "devtron/*", "allow"},
+		{"role:deny_devtron_app1", "applications", "trigger", "devtron/app1", "deny"},
+		{"role:view_devtron__", "applications", "get", "devtron/*"},
+	}
+	groupingPolicies := [][]string{
+		{"admin", "role:super-admin___"},
+		{"alice@example.com", "group:release_managers"},
+		{"group:release_managers", "role:trigger_devtron_prod_app1"},
+		{"bob@example.com", "role:admin_devtron__"},
+		{"bob@example.com", "role:deny_devtron_app1"},
+		{"carol@example.com", "role:view_devtron__"},
+		{"api-token:ci", "role:admin_devtron__"},
+	}
+	return newPolicyGraph(policies, groupingPolicies)
+}
+
+func TestPolicyGraphGetGrants(t *testing.T) {
+	graph := getTestPolicyGraph()
+	matchingPolicies := graph.getMatchingPolicies("applications", "trigger", "devtron/App1")
+	tests := []struct {
+		subject      string
+		wantAllowing [][]string
+		wantDenying  int
+	}{
+		{subject: "admin", wantAllowing: [][]string{{"admin", "role:super-admin___"}}},
+		{subject: "alice@example.com", wantAllowing: [][]string{{"alice@example.com", "group:release_managers", "role:trigger_devtron_prod_app1"}}},
+		{subject: "bob@example.com", wantAllowing: [][]string{{"bob@example.com", "role:admin_devtron__"}}, wantDenying: 1},
+		{subject: "carol@example.com"},
+		{subject: "unknown@example.com"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.subject, func(t *testing.T) {
+			allowing, denying := graph.getGrants(tt.subject, matchingPolicies)
+			gotAllowing := make([][]string, 0)
+			for _, g := range allowing {
+				gotAllowing = append(gotAllowing, g.path)
+			}
+			if len(tt.wantAllowing) == 0 {
+				tt.wantAllowing = [][]string{}
+			}
+			if !reflect.DeepEqual(gotAllowing, tt.wantAllowing) {
+				t.Errorf("getGrants() allowing = %v, want %v", gotAllowing, tt.wantAllowing)
+			}
+			if len(denying) != tt.wantDenying {
+				t.Errorf("getGrants() denying = %d, want %d", len(denying), tt.wantDenying)
+			}
+		})
+	}
+}
+
+func TestPolicyGraphGetSubjectsAndRolePaths(t *testing.T) {
+	graph := getTestPolicyGraph()
+	wantSubjects := []string{"admin", "alice@example.com", "api-token:ci", "bob@example.com", "carol@example.com", "group:release_managers"}
+	if got := graph.getSubjects(); !reflect.DeepEqual(got, wantSubjects) {
+		t.Errorf("getSubjects() = %v, want %v", got, wantSubjects)
+	}
+	wantPaths := [][]string{{"bob@example.com", "role:admin_devtron__"}, {"bob@example.com", "role:deny_devtron_app1"}}
+	if got := graph.getRolePaths("bob@example.com"); !reflect.DeepEqual(got, wantPaths) {
+		t.Errorf("getRolePaths() = %v, want %v", got, wantPaths)
+	}
+	path := graph.getRolePaths("alice@example.com")[0]
+	if got := getRoleGroupsInPath(path); !reflect.DeepEqual(got, []string{"group:release_managers"}) {
+		t.Errorf("getRoleGroupsInPath() = %v", got)
+	}
+}
+
+func TestGetSubjectType(t *testing.T) {
+	tests := map[string]bean.SubjectType{
+		"alice@example.com":      bean.SubjectTypeUser,
+		"group:release_managers": bean.SubjectTypeGroup,
+		"API-TOKEN:ci":           bean.SubjectTypeApiToken,
+		"api-token:ci":           bean.SubjectTypeApiToken,
+	}
+	for subject, want := range tests {
+		if got := getSubjectType(subject); got != want {
+			t.Errorf("getSubjectType(%s) = %s, want %s", subject, got, want)
+		}
+	}
+}
@@ -246,6 +246,30 @@ func GetAllSubjects() []string {
 	return e.GetAllSubjects()
 }
 
+// GetAllPolicies returns all the p policies as [sub, res, act, obj, eft]
+func GetAllPolicies() [][]string {
+	if isV2() {
+		policies, err := e2.GetPolicy()
+		if err != nil {
+			log.Println(err)
+		}
+		return policies
+	}
+	return e.GetPolicy()
+}
+
+// GetAllGroupingPolicies returns all the g policies as [subject, role or group]
+func GetAllGroupingPolicies() [][]string {
+	if isV2() {
+		policies, err := e2.GetGroupingPolicy()
+		if err != nil {
+			log.Println(err)
+		}
+		return policies
+	}
+	return e.GetGroupingPolicy()
+}
+
 func DeleteRoleForUser(user string, role string) bool {
 	user = strings.ToLower(user)
 	role = strings.ToLower(role)
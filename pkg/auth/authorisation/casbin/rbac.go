@@ -43,7 +43,7 @@ type Enforcer interface {
 	Enforce(token string, resource string, action string, resourceItem string) bool
 	//EnforceErr(emailId string, resource string, action string, resourceItem string) error
 	EnforceInBatch(token string, resource string, action string, vals []string) map[string]bool
-	//EnforceByEmail(emailId string, resource string, action string, resourceItem string) bool
+	EnforceByEmail(emailId string, resource string, action string, resourceItem string) bool
 	//EnforceByEmailInBatch(emailId string, resource string, action string, vals []string) map[string]bool
 	InvalidateCache(emailId string) bool
 	InvalidateCompleteCache()
@@ -0,0 +1,692 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package scim
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/auth/scim/bean"
+	"github.com/devtron-labs/devtron/pkg/auth/scim/repository"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	userBean "github.com/devtron-labs/devtron/pkg/auth/user/bean"
+	userHelper "github.com/devtron-labs/devtron/pkg/auth/user/helper"
+	userRepository "github.com/devtron-labs/devtron/pkg/auth/user/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"go.uber.org/zap"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// tokenLastUsedUpdateInterval throttles the update of the last used time of the token, the identity provider
+// calls the endpoints for every user and group on each sync
+const tokenLastUsedUpdateInterval = time.Minute
+
+type ScimService interface {
+	// GenerateToken generates a new scim token, the previous token is revoked
+	GenerateToken(userId int32) (*bean.ScimTokenDto, error)
+	GetToken() (*bean.ScimTokenDto, error)
+	DeleteToken(userId int32) error
+	VerifyToken(token string) (bool, error)
+
+	GetServiceProviderConfig() *bean.ServiceProviderConfig
+
+	ListUsers(request *bean.ListRequest) (*bean.ListResponse, error)
+	GetUser(id int32) (*bean.User, error)
+	CreateUser(user *bean.User) (*bean.User, error)
+	ReplaceUser(id int32, user *bean.User) (*bean.User, error)
+	PatchUser(id int32, request *bean.PatchRequest) (*bean.User, error)
+	// DeleteUser deactivates the user and revokes all the roles of the user
+	DeleteUser(id int32) error
+
+	ListGroups(request *bean.ListRequest) (*bean.ListResponse, error)
+	GetGroup(id int32) (*bean.Group, error)
+	CreateGroup(group *bean.Group) (*bean.Group, error)
+	ReplaceGroup(id int32, group *bean.Group) (*bean.Group, error)
+	PatchGroup(id int32, request *bean.PatchRequest) (*bean.Group, error)
+	DeleteGroup(id int32) error
+}
+
+type ScimServiceImpl struct {
+	logger              *zap.SugaredLogger
+	scimRepository      repository.ScimRepository
+	userService         user.UserService
+	roleGroupService    user.RoleGroupService
+	userRepository      userRepository.UserRepository
+	roleGroupRepository userRepository.RoleGroupRepository
+}
+
+func NewScimServiceImpl(logger *zap.SugaredLogger,
+	scimRepository repository.ScimRepository,
+	userService user.UserService,
+	roleGroupService user.RoleGroupService,
+	userRepository userRepository.UserRepository,
+	roleGroupRepository userRepository.RoleGroupRepository) *ScimServiceImpl {
+	return &ScimServiceImpl{
+		logger:              logger,
+		scimRepository:      scimRepository,
+		userService:         userService,
+		roleGroupService:    roleGroupService,
+		userRepository:      userRepository,
+		roleGroupRepository: roleGroupRepository,
+	}
+}
+
+// allowAll is the manager auth of the user and role group updates, the identity provider is authorised by the scim token
+func allowAll(resource, token string, object string) bool {
+	return true
+}
+
+func (impl *ScimServiceImpl) GenerateToken(userId int32) (*bean.ScimTokenDto, error) {
+	randomBytes := make([]byte, 32)
+	if _, err := rand.Read(randomBytes); err != nil {
+		impl.logger.Errorw("error in generating scim token", "err", err)
+		return nil, err
+	}
+	token := bean.TokenPrefix + hex.EncodeToString(randomBytes)
+	tx, err := impl.scimRepository.StartTx()
+	if err != nil {
+		impl.logger.Errorw("error in starting transaction", "err", err)
+		return nil, err
+	}
+	defer impl.scimRepository.RollbackTx(tx)
+	err = impl.scimRepository.DeactivateTokens(userId, tx)
+	if err != nil {
+		impl.logger.Errorw("error in revoking scim tokens", "err", err)
+		return nil, err
+	}
+	tokenModel := &repository.ScimToken{
+		TokenHash: getTokenHash(token),
+		Active:    true,
+		AuditLog:  sql.NewDefaultAuditLog(userId),
+	}
+	err = impl.scimRepository.SaveToken(tokenModel, tx)
+	if err != nil {
+		impl.logger.Errorw("error in saving scim token", "err", err)
+		return nil, err
+	}
+	err = impl.scimRepository.CommitTx(tx)
+	if err != nil {
+		impl.logger.Errorw("error in committing transaction", "err", err)
+		return nil, err
+	}
+	return &bean.ScimTokenDto{
+		Active:    true,
+		Token:     token,
+		CreatedBy: userId,
+		CreatedOn: &tokenModel.CreatedOn,
+	}, nil
+}
+
+func (impl *ScimServiceImpl) GetToken() (*bean.ScimTokenDto, error) {
+	tokenModel, err := impl.scimRepository.FindActiveToken()
+	if util.IsErrNoRows(err) {
+		return &bean.ScimTokenDto{Active: false}, nil
+	} else if err != nil {
+		impl.logger.Errorw("error in getting scim token", "err", err)
+		return nil, err
+	}
+	tokenDto := &bean.ScimTokenDto{
+		Active:    true,
+		CreatedBy: tokenModel.CreatedBy,
+		CreatedOn: &tokenModel.CreatedOn,
+	}
+	if !tokenModel.LastUsedOn.IsZero() {
+		tokenDto.LastUsedOn = &tokenModel.LastUsedOn
+	}
+	return tokenDto, nil
+}
+
+func (impl *ScimServiceImpl) DeleteToken(userId int32) error {
+	tx, err := impl.scimRepository.StartTx()
+	if err != nil {
+		impl.logger.Errorw("error in starting transaction", "err", err)
+		return err
+	}
+	defer impl.scimRepository.RollbackTx(tx)
+	err = impl.scimRepository.DeactivateTokens(userId, tx)
+	if err != nil {
+		impl.logger.Errorw("error in revoking scim tokens", "err", err)
+		return err
+	}
+	return impl.scimRepository.CommitTx(tx)
+}
+
+func (impl *ScimServiceImpl) VerifyToken(token string) (bool, error) {
+	if !strings.HasPrefix(token, bean.TokenPrefix) {
+		return false, nil
+	}
+	tokenModel, err := impl.scimRepository.FindActiveToken()
+	if util.IsErrNoRows(err) {
+		return false, nil
+	} else if err != nil {
+		impl.logger.Errorw("error in getting scim token", "err", err)
+		return false, err
+	}
+	if subtle.ConstantTimeCompare([]byte(getTokenHash(token)), []byte(tokenModel.TokenHash)) != 1 {
+		return false, nil
+	}
+	if now := time.Now(); now.Sub(tokenModel.LastUsedOn) > tokenLastUsedUpdateInterval {
+		if err = impl.scimRepository.UpdateTokenLastUsedOn(tokenModel.Id, now); err != nil {
+			impl.logger.Errorw("error in updating last used time of scim token", "err", err)
+		}
+	}
+	return true, nil
+}
+
+func getTokenHash(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+func (impl *ScimServiceImpl) GetServiceProviderConfig() *bean.ServiceProviderConfig {
+	return &bean.ServiceProviderConfig{
+		Schemas: []string{bean.ServiceProviderConfigSchema},
+		Patch:   bean.Supported{Supported: true},
+		Filter:  bean.FilterSupported{Supported: true, MaxResults: bean.MaxPageSize},
+		AuthenticationSchemes: []*bean.AuthenticationScheme{{
+			Type:        "oauthbearertoken",
+			Name:        "OAuth Bearer Token",
+			Description: "Authentication with the scim token generated in devtron",
+			Primary:     true,
+		}},
+	}
+}
+
+func (impl *ScimServiceImpl) ListUsers(request *bean.ListRequest) (*bean.ListResponse, error) {
+	filter, err := parseFilter(request.Filter)
+	if err != nil {
+		return nil, err
+	}
+	users, err := impl.getUsers()
+	if err != nil {
+		return nil, err
+	}
+	resources := make([]interface{}, 0, len(users))
+	for _, scimUser := range users {
+		if filter != nil && !filter.matches(getUserAttributes(scimUser)) {
+			continue
+		}
+		if isAttributeExcluded(request, "groups") {
+			scimUser.Groups = nil
+		}
+		resources = append(resources, scimUser)
+	}
+	return getListResponse(resources, request), nil
+}
+
+func (impl *ScimServiceImpl) getUsers() ([]*bean.User, error) {
+	users, err := impl.scimRepository.FindAllUsers()
+	if err != nil {
+		impl.logger.Errorw("error in getting users", "err", err)
+		return nil, err
+	}
+	resources, err := impl.getResourcesById(repository.ScimResourceTypeUser)
+	if err != nil {
+		return nil, err
+	}
+	roleGroups, err := impl.getRoleGroupsByCasbinName()
+	if err != nil {
+		return nil, err
+	}
+	scimUsers := make([]*bean.User, 0, len(users))
+	for _, userModel := range users {
+		userModel.EmailId = strings.ToLower(userModel.EmailId)
+		scimUsers = append(scimUsers, adaptUser(userModel, resources[userModel.Id], impl.getRoleGroupsOfUser(userModel, roleGroups)))
+	}
+	return scimUsers, nil
+}
+
+func (impl *ScimServiceImpl) GetUser(id int32) (*bean.User, error) {
+	userModel, err := impl.userRepository.GetByIdIncludeDeleted(id)
+	if util.IsErrNoRows(err) || (err == nil && !isProvisionableUser(userModel)) {
+		return nil, notFoundError(bean.ResourceTypeUser, id)
+	} else if err != nil {
+		impl.logger.Errorw("error in getting user", "id", id, "err", err)
+		return nil, err
+	}
+	resource, err := impl.getResource(repository.ScimResourceTypeUser, id)
+	if err != nil {
+		return nil, err
+	}
+	roleGroups, err := impl.getRoleGroupsByCasbinName()
+	if err != nil {
+		return nil, err
+	}
+	return adaptUser(userModel, resource, impl.getRoleGroupsOfUser(userModel, roleGroups)), nil
+}
+
+func (impl *ScimServiceImpl) CreateUser(scimUser *bean.User) (*bean.User, error) {
+	emailId := strings.ToLower(strings.TrimSpace(scimUser.UserName))
+	if len(emailId) == 0 || strings.Contains(emailId, ",") {
+		return nil, invalidValueError("userName must be the email of the user")
+	}
+	existingUser, err := impl.userRepository.FetchActiveOrDeletedUserByEmail(emailId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in getting user by email", "emailId", emailId, "err", err)
+		return nil, err
+	}
+	if err == nil && existingUser.Active {
+		return nil, bean.NewScimError(http.StatusConflict, bean.ScimTypeUniqueness, fmt.Sprintf("user %s already exists", emailId))
+	}
+	// an existing deactivated user is activated again
+	userInfos, err := impl.userService.CreateUser(&userBean.UserInfo{EmailId: emailId, UserId: userBean.SystemUserId}, "", allowAll)
+	if err != nil {
+		impl.logger.Errorw("error in creating user", "emailId", emailId, "err", err)
+		return nil, err
+	}
+	if len(userInfos) == 0 {
+		return nil, fmt.Errorf("user %s not created", emailId)
+	}
+	id := userInfos[0].Id
+	if scimUser.Active != nil && !*scimUser.Active {
+		if err = impl.deactivateUser(id); err != nil {
+			return nil, err
+		}
+	}
+	if err = impl.saveResource(repository.ScimResourceTypeUser, id, scimUser.ExternalId, scimUser.DisplayName, scimUser.Name); err != nil {
+		return nil, err
+	}
+	return impl.GetUser(id)
+}
+
+func (impl *ScimServiceImpl) ReplaceUser(id int32, scimUser *bean.User) (*bean.User, error) {
+	existingUser, err := impl.GetUser(id)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(strings.TrimSpace(scimUser.UserName), existingUser.UserName) {
+		return nil, bean.NewScimError(http.StatusBadRequest, bean.ScimTypeMutability, "userName is the email of the user and cannot be changed")
+	}
+	return impl.updateUser(id, existingUser, scimUser)
+}
+
+func (impl *ScimServiceImpl) PatchUser(id int32, request *bean.PatchRequest) (*bean.User, error) {
+	existingUser, err := impl.GetUser(id)
+	if err != nil {
+		return nil, err
+	}
+	patchedUser := *existingUser
+	if existingUser.Name != nil {
+		name := *existingUser.Name
+		patchedUser.Name = &name
+	}
+	if err = applyUserPatch(&patchedUser, request.Operations); err != nil {
+		return nil, err
+	}
+	return impl.updateUser(id, existingUser, &patchedUser)
+}
+
+// updateUser activates or deactivates the user and saves the scim attributes of the user
+func (impl *ScimServiceImpl) updateUser(id int32, existingUser *bean.User, scimUser *bean.User) (*bean.User, error) {
+	isActive := *existingUser.Active
+	if scimUser.Active != nil && *scimUser.Active != isActive {
+		var err error
+		if *scimUser.Active {
+			_, err = impl.userService.CreateUser(&userBean.UserInfo{EmailId: existingUser.UserName, UserId: userBean.SystemUserId}, "", allowAll)
+		} else {
+			err = impl.deactivateUser(id)
+		}
+		if err != nil {
+			impl.logger.Errorw("error in updating status of user", "id", id, "active", *scimUser.Active, "err", err)
+			return nil, err
+		}
+	}
+	if err := impl.saveResource(repository.ScimResourceTypeUser, id, scimUser.ExternalId, scimUser.DisplayName, scimUser.Name); err != nil {
+		return nil, err
+	}
+	return impl.GetUser(id)
+}
+
+func (impl *ScimServiceImpl) DeleteUser(id int32) error {
+	existingUser, err := impl.GetUser(id)
+	if err != nil {
+		return err
+	}
+	if !*existingUser.Active {
+		return nil
+	}
+	return impl.deactivateUser(id)
+}
+
+func (impl *ScimServiceImpl) deactivateUser(id int32) error {
+	_, err := impl.userService.DeleteUser(&userBean.UserInfo{Id: id, UserId: userBean.SystemUserId})
+	if err != nil {
+		impl.logger.Errorw("error in deactivating user", "id", id, "err", err)
+	}
+	return err
+}
+
+func (impl *ScimServiceImpl) ListGroups(request *bean.ListRequest) (*bean.ListResponse, error) {
+	filter, err := parseFilter(request.Filter)
+	if err != nil {
+		return nil, err
+	}
+	roleGroups, err := impl.roleGroupRepository.GetAllRoleGroup()
+	if err != nil {
+		impl.logger.Errorw("error in getting role groups", "err", err)
+		return nil, err
+	}
+	resources, err := impl.getResourcesById(repository.ScimResourceTypeGroup)
+	if err != nil {
+		return nil, err
+	}
+	// members are not needed to filter by the name of the group, which is the lookup of the identity providers
+	withMembers := !isAttributeExcluded(request, "members")
+	var usersByEmail map[string]*userRepository.UserModel
+	if withMembers {
+		if usersByEmail, err = impl.getActiveUsersByEmail(); err != nil {
+			return nil, err
+		}
+	}
+	sortRoleGroupsById(roleGroups)
+	groups := make([]interface{}, 0, len(roleGroups))
+	for _, roleGroup := range roleGroups {
+		group := adaptGroup(roleGroup, resources[roleGroup.Id], impl.getMembersOfRoleGroup(roleGroup, usersByEmail))
+		if filter != nil && !filter.matches(getGroupAttributes(group)) {
+			continue
+		}
+		groups = append(groups, group)
+	}
+	return getListResponse(groups, request), nil
+}
+
+func (impl *ScimServiceImpl) GetGroup(id int32) (*bean.Group, error) {
+	roleGroup, err := impl.roleGroupRepository.GetRoleGroupById(id)
+	if util.IsErrNoRows(err) {
+		return nil, notFoundError(bean.ResourceTypeGroup, id)
+	} else if err != nil {
+		impl.logger.Errorw("error in getting role group", "id", id, "err", err)
+		return nil, err
+	}
+	resource, err := impl.getResource(repository.ScimResourceTypeGroup, id)
+	if err != nil {
+		return nil, err
+	}
+	usersByEmail, err := impl.getActiveUsersByEmail()
+	if err != nil {
+		return nil, err
+	}
+	return adaptGroup(roleGroup, resource, impl.getMembersOfRoleGroup(roleGroup, usersByEmail)), nil
+}
+
+// CreateGroup creates a role group without any roles, the roles of the group are managed in devtron
+func (impl *ScimServiceImpl) CreateGroup(group *bean.Group) (*bean.Group, error) {
+	name := strings.TrimSpace(group.DisplayName)
+	if len(name) == 0 || strings.Contains(name, ",") {
+		return nil, invalidValueError("displayName is required and must not contain ','")
+	}
+	exists, err := impl.roleGroupRepository.CheckRoleGroupExistByCasbinName(userHelper.GetCasbinNameFromRoleGroupName(name))
+	if err != nil {
+		impl.logger.Errorw("error in checking role group", "name", name, "err", err)
+		return nil, err
+	} else if exists {
+		return nil, bean.NewScimError(http.StatusConflict, bean.ScimTypeUniqueness, fmt.Sprintf("group %s already exists", name))
+	}
+	roleGroup, err := impl.roleGroupService.CreateRoleGroup(&userBean.RoleGroup{
+		Name:        name,
+		Description: "provisioned by the identity provider",
+		RoleFilters: []userBean.RoleFilter{},
+		UserId:      userBean.SystemUserId,
+	})
+	if err != nil {
+		impl.logger.Errorw("error in creating role group", "name", name, "err", err)
+		return nil, err
+	}
+	if err = impl.saveResource(repository.ScimResourceTypeGroup, roleGroup.Id, group.ExternalId, "", nil); err != nil {
+		return nil, err
+	}
+	createdGroup, err := impl.GetGroup(roleGroup.Id)
+	if err != nil {
+		return nil, err
+	}
+	group.DisplayName = name
+	return impl.updateGroup(roleGroup.Id, createdGroup, group)
+}
+
+func (impl *ScimServiceImpl) ReplaceGroup(id int32, group *bean.Group) (*bean.Group, error) {
+	existingGroup, err := impl.GetGroup(id)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(group.DisplayName) != existingGroup.DisplayName {
+		return nil, bean.NewScimError(http.StatusBadRequest, bean.ScimTypeMutability, "displayName is the name of the role group and cannot be changed")
+	}
+	return impl.updateGroup(id, existingGroup, group)
+}
+
+func (impl *ScimServiceImpl) PatchGroup(id int32, request *bean.PatchRequest) (*bean.Group, error) {
+	existingGroup, err := impl.GetGroup(id)
+	if err != nil {
+		return nil, err
+	}
+	patchedGroup := *existingGroup
+	patchedGroup.Members = append([]bean.MultiValuedAttribute{}, existingGroup.Members...)
+	if err = applyGroupPatch(&patchedGroup, request.Operations); err != nil {
+		return nil, err
+	}
+	return impl.updateGroup(id, existingGroup, &patchedGroup)
+}
+
+// updateGroup adds and removes the role group to/from the users as per the members of the group
+func (impl *ScimServiceImpl) updateGroup(id int32, existingGroup *bean.Group, group *bean.Group) (*bean.Group, error) {
+	addedMemberIds, removedMemberIds, err := getMemberChanges(existingGroup.Members, group.Members)
+	if err != nil {
+		return nil, err
+	}
+	roleGroup := &userBean.RoleGroup{Id: id, Name: existingGroup.DisplayName}
+	for _, memberId := range addedMemberIds {
+		if err = impl.updateRoleGroupOfUser(memberId, roleGroup, true); err != nil {
+			return nil, err
+		}
+	}
+	for _, memberId := range removedMemberIds {
+		if err = impl.updateRoleGroupOfUser(memberId, roleGroup, false); err != nil {
+			return nil, err
+		}
+	}
+	if group.ExternalId != existingGroup.ExternalId {
+		if err = impl.saveResource(repository.ScimResourceTypeGroup, id, group.ExternalId, "", nil); err != nil {
+			return nil, err
+		}
+	}
+	return impl.GetGroup(id)
+}
+
+// updateRoleGroupOfUser adds or removes the role group of the user, keeping the other roles and groups of the user
+func (impl *ScimServiceImpl) updateRoleGroupOfUser(userId int32, roleGroup *userBean.RoleGroup, add bool) error {
+	userInfo, err := impl.userService.GetByIdWithoutGroupClaims(userId)
+	if util.IsErrNoRows(err) {
+		if !add {
+			// roles of deactivated users are already revoked
+			return nil
+		}
+		return invalidValueError(fmt.Sprintf("member %d is not an active user", userId))
+	} else if err != nil {
+		impl.logger.Errorw("error in getting user", "userId", userId, "err", err)
+		return err
+	}
+	userRoleGroups := make([]userBean.UserRoleGroup, 0, len(userInfo.UserRoleGroup)+1)
+	for _, userRoleGroup := range userInfo.UserRoleGroup {
+		if userRoleGroup.RoleGroup == nil || userRoleGroup.RoleGroup.Name == roleGroup.Name {
+			continue
+		}
+		userRoleGroups = append(userRoleGroups, userRoleGroup)
+	}
+	if add {
+		userRoleGroups = append(userRoleGroups, userBean.UserRoleGroup{RoleGroup: roleGroup})
+	}
+	userInfo.UserRoleGroup = userRoleGroups
+	userInfo.UserId = userBean.SystemUserId
+	_, err = impl.userService.UpdateUser(userInfo, "", nil, allowAll)
+	if err != nil {
+		impl.logger.Errorw("error in updating role groups of user", "userId", userId, "roleGroup", roleGroup.Name, "add", add, "err", err)
+	}
+	return err
+}
+
+func (impl *ScimServiceImpl) DeleteGroup(id int32) error {
+	if _, err := impl.GetGroup(id); err != nil {
+		return err
+	}
+	_, err := impl.roleGroupService.DeleteRoleGroup(&userBean.RoleGroup{Id: id, UserId: userBean.SystemUserId})
+	if err != nil {
+		impl.logger.Errorw("error in deleting role group", "id", id, "err", err)
+		return err
+	}
+	err = impl.scimRepository.DeleteResource(repository.ScimResourceTypeGroup, id)
+	if err != nil {
+		impl.logger.Errorw("error in deleting scim resource of group", "id", id, "err", err)
+	}
+	return err
+}
+
+func (impl *ScimServiceImpl) getResource(resourceType string, resourceId int32) (*repository.ScimResource, error) {
+	resource, err := impl.scimRepository.FindResource(resourceType, resourceId)
+	if util.IsErrNoRows(err) {
+		return nil, nil
+	} else if err != nil {
+		impl.logger.Errorw("error in getting scim resource", "resourceType", resourceType, "resourceId", resourceId, "err", err)
+		return nil, err
+	}
+	return resource, nil
+}
+
+func (impl *ScimServiceImpl) getResourcesById(resourceType string) (map[int32]*repository.ScimResource, error) {
+	resources, err := impl.scimRepository.FindResourcesByType(resourceType)
+	if err != nil {
+		impl.logger.Errorw("error in getting scim resources", "resourceType", resourceType, "err", err)
+		return nil, err
+	}
+	resourcesById := make(map[int32]*repository.ScimResource, len(resources))
+	for _, resource := range resources {
+		resourcesById[resource.ResourceId] = resource
+	}
+	return resourcesById, nil
+}
+
+func (impl *ScimServiceImpl) saveResource(resourceType string, resourceId int32, externalId, displayName string, name *bean.Name) error {
+	resource, err := impl.getResource(resourceType, resourceId)
+	if err != nil {
+		return err
+	}
+	if resource == nil {
+		resource = &repository.ScimResource{
+			ResourceType: resourceType,
+			ResourceId:   resourceId,
+			AuditLog:     sql.NewDefaultAuditLog(userBean.SystemUserId),
+		}
+	}
+	resource.ExternalId = externalId
+	resource.DisplayName = displayName
+	resource.GivenName, resource.FamilyName = "", ""
+	if name != nil {
+		resource.GivenName, resource.FamilyName = name.GivenName, name.FamilyName
+	}
+	resource.UpdatedOn = time.Now()
+	if resource.Id == 0 {
+		err = impl.scimRepository.SaveResource(resource)
+	} else {
+		err = impl.scimRepository.UpdateResource(resource)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in saving scim resource", "resourceType", resourceType, "resourceId", resourceId, "err", err)
+	}
+	return err
+}
+
+func (impl *ScimServiceImpl) getRoleGroupsByCasbinName() (map[string]*userRepository.RoleGroup, error) {
+	roleGroups, err := impl.roleGroupRepository.GetAllRoleGroup()
+	if err != nil {
+		impl.logger.Errorw("error in getting role groups", "err", err)
+		return nil, err
+	}
+	roleGroupsByCasbinName := make(map[string]*userRepository.RoleGroup, len(roleGroups))
+	for _, roleGroup := range roleGroups {
+		roleGroupsByCasbinName[strings.ToLower(roleGroup.CasbinName)] = roleGroup
+	}
+	return roleGroupsByCasbinName, nil
+}
+
+// getRoleGroupsOfUser returns the role groups of the user from the group policies of the user in casbin
+func (impl *ScimServiceImpl) getRoleGroupsOfUser(userModel *userRepository.UserModel, roleGroupsByCasbinName map[string]*userRepository.RoleGroup) []*userRepository.RoleGroup {
+	if !userModel.Active {
+		return nil
+	}
+	roles, err := casbin.GetRolesForUser(userModel.EmailId)
+	if err != nil {
+		impl.logger.Warnw("error in getting roles of user", "emailId", userModel.EmailId, "err", err)
+		return nil
+	}
+	roleGroups := make([]*userRepository.RoleGroup, 0)
+	for _, role := range roles {
+		if roleGroup, ok := roleGroupsByCasbinName[role]; ok {
+			roleGroups = append(roleGroups, roleGroup)
+		}
+	}
+	return roleGroups
+}
+
+func (impl *ScimServiceImpl) getActiveUsersByEmail() (map[string]*userRepository.UserModel, error) {
+	users, err := impl.scimRepository.FindAllUsers()
+	if err != nil {
+		impl.logger.Errorw("error in getting users", "err", err)
+		return nil, err
+	}
+	usersByEmail := make(map[string]*userRepository.UserModel, len(users))
+	for _, userModel := range users {
+		if userModel.Active {
+			usersByEmail[strings.ToLower(userModel.EmailId)] = userModel
+		}
+	}
+	return usersByEmail, nil
+}
+
+// getMembersOfRoleGroup returns the users having the group policy of the role group in casbin
+func (impl *ScimServiceImpl) getMembersOfRoleGroup(roleGroup *userRepository.RoleGroup, usersByEmail map[string]*userRepository.UserModel) []*userRepository.UserModel {
+	if usersByEmail == nil {
+		return nil
+	}
+	emailIds, err := casbin.GetUserByRole(roleGroup.CasbinName)
+	if err != nil {
+		impl.logger.Warnw("error in getting members of role group", "roleGroup", roleGroup.Name, "err", err)
+		return nil
+	}
+	members := make([]*userRepository.UserModel, 0, len(emailIds))
+	for _, emailId := range emailIds {
+		if member, ok := usersByEmail[strings.ToLower(emailId)]; ok {
+			members = append(members, member)
+		}
+	}
+	sortUsersById(members)
+	return members
+}
+
+// isProvisionableUser excludes the api token users and the admin and system users
+func isProvisionableUser(userModel *userRepository.UserModel) bool {
+	return userModel.UserType != userBean.USER_TYPE_API_TOKEN && userModel.EmailId != userBean.AdminUser && userModel.EmailId != userBean.SystemUser
+}
+
+func notFoundError(resourceType string, id int32) error {
+	return bean.NewScimError(http.StatusNotFound, "", fmt.Sprintf("%s %s not found", resourceType, strconv.Itoa(int(id))))
+}
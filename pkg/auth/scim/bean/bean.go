@@ -0,0 +1,202 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	UserSchema                  = "urn:ietf:params:scim:schemas:core:2.0:User"
+	GroupSchema                 = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	ListResponseSchema          = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	PatchOpSchema               = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	ErrorSchema                 = "urn:ietf:params:scim:api:messages:2.0:Error"
+	ServiceProviderConfigSchema = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+
+	ContentType = "application/scim+json"
+
+	ResourceTypeUser  = "User"
+	ResourceTypeGroup = "Group"
+
+	DefaultPageSize = 100
+	MaxPageSize     = 500
+
+	TokenPrefix = "scim_"
+	MaskedToken = "**********"
+)
+
+type PatchOp string
+
+const (
+	PatchOpAdd     PatchOp = "add"
+	PatchOpRemove  PatchOp = "remove"
+	PatchOpReplace PatchOp = "replace"
+)
+
+// ScimType is the detail error keyword of rfc7644 section 3.12
+type ScimType string
+
+const (
+	ScimTypeInvalidFilter ScimType = "invalidFilter"
+	ScimTypeUniqueness    ScimType = "uniqueness"
+	ScimTypeMutability    ScimType = "mutability"
+	ScimTypeInvalidSyntax ScimType = "invalidSyntax"
+	ScimTypeInvalidPath   ScimType = "invalidPath"
+	ScimTypeInvalidValue  ScimType = "invalidValue"
+	ScimTypeNoTarget      ScimType = "noTarget"
+)
+
+type Meta struct {
+	ResourceType string     `json:"resourceType"`
+	Created      *time.Time `json:"created,omitempty"`
+	LastModified *time.Time `json:"lastModified,omitempty"`
+	Location     string     `json:"location,omitempty"`
+}
+
+type Name struct {
+	Formatted  string `json:"formatted,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+}
+
+type MultiValuedAttribute struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+	Ref     string `json:"$ref,omitempty"`
+}
+
+// User is mapped onto a devtron user, userName is the email of the user and id is the id of the user
+type User struct {
+	Schemas     []string               `json:"schemas"`
+	Id          string                 `json:"id,omitempty"`
+	ExternalId  string                 `json:"externalId,omitempty"`
+	UserName    string                 `json:"userName"`
+	Name        *Name                  `json:"name,omitempty"`
+	DisplayName string                 `json:"displayName,omitempty"`
+	Active      *bool                  `json:"active,omitempty"`
+	Emails      []MultiValuedAttribute `json:"emails,omitempty"`
+	Groups      []MultiValuedAttribute `json:"groups,omitempty"`
+	Meta        *Meta                  `json:"meta,omitempty"`
+}
+
+// Group is mapped onto a devtron role group, displayName is the name of the role group and id is the id of the role group
+type Group struct {
+	Schemas     []string               `json:"schemas"`
+	Id          string                 `json:"id,omitempty"`
+	ExternalId  string                 `json:"externalId,omitempty"`
+	DisplayName string                 `json:"displayName"`
+	Members     []MultiValuedAttribute `json:"members,omitempty"`
+	Meta        *Meta                  `json:"meta,omitempty"`
+}
+
+type ListRequest struct {
+	Filter             string
+	StartIndex         int
+	Count              int
+	ExcludedAttributes []string
+}
+
+type ListResponse struct {
+	Schemas      []string      `json:"schemas"`
+	TotalResults int           `json:"totalResults"`
+	StartIndex   int           `json:"startIndex"`
+	ItemsPerPage int           `json:"itemsPerPage"`
+	Resources    []interface{} `json:"Resources"`
+}
+
+type PatchRequest struct {
+	Schemas    []string          `json:"schemas"`
+	Operations []*PatchOperation `json:"Operations"`
+}
+
+type PatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+type ErrorResponse struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	ScimType ScimType `json:"scimType,omitempty"`
+	Detail   string   `json:"detail,omitempty"`
+}
+
+type Supported struct {
+	Supported bool `json:"supported"`
+}
+
+type FilterSupported struct {
+	Supported  bool `json:"supported"`
+	MaxResults int  `json:"maxResults"`
+}
+
+type BulkSupported struct {
+	Supported      bool `json:"supported"`
+	MaxOperations  int  `json:"maxOperations"`
+	MaxPayloadSize int  `json:"maxPayloadSize"`
+}
+
+type AuthenticationScheme struct {
+	Type        string `json:"type"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Primary     bool   `json:"primary"`
+}
+
+type ServiceProviderConfig struct {
+	Schemas               []string                `json:"schemas"`
+	Patch                 Supported               `json:"patch"`
+	Bulk                  BulkSupported           `json:"bulk"`
+	Filter                FilterSupported         `json:"filter"`
+	ChangePassword        Supported               `json:"changePassword"`
+	Sort                  Supported               `json:"sort"`
+	Etag                  Supported               `json:"etag"`
+	AuthenticationSchemes []*AuthenticationScheme `json:"authenticationSchemes"`
+}
+
+// ScimTokenDto describes the scim token, Token is only returned once, when the token is generated
+type ScimTokenDto struct {
+	Active     bool       `json:"active"`
+	Token      string     `json:"token,omitempty"`
+	CreatedBy  int32      `json:"createdBy,omitempty"`
+	CreatedOn  *time.Time `json:"createdOn,omitempty"`
+	LastUsedOn *time.Time `json:"lastUsedOn,omitempty"`
+}
+
+// ScimError is written as a scim error response with the status and the scimType of the error
+type ScimError struct {
+	Status   int
+	ScimType ScimType
+	Detail   string
+}
+
+func (e *ScimError) Error() string {
+	return e.Detail
+}
+
+func NewScimError(status int, scimType ScimType, detail string) *ScimError {
+	return &ScimError{
+		Status:   status,
+		ScimType: scimType,
+		Detail:   detail,
+	}
+}
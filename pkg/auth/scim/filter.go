@@ -0,0 +1,280 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package scim
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/devtron-labs/devtron/pkg/auth/scim/bean"
+	"net/http"
+	"strings"
+)
+
+// filterExpression is a parsed scim filter (rfc7644 section 3.4.2.2), it is evaluated against the attributes of a
+// resource flattened by attribute path (lower cased), e.g. "username", "emails.value", "members.value"
+type filterExpression interface {
+	matches(attributes map[string][]string) bool
+}
+
+type logicalExpression struct {
+	operator string
+	left     filterExpression
+	right    filterExpression
+}
+
+func (expression *logicalExpression) matches(attributes map[string][]string) bool {
+	if expression.operator == "and" {
+		return expression.left.matches(attributes) && expression.right.matches(attributes)
+	}
+	return expression.left.matches(attributes) || expression.right.matches(attributes)
+}
+
+type notExpression struct {
+	expression filterExpression
+}
+
+func (expression *notExpression) matches(attributes map[string][]string) bool {
+	return !expression.expression.matches(attributes)
+}
+
+type attributeExpression struct {
+	path     string
+	operator string
+	value    string
+}
+
+// matches compares the values case-insensitively, a multi valued attribute matches if any of its values matches
+func (expression *attributeExpression) matches(attributes map[string][]string) bool {
+	values := attributes[expression.path]
+	if expression.operator == "pr" {
+		for _, value := range values {
+			if len(value) != 0 {
+				return true
+			}
+		}
+		return false
+	}
+	for _, value := range values {
+		value = strings.ToLower(value)
+		matched := false
+		switch expression.operator {
+		case "eq":
+			matched = value == expression.value
+		case "ne":
+			matched = value != expression.value
+		case "co":
+			matched = strings.Contains(value, expression.value)
+		case "sw":
+			matched = strings.HasPrefix(value, expression.value)
+		case "ew":
+			matched = strings.HasSuffix(value, expression.value)
+		case "gt":
+			matched = value > expression.value
+		case "ge":
+			matched = value >= expression.value
+		case "lt":
+			matched = value < expression.value
+		case "le":
+			matched = value <= expression.value
+		}
+		if matched {
+			return true
+		}
+	}
+	// a missing attribute is not equal to any value
+	return len(values) == 0 && expression.operator == "ne"
+}
+
+var filterOperators = map[string]bool{
+	"eq": true, "ne": true, "co": true, "sw": true, "ew": true, "gt": true, "ge": true, "lt": true, "le": true, "pr": true,
+}
+
+type filterParser struct {
+	tokens   []string
+	position int
+}
+
+// parseFilter parses a scim filter, an empty filter is parsed to a nil expression which matches every resource
+func parseFilter(filter string) (filterExpression, error) {
+	if len(strings.TrimSpace(filter)) == 0 {
+		return nil, nil
+	}
+	tokens, err := tokenizeFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	parser := &filterParser{tokens: tokens}
+	expression, err := parser.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if parser.position != len(parser.tokens) {
+		return nil, invalidFilterError(fmt.Sprintf("unexpected token %q", parser.tokens[parser.position]))
+	}
+	return expression, nil
+}
+
+func tokenizeFilter(filter string) ([]string, error) {
+	tokens := make([]string, 0)
+	for i := 0; i < len(filter); {
+		switch char := filter[i]; {
+		case char == ' ':
+			i++
+		case char == '(' || char == ')':
+			tokens = append(tokens, string(char))
+			i++
+		case char == '"':
+			end := i + 1
+			for ; end < len(filter) && filter[end] != '"'; end++ {
+				if filter[end] == '\\' {
+					end++
+				}
+			}
+			if end >= len(filter) {
+				return nil, invalidFilterError("unterminated string in filter")
+			}
+			tokens = append(tokens, filter[i:end+1])
+			i = end + 1
+		default:
+			end := i
+			for ; end < len(filter) && filter[end] != ' ' && filter[end] != '(' && filter[end] != ')'; end++ {
+			}
+			tokens = append(tokens, filter[i:end])
+			i = end
+		}
+	}
+	return tokens, nil
+}
+
+func (parser *filterParser) next() string {
+	if parser.position >= len(parser.tokens) {
+		return ""
+	}
+	token := parser.tokens[parser.position]
+	parser.position++
+	return token
+}
+
+func (parser *filterParser) peekKeyword() string {
+	if parser.position >= len(parser.tokens) {
+		return ""
+	}
+	return strings.ToLower(parser.tokens[parser.position])
+}
+
+func (parser *filterParser) parseOr() (filterExpression, error) {
+	left, err := parser.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for parser.peekKeyword() == "or" {
+		parser.next()
+		right, err := parser.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalExpression{operator: "or", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (parser *filterParser) parseAnd() (filterExpression, error) {
+	left, err := parser.parseFactor()
+	if err != nil {
+		return nil, err
+	}
+	for parser.peekKeyword() == "and" {
+		parser.next()
+		right, err := parser.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalExpression{operator: "and", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (parser *filterParser) parseFactor() (filterExpression, error) {
+	token := parser.next()
+	switch strings.ToLower(token) {
+	case "":
+		return nil, invalidFilterError("unexpected end of filter")
+	case "not":
+		if parser.peekKeyword() != "(" {
+			return nil, invalidFilterError("expected ( after not")
+		}
+		expression, err := parser.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		return &notExpression{expression: expression}, nil
+	case "(":
+		expression, err := parser.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if parser.next() != ")" {
+			return nil, invalidFilterError("expected )")
+		}
+		return expression, nil
+	}
+	path := normaliseAttributePath(token)
+	operator := strings.ToLower(parser.next())
+	if !filterOperators[operator] {
+		return nil, invalidFilterError(fmt.Sprintf("unsupported operator %q", operator))
+	}
+	if operator == "pr" {
+		return &attributeExpression{path: path, operator: operator}, nil
+	}
+	value, err := parseFilterValue(parser.next())
+	if err != nil {
+		return nil, err
+	}
+	return &attributeExpression{path: path, operator: operator, value: value}, nil
+}
+
+func parseFilterValue(token string) (string, error) {
+	if len(token) == 0 {
+		return "", invalidFilterError("expected a value in filter")
+	}
+	if strings.HasPrefix(token, "\"") {
+		var value string
+		if err := json.Unmarshal([]byte(token), &value); err != nil {
+			return "", invalidFilterError(fmt.Sprintf("invalid string %s in filter", token))
+		}
+		return strings.ToLower(value), nil
+	}
+	// true, false, null and numbers
+	return strings.ToLower(token), nil
+}
+
+// normaliseAttributePath lower cases the path and strips the core schema urn of the path,
+// e.g. "urn:ietf:params:scim:schemas:core:2.0:User:userName" is normalised to "username"
+func normaliseAttributePath(path string) string {
+	path = strings.ToLower(strings.TrimSpace(path))
+	for _, schema := range []string{bean.UserSchema, bean.GroupSchema} {
+		prefix := strings.ToLower(schema) + ":"
+		if strings.HasPrefix(path, prefix) {
+			return strings.TrimPrefix(path, prefix)
+		}
+	}
+	return path
+}
+
+func invalidFilterError(detail string) error {
+	return bean.NewScimError(http.StatusBadRequest, bean.ScimTypeInvalidFilter, detail)
+}
@@ -0,0 +1,499 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package scim
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/devtron-labs/devtron/pkg/auth/scim/bean"
+	"github.com/devtron-labs/devtron/pkg/auth/scim/repository"
+	userRepository "github.com/devtron-labs/devtron/pkg/auth/user/repository"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func adaptUser(model *userRepository.UserModel, resource *repository.ScimResource, roleGroups []*userRepository.RoleGroup) *bean.User {
+	active := model.Active
+	user := &bean.User{
+		Schemas:  []string{bean.UserSchema},
+		Id:       strconv.Itoa(int(model.Id)),
+		UserName: model.EmailId,
+		Active:   &active,
+		Emails:   []bean.MultiValuedAttribute{{Value: model.EmailId, Type: "work", Primary: true}},
+		Meta:     getMeta(bean.ResourceTypeUser, model.CreatedOn, model.UpdatedOn, resource),
+	}
+	if resource != nil {
+		user.ExternalId = resource.ExternalId
+		user.DisplayName = resource.DisplayName
+		if len(resource.GivenName) != 0 || len(resource.FamilyName) != 0 {
+			user.Name = &bean.Name{
+				GivenName:  resource.GivenName,
+				FamilyName: resource.FamilyName,
+				Formatted:  strings.TrimSpace(resource.GivenName + " " + resource.FamilyName),
+			}
+		}
+	}
+	for _, roleGroup := range roleGroups {
+		user.Groups = append(user.Groups, bean.MultiValuedAttribute{Value: strconv.Itoa(int(roleGroup.Id)), Display: roleGroup.Name})
+	}
+	return user
+}
+
+func adaptGroup(roleGroup *userRepository.RoleGroup, resource *repository.ScimResource, members []*userRepository.UserModel) *bean.Group {
+	group := &bean.Group{
+		Schemas:     []string{bean.GroupSchema},
+		Id:          strconv.Itoa(int(roleGroup.Id)),
+		DisplayName: roleGroup.Name,
+		Meta:        getMeta(bean.ResourceTypeGroup, roleGroup.CreatedOn, roleGroup.UpdatedOn, resource),
+	}
+	if resource != nil {
+		group.ExternalId = resource.ExternalId
+	}
+	for _, member := range members {
+		group.Members = append(group.Members, bean.MultiValuedAttribute{Value: strconv.Itoa(int(member.Id)), Display: member.EmailId})
+	}
+	return group
+}
+
+func getMeta(resourceType string, createdOn, updatedOn time.Time, resource *repository.ScimResource) *bean.Meta {
+	if resource != nil && resource.UpdatedOn.After(updatedOn) {
+		updatedOn = resource.UpdatedOn
+	}
+	return &bean.Meta{
+		ResourceType: resourceType,
+		Created:      &createdOn,
+		LastModified: &updatedOn,
+	}
+}
+
+// getUserAttributes flattens the filterable attributes of a user
+func getUserAttributes(user *bean.User) map[string][]string {
+	attributes := map[string][]string{
+		"id":          {user.Id},
+		"username":    {user.UserName},
+		"externalid":  {user.ExternalId},
+		"displayname": {user.DisplayName},
+	}
+	if user.Active != nil {
+		attributes["active"] = []string{strconv.FormatBool(*user.Active)}
+	}
+	if user.Name != nil {
+		attributes["name.givenname"] = []string{user.Name.GivenName}
+		attributes["name.familyname"] = []string{user.Name.FamilyName}
+		attributes["name.formatted"] = []string{user.Name.Formatted}
+	}
+	for _, email := range user.Emails {
+		attributes["emails"] = append(attributes["emails"], email.Value)
+		attributes["emails.value"] = append(attributes["emails.value"], email.Value)
+	}
+	for _, group := range user.Groups {
+		attributes["groups"] = append(attributes["groups"], group.Value)
+		attributes["groups.value"] = append(attributes["groups.value"], group.Value)
+		attributes["groups.display"] = append(attributes["groups.display"], group.Display)
+	}
+	return attributes
+}
+
+// getGroupAttributes flattens the filterable attributes of a group
+func getGroupAttributes(group *bean.Group) map[string][]string {
+	attributes := map[string][]string{
+		"id":          {group.Id},
+		"displayname": {group.DisplayName},
+		"externalid":  {group.ExternalId},
+	}
+	for _, member := range group.Members {
+		attributes["members"] = append(attributes["members"], member.Value)
+		attributes["members.value"] = append(attributes["members.value"], member.Value)
+		attributes["members.display"] = append(attributes["members.display"], member.Display)
+	}
+	return attributes
+}
+
+// getPage returns the bounds of the page of the list request, startIndex is 1-based as per rfc7644 section 3.4.2.4
+func getPage(totalResults int, request *bean.ListRequest) (start int, end int) {
+	start = request.StartIndex - 1
+	if start < 0 {
+		start = 0
+	}
+	if start > totalResults {
+		start = totalResults
+	}
+	count := request.Count
+	if count < 0 {
+		count = 0
+	} else if count > bean.MaxPageSize {
+		count = bean.MaxPageSize
+	}
+	end = start + count
+	if end > totalResults {
+		end = totalResults
+	}
+	return start, end
+}
+
+func getListResponse(resources []interface{}, request *bean.ListRequest) *bean.ListResponse {
+	start, end := getPage(len(resources), request)
+	return &bean.ListResponse{
+		Schemas:      []string{bean.ListResponseSchema},
+		TotalResults: len(resources),
+		StartIndex:   start + 1,
+		ItemsPerPage: end - start,
+		Resources:    resources[start:end],
+	}
+}
+
+func isAttributeExcluded(request *bean.ListRequest, attribute string) bool {
+	for _, excludedAttribute := range request.ExcludedAttributes {
+		if normaliseAttributePath(excludedAttribute) == attribute {
+			return true
+		}
+	}
+	return false
+}
+
+func getPatchOp(operation *bean.PatchOperation) (bean.PatchOp, error) {
+	op := bean.PatchOp(strings.ToLower(operation.Op))
+	switch op {
+	case bean.PatchOpAdd, bean.PatchOpRemove, bean.PatchOpReplace:
+		return op, nil
+	}
+	return op, bean.NewScimError(http.StatusBadRequest, bean.ScimTypeInvalidSyntax, fmt.Sprintf("unsupported patch operation %q", operation.Op))
+}
+
+// getPathlessValues returns the attributes of the value of an operation without path, e.g. {"active": false}
+func getPathlessValues(op bean.PatchOp, value json.RawMessage) (map[string]json.RawMessage, error) {
+	if op == bean.PatchOpRemove {
+		return nil, bean.NewScimError(http.StatusBadRequest, bean.ScimTypeNoTarget, "path is required for remove operation")
+	}
+	values := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(value, &values); err != nil {
+		return nil, invalidValueError("value of an operation without path must be an object")
+	}
+	return values, nil
+}
+
+// applyUserPatch applies the patch operations on the user, attributes which are not mapped onto devtron users
+// (e.g. title or the enterprise extension) are ignored so that the identity provider can keep provisioning
+func applyUserPatch(user *bean.User, operations []*bean.PatchOperation) error {
+	for _, operation := range operations {
+		op, err := getPatchOp(operation)
+		if err != nil {
+			return err
+		}
+		if len(operation.Path) != 0 {
+			if err = applyUserAttribute(user, op, normaliseAttributePath(operation.Path), operation.Value); err != nil {
+				return err
+			}
+			continue
+		}
+		values, err := getPathlessValues(op, operation.Value)
+		if err != nil {
+			return err
+		}
+		for path, value := range values {
+			if err = applyUserAttribute(user, op, normaliseAttributePath(path), value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func applyUserAttribute(user *bean.User, op bean.PatchOp, path string, value json.RawMessage) error {
+	switch path {
+	case "active":
+		if op == bean.PatchOpRemove {
+			return nil
+		}
+		active, err := getBoolValue(value)
+		if err != nil {
+			return err
+		}
+		user.Active = &active
+	case "username":
+		userName, err := getStringValue(op, value)
+		if err != nil {
+			return err
+		}
+		if !strings.EqualFold(userName, user.UserName) {
+			return bean.NewScimError(http.StatusBadRequest, bean.ScimTypeMutability, "userName is the email of the user and cannot be changed")
+		}
+	case "externalid":
+		externalId, err := getStringValue(op, value)
+		if err != nil {
+			return err
+		}
+		user.ExternalId = externalId
+	case "displayname":
+		displayName, err := getStringValue(op, value)
+		if err != nil {
+			return err
+		}
+		user.DisplayName = displayName
+	case "name":
+		if op == bean.PatchOpRemove {
+			user.Name = nil
+			return nil
+		}
+		name := &bean.Name{}
+		if err := json.Unmarshal(value, name); err != nil {
+			return invalidValueError("name must be an object")
+		}
+		if user.Name == nil || op == bean.PatchOpReplace {
+			user.Name = &bean.Name{}
+		}
+		if len(name.GivenName) != 0 {
+			user.Name.GivenName = name.GivenName
+		}
+		if len(name.FamilyName) != 0 {
+			user.Name.FamilyName = name.FamilyName
+		}
+	case "name.givenname", "name.familyname":
+		namePart, err := getStringValue(op, value)
+		if err != nil {
+			return err
+		}
+		if user.Name == nil {
+			user.Name = &bean.Name{}
+		}
+		if path == "name.givenname" {
+			user.Name.GivenName = namePart
+		} else {
+			user.Name.FamilyName = namePart
+		}
+	}
+	return nil
+}
+
+// applyGroupPatch applies the patch operations on the group, members can be removed by a value filter in the path,
+// e.g. members[value eq "2"]
+func applyGroupPatch(group *bean.Group, operations []*bean.PatchOperation) error {
+	for _, operation := range operations {
+		op, err := getPatchOp(operation)
+		if err != nil {
+			return err
+		}
+		if len(operation.Path) != 0 {
+			path, valueFilter, err := splitValuePath(operation.Path)
+			if err != nil {
+				return err
+			}
+			if err = applyGroupAttribute(group, op, path, valueFilter, operation.Value); err != nil {
+				return err
+			}
+			continue
+		}
+		values, err := getPathlessValues(op, operation.Value)
+		if err != nil {
+			return err
+		}
+		for path, value := range values {
+			if err = applyGroupAttribute(group, op, normaliseAttributePath(path), nil, value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func applyGroupAttribute(group *bean.Group, op bean.PatchOp, path string, valueFilter filterExpression, value json.RawMessage) error {
+	switch path {
+	case "members":
+		members, err := getMembersValue(value)
+		if err != nil {
+			return err
+		}
+		switch op {
+		case bean.PatchOpAdd:
+			group.Members = mergeMembers(group.Members, members)
+		case bean.PatchOpReplace:
+			if valueFilter != nil {
+				return bean.NewScimError(http.StatusBadRequest, bean.ScimTypeInvalidPath, "replace of filtered members is not supported")
+			}
+			group.Members = mergeMembers(nil, members)
+		case bean.PatchOpRemove:
+			group.Members = removeMembers(group.Members, members, valueFilter)
+		}
+	case "displayname":
+		displayName, err := getStringValue(op, value)
+		if err != nil {
+			return err
+		}
+		if displayName != group.DisplayName {
+			return bean.NewScimError(http.StatusBadRequest, bean.ScimTypeMutability, "displayName is the name of the role group and cannot be changed")
+		}
+	case "externalid":
+		externalId, err := getStringValue(op, value)
+		if err != nil {
+			return err
+		}
+		group.ExternalId = externalId
+	default:
+		return bean.NewScimError(http.StatusBadRequest, bean.ScimTypeInvalidPath, fmt.Sprintf("unsupported path %q", path))
+	}
+	return nil
+}
+
+// splitValuePath splits a path with a value filter, e.g. members[value eq "2"] is split to members and the filter
+func splitValuePath(path string) (string, filterExpression, error) {
+	open := strings.Index(path, "[")
+	if open < 0 {
+		return normaliseAttributePath(path), nil, nil
+	}
+	closing := strings.LastIndex(path, "]")
+	if closing < open {
+		return "", nil, bean.NewScimError(http.StatusBadRequest, bean.ScimTypeInvalidPath, fmt.Sprintf("invalid path %q", path))
+	}
+	valueFilter, err := parseFilter(path[open+1 : closing])
+	if err != nil {
+		return "", nil, err
+	}
+	return normaliseAttributePath(path[:open]), valueFilter, nil
+}
+
+// getMembersValue accepts a list of members as well as a single member, as sent by some identity providers
+func getMembersValue(value json.RawMessage) ([]bean.MultiValuedAttribute, error) {
+	if len(value) == 0 || string(value) == "null" {
+		return nil, nil
+	}
+	var members []bean.MultiValuedAttribute
+	if err := json.Unmarshal(value, &members); err == nil {
+		return members, nil
+	}
+	member := bean.MultiValuedAttribute{}
+	if err := json.Unmarshal(value, &member); err != nil {
+		return nil, invalidValueError("members must be a list of objects with a value")
+	}
+	return []bean.MultiValuedAttribute{member}, nil
+}
+
+func mergeMembers(members []bean.MultiValuedAttribute, addedMembers []bean.MultiValuedAttribute) []bean.MultiValuedAttribute {
+	exists := make(map[string]bool, len(members))
+	merged := make([]bean.MultiValuedAttribute, 0, len(members)+len(addedMembers))
+	for _, memberList := range [][]bean.MultiValuedAttribute{members, addedMembers} {
+		for _, member := range memberList {
+			if exists[member.Value] {
+				continue
+			}
+			exists[member.Value] = true
+			merged = append(merged, member)
+		}
+	}
+	return merged
+}
+
+// removeMembers removes the members matching the value filter or the given members, all the members are removed
+// if neither is given
+func removeMembers(members []bean.MultiValuedAttribute, removedMembers []bean.MultiValuedAttribute, valueFilter filterExpression) []bean.MultiValuedAttribute {
+	if valueFilter == nil && len(removedMembers) == 0 {
+		return nil
+	}
+	removed := make(map[string]bool, len(removedMembers))
+	for _, member := range removedMembers {
+		removed[member.Value] = true
+	}
+	remaining := make([]bean.MultiValuedAttribute, 0, len(members))
+	for _, member := range members {
+		if removed[member.Value] {
+			continue
+		}
+		if valueFilter != nil && valueFilter.matches(map[string][]string{"value": {member.Value}, "display": {member.Display}}) {
+			continue
+		}
+		remaining = append(remaining, member)
+	}
+	return remaining
+}
+
+// getBoolValue accepts booleans as well as strings, some identity providers send "True"/"False"
+func getBoolValue(value json.RawMessage) (bool, error) {
+	var boolValue bool
+	if err := json.Unmarshal(value, &boolValue); err == nil {
+		return boolValue, nil
+	}
+	var stringValue string
+	if err := json.Unmarshal(value, &stringValue); err == nil {
+		if boolValue, err = strconv.ParseBool(stringValue); err == nil {
+			return boolValue, nil
+		}
+	}
+	return false, invalidValueError(fmt.Sprintf("invalid boolean %s", string(value)))
+}
+
+func getStringValue(op bean.PatchOp, value json.RawMessage) (string, error) {
+	if op == bean.PatchOpRemove {
+		return "", nil
+	}
+	var stringValue string
+	if err := json.Unmarshal(value, &stringValue); err != nil {
+		return "", invalidValueError(fmt.Sprintf("invalid string %s", string(value)))
+	}
+	return stringValue, nil
+}
+
+func invalidValueError(detail string) error {
+	return bean.NewScimError(http.StatusBadRequest, bean.ScimTypeInvalidValue, detail)
+}
+
+// getMemberChanges returns the ids of the users added to and removed from the members of a group
+func getMemberChanges(existingMembers []bean.MultiValuedAttribute, members []bean.MultiValuedAttribute) (addedMemberIds []int32, removedMemberIds []int32, err error) {
+	existing := make(map[string]bool, len(existingMembers))
+	for _, member := range existingMembers {
+		existing[member.Value] = true
+	}
+	desired := make(map[string]bool, len(members))
+	for _, member := range members {
+		if desired[member.Value] {
+			continue
+		}
+		desired[member.Value] = true
+		if existing[member.Value] {
+			continue
+		}
+		memberId, err := strconv.Atoi(member.Value)
+		if err != nil {
+			return nil, nil, invalidValueError(fmt.Sprintf("invalid member %q, value must be the id of a user", member.Value))
+		}
+		addedMemberIds = append(addedMemberIds, int32(memberId))
+	}
+	for _, member := range existingMembers {
+		if desired[member.Value] {
+			continue
+		}
+		memberId, err := strconv.Atoi(member.Value)
+		if err != nil {
+			continue
+		}
+		removedMemberIds = append(removedMemberIds, int32(memberId))
+	}
+	return addedMemberIds, removedMemberIds, nil
+}
+
+func sortUsersById(users []*userRepository.UserModel) {
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].Id < users[j].Id
+	})
+}
+
+func sortRoleGroupsById(roleGroups []*userRepository.RoleGroup) {
+	sort.Slice(roleGroups, func(i, j int) bool {
+		return roleGroups[i].Id < roleGroups[j].Id
+	})
+}
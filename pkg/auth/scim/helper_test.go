@@ -0,0 +1,196 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package scim
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/devtron-labs/devtron/pkg/auth/scim/bean"
+	"reflect"
+	"testing"
+)
+
+func TestParseFilter(t *testing.T) {
+	active := true
+	user := &bean.User{
+		Id:         "12",
+		UserName:   "jane.doe@example.com",
+		ExternalId: "00u1abcd",
+		Active:     &active,
+		Name:       &bean.Name{GivenName: "Jane", FamilyName: "Doe"},
+		Emails:     []bean.MultiValuedAttribute{{Value: "jane.doe@example.com"}},
+	}
+	attributes := getUserAttributes(user)
+	tests := []struct {
+		filter  string
+		want    bool
+		wantErr bool
+	}{
+		{filter: `userName eq "Jane.Doe@example.com"`, want: true},
+		{filter: `urn:ietf:params:scim:schemas:core:2.0:User:userName eq "jane.doe@example.com"`, want: true},
+		{filter: `userName eq "john@example.com"`, want: false},
+		{filter: `emails.value co "@example.com" and active eq true`, want: true},
+		{filter: `externalId eq "other" or name.givenName sw "ja"`, want: true},
+		{filter: `not (name.familyName eq "Doe")`, want: false},
+		{filter: `displayName pr`, want: false},
+		{filter: `displayName ne "jane"`, want: true},
+		{filter: `(userName ew ".org" or id eq "12") and active eq true`, want: true},
+		{filter: `userName eq`, wantErr: true},
+		{filter: `userName regex "jane"`, wantErr: true},
+		{filter: `userName eq "jane`, wantErr: true},
+		{filter: `(userName eq "jane"`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			expression, err := parseFilter(tt.filter)
+			if tt.wantErr {
+				scimErr := &bean.ScimError{}
+				if !errors.As(err, &scimErr) || scimErr.ScimType != bean.ScimTypeInvalidFilter {
+					t.Fatalf("parseFilter() error = %v, want invalidFilter error", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseFilter() unexpected error = %v", err)
+			}
+			if got := expression.matches(attributes); got != tt.want {
+				t.Errorf("matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyUserPatch(t *testing.T) {
+	active := true
+	user := &bean.User{UserName: "jane.doe@example.com", Active: &active}
+	operations := []*bean.PatchOperation{
+		{Op: "Replace", Path: "active", Value: json.RawMessage(`"False"`)},
+		{Op: "replace", Value: json.RawMessage(`{"externalId": "00u1abcd", "name.givenName": "Jane", "title": "Engineer"}`)},
+		{Op: "add", Path: "name.familyName", Value: json.RawMessage(`"Doe"`)},
+		{Op: "replace", Path: "userName", Value: json.RawMessage(`"Jane.Doe@example.com"`)},
+	}
+	if err := applyUserPatch(user, operations); err != nil {
+		t.Fatalf("applyUserPatch() unexpected error = %v", err)
+	}
+	if *user.Active || user.ExternalId != "00u1abcd" || user.Name == nil || user.Name.GivenName != "Jane" || user.Name.FamilyName != "Doe" {
+		t.Errorf("applyUserPatch() user = %+v, name = %+v", user, user.Name)
+	}
+	err := applyUserPatch(user, []*bean.PatchOperation{{Op: "replace", Path: "userName", Value: json.RawMessage(`"john@example.com"`)}})
+	scimErr := &bean.ScimError{}
+	if !errors.As(err, &scimErr) || scimErr.ScimType != bean.ScimTypeMutability {
+		t.Errorf("applyUserPatch() error = %v, want mutability error", err)
+	}
+}
+
+func TestApplyGroupPatch(t *testing.T) {
+	members := func(values ...string) []bean.MultiValuedAttribute {
+		attributes := make([]bean.MultiValuedAttribute, 0, len(values))
+		for _, value := range values {
+			attributes = append(attributes, bean.MultiValuedAttribute{Value: value})
+		}
+		return attributes
+	}
+	tests := []struct {
+		name       string
+		operations []*bean.PatchOperation
+		want       []bean.MultiValuedAttribute
+		wantErr    bool
+	}{
+		{
+			name:       "add members",
+			operations: []*bean.PatchOperation{{Op: "add", Path: "members", Value: json.RawMessage(`[{"value": "2"}, {"value": "3"}]`)}},
+			want:       members("1", "2", "3"),
+		},
+		{
+			name:       "remove member by value filter",
+			operations: []*bean.PatchOperation{{Op: "remove", Path: `members[value eq "2"]`}},
+			want:       members("1"),
+		},
+		{
+			name:       "remove members by value",
+			operations: []*bean.PatchOperation{{Op: "Remove", Path: "members", Value: json.RawMessage(`[{"value": "1"}]`)}},
+			want:       members("2"),
+		},
+		{
+			name:       "remove all members",
+			operations: []*bean.PatchOperation{{Op: "remove", Path: "members"}},
+			want:       nil,
+		},
+		{
+			name:       "replace members without path",
+			operations: []*bean.PatchOperation{{Op: "replace", Value: json.RawMessage(`{"members": [{"value": "4"}, {"value": "4"}]}`)}},
+			want:       members("4"),
+		},
+		{
+			name:       "rename group",
+			operations: []*bean.PatchOperation{{Op: "replace", Path: "displayName", Value: json.RawMessage(`"platform"`)}},
+			wantErr:    true,
+		},
+		{
+			name:       "unsupported operation",
+			operations: []*bean.PatchOperation{{Op: "move", Path: "members"}},
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			group := &bean.Group{DisplayName: "developers", Members: members("1", "2")}
+			err := applyGroupPatch(group, tt.operations)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("applyGroupPatch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(group.Members, tt.want) {
+				t.Errorf("applyGroupPatch() members = %v, want %v", group.Members, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetMemberChanges(t *testing.T) {
+	existing := []bean.MultiValuedAttribute{{Value: "1"}, {Value: "2"}}
+	added, removed, err := getMemberChanges(existing, []bean.MultiValuedAttribute{{Value: "2"}, {Value: "3"}})
+	if err != nil || !reflect.DeepEqual(added, []int32{3}) || !reflect.DeepEqual(removed, []int32{1}) {
+		t.Errorf("getMemberChanges() = %v, %v, %v", added, removed, err)
+	}
+	if _, _, err = getMemberChanges(existing, []bean.MultiValuedAttribute{{Value: "jane"}}); err == nil {
+		t.Errorf("getMemberChanges() expected error for invalid member")
+	}
+}
+
+func TestGetListResponse(t *testing.T) {
+	resources := []interface{}{"a", "b", "c", "d", "e"}
+	tests := []struct {
+		name      string
+		request   *bean.ListRequest
+		wantStart int
+		want      []interface{}
+	}{
+		{name: "first page", request: &bean.ListRequest{StartIndex: 1, Count: 2}, wantStart: 1, want: []interface{}{"a", "b"}},
+		{name: "last page", request: &bean.ListRequest{StartIndex: 4, Count: 10}, wantStart: 4, want: []interface{}{"d", "e"}},
+		{name: "start index below 1", request: &bean.ListRequest{StartIndex: 0, Count: 1}, wantStart: 1, want: []interface{}{"a"}},
+		{name: "count 0", request: &bean.ListRequest{StartIndex: 1, Count: 0}, wantStart: 1, want: []interface{}{}},
+		{name: "beyond total", request: &bean.ListRequest{StartIndex: 9, Count: 2}, wantStart: 6, want: []interface{}{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := getListResponse(resources, tt.request)
+			if response.TotalResults != len(resources) || response.StartIndex != tt.wantStart || !reflect.DeepEqual(response.Resources, tt.want) || response.ItemsPerPage != len(tt.want) {
+				t.Errorf("getListResponse() = %+v", response)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	userBean "github.com/devtron-labs/devtron/pkg/auth/user/bean"
+	userRepository "github.com/devtron-labs/devtron/pkg/auth/user/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+const (
+	ScimResourceTypeUser  = "User"
+	ScimResourceTypeGroup = "Group"
+)
+
+// ScimToken is the bearer token of the identity provider, only the sha256 hash of the token is persisted
+type ScimToken struct {
+	tableName  struct{}  `sql:"scim_token" pg:",discard_unknown_columns"`
+	Id         int       `sql:"id,pk"`
+	TokenHash  string    `sql:"token_hash,notnull"`
+	Active     bool      `sql:"active,notnull"`
+	LastUsedOn time.Time `sql:"last_used_on"`
+	sql.AuditLog
+}
+
+// ScimResource holds the scim attributes of a user or a role group which are not stored by devtron
+type ScimResource struct {
+	tableName    struct{} `sql:"scim_resource" pg:",discard_unknown_columns"`
+	Id           int      `sql:"id,pk"`
+	ResourceType string   `sql:"resource_type,notnull"`
+	ResourceId   int32    `sql:"resource_id,notnull"`
+	ExternalId   string   `sql:"external_id"`
+	DisplayName  string   `sql:"display_name"`
+	GivenName    string   `sql:"given_name"`
+	FamilyName   string   `sql:"family_name"`
+	sql.AuditLog
+}
+
+type ScimRepository interface {
+	//transaction util funcs
+	sql.TransactionWrapper
+	FindActiveToken() (*ScimToken, error)
+	SaveToken(token *ScimToken, tx *pg.Tx) error
+	DeactivateTokens(userId int32, tx *pg.Tx) error
+	UpdateTokenLastUsedOn(id int, lastUsedOn time.Time) error
+
+	FindResource(resourceType string, resourceId int32) (*ScimResource, error)
+	FindResourcesByType(resourceType string) ([]*ScimResource, error)
+	SaveResource(resource *ScimResource) error
+	UpdateResource(resource *ScimResource) error
+	DeleteResource(resourceType string, resourceId int32) error
+
+	// FindAllUsers returns the active and the deactivated users, excluding api token users
+	FindAllUsers() ([]*userRepository.UserModel, error)
+}
+
+type ScimRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+	*sql.TransactionUtilImpl
+}
+
+func NewScimRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger, transactionUtilImpl *sql.TransactionUtilImpl) *ScimRepositoryImpl {
+	return &ScimRepositoryImpl{
+		dbConnection:        dbConnection,
+		logger:              logger,
+		TransactionUtilImpl: transactionUtilImpl,
+	}
+}
+
+func (repo *ScimRepositoryImpl) FindActiveToken() (*ScimToken, error) {
+	token := &ScimToken{}
+	err := repo.dbConnection.Model(token).
+		Where("active = ?", true).
+		Limit(1).
+		Select()
+	return token, err
+}
+
+func (repo *ScimRepositoryImpl) SaveToken(token *ScimToken, tx *pg.Tx) error {
+	return tx.Insert(token)
+}
+
+func (repo *ScimRepositoryImpl) DeactivateTokens(userId int32, tx *pg.Tx) error {
+	_, err := tx.Model((*ScimToken)(nil)).
+		Set("active = ?", false).
+		Set("updated_on = ?", time.Now()).
+		Set("updated_by = ?", userId).
+		Where("active = ?", true).
+		Update()
+	return err
+}
+
+func (repo *ScimRepositoryImpl) UpdateTokenLastUsedOn(id int, lastUsedOn time.Time) error {
+	_, err := repo.dbConnection.Model((*ScimToken)(nil)).
+		Set("last_used_on = ?", lastUsedOn).
+		Where("id = ?", id).
+		Update()
+	return err
+}
+
+func (repo *ScimRepositoryImpl) FindResource(resourceType string, resourceId int32) (*ScimResource, error) {
+	resource := &ScimResource{}
+	err := repo.dbConnection.Model(resource).
+		Where("resource_type = ?", resourceType).
+		Where("resource_id = ?", resourceId).
+		Select()
+	return resource, err
+}
+
+func (repo *ScimRepositoryImpl) FindResourcesByType(resourceType string) ([]*ScimResource, error) {
+	var resources []*ScimResource
+	err := repo.dbConnection.Model(&resources).
+		Where("resource_type = ?", resourceType).
+		Select()
+	return resources, err
+}
+
+func (repo *ScimRepositoryImpl) SaveResource(resource *ScimResource) error {
+	return repo.dbConnection.Insert(resource)
+}
+
+func (repo *ScimRepositoryImpl) UpdateResource(resource *ScimResource) error {
+	return repo.dbConnection.Update(resource)
+}
+
+func (repo *ScimRepositoryImpl) DeleteResource(resourceType string, resourceId int32) error {
+	_, err := repo.dbConnection.Model((*ScimResource)(nil)).
+		Where("resource_type = ?", resourceType).
+		Where("resource_id = ?", resourceId).
+		Delete()
+	return err
+}
+
+func (repo *ScimRepositoryImpl) FindAllUsers() ([]*userRepository.UserModel, error) {
+	var users []*userRepository.UserModel
+	err := repo.dbConnection.Model(&users).
+		Where("user_type is NULL or user_type != ?", userBean.USER_TYPE_API_TOKEN).
+		Where("email_id NOT IN (?)", pg.In([]string{userBean.AdminUser, userBean.SystemUser})).
+		Order("id ASC").
+		Select()
+	return users, err
+}
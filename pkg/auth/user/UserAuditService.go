@@ -31,12 +31,23 @@ type UserAudit struct {
 	UpdatedOn time.Time
 }
 
+// AccessGrantAudit is an action on a temporary access grant, e.g. requested, approved or expired
+type AccessGrantAudit struct {
+	AccessGrantId int       `json:"accessGrantId"`
+	Action        string    `json:"action"`
+	Comment       string    `json:"comment,omitempty"`
+	ActionBy      int32     `json:"actionBy"`
+	ActionOn      time.Time `json:"actionOn"`
+}
+
 type UserAuditService interface {
 	Save(userAudit *UserAudit) error
 	GetLatestByUserId(userId int32) (*UserAudit, error)
 	GetLatestUser() (*UserAudit, error)
 	Update(userAudit *UserAudit) error
 	GetActiveUsersCountInLast30Days() (int, error)
+	SaveAccessGrantAudit(accessGrantAudit *AccessGrantAudit) error
+	GetAccessGrantAudits(accessGrantId int) ([]*AccessGrantAudit, error)
 }
 
 type UserAuditServiceImpl struct {
@@ -134,3 +145,39 @@ func (impl UserAuditServiceImpl) GetActiveUsersCountInLast30Days() (int, error)
 	}
 	return count, nil
 }
+
+func (impl UserAuditServiceImpl) SaveAccessGrantAudit(accessGrantAudit *AccessGrantAudit) error {
+	impl.logger.Infow("saving access grant audit", "accessGrantId", accessGrantAudit.AccessGrantId, "action", accessGrantAudit.Action)
+	accessGrantAuditDb := &repository2.UserAccessGrantAudit{
+		AccessGrantId: accessGrantAudit.AccessGrantId,
+		Action:        accessGrantAudit.Action,
+		Comment:       accessGrantAudit.Comment,
+		ActionBy:      accessGrantAudit.ActionBy,
+		ActionOn:      accessGrantAudit.ActionOn,
+	}
+	err := impl.userAuditRepository.SaveAccessGrantAudit(accessGrantAuditDb)
+	if err != nil {
+		impl.logger.Errorw("error while saving access grant audit", "accessGrantId", accessGrantAudit.AccessGrantId, "error", err)
+		return err
+	}
+	return nil
+}
+
+func (impl UserAuditServiceImpl) GetAccessGrantAudits(accessGrantId int) ([]*AccessGrantAudit, error) {
+	accessGrantAuditsDb, err := impl.userAuditRepository.GetAccessGrantAuditsByGrantId(accessGrantId)
+	if err != nil {
+		impl.logger.Errorw("error while getting access grant audits", "accessGrantId", accessGrantId, "error", err)
+		return nil, err
+	}
+	accessGrantAudits := make([]*AccessGrantAudit, 0, len(accessGrantAuditsDb))
+	for _, accessGrantAuditDb := range accessGrantAuditsDb {
+		accessGrantAudits = append(accessGrantAudits, &AccessGrantAudit{
+			AccessGrantId: accessGrantAuditDb.AccessGrantId,
+			Action:        accessGrantAuditDb.Action,
+			Comment:       accessGrantAuditDb.Comment,
+			ActionBy:      accessGrantAuditDb.ActionBy,
+			ActionOn:      accessGrantAuditDb.ActionOn,
+		})
+	}
+	return accessGrantAudits, nil
+}
@@ -400,6 +400,8 @@ func WhitelistChecker(url string) bool {
 		"/orchestrator/auth/login",
 		"/dashboard",
 		"/orchestrator/webhook/git",
+		// authenticated by the scim token
+		"/orchestrator/scim/v2",
 	}
 	for _, a := range prefixUrls {
 		if strings.Contains(url, a) {
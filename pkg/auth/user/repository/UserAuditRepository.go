@@ -34,12 +34,25 @@ type UserAudit struct {
 	UpdatedOn time.Time `sql:"updated_on,type:timestamptz"`
 }
 
+// UserAccessGrantAudit is an action on a temporary access grant of a user or a role group
+type UserAccessGrantAudit struct {
+	TableName     struct{}  `sql:"user_access_grant_audit" pg:",discard_unknown_columns"`
+	Id            int       `sql:"id,pk"`
+	AccessGrantId int       `sql:"access_grant_id,notnull"`
+	Action        string    `sql:"action,notnull"`
+	Comment       string    `sql:"comment"`
+	ActionBy      int32     `sql:"action_by,notnull"`
+	ActionOn      time.Time `sql:"action_on,type:timestamptz"`
+}
+
 type UserAuditRepository interface {
 	Save(userAudit *UserAudit) error
 	GetLatestByUserId(userId int32) (*UserAudit, error)
 	GetLatestUser() (*UserAudit, error)
 	Update(userAudit *UserAudit) error
 	GetActiveUsersCountInLast30Days() (int, error)
+	SaveAccessGrantAudit(accessGrantAudit *UserAccessGrantAudit) error
+	GetAccessGrantAuditsByGrantId(accessGrantId int) ([]*UserAccessGrantAudit, error)
 }
 
 type UserAuditRepositoryImpl struct {
@@ -100,3 +113,16 @@ func (impl UserAuditRepositoryImpl) GetActiveUsersCountInLast30Days() (int, erro
 
 	return count, err
 }
+
+func (impl UserAuditRepositoryImpl) SaveAccessGrantAudit(accessGrantAudit *UserAccessGrantAudit) error {
+	return impl.dbConnection.Insert(accessGrantAudit)
+}
+
+func (impl UserAuditRepositoryImpl) GetAccessGrantAuditsByGrantId(accessGrantId int) ([]*UserAccessGrantAudit, error) {
+	var accessGrantAudits []*UserAccessGrantAudit
+	err := impl.dbConnection.Model(&accessGrantAudits).
+		Where("access_grant_id = ?", accessGrantId).
+		Order("id asc").
+		Select()
+	return accessGrantAudits, err
+}
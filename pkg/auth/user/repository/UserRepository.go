@@ -26,6 +26,7 @@ import (
 	"github.com/devtron-labs/devtron/pkg/auth/user/util"
 	"github.com/devtron-labs/devtron/pkg/sql"
 	"github.com/go-pg/pg"
+	"github.com/go-pg/pg/orm"
 	"go.uber.org/zap"
 	"time"
 )
@@ -270,10 +271,19 @@ func (impl UserRepositoryImpl) GetCountExecutingQuery(query string, queryParams
 // below method does operation on api_token table,
 // we are writing this method here instead of ApiTokenRepository to avoid cyclic import
 func (impl UserRepositoryImpl) CheckIfTokenExistsByTokenNameAndVersion(tokenName string, tokenVersion int) (bool, error) {
+	// the version replaced by a rotation stays valid until the overlap period of the rotation ends
 	query := impl.dbConnection.Model().
 		Table(userBean.ApiTokenTableName).
 		Where("name = ?", tokenName).
-		Where("version = ?", tokenVersion)
+		WhereGroup(func(q *orm.Query) (*orm.Query, error) {
+			q = q.WhereOr("version = ?", tokenVersion).
+				WhereOrGroup(func(q *orm.Query) (*orm.Query, error) {
+					q = q.Where("previous_version = ?", tokenVersion).
+						Where("previous_version_expire_at_in_ms > ?", time.Now().UnixMilli())
+					return q, nil
+				})
+			return q, nil
+		})
 
 	exists, err := query.Exists()
 	return exists, err
@@ -23,6 +23,7 @@ import (
 	bean4 "github.com/devtron-labs/common-lib/utils/bean"
 	"github.com/devtron-labs/common-lib/utils/k8s"
 	commonBean "github.com/devtron-labs/common-lib/workflow"
+	"github.com/devtron-labs/devtron/cel"
 	client "github.com/devtron-labs/devtron/client/events"
 	"github.com/devtron-labs/devtron/client/gitSensor"
 	"github.com/devtron-labs/devtron/internal/middleware"
@@ -63,6 +64,8 @@ import (
 	"github.com/devtron-labs/devtron/pkg/plugin"
 	bean2 "github.com/devtron-labs/devtron/pkg/plugin/bean"
 	repository2 "github.com/devtron-labs/devtron/pkg/plugin/repository"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/celPolicy"
+	celPolicyBean "github.com/devtron-labs/devtron/pkg/policyGovernance/celPolicy/bean"
 	"github.com/devtron-labs/devtron/pkg/resourceQualifiers"
 	"github.com/devtron-labs/devtron/pkg/variables"
 	repository4 "github.com/devtron-labs/devtron/pkg/variables/repository"
@@ -125,6 +128,7 @@ type HandlerServiceImpl struct {
 	K8sUtil                      *k8s.K8sServiceImpl
 	asyncRunnable                *async.Runnable
 	workflowTriggerAuditService  auditService.WorkflowTriggerAuditService
+	celPolicyService             celPolicy.CelPolicyService
 }
 
 func NewHandlerServiceImpl(Logger *zap.SugaredLogger, workflowService executor.WorkflowService,
@@ -152,6 +156,7 @@ func NewHandlerServiceImpl(Logger *zap.SugaredLogger, workflowService executor.W
 	K8sUtil *k8s.K8sServiceImpl,
 	asyncRunnable *async.Runnable,
 	workflowTriggerAuditService auditService.WorkflowTriggerAuditService,
+	celPolicyService celPolicy.CelPolicyService,
 ) *HandlerServiceImpl {
 	buildxCacheFlags := &BuildxGlobalFlags{}
 	err := env.Parse(buildxCacheFlags)
@@ -187,6 +192,7 @@ func NewHandlerServiceImpl(Logger *zap.SugaredLogger, workflowService executor.W
 		K8sUtil:                      K8sUtil,
 		asyncRunnable:                asyncRunnable,
 		workflowTriggerAuditService:  workflowTriggerAuditService,
+		celPolicyService:             celPolicyService,
 	}
 	config, err := types.GetCiConfig()
 	if err != nil {
@@ -808,11 +814,25 @@ func (impl *HandlerServiceImpl) StartCiWorkflowAndPrepareWfRequest(trigger *type
 			AppName:   pipeline.App.AppName,
 		}
 	}
-	savedCiWf, err := impl.saveNewWorkflowForCITrigger(pipeline, ciWorkflowConfigNamespace, trigger.CommitHashes, trigger.TriggeredBy, ciMaterials, trigger.EnvironmentId, isJob, trigger.ReferenceCiWorkflowId)
+	executorType, err := impl.getWorkflowExecutorType(envModal)
+	if err != nil {
+		impl.Logger.Errorw("error in getting workflow executor type", "pipelineId", trigger.PipelineId, "err", err)
+		return nil, nil, nil, err
+	}
+	savedCiWf, err := impl.saveNewWorkflowForCITrigger(pipeline, ciWorkflowConfigNamespace, trigger.CommitHashes, trigger.TriggeredBy, ciMaterials, trigger.EnvironmentId, isJob, trigger.ReferenceCiWorkflowId, executorType)
 	if err != nil {
 		impl.Logger.Errorw("could not save new workflow", "err", err)
 		return nil, nil, nil, err
 	}
+	err = impl.evaluateCiTriggerPolicies(pipeline, trigger, scope.EnvId)
+	if err != nil {
+		impl.Logger.Errorw("ci trigger blocked by policy", "ciPipelineId", pipeline.Id, "err", err)
+		dbErr := impl.markCurrentCiWorkflowFailed(savedCiWf, err)
+		if dbErr != nil {
+			impl.Logger.Errorw("saving workflow error", "err", dbErr)
+		}
+		return nil, nil, nil, err
+	}
 	// preCiSteps, postCiSteps, refPluginsData, err := impl.pipelineStageService.BuildPrePostAndRefPluginStepsDataForWfRequest(pipeline.Id, ciEvent)
 	request := pipelineConfigBean.NewBuildPrePostStepDataReq(pipeline.Id, pipelineConfigBean.CiStage, scope)
 	request = updateBuildPrePostStepDataReq(request, trigger)
@@ -928,6 +948,20 @@ func (impl *HandlerServiceImpl) getEnvironmentForJob(pipeline *pipelineConfig.Ci
 	return nil, isJob, nil
 }
 
+// getWorkflowExecutorType returns the executor type of the cluster the ci workflow runs in,
+// jobs with an environment run in the cluster of the environment and the rest in the default cluster
+func (impl *HandlerServiceImpl) getWorkflowExecutorType(env *repository6.Environment) (cdWorkflow.WorkflowExecutorType, error) {
+	if env != nil && env.Cluster != nil {
+		return impl.config.GetWorkflowExecutorTypeForCluster(env.Cluster.WorkflowExecutorType), nil
+	}
+	defaultCluster, err := impl.clusterService.FindById(clusterBean.DefaultClusterId)
+	if err != nil {
+		impl.Logger.Errorw("error in getting default cluster", "err", err)
+		return "", err
+	}
+	return impl.config.GetWorkflowExecutorTypeForCluster(defaultCluster.WorkflowExecutorType), nil
+}
+
 // TODO: Send all trigger data
 func (impl *HandlerServiceImpl) BuildPayload(trigger types.CiTriggerRequest, pipeline *pipelineConfig.CiPipeline) *client.Payload {
 	payload := &client.Payload{}
@@ -937,7 +971,8 @@ func (impl *HandlerServiceImpl) BuildPayload(trigger types.CiTriggerRequest, pip
 }
 
 func (impl *HandlerServiceImpl) saveNewWorkflowForCITrigger(pipeline *pipelineConfig.CiPipeline, ciWorkflowConfigNamespace string,
-	commitHashes map[int]pipelineConfig.GitCommit, userId int32, ciMaterials []*pipelineConfig.CiPipelineMaterial, EnvironmentId int, isJob bool, refCiWorkflowId int) (*pipelineConfig.CiWorkflow, error) {
+	commitHashes map[int]pipelineConfig.GitCommit, userId int32, ciMaterials []*pipelineConfig.CiPipelineMaterial, EnvironmentId int, isJob bool, refCiWorkflowId int,
+	executorType cdWorkflow.WorkflowExecutorType) (*pipelineConfig.CiWorkflow, error) {
 
 	isCiTriggerBlocked, err := impl.checkIfCITriggerIsBlocked(pipeline, ciMaterials, isJob)
 	if err != nil {
@@ -956,7 +991,7 @@ func (impl *HandlerServiceImpl) saveNewWorkflowForCITrigger(pipeline *pipelineCo
 		LogLocation:           "",
 		TriggeredBy:           userId,
 		ReferenceCiWorkflowId: refCiWorkflowId,
-		ExecutorType:          impl.config.GetWorkflowExecutorType(),
+		ExecutorType:          executorType,
 	}
 	if isJob {
 		ciWorkflow.Namespace = ciWorkflowConfigNamespace
@@ -1260,7 +1295,7 @@ func (impl *HandlerServiceImpl) buildWfRequestForCiPipeline(pipeline *pipelineCo
 		OrchestratorToken:           impl.config.OrchestratorToken,
 		ImageRetryCount:             impl.config.ImageRetryCount,
 		ImageRetryInterval:          impl.config.ImageRetryInterval,
-		WorkflowExecutor:            impl.config.GetWorkflowExecutorType(),
+		WorkflowExecutor:            savedWf.ExecutorType,
 		Type:                        pipelineConfigBean.CI_WORKFLOW_PIPELINE_TYPE,
 		CiArtifactLastFetch:         trigger.CiArtifactLastFetch,
 		RegistryCredentialMap:       registryCredentialMap,
@@ -1646,6 +1681,58 @@ func _getTruncatedImageTag(imageTag string) string {
 	}
 }
 
+// evaluateCiTriggerPolicies evaluates the CEL policies of CI_TRIGGER hook, returns an api error if the build is blocked
+func (impl *HandlerServiceImpl) evaluateCiTriggerPolicies(pipeline *pipelineConfig.CiPipeline, trigger *types.CiTriggerRequest, envId int) error {
+	triggeredBy, err := impl.userService.GetEmailById(trigger.TriggeredBy)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.Logger.Errorw("error in fetching user email", "userId", trigger.TriggeredBy, "err", err)
+		return err
+	}
+	gitBranches := getGitBranchesFromCommitHashes(trigger.CommitHashes)
+	var gitBranch string
+	if len(gitBranches) > 0 {
+		gitBranch = gitBranches[0]
+	}
+	evaluationRequest := &celPolicyBean.EvaluationRequest{
+		Hook: celPolicyBean.CiTriggerHook,
+		Scope: &celPolicyBean.PolicyEvaluationScope{
+			AppId: pipeline.AppId,
+			EnvId: envId,
+		},
+		Params: []cel.ExpressionParam{
+			{ParamName: cel.CiPipelineName, Value: pipeline.Name, Type: cel.ParamTypeString},
+			{ParamName: cel.GitBranch, Value: gitBranch, Type: cel.ParamTypeString},
+			{ParamName: cel.GitBranches, Value: gitBranches, Type: cel.ParamTypeList},
+			{ParamName: cel.TriggeredBy, Value: triggeredBy, Type: cel.ParamTypeString},
+		},
+	}
+	_, err = impl.celPolicyService.EvaluatePolicies(context.Background(), evaluationRequest)
+	policyViolationErr := &celPolicyBean.PolicyViolationError{}
+	if errors.As(err, &policyViolationErr) {
+		return util.NewApiError(http.StatusPreconditionFailed, policyViolationErr.Error(), policyViolationErr.Error())
+	}
+	return err
+}
+
+// getGitBranchesFromCommitHashes returns the branches being built, ordered by the ci material id
+func getGitBranchesFromCommitHashes(commitHashes map[int]pipelineConfig.GitCommit) []string {
+	materialIds := make([]int, 0, len(commitHashes))
+	for materialId := range commitHashes {
+		materialIds = append(materialIds, materialId)
+	}
+	slices.Sort(materialIds)
+	gitBranches := make([]string, 0, len(materialIds))
+	for _, materialId := range materialIds {
+		gitCommit := commitHashes[materialId]
+		if gitCommit.CiConfigureSourceType == constants.SOURCE_TYPE_BRANCH_FIXED {
+			gitBranches = append(gitBranches, gitCommit.CiConfigureSourceValue)
+		} else if sourceBranch, ok := gitCommit.WebhookData.Data[bean.WEBHOOK_SELECTOR_SOURCE_BRANCH_NAME_NAME]; ok {
+			gitBranches = append(gitBranches, sourceBranch)
+		}
+	}
+	return gitBranches
+}
+
 func (impl *HandlerServiceImpl) markCurrentCiWorkflowFailed(savedCiWf *pipelineConfig.CiWorkflow, validationErr error) error {
 	// currently such requirement is not there
 	if savedCiWf == nil {
@@ -1725,7 +1812,7 @@ func (impl *HandlerServiceImpl) CancelBuild(workflowId int, forceAbort bool) (in
 	}
 
 	workflow.Status = cdWorkflow.WorkflowCancel
-	if workflow.ExecutorType == cdWorkflow.WORKFLOW_EXECUTOR_TYPE_SYSTEM {
+	if workflow.ExecutorType.IsPodExecutor() {
 		workflow.PodStatus = "Failed"
 		workflow.Message = constants2.TERMINATE_MESSAGE
 	}
@@ -1,6 +1,10 @@
 package adapter
 
-import "github.com/devtron-labs/devtron/pkg/bulkAction/bean"
+import (
+	"encoding/json"
+	"github.com/devtron-labs/devtron/pkg/bulkAction/bean"
+	"github.com/devtron-labs/devtron/pkg/bulkAction/repository"
+)
 
 func GetCmAndSecretBulkUpdateResponseForOneApp(appId int, appName string, envId int, names []string, message string) *bean.CmAndSecretBulkUpdateResponseForOneApp {
 	return &bean.CmAndSecretBulkUpdateResponseForOneApp{
@@ -20,3 +24,45 @@ func GetDeploymentTemplateBulkUpdateResponseForOneApp(appId int, appName string,
 		Message: message,
 	}
 }
+
+func GetBulkEditJobObjectDto(object *repository.BulkEditJobObject) *bean.BulkEditJobObjectDto {
+	return &bean.BulkEditJobObjectDto{
+		Id:         object.Id,
+		ObjectType: bean.BulkEditObjectType(object.ObjectType),
+		AppId:      object.AppId,
+		AppName:    object.AppName,
+		EnvId:      object.EnvId,
+		Status:     bean.BulkEditObjectStatus(object.Status),
+		Message:    object.Message,
+	}
+}
+
+// GetBulkEditJobDto converts the job to the dto, the payload and the response are left nil if they cannot be parsed
+func GetBulkEditJobDto(job *repository.BulkEditJob, objects []*repository.BulkEditJobObject) *bean.BulkEditJobDto {
+	jobDto := &bean.BulkEditJobDto{
+		Id:           job.Id,
+		Status:       bean.BulkEditJobStatus(job.Status),
+		Message:      job.Message,
+		ScheduledAt:  job.ScheduledAt,
+		StartedOn:    job.StartedOn,
+		FinishedOn:   job.FinishedOn,
+		CreatedBy:    job.CreatedBy,
+		CreatedOn:    job.CreatedOn,
+		RolledBackBy: job.RolledBackBy,
+		RolledBackOn: job.RolledBackOn,
+	}
+	payload := &bean.BulkUpdatePayload{}
+	if err := json.Unmarshal([]byte(job.Payload), payload); err == nil {
+		jobDto.Payload = payload
+	}
+	if len(job.Response) > 0 {
+		response := &bean.BulkUpdateResponse{}
+		if err := json.Unmarshal([]byte(job.Response), response); err == nil {
+			jobDto.Response = response
+		}
+	}
+	for _, object := range objects {
+		jobDto.Objects = append(jobDto.Objects, GetBulkEditJobObjectDto(object))
+	}
+	return jobDto
+}
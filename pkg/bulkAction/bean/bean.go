@@ -106,11 +106,17 @@ type BulkApplicationForEnvironmentPayload struct {
 	AppNamesExcludes []string `json:"appNamesExcludes,omitempty"`
 	UserId           int32    `json:"-"`
 	InvalidateCache  bool     `json:"invalidateCache"`
+	// DeploymentWindowOverride deploys in spite of the blocking deployment windows on the pipelines
+	// for which the user has the override permission, used in bulk deploy
+	DeploymentWindowOverride       bool   `json:"deploymentWindowOverride,omitempty"`
+	DeploymentWindowOverrideReason string `json:"deploymentWindowOverrideReason,omitempty"`
 }
 
 type BulkApplicationForEnvironmentResponse struct {
 	BulkApplicationForEnvironmentPayload
 	Response map[string]map[string]bool `json:"response"`
+	// BlockedByDeploymentWindow is the map of pipeline key to the reason for pipelines skipped due to deployment windows
+	BlockedByDeploymentWindow map[string]string `json:"blockedByDeploymentWindow,omitempty"`
 }
 
 type BulkApplicationHibernateUnhibernateForEnvironmentResponse struct {
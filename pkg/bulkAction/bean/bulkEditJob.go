@@ -0,0 +1,111 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import (
+	userBean "github.com/devtron-labs/devtron/pkg/auth/user/bean"
+	"time"
+)
+
+type BulkEditJobStatus string
+
+const (
+	BulkEditJobScheduled      BulkEditJobStatus = "SCHEDULED"
+	BulkEditJobRunning        BulkEditJobStatus = "RUNNING"
+	BulkEditJobCompleted      BulkEditJobStatus = "COMPLETED"
+	BulkEditJobFailed         BulkEditJobStatus = "FAILED"
+	BulkEditJobCancelled      BulkEditJobStatus = "CANCELLED"
+	BulkEditJobRollingBack    BulkEditJobStatus = "ROLLING_BACK"
+	BulkEditJobRolledBack     BulkEditJobStatus = "ROLLED_BACK"
+	BulkEditJobRollbackFailed BulkEditJobStatus = "ROLLBACK_FAILED"
+)
+
+func (s BulkEditJobStatus) String() string {
+	return string(s)
+}
+
+type BulkEditObjectType string
+
+const (
+	BulkEditObjectDeploymentTemplate BulkEditObjectType = "DEPLOYMENT_TEMPLATE"
+	BulkEditObjectConfigMap          BulkEditObjectType = "CONFIG_MAP"
+	BulkEditObjectSecret             BulkEditObjectType = "SECRET"
+)
+
+func (t BulkEditObjectType) String() string {
+	return string(t)
+}
+
+type BulkEditObjectStatus string
+
+const (
+	// BulkEditObjectEdited is for the objects modified by the job
+	BulkEditObjectEdited BulkEditObjectStatus = "EDITED"
+	// BulkEditObjectUnchanged is for the matched objects left unchanged by the job, e.g. the patch failed on them
+	BulkEditObjectUnchanged BulkEditObjectStatus = "UNCHANGED"
+	// BulkEditObjectSkipped is for the matched objects not edited as the user who created the job cannot edit them anymore
+	BulkEditObjectSkipped        BulkEditObjectStatus = "SKIPPED"
+	BulkEditObjectRolledBack     BulkEditObjectStatus = "ROLLED_BACK"
+	BulkEditObjectRollbackFailed BulkEditObjectStatus = "ROLLBACK_FAILED"
+	// BulkEditObjectRollbackSkipped is for the edited objects modified again after the job, these are not rolled back
+	// unless forced so that the later changes are not lost
+	BulkEditObjectRollbackSkipped BulkEditObjectStatus = "ROLLBACK_SKIPPED"
+)
+
+func (s BulkEditObjectStatus) String() string {
+	return string(s)
+}
+
+// BulkEditJobRequest creates a bulk edit job, executed immediately if ScheduledAt is not set
+type BulkEditJobRequest struct {
+	Script       *BulkUpdateScript        `json:"script" validate:"required"`
+	ScheduledAt  *time.Time               `json:"scheduledAt,omitempty"`
+	UserMetadata *userBean.UserMetadata   `json:"-"`
+	Impacted     *ImpactedObjectsResponse `json:"-"`
+}
+
+type BulkEditJobRollbackRequest struct {
+	// Force rolls back the objects modified again after the job as well
+	Force  bool  `json:"force"`
+	UserId int32 `json:"-"`
+}
+
+type BulkEditJobDto struct {
+	Id           int                     `json:"id"`
+	Status       BulkEditJobStatus       `json:"status"`
+	Message      string                  `json:"message,omitempty"`
+	Payload      *BulkUpdatePayload      `json:"payload"`
+	ScheduledAt  *time.Time              `json:"scheduledAt,omitempty"`
+	StartedOn    *time.Time              `json:"startedOn,omitempty"`
+	FinishedOn   *time.Time              `json:"finishedOn,omitempty"`
+	Response     *BulkUpdateResponse     `json:"response,omitempty"`
+	CreatedBy    int32                   `json:"createdBy"`
+	CreatedOn    time.Time               `json:"createdOn"`
+	RolledBackBy int32                   `json:"rolledBackBy,omitempty"`
+	RolledBackOn *time.Time              `json:"rolledBackOn,omitempty"`
+	Objects      []*BulkEditJobObjectDto `json:"objects,omitempty"`
+}
+
+type BulkEditJobObjectDto struct {
+	Id         int                  `json:"id"`
+	ObjectType BulkEditObjectType   `json:"objectType"`
+	AppId      int                  `json:"appId"`
+	AppName    string               `json:"appName"`
+	EnvId      int                  `json:"envId"`
+	Status     BulkEditObjectStatus `json:"status"`
+	Message    string               `json:"message,omitempty"`
+}
@@ -0,0 +1,162 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+// BulkEditJob is a bulk edit persisted with its payload, executed immediately or at ScheduledAt.
+// ImpactedObjects is the json of the impacted objects authorised when the job was created and
+// Response is the json of the bulk edit response once the job is executed.
+type BulkEditJob struct {
+	tableName       struct{}   `sql:"bulk_edit_job" pg:",discard_unknown_columns"`
+	Id              int        `sql:"id,pk"`
+	Payload         string     `sql:"payload,notnull"`
+	ImpactedObjects string     `sql:"impacted_objects"`
+	Status          string     `sql:"status,notnull"`
+	Message         string     `sql:"message"`
+	ScheduledAt     *time.Time `sql:"scheduled_at"`
+	StartedOn       *time.Time `sql:"started_on"`
+	FinishedOn      *time.Time `sql:"finished_on"`
+	Response        string     `sql:"response"`
+	UserEmailId     string     `sql:"user_email_id"`
+	RolledBackBy    int32      `sql:"rolled_back_by"`
+	RolledBackOn    *time.Time `sql:"rolled_back_on"`
+	sql.AuditLog
+}
+
+// BulkEditJobObject is a deployment template, config map or secret impacted by a bulk edit job.
+// PreviousData is the data of the object before the edit and EditedData the data after it, used for detecting the
+// changes made after the job. Rollback restores the version in the history entry HistoryId.
+type BulkEditJobObject struct {
+	tableName    struct{} `sql:"bulk_edit_job_object" pg:",discard_unknown_columns"`
+	Id           int      `sql:"id,pk"`
+	JobId        int      `sql:"job_id,notnull"`
+	ObjectType   string   `sql:"object_type,notnull"`
+	ObjectId     int      `sql:"object_id,notnull"`
+	AppId        int      `sql:"app_id,notnull"`
+	AppName      string   `sql:"app_name"`
+	EnvId        int      `sql:"env_id,notnull"`
+	PreviousData string   `sql:"previous_data"`
+	HistoryId    int      `sql:"history_id"`
+	EditedData   string   `sql:"edited_data"`
+	Status       string   `sql:"status,notnull"`
+	Message      string   `sql:"message"`
+	sql.AuditLog
+}
+
+type BulkEditJobRepository interface {
+	Save(job *BulkEditJob) error
+	Update(job *BulkEditJob) error
+	FindById(id int) (*BulkEditJob, error)
+	// FindAll returns the latest jobs, only the ones created by createdBy if it is non-zero
+	FindAll(createdBy int32, offset, limit int) ([]*BulkEditJob, error)
+	// UpdateStatus moves the job to status only if it is in one of fromStatuses, returns false if the job was not in
+	// any of them. Used for claiming the job so that the same job is not processed twice.
+	UpdateStatus(id int, fromStatuses []string, status string, userId int32) (bool, error)
+	// ClaimDueScheduledJobs marks the scheduled jobs due at the given time as running and returns only the jobs claimed by this call
+	ClaimDueScheduledJobs(dueAt time.Time, runningStatus, scheduledStatus string) ([]*BulkEditJob, error)
+
+	SaveObjects(objects []*BulkEditJobObject) error
+	UpdateObject(object *BulkEditJobObject) error
+	FindObjectsByJobId(jobId int) ([]*BulkEditJobObject, error)
+}
+
+type BulkEditJobRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewBulkEditJobRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *BulkEditJobRepositoryImpl {
+	return &BulkEditJobRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *BulkEditJobRepositoryImpl) Save(job *BulkEditJob) error {
+	return impl.dbConnection.Insert(job)
+}
+
+func (impl *BulkEditJobRepositoryImpl) Update(job *BulkEditJob) error {
+	return impl.dbConnection.Update(job)
+}
+
+func (impl *BulkEditJobRepositoryImpl) FindById(id int) (*BulkEditJob, error) {
+	job := &BulkEditJob{}
+	err := impl.dbConnection.Model(job).Where("id = ?", id).Select()
+	return job, err
+}
+
+func (impl *BulkEditJobRepositoryImpl) FindAll(createdBy int32, offset, limit int) ([]*BulkEditJob, error) {
+	var jobs []*BulkEditJob
+	query := impl.dbConnection.Model(&jobs)
+	if createdBy > 0 {
+		query = query.Where("created_by = ?", createdBy)
+	}
+	err := query.Order("id DESC").Offset(offset).Limit(limit).Select()
+	return jobs, err
+}
+
+func (impl *BulkEditJobRepositoryImpl) UpdateStatus(id int, fromStatuses []string, status string, userId int32) (bool, error) {
+	result, err := impl.dbConnection.Model(&BulkEditJob{}).
+		Set("status = ?", status).
+		Set("updated_on = ?", time.Now()).
+		Set("updated_by = ?", userId).
+		Where("id = ?", id).
+		Where("status IN (?)", pg.In(fromStatuses)).
+		Update()
+	if err != nil {
+		impl.logger.Errorw("error in updating bulk edit job status", "id", id, "status", status, "err", err)
+		return false, err
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+func (impl *BulkEditJobRepositoryImpl) ClaimDueScheduledJobs(dueAt time.Time, runningStatus, scheduledStatus string) ([]*BulkEditJob, error) {
+	var jobs []*BulkEditJob
+	query := "UPDATE bulk_edit_job SET status = ?, started_on = ?, updated_on = ? " +
+		"WHERE status = ? AND scheduled_at <= ? RETURNING *;"
+	now := time.Now()
+	_, err := impl.dbConnection.Query(&jobs, query, runningStatus, now, now, scheduledStatus, dueAt)
+	return jobs, err
+}
+
+func (impl *BulkEditJobRepositoryImpl) SaveObjects(objects []*BulkEditJobObject) error {
+	if len(objects) == 0 {
+		return nil
+	}
+	_, err := impl.dbConnection.Model(&objects).Insert()
+	return err
+}
+
+func (impl *BulkEditJobRepositoryImpl) UpdateObject(object *BulkEditJobObject) error {
+	return impl.dbConnection.Update(object)
+}
+
+func (impl *BulkEditJobRepositoryImpl) FindObjectsByJobId(jobId int) ([]*BulkEditJobObject, error) {
+	var objects []*BulkEditJobObject
+	err := impl.dbConnection.Model(&objects).
+		Where("job_id = ?", jobId).
+		Order("id ASC").
+		Select()
+	return objects, err
+}
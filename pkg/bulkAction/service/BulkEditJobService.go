@@ -0,0 +1,902 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/caarlos0/env"
+	"github.com/devtron-labs/devtron/internal/sql/repository/app"
+	"github.com/devtron-labs/devtron/internal/sql/repository/chartConfig"
+	"github.com/devtron-labs/devtron/internal/sql/repository/helper"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	userBean "github.com/devtron-labs/devtron/pkg/auth/user/bean"
+	"github.com/devtron-labs/devtron/pkg/bulkAction/adapter"
+	"github.com/devtron-labs/devtron/pkg/bulkAction/bean"
+	"github.com/devtron-labs/devtron/pkg/bulkAction/repository"
+	chartRepoRepository "github.com/devtron-labs/devtron/pkg/chartRepo/repository"
+	"github.com/devtron-labs/devtron/pkg/deployment/manifest/configMapAndSecret"
+	cmRead "github.com/devtron-labs/devtron/pkg/deployment/manifest/configMapAndSecret/read"
+	"github.com/devtron-labs/devtron/pkg/deployment/manifest/deployedAppMetrics"
+	"github.com/devtron-labs/devtron/pkg/deployment/manifest/deploymentTemplate"
+	dtAdapter "github.com/devtron-labs/devtron/pkg/deployment/manifest/deploymentTemplate/adapter"
+	dtRead "github.com/devtron-labs/devtron/pkg/deployment/manifest/deploymentTemplate/read"
+	historyRepository "github.com/devtron-labs/devtron/pkg/pipeline/history/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/devtron-labs/devtron/pkg/variables"
+	variablesRepository "github.com/devtron-labs/devtron/pkg/variables/repository"
+	cron2 "github.com/devtron-labs/devtron/util/cron"
+	"github.com/devtron-labs/devtron/util/rbac"
+	"github.com/robfig/cron/v3"
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+	"go.uber.org/zap"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type BulkEditJobConfig struct {
+	BulkEditJobCron string `env:"BULK_EDIT_JOB_CRON" envDefault:"* * * * *" description:"Cron schedule for executing the scheduled bulk edit jobs"`
+}
+
+type BulkEditJobService interface {
+	// CreateJob persists the bulk edit as a job, the job is executed immediately unless it is scheduled for later
+	CreateJob(ctx context.Context, request *bean.BulkEditJobRequest) (*bean.BulkEditJobDto, error)
+	GetJob(id int) (*bean.BulkEditJobDto, error)
+	// GetAllJobs returns the latest jobs, only the ones created by the user unless the user is a super admin
+	GetAllJobs(userMetadata *userBean.UserMetadata, offset, limit int) ([]*bean.BulkEditJobDto, error)
+	CancelJob(id int, userId int32) error
+	// RollbackJob restores the objects edited by the job to their version before the edit
+	RollbackJob(ctx context.Context, id int, request *bean.BulkEditJobRollbackRequest) (*bean.BulkEditJobDto, error)
+	ExecuteScheduledJobs()
+}
+
+type BulkEditJobServiceImpl struct {
+	logger                               *zap.SugaredLogger
+	bulkEditJobRepository                repository.BulkEditJobRepository
+	bulkEditRepository                   repository.BulkEditRepository
+	bulkUpdateService                    BulkUpdateService
+	appRepository                        app.AppRepository
+	chartRepository                      chartRepoRepository.ChartRepository
+	envConfigOverrideRepository          chartConfig.EnvConfigOverrideRepository
+	configMapRepository                  chartConfig.ConfigMapRepository
+	deploymentTemplateHistoryService     deploymentTemplate.DeploymentTemplateHistoryService
+	configMapHistoryService              configMapAndSecret.ConfigMapHistoryService
+	deploymentTemplateHistoryReadService dtRead.DeploymentTemplateHistoryReadService
+	configMapHistoryReadService          cmRead.ConfigMapHistoryReadService
+	deployedAppMetricsService            deployedAppMetrics.DeployedAppMetricsService
+	scopedVariableManager                variables.ScopedVariableManager
+	enforcer                             casbin.Enforcer
+	enforcerUtil                         rbac.EnforcerUtil
+	userService                          user.UserService
+	cron                                 *cron.Cron
+}
+
+func NewBulkEditJobServiceImpl(logger *zap.SugaredLogger,
+	bulkEditJobRepository repository.BulkEditJobRepository,
+	bulkEditRepository repository.BulkEditRepository,
+	bulkUpdateService BulkUpdateService,
+	appRepository app.AppRepository,
+	chartRepository chartRepoRepository.ChartRepository,
+	envConfigOverrideRepository chartConfig.EnvConfigOverrideRepository,
+	configMapRepository chartConfig.ConfigMapRepository,
+	deploymentTemplateHistoryService deploymentTemplate.DeploymentTemplateHistoryService,
+	configMapHistoryService configMapAndSecret.ConfigMapHistoryService,
+	deploymentTemplateHistoryReadService dtRead.DeploymentTemplateHistoryReadService,
+	configMapHistoryReadService cmRead.ConfigMapHistoryReadService,
+	deployedAppMetricsService deployedAppMetrics.DeployedAppMetricsService,
+	scopedVariableManager variables.ScopedVariableManager,
+	enforcer casbin.Enforcer,
+	enforcerUtil rbac.EnforcerUtil,
+	userService user.UserService,
+	cronLogger *cron2.CronLoggerImpl,
+) *BulkEditJobServiceImpl {
+	impl := &BulkEditJobServiceImpl{
+		logger:                               logger,
+		bulkEditJobRepository:                bulkEditJobRepository,
+		bulkEditRepository:                   bulkEditRepository,
+		bulkUpdateService:                    bulkUpdateService,
+		appRepository:                        appRepository,
+		chartRepository:                      chartRepository,
+		envConfigOverrideRepository:          envConfigOverrideRepository,
+		configMapRepository:                  configMapRepository,
+		deploymentTemplateHistoryService:     deploymentTemplateHistoryService,
+		configMapHistoryService:              configMapHistoryService,
+		deploymentTemplateHistoryReadService: deploymentTemplateHistoryReadService,
+		configMapHistoryReadService:          configMapHistoryReadService,
+		deployedAppMetricsService:            deployedAppMetricsService,
+		scopedVariableManager:                scopedVariableManager,
+		enforcer:                             enforcer,
+		enforcerUtil:                         enforcerUtil,
+		userService:                          userService,
+	}
+	cfg := &BulkEditJobConfig{}
+	if err := env.Parse(cfg); err != nil {
+		logger.Errorw("error in parsing bulk edit job config", "err", err)
+		return impl
+	}
+	impl.cron = cron.New(cron.WithChain(cron.Recover(cronLogger)))
+	impl.cron.Start()
+	_, err := impl.cron.AddFunc(cfg.BulkEditJobCron, impl.ExecuteScheduledJobs)
+	if err != nil {
+		logger.Errorw("error in starting scheduled bulk edit jobs cron", "cron", cfg.BulkEditJobCron, "err", err)
+	}
+	return impl
+}
+
+// chartValuesSnapshot is the data of the base deployment template saved to detect the changes made after the job
+type chartValuesSnapshot struct {
+	Values         string `json:"values"`
+	GlobalOverride string `json:"globalOverride"`
+}
+
+func (impl *BulkEditJobServiceImpl) CreateJob(ctx context.Context, request *bean.BulkEditJobRequest) (*bean.BulkEditJobDto, error) {
+	payload, err := json.Marshal(request.Script.Spec)
+	if err != nil {
+		impl.logger.Errorw("error in marshaling bulk edit payload", "err", err)
+		return nil, err
+	}
+	job := &repository.BulkEditJob{
+		Payload:     string(payload),
+		Status:      bean.BulkEditJobRunning.String(),
+		UserEmailId: request.UserMetadata.UserEmailId,
+		AuditLog:    sql.NewDefaultAuditLog(request.UserMetadata.UserId),
+	}
+	if request.Impacted != nil {
+		impactedObjects, err := json.Marshal(request.Impacted)
+		if err != nil {
+			impl.logger.Errorw("error in marshaling impacted objects", "err", err)
+			return nil, err
+		}
+		job.ImpactedObjects = string(impactedObjects)
+	}
+	now := time.Now()
+	if request.ScheduledAt != nil && request.ScheduledAt.After(now) {
+		job.Status = bean.BulkEditJobScheduled.String()
+		job.ScheduledAt = request.ScheduledAt
+	} else {
+		job.StartedOn = &now
+	}
+	err = impl.bulkEditJobRepository.Save(job)
+	if err != nil {
+		impl.logger.Errorw("error in saving bulk edit job", "err", err)
+		return nil, err
+	}
+	if job.Status == bean.BulkEditJobScheduled.String() {
+		return adapter.GetBulkEditJobDto(job, nil), nil
+	}
+	objects := impl.executeJob(ctx, job)
+	return adapter.GetBulkEditJobDto(job, objects), nil
+}
+
+func (impl *BulkEditJobServiceImpl) GetJob(id int) (*bean.BulkEditJobDto, error) {
+	job, err := impl.bulkEditJobRepository.FindById(id)
+	if util.IsErrNoRows(err) {
+		return nil, util.NewApiError(http.StatusNotFound, "bulk edit job not found", err.Error())
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching bulk edit job", "id", id, "err", err)
+		return nil, err
+	}
+	objects, err := impl.bulkEditJobRepository.FindObjectsByJobId(id)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching bulk edit job objects", "jobId", id, "err", err)
+		return nil, err
+	}
+	return adapter.GetBulkEditJobDto(job, objects), nil
+}
+
+func (impl *BulkEditJobServiceImpl) GetAllJobs(userMetadata *userBean.UserMetadata, offset, limit int) ([]*bean.BulkEditJobDto, error) {
+	createdBy := userMetadata.UserId
+	if userMetadata.IsUserSuperAdmin {
+		createdBy = 0
+	}
+	jobs, err := impl.bulkEditJobRepository.FindAll(createdBy, offset, limit)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching bulk edit jobs", "createdBy", createdBy, "err", err)
+		return nil, err
+	}
+	jobDtos := make([]*bean.BulkEditJobDto, 0, len(jobs))
+	for _, job := range jobs {
+		jobDtos = append(jobDtos, adapter.GetBulkEditJobDto(job, nil))
+	}
+	return jobDtos, nil
+}
+
+func (impl *BulkEditJobServiceImpl) CancelJob(id int, userId int32) error {
+	cancelled, err := impl.bulkEditJobRepository.UpdateStatus(id, []string{bean.BulkEditJobScheduled.String()}, bean.BulkEditJobCancelled.String(), userId)
+	if err != nil {
+		impl.logger.Errorw("error in cancelling bulk edit job", "id", id, "err", err)
+		return err
+	}
+	if !cancelled {
+		return util.NewApiError(http.StatusBadRequest, "only scheduled bulk edit jobs can be cancelled", "bulk edit job is not in scheduled state")
+	}
+	return nil
+}
+
+func (impl *BulkEditJobServiceImpl) ExecuteScheduledJobs() {
+	jobs, err := impl.bulkEditJobRepository.ClaimDueScheduledJobs(time.Now(), bean.BulkEditJobRunning.String(), bean.BulkEditJobScheduled.String())
+	if err != nil {
+		impl.logger.Errorw("error in claiming due scheduled bulk edit jobs", "err", err)
+		return
+	}
+	for _, job := range jobs {
+		impl.logger.Infow("executing scheduled bulk edit job", "id", job.Id, "scheduledAt", job.ScheduledAt)
+		impl.executeJob(context.Background(), job)
+	}
+}
+
+// executeJob snapshots the objects matching the payload, applies the bulk edit and records which objects got edited.
+// The job is failed without editing anything if the objects impacted now are not the ones authorised at its creation,
+// the objects the user cannot edit anymore are skipped.
+func (impl *BulkEditJobServiceImpl) executeJob(ctx context.Context, job *repository.BulkEditJob) []*repository.BulkEditJobObject {
+	payload := &bean.BulkUpdatePayload{}
+	if err := json.Unmarshal([]byte(job.Payload), payload); err != nil {
+		impl.logger.Errorw("error in unmarshalling bulk edit job payload", "id", job.Id, "err", err)
+		impl.finishJob(job, bean.BulkEditJobFailed, "invalid bulk edit payload")
+		return nil
+	}
+	if err := impl.validateImpactedObjects(job, payload); err != nil {
+		impl.finishJob(job, bean.BulkEditJobFailed, err.Error())
+		return nil
+	}
+	userMetadata, err := impl.getUserMetadata(job)
+	if err != nil {
+		impl.finishJob(job, bean.BulkEditJobFailed, err.Error())
+		return nil
+	}
+	objects, err := impl.snapshotObjects(job.Id, payload)
+	if err != nil {
+		impl.logger.Errorw("error in taking snapshot of bulk edit objects", "id", job.Id, "err", err)
+		impl.finishJob(job, bean.BulkEditJobFailed, fmt.Sprintf("error in taking snapshot of the objects: %s", err.Error()))
+		return nil
+	}
+
+	excludedAppNames := impl.skipUnauthorisedObjects(userMetadata, objects)
+	response := impl.bulkEdit(ctx, payload, userMetadata, excludedAppNames)
+
+	for _, object := range objects {
+		if object.Status == bean.BulkEditObjectSkipped.String() {
+			continue
+		}
+		editedData, err := impl.getObjectData(object)
+		if err != nil {
+			impl.logger.Errorw("error in fetching edited data of bulk edit object", "jobId", job.Id, "objectType", object.ObjectType, "objectId", object.ObjectId, "err", err)
+			object.Status = bean.BulkEditObjectUnchanged.String()
+			object.Message = "unable to fetch the object after the edit"
+			continue
+		}
+		object.EditedData = editedData
+		if editedData != object.PreviousData {
+			object.Status = bean.BulkEditObjectEdited.String()
+		}
+	}
+	err = impl.bulkEditJobRepository.SaveObjects(objects)
+	if err != nil {
+		// the edit is already applied, only the rollback of this job is impacted
+		impl.logger.Errorw("error in saving bulk edit job objects", "id", job.Id, "err", err)
+	}
+	responseJson, err := json.Marshal(response)
+	if err != nil {
+		impl.logger.Errorw("error in marshaling bulk edit response", "id", job.Id, "err", err)
+	}
+	job.Response = string(responseJson)
+	impl.finishJob(job, bean.BulkEditJobCompleted, "")
+	return objects
+}
+
+// getUserMetadata resolves the permissions of the user who created the job at the time of execution,
+// as these might have changed since the job was scheduled
+func (impl *BulkEditJobServiceImpl) getUserMetadata(job *repository.BulkEditJob) (*userBean.UserMetadata, error) {
+	emailId, err := impl.userService.GetActiveEmailById(job.CreatedBy)
+	if err != nil {
+		impl.logger.Errorw("error in fetching user of bulk edit job", "id", job.Id, "userId", job.CreatedBy, "err", err)
+		return nil, fmt.Errorf("user who created the job is not active anymore")
+	}
+	return &userBean.UserMetadata{
+		UserEmailId:      emailId,
+		IsUserSuperAdmin: impl.enforcer.EnforceByEmail(emailId, casbin.ResourceGlobal, casbin.ActionCreate, "*"),
+		UserId:           job.CreatedBy,
+	}, nil
+}
+
+// skipUnauthorisedObjects marks the objects the user does not have the update access on as skipped and returns
+// the names of their apps by environment id, 0 being the base configurations
+func (impl *BulkEditJobServiceImpl) skipUnauthorisedObjects(userMetadata *userBean.UserMetadata, objects []*repository.BulkEditJobObject) map[int][]string {
+	excludedAppNames := make(map[int][]string)
+	if userMetadata.IsUserSuperAdmin || len(objects) == 0 {
+		return excludedAppNames
+	}
+	rbacObjects := impl.enforcerUtil.GetRbacObjectsForAllApps(helper.CustomApp)
+	authorisedScopes := make(map[string]bool)
+	for _, object := range objects {
+		scopeKey := fmt.Sprintf("%d-%d", object.AppId, object.EnvId)
+		isAuthorised, checked := authorisedScopes[scopeKey]
+		if !checked {
+			isAuthorised = impl.checkUpdateAccess(userMetadata.UserEmailId, object, rbacObjects)
+			authorisedScopes[scopeKey] = isAuthorised
+			if !isAuthorised {
+				excludedAppNames[object.EnvId] = append(excludedAppNames[object.EnvId], object.AppName)
+			}
+		}
+		if !isAuthorised {
+			object.Status = bean.BulkEditObjectSkipped.String()
+			object.Message = "user who created the job does not have the update access on the app or environment anymore"
+		}
+	}
+	return excludedAppNames
+}
+
+func (impl *BulkEditJobServiceImpl) checkUpdateAccess(emailId string, object *repository.BulkEditJobObject, rbacObjects map[int]string) bool {
+	if ok := impl.enforcer.EnforceByEmail(emailId, casbin.ResourceApplications, casbin.ActionUpdate, rbacObjects[object.AppId]); !ok {
+		return false
+	}
+	if object.EnvId > 0 {
+		resourceName := impl.enforcerUtil.GetAppRBACByAppNameAndEnvId(object.AppName, object.EnvId)
+		return impl.enforcer.EnforceByEmail(emailId, casbin.ResourceEnvironment, casbin.ActionUpdate, resourceName)
+	}
+	return true
+}
+
+// bulkEdit applies the payload leaving out the excluded apps. As the excluded apps differ by environment, the payload
+// is applied separately for the base configurations and each environment when any app is excluded.
+func (impl *BulkEditJobServiceImpl) bulkEdit(ctx context.Context, payload *bean.BulkUpdatePayload, userMetadata *userBean.UserMetadata, excludedAppNames map[int][]string) *bean.BulkUpdateResponse {
+	if len(excludedAppNames) == 0 {
+		return impl.bulkUpdateService.BulkEdit(ctx, payload, userMetadata)
+	}
+	response := &bean.BulkUpdateResponse{}
+	if payload.Global {
+		scopedPayload := getScopedPayload(payload, true, nil, excludedAppNames[0])
+		mergeBulkUpdateResponse(response, impl.bulkUpdateService.BulkEdit(ctx, scopedPayload, userMetadata))
+	}
+	for _, envId := range payload.EnvIds {
+		scopedPayload := getScopedPayload(payload, false, []int{envId}, excludedAppNames[envId])
+		mergeBulkUpdateResponse(response, impl.bulkUpdateService.BulkEdit(ctx, scopedPayload, userMetadata))
+	}
+	return response
+}
+
+// likePatternEscaper escapes the app names for matching them as such in the LIKE patterns of the excludes
+var likePatternEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+func getScopedPayload(payload *bean.BulkUpdatePayload, global bool, envIds []int, excludedAppNames []string) *bean.BulkUpdatePayload {
+	scopedPayload := *payload
+	scopedPayload.Global = global
+	scopedPayload.EnvIds = envIds
+	if len(excludedAppNames) == 0 {
+		return &scopedPayload
+	}
+	excludes := &bean.NameIncludesExcludes{}
+	if payload.Excludes != nil {
+		excludes.Names = append(excludes.Names, payload.Excludes.Names...)
+	}
+	for _, appName := range excludedAppNames {
+		excludes.Names = append(excludes.Names, likePatternEscaper.Replace(appName))
+	}
+	scopedPayload.Excludes = excludes
+	return &scopedPayload
+}
+
+func mergeBulkUpdateResponse(response, scopedResponse *bean.BulkUpdateResponse) {
+	if scopedResponse == nil {
+		return
+	}
+	if scopedResponse.DeploymentTemplate != nil {
+		if response.DeploymentTemplate == nil {
+			response.DeploymentTemplate = &bean.DeploymentTemplateBulkUpdateResponse{}
+		}
+		response.DeploymentTemplate.Message = append(response.DeploymentTemplate.Message, scopedResponse.DeploymentTemplate.Message...)
+		response.DeploymentTemplate.Failure = append(response.DeploymentTemplate.Failure, scopedResponse.DeploymentTemplate.Failure...)
+		response.DeploymentTemplate.Successful = append(response.DeploymentTemplate.Successful, scopedResponse.DeploymentTemplate.Successful...)
+	}
+	response.ConfigMap = mergeCmAndSecretBulkUpdateResponse(response.ConfigMap, scopedResponse.ConfigMap)
+	response.Secret = mergeCmAndSecretBulkUpdateResponse(response.Secret, scopedResponse.Secret)
+}
+
+func mergeCmAndSecretBulkUpdateResponse(response, scopedResponse *bean.CmAndSecretBulkUpdateResponse) *bean.CmAndSecretBulkUpdateResponse {
+	if scopedResponse == nil {
+		return response
+	}
+	if response == nil {
+		response = &bean.CmAndSecretBulkUpdateResponse{}
+	}
+	response.Message = append(response.Message, scopedResponse.Message...)
+	response.Failure = append(response.Failure, scopedResponse.Failure...)
+	response.Successful = append(response.Successful, scopedResponse.Successful...)
+	return response
+}
+
+func (impl *BulkEditJobServiceImpl) finishJob(job *repository.BulkEditJob, status bean.BulkEditJobStatus, message string) {
+	now := time.Now()
+	job.Status = status.String()
+	job.Message = message
+	job.FinishedOn = &now
+	job.UpdatedOn = now
+	if err := impl.bulkEditJobRepository.Update(job); err != nil {
+		impl.logger.Errorw("error in updating bulk edit job", "id", job.Id, "status", status, "err", err)
+	}
+}
+
+// validateImpactedObjects ensures that a job does not edit objects other than the ones authorised when it was created,
+// e.g. apps created after scheduling the job that match its app name patterns
+func (impl *BulkEditJobServiceImpl) validateImpactedObjects(job *repository.BulkEditJob, payload *bean.BulkUpdatePayload) error {
+	if len(job.ImpactedObjects) == 0 {
+		return nil
+	}
+	authorised := &bean.ImpactedObjectsResponse{}
+	if err := json.Unmarshal([]byte(job.ImpactedObjects), authorised); err != nil {
+		impl.logger.Errorw("error in unmarshalling impacted objects of bulk edit job", "id", job.Id, "err", err)
+		return fmt.Errorf("invalid impacted objects of the job")
+	}
+	impacted, err := impl.bulkUpdateService.DryRunBulkEdit(payload)
+	if err != nil {
+		impl.logger.Errorw("error in fetching impacted objects of bulk edit job", "id", job.Id, "err", err)
+		return fmt.Errorf("error in fetching impacted objects: %s", err.Error())
+	}
+	authorisedKeys := getImpactedObjectKeys(authorised)
+	for key := range getImpactedObjectKeys(impacted) {
+		if !authorisedKeys[key] {
+			return fmt.Errorf("objects impacted by the job have changed since it was created, please create the job again")
+		}
+	}
+	return nil
+}
+
+func getImpactedObjectKeys(impacted *bean.ImpactedObjectsResponse) map[string]bool {
+	keys := make(map[string]bool)
+	for _, object := range impacted.DeploymentTemplate {
+		keys[fmt.Sprintf("%s-%d-%d", bean.BulkEditObjectDeploymentTemplate, object.AppId, object.EnvId)] = true
+	}
+	for _, object := range impacted.ConfigMap {
+		keys[fmt.Sprintf("%s-%d-%d", bean.BulkEditObjectConfigMap, object.AppId, object.EnvId)] = true
+	}
+	for _, object := range impacted.Secret {
+		keys[fmt.Sprintf("%s-%d-%d", bean.BulkEditObjectSecret, object.AppId, object.EnvId)] = true
+	}
+	return keys
+}
+
+// snapshotObjects fetches the current data of the deployment templates, config maps and secrets matching the payload
+func (impl *BulkEditJobServiceImpl) snapshotObjects(jobId int, payload *bean.BulkUpdatePayload) ([]*repository.BulkEditJobObject, error) {
+	objects := make([]*repository.BulkEditJobObject, 0)
+	if payload.Includes == nil || len(payload.Includes.Names) == 0 {
+		// bulk edit rejects such payloads
+		return objects, nil
+	}
+	appNameIncludes := payload.Includes.Names
+	var appNameExcludes []string
+	if payload.Excludes != nil {
+		appNameExcludes = payload.Excludes.Names
+	}
+	newObject := func(objectType bean.BulkEditObjectType, objectId, appId, envId int, data string) *repository.BulkEditJobObject {
+		return &repository.BulkEditJobObject{
+			JobId:        jobId,
+			ObjectType:   objectType.String(),
+			ObjectId:     objectId,
+			AppId:        appId,
+			EnvId:        envId,
+			PreviousData: data,
+			Status:       bean.BulkEditObjectUnchanged.String(),
+			AuditLog:     sql.NewDefaultAuditLog(1),
+		}
+	}
+
+	if payload.DeploymentTemplate != nil && payload.DeploymentTemplate.Spec != nil && len(payload.DeploymentTemplate.Spec.PatchJson) > 0 {
+		if payload.Global {
+			charts, err := impl.bulkEditRepository.FindBulkChartsByAppNameSubstring(appNameIncludes, appNameExcludes)
+			if err != nil {
+				return nil, err
+			}
+			for _, chart := range charts {
+				data, err := getChartValuesSnapshot(chart)
+				if err != nil {
+					return nil, err
+				}
+				objects = append(objects, newObject(bean.BulkEditObjectDeploymentTemplate, chart.Id, chart.AppId, 0, data))
+			}
+		}
+		for _, envId := range payload.EnvIds {
+			envOverrides, err := impl.bulkEditRepository.FindBulkChartsEnvByAppNameSubstring(appNameIncludes, appNameExcludes, envId)
+			if err != nil {
+				return nil, err
+			}
+			for _, envOverride := range envOverrides {
+				objects = append(objects, newObject(bean.BulkEditObjectDeploymentTemplate, envOverride.Id, envOverride.Chart.AppId, envId, envOverride.EnvOverrideValues))
+			}
+		}
+	}
+	for _, cmAndSecret := range []struct {
+		objectType bean.BulkEditObjectType
+		task       *bean.CmAndSecretTask
+	}{
+		{objectType: bean.BulkEditObjectConfigMap, task: payload.ConfigMap},
+		{objectType: bean.BulkEditObjectSecret, task: payload.Secret},
+	} {
+		if cmAndSecret.task == nil || cmAndSecret.task.Spec == nil || len(cmAndSecret.task.Spec.Names) == 0 || len(cmAndSecret.task.Spec.PatchJson) == 0 {
+			continue
+		}
+		isSecret := cmAndSecret.objectType == bean.BulkEditObjectSecret
+		if payload.Global {
+			var appModels []*chartConfig.ConfigMapAppModel
+			var err error
+			if isSecret {
+				appModels, err = impl.bulkEditRepository.FindSecretBulkAppModelForGlobal(appNameIncludes, appNameExcludes, cmAndSecret.task.Spec.Names)
+			} else {
+				appModels, err = impl.bulkEditRepository.FindCMBulkAppModelForGlobal(appNameIncludes, appNameExcludes, cmAndSecret.task.Spec.Names)
+			}
+			if err != nil {
+				return nil, err
+			}
+			for _, appModel := range appModels {
+				objects = append(objects, newObject(cmAndSecret.objectType, appModel.Id, appModel.AppId, 0, getCmAndSecretData(appModel.ConfigMapData, appModel.SecretData, isSecret)))
+			}
+		}
+		for _, envId := range payload.EnvIds {
+			var envModels []*chartConfig.ConfigMapEnvModel
+			var err error
+			if isSecret {
+				envModels, err = impl.bulkEditRepository.FindSecretBulkAppModelForEnv(appNameIncludes, appNameExcludes, envId, cmAndSecret.task.Spec.Names)
+			} else {
+				envModels, err = impl.bulkEditRepository.FindCMBulkAppModelForEnv(appNameIncludes, appNameExcludes, envId, cmAndSecret.task.Spec.Names)
+			}
+			if err != nil {
+				return nil, err
+			}
+			for _, envModel := range envModels {
+				objects = append(objects, newObject(cmAndSecret.objectType, envModel.Id, envModel.AppId, envId, getCmAndSecretData(envModel.ConfigMapData, envModel.SecretData, isSecret)))
+			}
+		}
+	}
+	if err := impl.setHistoryIds(objects); err != nil {
+		return nil, err
+	}
+	return objects, impl.setAppNames(objects)
+}
+
+// setHistoryIds records the latest history entry of the objects, it is the version restored on rollback
+func (impl *BulkEditJobServiceImpl) setHistoryIds(objects []*repository.BulkEditJobObject) error {
+	for _, object := range objects {
+		if object.ObjectType == bean.BulkEditObjectDeploymentTemplate.String() {
+			history, err := impl.deploymentTemplateHistoryReadService.GetLatestTemplateHistoryModel(object.AppId, object.EnvId)
+			if err != nil && !util.IsErrNoRows(err) {
+				return err
+			} else if err == nil {
+				object.HistoryId = history.Id
+			}
+			continue
+		}
+		history, err := impl.configMapHistoryReadService.GetLatestHistoryModel(object.AppId, object.EnvId, getConfigType(object))
+		if err != nil && !util.IsErrNoRows(err) {
+			return err
+		} else if err == nil {
+			object.HistoryId = history.Id
+		}
+	}
+	return nil
+}
+
+func getConfigType(object *repository.BulkEditJobObject) historyRepository.ConfigType {
+	if object.ObjectType == bean.BulkEditObjectSecret.String() {
+		return historyRepository.SECRET_TYPE
+	}
+	return historyRepository.CONFIGMAP_TYPE
+}
+
+func (impl *BulkEditJobServiceImpl) setAppNames(objects []*repository.BulkEditJobObject) error {
+	if len(objects) == 0 {
+		return nil
+	}
+	var appIds []int
+	seenAppIds := make(map[int]bool)
+	for _, object := range objects {
+		if !seenAppIds[object.AppId] {
+			seenAppIds[object.AppId] = true
+			appIds = append(appIds, object.AppId)
+		}
+	}
+	apps, err := impl.appRepository.FindAppAndProjectByIdsIn(appIds)
+	if err != nil {
+		return err
+	}
+	appNames := make(map[int]string, len(apps))
+	for _, app := range apps {
+		appNames[app.Id] = app.AppName
+	}
+	for _, object := range objects {
+		object.AppName = appNames[object.AppId]
+	}
+	return nil
+}
+
+func getChartValuesSnapshot(chart *chartRepoRepository.Chart) (string, error) {
+	data, err := json.Marshal(&chartValuesSnapshot{Values: chart.Values, GlobalOverride: chart.GlobalOverride})
+	return string(data), err
+}
+
+func getCmAndSecretData(configMapData, secretData string, isSecret bool) string {
+	if isSecret {
+		return secretData
+	}
+	return configMapData
+}
+
+// getObjectData returns the current data of the object in the same format as its snapshot
+func (impl *BulkEditJobServiceImpl) getObjectData(object *repository.BulkEditJobObject) (string, error) {
+	isSecret := object.ObjectType == bean.BulkEditObjectSecret.String()
+	switch {
+	case object.ObjectType == bean.BulkEditObjectDeploymentTemplate.String() && object.EnvId == 0:
+		chart, err := impl.chartRepository.FindById(object.ObjectId)
+		if err != nil {
+			return "", err
+		}
+		return getChartValuesSnapshot(chart)
+	case object.ObjectType == bean.BulkEditObjectDeploymentTemplate.String():
+		envOverride, err := impl.envConfigOverrideRepository.GetByIdIncludingInactive(object.ObjectId)
+		if err != nil {
+			return "", err
+		}
+		return envOverride.EnvOverrideValues, nil
+	case object.EnvId == 0:
+		appModel, err := impl.configMapRepository.GetByIdAppLevel(object.ObjectId)
+		if err != nil {
+			return "", err
+		}
+		return getCmAndSecretData(appModel.ConfigMapData, appModel.SecretData, isSecret), nil
+	default:
+		envModel, err := impl.configMapRepository.GetByIdEnvLevel(object.ObjectId)
+		if err != nil {
+			return "", err
+		}
+		return getCmAndSecretData(envModel.ConfigMapData, envModel.SecretData, isSecret), nil
+	}
+}
+
+func (impl *BulkEditJobServiceImpl) RollbackJob(ctx context.Context, id int, request *bean.BulkEditJobRollbackRequest) (*bean.BulkEditJobDto, error) {
+	claimed, err := impl.bulkEditJobRepository.UpdateStatus(id, []string{bean.BulkEditJobCompleted.String(), bean.BulkEditJobRollbackFailed.String()},
+		bean.BulkEditJobRollingBack.String(), request.UserId)
+	if err != nil {
+		impl.logger.Errorw("error in marking bulk edit job for rollback", "id", id, "err", err)
+		return nil, err
+	}
+	if !claimed {
+		return nil, util.NewApiError(http.StatusBadRequest, "only completed bulk edit jobs can be rolled back", "bulk edit job is not in completed state")
+	}
+	job, err := impl.bulkEditJobRepository.FindById(id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching bulk edit job", "id", id, "err", err)
+		return nil, err
+	}
+	objects, err := impl.bulkEditJobRepository.FindObjectsByJobId(id)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching bulk edit job objects", "jobId", id, "err", err)
+		impl.finishRollback(job, bean.BulkEditJobRollbackFailed, request.UserId)
+		return nil, err
+	}
+
+	status := bean.BulkEditJobRolledBack
+	for _, object := range objects {
+		switch bean.BulkEditObjectStatus(object.Status) {
+		case bean.BulkEditObjectEdited, bean.BulkEditObjectRollbackFailed:
+		case bean.BulkEditObjectRollbackSkipped:
+			if !request.Force {
+				status = bean.BulkEditJobRollbackFailed
+				continue
+			}
+		default:
+			continue
+		}
+		impl.rollbackObject(object, request)
+		if object.Status != bean.BulkEditObjectRolledBack.String() {
+			status = bean.BulkEditJobRollbackFailed
+		}
+		object.UpdatedOn = time.Now()
+		object.UpdatedBy = request.UserId
+		if err := impl.bulkEditJobRepository.UpdateObject(object); err != nil {
+			impl.logger.Errorw("error in updating bulk edit job object", "id", object.Id, "err", err)
+		}
+	}
+	impl.finishRollback(job, status, request.UserId)
+	return adapter.GetBulkEditJobDto(job, objects), nil
+}
+
+func (impl *BulkEditJobServiceImpl) finishRollback(job *repository.BulkEditJob, status bean.BulkEditJobStatus, userId int32) {
+	now := time.Now()
+	job.Status = status.String()
+	job.RolledBackBy = userId
+	job.RolledBackOn = &now
+	job.UpdatedOn = now
+	job.UpdatedBy = userId
+	if err := impl.bulkEditJobRepository.Update(job); err != nil {
+		impl.logger.Errorw("error in updating bulk edit job", "id", job.Id, "status", status, "err", err)
+	}
+}
+
+// rollbackObject restores the object to its data before the job and records the restored version in the history,
+// objects modified after the job are skipped unless the rollback is forced
+func (impl *BulkEditJobServiceImpl) rollbackObject(object *repository.BulkEditJobObject, request *bean.BulkEditJobRollbackRequest) {
+	currentData, err := impl.getObjectData(object)
+	if err != nil {
+		impl.logger.Errorw("error in fetching bulk edit object for rollback", "objectType", object.ObjectType, "objectId", object.ObjectId, "err", err)
+		object.Status = bean.BulkEditObjectRollbackFailed.String()
+		object.Message = fmt.Sprintf("unable to fetch the object: %s", err.Error())
+		return
+	}
+	if currentData != object.EditedData && !request.Force {
+		object.Status = bean.BulkEditObjectRollbackSkipped.String()
+		object.Message = "object was modified after the bulk edit, rollback with force to overwrite the changes"
+		return
+	}
+	if err = impl.restoreObject(object, request.UserId); err != nil {
+		impl.logger.Errorw("error in rolling back bulk edit object", "objectType", object.ObjectType, "objectId", object.ObjectId, "err", err)
+		object.Status = bean.BulkEditObjectRollbackFailed.String()
+		object.Message = fmt.Sprintf("error in rollback: %s", err.Error())
+		return
+	}
+	object.Status = bean.BulkEditObjectRolledBack.String()
+	object.Message = ""
+}
+
+// restoreObject restores the version of the object recorded in its history before the job
+func (impl *BulkEditJobServiceImpl) restoreObject(object *repository.BulkEditJobObject, userId int32) error {
+	if object.HistoryId == 0 {
+		return fmt.Errorf("no history found for the version before the bulk edit")
+	}
+	if object.ObjectType == bean.BulkEditObjectDeploymentTemplate.String() {
+		history, err := impl.deploymentTemplateHistoryReadService.GetTemplateHistoryModelById(object.HistoryId)
+		if err != nil {
+			return err
+		}
+		if object.EnvId == 0 {
+			return impl.restoreChart(object, history.Template, userId)
+		}
+		return impl.restoreEnvOverride(object, history.Template, userId)
+	}
+	history, err := impl.configMapHistoryReadService.GetHistoryModelById(object.HistoryId)
+	if err != nil {
+		return err
+	}
+	if object.EnvId == 0 {
+		return impl.restoreAppLevelConfig(object, history.Data)
+	}
+	return impl.restoreEnvLevelConfig(object, history.Data)
+}
+
+func (impl *BulkEditJobServiceImpl) restoreChart(object *repository.BulkEditJobObject, globalOverride string, userId int32) error {
+	// history only has the override, the values yaml is restored from the snapshot taken by the job
+	snapshot := &chartValuesSnapshot{}
+	if err := json.Unmarshal([]byte(object.PreviousData), snapshot); err != nil {
+		return err
+	}
+	if err := impl.bulkEditRepository.BulkUpdateChartsValuesYamlAndGlobalOverrideById(object.ObjectId, snapshot.Values, globalOverride); err != nil {
+		return err
+	}
+	chart, err := impl.chartRepository.FindById(object.ObjectId)
+	if err != nil {
+		return err
+	}
+	isAppMetricsEnabled, err := impl.deployedAppMetricsService.GetMetricsFlagByAppId(chart.AppId)
+	if err != nil {
+		return err
+	}
+	if err = impl.deploymentTemplateHistoryService.CreateDeploymentTemplateHistoryFromGlobalTemplate(chart, nil, isAppMetricsEnabled); err != nil {
+		impl.logger.Errorw("error in creating entry for deployment template history", "chartId", chart.Id, "err", err)
+	}
+	return impl.scopedVariableManager.ExtractAndMapVariables(chart.GlobalOverride, chart.Id, variablesRepository.EntityTypeDeploymentTemplateAppLevel, userId, nil)
+}
+
+func (impl *BulkEditJobServiceImpl) restoreEnvOverride(object *repository.BulkEditJobObject, envOverrideValues string, userId int32) error {
+	if err := impl.bulkEditRepository.BulkUpdateChartsEnvYamlOverrideById(object.ObjectId, envOverrideValues); err != nil {
+		return err
+	}
+	envOverride, err := impl.envConfigOverrideRepository.GetByIdIncludingInactive(object.ObjectId)
+	if err != nil {
+		return err
+	}
+	isAppMetricsEnabled, err := impl.deployedAppMetricsService.GetMetricsFlagForAPipelineByAppIdAndEnvId(envOverride.Chart.AppId, envOverride.TargetEnvironment)
+	if err != nil {
+		return err
+	}
+	if err = impl.deploymentTemplateHistoryService.CreateDeploymentTemplateHistoryFromEnvOverrideTemplate(dtAdapter.EnvOverrideDBToDTO(envOverride), nil, isAppMetricsEnabled, 0); err != nil {
+		impl.logger.Errorw("error in creating entry for env deployment template history", "envOverrideId", envOverride.Id, "err", err)
+	}
+	return impl.scopedVariableManager.ExtractAndMapVariables(envOverride.EnvOverrideValues, envOverride.Id, variablesRepository.EntityTypeDeploymentTemplateEnvLevel, userId, nil)
+}
+
+func (impl *BulkEditJobServiceImpl) restoreAppLevelConfig(object *repository.BulkEditJobObject, historyData string) error {
+	isSecret := object.ObjectType == bean.BulkEditObjectSecret.String()
+	appModel, err := impl.configMapRepository.GetByIdAppLevel(object.ObjectId)
+	if err != nil {
+		return err
+	}
+	data, err := restoreConfigData(getCmAndSecretData(appModel.ConfigMapData, appModel.SecretData, isSecret), historyData, isSecret)
+	if err != nil {
+		return err
+	}
+	if isSecret {
+		err = impl.bulkEditRepository.BulkUpdateSecretDataForGlobalById(object.ObjectId, data)
+	} else {
+		err = impl.bulkEditRepository.BulkUpdateConfigMapDataForGlobalById(object.ObjectId, data)
+	}
+	if err != nil {
+		return err
+	}
+	appModel, err = impl.configMapRepository.GetByIdAppLevel(object.ObjectId)
+	if err != nil {
+		return err
+	}
+	if err = impl.configMapHistoryService.CreateHistoryFromAppLevelConfig(appModel, getConfigType(object)); err != nil {
+		impl.logger.Errorw("error in creating entry for app level config history", "id", appModel.Id, "configType", getConfigType(object), "err", err)
+	}
+	return nil
+}
+
+func (impl *BulkEditJobServiceImpl) restoreEnvLevelConfig(object *repository.BulkEditJobObject, historyData string) error {
+	isSecret := object.ObjectType == bean.BulkEditObjectSecret.String()
+	envModel, err := impl.configMapRepository.GetByIdEnvLevel(object.ObjectId)
+	if err != nil {
+		return err
+	}
+	data, err := restoreConfigData(getCmAndSecretData(envModel.ConfigMapData, envModel.SecretData, isSecret), historyData, isSecret)
+	if err != nil {
+		return err
+	}
+	if isSecret {
+		err = impl.bulkEditRepository.BulkUpdateSecretDataForEnvById(object.ObjectId, data)
+	} else {
+		err = impl.bulkEditRepository.BulkUpdateConfigMapDataForEnvById(object.ObjectId, data)
+	}
+	if err != nil {
+		return err
+	}
+	envModel, err = impl.configMapRepository.GetByIdEnvLevel(object.ObjectId)
+	if err != nil {
+		return err
+	}
+	if err = impl.configMapHistoryService.CreateHistoryFromEnvLevelConfig(envModel, getConfigType(object)); err != nil {
+		impl.logger.Errorw("error in creating entry for env level config history", "id", envModel.Id, "configType", getConfigType(object), "err", err)
+	}
+	return nil
+}
+
+// restoreConfigData replaces the configs in data with their version in the history. The history of the environment
+// has the app and env level configs merged, only the configs present in data are taken from it.
+func restoreConfigData(data, historyData string, isSecret bool) (string, error) {
+	listKey := "maps"
+	if isSecret {
+		listKey = "secrets"
+	}
+	historyConfigs := make(map[string]string)
+	for _, historyConfig := range gjson.Get(historyData, listKey).Array() {
+		historyConfigs[historyConfig.Get("name").String()] = historyConfig.Raw
+	}
+	var err error
+	for i, config := range gjson.Get(data, listKey).Array() {
+		historyConfig, ok := historyConfigs[config.Get("name").String()]
+		if !ok {
+			continue
+		}
+		data, err = sjson.SetRaw(data, fmt.Sprintf("%s.%d", listKey, i), historyConfig)
+		if err != nil {
+			return "", err
+		}
+	}
+	return data, nil
+}
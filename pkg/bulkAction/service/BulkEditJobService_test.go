@@ -0,0 +1,500 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/devtron-labs/devtron/internal/sql/repository/app"
+	"github.com/devtron-labs/devtron/internal/sql/repository/helper"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	userBean "github.com/devtron-labs/devtron/pkg/auth/user/bean"
+	"github.com/devtron-labs/devtron/pkg/bulkAction/bean"
+	"github.com/devtron-labs/devtron/pkg/bulkAction/repository"
+	chartRepoRepository "github.com/devtron-labs/devtron/pkg/chartRepo/repository"
+	"github.com/devtron-labs/devtron/pkg/deployment/manifest/deployedAppMetrics"
+	"github.com/devtron-labs/devtron/pkg/deployment/manifest/deploymentTemplate"
+	dtRead "github.com/devtron-labs/devtron/pkg/deployment/manifest/deploymentTemplate/read"
+	historyRepository "github.com/devtron-labs/devtron/pkg/pipeline/history/repository"
+	"github.com/devtron-labs/devtron/pkg/variables"
+	variablesRepository "github.com/devtron-labs/devtron/pkg/variables/repository"
+	"github.com/devtron-labs/devtron/util/rbac"
+	"github.com/go-pg/pg"
+	"github.com/stretchr/testify/assert"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+type bulkEditJobRepositoryStub struct {
+	repository.BulkEditJobRepository
+	job            *repository.BulkEditJob
+	objects        []*repository.BulkEditJobObject
+	updatedObjects []*repository.BulkEditJobObject
+}
+
+func (stub *bulkEditJobRepositoryStub) Save(job *repository.BulkEditJob) error {
+	job.Id = 1
+	stub.job = job
+	return nil
+}
+
+func (stub *bulkEditJobRepositoryStub) Update(job *repository.BulkEditJob) error {
+	stub.job = job
+	return nil
+}
+
+func (stub *bulkEditJobRepositoryStub) FindById(id int) (*repository.BulkEditJob, error) {
+	return stub.job, nil
+}
+
+func (stub *bulkEditJobRepositoryStub) UpdateStatus(id int, fromStatuses []string, status string, userId int32) (bool, error) {
+	for _, fromStatus := range fromStatuses {
+		if stub.job.Status == fromStatus {
+			stub.job.Status = status
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (stub *bulkEditJobRepositoryStub) SaveObjects(objects []*repository.BulkEditJobObject) error {
+	stub.objects = objects
+	return nil
+}
+
+func (stub *bulkEditJobRepositoryStub) UpdateObject(object *repository.BulkEditJobObject) error {
+	stub.updatedObjects = append(stub.updatedObjects, object)
+	return nil
+}
+
+func (stub *bulkEditJobRepositoryStub) FindObjectsByJobId(jobId int) ([]*repository.BulkEditJobObject, error) {
+	return stub.objects, nil
+}
+
+// chartStore is the base deployment templates shared by the repository stubs
+type chartStore map[int]*chartRepoRepository.Chart
+
+type bulkEditRepositoryStub struct {
+	repository.BulkEditRepository
+	charts chartStore
+}
+
+func (stub *bulkEditRepositoryStub) FindBulkChartsByAppNameSubstring(appNameIncludes []string, appNameExcludes []string) ([]*chartRepoRepository.Chart, error) {
+	charts := make([]*chartRepoRepository.Chart, 0, len(stub.charts))
+	for _, chart := range stub.charts {
+		copied := *chart
+		charts = append(charts, &copied)
+	}
+	sort.Slice(charts, func(i, j int) bool { return charts[i].Id < charts[j].Id })
+	return charts, nil
+}
+
+func (stub *bulkEditRepositoryStub) BulkUpdateChartsValuesYamlAndGlobalOverrideById(id int, patchValuesYml string, patchGlobalOverrideYml string) error {
+	stub.charts[id].Values = patchValuesYml
+	stub.charts[id].GlobalOverride = patchGlobalOverrideYml
+	return nil
+}
+
+type chartRepositoryStub struct {
+	chartRepoRepository.ChartRepository
+	charts chartStore
+}
+
+func (stub *chartRepositoryStub) FindById(id int) (*chartRepoRepository.Chart, error) {
+	chart, ok := stub.charts[id]
+	if !ok {
+		return nil, pg.ErrNoRows
+	}
+	copied := *chart
+	return &copied, nil
+}
+
+// bulkUpdateServiceStub edits the base deployment template of the apps not excluded by the payload,
+// except the ones in failedAppIds
+type bulkUpdateServiceStub struct {
+	BulkUpdateService
+	charts       chartStore
+	appNames     map[int]string
+	failedAppIds map[int]bool
+	payloads     []*bean.BulkUpdatePayload
+}
+
+func (stub *bulkUpdateServiceStub) BulkEdit(ctx context.Context, payload *bean.BulkUpdatePayload, userMetadata *userBean.UserMetadata) *bean.BulkUpdateResponse {
+	stub.payloads = append(stub.payloads, payload)
+	excluded := make(map[string]bool)
+	if payload.Excludes != nil {
+		for _, name := range payload.Excludes.Names {
+			excluded[name] = true
+		}
+	}
+	response := &bean.DeploymentTemplateBulkUpdateResponse{}
+	for _, chart := range stub.charts {
+		appName := stub.appNames[chart.AppId]
+		if excluded[appName] {
+			continue
+		}
+		appResponse := &bean.DeploymentTemplateBulkUpdateResponseForOneApp{AppId: chart.AppId, AppName: appName}
+		if stub.failedAppIds[chart.AppId] {
+			response.Failure = append(response.Failure, appResponse)
+			continue
+		}
+		chart.GlobalOverride = `{"replicaCount":2}`
+		response.Successful = append(response.Successful, appResponse)
+	}
+	return &bean.BulkUpdateResponse{DeploymentTemplate: response}
+}
+
+type appRepositoryStub struct {
+	app.AppRepository
+	appNames map[int]string
+}
+
+func (stub *appRepositoryStub) FindAppAndProjectByIdsIn(ids []int) ([]*app.App, error) {
+	apps := make([]*app.App, 0, len(ids))
+	for _, id := range ids {
+		apps = append(apps, &app.App{Id: id, AppName: stub.appNames[id]})
+	}
+	return apps, nil
+}
+
+// deploymentTemplateHistoryReadServiceStub has the history entry 100+appId as the latest base template of the app
+type deploymentTemplateHistoryReadServiceStub struct {
+	dtRead.DeploymentTemplateHistoryReadService
+	templates map[int]string
+}
+
+func (stub *deploymentTemplateHistoryReadServiceStub) GetLatestTemplateHistoryModel(appId, envId int) (*historyRepository.DeploymentTemplateHistory, error) {
+	return &historyRepository.DeploymentTemplateHistory{Id: 100 + appId, AppId: appId}, nil
+}
+
+func (stub *deploymentTemplateHistoryReadServiceStub) GetTemplateHistoryModelById(id int) (*historyRepository.DeploymentTemplateHistory, error) {
+	template, ok := stub.templates[id]
+	if !ok {
+		return nil, pg.ErrNoRows
+	}
+	return &historyRepository.DeploymentTemplateHistory{Id: id, Template: template}, nil
+}
+
+type deploymentTemplateHistoryServiceStub struct {
+	deploymentTemplate.DeploymentTemplateHistoryService
+}
+
+func (stub *deploymentTemplateHistoryServiceStub) CreateDeploymentTemplateHistoryFromGlobalTemplate(chart *chartRepoRepository.Chart, tx *pg.Tx, IsAppMetricsEnabled bool) error {
+	return nil
+}
+
+type deployedAppMetricsServiceStub struct {
+	deployedAppMetrics.DeployedAppMetricsService
+}
+
+func (stub *deployedAppMetricsServiceStub) GetMetricsFlagByAppId(appId int) (bool, error) {
+	return false, nil
+}
+
+type scopedVariableManagerStub struct {
+	variables.ScopedVariableManager
+}
+
+func (stub *scopedVariableManagerStub) ExtractAndMapVariables(template string, entityId int, entityType variablesRepository.EntityType, userId int32, tx *pg.Tx) error {
+	return nil
+}
+
+// enforcerStub allows the actions on the resource items in allowed, keyed by resource/item
+type enforcerStub struct {
+	casbin.Enforcer
+	allowed map[string]bool
+}
+
+func (stub *enforcerStub) EnforceByEmail(emailId string, resource string, action string, resourceItem string) bool {
+	return stub.allowed[resource+"/"+resourceItem]
+}
+
+type enforcerUtilStub struct {
+	rbac.EnforcerUtil
+	appNames map[int]string
+}
+
+func (stub *enforcerUtilStub) GetRbacObjectsForAllApps(appType helper.AppType) map[int]string {
+	rbacObjects := make(map[int]string, len(stub.appNames))
+	for appId, appName := range stub.appNames {
+		rbacObjects[appId] = "team/" + appName
+	}
+	return rbacObjects
+}
+
+type userServiceStub struct {
+	user.UserService
+	inactive bool
+}
+
+func (stub *userServiceStub) GetActiveEmailById(userId int32) (string, error) {
+	if stub.inactive {
+		return "", pg.ErrNoRows
+	}
+	return "user@example.com", nil
+}
+
+type bulkEditJobServiceFixture struct {
+	service           *BulkEditJobServiceImpl
+	jobRepository     *bulkEditJobRepositoryStub
+	bulkUpdateService *bulkUpdateServiceStub
+	charts            chartStore
+}
+
+// newBulkEditJobServiceFixture has the base deployment templates of app-1 and app-2, the user can edit only the apps
+// in editableAppIds
+func newBulkEditJobServiceFixture(t *testing.T, editableAppIds ...int) *bulkEditJobServiceFixture {
+	logger, err := util.NewSugardLogger()
+	assert.Nil(t, err)
+	appNames := map[int]string{1: "app-1", 2: "app-2"}
+	charts := chartStore{
+		11: {Id: 11, AppId: 1, Values: `{"replicaCount":1}`, GlobalOverride: `{"replicaCount":1}`},
+		12: {Id: 12, AppId: 2, Values: `{"replicaCount":1}`, GlobalOverride: `{"replicaCount":1}`},
+	}
+	allowed := make(map[string]bool)
+	for _, appId := range editableAppIds {
+		allowed[casbin.ResourceApplications+"/team/"+appNames[appId]] = true
+	}
+	fixture := &bulkEditJobServiceFixture{
+		jobRepository:     &bulkEditJobRepositoryStub{},
+		bulkUpdateService: &bulkUpdateServiceStub{charts: charts, appNames: appNames, failedAppIds: map[int]bool{}},
+		charts:            charts,
+	}
+	fixture.service = &BulkEditJobServiceImpl{
+		logger:                           logger,
+		bulkEditJobRepository:            fixture.jobRepository,
+		bulkEditRepository:               &bulkEditRepositoryStub{charts: charts},
+		bulkUpdateService:                fixture.bulkUpdateService,
+		appRepository:                    &appRepositoryStub{appNames: appNames},
+		chartRepository:                  &chartRepositoryStub{charts: charts},
+		deploymentTemplateHistoryService: &deploymentTemplateHistoryServiceStub{},
+		deploymentTemplateHistoryReadService: &deploymentTemplateHistoryReadServiceStub{
+			templates: map[int]string{101: `{"replicaCount":1}`, 102: `{"replicaCount":1}`},
+		},
+		deployedAppMetricsService: &deployedAppMetricsServiceStub{},
+		scopedVariableManager:     &scopedVariableManagerStub{},
+		enforcer:                  &enforcerStub{allowed: allowed},
+		enforcerUtil:              &enforcerUtilStub{appNames: appNames},
+		userService:               &userServiceStub{},
+	}
+	return fixture
+}
+
+func newBulkEditJobRequest(scheduledAt *time.Time) *bean.BulkEditJobRequest {
+	return &bean.BulkEditJobRequest{
+		Script: &bean.BulkUpdateScript{
+			Spec: &bean.BulkUpdatePayload{
+				Includes: &bean.NameIncludesExcludes{Names: []string{"app-%"}},
+				Global:   true,
+				DeploymentTemplate: &bean.DeploymentTemplateTask{
+					Spec: &bean.DeploymentTemplateSpec{PatchJson: `[{"op":"replace","path":"/replicaCount","value":2}]`},
+				},
+			},
+		},
+		ScheduledAt:  scheduledAt,
+		UserMetadata: &userBean.UserMetadata{UserId: 2, UserEmailId: "user@example.com"},
+	}
+}
+
+func getObjectStatuses(objects []*repository.BulkEditJobObject) map[int]string {
+	statuses := make(map[int]string, len(objects))
+	for _, object := range objects {
+		statuses[object.AppId] = object.Status
+	}
+	return statuses
+}
+
+func TestCreateJob(t *testing.T) {
+	t.Run("scheduled job is not executed", func(t *testing.T) {
+		fixture := newBulkEditJobServiceFixture(t, 1, 2)
+		scheduledAt := time.Now().Add(time.Hour)
+		job, err := fixture.service.CreateJob(context.Background(), newBulkEditJobRequest(&scheduledAt))
+		assert.Nil(t, err)
+		assert.Equal(t, bean.BulkEditJobScheduled, job.Status)
+		assert.Nil(t, job.StartedOn)
+		assert.Empty(t, fixture.bulkUpdateService.payloads)
+		assert.Equal(t, `{"replicaCount":1}`, fixture.charts[11].GlobalOverride)
+	})
+
+	t.Run("job is executed immediately", func(t *testing.T) {
+		fixture := newBulkEditJobServiceFixture(t, 1, 2)
+		job, err := fixture.service.CreateJob(context.Background(), newBulkEditJobRequest(nil))
+		assert.Nil(t, err)
+		assert.Equal(t, bean.BulkEditJobCompleted, job.Status)
+		assert.Len(t, fixture.bulkUpdateService.payloads, 1)
+		assert.Equal(t, map[int]string{1: bean.BulkEditObjectEdited.String(), 2: bean.BulkEditObjectEdited.String()}, getObjectStatuses(fixture.jobRepository.objects))
+		for _, object := range fixture.jobRepository.objects {
+			assert.Equal(t, 100+object.AppId, object.HistoryId)
+		}
+	})
+}
+
+func TestExecuteJob(t *testing.T) {
+	t.Run("objects the user cannot edit anymore are skipped", func(t *testing.T) {
+		fixture := newBulkEditJobServiceFixture(t, 1)
+		job, err := fixture.service.CreateJob(context.Background(), newBulkEditJobRequest(nil))
+		assert.Nil(t, err)
+		assert.Equal(t, bean.BulkEditJobCompleted, job.Status)
+		assert.Len(t, fixture.bulkUpdateService.payloads, 1)
+		assert.Equal(t, []string{"app-2"}, fixture.bulkUpdateService.payloads[0].Excludes.Names)
+		assert.Equal(t, map[int]string{1: bean.BulkEditObjectEdited.String(), 2: bean.BulkEditObjectSkipped.String()}, getObjectStatuses(fixture.jobRepository.objects))
+		assert.Equal(t, `{"replicaCount":2}`, fixture.charts[11].GlobalOverride)
+		assert.Equal(t, `{"replicaCount":1}`, fixture.charts[12].GlobalOverride)
+	})
+
+	t.Run("objects failed by the patch are left unchanged", func(t *testing.T) {
+		fixture := newBulkEditJobServiceFixture(t, 1, 2)
+		fixture.bulkUpdateService.failedAppIds[2] = true
+		job, err := fixture.service.CreateJob(context.Background(), newBulkEditJobRequest(nil))
+		assert.Nil(t, err)
+		assert.Equal(t, bean.BulkEditJobCompleted, job.Status)
+		assert.Equal(t, map[int]string{1: bean.BulkEditObjectEdited.String(), 2: bean.BulkEditObjectUnchanged.String()}, getObjectStatuses(fixture.jobRepository.objects))
+		assert.Len(t, job.Response.DeploymentTemplate.Failure, 1)
+		assert.Len(t, job.Response.DeploymentTemplate.Successful, 1)
+	})
+
+	t.Run("job is failed if the user is not active anymore", func(t *testing.T) {
+		fixture := newBulkEditJobServiceFixture(t, 1, 2)
+		fixture.service.userService = &userServiceStub{inactive: true}
+		job, err := fixture.service.CreateJob(context.Background(), newBulkEditJobRequest(nil))
+		assert.Nil(t, err)
+		assert.Equal(t, bean.BulkEditJobFailed, job.Status)
+		assert.Empty(t, fixture.bulkUpdateService.payloads)
+		assert.Equal(t, `{"replicaCount":1}`, fixture.charts[11].GlobalOverride)
+	})
+}
+
+func TestRollbackJob(t *testing.T) {
+	tests := []struct {
+		name           string
+		force          bool
+		expectedStatus bean.BulkEditJobStatus
+		expectedObject map[int]string
+		expectedApp2   string
+	}{
+		{
+			name:           "objects modified after the job are skipped",
+			expectedStatus: bean.BulkEditJobRollbackFailed,
+			expectedObject: map[int]string{1: bean.BulkEditObjectRolledBack.String(), 2: bean.BulkEditObjectRollbackSkipped.String()},
+			expectedApp2:   `{"replicaCount":3}`,
+		},
+		{
+			name:           "forced rollback restores the objects modified after the job",
+			force:          true,
+			expectedStatus: bean.BulkEditJobRolledBack,
+			expectedObject: map[int]string{1: bean.BulkEditObjectRolledBack.String(), 2: bean.BulkEditObjectRolledBack.String()},
+			expectedApp2:   `{"replicaCount":1}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fixture := newBulkEditJobServiceFixture(t, 1, 2)
+			_, err := fixture.service.CreateJob(context.Background(), newBulkEditJobRequest(nil))
+			assert.Nil(t, err)
+			// app-2 is modified again after the job
+			fixture.charts[12].GlobalOverride = `{"replicaCount":3}`
+
+			job, err := fixture.service.RollbackJob(context.Background(), 1, &bean.BulkEditJobRollbackRequest{Force: tt.force, UserId: 2})
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expectedStatus, job.Status)
+			assert.Equal(t, tt.expectedObject, getObjectStatuses(fixture.jobRepository.objects))
+			assert.Equal(t, `{"replicaCount":1}`, fixture.charts[11].GlobalOverride)
+			assert.Equal(t, tt.expectedApp2, fixture.charts[12].GlobalOverride)
+		})
+	}
+
+	t.Run("objects without history are not rolled back", func(t *testing.T) {
+		fixture := newBulkEditJobServiceFixture(t, 1, 2)
+		_, err := fixture.service.CreateJob(context.Background(), newBulkEditJobRequest(nil))
+		assert.Nil(t, err)
+		fixture.jobRepository.objects[0].HistoryId = 0
+
+		job, err := fixture.service.RollbackJob(context.Background(), 1, &bean.BulkEditJobRollbackRequest{UserId: 2})
+		assert.Nil(t, err)
+		assert.Equal(t, bean.BulkEditJobRollbackFailed, job.Status)
+		assert.Equal(t, bean.BulkEditObjectRollbackFailed.String(), fixture.jobRepository.objects[0].Status)
+		assert.Equal(t, `{"replicaCount":2}`, fixture.charts[11].GlobalOverride)
+	})
+
+	t.Run("only completed jobs can be rolled back", func(t *testing.T) {
+		fixture := newBulkEditJobServiceFixture(t, 1, 2)
+		scheduledAt := time.Now().Add(time.Hour)
+		_, err := fixture.service.CreateJob(context.Background(), newBulkEditJobRequest(&scheduledAt))
+		assert.Nil(t, err)
+		_, err = fixture.service.RollbackJob(context.Background(), 1, &bean.BulkEditJobRollbackRequest{UserId: 2})
+		var apiErr *util.ApiError
+		assert.True(t, errors.As(err, &apiErr))
+	})
+}
+
+func TestGetScopedPayload(t *testing.T) {
+	payload := &bean.BulkUpdatePayload{
+		Includes: &bean.NameIncludesExcludes{Names: []string{"%"}},
+		Excludes: &bean.NameIncludesExcludes{Names: []string{"demo-%"}},
+		Global:   true,
+		EnvIds:   []int{1, 2},
+	}
+	scopedPayload := getScopedPayload(payload, false, []int{2}, []string{"my_app%"})
+	assert.False(t, scopedPayload.Global)
+	assert.Equal(t, []int{2}, scopedPayload.EnvIds)
+	assert.Equal(t, []string{"demo-%", `my\_app\%`}, scopedPayload.Excludes.Names)
+	// the payload of the job is left as it is
+	assert.True(t, payload.Global)
+	assert.Equal(t, []string{"demo-%"}, payload.Excludes.Names)
+}
+
+func TestRestoreConfigData(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		historyData string
+		isSecret    bool
+		expected    string
+	}{
+		{
+			name:        "configs are restored from the history",
+			data:        `{"enabled":true,"maps":[{"name":"cm-1","data":{"key":"edited"}},{"name":"cm-2","data":{"key":"value"}}]}`,
+			historyData: `{"maps":[{"name":"cm-1","data":{"key":"value"}},{"name":"cm-2","data":{"key":"value"}}]}`,
+			expected:    `{"enabled":true,"maps":[{"name":"cm-1","data":{"key":"value"}},{"name":"cm-2","data":{"key":"value"}}]}`,
+		},
+		{
+			name:        "app level configs merged in the env history are not picked",
+			data:        `{"secrets":[{"name":"cs-1","data":{"key":"edited"}}]}`,
+			historyData: `{"secrets":[{"name":"cs-1","data":{"key":"value"}},{"name":"cs-global","data":{"key":"value"}}]}`,
+			isSecret:    true,
+			expected:    `{"secrets":[{"name":"cs-1","data":{"key":"value"}}]}`,
+		},
+		{
+			name:        "configs missing in the history are left as they are",
+			data:        `{"maps":[{"name":"cm-1","data":{"key":"edited"}}]}`,
+			historyData: `{"maps":[]}`,
+			expected:    `{"maps":[{"name":"cm-1","data":{"key":"edited"}}]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			restored, err := restoreConfigData(tt.data, tt.historyData, tt.isSecret)
+			assert.Nil(t, err)
+			assert.JSONEq(t, tt.expected, restored)
+			assert.False(t, strings.Contains(restored, "cs-global"))
+		})
+	}
+}
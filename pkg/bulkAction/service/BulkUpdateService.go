@@ -52,6 +52,8 @@ import (
 	"github.com/devtron-labs/devtron/pkg/eventProcessor/out"
 	"github.com/devtron-labs/devtron/pkg/pipeline"
 	repository4 "github.com/devtron-labs/devtron/pkg/pipeline/history/repository"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/deploymentWindow"
+	deploymentWindowBean "github.com/devtron-labs/devtron/pkg/policyGovernance/deploymentWindow/bean"
 	"github.com/devtron-labs/devtron/pkg/variables"
 	repository5 "github.com/devtron-labs/devtron/pkg/variables/repository"
 	util2 "github.com/devtron-labs/devtron/util"
@@ -66,6 +68,7 @@ import (
 	"net/http"
 	"sort"
 	"strings"
+	"time"
 )
 
 type BulkUpdateService interface {
@@ -80,7 +83,10 @@ type BulkUpdateService interface {
 	BulkHibernate(ctx context.Context, request *bean4.BulkApplicationForEnvironmentPayload, checkAuthForBulkActions func(token string, appObject string, envObject string) bool, userMetadata *bean6.UserMetadata) (*bean4.BulkApplicationHibernateUnhibernateForEnvironmentResponse, error)
 	BulkHibernateV1(ctx context.Context, request *bean4.BulkApplicationForEnvironmentPayload, checkAuthForBulkActions func(token string, appObject string, envObject string) bool, userMetadata *bean6.UserMetadata) (*bean4.BulkApplicationHibernateUnhibernateForEnvironmentResponse, error)
 	BulkUnHibernate(ctx context.Context, request *bean4.BulkApplicationForEnvironmentPayload, checkAuthForBulkActions func(token string, appObject string, envObject string) bool, userMetadata *bean6.UserMetadata) (*bean4.BulkApplicationHibernateUnhibernateForEnvironmentResponse, error)
-	BulkDeploy(ctx *util2.RequestCtx, request *bean4.BulkApplicationForEnvironmentPayload, checkAuthBatch func(token string, appObject []string, envObject []string) (map[string]bool, map[string]bool), userMetadata *bean6.UserMetadata) (*bean4.BulkApplicationForEnvironmentResponse, error)
+	// BulkDeploy triggers deployment of the latest artifact on the pipelines of the environment, pipelines blocked by
+	// deployment windows are skipped unless the override is requested and checkDeploymentWindowOverrideAuth allows it
+	BulkDeploy(ctx *util2.RequestCtx, request *bean4.BulkApplicationForEnvironmentPayload, checkAuthBatch func(token string, appObject []string, envObject []string) (map[string]bool, map[string]bool),
+		checkDeploymentWindowOverrideAuth func(token string, envObject []string) map[string]bool, userMetadata *bean6.UserMetadata) (*bean4.BulkApplicationForEnvironmentResponse, error)
 	BulkBuildTrigger(request *bean4.BulkApplicationForEnvironmentPayload, ctx context.Context, w http.ResponseWriter, token string, checkAuthForBulkActions func(token string, appObject string, envObject string) bool) (*bean4.BulkApplicationForEnvironmentResponse, error)
 
 	GetBulkActionImpactedPipelinesAndWfs(dto *bean4.CdBulkActionRequestDto) ([]*pipelineConfig.Pipeline, []int, []int, error)
@@ -109,6 +115,7 @@ type BulkUpdateServiceImpl struct {
 	deployedAppService               deployedApp.DeployedAppService
 	cdPipelineEventPublishService    out.CDPipelineEventPublishService
 	ciHandlerService                 trigger.HandlerService
+	deploymentWindowService          deploymentWindow.DeploymentWindowService
 	*BulkUpdateServiceEntImpl
 }
 
@@ -131,6 +138,7 @@ func NewBulkUpdateServiceImpl(bulkUpdateRepository repository.BulkEditRepository
 	deployedAppService deployedApp.DeployedAppService,
 	cdPipelineEventPublishService out.CDPipelineEventPublishService,
 	ciHandlerService trigger.HandlerService,
+	deploymentWindowService deploymentWindow.DeploymentWindowService,
 	bulkUpdateServiceEntImpl *BulkUpdateServiceEntImpl,
 ) *BulkUpdateServiceImpl {
 	return &BulkUpdateServiceImpl{
@@ -153,6 +161,7 @@ func NewBulkUpdateServiceImpl(bulkUpdateRepository repository.BulkEditRepository
 		deployedAppService:               deployedAppService,
 		cdPipelineEventPublishService:    cdPipelineEventPublishService,
 		ciHandlerService:                 ciHandlerService,
+		deploymentWindowService:          deploymentWindowService,
 		BulkUpdateServiceEntImpl:         bulkUpdateServiceEntImpl,
 	}
 }
@@ -1384,7 +1393,7 @@ func (impl BulkUpdateServiceImpl) BulkUnHibernate(ctx context.Context, request *
 }
 
 func (impl BulkUpdateServiceImpl) BulkDeploy(ctx *util2.RequestCtx, request *bean4.BulkApplicationForEnvironmentPayload, checkAuthBatch func(token string, appObject []string, envObject []string) (map[string]bool, map[string]bool),
-	userMetadata *bean6.UserMetadata) (*bean4.BulkApplicationForEnvironmentResponse, error) {
+	checkDeploymentWindowOverrideAuth func(token string, envObject []string) map[string]bool, userMetadata *bean6.UserMetadata) (*bean4.BulkApplicationForEnvironmentResponse, error) {
 	var pipelines []*pipelineConfig.Pipeline
 	var err error
 
@@ -1452,9 +1461,14 @@ func (impl BulkUpdateServiceImpl) BulkDeploy(ctx *util2.RequestCtx, request *bea
 		envObjectArr = append(envObjectArr, object[1])
 	}
 	appResults, envResults := checkAuthBatch(util2.GetTokenFromContext(ctx), appObjectArr, envObjectArr)
+	var overrideResults map[string]bool
+	if request.DeploymentWindowOverride {
+		overrideResults = checkDeploymentWindowOverrideAuth(util2.GetTokenFromContext(ctx), envObjectArr)
+	}
 	//authorization block ends here
 
 	response := make(map[string]map[string]bool)
+	blockedByDeploymentWindow := make(map[string]string)
 	for _, pipeline := range pipelines {
 		appKey := utils.GenerateIdentifierKey(pipeline.AppId, pipeline.App.AppName)
 		pipelineKey := utils.GenerateIdentifierKey(pipeline.Id, pipeline.Name)
@@ -1503,7 +1517,27 @@ func (impl BulkUpdateServiceImpl) BulkDeploy(ctx *util2.RequestCtx, request *bea
 			continue
 		}
 		artifact := artifacts[0]
-		err = impl.cdPipelineEventPublishService.PublishBulkTriggerTopicEvent(pipeline.Id, pipeline.AppId, artifact.Id, userMetadata)
+		windowState, err := impl.deploymentWindowService.GetDeploymentWindowState(ctx, &deploymentWindowBean.WindowEvaluationScope{AppId: pipeline.AppId, EnvId: pipeline.EnvironmentId}, time.Now())
+		if err != nil {
+			impl.logger.Errorw("error in evaluating deployment windows", "pipelineId", pipeline.Id, "err", err)
+			pipelineResponse := response[appKey]
+			pipelineResponse[pipelineKey] = false
+			response[appKey] = pipelineResponse
+			continue
+		}
+		deploymentWindowOverrideReason := ""
+		if !windowState.IsDeploymentAllowed {
+			if !overrideResults[envObject] {
+				//deployment window is blocking and user has not requested or is not allowed to override, skip cd trigger
+				blockedByDeploymentWindow[pipelineKey] = windowState.GetBlockedReason()
+				pipelineResponse := response[appKey]
+				pipelineResponse[pipelineKey] = false
+				response[appKey] = pipelineResponse
+				continue
+			}
+			deploymentWindowOverrideReason = request.DeploymentWindowOverrideReason
+		}
+		err = impl.cdPipelineEventPublishService.PublishBulkTriggerTopicEvent(pipeline.Id, pipeline.AppId, artifact.Id, deploymentWindowOverrideReason, userMetadata)
 		if err != nil {
 			impl.logger.Errorw("error, PublishBulkTriggerTopicEvent", "err", err, "pipeline", pipeline)
 			pipelineResponse := response[appKey]
@@ -1518,6 +1552,9 @@ func (impl BulkUpdateServiceImpl) BulkDeploy(ctx *util2.RequestCtx, request *bea
 	bulkOperationResponse := &bean4.BulkApplicationForEnvironmentResponse{}
 	bulkOperationResponse.BulkApplicationForEnvironmentPayload = *request
 	bulkOperationResponse.Response = response
+	if len(blockedByDeploymentWindow) > 0 {
+		bulkOperationResponse.BlockedByDeploymentWindow = blockedByDeploymentWindow
+	}
 	return bulkOperationResponse, nil
 }
 
@@ -164,6 +164,7 @@ func (impl *ClusterServiceImpl) ConvertClusterBeanToCluster(clusterBean *bean.Cl
 	model.PrometheusEndpoint = clusterBean.PrometheusUrl
 	model.InsecureSkipTlsVerify = clusterBean.InsecureSkipTLSVerify
 	model.IsProd = clusterBean.IsProd
+	model.WorkflowExecutorType = clusterBean.WorkflowExecutorType
 
 	if clusterBean.PrometheusAuth != nil {
 		model.PUserName = clusterBean.PrometheusAuth.UserName
@@ -449,6 +450,7 @@ func (impl *ClusterServiceImpl) Update(ctx context.Context, bean *bean.ClusterBe
 	model.ServerUrl = bean.ServerUrl
 	model.InsecureSkipTlsVerify = bean.InsecureSkipTLSVerify
 	model.IsProd = bean.IsProd
+	model.WorkflowExecutorType = bean.WorkflowExecutorType
 	model.PrometheusEndpoint = bean.PrometheusUrl
 
 	if bean.PrometheusAuth != nil {
@@ -38,6 +38,7 @@ type ClusterBean struct {
 	IsVirtualCluster        bool                       `json:"isVirtualCluster"`
 	ClusterUpdated          bool                       `json:"clusterUpdated"`
 	IsProd                  bool                       `json:"isProd"`
+	WorkflowExecutorType    string                     `json:"workflowExecutorType,omitempty" validate:"omitempty,oneof=AWF SYSTEM TEKTON"`
 	ClusterStatus           ClusterStatus              `json:"clusterStatus,omitempty"`
 }
 
@@ -12,6 +12,7 @@ import (
 	"github.com/devtron-labs/devtron/api/bean"
 	apiBean "github.com/devtron-labs/devtron/api/bean/gitOps"
 	"github.com/devtron-labs/devtron/internal/util"
+	bean2 "github.com/devtron-labs/devtron/pkg/deployment/gitOps/git/bean"
 	"github.com/devtron-labs/devtron/pkg/deployment/gitOps/git/commandManager"
 	validationBean "github.com/devtron-labs/devtron/pkg/deployment/gitOps/validation/bean"
 	"github.com/stretchr/testify/assert"
@@ -43,7 +44,7 @@ func newGitHubTestConfig() (*gitHubTestConfig, error) {
 }
 
 func (github *gitHubTestConfig) getProvider() string {
-	return GITHUB_PROVIDER
+	return bean2.GITHUB_PROVIDER
 }
 
 func (github *gitHubTestConfig) getHost() string {
@@ -102,7 +102,7 @@ func (impl *GitOperationServiceImpl) CreateGitRepositoryForDevtronApp(ctx contex
 	gitRepoRequest := &apiBean.GitOpsConfigDto{
 		GitRepoName:          gitOpsRepoName,
 		TargetRevision:       targetRevision,
-		Description:          fmt.Sprintf("helm chart for " + gitOpsRepoName),
+		Description:          fmt.Sprintf("helm chart for %s", gitOpsRepoName),
 		BitBucketWorkspaceId: bitbucketMetadata.BitBucketWorkspaceId,
 		BitBucketProjectKey:  bitbucketMetadata.BitBucketProjectKey,
 	}
@@ -629,6 +629,12 @@ func (impl *GitOperationServiceImpl) UpdateGitHostUrlByProvider(request *apiBean
 		}
 	case bean.BITBUCKET_PROVIDER:
 		request.Host = BITBUCKET_CLONE_BASE_URL + request.BitBucketWorkspaceId
+	case bean.GITEA_PROVIDER:
+		orgUrl, err := buildGithubOrgUrl(request.Host, request.GiteaOrgId)
+		if err != nil {
+			return err
+		}
+		request.Host = orgUrl
 	}
 	return nil
 }
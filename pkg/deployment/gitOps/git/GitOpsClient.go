@@ -59,6 +59,7 @@ func GetGitConfigAll(gitOpsConfigReadService config.GitOpsConfigReadService) ([]
 			AzureProject:          gitOpsConfig.AzureProjectName,
 			BitbucketWorkspaceId:  gitOpsConfig.BitBucketWorkspaceId,
 			BitbucketProjectKey:   gitOpsConfig.BitBucketProjectKey,
+			GiteaOrganization:     gitOpsConfig.GiteaOrgId,
 			IsActiveConfig:        gitOpsConfig.Active,
 			CaCert:                gitOpsConfig.TLSConfig.CaData,
 			TLSCert:               gitOpsConfig.TLSConfig.TLSCertData,
@@ -93,6 +94,9 @@ func NewGitOpsClient(config *bean.GitConfig, logger *zap.SugaredLogger, gitOpsHe
 	} else if config.GitProvider == bean.BITBUCKET_PROVIDER {
 		gitBitbucketClient := NewGitBitbucketClient(config.GitUserName, config.GitToken, config.GitHost, logger, gitOpsHelper, tlsConfig)
 		return gitBitbucketClient, nil
+	} else if config.GitProvider == bean.GITEA_PROVIDER {
+		gitGiteaClient, err := NewGitGiteaClient(config.GitHost, config.GitToken, config.GiteaOrganization, logger, gitOpsHelper, tlsConfig)
+		return gitGiteaClient, err
 	} else {
 		logger.Warn("no gitops config provided, gitops will not work")
 		return &UnimplementedGitOpsClient{}, nil
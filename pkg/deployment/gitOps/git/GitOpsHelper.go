@@ -18,6 +18,7 @@ package git
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/devtron-labs/devtron/api/bean"
 	apiGitOpsBean "github.com/devtron-labs/devtron/api/bean/gitOps"
@@ -99,7 +100,7 @@ func (impl *GitOpsHelper) cloneAndFetch(url, targetDir string) (ctx git.GitConte
 	_, errMsg, err := impl.gitCommandManager.Fetch(ctx, clonedDir)
 	if errMsg != "" {
 		impl.logger.Errorw("error in git fetch command", "errMsg", errMsg, "err", err)
-		return ctx, clonedDir, fmt.Errorf(errMsg)
+		return ctx, clonedDir, errors.New(errMsg)
 	} else if err != nil {
 		impl.logger.Errorw("error in git fetch command", "clonedDir", clonedDir, "url", url, "err", err)
 		return ctx, clonedDir, fmt.Errorf("error in git fetch command: %v", err)
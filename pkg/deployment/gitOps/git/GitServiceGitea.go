@@ -0,0 +1,468 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package git
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/devtron-labs/common-lib/utils/retryFunc"
+	bean2 "github.com/devtron-labs/devtron/api/bean/gitOps"
+	"github.com/devtron-labs/devtron/pkg/deployment/gitOps/git/bean"
+	globalUtil "github.com/devtron-labs/devtron/util"
+	"go.uber.org/zap"
+	"io"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	GITEA_API_V1 = "api/v1"
+)
+
+// GiteaErrorResponse is returned for all non 2xx responses of the gitea (or forgejo) api
+type GiteaErrorResponse struct {
+	StatusCode int    `json:"-"`
+	Message    string `json:"message"`
+	Url        string `json:"url"`
+}
+
+func (e *GiteaErrorResponse) Error() string {
+	return fmt.Sprintf("%d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), e.Message)
+}
+
+func IsGiteaRepoNotFound(err error) bool {
+	var responseErr *GiteaErrorResponse
+	return errors.As(err, &responseErr) && responseErr.StatusCode == http.StatusNotFound
+}
+
+func isGiteaConflictError(err error) bool {
+	var responseErr *GiteaErrorResponse
+	return errors.As(err, &responseErr) && responseErr.StatusCode == http.StatusConflict
+}
+
+type giteaRepository struct {
+	Name          string `json:"name"`
+	FullName      string `json:"full_name"`
+	CloneUrl      string `json:"clone_url"`
+	Empty         bool   `json:"empty"`
+	DefaultBranch string `json:"default_branch"`
+}
+
+type giteaCreateRepoOption struct {
+	Name          string `json:"name"`
+	Description   string `json:"description"`
+	Private       bool   `json:"private"`
+	AutoInit      bool   `json:"auto_init"`
+	DefaultBranch string `json:"default_branch,omitempty"`
+}
+
+type giteaIdentity struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+type giteaCommitDateOptions struct {
+	Author    time.Time `json:"author"`
+	Committer time.Time `json:"committer"`
+}
+
+type giteaFileOptions struct {
+	Content   string                 `json:"content"`
+	Message   string                 `json:"message"`
+	Branch    string                 `json:"branch,omitempty"`
+	SHA       string                 `json:"sha,omitempty"`
+	Author    giteaIdentity          `json:"author"`
+	Committer giteaIdentity          `json:"committer"`
+	Dates     giteaCommitDateOptions `json:"dates"`
+}
+
+type giteaContentsResponse struct {
+	Path string `json:"path"`
+	SHA  string `json:"sha"`
+}
+
+type giteaFileResponse struct {
+	Commit struct {
+		SHA    string `json:"sha"`
+		Author struct {
+			Date time.Time `json:"date"`
+		} `json:"author"`
+	} `json:"commit"`
+}
+
+type GitGiteaClient struct {
+	httpClient   *http.Client
+	baseUrl      string
+	token        string
+	org          string
+	logger       *zap.SugaredLogger
+	gitOpsHelper *GitOpsHelper
+}
+
+func NewGitGiteaClient(host string, token string, org string, logger *zap.SugaredLogger,
+	gitOpsHelper *GitOpsHelper, tlsConfig *tls.Config) (GitGiteaClient, error) {
+	hostUrl, err := url.Parse(host)
+	if err != nil {
+		logger.Errorw("error in creating gitea client", "host", host, "err", err)
+		return GitGiteaClient{}, err
+	}
+	if len(hostUrl.Scheme) == 0 || len(hostUrl.Host) == 0 {
+		return GitGiteaClient{}, fmt.Errorf("invalid host url '%s'", host)
+	}
+	if len(strings.TrimSpace(org)) == 0 {
+		return GitGiteaClient{}, fmt.Errorf("gitea organisation is required")
+	}
+	baseUrl := fmt.Sprintf("%s://%s/%s", hostUrl.Scheme, hostUrl.Host, GITEA_API_V1)
+	if trimmedPath := strings.Trim(hostUrl.Path, "/"); len(trimmedPath) > 0 {
+		// gitea can be served on a sub path, e.g. https://example.com/gitea
+		baseUrl = fmt.Sprintf("%s://%s/%s/%s", hostUrl.Scheme, hostUrl.Host, trimmedPath, GITEA_API_V1)
+	}
+	logger.Infow("gitea client created", "baseUrl", baseUrl, "org", org)
+	return GitGiteaClient{
+		httpClient:   globalUtil.GetHTTPClientWithTLSConfig(tlsConfig),
+		baseUrl:      baseUrl,
+		token:        token,
+		org:          org,
+		logger:       logger,
+		gitOpsHelper: gitOpsHelper,
+	}, nil
+}
+
+func (impl GitGiteaClient) doRequest(ctx context.Context, method, apiPath string, reqBody, respBody interface{}) error {
+	var body io.Reader
+	if reqBody != nil {
+		reqBytes, err := json.Marshal(reqBody)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(reqBytes)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, fmt.Sprintf("%s/%s", impl.baseUrl, strings.TrimPrefix(apiPath, "/")), body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("token %s", impl.token))
+	req.Header.Set("Accept", "application/json")
+	if reqBody != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := impl.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	respBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		responseErr := &GiteaErrorResponse{StatusCode: resp.StatusCode}
+		if len(respBytes) > 0 {
+			// error response body is best effort, status code is sufficient to classify the error
+			_ = json.Unmarshal(respBytes, responseErr)
+		}
+		return responseErr
+	}
+	if respBody != nil && len(respBytes) > 0 {
+		return json.Unmarshal(respBytes, respBody)
+	}
+	return nil
+}
+
+func (impl GitGiteaClient) repoApiPath(repoName string) string {
+	return fmt.Sprintf("repos/%s/%s", url.PathEscape(impl.org), url.PathEscape(repoName))
+}
+
+func (impl GitGiteaClient) DeleteRepository(config *bean2.GitOpsConfigDto) (err error) {
+	start := time.Now()
+	defer func() {
+		globalUtil.TriggerGitOpsMetrics("DeleteRepository", "GitGiteaClient", start, err)
+	}()
+	err = impl.doRequest(context.Background(), http.MethodDelete, impl.repoApiPath(config.GitRepoName), nil, nil)
+	if err != nil {
+		impl.logger.Errorw("repo deletion failed for gitea", "repo", config.GitRepoName, "err", err)
+		return err
+	}
+	return nil
+}
+
+func (impl GitGiteaClient) GetRepoUrl(config *bean2.GitOpsConfigDto) (repoUrl string, isRepoEmpty bool, err error) {
+	return impl.getRepoUrl(context.Background(), config, globalUtil.AllPublishableError())
+}
+
+func (impl GitGiteaClient) getRepoUrl(ctx context.Context, config *bean2.GitOpsConfigDto, isNonPublishableError globalUtil.EvalIsNonPublishableErr) (repoUrl string, isRepoEmpty bool, err error) {
+	start := time.Now()
+	defer func() {
+		if isNonPublishableError(err) {
+			impl.logger.Debugw("found non publishable error. skipping metrics publish!", "err", err)
+			return
+		}
+		globalUtil.TriggerGitOpsMetrics("GetRepoUrl", "GitGiteaClient", start, err)
+	}()
+	repo := &giteaRepository{}
+	err = impl.doRequest(ctx, http.MethodGet, impl.repoApiPath(config.GitRepoName), nil, repo)
+	if err != nil {
+		impl.logger.Errorw("error in getting repo url by repo name", "org", impl.org, "gitRepoName", config.GitRepoName, "err", err)
+		return "", false, err
+	}
+	return repo.CloneUrl, repo.Empty, nil
+}
+
+func (impl GitGiteaClient) CreateRepository(ctx context.Context, config *bean2.GitOpsConfigDto) (url string, isNew bool, isEmpty bool, detailedErrorGitOpsConfigActions DetailedErrorGitOpsConfigActions) {
+	var err error
+	start := time.Now()
+
+	detailedErrorGitOpsConfigActions.StageErrorMap = make(map[string]error)
+	repoExists := true
+	url, isEmpty, err = impl.getRepoUrl(ctx, config, IsGiteaRepoNotFound)
+	if err != nil {
+		if IsGiteaRepoNotFound(err) {
+			repoExists = false
+		} else {
+			impl.logger.Errorw("error in creating gitea repo", "err", err)
+			detailedErrorGitOpsConfigActions.StageErrorMap[bean.GetRepoUrlStage] = err
+			globalUtil.TriggerGitOpsMetrics("CreateRepository", "GitGiteaClient", start, err)
+			return "", false, isEmpty, detailedErrorGitOpsConfigActions
+		}
+	}
+	if repoExists {
+		detailedErrorGitOpsConfigActions.SuccessfulStages = append(detailedErrorGitOpsConfigActions.SuccessfulStages, bean.GetRepoUrlStage)
+		globalUtil.TriggerGitOpsMetrics("CreateRepository", "GitGiteaClient", start, nil)
+		return url, false, isEmpty, detailedErrorGitOpsConfigActions
+	}
+	repo, err := impl.createRepo(ctx, config)
+	if err != nil {
+		impl.logger.Errorw("error in creating gitea repo", "repo", config.GitRepoName, "err", err)
+		url, isEmpty, repoErr := impl.GetRepoUrl(config)
+		if repoErr != nil {
+			impl.logger.Errorw("error in getting gitea repo", "repo", config.GitRepoName, "err", repoErr)
+			detailedErrorGitOpsConfigActions.StageErrorMap[bean.CreateRepoStage] = err
+			globalUtil.TriggerGitOpsMetrics("CreateRepository", "GitGiteaClient", start, err)
+			return "", true, isEmpty, detailedErrorGitOpsConfigActions
+		}
+		detailedErrorGitOpsConfigActions.SuccessfulStages = append(detailedErrorGitOpsConfigActions.SuccessfulStages, bean.GetRepoUrlStage)
+		globalUtil.TriggerGitOpsMetrics("CreateRepository", "GitGiteaClient", start, nil)
+		return url, false, isEmpty, detailedErrorGitOpsConfigActions
+	}
+	impl.logger.Infow("gitea repo created ", "cloneUrl", repo.CloneUrl)
+	isEmpty = true
+	detailedErrorGitOpsConfigActions.SuccessfulStages = append(detailedErrorGitOpsConfigActions.SuccessfulStages, bean.CreateRepoStage)
+
+	validated, err := impl.ensureProjectAvailabilityOnHttp(config)
+	if err != nil {
+		impl.logger.Errorw("error in ensuring project availability gitea", "project", config.GitRepoName, "err", err)
+		detailedErrorGitOpsConfigActions.StageErrorMap[bean.CloneHttpStage] = err
+		globalUtil.TriggerGitOpsMetrics("CreateRepository", "GitGiteaClient", start, err)
+		return repo.CloneUrl, true, isEmpty, detailedErrorGitOpsConfigActions
+	}
+	if !validated {
+		err = fmt.Errorf("unable to validate project:%s in given time", config.GitRepoName)
+		detailedErrorGitOpsConfigActions.StageErrorMap[bean.CloneHttpStage] = err
+		globalUtil.TriggerGitOpsMetrics("CreateRepository", "GitGiteaClient", start, err)
+		return "", true, isEmpty, detailedErrorGitOpsConfigActions
+	}
+	detailedErrorGitOpsConfigActions.SuccessfulStages = append(detailedErrorGitOpsConfigActions.SuccessfulStages, bean.CloneHttpStage)
+
+	_, err = impl.CreateReadme(ctx, config)
+	if err != nil {
+		impl.logger.Errorw("error in creating readme gitea", "project", config.GitRepoName, "err", err)
+		detailedErrorGitOpsConfigActions.StageErrorMap[bean.CreateReadmeStage] = err
+		globalUtil.TriggerGitOpsMetrics("CreateRepository", "GitGiteaClient", start, err)
+		return repo.CloneUrl, true, isEmpty, detailedErrorGitOpsConfigActions
+	}
+	isEmpty = false //As we have created readme, repo is no longer empty
+	detailedErrorGitOpsConfigActions.SuccessfulStages = append(detailedErrorGitOpsConfigActions.SuccessfulStages, bean.CreateReadmeStage)
+
+	validated, err = impl.ensureProjectAvailabilityOnSsh(config.GitRepoName, repo.CloneUrl, config.TargetRevision)
+	if err != nil {
+		impl.logger.Errorw("error in ensuring project availability gitea", "project", config.GitRepoName, "err", err)
+		detailedErrorGitOpsConfigActions.StageErrorMap[bean.CloneSshStage] = err
+		globalUtil.TriggerGitOpsMetrics("CreateRepository", "GitGiteaClient", start, err)
+		return repo.CloneUrl, true, isEmpty, detailedErrorGitOpsConfigActions
+	}
+	if !validated {
+		err = fmt.Errorf("unable to validate project:%s in given time", config.GitRepoName)
+		detailedErrorGitOpsConfigActions.StageErrorMap[bean.CloneSshStage] = err
+		globalUtil.TriggerGitOpsMetrics("CreateRepository", "GitGiteaClient", start, err)
+		return "", true, isEmpty, detailedErrorGitOpsConfigActions
+	}
+	detailedErrorGitOpsConfigActions.SuccessfulStages = append(detailedErrorGitOpsConfigActions.SuccessfulStages, bean.CloneSshStage)
+	globalUtil.TriggerGitOpsMetrics("CreateRepository", "GitGiteaClient", start, nil)
+	return repo.CloneUrl, true, isEmpty, detailedErrorGitOpsConfigActions
+}
+
+func (impl GitGiteaClient) createRepo(ctx context.Context, config *bean2.GitOpsConfigDto) (*giteaRepository, error) {
+	createRepoOption := &giteaCreateRepoOption{
+		Name:          config.GitRepoName,
+		Description:   config.Description,
+		Private:       true,
+		DefaultBranch: config.TargetRevision,
+	}
+	repo := &giteaRepository{}
+	err := impl.doRequest(ctx, http.MethodPost, fmt.Sprintf("orgs/%s/repos", url.PathEscape(impl.org)), createRepoOption, repo)
+	if err != nil {
+		return nil, err
+	}
+	return repo, nil
+}
+
+func (impl GitGiteaClient) CreateFirstCommitOnHead(ctx context.Context, config *bean2.GitOpsConfigDto) (string, error) {
+	return impl.CreateReadme(ctx, config)
+}
+
+func (impl GitGiteaClient) CreateReadme(ctx context.Context, config *bean2.GitOpsConfigDto) (string, error) {
+	var err error
+	start := time.Now()
+	defer func() {
+		globalUtil.TriggerGitOpsMetrics("CreateReadme", "GitGiteaClient", start, err)
+	}()
+
+	cfg := &ChartConfig{
+		ChartName:      config.GitRepoName,
+		ChartLocation:  "",
+		FileName:       "README.md",
+		FileContent:    "@devtron",
+		ReleaseMessage: "readme",
+		ChartRepoName:  config.GitRepoName,
+		TargetRevision: config.TargetRevision,
+		UserName:       config.Username,
+		UserEmailId:    config.UserEmailId,
+	}
+	hash, _, err := impl.CommitValues(ctx, cfg, config, true)
+	if err != nil {
+		impl.logger.Errorw("error in creating readme gitea", "repo", config.GitRepoName, "err", err)
+	}
+	return hash, err
+}
+
+func (impl GitGiteaClient) CommitValues(ctx context.Context, config *ChartConfig, gitOpsConfig *bean2.GitOpsConfigDto, publishStatusConflictErrorMetrics bool) (commitHash string, commitTime time.Time, err error) {
+	start := time.Now()
+
+	branch := config.TargetRevision
+	if len(branch) == 0 {
+		branch = globalUtil.GetDefaultTargetRevision()
+	}
+	filePath := filepath.Join(config.ChartLocation, config.FileName)
+	contentsApiPath := fmt.Sprintf("%s/contents/%s", impl.repoApiPath(config.ChartRepoName), filePath)
+	newFile := false
+	existingContent := &giteaContentsResponse{}
+	err = impl.doRequest(ctx, http.MethodGet, fmt.Sprintf("%s?ref=%s", contentsApiPath, url.QueryEscape(branch)), nil, existingContent)
+	if err != nil {
+		if !IsGiteaRepoNotFound(err) {
+			impl.logger.Errorw("error in getting file contents gitea", "config", config, "err", err)
+			globalUtil.TriggerGitOpsMetrics("CommitValues", "GitGiteaClient", start, err)
+			return "", time.Time{}, err
+		}
+		newFile = true
+	}
+	timeNow := time.Now()
+	identity := giteaIdentity{
+		Name:  config.UserName,
+		Email: config.UserEmailId,
+	}
+	fileOptions := &giteaFileOptions{
+		Content:   base64.StdEncoding.EncodeToString([]byte(config.FileContent)),
+		Message:   config.ReleaseMessage,
+		Branch:    branch,
+		Author:    identity,
+		Committer: identity,
+		Dates: giteaCommitDateOptions{
+			Author:    timeNow,
+			Committer: timeNow,
+		},
+	}
+	method := http.MethodPost
+	if !newFile {
+		// updating an existing file requires the blob sha of the current version
+		method = http.MethodPut
+		fileOptions.SHA = existingContent.SHA
+	}
+	fileResponse := &giteaFileResponse{}
+	err = impl.doRequest(ctx, method, contentsApiPath, fileOptions, fileResponse)
+	if err != nil && isGiteaConflictError(err) {
+		impl.logger.Warnw("conflict found in commit gitea", "config", config, "err", err)
+		if publishStatusConflictErrorMetrics {
+			globalUtil.TriggerGitOpsMetrics("CommitValues", "GitGiteaClient", start, err)
+		}
+		return "", time.Time{}, retryFunc.NewRetryableError(err)
+	} else if err != nil {
+		impl.logger.Errorw("error in commit gitea", "config", config, "err", err)
+		globalUtil.TriggerGitOpsMetrics("CommitValues", "GitGiteaClient", start, err)
+		return "", time.Time{}, err
+	}
+	commitTime = time.Now() // default is current time, if found then will get updated accordingly
+	if !fileResponse.Commit.Author.Date.IsZero() {
+		commitTime = fileResponse.Commit.Author.Date
+	}
+	globalUtil.TriggerGitOpsMetrics("CommitValues", "GitGiteaClient", start, nil)
+	return fileResponse.Commit.SHA, commitTime, nil
+}
+
+func (impl GitGiteaClient) ensureProjectAvailabilityOnHttp(config *bean2.GitOpsConfigDto) (bool, error) {
+	var err error
+	start := time.Now()
+	defer func() {
+		globalUtil.TriggerGitOpsMetrics("ensureProjectAvailabilityOnHttp", "GitGiteaClient", start, err)
+	}()
+
+	count := 0
+	for count < 3 {
+		count = count + 1
+		_, _, err := impl.GetRepoUrl(config)
+		if err == nil {
+			return true, nil
+		}
+		if !IsGiteaRepoNotFound(err) {
+			impl.logger.Errorw("error in validating repo gitea", "project", config.GitRepoName, "err", err)
+			return false, err
+		} else {
+			impl.logger.Errorw("error in validating repo gitea", "project", config.GitRepoName, "err", err)
+		}
+		time.Sleep(10 * time.Second)
+	}
+	return false, nil
+}
+
+func (impl GitGiteaClient) ensureProjectAvailabilityOnSsh(projectName string, repoUrl, targetRevision string) (bool, error) {
+	var err error
+	start := time.Now()
+	defer func() {
+		globalUtil.TriggerGitOpsMetrics("ensureProjectAvailabilityOnSsh", "GitGiteaClient", start, err)
+	}()
+
+	count := 0
+	for count < 3 {
+		count = count + 1
+		_, err := impl.gitOpsHelper.Clone(repoUrl, fmt.Sprintf("/ensure-clone/%s", projectName), targetRevision)
+		if err == nil {
+			impl.logger.Infow("gitea ensureProjectAvailability clone passed", "try count", count, "repoUrl", repoUrl)
+			return true, nil
+		} else {
+			impl.logger.Errorw("gitea ensureProjectAvailability clone failed", "try count", count, "err", err)
+		}
+		time.Sleep(10 * time.Second)
+	}
+	return false, nil
+}
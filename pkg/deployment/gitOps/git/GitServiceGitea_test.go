@@ -0,0 +1,256 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ */
+
+package git
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/devtron-labs/common-lib/utils/retryFunc"
+	apiBean "github.com/devtron-labs/devtron/api/bean/gitOps"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/deployment/gitOps/git/bean"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// giteaStubServer is an in-memory stand-in of the gitea api, serving a single organisation
+type giteaStubServer struct {
+	*httptest.Server
+	t            *testing.T
+	org          string
+	repos        map[string]*giteaRepository
+	files        map[string]string
+	commitStatus int
+	requests     []string
+}
+
+func newGiteaStubServer(t *testing.T, org string) *giteaStubServer {
+	stub := &giteaStubServer{
+		t:     t,
+		org:   org,
+		repos: make(map[string]*giteaRepository),
+		files: make(map[string]string),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/v1/orgs/{org}/repos", stub.createRepo)
+	mux.HandleFunc("GET /api/v1/repos/{org}/{repo}", stub.getRepo)
+	mux.HandleFunc("GET /api/v1/repos/{org}/{repo}/contents/{path...}", stub.getContents)
+	mux.HandleFunc("POST /api/v1/repos/{org}/{repo}/contents/{path...}", stub.commitFile)
+	mux.HandleFunc("PUT /api/v1/repos/{org}/{repo}/contents/{path...}", stub.commitFile)
+	stub.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		stub.requests = append(stub.requests, r.Method+" "+r.URL.EscapedPath())
+		if r.Header.Get("Authorization") != "token test-token" {
+			stub.writeError(w, http.StatusUnauthorized, "invalid token")
+			return
+		}
+		mux.ServeHTTP(w, r)
+	}))
+	t.Cleanup(stub.Close)
+	return stub
+}
+
+func (stub *giteaStubServer) writeError(w http.ResponseWriter, statusCode int, message string) {
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(&GiteaErrorResponse{Message: message})
+}
+
+func (stub *giteaStubServer) createRepo(w http.ResponseWriter, r *http.Request) {
+	if r.PathValue("org") != stub.org {
+		stub.writeError(w, http.StatusNotFound, "org not found")
+		return
+	}
+	createRepoOption := &giteaCreateRepoOption{}
+	assert.Nil(stub.t, json.NewDecoder(r.Body).Decode(createRepoOption))
+	if _, ok := stub.repos[createRepoOption.Name]; ok {
+		stub.writeError(w, http.StatusConflict, "repository already exists")
+		return
+	}
+	repo := &giteaRepository{
+		Name:          createRepoOption.Name,
+		FullName:      stub.org + "/" + createRepoOption.Name,
+		CloneUrl:      stub.URL + "/" + stub.org + "/" + createRepoOption.Name + ".git",
+		Empty:         true,
+		DefaultBranch: createRepoOption.DefaultBranch,
+	}
+	stub.repos[repo.Name] = repo
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(repo)
+}
+
+func (stub *giteaStubServer) getRepo(w http.ResponseWriter, r *http.Request) {
+	repo, ok := stub.repos[r.PathValue("repo")]
+	if r.PathValue("org") != stub.org || !ok {
+		stub.writeError(w, http.StatusNotFound, "repository not found")
+		return
+	}
+	_ = json.NewEncoder(w).Encode(repo)
+}
+
+func (stub *giteaStubServer) getContents(w http.ResponseWriter, r *http.Request) {
+	filePath := r.PathValue("repo") + "/" + r.PathValue("path")
+	content, ok := stub.files[filePath]
+	if !ok {
+		stub.writeError(w, http.StatusNotFound, "file not found")
+		return
+	}
+	_ = json.NewEncoder(w).Encode(&giteaContentsResponse{Path: r.PathValue("path"), SHA: "sha-" + content})
+}
+
+func (stub *giteaStubServer) commitFile(w http.ResponseWriter, r *http.Request) {
+	if stub.commitStatus != 0 {
+		stub.writeError(w, stub.commitStatus, "commit failed")
+		return
+	}
+	fileOptions := &giteaFileOptions{}
+	assert.Nil(stub.t, json.NewDecoder(r.Body).Decode(fileOptions))
+	filePath := r.PathValue("repo") + "/" + r.PathValue("path")
+	existingContent, exists := stub.files[filePath]
+	if r.Method == http.MethodPut && (!exists || fileOptions.SHA != "sha-"+existingContent) {
+		stub.writeError(w, http.StatusConflict, "sha does not match")
+		return
+	}
+	content, err := base64.StdEncoding.DecodeString(fileOptions.Content)
+	assert.Nil(stub.t, err)
+	stub.files[filePath] = string(content)
+	fileResponse := &giteaFileResponse{}
+	fileResponse.Commit.SHA = "commit-" + string(content)
+	fileResponse.Commit.Author.Date = fileOptions.Dates.Author
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(fileResponse)
+}
+
+func newGiteaTestClient(t *testing.T, host, org string) GitGiteaClient {
+	logger, err := util.NewSugardLogger()
+	assert.Nil(t, err)
+	client, err := NewGitGiteaClient(host, "test-token", org, logger, nil, nil)
+	assert.Nil(t, err)
+	return client
+}
+
+func TestNewGitGiteaClient(t *testing.T) {
+	logger, err := util.NewSugardLogger()
+	assert.Nil(t, err)
+
+	t.Run("sub path host", func(t *testing.T) {
+		client, err := NewGitGiteaClient("https://example.com/gitea/", "test-token", "devtron", logger, nil, nil)
+		assert.Nil(t, err)
+		assert.Equal(t, "https://example.com/gitea/api/v1", client.baseUrl)
+	})
+
+	t.Run("missing organisation", func(t *testing.T) {
+		_, err := NewGitGiteaClient("https://example.com", "test-token", " ", logger, nil, nil)
+		assert.NotNil(t, err)
+	})
+}
+
+func TestGitGiteaClient(t *testing.T) {
+	t.Run("create repo escapes organisation", func(t *testing.T) {
+		server := newGiteaStubServer(t, "dev ops")
+		client := newGiteaTestClient(t, server.URL, "dev ops")
+		repo, err := client.createRepo(context.Background(), &apiBean.GitOpsConfigDto{GitRepoName: "app-1", TargetRevision: "main"})
+		assert.Nil(t, err)
+		assert.Equal(t, "dev ops/app-1", repo.FullName)
+		assert.Equal(t, "main", repo.DefaultBranch)
+		assert.Equal(t, []string{"POST /api/v1/orgs/dev%20ops/repos"}, server.requests)
+	})
+
+	t.Run("create repo in missing organisation", func(t *testing.T) {
+		server := newGiteaStubServer(t, "devtron")
+		client := newGiteaTestClient(t, server.URL, "unknown")
+		_, err := client.createRepo(context.Background(), &apiBean.GitOpsConfigDto{GitRepoName: "app-1"})
+		assert.True(t, IsGiteaRepoNotFound(err))
+	})
+
+	t.Run("get repo", func(t *testing.T) {
+		server := newGiteaStubServer(t, "devtron")
+		server.repos["app-1"] = &giteaRepository{Name: "app-1", CloneUrl: "https://gitea.example.com/devtron/app-1.git", Empty: true}
+		client := newGiteaTestClient(t, server.URL, "devtron")
+		repoUrl, isEmpty, err := client.GetRepoUrl(&apiBean.GitOpsConfigDto{GitRepoName: "app-1"})
+		assert.Nil(t, err)
+		assert.Equal(t, "https://gitea.example.com/devtron/app-1.git", repoUrl)
+		assert.True(t, isEmpty)
+
+		_, _, err = client.GetRepoUrl(&apiBean.GitOpsConfigDto{GitRepoName: "app-2"})
+		assert.True(t, IsGiteaRepoNotFound(err))
+	})
+
+	t.Run("get repo with invalid token", func(t *testing.T) {
+		server := newGiteaStubServer(t, "devtron")
+		logger, err := util.NewSugardLogger()
+		assert.Nil(t, err)
+		client, err := NewGitGiteaClient(server.URL, "invalid-token", "devtron", logger, nil, nil)
+		assert.Nil(t, err)
+		_, _, err = client.GetRepoUrl(&apiBean.GitOpsConfigDto{GitRepoName: "app-1"})
+		assert.NotNil(t, err)
+		assert.False(t, IsGiteaRepoNotFound(err))
+		assert.Equal(t, "401 Unauthorized: invalid token", err.Error())
+	})
+
+	t.Run("create repository when repo exists", func(t *testing.T) {
+		server := newGiteaStubServer(t, "devtron")
+		server.repos["app-1"] = &giteaRepository{Name: "app-1", CloneUrl: "https://gitea.example.com/devtron/app-1.git"}
+		client := newGiteaTestClient(t, server.URL, "devtron")
+		repoUrl, isNew, isEmpty, detailedErr := client.CreateRepository(context.Background(), &apiBean.GitOpsConfigDto{GitRepoName: "app-1"})
+		assert.Empty(t, detailedErr.StageErrorMap)
+		assert.Equal(t, []string{bean.GetRepoUrlStage}, detailedErr.SuccessfulStages)
+		assert.Equal(t, "https://gitea.example.com/devtron/app-1.git", repoUrl)
+		assert.False(t, isNew)
+		assert.False(t, isEmpty)
+		assert.Equal(t, []string{"GET /api/v1/repos/devtron/app-1"}, server.requests)
+	})
+
+	t.Run("push new and existing file", func(t *testing.T) {
+		server := newGiteaStubServer(t, "devtron")
+		client := newGiteaTestClient(t, server.URL, "devtron")
+		chartConfig := &ChartConfig{
+			ChartLocation:  "app-1/env-1",
+			FileName:       "values.yaml",
+			FileContent:    "replicaCount: 1",
+			ReleaseMessage: "release 1",
+			ChartRepoName:  "app-1",
+			TargetRevision: "main",
+			UserName:       "admin",
+			UserEmailId:    "admin@example.com",
+		}
+		commitHash, commitTime, err := client.CommitValues(context.Background(), chartConfig, nil, true)
+		assert.Nil(t, err)
+		assert.Equal(t, "commit-replicaCount: 1", commitHash)
+		assert.False(t, commitTime.IsZero())
+
+		chartConfig.FileContent = "replicaCount: 2"
+		commitHash, _, err = client.CommitValues(context.Background(), chartConfig, nil, true)
+		assert.Nil(t, err)
+		assert.Equal(t, "commit-replicaCount: 2", commitHash)
+		assert.Equal(t, "replicaCount: 2", server.files["app-1/app-1/env-1/values.yaml"])
+		assert.Equal(t, []string{
+			"GET /api/v1/repos/devtron/app-1/contents/app-1/env-1/values.yaml",
+			"POST /api/v1/repos/devtron/app-1/contents/app-1/env-1/values.yaml",
+			"GET /api/v1/repos/devtron/app-1/contents/app-1/env-1/values.yaml",
+			"PUT /api/v1/repos/devtron/app-1/contents/app-1/env-1/values.yaml",
+		}, server.requests)
+	})
+
+	t.Run("push conflict is retryable", func(t *testing.T) {
+		server := newGiteaStubServer(t, "devtron")
+		server.commitStatus = http.StatusConflict
+		client := newGiteaTestClient(t, server.URL, "devtron")
+		chartConfig := &ChartConfig{FileName: "values.yaml", FileContent: "replicaCount: 1", ChartRepoName: "app-1"}
+		_, _, err := client.CommitValues(context.Background(), chartConfig, nil, false)
+		assert.True(t, retryFunc.IsRetryableError(err))
+	})
+
+	t.Run("push failure is not retryable", func(t *testing.T) {
+		server := newGiteaStubServer(t, "devtron")
+		server.commitStatus = http.StatusForbidden
+		client := newGiteaTestClient(t, server.URL, "devtron")
+		chartConfig := &ChartConfig{FileName: "values.yaml", FileContent: "replicaCount: 1", ChartRepoName: "app-1"}
+		_, _, err := client.CommitValues(context.Background(), chartConfig, nil, true)
+		assert.NotNil(t, err)
+		assert.False(t, retryFunc.IsRetryableError(err))
+	})
+}
@@ -27,12 +27,13 @@ type GitConfig struct {
 	GitToken             string //not null  // public
 	GitUserName          string //not null  // public
 	GithubOrganization   string
-	GitProvider          string // SUPPORTED VALUES  GITHUB, GITLAB
+	GitProvider          string // SUPPORTED VALUES  GITHUB, GITLAB, AZURE_DEVOPS, BITBUCKET_CLOUD, GITEA
 	GitHost              string
 	AzureToken           string
 	AzureProject         string
 	BitbucketWorkspaceId string
 	BitbucketProjectKey  string
+	GiteaOrganization    string
 
 	IsActiveConfig bool //flag to check if the gitOps config is active
 
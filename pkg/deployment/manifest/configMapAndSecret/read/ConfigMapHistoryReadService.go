@@ -28,6 +28,10 @@ type ConfigMapHistoryReadService interface {
 	ConvertConfigDataToComponentLevelDto(config *bean2.ConfigData, configType repository.ConfigType, userHasAdminAccess bool) (*bean.ComponentLevelHistoryDetailDto, error)
 	GetCmCsHistoryByWfrIdAndPipelineId(ctx context.Context, pipelineId int, wfrId int, configType repository.ConfigType, userHasAdminAccess bool) (*bean3.DeploymentAndCmCsConfig, error)
 	GetCmCsListObjectFromHistory(history *repository.ConfigmapAndSecretHistory) (*bean4.ConfigsList, *bean4.SecretsList, error)
+	GetHistoryModelById(id int) (*repository.ConfigmapAndSecretHistory, error)
+	// GetLatestHistoryModel returns the latest saved version of the app level configs if envId is 0,
+	// else of the configs merged for the environment
+	GetLatestHistoryModel(appId, envId int, configType repository.ConfigType) (*repository.ConfigmapAndSecretHistory, error)
 }
 
 type ConfigMapHistoryReadServiceImpl struct {
@@ -483,3 +487,21 @@ func (impl *ConfigMapHistoryReadServiceImpl) GetCmCsListObjectFromHistory(histor
 	}
 	return configsList, secretsList, nil
 }
+
+func (impl *ConfigMapHistoryReadServiceImpl) GetHistoryModelById(id int) (*repository.ConfigmapAndSecretHistory, error) {
+	history, err := impl.configMapHistoryRepository.GetHistoryById(id)
+	if err != nil {
+		impl.logger.Errorw("error in getting CM/CS history", "err", err, "id", id)
+		return nil, err
+	}
+	return history, nil
+}
+
+func (impl *ConfigMapHistoryReadServiceImpl) GetLatestHistoryModel(appId, envId int, configType repository.ConfigType) (*repository.ConfigmapAndSecretHistory, error) {
+	history, err := impl.configMapHistoryRepository.GetLatestHistoryByAppIdAndEnvId(appId, envId, configType)
+	if err != nil {
+		impl.logger.Errorw("error in getting latest CM/CS history", "err", err, "appId", appId, "envId", envId, "configType", configType)
+		return nil, err
+	}
+	return history, nil
+}
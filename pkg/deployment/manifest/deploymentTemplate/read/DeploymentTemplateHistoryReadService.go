@@ -25,6 +25,9 @@ type DeploymentTemplateHistoryReadService interface {
 
 	GetTemplateHistoryModelForDeployedTemplateById(deploymentTemplateHistoryId, pipelineId int) (*repository2.DeploymentTemplateHistory, error)
 	GetDeployedOnByDeploymentTemplateAndPipelineId(deploymentTemplateHistoryId, pipelineId int) (time.Time, error)
+	GetTemplateHistoryModelById(deploymentTemplateHistoryId int) (*repository2.DeploymentTemplateHistory, error)
+	// GetLatestTemplateHistoryModel returns the latest saved version of the base template if envId is 0, else of the env override
+	GetLatestTemplateHistoryModel(appId, envId int) (*repository2.DeploymentTemplateHistory, error)
 }
 
 type DeploymentTemplateHistoryReadServiceImpl struct {
@@ -129,6 +132,24 @@ func (impl *DeploymentTemplateHistoryReadServiceImpl) GetTemplateHistoryModelFor
 	return history, nil
 }
 
+func (impl *DeploymentTemplateHistoryReadServiceImpl) GetTemplateHistoryModelById(deploymentTemplateHistoryId int) (*repository2.DeploymentTemplateHistory, error) {
+	history, err := impl.deploymentTemplateHistoryRepository.GetHistoryById(deploymentTemplateHistoryId)
+	if err != nil {
+		impl.logger.Errorw("error in getting deployment template history", "err", err, "deploymentTemplateHistoryId", deploymentTemplateHistoryId)
+		return nil, err
+	}
+	return history, nil
+}
+
+func (impl *DeploymentTemplateHistoryReadServiceImpl) GetLatestTemplateHistoryModel(appId, envId int) (*repository2.DeploymentTemplateHistory, error) {
+	history, err := impl.deploymentTemplateHistoryRepository.GetLatestHistoryByAppIdAndEnvId(appId, envId)
+	if err != nil {
+		impl.logger.Errorw("error in getting latest deployment template history", "err", err, "appId", appId, "envId", envId)
+		return nil, err
+	}
+	return history, nil
+}
+
 func (impl *DeploymentTemplateHistoryReadServiceImpl) GetDeployedHistoryList(pipelineId, baseConfigId int) ([]*bean.DeployedHistoryComponentMetadataDto, error) {
 	impl.logger.Debugw("received request, GetDeployedHistoryList", "pipelineId", pipelineId, "baseConfigId", baseConfigId)
 
@@ -21,12 +21,15 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"github.com/devtron-labs/devtron/cel"
 	"github.com/devtron-labs/devtron/internal/util"
 	bean3 "github.com/devtron-labs/devtron/pkg/chart/bean"
 	"github.com/devtron-labs/devtron/pkg/deployment/manifest/deployedAppMetrics"
 	"github.com/devtron-labs/devtron/pkg/deployment/manifest/deploymentTemplate/bean"
 	"github.com/devtron-labs/devtron/pkg/deployment/manifest/deploymentTemplate/chartRef"
 	pipelineBean "github.com/devtron-labs/devtron/pkg/pipeline/bean"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/celPolicy"
+	celPolicyBean "github.com/devtron-labs/devtron/pkg/policyGovernance/celPolicy/bean"
 	"github.com/devtron-labs/devtron/pkg/resourceQualifiers"
 	"github.com/devtron-labs/devtron/pkg/variables"
 	"github.com/devtron-labs/devtron/pkg/variables/parsers"
@@ -49,6 +52,7 @@ type DeploymentTemplateValidationServiceImpl struct {
 	chartRefService           chartRef.ChartRefService
 	scopedVariableManager     variables.ScopedVariableManager
 	deployedAppMetricsService deployedAppMetrics.DeployedAppMetricsService
+	celPolicyService          celPolicy.CelPolicyService
 	*DeploymentTemplateValidationServiceEntImpl
 }
 
@@ -57,12 +61,14 @@ func NewDeploymentTemplateValidationServiceImpl(logger *zap.SugaredLogger,
 	scopedVariableManager variables.ScopedVariableManager,
 	deployedAppMetricsService deployedAppMetrics.DeployedAppMetricsService,
 	deploymentTemplateValidationServiceEntImpl *DeploymentTemplateValidationServiceEntImpl,
+	celPolicyService celPolicy.CelPolicyService,
 ) *DeploymentTemplateValidationServiceImpl {
 	return &DeploymentTemplateValidationServiceImpl{
 		logger:                    logger,
 		chartRefService:           chartRefService,
 		scopedVariableManager:     scopedVariableManager,
 		deployedAppMetricsService: deployedAppMetricsService,
+		celPolicyService:          celPolicyService,
 		DeploymentTemplateValidationServiceEntImpl: deploymentTemplateValidationServiceEntImpl,
 	}
 }
@@ -73,7 +79,8 @@ func (impl *DeploymentTemplateValidationServiceImpl) DeploymentTemplateValidate(
 	span.End()
 	if err != nil {
 		impl.logger.Errorw("Json Schema not found err, FindJsonSchema", "err", err)
-		return true, nil
+		// policies of CONFIG_SAVE hook are enforced for the charts without schema as well
+		return impl.evaluateConfigSavePoliciesWithoutSchema(ctx, template, chartRefId, scope)
 	}
 
 	templateBytes := template.(json.RawMessage)
@@ -118,6 +125,11 @@ func (impl *DeploymentTemplateValidationServiceImpl) DeploymentTemplateValidate(
 			impl.logger.Errorw("LimitRequestCompare err, DeploymentTemplateValidate", "err", err)
 			return false, err
 		}
+		err = impl.evaluateConfigSavePolicies(ctx, dat, chartRefId, version, scope)
+		if err != nil {
+			impl.logger.Errorw("policy evaluation err, DeploymentTemplateValidate", "chartRefId", chartRefId, "scope", scope, "err", err)
+			return false, getPolicyViolationApiError(err)
+		}
 		return true, nil
 	} else {
 		var stringerror string
@@ -135,6 +147,64 @@ func (impl *DeploymentTemplateValidationServiceImpl) DeploymentTemplateValidate(
 	}
 }
 
+// evaluateConfigSavePolicies evaluates the CEL policies of CONFIG_SAVE hook on the resolved deployment template values
+func (impl *DeploymentTemplateValidationServiceImpl) evaluateConfigSavePolicies(ctx context.Context, values map[string]interface{}, chartRefId int, chartVersion string, scope resourceQualifiers.Scope) error {
+	evaluationRequest := &celPolicyBean.EvaluationRequest{
+		Hook: celPolicyBean.ConfigSaveHook,
+		Scope: &celPolicyBean.PolicyEvaluationScope{
+			AppId:     scope.AppId,
+			EnvId:     scope.EnvId,
+			ClusterId: scope.ClusterId,
+		},
+		Params: []cel.ExpressionParam{
+			{ParamName: cel.ChartRefId, Value: chartRefId, Type: cel.ParamTypeInteger},
+			{ParamName: cel.ChartVersion, Value: chartVersion, Type: cel.ParamTypeString},
+			{ParamName: cel.DeploymentTemplateValues, Value: values, Type: cel.ParamTypeMapStringToAny},
+		},
+	}
+	_, err := impl.celPolicyService.EvaluatePolicies(ctx, evaluationRequest)
+	return err
+}
+
+// evaluateConfigSavePoliciesWithoutSchema evaluates the CONFIG_SAVE policies for the charts without schema, the values
+// of a template which can not be resolved are evaluated as empty
+func (impl *DeploymentTemplateValidationServiceImpl) evaluateConfigSavePoliciesWithoutSchema(ctx context.Context, template interface{}, chartRefId int, scope resourceQualifiers.Scope) (bool, error) {
+	var chartVersion string
+	chartRefDto, err := impl.chartRefService.FindById(chartRefId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching chart ref", "chartRefId", chartRefId, "err", err)
+		return false, err
+	} else if chartRefDto != nil {
+		chartVersion = chartRefDto.Version
+	}
+	values := make(map[string]interface{})
+	if templateBytes, ok := template.(json.RawMessage); ok {
+		templateJsonString, _, err := impl.scopedVariableManager.ExtractVariablesAndResolveTemplate(scope, string(templateBytes), parsers.JsonVariableTemplate, true, false)
+		if err == nil {
+			err = json.Unmarshal([]byte(templateJsonString), &values)
+		}
+		if err != nil {
+			impl.logger.Warnw("error in resolving template of chart without schema, evaluating policies on empty values", "chartRefId", chartRefId, "err", err)
+			values = make(map[string]interface{})
+		}
+	}
+	err = impl.evaluateConfigSavePolicies(ctx, values, chartRefId, chartVersion, scope)
+	if err != nil {
+		impl.logger.Errorw("policy evaluation err, DeploymentTemplateValidate", "chartRefId", chartRefId, "scope", scope, "err", err)
+		return false, getPolicyViolationApiError(err)
+	}
+	return true, nil
+}
+
+// getPolicyViolationApiError returns the violation of the blocking policies as an unprocessable entity error listing the violations
+func getPolicyViolationApiError(err error) error {
+	policyViolationErr := &celPolicyBean.PolicyViolationError{}
+	if !errors.As(err, &policyViolationErr) {
+		return err
+	}
+	return util.NewApiError(http.StatusUnprocessableEntity, policyViolationErr.Error(), policyViolationErr.Error())
+}
+
 func (impl *DeploymentTemplateValidationServiceImpl) FlaggerCanaryEnabled(values json.RawMessage) (bool, error) {
 	var jsonMap map[string]json.RawMessage
 	if err := json.Unmarshal(values, &jsonMap); err != nil {
@@ -197,6 +267,9 @@ func (impl *DeploymentTemplateValidationServiceImpl) ValidateChangeChartRefReque
 	validate, err2 := impl.DeploymentTemplateValidate(ctx, envConfigProperties.EnvOverrideValues, envConfigProperties.ChartRefId, scope)
 	if !validate {
 		impl.logger.Errorw("validation err, UpdateAppOverride", "err", err2, "payload", request)
+		if apiErr, ok := err2.(*util.ApiError); ok {
+			return envConfigProperties, envMetrics, apiErr
+		}
 		errMsg := fmt.Sprintf("template schema validation error for appId: %d, envId: %d", request.AppId, request.EnvId)
 		return envConfigProperties, envMetrics, util.NewApiError(http.StatusBadRequest, errMsg, err2.Error())
 	}
@@ -0,0 +1,87 @@
+package validator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/devtron-labs/devtron/cel"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/deployment/manifest/deploymentTemplate/chartRef"
+	chartRefBean "github.com/devtron-labs/devtron/pkg/deployment/manifest/deploymentTemplate/chartRef/bean"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/celPolicy"
+	celPolicyBean "github.com/devtron-labs/devtron/pkg/policyGovernance/celPolicy/bean"
+	"github.com/devtron-labs/devtron/pkg/resourceQualifiers"
+	"github.com/devtron-labs/devtron/pkg/variables"
+	"github.com/devtron-labs/devtron/pkg/variables/parsers"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+type chartRefServiceStub struct {
+	chartRef.ChartRefService
+}
+
+func (stub *chartRefServiceStub) JsonSchemaExtractFromFile(chartRefId int) (map[string]interface{}, string, error) {
+	return nil, "", errors.New("schema not found")
+}
+
+func (stub *chartRefServiceStub) FindById(chartRefId int) (*chartRefBean.ChartRefDto, error) {
+	return &chartRefBean.ChartRefDto{Id: chartRefId, Version: "1.0.0"}, nil
+}
+
+type scopedVariableManagerStub struct {
+	variables.ScopedVariableManager
+}
+
+func (stub *scopedVariableManagerStub) ExtractVariablesAndResolveTemplate(scope resourceQualifiers.Scope, template string, templateType parsers.VariableTemplateType, unmaskSensitiveData bool, maskUnknownVariable bool) (string, map[string]string, error) {
+	return template, nil, nil
+}
+
+// celPolicyServiceStub blocks the templates with more than maxReplicas replicas
+type celPolicyServiceStub struct {
+	celPolicy.CelPolicyService
+	maxReplicas float64
+}
+
+func (stub *celPolicyServiceStub) EvaluatePolicies(ctx context.Context, request *celPolicyBean.EvaluationRequest) (*celPolicyBean.EvaluationResult, error) {
+	var values map[string]interface{}
+	for _, param := range request.Params {
+		if param.ParamName == cel.DeploymentTemplateValues {
+			values = param.Value.(map[string]interface{})
+		}
+	}
+	if replicas, ok := values["replicaCount"].(float64); ok && replicas > stub.maxReplicas {
+		violations := []*celPolicyBean.PolicyViolation{{PolicyName: "max-replicas", Message: "too many replicas"}}
+		return &celPolicyBean.EvaluationResult{Violations: violations}, &celPolicyBean.PolicyViolationError{Hook: request.Hook, Violations: violations}
+	}
+	return &celPolicyBean.EvaluationResult{}, nil
+}
+
+func TestDeploymentTemplateValidateWithoutSchema(t *testing.T) {
+	logger, err := util.NewSugardLogger()
+	assert.NoError(t, err)
+	impl := &DeploymentTemplateValidationServiceImpl{
+		logger:                logger,
+		chartRefService:       &chartRefServiceStub{},
+		scopedVariableManager: &scopedVariableManagerStub{},
+		celPolicyService:      &celPolicyServiceStub{maxReplicas: 3},
+	}
+	scope := resourceQualifiers.Scope{AppId: 1}
+
+	valid, err := impl.DeploymentTemplateValidate(context.Background(), json.RawMessage(`{"replicaCount": 2}`), 1, scope)
+	assert.True(t, valid)
+	assert.NoError(t, err)
+
+	valid, err = impl.DeploymentTemplateValidate(context.Background(), json.RawMessage(`{"replicaCount": 5}`), 1, scope)
+	assert.False(t, valid)
+	apiErr, ok := err.(*util.ApiError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusUnprocessableEntity, apiErr.HttpStatusCode)
+	assert.Contains(t, apiErr.UserMessage, "max-replicas: too many replicas")
+
+	// templates which are not valid json are accepted as before, the policies are evaluated on empty values
+	valid, err = impl.DeploymentTemplateValidate(context.Background(), json.RawMessage(`replicaCount: 5`), 1, scope)
+	assert.True(t, valid)
+	assert.NoError(t, err)
+}
@@ -19,8 +19,11 @@ package devtronApps
 import (
 	"bufio"
 	"context"
+	"github.com/caarlos0/env"
 	"github.com/devtron-labs/common-lib/async"
 	"github.com/devtron-labs/devtron/client/fluxcd"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/celPolicy"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/deploymentWindow"
 	service2 "github.com/devtron-labs/devtron/pkg/workflow/trigger/audit/service"
 	"github.com/devtron-labs/devtron/pkg/workflow/workflowStatusLatest"
 	"os"
@@ -46,6 +49,7 @@ import (
 	"github.com/devtron-labs/devtron/pkg/attributes"
 	"github.com/devtron-labs/devtron/pkg/auth/user"
 	userBean "github.com/devtron-labs/devtron/pkg/auth/user/bean"
+	imageTaggingRead "github.com/devtron-labs/devtron/pkg/build/artifacts/imageTagging/read"
 	"github.com/devtron-labs/devtron/pkg/build/git/gitMaterial/read"
 	pipeline2 "github.com/devtron-labs/devtron/pkg/build/pipeline"
 	chartRepoRepository "github.com/devtron-labs/devtron/pkg/chartRepo/repository"
@@ -57,6 +61,7 @@ import (
 	"github.com/devtron-labs/devtron/pkg/deployment/gitOps/config"
 	"github.com/devtron-labs/devtron/pkg/deployment/gitOps/git"
 	"github.com/devtron-labs/devtron/pkg/deployment/manifest"
+	deploymentTemplateRead "github.com/devtron-labs/devtron/pkg/deployment/manifest/deploymentTemplate/read"
 	"github.com/devtron-labs/devtron/pkg/deployment/manifest/publish"
 	"github.com/devtron-labs/devtron/pkg/deployment/trigger/devtronApps/bean"
 	"github.com/devtron-labs/devtron/pkg/deployment/trigger/devtronApps/userDeploymentRequest/service"
@@ -176,6 +181,12 @@ type HandlerServiceImpl struct {
 	workflowTriggerAuditService         service2.WorkflowTriggerAuditService
 	fluxCdDeploymentService             fluxcd.DeploymentService
 	workflowStatusLatestService         workflowStatusLatest.WorkflowStatusLatestService
+	imageScanResultReadService          read2.ImageScanResultReadService
+	imageTaggingReadService             imageTaggingRead.ImageTaggingReadService
+	feasibilityCheckConfig              *bean.FeasibilityCheckConfig
+	celPolicyService                    celPolicy.CelPolicyService
+	deploymentWindowService             deploymentWindow.DeploymentWindowService
+	envConfigOverrideReadService        deploymentTemplateRead.EnvConfigOverrideService
 }
 
 func NewHandlerServiceImpl(logger *zap.SugaredLogger,
@@ -241,7 +252,12 @@ func NewHandlerServiceImpl(logger *zap.SugaredLogger,
 	asyncRunnable *async.Runnable,
 	workflowTriggerAuditService service2.WorkflowTriggerAuditService,
 	fluxCdDeploymentService fluxcd.DeploymentService,
-	workflowStatusLatestService workflowStatusLatest.WorkflowStatusLatestService) (*HandlerServiceImpl, error) {
+	workflowStatusLatestService workflowStatusLatest.WorkflowStatusLatestService,
+	imageScanResultReadService read2.ImageScanResultReadService,
+	imageTaggingReadService imageTaggingRead.ImageTaggingReadService,
+	celPolicyService celPolicy.CelPolicyService,
+	deploymentWindowService deploymentWindow.DeploymentWindowService,
+	envConfigOverrideReadService deploymentTemplateRead.EnvConfigOverrideService) (*HandlerServiceImpl, error) {
 	impl := &HandlerServiceImpl{
 		logger:                              logger,
 		cdWorkflowCommonService:             cdWorkflowCommonService,
@@ -302,22 +318,34 @@ func NewHandlerServiceImpl(logger *zap.SugaredLogger,
 		attributeService:         attributeService,
 		cdWorkflowRunnerService:  cdWorkflowRunnerService,
 
-		clusterRepository:           clusterRepository,
-		clusterService:              clusterService,
-		ciLogService:                ciLogService,
-		workflowService:             workflowService,
-		blobConfigStorageService:    blobConfigStorageService,
-		deploymentEventHandler:      deploymentEventHandler,
-		asyncRunnable:               asyncRunnable,
-		workflowTriggerAuditService: workflowTriggerAuditService,
-		fluxCdDeploymentService:     fluxCdDeploymentService,
-		workflowStatusLatestService: workflowStatusLatestService,
+		clusterRepository:            clusterRepository,
+		clusterService:               clusterService,
+		ciLogService:                 ciLogService,
+		workflowService:              workflowService,
+		blobConfigStorageService:     blobConfigStorageService,
+		deploymentEventHandler:       deploymentEventHandler,
+		asyncRunnable:                asyncRunnable,
+		workflowTriggerAuditService:  workflowTriggerAuditService,
+		fluxCdDeploymentService:      fluxCdDeploymentService,
+		workflowStatusLatestService:  workflowStatusLatestService,
+		imageScanResultReadService:   imageScanResultReadService,
+		imageTaggingReadService:      imageTaggingReadService,
+		celPolicyService:             celPolicyService,
+		deploymentWindowService:      deploymentWindowService,
+		envConfigOverrideReadService: envConfigOverrideReadService,
 	}
 	config, err := types.GetCdConfig()
 	if err != nil {
 		return nil, err
 	}
 	impl.config = config
+	feasibilityCheckConfig := &bean.FeasibilityCheckConfig{}
+	err = env.Parse(feasibilityCheckConfig)
+	if err != nil {
+		logger.Errorw("error in parsing feasibility check config", "err", err)
+		return nil, err
+	}
+	impl.feasibilityCheckConfig = feasibilityCheckConfig
 	return impl, nil
 }
 
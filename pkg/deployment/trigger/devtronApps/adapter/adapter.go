@@ -17,8 +17,10 @@
 package adapter
 
 import (
+	"context"
 	apiBean "github.com/devtron-labs/devtron/api/bean"
 	helmBean "github.com/devtron-labs/devtron/api/helm-app/service/bean"
+	"github.com/devtron-labs/devtron/internal/sql/repository"
 	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
 	bean2 "github.com/devtron-labs/devtron/pkg/deployment/common/bean"
 	"github.com/devtron-labs/devtron/pkg/deployment/trigger/devtronApps/bean"
@@ -62,14 +64,28 @@ func NewAppIdentifierFromOverrideRequest(overrideRequest *apiBean.ValuesOverride
 	}
 }
 
-func NewValidateDeploymentTriggerObj(runner *pipelineConfig.CdWorkflowRunner, cdPipeline *pipelineConfig.Pipeline, imageDigest string,
+func NewValidateDeploymentTriggerObj(runner *pipelineConfig.CdWorkflowRunner, cdPipeline *pipelineConfig.Pipeline, artifact *repository.CiArtifact,
 	deploymentConfig *bean2.DeploymentConfig, userId int32, isRollbackDeployment bool) *bean.ValidateDeploymentTriggerObj {
 	return &bean.ValidateDeploymentTriggerObj{
 		Runner:               runner,
 		CdPipeline:           cdPipeline,
-		ImageDigest:          imageDigest,
+		Artifact:             artifact,
+		ImageDigest:          artifact.ImageDigest,
 		DeploymentConfig:     deploymentConfig,
 		TriggeredBy:          userId,
 		IsRollbackDeployment: isRollbackDeployment,
 	}
 }
+
+func NewTriggerRequirementRequest(ctx context.Context, cdPipeline *pipelineConfig.Pipeline, artifact *repository.CiArtifact, triggeredBy int32) *bean.TriggerRequirementRequestDto {
+	return &bean.TriggerRequirementRequestDto{
+		TriggerRequest: bean.CdTriggerRequest{
+			Pipeline:    cdPipeline,
+			Artifact:    artifact,
+			TriggeredBy: triggeredBy,
+			TriggerContext: bean.TriggerContext{
+				Context: ctx,
+			},
+		},
+	}
+}
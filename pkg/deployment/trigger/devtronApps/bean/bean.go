@@ -18,10 +18,12 @@ package bean
 
 import (
 	"context"
+	"fmt"
 	"github.com/devtron-labs/devtron/api/bean"
 	"github.com/devtron-labs/devtron/internal/sql/repository"
 	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
 	bean2 "github.com/devtron-labs/devtron/pkg/deployment/common/bean"
+	"strings"
 	"time"
 )
 
@@ -88,6 +90,59 @@ type TriggerRequirementRequestDto struct {
 	TriggerRequest CdTriggerRequest
 }
 
+// CATEGORY=CD
+type FeasibilityCheckConfig struct {
+	EnableDeploymentFeasibilityCheck    bool     `env:"ENABLE_DEPLOYMENT_FEASIBILITY_CHECK" envDefault:"true" description:"Evaluate scan freshness and required image labels for an artifact before it is deployed, CVE policy is always evaluated"`
+	FeasibilityCheckMaxScanAgeInHours   int      `env:"FEASIBILITY_CHECK_MAX_SCAN_AGE_IN_HOURS" envDefault:"0" description:"Block deployment if the latest image scan of the artifact is older than the defined hours, 0 disables the check"`
+	FeasibilityCheckRequiredImageLabels []string `env:"FEASIBILITY_CHECK_REQUIRED_IMAGE_LABELS" envDefault:"" envSeparator:"," description:"Comma separated list of image labels (release tags) that must be present on an artifact before it is deployed" example:"qa-approved,security-reviewed"`
+}
+
+func (c *FeasibilityCheckConfig) IsScanFreshnessCheckEnabled() bool {
+	return c.FeasibilityCheckMaxScanAgeInHours > 0
+}
+
+func (c *FeasibilityCheckConfig) IsImageLabelCheckEnabled() bool {
+	return len(c.FeasibilityCheckRequiredImageLabels) > 0
+}
+
+type FeasibilityRule string
+
+const (
+	FeasibilityRuleCvePolicy           FeasibilityRule = "CVE_POLICY"
+	FeasibilityRuleScanFreshness       FeasibilityRule = "SCAN_FRESHNESS"
+	FeasibilityRuleRequiredImageLabels FeasibilityRule = "REQUIRED_IMAGE_LABELS"
+	FeasibilityRuleCelPolicy           FeasibilityRule = "CEL_POLICY"
+)
+
+// FeasibilityRuleFailure describes why a single feasibility rule blocked the trigger
+type FeasibilityRuleFailure struct {
+	Rule    FeasibilityRule `json:"rule"`
+	Reason  string          `json:"reason"`
+	Details []string        `json:"details,omitempty"`
+}
+
+// FeasibilityCheckError is returned by FeasibilityManager.CheckFeasibility when one or more rules fail
+type FeasibilityCheckError struct {
+	Failures []*FeasibilityRuleFailure `json:"failures"`
+}
+
+func (e *FeasibilityCheckError) Error() string {
+	reasons := make([]string, 0, len(e.Failures))
+	for _, failure := range e.Failures {
+		reasons = append(reasons, fmt.Sprintf("%s: %s", failure.Rule, failure.Reason))
+	}
+	return fmt.Sprintf("deployment blocked by feasibility check, %s", strings.Join(reasons, "; "))
+}
+
+func (e *FeasibilityCheckError) HasFailedRule(rule FeasibilityRule) bool {
+	for _, failure := range e.Failures {
+		if failure.Rule == rule {
+			return true
+		}
+	}
+	return false
+}
+
 type VulnerabilityCheckRequest struct {
 	ImageDigest string
 	CdPipeline  *pipelineConfig.Pipeline
@@ -110,12 +165,22 @@ const (
 type ValidateDeploymentTriggerObj struct {
 	Runner               *pipelineConfig.CdWorkflowRunner
 	CdPipeline           *pipelineConfig.Pipeline
+	Artifact             *repository.CiArtifact
 	ImageDigest          string
 	DeploymentConfig     *bean2.DeploymentConfig
 	TriggeredBy          int32
 	IsRollbackDeployment bool
+	// DeploymentWindowOverride is set when the user (having the override permission) deploys in spite of the blocking windows
+	DeploymentWindowOverride       bool
+	DeploymentWindowOverrideReason string
 }
 
 func (r *ValidateDeploymentTriggerObj) IsDeploymentTypeRollback() bool {
 	return r.IsRollbackDeployment
 }
+
+func (r *ValidateDeploymentTriggerObj) WithDeploymentWindowOverride(override bool, reason string) *ValidateDeploymentTriggerObj {
+	r.DeploymentWindowOverride = override
+	r.DeploymentWindowOverrideReason = reason
+	return r
+}
@@ -132,24 +132,34 @@ func (impl *HandlerServiceImpl) validateDeploymentTriggerRequest(ctx context.Con
 		return err
 	}
 	// custom GitOps repo url validation --> Ends
-	var isVulnerable bool
-	// if request is for rollback then bypass vulnerability validation
-	if !validateDeploymentTriggerObj.IsDeploymentTypeRollback() {
-		// checking vulnerability for deploying image
-		vulnerabilityCheckRequest := adapter.GetVulnerabilityCheckRequest(validateDeploymentTriggerObj.CdPipeline, validateDeploymentTriggerObj.ImageDigest)
-		isVulnerable, err = impl.imageScanService.GetArtifactVulnerabilityStatus(newCtx, vulnerabilityCheckRequest)
-		if err != nil {
-			impl.logger.Errorw("error in getting Artifact vulnerability status, ManualCdTrigger", "err", err)
+	// deployment windows are enforced for rollback deployments as well
+	err = impl.checkDeploymentWindow(newCtx, validateDeploymentTriggerObj)
+	if err != nil {
+		return err
+	}
+	// if request is for rollback then bypass feasibility validation
+	if validateDeploymentTriggerObj.IsDeploymentTypeRollback() {
+		return nil
+	}
+	// checking cve policy, scan freshness and image labels for deploying image
+	triggerRequirementRequest := adapter.NewTriggerRequirementRequest(newCtx, validateDeploymentTriggerObj.CdPipeline, validateDeploymentTriggerObj.Artifact, validateDeploymentTriggerObj.TriggeredBy)
+	err = impl.CheckFeasibility(triggerRequirementRequest)
+	if err != nil {
+		feasibilityErr := &bean.FeasibilityCheckError{}
+		if !errors.As(err, &feasibilityErr) {
+			impl.logger.Errorw("error in checking deployment feasibility", "err", err)
 			return err
 		}
-	}
-
-	if isVulnerable == true {
-		// if image vulnerable, update timeline status and return
-		if err = impl.cdWorkflowCommonService.MarkCurrentDeploymentFailed(validateDeploymentTriggerObj.Runner, errors.New(cdWorkflow.FOUND_VULNERABILITY), validateDeploymentTriggerObj.TriggeredBy); err != nil {
+		// if feasibility check failed, update timeline status and return
+		failureReason := error(feasibilityErr)
+		if len(feasibilityErr.Failures) == 1 && feasibilityErr.HasFailedRule(bean.FeasibilityRuleCvePolicy) {
+			failureReason = errors.New(cdWorkflow.FOUND_VULNERABILITY)
+		}
+		if err = impl.cdWorkflowCommonService.MarkCurrentDeploymentFailed(validateDeploymentTriggerObj.Runner, failureReason, validateDeploymentTriggerObj.TriggeredBy); err != nil {
 			impl.logger.Errorw("error while updating current runner status to failed, TriggerDeployment", "wfrId", validateDeploymentTriggerObj.Runner.Id, "err", err)
 		}
-		return fmt.Errorf("found vulnerability for image digest %s", validateDeploymentTriggerObj.ImageDigest)
+		return util.NewApiError(http.StatusPreconditionFailed, feasibilityErr.Error(), feasibilityErr.Error()).
+			WithUserMessage(feasibilityErr.Failures)
 	}
 	return nil
 }
@@ -330,7 +340,8 @@ func (impl *HandlerServiceImpl) ManualCdTrigger(triggerContext bean.TriggerConte
 			impl.logger.Errorw("error in creating timeline status for deployment initiation, ManualCdTrigger", "err", err, "timeline", timeline)
 		}
 		if isNotHibernateRequest(overrideRequest.DeploymentType) {
-			validateReqObj := adapter.NewValidateDeploymentTriggerObj(runner, cdPipeline, artifact.ImageDigest, envDeploymentConfig, overrideRequest.UserId, overrideRequest.IsRollbackDeployment)
+			validateReqObj := adapter.NewValidateDeploymentTriggerObj(runner, cdPipeline, artifact, envDeploymentConfig, overrideRequest.UserId, overrideRequest.IsRollbackDeployment).
+				WithDeploymentWindowOverride(overrideRequest.DeploymentWindowOverride, overrideRequest.DeploymentWindowOverrideReason)
 			validationErr := impl.validateDeploymentTriggerRequest(ctx, validateReqObj)
 			if validationErr != nil {
 				impl.logger.Errorw("validation error deployment request", "cdWfr", runner.Id, "err", validationErr)
@@ -500,7 +511,7 @@ func (impl *HandlerServiceImpl) TriggerAutomaticDeployment(request bean.CdTrigge
 		impl.logger.Errorw("error in fetching environment deployment config by appId and envId", "appId", pipeline.AppId, "envId", pipeline.EnvironmentId, "err", err)
 		return err
 	}
-	validationErr := impl.validateDeploymentTriggerRequest(ctx, adapter.NewValidateDeploymentTriggerObj(runner, pipeline, artifact.ImageDigest, envDeploymentConfig, triggeredBy, false))
+	validationErr := impl.validateDeploymentTriggerRequest(ctx, adapter.NewValidateDeploymentTriggerObj(runner, pipeline, artifact, envDeploymentConfig, triggeredBy, false))
 	if validationErr != nil {
 		impl.logger.Errorw("validation error deployment request", "cdWfr", runner.Id, "err", validationErr)
 		return validationErr
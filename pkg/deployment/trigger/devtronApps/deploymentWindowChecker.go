@@ -0,0 +1,72 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package devtronApps
+
+import (
+	"context"
+	"errors"
+	"github.com/devtron-labs/devtron/internal/constants"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/deployment/trigger/devtronApps/bean"
+	deploymentWindowBean "github.com/devtron-labs/devtron/pkg/policyGovernance/deploymentWindow/bean"
+	"go.opentelemetry.io/otel"
+	"net/http"
+	"time"
+)
+
+// checkDeploymentWindow blocks the deployment if a blackout window is active or none of the maintenance windows
+// applicable on the pipeline is active. An override requested by the user (permission is enforced at the api layer)
+// lets the deployment through and is recorded in the override audit.
+func (impl *HandlerServiceImpl) checkDeploymentWindow(ctx context.Context, validateDeploymentTriggerObj *bean.ValidateDeploymentTriggerObj) error {
+	newCtx, span := otel.Tracer("orchestrator").Start(ctx, "HandlerServiceImpl.checkDeploymentWindow")
+	defer span.End()
+	cdPipeline := validateDeploymentTriggerObj.CdPipeline
+	runner := validateDeploymentTriggerObj.Runner
+	scope := &deploymentWindowBean.WindowEvaluationScope{AppId: cdPipeline.AppId, EnvId: cdPipeline.EnvironmentId}
+	state, err := impl.deploymentWindowService.GetDeploymentWindowState(newCtx, scope, time.Now())
+	if err != nil {
+		impl.logger.Errorw("error in evaluating deployment windows", "pipelineId", cdPipeline.Id, "err", err)
+		return err
+	}
+	if state.IsDeploymentAllowed {
+		return nil
+	}
+	if validateDeploymentTriggerObj.DeploymentWindowOverride {
+		override := &deploymentWindowBean.DeploymentWindowOverride{
+			PipelineId: cdPipeline.Id,
+			AppId:      cdPipeline.AppId,
+			EnvId:      cdPipeline.EnvironmentId,
+			WfrId:      runner.Id,
+			Reason:     validateDeploymentTriggerObj.DeploymentWindowOverrideReason,
+			UserId:     validateDeploymentTriggerObj.TriggeredBy,
+			Windows:    state.BlockingWindows(),
+		}
+		err = impl.deploymentWindowService.SaveOverrideAudit(override)
+		if err != nil {
+			impl.logger.Errorw("error in saving deployment window override audit", "pipelineId", cdPipeline.Id, "wfrId", runner.Id, "err", err)
+			return err
+		}
+		impl.logger.Infow("deployment window overridden", "pipelineId", cdPipeline.Id, "wfrId", runner.Id, "userId", validateDeploymentTriggerObj.TriggeredBy)
+		return nil
+	}
+	blockedReason := state.GetBlockedReason()
+	impl.logger.Infow("deployment trigger blocked by deployment window", "pipelineId", cdPipeline.Id, "wfrId", runner.Id, "reason", blockedReason)
+	if err = impl.cdWorkflowCommonService.MarkCurrentDeploymentFailed(runner, errors.New(blockedReason), validateDeploymentTriggerObj.TriggeredBy); err != nil {
+		impl.logger.Errorw("error while updating current runner status to failed, checkDeploymentWindow", "wfrId", runner.Id, "err", err)
+	}
+	return util.NewApiError(http.StatusPreconditionFailed, blockedReason, blockedReason).WithCode(constants.DeploymentWindowFail)
+}
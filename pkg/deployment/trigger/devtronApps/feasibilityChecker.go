@@ -17,14 +17,267 @@
 package devtronApps
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/devtron-labs/devtron/cel"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/deployment/trigger/devtronApps/adapter"
 	"github.com/devtron-labs/devtron/pkg/deployment/trigger/devtronApps/bean"
+	celPolicyBean "github.com/devtron-labs/devtron/pkg/policyGovernance/celPolicy/bean"
+	securityBean "github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageScanning/repository/bean"
+	errors2 "github.com/juju/errors"
+	"go.opentelemetry.io/otel"
+	"golang.org/x/exp/slices"
+	"time"
 )
 
 type FeasibilityManager interface {
+	// CheckFeasibility evaluates the artifact of the trigger request against the pre-deployment rules
+	// (CVE policy, scan freshness, required image labels and the CEL policies of CD_TRIGGER hook).
+	// CVE policy is always enforced, scan freshness and image labels only when ENABLE_DEPLOYMENT_FEASIBILITY_CHECK is set.
+	// returns *bean.FeasibilityCheckError with all the failed rules if the trigger is to be blocked
 	CheckFeasibility(triggerRequirementRequest *bean.TriggerRequirementRequestDto) error
 }
 
 func (impl *HandlerServiceImpl) CheckFeasibility(triggerRequirementRequest *bean.TriggerRequirementRequestDto) error {
-	// have not implemented right now, will be implemented in future for security vulnerability
+	triggerRequest := triggerRequirementRequest.TriggerRequest
+	if triggerRequest.Pipeline == nil || triggerRequest.Artifact == nil {
+		return fmt.Errorf("invalid feasibility check request, pipeline and artifact are required")
+	}
+	ctx := triggerRequest.TriggerContext.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	newCtx, span := otel.Tracer("orchestrator").Start(ctx, "HandlerServiceImpl.CheckFeasibility")
+	defer span.End()
+	failures, err := impl.checkBuiltInRulesFeasibility(newCtx, triggerRequest)
+	if err != nil {
+		return err
+	}
+	celPolicyFailure, err := impl.checkCelPolicyFeasibility(newCtx, triggerRequest)
+	if err != nil {
+		impl.logger.Errorw("error in checking cel policy feasibility", "pipelineId", triggerRequest.Pipeline.Id, "artifactId", triggerRequest.Artifact.Id, "err", err)
+		return err
+	}
+	if celPolicyFailure != nil {
+		failures = append(failures, celPolicyFailure)
+	}
+	if len(failures) > 0 {
+		impl.logger.Infow("deployment trigger blocked by feasibility check", "pipelineId", triggerRequest.Pipeline.Id, "artifactId", triggerRequest.Artifact.Id, "failures", failures)
+		return &bean.FeasibilityCheckError{Failures: failures}
+	}
 	return nil
 }
+
+func (impl *HandlerServiceImpl) checkBuiltInRulesFeasibility(ctx context.Context, triggerRequest bean.CdTriggerRequest) ([]*bean.FeasibilityRuleFailure, error) {
+	var failures []*bean.FeasibilityRuleFailure
+	cvePolicyFailure, err := impl.checkCvePolicyFeasibility(ctx, triggerRequest.Pipeline, triggerRequest.Artifact.ImageDigest)
+	if err != nil {
+		impl.logger.Errorw("error in checking cve policy feasibility", "pipelineId", triggerRequest.Pipeline.Id, "artifactId", triggerRequest.Artifact.Id, "err", err)
+		return nil, err
+	}
+	if cvePolicyFailure != nil {
+		failures = append(failures, cvePolicyFailure)
+	}
+	if !impl.feasibilityCheckConfig.EnableDeploymentFeasibilityCheck {
+		return failures, nil
+	}
+	if impl.feasibilityCheckConfig.IsScanFreshnessCheckEnabled() {
+		scanFreshnessFailure, err := impl.checkScanFreshnessFeasibility(triggerRequest.Artifact.Image, triggerRequest.Artifact.ImageDigest)
+		if err != nil {
+			impl.logger.Errorw("error in checking scan freshness feasibility", "artifactId", triggerRequest.Artifact.Id, "err", err)
+			return nil, err
+		}
+		if scanFreshnessFailure != nil {
+			failures = append(failures, scanFreshnessFailure)
+		}
+	}
+	if impl.feasibilityCheckConfig.IsImageLabelCheckEnabled() {
+		imageLabelFailure, err := impl.checkImageLabelFeasibility(triggerRequest.Artifact.Id)
+		if err != nil {
+			impl.logger.Errorw("error in checking image label feasibility", "artifactId", triggerRequest.Artifact.Id, "err", err)
+			return nil, err
+		}
+		if imageLabelFailure != nil {
+			failures = append(failures, imageLabelFailure)
+		}
+	}
+	return failures, nil
+}
+
+func (impl *HandlerServiceImpl) checkCvePolicyFeasibility(ctx context.Context, cdPipeline *pipelineConfig.Pipeline, imageDigest string) (*bean.FeasibilityRuleFailure, error) {
+	vulnerabilityCheckRequest := adapter.GetVulnerabilityCheckRequest(cdPipeline, imageDigest)
+	blockedCves, err := impl.imageScanService.GetArtifactBlockedCveList(ctx, vulnerabilityCheckRequest)
+	if err != nil {
+		impl.logger.Errorw("error in getting blocked cve list of artifact", "pipelineId", cdPipeline.Id, "imageDigest", imageDigest, "err", err)
+		return nil, err
+	}
+	if len(blockedCves) == 0 {
+		return nil, nil
+	}
+	details := make([]string, 0, len(blockedCves))
+	for _, blockedCve := range blockedCves {
+		details = append(details, fmt.Sprintf("%s (%s)", blockedCve.Name, blockedCve.GetSeverity().String()))
+	}
+	return &bean.FeasibilityRuleFailure{
+		Rule:    bean.FeasibilityRuleCvePolicy,
+		Reason:  fmt.Sprintf("image has %d vulnerabilities blocked by the applicable cve policy", len(blockedCves)),
+		Details: details,
+	}, nil
+}
+
+func (impl *HandlerServiceImpl) checkScanFreshnessFeasibility(image, imageDigest string) (*bean.FeasibilityRuleFailure, error) {
+	scanHistory, err := impl.imageScanHistoryReadService.FindByImageAndDigest(imageDigest, image)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching image scan history", "image", image, "imageDigest", imageDigest, "err", err)
+		return nil, err
+	}
+	if util.IsErrNoRows(err) || scanHistory == nil || scanHistory.Id == 0 {
+		return &bean.FeasibilityRuleFailure{
+			Rule:   bean.FeasibilityRuleScanFreshness,
+			Reason: "image has not been scanned",
+		}, nil
+	}
+	maxScanAge := time.Duration(impl.feasibilityCheckConfig.FeasibilityCheckMaxScanAgeInHours) * time.Hour
+	if scanAge := time.Since(scanHistory.ExecutionTime); scanAge > maxScanAge {
+		return &bean.FeasibilityRuleFailure{
+			Rule:    bean.FeasibilityRuleScanFreshness,
+			Reason:  fmt.Sprintf("latest image scan is older than %d hours", impl.feasibilityCheckConfig.FeasibilityCheckMaxScanAgeInHours),
+			Details: []string{fmt.Sprintf("last scanned at %s", scanHistory.ExecutionTime.Format(time.RFC3339))},
+		}, nil
+	}
+	return nil, nil
+}
+
+func (impl *HandlerServiceImpl) checkImageLabelFeasibility(artifactId int) (*bean.FeasibilityRuleFailure, error) {
+	imageLabels, err := impl.imageTaggingReadService.GetTagNamesByArtifactId(artifactId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching image labels for artifact", "artifactId", artifactId, "err", err)
+		return nil, err
+	}
+	var missingLabels []string
+	for _, requiredLabel := range impl.feasibilityCheckConfig.FeasibilityCheckRequiredImageLabels {
+		if !slices.Contains(imageLabels, requiredLabel) {
+			missingLabels = append(missingLabels, requiredLabel)
+		}
+	}
+	if len(missingLabels) == 0 {
+		return nil, nil
+	}
+	return &bean.FeasibilityRuleFailure{
+		Rule:    bean.FeasibilityRuleRequiredImageLabels,
+		Reason:  "image is missing required labels",
+		Details: missingLabels,
+	}, nil
+}
+
+func (impl *HandlerServiceImpl) checkCelPolicyFeasibility(ctx context.Context, triggerRequest bean.CdTriggerRequest) (*bean.FeasibilityRuleFailure, error) {
+	evaluationRequest := &celPolicyBean.EvaluationRequest{
+		Hook: celPolicyBean.CdTriggerHook,
+		Scope: &celPolicyBean.PolicyEvaluationScope{
+			AppId: triggerRequest.Pipeline.AppId,
+			EnvId: triggerRequest.Pipeline.EnvironmentId,
+		},
+		ParamsProvider: func() ([]cel.ExpressionParam, error) {
+			return impl.getCelPolicyParamsForCdTrigger(triggerRequest)
+		},
+	}
+	_, err := impl.celPolicyService.EvaluatePolicies(ctx, evaluationRequest)
+	policyViolationErr := &celPolicyBean.PolicyViolationError{}
+	if errors.As(err, &policyViolationErr) {
+		details := make([]string, 0, len(policyViolationErr.Violations))
+		for _, violation := range policyViolationErr.Violations {
+			details = append(details, fmt.Sprintf("%s: %s", violation.PolicyName, violation.Message))
+		}
+		return &bean.FeasibilityRuleFailure{
+			Rule:    bean.FeasibilityRuleCelPolicy,
+			Reason:  fmt.Sprintf("deployment violates %d policies", len(policyViolationErr.Violations)),
+			Details: details,
+		}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+func (impl *HandlerServiceImpl) getCelPolicyParamsForCdTrigger(triggerRequest bean.CdTriggerRequest) ([]cel.ExpressionParam, error) {
+	cdPipeline, artifact := triggerRequest.Pipeline, triggerRequest.Artifact
+	imageLabels, err := impl.imageTaggingReadService.GetTagNamesByArtifactId(artifact.Id)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching image labels for artifact", "artifactId", artifact.Id, "err", err)
+		return nil, err
+	}
+	if imageLabels == nil {
+		imageLabels = make([]string, 0)
+	}
+	severityCount, err := impl.getVulnerabilityCountBySeverity(artifact.ImageDigest)
+	if err != nil {
+		return nil, err
+	}
+	chartName, chartVersion, err := impl.getChartNameAndVersionForEnv(cdPipeline.AppId, cdPipeline.EnvironmentId)
+	if err != nil {
+		return nil, err
+	}
+	triggeredBy, err := impl.userService.GetEmailById(triggerRequest.TriggeredBy)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching user email", "userId", triggerRequest.TriggeredBy, "err", err)
+		return nil, err
+	}
+	containerRepository, containerImageTag, err := artifact.ExtractImageRepoAndTag()
+	if err != nil {
+		impl.logger.Errorw("error in getting image tag and repo", "image", artifact.Image, "err", err)
+	}
+	return []cel.ExpressionParam{
+		{ParamName: cel.CdPipelineName, Value: cdPipeline.Name, Type: cel.ParamTypeString},
+		{ParamName: cel.CdPipelineTriggerType, Value: cdPipeline.TriggerType.ToString(), Type: cel.ParamTypeString},
+		{ParamName: cel.ContainerRepo, Value: containerRepository, Type: cel.ParamTypeString},
+		{ParamName: cel.ContainerImage, Value: artifact.Image, Type: cel.ParamTypeString},
+		{ParamName: cel.ContainerImageTag, Value: containerImageTag, Type: cel.ParamTypeString},
+		{ParamName: cel.ImageLabels, Value: imageLabels, Type: cel.ParamTypeList},
+		{ParamName: cel.CriticalVulnerabilityCount, Value: severityCount[securityBean.Critical], Type: cel.ParamTypeInteger},
+		{ParamName: cel.HighVulnerabilityCount, Value: severityCount[securityBean.High], Type: cel.ParamTypeInteger},
+		{ParamName: cel.MediumVulnerabilityCount, Value: severityCount[securityBean.Medium], Type: cel.ParamTypeInteger},
+		{ParamName: cel.LowVulnerabilityCount, Value: severityCount[securityBean.Low], Type: cel.ParamTypeInteger},
+		{ParamName: cel.ChartName, Value: chartName, Type: cel.ParamTypeString},
+		{ParamName: cel.ChartVersion, Value: chartVersion, Type: cel.ParamTypeString},
+		{ParamName: cel.TriggeredBy, Value: triggeredBy, Type: cel.ParamTypeString},
+	}, nil
+}
+
+// getChartNameAndVersionForEnv returns the chart of the environment override if overridden, else the base chart of the app
+func (impl *HandlerServiceImpl) getChartNameAndVersionForEnv(appId, envId int) (string, string, error) {
+	envOverride, err := impl.envConfigOverrideReadService.FindLatestChartForAppByAppIdAndEnvId(nil, appId, envId)
+	if err != nil && !errors2.IsNotFound(err) {
+		impl.logger.Errorw("error in fetching env config override", "appId", appId, "envId", envId, "err", err)
+		return "", "", err
+	}
+	if envOverride.IsOverridden() && envOverride.Chart != nil {
+		return envOverride.Chart.ChartName, envOverride.Chart.ChartVersion, nil
+	}
+	chart, err := impl.chartRepository.FindLatestChartForAppByAppId(nil, appId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching latest chart for app", "appId", appId, "err", err)
+		return "", "", err
+	} else if chart == nil {
+		return "", "", nil
+	}
+	return chart.ChartName, chart.ChartVersion, nil
+}
+
+func (impl *HandlerServiceImpl) getVulnerabilityCountBySeverity(imageDigest string) (map[securityBean.Severity]int, error) {
+	severityCount := make(map[securityBean.Severity]int)
+	if len(imageDigest) == 0 {
+		return severityCount, nil
+	}
+	imageScanResults, err := impl.imageScanResultReadService.FindByImageDigests([]string{imageDigest})
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching image scan results", "imageDigest", imageDigest, "err", err)
+		return nil, err
+	}
+	for _, imageScanResult := range imageScanResults {
+		severityCount[imageScanResult.CveStore.GetSeverity()]++
+	}
+	return severityCount, nil
+}
@@ -0,0 +1,190 @@
+package devtronApps
+
+import (
+	"context"
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/util"
+	imageTaggingRead "github.com/devtron-labs/devtron/pkg/build/artifacts/imageTagging/read"
+	"github.com/devtron-labs/devtron/pkg/deployment/trigger/devtronApps/bean"
+	security2 "github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageScanning"
+	imageScanningRead "github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageScanning/read"
+	repository6 "github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageScanning/repository"
+	securityBean "github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageScanning/repository/bean"
+	"github.com/go-pg/pg"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+type imageTaggingReadServiceStub struct {
+	imageTaggingRead.ImageTaggingReadService
+	tags []string
+}
+
+func (stub *imageTaggingReadServiceStub) GetTagNamesByArtifactId(artifactId int) ([]string, error) {
+	return stub.tags, nil
+}
+
+type imageScanServiceStub struct {
+	security2.ImageScanService
+	blockedCves []*repository6.CveStore
+}
+
+func (stub *imageScanServiceStub) GetArtifactBlockedCveList(ctx context.Context, request *bean.VulnerabilityCheckRequest) ([]*repository6.CveStore, error) {
+	return stub.blockedCves, nil
+}
+
+type imageScanHistoryReadServiceStub struct {
+	imageScanningRead.ImageScanHistoryReadService
+	scanHistory *repository6.ImageScanExecutionHistory
+}
+
+func (stub *imageScanHistoryReadServiceStub) FindByImageAndDigest(imageDigest string, image string) (*repository6.ImageScanExecutionHistory, error) {
+	if stub.scanHistory == nil {
+		return nil, pg.ErrNoRows
+	}
+	return stub.scanHistory, nil
+}
+
+func TestCheckScanFreshnessFeasibility(t *testing.T) {
+	logger, err := util.NewSugardLogger()
+	assert.Nil(t, err)
+	newHandlerService := func(scanHistory *repository6.ImageScanExecutionHistory) *HandlerServiceImpl {
+		return &HandlerServiceImpl{
+			logger:                      logger,
+			imageScanHistoryReadService: &imageScanHistoryReadServiceStub{scanHistory: scanHistory},
+			feasibilityCheckConfig: &bean.FeasibilityCheckConfig{
+				EnableDeploymentFeasibilityCheck:  true,
+				FeasibilityCheckMaxScanAgeInHours: 24,
+			},
+		}
+	}
+
+	t.Run("image not scanned", func(t *testing.T) {
+		impl := newHandlerService(nil)
+		failure, err := impl.checkScanFreshnessFeasibility("quay.io/devtron/test:v1", "sha256:abc")
+		assert.Nil(t, err)
+		assert.NotNil(t, failure)
+		assert.Equal(t, bean.FeasibilityRuleScanFreshness, failure.Rule)
+		assert.Equal(t, "image has not been scanned", failure.Reason)
+	})
+
+	t.Run("latest scan older than max scan age", func(t *testing.T) {
+		impl := newHandlerService(&repository6.ImageScanExecutionHistory{Id: 1, ExecutionTime: time.Now().Add(-48 * time.Hour)})
+		failure, err := impl.checkScanFreshnessFeasibility("quay.io/devtron/test:v1", "sha256:abc")
+		assert.Nil(t, err)
+		assert.NotNil(t, failure)
+		assert.Equal(t, bean.FeasibilityRuleScanFreshness, failure.Rule)
+		assert.Equal(t, "latest image scan is older than 24 hours", failure.Reason)
+	})
+
+	t.Run("latest scan within max scan age", func(t *testing.T) {
+		impl := newHandlerService(&repository6.ImageScanExecutionHistory{Id: 1, ExecutionTime: time.Now().Add(-time.Hour)})
+		failure, err := impl.checkScanFreshnessFeasibility("quay.io/devtron/test:v1", "sha256:abc")
+		assert.Nil(t, err)
+		assert.Nil(t, failure)
+	})
+}
+
+func TestCheckImageLabelFeasibility(t *testing.T) {
+	logger, err := util.NewSugardLogger()
+	assert.Nil(t, err)
+	newHandlerService := func(tags []string, requiredLabels []string) *HandlerServiceImpl {
+		return &HandlerServiceImpl{
+			logger:                  logger,
+			imageTaggingReadService: &imageTaggingReadServiceStub{tags: tags},
+			feasibilityCheckConfig: &bean.FeasibilityCheckConfig{
+				EnableDeploymentFeasibilityCheck:    true,
+				FeasibilityCheckRequiredImageLabels: requiredLabels,
+			},
+		}
+	}
+
+	t.Run("all required labels present", func(t *testing.T) {
+		impl := newHandlerService([]string{"qa-approved", "security-reviewed", "v1.2.0"}, []string{"qa-approved", "security-reviewed"})
+		failure, err := impl.checkImageLabelFeasibility(1)
+		assert.Nil(t, err)
+		assert.Nil(t, failure)
+	})
+
+	t.Run("missing required labels", func(t *testing.T) {
+		impl := newHandlerService([]string{"qa-approved"}, []string{"qa-approved", "security-reviewed"})
+		failure, err := impl.checkImageLabelFeasibility(1)
+		assert.Nil(t, err)
+		assert.NotNil(t, failure)
+		assert.Equal(t, bean.FeasibilityRuleRequiredImageLabels, failure.Rule)
+		assert.Equal(t, []string{"security-reviewed"}, failure.Details)
+	})
+}
+
+func TestFeasibilityCheckError(t *testing.T) {
+	feasibilityErr := &bean.FeasibilityCheckError{
+		Failures: []*bean.FeasibilityRuleFailure{
+			{Rule: bean.FeasibilityRuleCvePolicy, Reason: "image has 2 vulnerabilities blocked by the applicable cve policy"},
+			{Rule: bean.FeasibilityRuleScanFreshness, Reason: "image has not been scanned"},
+		},
+	}
+	assert.True(t, feasibilityErr.HasFailedRule(bean.FeasibilityRuleScanFreshness))
+	assert.False(t, feasibilityErr.HasFailedRule(bean.FeasibilityRuleRequiredImageLabels))
+	assert.Equal(t, "deployment blocked by feasibility check, CVE_POLICY: image has 2 vulnerabilities blocked by the applicable cve policy; SCAN_FRESHNESS: image has not been scanned", feasibilityErr.Error())
+}
+
+func TestCheckBuiltInRulesFeasibility(t *testing.T) {
+	logger, err := util.NewSugardLogger()
+	assert.Nil(t, err)
+	criticalSeverity := securityBean.Critical
+	blockedCves := []*repository6.CveStore{
+		{Name: "CVE-2024-0001", StandardSeverity: &criticalSeverity},
+		{Name: "CVE-2024-0002", Severity: securityBean.High},
+	}
+	newHandlerService := func(blockedCves []*repository6.CveStore, tags []string, enableFeasibilityCheck bool) *HandlerServiceImpl {
+		return &HandlerServiceImpl{
+			logger:                  logger,
+			imageScanService:        &imageScanServiceStub{blockedCves: blockedCves},
+			imageTaggingReadService: &imageTaggingReadServiceStub{tags: tags},
+			feasibilityCheckConfig: &bean.FeasibilityCheckConfig{
+				EnableDeploymentFeasibilityCheck:    enableFeasibilityCheck,
+				FeasibilityCheckRequiredImageLabels: []string{"qa-approved"},
+			},
+		}
+	}
+	triggerRequest := bean.CdTriggerRequest{
+		Pipeline: &pipelineConfig.Pipeline{Id: 1, AppId: 1, EnvironmentId: 1},
+		Artifact: &repository.CiArtifact{Id: 1, Image: "quay.io/devtron/test:v1", ImageDigest: "sha256:abc"},
+	}
+
+	t.Run("cve policy blocks the image", func(t *testing.T) {
+		impl := newHandlerService(blockedCves, []string{"qa-approved"}, true)
+		failures, err := impl.checkBuiltInRulesFeasibility(context.Background(), triggerRequest)
+		assert.Nil(t, err)
+		assert.Len(t, failures, 1)
+		assert.Equal(t, bean.FeasibilityRuleCvePolicy, failures[0].Rule)
+		assert.Equal(t, []string{"CVE-2024-0001 (critical)", "CVE-2024-0002 (high)"}, failures[0].Details)
+	})
+
+	t.Run("no blocked cve and labels present", func(t *testing.T) {
+		impl := newHandlerService(nil, []string{"qa-approved"}, true)
+		failures, err := impl.checkBuiltInRulesFeasibility(context.Background(), triggerRequest)
+		assert.Nil(t, err)
+		assert.Empty(t, failures)
+	})
+
+	t.Run("cve policy and image labels both fail", func(t *testing.T) {
+		impl := newHandlerService(blockedCves, nil, true)
+		failures, err := impl.checkBuiltInRulesFeasibility(context.Background(), triggerRequest)
+		assert.Nil(t, err)
+		assert.Len(t, failures, 2)
+		feasibilityErr := &bean.FeasibilityCheckError{Failures: failures}
+		assert.True(t, feasibilityErr.HasFailedRule(bean.FeasibilityRuleCvePolicy))
+		assert.True(t, feasibilityErr.HasFailedRule(bean.FeasibilityRuleRequiredImageLabels))
+	})
+
+	t.Run("cve policy is enforced when feasibility check is disabled", func(t *testing.T) {
+		impl := newHandlerService(blockedCves, nil, false)
+		failures, err := impl.checkBuiltInRulesFeasibility(context.Background(), triggerRequest)
+		assert.Nil(t, err)
+		assert.Len(t, failures, 1)
+		assert.Equal(t, bean.FeasibilityRuleCvePolicy, failures[0].Rule)
+	})
+}
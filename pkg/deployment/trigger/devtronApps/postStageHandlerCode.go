@@ -44,7 +44,7 @@ func (impl *HandlerServiceImpl) TriggerPostStage(request bean.CdTriggerRequest)
 	}
 	request.RunStageInEnvNamespace = namespace
 
-	cdWf, runner, err := impl.createStartingWfAndRunner(request, triggeredAt)
+	cdWf, runner, err := impl.createStartingWfAndRunner(request, env, triggeredAt)
 	if err != nil {
 		impl.logger.Errorw("error in creating wf starting and runner entry", "err", err, "request", request)
 		return nil, err
@@ -98,10 +98,10 @@ func (impl *HandlerServiceImpl) TriggerPostStage(request bean.CdTriggerRequest)
 	}
 	// custom GitOps repo url validation --> Ends
 
-	// checking vulnerability for the selected image
-	err = impl.checkVulnerabilityStatusAndFailWfIfNeeded(ctx, cdWf.CiArtifact, pipeline, runner, triggeredBy)
+	// checking feasibility for the selected image
+	err = impl.checkFeasibilityAndFailWfIfNeeded(ctx, cdWf.CiArtifact, pipeline, runner, triggeredBy)
 	if err != nil {
-		impl.logger.Errorw("error, checkVulnerabilityStatusAndFailWfIfNeeded", "err", err, "runner", runner)
+		impl.logger.Errorw("error, checkFeasibilityAndFailWfIfNeeded", "err", err, "runner", runner)
 		return nil, err
 	}
 	cdStageWorkflowRequest, err := impl.preparePrePostCdWorkflowRequest(ctx, runner, cdWf, request, env, types.POST, envDevploymentConfig)
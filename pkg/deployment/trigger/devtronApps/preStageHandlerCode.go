@@ -19,6 +19,7 @@ package devtronApps
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/argoproj/argo-workflows/v3/pkg/apis/workflow/v1alpha1"
 	blob_storage "github.com/devtron-labs/common-lib/blob-storage"
@@ -36,6 +37,7 @@ import (
 	bean4 "github.com/devtron-labs/devtron/pkg/bean"
 	"github.com/devtron-labs/devtron/pkg/bean/common"
 	buildCommonBean "github.com/devtron-labs/devtron/pkg/build/pipeline/bean/common"
+	clusterBean "github.com/devtron-labs/devtron/pkg/cluster/bean"
 	repository4 "github.com/devtron-labs/devtron/pkg/cluster/environment/repository"
 	bean5 "github.com/devtron-labs/devtron/pkg/deployment/common/bean"
 	adapter2 "github.com/devtron-labs/devtron/pkg/deployment/trigger/devtronApps/adapter"
@@ -55,6 +57,7 @@ import (
 	"github.com/devtron-labs/devtron/util/sliceUtil"
 	"github.com/go-pg/pg"
 	"go.opentelemetry.io/otel"
+	"net/http"
 	"path"
 	"strconv"
 	"strings"
@@ -110,7 +113,7 @@ func (impl *HandlerServiceImpl) TriggerPreStage(request bean.CdTriggerRequest) (
 		return nil, nil
 	}
 
-	cdWf, runner, err := impl.createStartingWfAndRunner(request, triggeredAt)
+	cdWf, runner, err := impl.createStartingWfAndRunner(request, env, triggeredAt)
 	if err != nil {
 		impl.logger.Errorw("error in creating wf starting and runner entry", "err", err, "request", request)
 		return nil, err
@@ -145,10 +148,10 @@ func (impl *HandlerServiceImpl) TriggerPreStage(request bean.CdTriggerRequest) (
 	}
 	// custom GitOps repo url validation --> Ends
 
-	//checking vulnerability for the selected image
-	err = impl.checkVulnerabilityStatusAndFailWfIfNeeded(ctx, artifact, pipeline, runner, triggeredBy)
+	// checking feasibility for the selected image
+	err = impl.checkFeasibilityAndFailWfIfNeeded(ctx, artifact, pipeline, runner, triggeredBy)
 	if err != nil {
-		impl.logger.Errorw("error, checkVulnerabilityStatusAndFailWfIfNeeded", "err", err, "runner", runner)
+		impl.logger.Errorw("error, checkFeasibilityAndFailWfIfNeeded", "err", err, "runner", runner)
 		return nil, err
 	}
 	cdStageWorkflowRequest, err := impl.preparePrePostCdWorkflowRequest(ctx, runner, cdWf, request, env, types.PRE, envDeploymentConfig)
@@ -276,14 +279,18 @@ func (impl *HandlerServiceImpl) checkDeploymentTriggeredAlready(wfId int) bool {
 	return deploymentTriggeredAlready
 }
 
-func (impl *HandlerServiceImpl) createStartingWfAndRunner(request bean.CdTriggerRequest, triggeredAt time.Time) (*pipelineConfig.CdWorkflow, *pipelineConfig.CdWorkflowRunner, error) {
+func (impl *HandlerServiceImpl) createStartingWfAndRunner(request bean.CdTriggerRequest, env *repository4.Environment, triggeredAt time.Time) (*pipelineConfig.CdWorkflow, *pipelineConfig.CdWorkflowRunner, error) {
 	triggeredBy := request.TriggeredBy
 	artifact := request.Artifact
 	pipeline := request.Pipeline
 	ctx := request.TriggerContext.Context
 	//in case of pre stage manual trigger auth is already applied and for auto triggers there is no need for auth check here
 	cdWf := request.CdWf
-	var err error
+	executorType, err := impl.getWorkflowExecutorType(request, env)
+	if err != nil {
+		impl.logger.Errorw("error in getting workflow executor type", "pipelineId", pipeline.Id, "stage", request.WorkflowType, "err", err)
+		return nil, nil, err
+	}
 	if cdWf == nil && request.WorkflowType == apiBean.CD_WORKFLOW_TYPE_PRE {
 		cdWf = &pipelineConfig.CdWorkflow{
 			CiArtifactId: artifact.Id,
@@ -298,7 +305,7 @@ func (impl *HandlerServiceImpl) createStartingWfAndRunner(request bean.CdTrigger
 	runner := &pipelineConfig.CdWorkflowRunner{
 		Name:                  pipeline.Name,
 		WorkflowType:          request.WorkflowType,
-		ExecutorType:          impl.config.GetWorkflowExecutorType(),
+		ExecutorType:          executorType,
 		Status:                cdWorkflow.WorkflowStarting, // starting PreStage
 		PodStatus:             string(v1alpha1.NodePending),
 		TriggeredBy:           triggeredBy,
@@ -324,17 +331,10 @@ func (impl *HandlerServiceImpl) createStartingWfAndRunner(request bean.CdTrigger
 }
 
 func (impl *HandlerServiceImpl) getEnvAndNsIfRunStageInEnv(ctx context.Context, request bean.CdTriggerRequest) (*repository4.Environment, string, error) {
-	workflowStage := request.WorkflowType
 	pipeline := request.Pipeline
 	var env *repository4.Environment
 	var err error
 	namespace := impl.config.GetDefaultNamespace()
-	runStageInEnv := false
-	if workflowStage == apiBean.CD_WORKFLOW_TYPE_PRE {
-		runStageInEnv = pipeline.RunPreStageInEnv
-	} else if workflowStage == apiBean.CD_WORKFLOW_TYPE_POST {
-		runStageInEnv = pipeline.RunPostStageInEnv
-	}
 	_, span := otel.Tracer("orchestrator").Start(ctx, "envRepository.FindById")
 	env, err = impl.envRepository.FindById(pipeline.EnvironmentId)
 	span.End()
@@ -342,36 +342,55 @@ func (impl *HandlerServiceImpl) getEnvAndNsIfRunStageInEnv(ctx context.Context,
 		impl.logger.Errorw(" unable to find env ", "err", err)
 		return nil, namespace, err
 	}
-	if runStageInEnv {
+	if request.Pipeline.IsStageRunInEnv(request.WorkflowType) {
 		namespace = env.Namespace
 	}
 	return env, namespace, nil
 }
 
-func (impl *HandlerServiceImpl) checkVulnerabilityStatusAndFailWfIfNeeded(ctx context.Context, artifact *repository.CiArtifact,
-	cdPipeline *pipelineConfig.Pipeline, runner *pipelineConfig.CdWorkflowRunner, triggeredBy int32) error {
-	//checking vulnerability for the selected image
-	vulnerabilityCheckRequest := adapter2.GetVulnerabilityCheckRequest(cdPipeline, artifact.ImageDigest)
-	isVulnerable, err := impl.imageScanService.GetArtifactVulnerabilityStatus(ctx, vulnerabilityCheckRequest)
+// getWorkflowExecutorType returns the executor type of the cluster the pre/post cd stage runs in,
+// the stage runs in the cluster of the environment if it runs in the environment and in the default cluster otherwise
+func (impl *HandlerServiceImpl) getWorkflowExecutorType(request bean.CdTriggerRequest, env *repository4.Environment) (cdWorkflow.WorkflowExecutorType, error) {
+	if request.Pipeline.IsStageRunInEnv(request.WorkflowType) && env != nil && env.Cluster != nil {
+		return impl.config.GetWorkflowExecutorTypeForCluster(env.Cluster.WorkflowExecutorType), nil
+	}
+	defaultCluster, err := impl.clusterRepository.FindById(clusterBean.DefaultClusterId)
 	if err != nil {
-		impl.logger.Errorw("error in getting Artifact vulnerability status, TriggerPreStage", "err", err)
+		impl.logger.Errorw("error in getting default cluster", "err", err)
+		return "", err
+	}
+	return impl.config.GetWorkflowExecutorTypeForCluster(defaultCluster.WorkflowExecutorType), nil
+}
+
+func (impl *HandlerServiceImpl) checkFeasibilityAndFailWfIfNeeded(ctx context.Context, artifact *repository.CiArtifact,
+	cdPipeline *pipelineConfig.Pipeline, runner *pipelineConfig.CdWorkflowRunner, triggeredBy int32) error {
+	// checking cve policy, scan freshness and image labels for the selected image
+	triggerRequirementRequest := adapter2.NewTriggerRequirementRequest(ctx, cdPipeline, artifact, triggeredBy)
+	err := impl.CheckFeasibility(triggerRequirementRequest)
+	if err == nil {
+		return nil
+	}
+	feasibilityErr := &bean.FeasibilityCheckError{}
+	if !errors.As(err, &feasibilityErr) {
+		impl.logger.Errorw("error in checking deployment feasibility", "pipelineId", cdPipeline.Id, "err", err)
 		return err
 	}
-	if isVulnerable {
-		// if image vulnerable, update timeline status and return
-		runner.Status = cdWorkflow.WorkflowFailed
+	// if feasibility check failed, update timeline status and return
+	runner.Status = cdWorkflow.WorkflowFailed
+	runner.Message = feasibilityErr.Error()
+	if len(feasibilityErr.Failures) == 1 && feasibilityErr.HasFailedRule(bean.FeasibilityRuleCvePolicy) {
 		runner.Message = cdWorkflow.FOUND_VULNERABILITY
-		runner.FinishedOn = time.Now()
-		runner.UpdatedOn = time.Now()
-		runner.UpdatedBy = triggeredBy
-		err = impl.cdWorkflowRunnerService.UpdateCdWorkflowRunnerWithStage(runner)
-		if err != nil {
-			impl.logger.Errorw("error in updating wfr status due to vulnerable image", "err", err)
-			return err
-		}
-		return fmt.Errorf("found vulnerability for image digest %s", artifact.ImageDigest)
 	}
-	return nil
+	runner.FinishedOn = time.Now()
+	runner.UpdatedOn = time.Now()
+	runner.UpdatedBy = triggeredBy
+	err = impl.cdWorkflowRunnerService.UpdateCdWorkflowRunnerWithStage(runner)
+	if err != nil {
+		impl.logger.Errorw("error in updating wfr status due to failed feasibility check", "err", err)
+		return err
+	}
+	return util.NewApiError(http.StatusPreconditionFailed, feasibilityErr.Error(), feasibilityErr.Error()).
+		WithUserMessage(feasibilityErr.Failures)
 }
 
 // setCopyContainerImagePluginDataAndReserveImages sets required fields in cdStageWorkflowRequest and reserve images generated by plugin
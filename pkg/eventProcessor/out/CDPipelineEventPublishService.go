@@ -26,8 +26,10 @@ import (
 )
 
 type CDPipelineEventPublishService interface {
+	// PublishBulkTriggerTopicEvent publishes the cd trigger event, non-empty deploymentWindowOverrideReason
+	// overrides the blocking deployment windows and must only be passed for users having the override permission
 	PublishBulkTriggerTopicEvent(pipelineId, appId,
-		artifactId int, userMetadata *bean3.UserMetadata) error
+		artifactId int, deploymentWindowOverrideReason string, userMetadata *bean3.UserMetadata) error
 
 	PublishArgoTypePipelineSyncEvent(pipelineId, installedAppVersionId int,
 		userId int32, isAppStoreApplication bool) error
@@ -47,14 +49,16 @@ func NewCDPipelineEventPublishServiceImpl(logger *zap.SugaredLogger,
 }
 
 func (impl *CDPipelineEventPublishServiceImpl) PublishBulkTriggerTopicEvent(pipelineId, appId,
-	artifactId int, userMetadata *bean3.UserMetadata) error {
+	artifactId int, deploymentWindowOverrideReason string, userMetadata *bean3.UserMetadata) error {
 	event := &bean.BulkCDDeployEvent{
 		ValuesOverrideRequest: &bean2.ValuesOverrideRequest{
-			PipelineId:     pipelineId,
-			AppId:          appId,
-			CiArtifactId:   artifactId,
-			UserId:         userMetadata.UserId,
-			CdWorkflowType: bean2.CD_WORKFLOW_TYPE_DEPLOY,
+			PipelineId:                     pipelineId,
+			AppId:                          appId,
+			CiArtifactId:                   artifactId,
+			UserId:                         userMetadata.UserId,
+			CdWorkflowType:                 bean2.CD_WORKFLOW_TYPE_DEPLOY,
+			DeploymentWindowOverride:       len(deploymentWindowOverrideReason) > 0,
+			DeploymentWindowOverrideReason: deploymentWindowOverrideReason,
 		},
 		UserId:       userMetadata.UserId,
 		UserMetadata: userMetadata,
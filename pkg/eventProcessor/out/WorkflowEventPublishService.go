@@ -43,7 +43,8 @@ import (
 type WorkflowEventPublishService interface {
 	TriggerBulkHibernateAsync(request bean.StopDeploymentGroupRequest, userMetadata *bean2.UserMetadata) (interface{}, error)
 	TriggerAsyncRelease(userDeploymentRequestId int, overrideRequest *apiBean.ValuesOverrideRequest, valuesOverrideResponse *app.ValuesOverrideResponse, ctx context.Context, triggeredBy int32) (releaseNo int, manifestPushTemplate *appBean.ManifestPushTemplate, err error)
-	TriggerBulkDeploymentAsync(requests []*bean.BulkTriggerRequest, UserId int32) (interface{}, error)
+	// TriggerBulkDeploymentAsync saves a cd workflow for each request and enqueues it for the deployment, returns the saved cd workflows
+	TriggerBulkDeploymentAsync(requests []*bean.BulkTriggerRequest, UserId int32) ([]*pipelineConfig.CdWorkflow, error)
 }
 
 type WorkflowEventPublishServiceImpl struct {
@@ -153,7 +154,7 @@ func (impl *WorkflowEventPublishServiceImpl) TriggerAsyncRelease(userDeploymentR
 	return 0, manifestPushTemplate, nil
 }
 
-func (impl *WorkflowEventPublishServiceImpl) TriggerBulkDeploymentAsync(requests []*bean.BulkTriggerRequest, UserId int32) (interface{}, error) {
+func (impl *WorkflowEventPublishServiceImpl) TriggerBulkDeploymentAsync(requests []*bean.BulkTriggerRequest, UserId int32) ([]*pipelineConfig.CdWorkflow, error) {
 	var cdWorkflows []*pipelineConfig.CdWorkflow
 	for _, request := range requests {
 		cdWf := &pipelineConfig.CdWorkflow{
@@ -170,7 +171,7 @@ func (impl *WorkflowEventPublishServiceImpl) TriggerBulkDeploymentAsync(requests
 		return nil, err
 	}
 	impl.triggerNatsEventForBulkAction(cdWorkflows)
-	return nil, nil
+	return cdWorkflows, nil
 }
 
 func (impl *WorkflowEventPublishServiceImpl) triggerNatsEventForBulkAction(cdWorkflows []*pipelineConfig.CdWorkflow) {
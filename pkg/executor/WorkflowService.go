@@ -73,6 +73,7 @@ type WorkflowServiceImpl struct {
 	globalCMCSService       pipeline.GlobalCMCSService
 	argoWorkflowExecutor    executors.ArgoWorkflowExecutor
 	systemWorkflowExecutor  executors.SystemWorkflowExecutor
+	tektonWorkflowExecutor  executors.TektonWorkflowExecutor
 	k8sCommonService        k8s2.K8sCommonService
 	infraProvider           infraProviders.InfraProvider
 	ucid                    ucid.Service
@@ -90,6 +91,7 @@ func NewWorkflowServiceImpl(Logger *zap.SugaredLogger,
 	globalCMCSService pipeline.GlobalCMCSService,
 	argoWorkflowExecutor executors.ArgoWorkflowExecutor,
 	systemWorkflowExecutor executors.SystemWorkflowExecutor,
+	tektonWorkflowExecutor executors.TektonWorkflowExecutor,
 	k8sCommonService k8s2.K8sCommonService,
 	infraProvider infraProviders.InfraProvider,
 	ucid ucid.Service,
@@ -106,6 +108,7 @@ func NewWorkflowServiceImpl(Logger *zap.SugaredLogger,
 		argoWorkflowExecutor:    argoWorkflowExecutor,
 		k8sUtil:                 k8sUtil,
 		systemWorkflowExecutor:  systemWorkflowExecutor,
+		tektonWorkflowExecutor:  tektonWorkflowExecutor,
 		k8sCommonService:        k8sCommonService,
 		infraProvider:           infraProvider,
 		ucid:                    ucid,
@@ -425,6 +428,8 @@ func (impl *WorkflowServiceImpl) getWorkflowExecutor(executorType cdWorkflow.Wor
 		return impl.argoWorkflowExecutor
 	} else if executorType == cdWorkflow.WORKFLOW_EXECUTOR_TYPE_SYSTEM {
 		return impl.systemWorkflowExecutor
+	} else if executorType == cdWorkflow.WORKFLOW_EXECUTOR_TYPE_TEKTON {
+		return impl.tektonWorkflowExecutor
 	}
 	impl.Logger.Warnw("workflow executor not found", "type", executorType)
 	return nil
@@ -0,0 +1,239 @@
+package fluxApplication
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/fluxApplication/bean"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"net/http"
+	"sort"
+	"time"
+)
+
+func (impl *FluxApplicationServiceImpl) ReconcileFluxApplication(ctx context.Context, app *bean.FluxAppIdentifier, withSource bool) (*bean.FluxAppActionResponse, error) {
+	restConfig, err := impl.getRestConfig(app.ClusterId)
+	if err != nil {
+		return nil, err
+	}
+	kind := getFluxAppKind(app)
+	obj, gvk, err := impl.getFluxObject(ctx, restConfig, kind, app.Namespace, app.Name)
+	if err != nil {
+		impl.logger.Errorw("error in getting flux app", "appIdentifier", app, "err", err)
+		return nil, err
+	}
+	if isFluxObjectSuspended(obj) {
+		return nil, util.NewApiError(http.StatusConflict, "app is suspended, resume it to reconcile", "flux app is suspended")
+	}
+	requestedAt := time.Now().Format(time.RFC3339Nano)
+	response := &bean.FluxAppActionResponse{
+		Suspended:         false,
+		RequestedAt:       requestedAt,
+		ReconciledSources: make([]*bean.FluxObjectRef, 0),
+	}
+	if withSource {
+		sourceRefs := make([]*bean.FluxObjectRef, 0, 2)
+		if sourceRef := getFluxSourceRef(obj, kind); sourceRef != nil {
+			sourceRefs = append(sourceRefs, sourceRef)
+		}
+		// the chart of a HelmRelease using a chart template is fetched into a HelmChart by the source controller
+		if helmChartRef := getHelmChartRef(obj); kind == bean.HelmReleaseKind && helmChartRef != nil {
+			sourceRefs = append(sourceRefs, helmChartRef)
+		}
+		for _, sourceRef := range sourceRefs {
+			_, sourceGvk, err := impl.getFluxObject(ctx, restConfig, sourceRef.Kind, sourceRef.Namespace, sourceRef.Name)
+			if err != nil {
+				impl.logger.Errorw("error in getting flux source", "appIdentifier", app, "sourceRef", sourceRef, "err", err)
+				return nil, err
+			}
+			err = impl.patchFluxObject(ctx, restConfig, sourceGvk, sourceRef.Namespace, sourceRef.Name, getReconcileRequestPatch(requestedAt, nil))
+			if err != nil {
+				impl.logger.Errorw("error in requesting reconciliation of flux source", "appIdentifier", app, "sourceRef", sourceRef, "err", err)
+				return nil, err
+			}
+			response.ReconciledSources = append(response.ReconciledSources, sourceRef)
+		}
+	}
+	err = impl.patchFluxObject(ctx, restConfig, gvk, app.Namespace, app.Name, getReconcileRequestPatch(requestedAt, nil))
+	if err != nil {
+		impl.logger.Errorw("error in requesting reconciliation of flux app", "appIdentifier", app, "err", err)
+		return nil, err
+	}
+	return response, nil
+}
+
+func (impl *FluxApplicationServiceImpl) SuspendFluxApplication(ctx context.Context, app *bean.FluxAppIdentifier) (*bean.FluxAppActionResponse, error) {
+	restConfig, err := impl.getRestConfig(app.ClusterId)
+	if err != nil {
+		return nil, err
+	}
+	_, gvk, err := impl.getFluxObject(ctx, restConfig, getFluxAppKind(app), app.Namespace, app.Name)
+	if err != nil {
+		impl.logger.Errorw("error in getting flux app", "appIdentifier", app, "err", err)
+		return nil, err
+	}
+	suspend := true
+	err = impl.patchFluxObject(ctx, restConfig, gvk, app.Namespace, app.Name, map[string]interface{}{
+		"spec": map[string]interface{}{"suspend": suspend},
+	})
+	if err != nil {
+		impl.logger.Errorw("error in suspending flux app", "appIdentifier", app, "err", err)
+		return nil, err
+	}
+	return &bean.FluxAppActionResponse{Suspended: suspend}, nil
+}
+
+// ResumeFluxApplication resumes a suspended app and requests its reconciliation, same as `flux resume`
+func (impl *FluxApplicationServiceImpl) ResumeFluxApplication(ctx context.Context, app *bean.FluxAppIdentifier) (*bean.FluxAppActionResponse, error) {
+	restConfig, err := impl.getRestConfig(app.ClusterId)
+	if err != nil {
+		return nil, err
+	}
+	_, gvk, err := impl.getFluxObject(ctx, restConfig, getFluxAppKind(app), app.Namespace, app.Name)
+	if err != nil {
+		impl.logger.Errorw("error in getting flux app", "appIdentifier", app, "err", err)
+		return nil, err
+	}
+	requestedAt := time.Now().Format(time.RFC3339Nano)
+	suspend := false
+	err = impl.patchFluxObject(ctx, restConfig, gvk, app.Namespace, app.Name, getReconcileRequestPatch(requestedAt, &suspend))
+	if err != nil {
+		impl.logger.Errorw("error in resuming flux app", "appIdentifier", app, "err", err)
+		return nil, err
+	}
+	return &bean.FluxAppActionResponse{Suspended: suspend, RequestedAt: requestedAt}, nil
+}
+
+func (impl *FluxApplicationServiceImpl) GetFluxAppReconciliationHistory(ctx context.Context, app *bean.FluxAppIdentifier) (*bean.FluxReconciliationHistory, error) {
+	restConfig, err := impl.getRestConfig(app.ClusterId)
+	if err != nil {
+		return nil, err
+	}
+	obj, gvk, err := impl.getFluxObject(ctx, restConfig, getFluxAppKind(app), app.Namespace, app.Name)
+	if err != nil {
+		impl.logger.Errorw("error in getting flux app", "appIdentifier", app, "err", err)
+		return nil, err
+	}
+	history := &bean.FluxReconciliationHistory{
+		Suspended:  isFluxObjectSuspended(obj),
+		Conditions: getFluxConditions(obj),
+		History:    getFluxReconciliationSnapshots(obj),
+		Events:     make([]*bean.FluxEvent, 0),
+	}
+	history.LastAppliedRevision, _, _ = unstructured.NestedString(obj.Object, "status", "lastAppliedRevision")
+	history.LastAttemptedRevision, _, _ = unstructured.NestedString(obj.Object, "status", "lastAttemptedRevision")
+	history.LastHandledReconcileAt, _, _ = unstructured.NestedString(obj.Object, "status", "lastHandledReconcileAt")
+	eventList, err := impl.k8sUtil.ListEvents(restConfig, app.Namespace, gvk, ctx, app.Name)
+	if err != nil {
+		impl.logger.Errorw("error in getting events of flux app", "appIdentifier", app, "err", err)
+		return nil, err
+	}
+	for _, event := range eventList.Items {
+		fluxEvent := &bean.FluxEvent{
+			Type:    event.Type,
+			Reason:  event.Reason,
+			Message: event.Message,
+			Count:   event.Count,
+		}
+		if !event.FirstTimestamp.IsZero() {
+			fluxEvent.FirstTimestamp = event.FirstTimestamp.Format(time.RFC3339)
+		}
+		if !event.LastTimestamp.IsZero() {
+			fluxEvent.LastTimestamp = event.LastTimestamp.Format(time.RFC3339)
+		} else if !event.EventTime.IsZero() {
+			fluxEvent.LastTimestamp = event.EventTime.Format(time.RFC3339)
+		}
+		history.Events = append(history.Events, fluxEvent)
+	}
+	sort.SliceStable(history.Events, func(i, j int) bool {
+		return history.Events[i].LastTimestamp > history.Events[j].LastTimestamp
+	})
+	return history, nil
+}
+
+func (impl *FluxApplicationServiceImpl) GetFluxAppSource(ctx context.Context, app *bean.FluxAppIdentifier) (*bean.FluxSourceDetail, error) {
+	restConfig, err := impl.getRestConfig(app.ClusterId)
+	if err != nil {
+		return nil, err
+	}
+	kind := getFluxAppKind(app)
+	obj, _, err := impl.getFluxObject(ctx, restConfig, kind, app.Namespace, app.Name)
+	if err != nil {
+		impl.logger.Errorw("error in getting flux app", "appIdentifier", app, "err", err)
+		return nil, err
+	}
+	sourceRef := getFluxSourceRef(obj, kind)
+	if sourceRef == nil {
+		return nil, util.NewApiError(http.StatusNotFound, "source not found for the app", "source reference not found in flux app spec")
+	}
+	sourceObj, _, err := impl.getFluxObject(ctx, restConfig, sourceRef.Kind, sourceRef.Namespace, sourceRef.Name)
+	if err != nil {
+		impl.logger.Errorw("error in getting flux source", "appIdentifier", app, "sourceRef", sourceRef, "err", err)
+		return nil, err
+	}
+	return getFluxSourceDetail(sourceObj, sourceRef), nil
+}
+
+func (impl *FluxApplicationServiceImpl) getRestConfig(clusterId int) (*rest.Config, error) {
+	clusterBean, err := impl.clusterService.FindById(clusterId)
+	if err != nil {
+		impl.logger.Errorw("error in getting cluster", "clusterId", clusterId, "err", err)
+		return nil, err
+	}
+	restConfig, err := impl.k8sUtil.GetRestConfigByCluster(clusterBean.GetClusterConfig())
+	if err != nil {
+		impl.logger.Errorw("error in getting rest config by cluster", "clusterId", clusterId, "err", err)
+		return nil, err
+	}
+	return restConfig, nil
+}
+
+// getFluxObject gets a flux object with the first api version of its kind served by the cluster
+func (impl *FluxApplicationServiceImpl) getFluxObject(ctx context.Context, restConfig *rest.Config, kind, namespace, name string) (*unstructured.Unstructured, schema.GroupVersionKind, error) {
+	group, ok := bean.FluxKindGroups[kind]
+	if !ok {
+		return nil, schema.GroupVersionKind{}, util.NewApiError(http.StatusBadRequest, fmt.Sprintf("unsupported flux kind %s", kind), "unsupported flux kind")
+	}
+	var err error
+	for _, version := range bean.FluxKindVersions[kind] {
+		gvk := schema.GroupVersionKind{Group: group, Version: version, Kind: kind}
+		resp, getErr := impl.k8sUtil.GetResource(ctx, namespace, name, gvk, restConfig)
+		if getErr == nil {
+			return &resp.Manifest, gvk, nil
+		}
+		err = getErr
+		if !errors.IsNotFound(getErr) {
+			break
+		}
+	}
+	if errors.IsNotFound(err) {
+		return nil, schema.GroupVersionKind{}, util.NewApiError(http.StatusNotFound, fmt.Sprintf("%s %s/%s not found", kind, namespace, name), err.Error())
+	}
+	return nil, schema.GroupVersionKind{}, err
+}
+
+func (impl *FluxApplicationServiceImpl) patchFluxObject(ctx context.Context, restConfig *rest.Config, gvk schema.GroupVersionKind, namespace, name string, patch map[string]interface{}) error {
+	patchJson, err := json.Marshal(patch)
+	if err != nil {
+		return err
+	}
+	_, err = impl.k8sUtil.PatchResourceRequest(ctx, restConfig, types.MergePatchType, string(patchJson), name, namespace, gvk)
+	return err
+}
+
+func getReconcileRequestPatch(requestedAt string, suspend *bool) map[string]interface{} {
+	patch := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{bean.ReconcileRequestedAtAnnotation: requestedAt},
+		},
+	}
+	if suspend != nil {
+		patch["spec"] = map[string]interface{}{"suspend": *suspend}
+	}
+	return patch
+}
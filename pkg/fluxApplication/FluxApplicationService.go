@@ -3,6 +3,7 @@ package fluxApplication
 import (
 	"context"
 	"fmt"
+	"github.com/devtron-labs/common-lib/utils/k8s"
 	"github.com/devtron-labs/common-lib/utils/k8s/commonBean"
 	"github.com/devtron-labs/devtron/api/connector"
 	"github.com/devtron-labs/devtron/api/helm-app/gRPC"
@@ -28,6 +29,11 @@ type FluxApplicationService interface {
 	GetFluxAppDetail(ctx context.Context, app *bean.FluxAppIdentifier) (*bean.FluxApplicationDetailDto, error)
 	HibernateFluxApplication(ctx context.Context, app *bean.FluxAppIdentifier, hibernateRequest *openapi.HibernateRequest) ([]*openapi.HibernateStatus, error)
 	UnHibernateFluxApplication(ctx context.Context, app *bean.FluxAppIdentifier, hibernateRequest *openapi.HibernateRequest) ([]*openapi.HibernateStatus, error)
+	ReconcileFluxApplication(ctx context.Context, app *bean.FluxAppIdentifier, withSource bool) (*bean.FluxAppActionResponse, error)
+	SuspendFluxApplication(ctx context.Context, app *bean.FluxAppIdentifier) (*bean.FluxAppActionResponse, error)
+	ResumeFluxApplication(ctx context.Context, app *bean.FluxAppIdentifier) (*bean.FluxAppActionResponse, error)
+	GetFluxAppReconciliationHistory(ctx context.Context, app *bean.FluxAppIdentifier) (*bean.FluxReconciliationHistory, error)
+	GetFluxAppSource(ctx context.Context, app *bean.FluxAppIdentifier) (*bean.FluxSourceDetail, error)
 }
 
 type FluxApplicationServiceImpl struct {
@@ -38,6 +44,7 @@ type FluxApplicationServiceImpl struct {
 	pump                   connector.Pump
 	pipelineRepository     pipelineConfig.PipelineRepository
 	installedAppRepository repository.InstalledAppRepository
+	k8sUtil                *k8s.K8sServiceImpl
 }
 
 func NewFluxApplicationServiceImpl(logger *zap.SugaredLogger,
@@ -45,7 +52,8 @@ func NewFluxApplicationServiceImpl(logger *zap.SugaredLogger,
 	clusterService cluster.ClusterService,
 	helmAppClient gRPC.HelmAppClient, pump connector.Pump,
 	pipelineRepository pipelineConfig.PipelineRepository,
-	installedAppRepository repository.InstalledAppRepository) *FluxApplicationServiceImpl {
+	installedAppRepository repository.InstalledAppRepository,
+	k8sUtil *k8s.K8sServiceImpl) *FluxApplicationServiceImpl {
 	return &FluxApplicationServiceImpl{
 		logger:                 logger,
 		helmAppReadService:     helmAppReadService,
@@ -54,6 +62,7 @@ func NewFluxApplicationServiceImpl(logger *zap.SugaredLogger,
 		pump:                   pump,
 		pipelineRepository:     pipelineRepository,
 		installedAppRepository: installedAppRepository,
+		k8sUtil:                k8sUtil,
 	}
 
 }
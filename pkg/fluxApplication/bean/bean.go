@@ -43,3 +43,120 @@ type FluxAppStatusDetail struct {
 	Message string `json:"message"`
 	Reason  string `json:"reason"`
 }
+
+const (
+	FluxKustomizationGroup = "kustomize.toolkit.fluxcd.io"
+	FluxHelmReleaseGroup   = "helm.toolkit.fluxcd.io"
+	FluxSourceGroup        = "source.toolkit.fluxcd.io"
+
+	KustomizationKind  = "Kustomization"
+	HelmReleaseKind    = "HelmRelease"
+	GitRepositoryKind  = "GitRepository"
+	OCIRepositoryKind  = "OCIRepository"
+	HelmRepositoryKind = "HelmRepository"
+	HelmChartKind      = "HelmChart"
+	BucketKind         = "Bucket"
+
+	// ReconcileRequestedAtAnnotation triggers a reconciliation of a flux object when its value changes, same as `flux reconcile`
+	ReconcileRequestedAtAnnotation = "reconcile.fluxcd.io/requestedAt"
+
+	FluxSystemNamespace = "flux-system"
+)
+
+// FluxKindGroups maps the flux kinds to their api group
+var FluxKindGroups = map[string]string{
+	KustomizationKind:  FluxKustomizationGroup,
+	HelmReleaseKind:    FluxHelmReleaseGroup,
+	GitRepositoryKind:  FluxSourceGroup,
+	OCIRepositoryKind:  FluxSourceGroup,
+	HelmRepositoryKind: FluxSourceGroup,
+	HelmChartKind:      FluxSourceGroup,
+	BucketKind:         FluxSourceGroup,
+}
+
+// FluxKindVersions are the api versions of a flux kind in order of preference, the first one served by the cluster is used
+var FluxKindVersions = map[string][]string{
+	KustomizationKind:  {"v1", "v1beta2"},
+	HelmReleaseKind:    {"v2", "v2beta2", "v2beta1"},
+	GitRepositoryKind:  {"v1", "v1beta2"},
+	OCIRepositoryKind:  {"v1", "v1beta2"},
+	HelmRepositoryKind: {"v1", "v1beta2"},
+	HelmChartKind:      {"v1", "v1beta2"},
+	BucketKind:         {"v1", "v1beta2"},
+}
+
+type FluxAppActionRequest struct {
+	AppId string `json:"appId"`
+	// WithSource reconciles the source of the app before the app itself, same as `flux reconcile --with-source`
+	WithSource bool `json:"withSource"`
+}
+
+type FluxAppActionResponse struct {
+	AppId       string `json:"appId"`
+	Suspended   bool   `json:"suspended"`
+	RequestedAt string `json:"requestedAt,omitempty"`
+	// ReconciledSources are the sources whose reconciliation was requested along with the app
+	ReconciledSources []*FluxObjectRef `json:"reconciledSources,omitempty"`
+}
+
+type FluxObjectRef struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+}
+
+type FluxCondition struct {
+	Type               string `json:"type"`
+	Status             string `json:"status"`
+	Reason             string `json:"reason,omitempty"`
+	Message            string `json:"message,omitempty"`
+	LastTransitionTime string `json:"lastTransitionTime,omitempty"`
+}
+
+type FluxSourceDetail struct {
+	*FluxObjectRef
+	Url                 string           `json:"url,omitempty"`
+	Suspended           bool             `json:"suspended"`
+	Interval            string           `json:"interval,omitempty"`
+	Status              string           `json:"status"`
+	Message             string           `json:"message,omitempty"`
+	LastFetchedRevision string           `json:"lastFetchedRevision,omitempty"`
+	LastUpdateTime      string           `json:"lastUpdateTime,omitempty"`
+	Conditions          []*FluxCondition `json:"conditions"`
+}
+
+type FluxReconciliationHistory struct {
+	AppId                  string                        `json:"appId"`
+	Suspended              bool                          `json:"suspended"`
+	LastAppliedRevision    string                        `json:"lastAppliedRevision,omitempty"`
+	LastAttemptedRevision  string                        `json:"lastAttemptedRevision,omitempty"`
+	LastHandledReconcileAt string                        `json:"lastHandledReconcileAt,omitempty"`
+	Conditions             []*FluxCondition              `json:"conditions"`
+	History                []*FluxReconciliationSnapshot `json:"history"`
+	// Events are the kubernetes events recorded by the flux controllers for the app, latest first
+	Events []*FluxEvent `json:"events"`
+}
+
+// FluxReconciliationSnapshot is an entry of status.history, release fields are filled for HelmRelease and
+// reconciliation count for Kustomization
+type FluxReconciliationSnapshot struct {
+	Digest               string `json:"digest,omitempty"`
+	Status               string `json:"status"`
+	Revision             string `json:"revision,omitempty"`
+	FirstReconciledAt    string `json:"firstReconciledAt,omitempty"`
+	LastReconciledAt     string `json:"lastReconciledAt,omitempty"`
+	TotalReconciliations int64  `json:"totalReconciliations,omitempty"`
+	ReleaseVersion       int64  `json:"releaseVersion,omitempty"`
+	ChartName            string `json:"chartName,omitempty"`
+	ChartVersion         string `json:"chartVersion,omitempty"`
+	AppVersion           string `json:"appVersion,omitempty"`
+}
+
+type FluxEvent struct {
+	Type           string `json:"type"`
+	Reason         string `json:"reason"`
+	Message        string `json:"message"`
+	Count          int32  `json:"count"`
+	FirstTimestamp string `json:"firstTimestamp,omitempty"`
+	LastTimestamp  string `json:"lastTimestamp,omitempty"`
+}
@@ -3,6 +3,7 @@ package fluxApplication
 import (
 	"fmt"
 	"github.com/devtron-labs/devtron/pkg/fluxApplication/bean"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"strconv"
 	"strings"
 )
@@ -45,3 +46,147 @@ func DecodeFluxExternalAppId(appId string) (*bean.FluxAppIdentifier, error) {
 		IsKustomizeApp: isKustomizeApp,
 	}, nil
 }
+
+func getFluxAppKind(app *bean.FluxAppIdentifier) string {
+	if app.IsKustomizeApp {
+		return bean.KustomizationKind
+	}
+	return bean.HelmReleaseKind
+}
+
+// IsFluxSystemRootApp checks for the root kustomization bootstrapped by flux, actions on it are not allowed
+func IsFluxSystemRootApp(app *bean.FluxAppIdentifier) bool {
+	return app.IsKustomizeApp && app.Name == bean.FluxSystemNamespace && app.Namespace == bean.FluxSystemNamespace
+}
+
+func isFluxObjectSuspended(obj *unstructured.Unstructured) bool {
+	suspended, _, _ := unstructured.NestedBool(obj.Object, "spec", "suspend")
+	return suspended
+}
+
+func getFluxConditions(obj *unstructured.Unstructured) []*bean.FluxCondition {
+	conditions := make([]*bean.FluxCondition, 0)
+	conditionsRaw, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, conditionRaw := range conditionsRaw {
+		conditionObj, ok := conditionRaw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		condition := &bean.FluxCondition{}
+		condition.Type, _, _ = unstructured.NestedString(conditionObj, "type")
+		condition.Status, _, _ = unstructured.NestedString(conditionObj, "status")
+		condition.Reason, _, _ = unstructured.NestedString(conditionObj, "reason")
+		condition.Message, _, _ = unstructured.NestedString(conditionObj, "message")
+		condition.LastTransitionTime, _, _ = unstructured.NestedString(conditionObj, "lastTransitionTime")
+		conditions = append(conditions, condition)
+	}
+	return conditions
+}
+
+/*
+* getFluxSourceRef returns the source an app is reconciled from:
+* 1. Kustomization: spec.sourceRef
+* 2. HelmRelease: spec.chartRef if the chart is referenced directly, otherwise spec.chart.spec.sourceRef
+*
+* The namespace of the source defaults to the namespace of the app.
+ */
+func getFluxSourceRef(obj *unstructured.Unstructured, kind string) *bean.FluxObjectRef {
+	var sourceRefObj map[string]interface{}
+	if kind == bean.KustomizationKind {
+		sourceRefObj, _, _ = unstructured.NestedMap(obj.Object, "spec", "sourceRef")
+	} else {
+		sourceRefObj, _, _ = unstructured.NestedMap(obj.Object, "spec", "chartRef")
+		if len(sourceRefObj) == 0 {
+			sourceRefObj, _, _ = unstructured.NestedMap(obj.Object, "spec", "chart", "spec", "sourceRef")
+		}
+	}
+	if len(sourceRefObj) == 0 {
+		return nil
+	}
+	sourceRef := &bean.FluxObjectRef{}
+	sourceRef.Kind, _, _ = unstructured.NestedString(sourceRefObj, "kind")
+	sourceRef.Name, _, _ = unstructured.NestedString(sourceRefObj, "name")
+	sourceRef.Namespace, _, _ = unstructured.NestedString(sourceRefObj, "namespace")
+	if len(sourceRef.Namespace) == 0 {
+		sourceRef.Namespace = obj.GetNamespace()
+	}
+	return sourceRef
+}
+
+// getHelmChartRef returns the HelmChart generated by the source controller for a HelmRelease using a chart template,
+// status.helmChart is of the format namespace/name
+func getHelmChartRef(obj *unstructured.Unstructured) *bean.FluxObjectRef {
+	helmChart, _, _ := unstructured.NestedString(obj.Object, "status", "helmChart")
+	namespace, name, found := strings.Cut(helmChart, "/")
+	if !found || len(namespace) == 0 || len(name) == 0 {
+		return nil
+	}
+	return &bean.FluxObjectRef{Kind: bean.HelmChartKind, Name: name, Namespace: namespace}
+}
+
+func getFluxSourceDetail(obj *unstructured.Unstructured, sourceRef *bean.FluxObjectRef) *bean.FluxSourceDetail {
+	sourceDetail := &bean.FluxSourceDetail{
+		FluxObjectRef: sourceRef,
+		Suspended:     isFluxObjectSuspended(obj),
+		Conditions:    getFluxConditions(obj),
+		Status:        "Unknown",
+	}
+	sourceDetail.Url, _, _ = unstructured.NestedString(obj.Object, "spec", "url")
+	sourceDetail.Interval, _, _ = unstructured.NestedString(obj.Object, "spec", "interval")
+	sourceDetail.LastFetchedRevision, _, _ = unstructured.NestedString(obj.Object, "status", "artifact", "revision")
+	sourceDetail.LastUpdateTime, _, _ = unstructured.NestedString(obj.Object, "status", "artifact", "lastUpdateTime")
+	for _, condition := range sourceDetail.Conditions {
+		if condition.Type == "Ready" {
+			sourceDetail.Status = getFluxReadyStatus(condition.Status)
+			sourceDetail.Message = condition.Message
+		}
+	}
+	return sourceDetail
+}
+
+func getFluxReadyStatus(readyConditionStatus string) string {
+	switch readyConditionStatus {
+	case "True":
+		return "Ready"
+	case "False":
+		return "Failed"
+	default:
+		return "Progressing"
+	}
+}
+
+// getFluxReconciliationSnapshots returns status.history of an app, latest first as recorded by the flux controllers
+func getFluxReconciliationSnapshots(obj *unstructured.Unstructured) []*bean.FluxReconciliationSnapshot {
+	snapshots := make([]*bean.FluxReconciliationSnapshot, 0)
+	historyRaw, _, _ := unstructured.NestedSlice(obj.Object, "status", "history")
+	for _, entryRaw := range historyRaw {
+		entry, ok := entryRaw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		snapshot := &bean.FluxReconciliationSnapshot{}
+		snapshot.Digest, _, _ = unstructured.NestedString(entry, "digest")
+		// Kustomization history
+		snapshot.Status, _, _ = unstructured.NestedString(entry, "lastReconciledStatus")
+		snapshot.Revision, _, _ = unstructured.NestedString(entry, "metadata", "revision")
+		snapshot.FirstReconciledAt, _, _ = unstructured.NestedString(entry, "firstReconciled")
+		snapshot.LastReconciledAt, _, _ = unstructured.NestedString(entry, "lastReconciled")
+		snapshot.TotalReconciliations, _, _ = unstructured.NestedInt64(entry, "totalReconciliations")
+		// HelmRelease history
+		if len(snapshot.Status) == 0 {
+			snapshot.Status, _, _ = unstructured.NestedString(entry, "status")
+		}
+		if len(snapshot.FirstReconciledAt) == 0 {
+			snapshot.FirstReconciledAt, _, _ = unstructured.NestedString(entry, "firstDeployed")
+		}
+		if len(snapshot.LastReconciledAt) == 0 {
+			snapshot.LastReconciledAt, _, _ = unstructured.NestedString(entry, "lastDeployed")
+		}
+		snapshot.ReleaseVersion, _, _ = unstructured.NestedInt64(entry, "version")
+		snapshot.ChartName, _, _ = unstructured.NestedString(entry, "chartName")
+		snapshot.ChartVersion, _, _ = unstructured.NestedString(entry, "chartVersion")
+		snapshot.AppVersion, _, _ = unstructured.NestedString(entry, "appVersion")
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots
+}
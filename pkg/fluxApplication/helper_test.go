@@ -0,0 +1,73 @@
+package fluxApplication
+
+import (
+	"github.com/devtron-labs/devtron/pkg/fluxApplication/bean"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"testing"
+)
+
+func TestGetFluxSourceRef(t *testing.T) {
+	kustomization := &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "apps", "namespace": "apps"},
+		"spec": map[string]interface{}{
+			"sourceRef": map[string]interface{}{"kind": "GitRepository", "name": "fleet"},
+		},
+	}}
+	assert.Equal(t, &bean.FluxObjectRef{Kind: "GitRepository", Name: "fleet", Namespace: "apps"}, getFluxSourceRef(kustomization, bean.KustomizationKind))
+
+	helmRelease := &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "podinfo", "namespace": "apps"},
+		"spec": map[string]interface{}{
+			"chart": map[string]interface{}{"spec": map[string]interface{}{
+				"chart":     "podinfo",
+				"sourceRef": map[string]interface{}{"kind": "HelmRepository", "name": "podinfo", "namespace": "flux-system"},
+			}},
+		},
+		"status": map[string]interface{}{"helmChart": "flux-system/apps-podinfo"},
+	}}
+	assert.Equal(t, &bean.FluxObjectRef{Kind: "HelmRepository", Name: "podinfo", Namespace: "flux-system"}, getFluxSourceRef(helmRelease, bean.HelmReleaseKind))
+	assert.Equal(t, &bean.FluxObjectRef{Kind: "HelmChart", Name: "apps-podinfo", Namespace: "flux-system"}, getHelmChartRef(helmRelease))
+
+	helmRelease.Object["spec"] = map[string]interface{}{
+		"chartRef": map[string]interface{}{"kind": "OCIRepository", "name": "podinfo"},
+	}
+	assert.Equal(t, &bean.FluxObjectRef{Kind: "OCIRepository", Name: "podinfo", Namespace: "apps"}, getFluxSourceRef(helmRelease, bean.HelmReleaseKind))
+	assert.Nil(t, getFluxSourceRef(&unstructured.Unstructured{Object: map[string]interface{}{}}, bean.KustomizationKind))
+}
+
+func TestGetFluxSourceDetail(t *testing.T) {
+	source := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{"url": "https://github.com/org/fleet", "interval": "1m"},
+		"status": map[string]interface{}{
+			"artifact": map[string]interface{}{"revision": "main@sha1:abc", "lastUpdateTime": "2024-01-01T00:00:00Z"},
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Ready", "status": "True", "message": "stored artifact"},
+			},
+		},
+	}}
+	detail := getFluxSourceDetail(source, &bean.FluxObjectRef{Kind: "GitRepository", Name: "fleet", Namespace: "apps"})
+	assert.Equal(t, "Ready", detail.Status)
+	assert.Equal(t, "main@sha1:abc", detail.LastFetchedRevision)
+	assert.Equal(t, "https://github.com/org/fleet", detail.Url)
+	assert.False(t, detail.Suspended)
+	assert.Len(t, detail.Conditions, 1)
+}
+
+func TestGetFluxReconciliationSnapshots(t *testing.T) {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"status": map[string]interface{}{
+			"history": []interface{}{
+				map[string]interface{}{"digest": "sha256:1", "status": "deployed", "version": int64(3), "chartVersion": "6.5.0", "lastDeployed": "2024-01-02T00:00:00Z"},
+				map[string]interface{}{"digest": "sha256:2", "lastReconciledStatus": "ReconciliationSucceeded", "totalReconciliations": int64(7), "lastReconciled": "2024-01-01T00:00:00Z"},
+			},
+		},
+	}}
+	snapshots := getFluxReconciliationSnapshots(obj)
+	assert.Len(t, snapshots, 2)
+	assert.Equal(t, "deployed", snapshots[0].Status)
+	assert.Equal(t, int64(3), snapshots[0].ReleaseVersion)
+	assert.Equal(t, "2024-01-02T00:00:00Z", snapshots[0].LastReconciledAt)
+	assert.Equal(t, "ReconciliationSucceeded", snapshots[1].Status)
+	assert.Equal(t, int64(7), snapshots[1].TotalReconciliations)
+}
@@ -18,6 +18,7 @@ package generateManifest
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"github.com/caarlos0/env"
 	"github.com/devtron-labs/common-lib/utils/k8s"
@@ -399,7 +400,12 @@ func (impl DeploymentTemplateServiceImpl) resolveTemplateVariables(ctx context.C
 		return values, nil, err
 	}
 	maskUnknownVariableForHelmGenerate := request.RequestDataMode == Manifest
-	resolvedTemplate, variableSnapshot, err := impl.scopedVariableManager.ExtractVariablesAndResolveTemplate(scope, values, parsers.StringVariableTemplate, isSuperAdmin, maskUnknownVariableForHelmGenerate)
+	templateType := parsers.StringVariableTemplate
+	if !json.Valid([]byte(values)) {
+		// values edited by the user are yaml, resolving them as yaml keeps the type of the variable values
+		templateType = parsers.YamlVariableTemplate
+	}
+	resolvedTemplate, variableSnapshot, err := impl.scopedVariableManager.ExtractVariablesAndResolveTemplate(scope, values, templateType, isSuperAdmin, maskUnknownVariableForHelmGenerate)
 	if err != nil {
 		return values, variableSnapshot, err
 	}
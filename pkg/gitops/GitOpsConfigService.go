@@ -37,6 +37,7 @@ import (
 	"github.com/devtron-labs/devtron/pkg/cluster/read"
 	"github.com/devtron-labs/devtron/pkg/deployment/gitOps/config"
 	"github.com/devtron-labs/devtron/pkg/deployment/gitOps/git"
+	gitBean "github.com/devtron-labs/devtron/pkg/deployment/gitOps/git/bean"
 	"github.com/devtron-labs/devtron/pkg/deployment/gitOps/validation"
 	gitOpsBean "github.com/devtron-labs/devtron/pkg/gitops/bean"
 	moduleBean "github.com/devtron-labs/devtron/pkg/module/bean"
@@ -124,6 +125,10 @@ func NewGitOpsConfigServiceImpl(Logger *zap.SugaredLogger,
 }
 
 func (impl *GitOpsConfigServiceImpl) ValidateAndCreateGitOpsConfig(config *apiBean.GitOpsConfigDto) (apiBean.DetailedErrorGitOpsConfigResponse, error) {
+	err := impl.validateProviderConfig(config)
+	if err != nil {
+		return apiBean.DetailedErrorGitOpsConfigResponse{}, err
+	}
 	argoModule, err := impl.moduleReadService.GetModuleInfoByName(moduleBean.ModuleNameArgoCd)
 	if err != nil && !errors.Is(err, moduleErr.ModuleNotFoundError) {
 		impl.logger.Errorw("error in getting argo module", "error", err)
@@ -158,6 +163,15 @@ func (impl *GitOpsConfigServiceImpl) ValidateAndCreateGitOpsConfig(config *apiBe
 	return detailedErrorGitOpsConfigResponse, nil
 }
 
+// validateProviderConfig validates the provider specific fields of the gitops config
+func (impl *GitOpsConfigServiceImpl) validateProviderConfig(config *apiBean.GitOpsConfigDto) error {
+	if strings.ToUpper(config.Provider) == gitBean.GITEA_PROVIDER && len(strings.TrimSpace(config.GiteaOrgId)) == 0 {
+		impl.logger.Errorw("gitea organisation is not provided in gitops config", "provider", config.Provider)
+		return util.NewApiError(http.StatusBadRequest, "gitea organisation is required", "gitea organisation is required")
+	}
+	return nil
+}
+
 func (impl *GitOpsConfigServiceImpl) updateArgoCdUserDetailIfNotPresent(argoModule *moduleReadBean.ModuleInfoMin) error {
 	// create argo-cd user, if not created, here argo-cd integration has to be installed
 	if argoModule.IsInstalled() {
@@ -172,6 +186,10 @@ func (impl *GitOpsConfigServiceImpl) updateArgoCdUserDetailIfNotPresent(argoModu
 }
 
 func (impl *GitOpsConfigServiceImpl) ValidateAndUpdateGitOpsConfig(config *apiBean.GitOpsConfigDto) (apiBean.DetailedErrorGitOpsConfigResponse, error) {
+	err := impl.validateProviderConfig(config)
+	if err != nil {
+		return apiBean.DetailedErrorGitOpsConfigResponse{}, err
+	}
 	isTokenEmpty := config.Token == ""
 	isTlsDetailsEmpty := config.EnableTLSVerification &&
 		(config.TLSConfig == nil ||
@@ -416,6 +434,7 @@ func (impl *GitOpsConfigServiceImpl) createGitOpsConfig(ctx context.Context, req
 		AllowCustomRepository: request.AllowCustomRepository,
 		BitBucketWorkspaceId:  request.BitBucketWorkspaceId,
 		BitBucketProjectKey:   request.BitBucketProjectKey,
+		GiteaOrgId:            request.GiteaOrgId,
 		EnableTLSVerification: request.EnableTLSVerification,
 		AuditLog:              sql.AuditLog{CreatedBy: request.UserId, CreatedOn: time.Now(), UpdatedOn: time.Now(), UpdatedBy: request.UserId},
 	}
@@ -687,6 +706,7 @@ func (impl *GitOpsConfigServiceImpl) updateGitOpsConfig(request *apiBean.GitOpsC
 	model.AzureProject = request.AzureProjectName
 	model.BitBucketWorkspaceId = request.BitBucketWorkspaceId
 	model.BitBucketProjectKey = request.BitBucketProjectKey
+	model.GiteaOrgId = request.GiteaOrgId
 	model.AllowCustomRepository = request.AllowCustomRepository
 	model.EnableTLSVerification = request.EnableTLSVerification
 	model.UpdatedBy = request.UserId
@@ -790,6 +810,7 @@ func (impl *GitOpsConfigServiceImpl) GetGitOpsConfigById(id int) (*apiBean.GitOp
 		AzureProjectName:      model.AzureProject,
 		BitBucketWorkspaceId:  model.BitBucketWorkspaceId,
 		BitBucketProjectKey:   model.BitBucketProjectKey,
+		GiteaOrgId:            model.GiteaOrgId,
 		AllowCustomRepository: model.AllowCustomRepository,
 		EnableTLSVerification: model.EnableTLSVerification,
 		TLSConfig: &bean.TLSConfig{ // sending empty values as they are hidden in FE
@@ -825,6 +846,7 @@ func (impl *GitOpsConfigServiceImpl) GetAllGitOpsConfig() ([]*apiBean.GitOpsConf
 			AzureProjectName:      model.AzureProject,
 			BitBucketWorkspaceId:  model.BitBucketWorkspaceId,
 			BitBucketProjectKey:   model.BitBucketProjectKey,
+			GiteaOrgId:            model.GiteaOrgId,
 			AllowCustomRepository: model.AllowCustomRepository,
 			EnableTLSVerification: model.EnableTLSVerification,
 			TLSConfig: &bean.TLSConfig{ // sending empty values as they are hidden in FE
@@ -860,6 +882,7 @@ func (impl *GitOpsConfigServiceImpl) GetGitOpsConfigByProvider(provider string)
 		AzureProjectName:      model.AzureProject,
 		BitBucketWorkspaceId:  model.BitBucketWorkspaceId,
 		BitBucketProjectKey:   model.BitBucketProjectKey,
+		GiteaOrgId:            model.GiteaOrgId,
 		AllowCustomRepository: model.AllowCustomRepository,
 		EnableTLSVerification: model.EnableTLSVerification,
 		TLSConfig: &bean.TLSConfig{ // sending empty values as they are hidden in FE
@@ -0,0 +1,489 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jira
+
+import (
+	"context"
+	"fmt"
+	"github.com/devtron-labs/common-lib/async"
+	"github.com/devtron-labs/common-lib/securestore"
+	apiBean "github.com/devtron-labs/devtron/api/bean"
+	client "github.com/devtron-labs/devtron/client/jira"
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	"github.com/devtron-labs/devtron/internal/sql/repository/app"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/util"
+	userBean "github.com/devtron-labs/devtron/pkg/auth/user/bean"
+	"github.com/devtron-labs/devtron/pkg/jira/bean"
+	jiraRepository "github.com/devtron-labs/devtron/pkg/jira/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"go.uber.org/zap"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+type JiraIntegrationService interface {
+	// SaveJiraConfig creates or replaces the jira config of the project, the credentials are verified against jira
+	SaveJiraConfig(config *bean.JiraConfigDto) (*bean.JiraConfigDto, error)
+	GetJiraConfigs() ([]*bean.JiraConfigDto, error)
+	GetJiraConfigByTeamId(teamId int) (*bean.JiraConfigDto, error)
+	DeleteJiraConfig(teamId int, userId int32) error
+
+	// GetLinkedIssuesForArtifact returns the jira issues mentioned in the commits the artifact was built from
+	GetLinkedIssuesForArtifact(appId int, artifactId int) (*bean.LinkedIssuesResponse, error)
+	// GetLinkedIssuesForDeployment returns the issues of the deployed artifact along with the transitions applied to them
+	GetLinkedIssuesForDeployment(appId int, envId int, cdWorkflowRunnerId int) (*bean.LinkedIssuesResponse, error)
+	// TransitionIssuesOnDeploymentSuccess asynchronously moves the issues linked to the deployed artifact through the
+	// transition configured for the environment of the deployment
+	TransitionIssuesOnDeploymentSuccess(cdWorkflowId int)
+}
+
+type JiraIntegrationServiceImpl struct {
+	logger                        *zap.SugaredLogger
+	jiraConfigRepository          jiraRepository.JiraConfigRepository
+	jiraIssueTransitionRepository jiraRepository.JiraIssueTransitionRepository
+	jiraClient                    client.JiraClient
+	appRepository                 app.AppRepository
+	ciArtifactRepository          repository.CiArtifactRepository
+	cdWorkflowRepository          pipelineConfig.CdWorkflowRepository
+	asyncRunnable                 *async.Runnable
+}
+
+func NewJiraIntegrationServiceImpl(logger *zap.SugaredLogger,
+	jiraConfigRepository jiraRepository.JiraConfigRepository,
+	jiraIssueTransitionRepository jiraRepository.JiraIssueTransitionRepository,
+	jiraClient client.JiraClient,
+	appRepository app.AppRepository,
+	ciArtifactRepository repository.CiArtifactRepository,
+	cdWorkflowRepository pipelineConfig.CdWorkflowRepository,
+	asyncRunnable *async.Runnable) *JiraIntegrationServiceImpl {
+	return &JiraIntegrationServiceImpl{
+		logger:                        logger,
+		jiraConfigRepository:          jiraConfigRepository,
+		jiraIssueTransitionRepository: jiraIssueTransitionRepository,
+		jiraClient:                    jiraClient,
+		appRepository:                 appRepository,
+		ciArtifactRepository:          ciArtifactRepository,
+		cdWorkflowRepository:          cdWorkflowRepository,
+		asyncRunnable:                 asyncRunnable,
+	}
+}
+
+func (impl *JiraIntegrationServiceImpl) SaveJiraConfig(configDto *bean.JiraConfigDto) (*bean.JiraConfigDto, error) {
+	configDto.JiraUrl = strings.TrimSuffix(strings.TrimSpace(configDto.JiraUrl), "/")
+	configDto.ProjectKeys = normaliseProjectKeys(configDto.ProjectKeys)
+	ruleEnvIds := make(map[int]bool, len(configDto.TransitionRules))
+	for _, rule := range configDto.TransitionRules {
+		if ruleEnvIds[rule.EnvironmentId] {
+			return nil, util.NewApiError(http.StatusBadRequest, fmt.Sprintf("multiple transitions configured for environment %d", rule.EnvironmentId), "duplicate environment in transition rules")
+		}
+		ruleEnvIds[rule.EnvironmentId] = true
+	}
+	existingConfig, err := impl.jiraConfigRepository.FindByTeamId(configDto.TeamId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching jira config", "teamId", configDto.TeamId, "err", err)
+		return nil, err
+	}
+	isUpdate := err == nil
+	if configDto.ApiToken == bean.MaskedApiToken {
+		if !isUpdate {
+			return nil, util.NewApiError(http.StatusBadRequest, "api token is required", "masked api token for new jira config")
+		}
+		configDto.ApiToken = existingConfig.ApiToken.String()
+	}
+	if err = impl.authenticate(configDto); err != nil {
+		return nil, err
+	}
+	config := &jiraRepository.JiraConfig{
+		TeamId:      configDto.TeamId,
+		JiraUrl:     configDto.JiraUrl,
+		Username:    configDto.Username,
+		ApiToken:    securestore.ToEncryptedString(configDto.ApiToken),
+		ProjectKeys: configDto.ProjectKeys,
+		Active:      true,
+		AuditLog:    sql.NewDefaultAuditLog(configDto.UserId),
+	}
+	tx, err := impl.jiraConfigRepository.StartTx()
+	if err != nil {
+		impl.logger.Errorw("error in starting transaction", "err", err)
+		return nil, err
+	}
+	defer impl.jiraConfigRepository.RollbackTx(tx)
+	if isUpdate {
+		config.Id = existingConfig.Id
+		config.CreatedOn, config.CreatedBy = existingConfig.CreatedOn, existingConfig.CreatedBy
+		err = impl.jiraConfigRepository.Update(config, tx)
+	} else {
+		err = impl.jiraConfigRepository.Save(config, tx)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in saving jira config", "teamId", configDto.TeamId, "err", err)
+		return nil, err
+	}
+	if err = impl.jiraConfigRepository.DeactivateTransitionRules(config.Id, configDto.UserId, tx); err != nil {
+		impl.logger.Errorw("error in deactivating jira transition rules", "jiraConfigId", config.Id, "err", err)
+		return nil, err
+	}
+	rules := make([]*jiraRepository.JiraTransitionRule, 0, len(configDto.TransitionRules))
+	for _, rule := range configDto.TransitionRules {
+		rules = append(rules, &jiraRepository.JiraTransitionRule{
+			JiraConfigId:   config.Id,
+			EnvironmentId:  rule.EnvironmentId,
+			TransitionName: strings.TrimSpace(rule.TransitionName),
+			Active:         true,
+			AuditLog:       sql.NewDefaultAuditLog(configDto.UserId),
+		})
+	}
+	if err = impl.jiraConfigRepository.SaveTransitionRules(rules, tx); err != nil {
+		impl.logger.Errorw("error in saving jira transition rules", "jiraConfigId", config.Id, "err", err)
+		return nil, err
+	}
+	if err = impl.jiraConfigRepository.CommitTx(tx); err != nil {
+		impl.logger.Errorw("error in committing transaction", "err", err)
+		return nil, err
+	}
+	return impl.GetJiraConfigByTeamId(configDto.TeamId)
+}
+
+func (impl *JiraIntegrationServiceImpl) GetJiraConfigs() ([]*bean.JiraConfigDto, error) {
+	configs, err := impl.jiraConfigRepository.FindAll()
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching jira configs", "err", err)
+		return nil, err
+	}
+	configDtos := make([]*bean.JiraConfigDto, 0, len(configs))
+	for _, config := range configs {
+		configDto, err := impl.adaptJiraConfig(config)
+		if err != nil {
+			return nil, err
+		}
+		configDtos = append(configDtos, configDto)
+	}
+	return configDtos, nil
+}
+
+func (impl *JiraIntegrationServiceImpl) GetJiraConfigByTeamId(teamId int) (*bean.JiraConfigDto, error) {
+	config, err := impl.getJiraConfig(teamId)
+	if err != nil {
+		return nil, err
+	}
+	return impl.adaptJiraConfig(config)
+}
+
+func (impl *JiraIntegrationServiceImpl) DeleteJiraConfig(teamId int, userId int32) error {
+	config, err := impl.getJiraConfig(teamId)
+	if err != nil {
+		return err
+	}
+	tx, err := impl.jiraConfigRepository.StartTx()
+	if err != nil {
+		impl.logger.Errorw("error in starting transaction", "err", err)
+		return err
+	}
+	defer impl.jiraConfigRepository.RollbackTx(tx)
+	config.Active = false
+	config.UpdateAuditLog(userId)
+	if err = impl.jiraConfigRepository.Update(config, tx); err != nil {
+		impl.logger.Errorw("error in deleting jira config", "teamId", teamId, "err", err)
+		return err
+	}
+	if err = impl.jiraConfigRepository.DeactivateTransitionRules(config.Id, userId, tx); err != nil {
+		impl.logger.Errorw("error in deactivating jira transition rules", "jiraConfigId", config.Id, "err", err)
+		return err
+	}
+	return impl.jiraConfigRepository.CommitTx(tx)
+}
+
+func (impl *JiraIntegrationServiceImpl) GetLinkedIssuesForArtifact(appId int, artifactId int) (*bean.LinkedIssuesResponse, error) {
+	config, err := impl.getJiraConfigForApp(appId)
+	if err != nil {
+		return nil, err
+	}
+	artifact, err := impl.ciArtifactRepository.Get(artifactId)
+	if err != nil {
+		if util.IsErrNoRows(err) {
+			return nil, util.NewApiError(http.StatusNotFound, "artifact not found", err.Error())
+		}
+		impl.logger.Errorw("error in fetching artifact", "artifactId", artifactId, "err", err)
+		return nil, err
+	}
+	return &bean.LinkedIssuesResponse{
+		ArtifactId: artifactId,
+		Issues:     impl.getIssues(config, impl.getArtifactIssueKeys(artifact, config.ProjectKeys)),
+	}, nil
+}
+
+func (impl *JiraIntegrationServiceImpl) GetLinkedIssuesForDeployment(appId int, envId int, cdWorkflowRunnerId int) (*bean.LinkedIssuesResponse, error) {
+	wfr, err := impl.cdWorkflowRepository.FindWorkflowRunnerById(cdWorkflowRunnerId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching cd workflow runner", "cdWorkflowRunnerId", cdWorkflowRunnerId, "err", err)
+		return nil, err
+	}
+	if util.IsErrNoRows(err) || wfr.CdWorkflow == nil || wfr.CdWorkflow.Pipeline == nil || wfr.CdWorkflow.CiArtifact == nil ||
+		wfr.CdWorkflow.Pipeline.AppId != appId || wfr.CdWorkflow.Pipeline.EnvironmentId != envId {
+		return nil, util.NewApiError(http.StatusNotFound, "deployment not found", "cd workflow runner not found for app and env")
+	}
+	config, err := impl.getJiraConfigForApp(appId)
+	if err != nil {
+		return nil, err
+	}
+	transitions, err := impl.jiraIssueTransitionRepository.FindByCdWorkflowRunnerId(cdWorkflowRunnerId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching jira issue transitions", "cdWorkflowRunnerId", cdWorkflowRunnerId, "err", err)
+		return nil, err
+	}
+	response := &bean.LinkedIssuesResponse{
+		ArtifactId:  wfr.CdWorkflow.CiArtifactId,
+		Issues:      impl.getIssues(config, impl.getArtifactIssueKeys(wfr.CdWorkflow.CiArtifact, config.ProjectKeys)),
+		Transitions: make([]*bean.IssueTransitionDto, 0, len(transitions)),
+	}
+	for _, transition := range transitions {
+		response.Transitions = append(response.Transitions, &bean.IssueTransitionDto{
+			IssueKey:       transition.IssueKey,
+			TransitionName: transition.TransitionName,
+			Status:         bean.JiraIssueTransitionStatus(transition.Status),
+			Message:        transition.Message,
+			TransitionedOn: transition.CreatedOn,
+		})
+	}
+	return response, nil
+}
+
+func (impl *JiraIntegrationServiceImpl) TransitionIssuesOnDeploymentSuccess(cdWorkflowId int) {
+	impl.asyncRunnable.Execute(func() {
+		if err := impl.transitionIssuesOnDeploymentSuccess(cdWorkflowId); err != nil {
+			impl.logger.Errorw("error in transitioning jira issues after deployment", "cdWorkflowId", cdWorkflowId, "err", err)
+		}
+	})
+}
+
+func (impl *JiraIntegrationServiceImpl) transitionIssuesOnDeploymentSuccess(cdWorkflowId int) error {
+	wfr, err := impl.cdWorkflowRepository.FindByWorkflowIdAndRunnerType(context.Background(), cdWorkflowId, apiBean.CD_WORKFLOW_TYPE_DEPLOY)
+	if err != nil {
+		return err
+	}
+	if wfr.CdWorkflow == nil || wfr.CdWorkflow.Pipeline == nil || wfr.CdWorkflow.CiArtifact == nil {
+		return nil
+	}
+	pipeline := wfr.CdWorkflow.Pipeline
+	app, err := impl.appRepository.FindById(pipeline.AppId)
+	if err != nil {
+		return err
+	}
+	config, err := impl.jiraConfigRepository.FindByTeamId(app.TeamId)
+	if err != nil {
+		if util.IsErrNoRows(err) {
+			return nil
+		}
+		return err
+	}
+	rule, err := impl.jiraConfigRepository.FindTransitionRuleByConfigIdAndEnvId(config.Id, pipeline.EnvironmentId)
+	if err != nil {
+		if util.IsErrNoRows(err) {
+			return nil
+		}
+		return err
+	}
+	issueKeys := impl.getArtifactIssueKeys(wfr.CdWorkflow.CiArtifact, config.ProjectKeys)
+	if len(issueKeys) == 0 {
+		return nil
+	}
+	// the deployment success event can be received more than once, issues already transitioned are not retried
+	existingTransitions, err := impl.jiraIssueTransitionRepository.FindByCdWorkflowRunnerId(wfr.Id)
+	if err != nil && !util.IsErrNoRows(err) {
+		return err
+	}
+	processedIssueKeys := make(map[string]bool, len(existingTransitions))
+	for _, transition := range existingTransitions {
+		if transition.Status != bean.JiraIssueTransitionFailed.String() {
+			processedIssueKeys[transition.IssueKey] = true
+		}
+	}
+	clientRequest := client.CreateClientReq(config.Username, config.ApiToken.String(), config.JiraUrl)
+	transitions := make([]*jiraRepository.JiraIssueTransition, 0, len(issueKeys))
+	for _, issueKey := range issueKeys {
+		if processedIssueKeys[issueKey] {
+			continue
+		}
+		status, message := impl.transitionIssue(clientRequest, issueKey, rule.TransitionName)
+		transitions = append(transitions, &jiraRepository.JiraIssueTransition{
+			CdWorkflowRunnerId: wfr.Id,
+			IssueKey:           issueKey,
+			TransitionName:     rule.TransitionName,
+			Status:             status.String(),
+			Message:            message,
+			AuditLog:           sql.NewDefaultAuditLog(userBean.SYSTEM_USER_ID),
+		})
+	}
+	return impl.jiraIssueTransitionRepository.Save(transitions)
+}
+
+// transitionIssue moves the issue through the transition, matched by its name or by the name of the status it leads to
+func (impl *JiraIntegrationServiceImpl) transitionIssue(clientRequest client.JiraClientRequest, issueKey string, transitionName string) (bean.JiraIssueTransitionStatus, string) {
+	issue, err := impl.jiraClient.GetIssue(clientRequest, issueKey)
+	if err != nil {
+		impl.logger.Errorw("error in fetching jira issue", "issueKey", issueKey, "err", err)
+		return bean.JiraIssueTransitionFailed, err.Error()
+	}
+	currentStatus := ""
+	if issue.Fields.Status != nil {
+		currentStatus = issue.Fields.Status.Name
+	}
+	// the rule can name the target status, the transition leading to it is usually not offered once the issue is in it
+	if strings.EqualFold(currentStatus, transitionName) {
+		return bean.JiraIssueTransitionSkipped, fmt.Sprintf("issue is already in status %s", currentStatus)
+	}
+	transitions, err := impl.jiraClient.FindIssueTransitions(clientRequest, issueKey)
+	if err != nil {
+		impl.logger.Errorw("error in fetching jira issue transitions", "issueKey", issueKey, "err", err)
+		return bean.JiraIssueTransitionFailed, err.Error()
+	}
+	transition := findTransition(transitions, transitionName)
+	if transition == nil {
+		return bean.JiraIssueTransitionSkipped, fmt.Sprintf("transition %s is not available from status %s", transitionName, currentStatus)
+	}
+	if transition.To != nil && strings.EqualFold(currentStatus, transition.To.Name) {
+		return bean.JiraIssueTransitionSkipped, fmt.Sprintf("issue is already in status %s", currentStatus)
+	}
+	resp, err := impl.jiraClient.UpdateJiraTransition(clientRequest, issueKey, transition.Id)
+	if err != nil {
+		impl.logger.Errorw("error in transitioning jira issue", "issueKey", issueKey, "transitionId", transition.Id, "err", err)
+		return bean.JiraIssueTransitionFailed, err.Error()
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return bean.JiraIssueTransitionFailed, fmt.Sprintf("jira responded with status %d", resp.StatusCode)
+	}
+	return bean.JiraIssueTransitionSucceeded, ""
+}
+
+// getIssues fetches the issues from jira, an issue which could not be fetched is returned with the error
+func (impl *JiraIntegrationServiceImpl) getIssues(config *jiraRepository.JiraConfig, issueKeys []string) []*bean.LinkedIssue {
+	if len(issueKeys) > bean.MaxLinkedIssues {
+		issueKeys = issueKeys[:bean.MaxLinkedIssues]
+	}
+	clientRequest := client.CreateClientReq(config.Username, config.ApiToken.String(), config.JiraUrl)
+	issues := make([]*bean.LinkedIssue, len(issueKeys))
+	wg := &sync.WaitGroup{}
+	for i, issueKey := range issueKeys {
+		issues[i] = &bean.LinkedIssue{Key: issueKey, Url: getIssueUrl(config.JiraUrl, issueKey)}
+		wg.Add(1)
+		go func(linkedIssue *bean.LinkedIssue) {
+			defer wg.Done()
+			issue, err := impl.jiraClient.GetIssue(clientRequest, linkedIssue.Key)
+			if err != nil {
+				impl.logger.Warnw("error in fetching jira issue", "issueKey", linkedIssue.Key, "err", err)
+				linkedIssue.Error = err.Error()
+				return
+			}
+			linkedIssue.Summary = issue.Fields.Summary
+			if issue.Fields.Status != nil {
+				linkedIssue.Status = issue.Fields.Status.Name
+			}
+		}(issues[i])
+	}
+	wg.Wait()
+	return issues
+}
+
+// getArtifactIssueKeys extracts the issue keys from the material info of the artifact, artifacts created by the
+// pre/post stages are resolved to the artifact they were created from
+func (impl *JiraIntegrationServiceImpl) getArtifactIssueKeys(artifact *repository.CiArtifact, projectKeys []string) []string {
+	if len(artifact.MaterialInfo) == 0 && artifact.ParentCiArtifact > 0 {
+		parentArtifact, err := impl.ciArtifactRepository.Get(artifact.ParentCiArtifact)
+		if err != nil {
+			impl.logger.Errorw("error in fetching parent artifact", "artifactId", artifact.Id, "parentArtifactId", artifact.ParentCiArtifact, "err", err)
+			return []string{}
+		}
+		artifact = parentArtifact
+	}
+	if len(artifact.MaterialInfo) == 0 {
+		return []string{}
+	}
+	ciMaterials, err := repository.GetCiMaterialInfo(artifact.MaterialInfo, artifact.DataSource)
+	if err != nil {
+		impl.logger.Errorw("error in parsing material info of artifact", "artifactId", artifact.Id, "err", err)
+		return []string{}
+	}
+	return ExtractIssueKeys(ciMaterials, projectKeys)
+}
+
+func (impl *JiraIntegrationServiceImpl) authenticate(configDto *bean.JiraConfigDto) error {
+	clientRequest := client.CreateClientReq(configDto.Username, configDto.ApiToken, configDto.JiraUrl+client.JiraAuthenticateUserApi)
+	resp, err := impl.jiraClient.AuthenticateUserAccount(clientRequest)
+	if err != nil {
+		impl.logger.Errorw("error in authenticating jira account", "jiraUrl", configDto.JiraUrl, "err", err)
+		return util.NewApiError(http.StatusBadRequest, fmt.Sprintf("could not connect to jira, %s", err.Error()), err.Error())
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return util.NewApiError(http.StatusBadRequest, fmt.Sprintf("could not authenticate with jira, jira responded with status %d", resp.StatusCode), "jira authentication failed")
+	}
+	return nil
+}
+
+func (impl *JiraIntegrationServiceImpl) getJiraConfigForApp(appId int) (*jiraRepository.JiraConfig, error) {
+	app, err := impl.appRepository.FindById(appId)
+	if err != nil {
+		if util.IsErrNoRows(err) {
+			return nil, util.NewApiError(http.StatusNotFound, "app not found", err.Error())
+		}
+		impl.logger.Errorw("error in fetching app", "appId", appId, "err", err)
+		return nil, err
+	}
+	return impl.getJiraConfig(app.TeamId)
+}
+
+func (impl *JiraIntegrationServiceImpl) getJiraConfig(teamId int) (*jiraRepository.JiraConfig, error) {
+	config, err := impl.jiraConfigRepository.FindByTeamId(teamId)
+	if err != nil {
+		if util.IsErrNoRows(err) {
+			return nil, util.NewApiError(http.StatusNotFound, bean.JiraConfigNotFoundMessage, err.Error())
+		}
+		impl.logger.Errorw("error in fetching jira config", "teamId", teamId, "err", err)
+		return nil, err
+	}
+	return config, nil
+}
+
+func (impl *JiraIntegrationServiceImpl) adaptJiraConfig(config *jiraRepository.JiraConfig) (*bean.JiraConfigDto, error) {
+	rules, err := impl.jiraConfigRepository.FindTransitionRulesByConfigId(config.Id)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching jira transition rules", "jiraConfigId", config.Id, "err", err)
+		return nil, err
+	}
+	configDto := &bean.JiraConfigDto{
+		Id:              config.Id,
+		TeamId:          config.TeamId,
+		JiraUrl:         config.JiraUrl,
+		Username:        config.Username,
+		ApiToken:        bean.MaskedApiToken,
+		ProjectKeys:     config.ProjectKeys,
+		TransitionRules: make([]*bean.JiraTransitionRuleDto, 0, len(rules)),
+	}
+	if configDto.ProjectKeys == nil {
+		configDto.ProjectKeys = []string{}
+	}
+	for _, rule := range rules {
+		configDto.TransitionRules = append(configDto.TransitionRules, &bean.JiraTransitionRuleDto{
+			EnvironmentId:  rule.EnvironmentId,
+			TransitionName: rule.TransitionName,
+		})
+	}
+	return configDto, nil
+}
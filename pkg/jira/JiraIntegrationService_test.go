@@ -0,0 +1,131 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	client "github.com/devtron-labs/devtron/client/jira"
+	"github.com/devtron-labs/devtron/pkg/jira/bean"
+	jiraRepository "github.com/devtron-labs/devtron/pkg/jira/repository"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeJira serves the issue and transition apis of jira for a set of issues, transitions are applied in memory
+type fakeJira struct {
+	lock          sync.Mutex
+	issueStatuses map[string]string
+	transitions   []client.JiraTransition
+}
+
+func (f *fakeJira) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	// rest/api/latest/issue/{key}[/transitions]
+	if len(parts) < 5 || parts[3] != "issue" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	status, ok := f.issueStatuses[parts[4]]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	switch {
+	case len(parts) == 5 && r.Method == http.MethodGet:
+		_ = json.NewEncoder(w).Encode(client.JiraIssue{Key: parts[4], Fields: client.JiraIssueFields{Summary: "summary of " + parts[4], Status: &client.JiraIssueStatus{Name: status}}})
+	case len(parts) == 6 && r.Method == http.MethodGet:
+		_ = json.NewEncoder(w).Encode(client.TransitionResponse{Transitions: f.transitions})
+	case len(parts) == 6 && r.Method == http.MethodPost:
+		request := client.JiraTransitionUpdateRequest{}
+		_ = json.NewDecoder(r.Body).Decode(&request)
+		for _, transition := range f.transitions {
+			if transition.Id == request.JiraTransition.Id {
+				f.issueStatuses[parts[4]] = transition.To.Name
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+		}
+		w.WriteHeader(http.StatusBadRequest)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func TestTransitionIssue(t *testing.T) {
+	jira := &fakeJira{
+		issueStatuses: map[string]string{"DEV-1": "In Review", "DEV-2": "Deployed to Staging", "DEV-3": "In Review"},
+		transitions:   []client.JiraTransition{{Id: "31", Name: "Deploy to staging", To: &client.JiraIssueStatus{Name: "Deployed to Staging"}}},
+	}
+	server := httptest.NewServer(jira)
+	defer server.Close()
+	logger := zap.NewNop().Sugar()
+	impl := &JiraIntegrationServiceImpl{logger: logger, jiraClient: client.NewJiraClientImpl(logger, server.Client())}
+	clientRequest := client.CreateClientReq("user", "token", server.URL)
+
+	tests := []struct {
+		issueKey   string
+		wantStatus bean.JiraIssueTransitionStatus
+	}{
+		{issueKey: "DEV-1", wantStatus: bean.JiraIssueTransitionSucceeded},
+		{issueKey: "DEV-2", wantStatus: bean.JiraIssueTransitionSkipped},
+		{issueKey: "DEV-404", wantStatus: bean.JiraIssueTransitionFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.issueKey, func(t *testing.T) {
+			status, message := impl.transitionIssue(clientRequest, tt.issueKey, "Deployed to Staging")
+			if status != tt.wantStatus {
+				t.Errorf("transitionIssue() = %v (%s), want %v", status, message, tt.wantStatus)
+			}
+		})
+	}
+	if got := jira.issueStatuses["DEV-1"]; got != "Deployed to Staging" {
+		t.Errorf("status of DEV-1 = %s, want Deployed to Staging", got)
+	}
+	if status, _ := impl.transitionIssue(clientRequest, "DEV-2", "Deploy to staging"); status != bean.JiraIssueTransitionSkipped {
+		t.Errorf("transitionIssue() by transition name for issue in target status = %v, want %v", status, bean.JiraIssueTransitionSkipped)
+	}
+
+	jira.transitions = nil
+	if status, _ := impl.transitionIssue(clientRequest, "DEV-3", "Deployed to Staging"); status != bean.JiraIssueTransitionSkipped {
+		t.Errorf("transitionIssue() without available transition = %v, want %v", status, bean.JiraIssueTransitionSkipped)
+	}
+}
+
+func TestGetIssues(t *testing.T) {
+	server := httptest.NewServer(&fakeJira{issueStatuses: map[string]string{"DEV-1": "Done"}})
+	defer server.Close()
+	logger := zap.NewNop().Sugar()
+	impl := &JiraIntegrationServiceImpl{logger: logger, jiraClient: client.NewJiraClientImpl(logger, server.Client())}
+
+	issues := impl.getIssues(&jiraRepository.JiraConfig{JiraUrl: server.URL}, []string{"DEV-1", "DEV-2"})
+	if len(issues) != 2 {
+		t.Fatalf("getIssues() returned %d issues, want 2", len(issues))
+	}
+	if issues[0].Status != "Done" || issues[0].Summary != "summary of DEV-1" || issues[0].Url != fmt.Sprintf("%s/browse/DEV-1", server.URL) {
+		t.Errorf("getIssues() issue = %+v", issues[0])
+	}
+	if len(issues[1].Error) == 0 {
+		t.Errorf("getIssues() expected error for missing issue, got %+v", issues[1])
+	}
+}
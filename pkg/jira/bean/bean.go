@@ -0,0 +1,84 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import "time"
+
+type JiraIssueTransitionStatus string
+
+const (
+	JiraIssueTransitionSucceeded JiraIssueTransitionStatus = "SUCCEEDED"
+	JiraIssueTransitionFailed    JiraIssueTransitionStatus = "FAILED"
+	// JiraIssueTransitionSkipped is for the issues already in the target status or not having the transition from their current status
+	JiraIssueTransitionSkipped JiraIssueTransitionStatus = "SKIPPED"
+)
+
+func (s JiraIssueTransitionStatus) String() string {
+	return string(s)
+}
+
+const (
+	JiraConfigNotFoundMessage = "jira is not configured for the project"
+	// MaskedApiToken is returned in place of the api token, an update with the masked token keeps the saved token
+	MaskedApiToken = "**********"
+	// MaxLinkedIssues caps the issues fetched from jira for an artifact
+	MaxLinkedIssues = 50
+)
+
+// JiraConfigDto is the jira account used for the apps of a project
+type JiraConfigDto struct {
+	Id       int    `json:"id"`
+	TeamId   int    `json:"teamId" validate:"number,required"`
+	JiraUrl  string `json:"jiraUrl" validate:"required,url"`
+	Username string `json:"username" validate:"required"`
+	ApiToken string `json:"apiToken" validate:"required"`
+	// ProjectKeys restricts the issue keys picked from the commits to these jira projects, all keys are picked if empty
+	ProjectKeys     []string                 `json:"projectKeys"`
+	TransitionRules []*JiraTransitionRuleDto `json:"transitionRules" validate:"dive"`
+	UserId          int32                    `json:"-"`
+}
+
+// JiraTransitionRuleDto moves the linked issues through the transition once a deployment on the environment succeeds
+type JiraTransitionRuleDto struct {
+	EnvironmentId int `json:"environmentId" validate:"number,required"`
+	// TransitionName is the name of the transition or of the status it leads to, e.g. "Deployed to Staging"
+	TransitionName string `json:"transitionName" validate:"required"`
+}
+
+type LinkedIssue struct {
+	Key     string `json:"key"`
+	Summary string `json:"summary,omitempty"`
+	Status  string `json:"status,omitempty"`
+	Url     string `json:"url"`
+	// Error is set if the issue could not be fetched from jira
+	Error string `json:"error,omitempty"`
+}
+
+type IssueTransitionDto struct {
+	IssueKey       string                    `json:"issueKey"`
+	TransitionName string                    `json:"transitionName"`
+	Status         JiraIssueTransitionStatus `json:"status"`
+	Message        string                    `json:"message,omitempty"`
+	TransitionedOn time.Time                 `json:"transitionedOn"`
+}
+
+type LinkedIssuesResponse struct {
+	ArtifactId int            `json:"artifactId"`
+	Issues     []*LinkedIssue `json:"issues"`
+	// Transitions are the transitions applied to the issues after the deployment, only for deployments
+	Transitions []*IssueTransitionDto `json:"transitions,omitempty"`
+}
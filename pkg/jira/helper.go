@@ -0,0 +1,88 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jira
+
+import (
+	client "github.com/devtron-labs/devtron/client/jira"
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	"regexp"
+	"strings"
+)
+
+// issueKeyRegex matches jira issue keys like DEV-123, the project key being uppercase letters, digits and underscores
+var issueKeyRegex = regexp.MustCompile(`\b([A-Z][A-Z0-9_]+)-([1-9][0-9]*)\b`)
+
+// ExtractIssueKeys returns the issue keys mentioned in the commit messages, branches and webhook data of the materials,
+// in the order they are found. Only the keys of the given jira projects are returned if projectKeys is not empty.
+func ExtractIssueKeys(ciMaterials []repository.CiMaterialInfo, projectKeys []string) []string {
+	allowedProjects := make(map[string]bool, len(projectKeys))
+	for _, projectKey := range projectKeys {
+		allowedProjects[strings.ToUpper(projectKey)] = true
+	}
+	found := make(map[string]bool)
+	issueKeys := make([]string, 0)
+	addIssueKeys := func(text string) {
+		for _, match := range issueKeyRegex.FindAllStringSubmatch(text, -1) {
+			if len(allowedProjects) != 0 && !allowedProjects[match[1]] {
+				continue
+			}
+			if !found[match[0]] {
+				found[match[0]] = true
+				issueKeys = append(issueKeys, match[0])
+			}
+		}
+	}
+	for _, ciMaterial := range ciMaterials {
+		for _, modification := range ciMaterial.Modifications {
+			addIssueKeys(modification.Message)
+			addIssueKeys(modification.Branch)
+			for _, value := range modification.WebhookData.Data {
+				addIssueKeys(value)
+			}
+		}
+	}
+	return issueKeys
+}
+
+// findTransition returns the transition having the name, or leading to the status with the name
+func findTransition(transitions []client.JiraTransition, name string) *client.JiraTransition {
+	for i := range transitions {
+		if strings.EqualFold(transitions[i].Name, name) {
+			return &transitions[i]
+		}
+	}
+	for i := range transitions {
+		if transitions[i].To != nil && strings.EqualFold(transitions[i].To.Name, name) {
+			return &transitions[i]
+		}
+	}
+	return nil
+}
+
+func normaliseProjectKeys(projectKeys []string) []string {
+	normalised := make([]string, 0, len(projectKeys))
+	for _, projectKey := range projectKeys {
+		if projectKey = strings.ToUpper(strings.TrimSpace(projectKey)); len(projectKey) != 0 {
+			normalised = append(normalised, projectKey)
+		}
+	}
+	return normalised
+}
+
+func getIssueUrl(jiraUrl string, issueKey string) string {
+	return strings.TrimSuffix(jiraUrl, "/") + "/browse/" + issueKey
+}
@@ -0,0 +1,83 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jira
+
+import (
+	client "github.com/devtron-labs/devtron/client/jira"
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	"reflect"
+	"testing"
+)
+
+func TestExtractIssueKeys(t *testing.T) {
+	ciMaterials := []repository.CiMaterialInfo{
+		{
+			Modifications: []repository.Modification{
+				{Message: "DEV-12 fix login, refs OPS-7 and dev-13", Branch: "feature/DEV-14-login"},
+				{Message: "Merge DEV-12 into main, bump UTF-8 encoding"},
+			},
+		},
+		{
+			Modifications: []repository.Modification{
+				{WebhookData: repository.WebhookData{Data: map[string]string{"title": "OPS-8: rotate certificates"}}},
+			},
+		},
+	}
+	tests := []struct {
+		name        string
+		projectKeys []string
+		want        []string
+	}{
+		{name: "all projects", want: []string{"DEV-12", "OPS-7", "DEV-14", "UTF-8", "OPS-8"}},
+		{name: "configured projects only", projectKeys: []string{"dev", "OPS"}, want: []string{"DEV-12", "OPS-7", "DEV-14", "OPS-8"}},
+		{name: "unknown project", projectKeys: []string{"QA"}, want: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExtractIssueKeys(ciMaterials, tt.projectKeys); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExtractIssueKeys() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindTransition(t *testing.T) {
+	transitions := []client.JiraTransition{
+		{Id: "11", Name: "Start Progress", To: &client.JiraIssueStatus{Name: "In Progress"}},
+		{Id: "21", Name: "Deploy", To: &client.JiraIssueStatus{Name: "Deployed to Staging"}},
+	}
+	tests := []struct {
+		name   string
+		wantId string
+	}{
+		{name: "deploy", wantId: "21"},
+		{name: "deployed to staging", wantId: "21"},
+		{name: "Done", wantId: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := findTransition(transitions, tt.name)
+			gotId := ""
+			if got != nil {
+				gotId = got.Id
+			}
+			if gotId != tt.wantId {
+				t.Errorf("findTransition() = %v, want %v", gotId, tt.wantId)
+			}
+		})
+	}
+}
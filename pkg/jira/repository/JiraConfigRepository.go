@@ -0,0 +1,141 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/common-lib/securestore"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+type JiraConfig struct {
+	tableName   struct{}                    `sql:"jira_config" pg:",discard_unknown_columns"`
+	Id          int                         `sql:"id,pk"`
+	TeamId      int                         `sql:"team_id,notnull"`
+	JiraUrl     string                      `sql:"jira_url,notnull"`
+	Username    string                      `sql:"username,notnull"`
+	ApiToken    securestore.EncryptedString `sql:"api_token,notnull"`
+	ProjectKeys []string                    `sql:"project_keys,array"`
+	Active      bool                        `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+type JiraTransitionRule struct {
+	tableName      struct{} `sql:"jira_transition_rule" pg:",discard_unknown_columns"`
+	Id             int      `sql:"id,pk"`
+	JiraConfigId   int      `sql:"jira_config_id,notnull"`
+	EnvironmentId  int      `sql:"environment_id,notnull"`
+	TransitionName string   `sql:"transition_name,notnull"`
+	Active         bool     `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+type JiraConfigRepository interface {
+	//transaction util funcs
+	sql.TransactionWrapper
+	Save(config *JiraConfig, tx *pg.Tx) error
+	Update(config *JiraConfig, tx *pg.Tx) error
+	FindByTeamId(teamId int) (*JiraConfig, error)
+	FindAll() ([]*JiraConfig, error)
+
+	SaveTransitionRules(rules []*JiraTransitionRule, tx *pg.Tx) error
+	DeactivateTransitionRules(jiraConfigId int, userId int32, tx *pg.Tx) error
+	FindTransitionRulesByConfigId(jiraConfigId int) ([]*JiraTransitionRule, error)
+	FindTransitionRuleByConfigIdAndEnvId(jiraConfigId int, envId int) (*JiraTransitionRule, error)
+}
+
+type JiraConfigRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+	*sql.TransactionUtilImpl
+}
+
+func NewJiraConfigRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger, transactionUtilImpl *sql.TransactionUtilImpl) *JiraConfigRepositoryImpl {
+	return &JiraConfigRepositoryImpl{
+		dbConnection:        dbConnection,
+		logger:              logger,
+		TransactionUtilImpl: transactionUtilImpl,
+	}
+}
+
+func (repo *JiraConfigRepositoryImpl) Save(config *JiraConfig, tx *pg.Tx) error {
+	return tx.Insert(config)
+}
+
+func (repo *JiraConfigRepositoryImpl) Update(config *JiraConfig, tx *pg.Tx) error {
+	return tx.Update(config)
+}
+
+func (repo *JiraConfigRepositoryImpl) FindByTeamId(teamId int) (*JiraConfig, error) {
+	config := &JiraConfig{}
+	err := repo.dbConnection.Model(config).
+		Where("team_id = ?", teamId).
+		Where("active = ?", true).
+		Select()
+	return config, err
+}
+
+func (repo *JiraConfigRepositoryImpl) FindAll() ([]*JiraConfig, error) {
+	var configs []*JiraConfig
+	err := repo.dbConnection.Model(&configs).
+		Where("active = ?", true).
+		Order("team_id ASC").
+		Select()
+	return configs, err
+}
+
+func (repo *JiraConfigRepositoryImpl) SaveTransitionRules(rules []*JiraTransitionRule, tx *pg.Tx) error {
+	if len(rules) == 0 {
+		return nil
+	}
+	_, err := tx.Model(&rules).Insert()
+	return err
+}
+
+func (repo *JiraConfigRepositoryImpl) DeactivateTransitionRules(jiraConfigId int, userId int32, tx *pg.Tx) error {
+	_, err := tx.Model(&JiraTransitionRule{}).
+		Set("active = ?", false).
+		Set("updated_on = ?", time.Now()).
+		Set("updated_by = ?", userId).
+		Where("jira_config_id = ?", jiraConfigId).
+		Where("active = ?", true).
+		Update()
+	return err
+}
+
+func (repo *JiraConfigRepositoryImpl) FindTransitionRulesByConfigId(jiraConfigId int) ([]*JiraTransitionRule, error) {
+	var rules []*JiraTransitionRule
+	err := repo.dbConnection.Model(&rules).
+		Where("jira_config_id = ?", jiraConfigId).
+		Where("active = ?", true).
+		Order("id ASC").
+		Select()
+	return rules, err
+}
+
+func (repo *JiraConfigRepositoryImpl) FindTransitionRuleByConfigIdAndEnvId(jiraConfigId int, envId int) (*JiraTransitionRule, error) {
+	rule := &JiraTransitionRule{}
+	err := repo.dbConnection.Model(rule).
+		Where("jira_config_id = ?", jiraConfigId).
+		Where("environment_id = ?", envId).
+		Where("active = ?", true).
+		Limit(1).
+		Select()
+	return rule, err
+}
@@ -0,0 +1,69 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+)
+
+// JiraIssueTransition is the outcome of the transition of an issue linked to a deployment
+type JiraIssueTransition struct {
+	tableName          struct{} `sql:"jira_issue_transition" pg:",discard_unknown_columns"`
+	Id                 int      `sql:"id,pk"`
+	CdWorkflowRunnerId int      `sql:"cd_workflow_runner_id,notnull"`
+	IssueKey           string   `sql:"issue_key,notnull"`
+	TransitionName     string   `sql:"transition_name,notnull"`
+	Status             string   `sql:"status,notnull"`
+	Message            string   `sql:"message"`
+	sql.AuditLog
+}
+
+type JiraIssueTransitionRepository interface {
+	Save(transitions []*JiraIssueTransition) error
+	FindByCdWorkflowRunnerId(cdWorkflowRunnerId int) ([]*JiraIssueTransition, error)
+}
+
+type JiraIssueTransitionRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewJiraIssueTransitionRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *JiraIssueTransitionRepositoryImpl {
+	return &JiraIssueTransitionRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (repo *JiraIssueTransitionRepositoryImpl) Save(transitions []*JiraIssueTransition) error {
+	if len(transitions) == 0 {
+		return nil
+	}
+	_, err := repo.dbConnection.Model(&transitions).Insert()
+	return err
+}
+
+func (repo *JiraIssueTransitionRepositoryImpl) FindByCdWorkflowRunnerId(cdWorkflowRunnerId int) ([]*JiraIssueTransition, error) {
+	var transitions []*JiraIssueTransition
+	err := repo.dbConnection.Model(&transitions).
+		Where("cd_workflow_runner_id = ?", cdWorkflowRunnerId).
+		Order("id ASC").
+		Select()
+	return transitions, err
+}
@@ -0,0 +1,35 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jira
+
+import (
+	client "github.com/devtron-labs/devtron/client/jira"
+	"github.com/devtron-labs/devtron/pkg/jira/repository"
+	"github.com/google/wire"
+)
+
+var JiraWireSet = wire.NewSet(
+	client.NewJiraClientImpl,
+	wire.Bind(new(client.JiraClient), new(*client.JiraClientImpl)),
+	repository.NewJiraConfigRepositoryImpl,
+	wire.Bind(new(repository.JiraConfigRepository), new(*repository.JiraConfigRepositoryImpl)),
+	repository.NewJiraIssueTransitionRepositoryImpl,
+	wire.Bind(new(repository.JiraIssueTransitionRepository), new(*repository.JiraIssueTransitionRepositoryImpl)),
+
+	NewJiraIntegrationServiceImpl,
+	wire.Bind(new(JiraIntegrationService), new(*JiraIntegrationServiceImpl)),
+)
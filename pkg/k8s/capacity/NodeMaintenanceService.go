@@ -0,0 +1,550 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package capacity
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/caarlos0/env"
+	"github.com/devtron-labs/common-lib/async"
+	k8s2 "github.com/devtron-labs/common-lib/utils/k8s"
+	client "github.com/devtron-labs/devtron/api/helm-app/service"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/k8s"
+	"github.com/devtron-labs/devtron/pkg/k8s/capacity/bean"
+	"github.com/devtron-labs/devtron/pkg/k8s/capacity/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	cron2 "github.com/devtron-labs/devtron/util/cron"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type NodeMaintenanceConfig struct {
+	NodeMaintenanceCron                string `env:"NODE_MAINTENANCE_CRON" envDefault:"*/2 * * * *" description:"Cron schedule for picking up the node maintenance runs interrupted by a restart"`
+	NodeMaintenancePollIntervalSeconds int    `env:"NODE_MAINTENANCE_POLL_INTERVAL_SECONDS" envDefault:"5" description:"Seconds between two eviction attempts of the pods of a node being drained by a node maintenance run"`
+}
+
+type NodeMaintenanceService interface {
+	// StartRun cordons and drains the selected nodes of a cluster in a rolling fashion, at most concurrency nodes at
+	// a time. The run is progressed asynchronously and stops at the first node which could not be drained.
+	StartRun(ctx context.Context, request *bean.NodeMaintenanceRequest) (*bean.NodeMaintenanceRunDto, error)
+	GetRun(runId int) (*bean.NodeMaintenanceRunDto, error)
+	GetRuns(clusterId int, offset, limit int) ([]*bean.NodeMaintenanceRunDto, error)
+	// ResumeRun retries the failed and aborted nodes of a failed or aborted run along with the nodes not drained yet
+	ResumeRun(runId int, userId int32) (*bean.NodeMaintenanceRunDto, error)
+	// AbortRun stops the run, nodes being drained are left cordoned
+	AbortRun(runId int, userId int32) (*bean.NodeMaintenanceRunDto, error)
+	// ProcessRun drains the pending nodes of a running run, returns once the run has finished
+	ProcessRun(runId int)
+	// ProcessRunningRuns picks up the running runs which are not being processed, called periodically
+	ProcessRunningRuns()
+}
+
+type NodeMaintenanceServiceImpl struct {
+	logger                       *zap.SugaredLogger
+	nodeMaintenanceRunRepository repository.NodeMaintenanceRunRepository
+	k8sCommonService             k8s.K8sCommonService
+	K8sUtil                      *k8s2.K8sServiceImpl
+	asyncRunnable                *async.Runnable
+	pollInterval                 time.Duration
+	// runsInProgress are the ids of the runs being processed by this instance
+	runsInProgress sync.Map
+	cron           *cron.Cron
+}
+
+func NewNodeMaintenanceServiceImpl(logger *zap.SugaredLogger,
+	nodeMaintenanceRunRepository repository.NodeMaintenanceRunRepository,
+	k8sCommonService k8s.K8sCommonService,
+	K8sUtil *k8s2.K8sServiceImpl,
+	asyncRunnable *async.Runnable,
+	cronLogger *cron2.CronLoggerImpl) *NodeMaintenanceServiceImpl {
+	impl := &NodeMaintenanceServiceImpl{
+		logger:                       logger,
+		nodeMaintenanceRunRepository: nodeMaintenanceRunRepository,
+		k8sCommonService:             k8sCommonService,
+		K8sUtil:                      K8sUtil,
+		asyncRunnable:                asyncRunnable,
+		pollInterval:                 5 * time.Second,
+	}
+	cfg := &NodeMaintenanceConfig{}
+	if err := env.Parse(cfg); err != nil {
+		logger.Errorw("error in parsing node maintenance config", "err", err)
+		return impl
+	}
+	if cfg.NodeMaintenancePollIntervalSeconds > 0 {
+		impl.pollInterval = time.Duration(cfg.NodeMaintenancePollIntervalSeconds) * time.Second
+	}
+	impl.cron = cron.New(cron.WithChain(cron.Recover(cronLogger)))
+	impl.cron.Start()
+	_, err := impl.cron.AddFunc(cfg.NodeMaintenanceCron, impl.ProcessRunningRuns)
+	if err != nil {
+		logger.Errorw("error in starting node maintenance cron", "cron", cfg.NodeMaintenanceCron, "err", err)
+	}
+	return impl
+}
+
+func (impl *NodeMaintenanceServiceImpl) StartRun(ctx context.Context, request *bean.NodeMaintenanceRequest) (*bean.NodeMaintenanceRunDto, error) {
+	if len(request.NodeNames) == 0 && len(request.NodeGroup) == 0 && len(request.NodeSelector) == 0 {
+		return nil, util.NewApiError(http.StatusBadRequest, "select the nodes by name, node group or label selector", "no node selection in request")
+	}
+	if _, err := labels.Parse(request.NodeSelector); err != nil {
+		return nil, util.NewApiError(http.StatusBadRequest, fmt.Sprintf("invalid node selector, %s", err.Error()), err.Error())
+	}
+	if request.Concurrency <= 0 {
+		request.Concurrency = bean.NodeMaintenanceDefaultConcurrency
+	} else if request.Concurrency > bean.NodeMaintenanceMaxConcurrency {
+		request.Concurrency = bean.NodeMaintenanceMaxConcurrency
+	}
+	if request.DrainTimeoutSeconds <= 0 {
+		request.DrainTimeoutSeconds = bean.NodeMaintenanceDefaultDrainTimeoutSeconds
+	}
+	if err := impl.validateNoRunInProgress(request.ClusterId); err != nil {
+		return nil, err
+	}
+	_, _, k8sClientSet, err := impl.k8sCommonService.GetK8sConfigAndClientsByClusterId(ctx, request.ClusterId)
+	if err != nil {
+		impl.logger.Errorw("error in getting k8s client for cluster", "clusterId", request.ClusterId, "err", err)
+		return nil, err
+	}
+	nodeList, err := k8sClientSet.CoreV1().Nodes().List(ctx, v1.ListOptions{LabelSelector: request.NodeSelector})
+	if err != nil {
+		impl.logger.Errorw("error in listing nodes", "clusterId", request.ClusterId, "nodeSelector", request.NodeSelector, "err", err)
+		return nil, err
+	}
+	nodeNames, err := selectNodesForMaintenance(nodeList.Items, request)
+	if err != nil {
+		return nil, util.NewApiError(http.StatusBadRequest, err.Error(), err.Error())
+	}
+	if len(nodeNames) == 0 {
+		return nil, util.NewApiError(http.StatusBadRequest, "no nodes found for the selection", "no nodes found for the selection")
+	}
+	drainOptions, err := json.Marshal(request.NodeDrainHelper)
+	if err != nil {
+		impl.logger.Errorw("error in marshalling drain options", "err", err)
+		return nil, err
+	}
+	run := &repository.NodeMaintenanceRun{
+		ClusterId:           request.ClusterId,
+		NodeSelector:        request.NodeSelector,
+		NodeGroup:           request.NodeGroup,
+		Concurrency:         request.Concurrency,
+		DrainTimeoutSeconds: request.DrainTimeoutSeconds,
+		DrainOptions:        string(drainOptions),
+		Status:              bean.NodeMaintenanceRunRunning.String(),
+		StartedOn:           time.Now(),
+		AuditLog:            sql.NewDefaultAuditLog(request.UserId),
+	}
+	tx, err := impl.nodeMaintenanceRunRepository.StartTx()
+	if err != nil {
+		impl.logger.Errorw("error in starting transaction", "err", err)
+		return nil, err
+	}
+	defer impl.nodeMaintenanceRunRepository.RollbackTx(tx)
+	if err = impl.nodeMaintenanceRunRepository.SaveRun(run, tx); err != nil {
+		impl.logger.Errorw("error in saving node maintenance run", "clusterId", request.ClusterId, "err", err)
+		return nil, err
+	}
+	nodes := make([]*repository.NodeMaintenanceNode, 0, len(nodeNames))
+	for i, nodeName := range nodeNames {
+		nodes = append(nodes, &repository.NodeMaintenanceNode{
+			RunId:     run.Id,
+			NodeName:  nodeName,
+			NodeOrder: i,
+			Status:    bean.NodeMaintenanceNodePending.String(),
+			AuditLog:  sql.NewDefaultAuditLog(request.UserId),
+		})
+	}
+	if err = impl.nodeMaintenanceRunRepository.SaveNodes(nodes, tx); err != nil {
+		impl.logger.Errorw("error in saving node maintenance nodes", "runId", run.Id, "err", err)
+		return nil, err
+	}
+	if err = impl.nodeMaintenanceRunRepository.CommitTx(tx); err != nil {
+		impl.logger.Errorw("error in committing transaction", "runId", run.Id, "err", err)
+		return nil, err
+	}
+	runId := run.Id
+	impl.asyncRunnable.Execute(func() { impl.ProcessRun(runId) })
+	return adaptNodeMaintenanceRun(run, nodes, true), nil
+}
+
+func (impl *NodeMaintenanceServiceImpl) GetRun(runId int) (*bean.NodeMaintenanceRunDto, error) {
+	run, err := impl.nodeMaintenanceRunRepository.FindRunById(runId)
+	if err != nil {
+		if util.IsErrNoRows(err) {
+			return nil, util.NewApiError(http.StatusNotFound, "node maintenance run not found", err.Error())
+		}
+		impl.logger.Errorw("error in fetching node maintenance run", "runId", runId, "err", err)
+		return nil, err
+	}
+	nodes, err := impl.nodeMaintenanceRunRepository.FindNodesByRunId(runId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching node maintenance nodes", "runId", runId, "err", err)
+		return nil, err
+	}
+	return adaptNodeMaintenanceRun(run, nodes, true), nil
+}
+
+func (impl *NodeMaintenanceServiceImpl) GetRuns(clusterId int, offset, limit int) ([]*bean.NodeMaintenanceRunDto, error) {
+	runs, err := impl.nodeMaintenanceRunRepository.FindRunsByClusterId(clusterId, offset, limit)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching node maintenance runs", "clusterId", clusterId, "err", err)
+		return nil, err
+	}
+	runDtos := make([]*bean.NodeMaintenanceRunDto, 0, len(runs))
+	for _, run := range runs {
+		nodes, err := impl.nodeMaintenanceRunRepository.FindNodesByRunId(run.Id)
+		if err != nil && !util.IsErrNoRows(err) {
+			impl.logger.Errorw("error in fetching node maintenance nodes", "runId", run.Id, "err", err)
+			return nil, err
+		}
+		runDtos = append(runDtos, adaptNodeMaintenanceRun(run, nodes, false))
+	}
+	return runDtos, nil
+}
+
+func (impl *NodeMaintenanceServiceImpl) ResumeRun(runId int, userId int32) (*bean.NodeMaintenanceRunDto, error) {
+	run, err := impl.GetRun(runId)
+	if err != nil {
+		return nil, err
+	}
+	if err = impl.validateNoRunInProgress(run.ClusterId); err != nil {
+		return nil, err
+	}
+	resumed, err := impl.nodeMaintenanceRunRepository.UpdateRunStatus(runId,
+		[]string{bean.NodeMaintenanceRunFailed.String(), bean.NodeMaintenanceRunAborted.String()},
+		bean.NodeMaintenanceRunRunning.String(), "", nil, userId)
+	if err != nil {
+		return nil, err
+	}
+	if !resumed {
+		return nil, util.NewApiError(http.StatusConflict, "only failed or aborted runs can be resumed", "run is not in failed or aborted status")
+	}
+	err = impl.nodeMaintenanceRunRepository.UpdateNodesStatus(runId,
+		[]string{bean.NodeMaintenanceNodeFailed.String(), bean.NodeMaintenanceNodeAborted.String()},
+		bean.NodeMaintenanceNodePending.String(), "", userId)
+	if err != nil {
+		return nil, err
+	}
+	impl.asyncRunnable.Execute(func() { impl.ProcessRun(runId) })
+	return impl.GetRun(runId)
+}
+
+func (impl *NodeMaintenanceServiceImpl) AbortRun(runId int, userId int32) (*bean.NodeMaintenanceRunDto, error) {
+	now := time.Now()
+	aborted, err := impl.nodeMaintenanceRunRepository.UpdateRunStatus(runId,
+		[]string{bean.NodeMaintenanceRunRunning.String(), bean.NodeMaintenanceRunFailed.String()},
+		bean.NodeMaintenanceRunAborted.String(), "aborted by user", &now, userId)
+	if err != nil {
+		return nil, err
+	}
+	if !aborted {
+		if _, err = impl.GetRun(runId); err != nil {
+			return nil, err
+		}
+		return nil, util.NewApiError(http.StatusConflict, "only running or failed runs can be aborted", "run is not in running or failed status")
+	}
+	// nodes being drained are marked aborted by their drain once it notices the run is not running anymore
+	err = impl.nodeMaintenanceRunRepository.UpdateNodesStatus(runId, []string{bean.NodeMaintenanceNodePending.String()},
+		bean.NodeMaintenanceNodeAborted.String(), "run aborted before the node was drained", userId)
+	if err != nil {
+		return nil, err
+	}
+	return impl.GetRun(runId)
+}
+
+func (impl *NodeMaintenanceServiceImpl) ProcessRunningRuns() {
+	runs, err := impl.nodeMaintenanceRunRepository.FindRunsByStatus(bean.NodeMaintenanceRunRunning.String())
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching running node maintenance runs", "err", err)
+		return
+	}
+	for _, run := range runs {
+		runId := run.Id
+		if _, inProgress := impl.runsInProgress.Load(runId); inProgress {
+			continue
+		}
+		impl.asyncRunnable.Execute(func() { impl.ProcessRun(runId) })
+	}
+}
+
+func (impl *NodeMaintenanceServiceImpl) ProcessRun(runId int) {
+	if _, inProgress := impl.runsInProgress.LoadOrStore(runId, true); inProgress {
+		return
+	}
+	defer impl.runsInProgress.Delete(runId)
+	run, err := impl.nodeMaintenanceRunRepository.FindRunById(runId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching node maintenance run", "runId", runId, "err", err)
+		return
+	}
+	if run.Status != bean.NodeMaintenanceRunRunning.String() {
+		return
+	}
+	drainOptions := bean.NodeDrainHelper{}
+	if len(run.DrainOptions) != 0 {
+		if err = json.Unmarshal([]byte(run.DrainOptions), &drainOptions); err != nil {
+			impl.logger.Errorw("error in unmarshalling drain options", "runId", runId, "err", err)
+			return
+		}
+	}
+	nodes, err := impl.nodeMaintenanceRunRepository.FindNodesByRunId(runId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching node maintenance nodes", "runId", runId, "err", err)
+		return
+	}
+	impl.releaseInterruptedNodes(run, nodes)
+
+	// rolling drain, a new node is picked as soon as one of the nodes being drained is done
+	slots := make(chan struct{}, run.Concurrency)
+	wg := &sync.WaitGroup{}
+	var failed atomic.Bool
+	for _, node := range nodes {
+		if node.Status != bean.NodeMaintenanceNodePending.String() {
+			continue
+		}
+		slots <- struct{}{}
+		if failed.Load() || !impl.isRunRunning(runId) {
+			<-slots
+			break
+		}
+		claimed, err := impl.nodeMaintenanceRunRepository.UpdateNodeStatus(node.Id, bean.NodeMaintenanceNodePending.String(), bean.NodeMaintenanceNodeDraining.String(), run.UpdatedBy)
+		if err != nil || !claimed {
+			// the node is being drained by another instance
+			<-slots
+			continue
+		}
+		wg.Add(1)
+		nodeToDrain := node
+		impl.asyncRunnable.Execute(func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			if status := impl.drainNode(run, nodeToDrain, drainOptions); status == bean.NodeMaintenanceNodeFailed {
+				failed.Store(true)
+			}
+		})
+	}
+	wg.Wait()
+	impl.finishRun(run)
+}
+
+// releaseInterruptedNodes moves back to pending the nodes left draining by an instance which went down,
+// a node being drained is updated at every poll so a node not updated for a while is not being drained anymore
+func (impl *NodeMaintenanceServiceImpl) releaseInterruptedNodes(run *repository.NodeMaintenanceRun, nodes []*repository.NodeMaintenanceNode) {
+	staleAfter := 3*impl.pollInterval + time.Minute
+	for _, node := range nodes {
+		if node.Status != bean.NodeMaintenanceNodeDraining.String() || time.Since(node.UpdatedOn) < staleAfter {
+			continue
+		}
+		released, err := impl.nodeMaintenanceRunRepository.UpdateNodeStatus(node.Id, bean.NodeMaintenanceNodeDraining.String(), bean.NodeMaintenanceNodePending.String(), run.UpdatedBy)
+		if err != nil {
+			impl.logger.Errorw("error in releasing interrupted node", "runId", run.Id, "nodeName", node.NodeName, "err", err)
+			continue
+		}
+		if released {
+			impl.logger.Infow("releasing interrupted drain of node", "runId", run.Id, "nodeName", node.NodeName)
+			node.Status = bean.NodeMaintenanceNodePending.String()
+		}
+	}
+}
+
+// finishRun marks the run failed if any node failed and succeeded once all the nodes are done
+func (impl *NodeMaintenanceServiceImpl) finishRun(run *repository.NodeMaintenanceRun) {
+	nodes, err := impl.nodeMaintenanceRunRepository.FindNodesByRunId(run.Id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching node maintenance nodes", "runId", run.Id, "err", err)
+		return
+	}
+	progress := getNodeMaintenanceProgress(nodes)
+	if progress.Draining > 0 {
+		// nodes are still being drained by another instance, which finishes the run
+		return
+	}
+	now := time.Now()
+	status, message := bean.NodeMaintenanceRunSucceeded, ""
+	if progress.Failed > 0 {
+		status = bean.NodeMaintenanceRunFailed
+		message = fmt.Sprintf("%d node(s) could not be drained, resume the run once the blocking pods are handled", progress.Failed)
+	} else if progress.Pending > 0 {
+		return
+	}
+	if _, err = impl.nodeMaintenanceRunRepository.UpdateRunStatus(run.Id, []string{bean.NodeMaintenanceRunRunning.String()}, status.String(), message, &now, run.UpdatedBy); err != nil {
+		impl.logger.Errorw("error in updating node maintenance run status", "runId", run.Id, "status", status, "err", err)
+	}
+}
+
+// drainNode cordons the node and evicts its pods until none is left or the drain times out, pods whose eviction is
+// refused (e.g. by a PodDisruptionBudget) are retried at every poll and reported as blocking. Returns the final status of the node.
+func (impl *NodeMaintenanceServiceImpl) drainNode(run *repository.NodeMaintenanceRun, node *repository.NodeMaintenanceNode, drainOptions bean.NodeDrainHelper) bean.NodeMaintenanceNodeStatus {
+	ctx := context.Background()
+	startedOn := time.Now()
+	node.Status = bean.NodeMaintenanceNodeDraining.String()
+	node.StartedOn = &startedOn
+	node.FinishedOn = nil
+	node.Message = "cordoning node"
+	node.BlockingPods = ""
+	impl.updateNode(run, node)
+
+	_, _, k8sClientSet, err := impl.k8sCommonService.GetK8sConfigAndClientsByClusterId(ctx, run.ClusterId)
+	if err != nil {
+		impl.logger.Errorw("error in getting k8s client for cluster", "clusterId", run.ClusterId, "err", err)
+		return impl.finishNode(run, node, bean.NodeMaintenanceNodeFailed, err.Error(), nil)
+	}
+	k8sNode, err := impl.K8sUtil.GetNodeByName(ctx, k8sClientSet, node.NodeName)
+	if err != nil {
+		if client.IsNodeNotFoundError(err) || apierrors.IsNotFound(err) {
+			return impl.finishNode(run, node, bean.NodeMaintenanceNodeSkipped, "node not found in the cluster", nil)
+		}
+		impl.logger.Errorw("error in getting node", "nodeName", node.NodeName, "err", err)
+		return impl.finishNode(run, node, bean.NodeMaintenanceNodeFailed, err.Error(), nil)
+	}
+	if !k8sNode.Spec.Unschedulable {
+		if _, err = k8s2.UpdateNodeUnschedulableProperty(true, k8sNode, k8sClientSet); err != nil {
+			impl.logger.Errorw("error in cordoning node", "nodeName", node.NodeName, "err", err)
+			return impl.finishNode(run, node, bean.NodeMaintenanceNodeFailed, fmt.Sprintf("node could not be cordoned, %s", err.Error()), nil)
+		}
+	}
+	drainOptions.K8sClientSet = k8sClientSet
+	evictPod, err := impl.getPodEvictionFunc(k8sClientSet, drainOptions)
+	if err != nil {
+		impl.logger.Errorw("error in checking eviction support", "clusterId", run.ClusterId, "err", err)
+		return impl.finishNode(run, node, bean.NodeMaintenanceNodeFailed, err.Error(), nil)
+	}
+	timeout := time.Duration(run.DrainTimeoutSeconds) * time.Second
+	deadline := startedOn.Add(timeout)
+	for {
+		if !impl.isRunRunning(run.Id) {
+			return impl.finishNode(run, node, bean.NodeMaintenanceNodeAborted, "run aborted while draining, node is left cordoned", nil)
+		}
+		podList, errs := GetPodsByNodeNameForDeletion(node.NodeName, &drainOptions)
+		if podList == nil {
+			return impl.finishNode(run, node, bean.NodeMaintenanceNodeFailed, fmt.Sprintf("pods of node could not be listed, %v", errs), nil)
+		}
+		if blockedPods := podList.BlockedPods(); len(blockedPods) != 0 {
+			return impl.finishNode(run, node, bean.NodeMaintenanceNodeFailed, "pods cannot be deleted with the drain options", getBlockingPods(blockedPods))
+		}
+		pods := podList.Pods()
+		if len(pods) == 0 {
+			return impl.finishNode(run, node, bean.NodeMaintenanceNodeDrained, "", nil)
+		}
+		blockingPods := make([]*bean.NodeMaintenanceBlockingPod, 0)
+		terminatingPods := 0
+		for _, pod := range pods {
+			if pod.DeletionTimestamp != nil {
+				terminatingPods++
+				continue
+			}
+			err = evictPod(pod)
+			if err == nil || apierrors.IsNotFound(err) {
+				node.EvictedPodCount++
+				continue
+			}
+			blockingPods = append(blockingPods, &bean.NodeMaintenanceBlockingPod{Name: pod.Name, Namespace: pod.Namespace, Reason: err.Error()})
+		}
+		if time.Now().After(deadline) {
+			return impl.finishNode(run, node, bean.NodeMaintenanceNodeFailed, fmt.Sprintf("drain timed out after %s with %d pod(s) left on the node", timeout.String(), len(pods)), blockingPods)
+		}
+		node.Message = fmt.Sprintf("waiting for %d pod(s) to be evicted, %d terminating", len(pods)-terminatingPods, terminatingPods)
+		node.BlockingPods = marshalBlockingPods(blockingPods)
+		impl.updateNode(run, node)
+		time.Sleep(impl.pollInterval)
+	}
+}
+
+// getPodEvictionFunc evicts pods through the eviction api so that PodDisruptionBudgets are honoured, pods are deleted
+// if eviction is disabled in the drain options or not supported by the cluster
+func (impl *NodeMaintenanceServiceImpl) getPodEvictionFunc(k8sClientSet *kubernetes.Clientset, drainOptions bean.NodeDrainHelper) (func(pod corev1.Pod) error, error) {
+	deleteOptions := v1.DeleteOptions{}
+	if drainOptions.GracePeriodSeconds >= 0 {
+		gracePeriodSeconds := int64(drainOptions.GracePeriodSeconds)
+		deleteOptions.GracePeriodSeconds = &gracePeriodSeconds
+	}
+	if !drainOptions.DisableEviction {
+		evictionGroupVersion, err := k8s2.CheckEvictionSupport(k8sClientSet)
+		if err != nil {
+			return nil, err
+		}
+		if !evictionGroupVersion.Empty() {
+			return func(pod corev1.Pod) error {
+				return k8s2.EvictPod(pod, k8sClientSet, evictionGroupVersion, deleteOptions)
+			}, nil
+		}
+	}
+	return func(pod corev1.Pod) error {
+		return k8s2.DeletePod(pod, k8sClientSet, deleteOptions)
+	}, nil
+}
+
+func (impl *NodeMaintenanceServiceImpl) finishNode(run *repository.NodeMaintenanceRun, node *repository.NodeMaintenanceNode, status bean.NodeMaintenanceNodeStatus,
+	message string, blockingPods []*bean.NodeMaintenanceBlockingPod) bean.NodeMaintenanceNodeStatus {
+	now := time.Now()
+	node.Status = status.String()
+	node.Message = message
+	node.BlockingPods = marshalBlockingPods(blockingPods)
+	node.FinishedOn = &now
+	impl.updateNode(run, node)
+	return status
+}
+
+func (impl *NodeMaintenanceServiceImpl) updateNode(run *repository.NodeMaintenanceRun, node *repository.NodeMaintenanceNode) {
+	node.UpdateAuditLog(run.UpdatedBy)
+	if err := impl.nodeMaintenanceRunRepository.UpdateNode(node); err != nil {
+		impl.logger.Errorw("error in updating node maintenance node", "runId", run.Id, "nodeName", node.NodeName, "err", err)
+	}
+}
+
+func (impl *NodeMaintenanceServiceImpl) isRunRunning(runId int) bool {
+	run, err := impl.nodeMaintenanceRunRepository.FindRunById(runId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching node maintenance run", "runId", runId, "err", err)
+		// keep draining, the status is checked again at the next poll
+		return true
+	}
+	return run.Status == bean.NodeMaintenanceRunRunning.String()
+}
+
+func (impl *NodeMaintenanceServiceImpl) validateNoRunInProgress(clusterId int) error {
+	runs, err := impl.nodeMaintenanceRunRepository.FindRunsByClusterIdAndStatuses(clusterId, []string{bean.NodeMaintenanceRunRunning.String()})
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching running node maintenance runs", "clusterId", clusterId, "err", err)
+		return err
+	}
+	if len(runs) != 0 {
+		return util.NewApiError(http.StatusConflict, fmt.Sprintf("node maintenance run %d is already in progress on the cluster", runs[0].Id), "node maintenance run in progress")
+	}
+	return nil
+}
+
+func marshalBlockingPods(blockingPods []*bean.NodeMaintenanceBlockingPod) string {
+	if len(blockingPods) == 0 {
+		return ""
+	}
+	blockingPodsJson, err := json.Marshal(blockingPods)
+	if err != nil {
+		return ""
+	}
+	return string(blockingPodsJson)
+}
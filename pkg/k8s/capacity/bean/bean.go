@@ -210,8 +210,8 @@ type NodeDrainHelper struct {
 	GracePeriodSeconds  int  `json:"gracePeriodSeconds"`
 	IgnoreAllDaemonSets bool `json:"ignoreAllDaemonSets"`
 	// DisableEviction forces drain to use delete rather than evict
-	DisableEviction bool `json:"disableEviction"`
-	K8sClientSet    *kubernetes.Clientset
+	DisableEviction bool                  `json:"disableEviction"`
+	K8sClientSet    *kubernetes.Clientset `json:"-"`
 }
 
 type NodeDetails struct {
@@ -242,6 +242,17 @@ func (l *PodDeleteList) Pods() []corev1.Pod {
 	return pods
 }
 
+// BlockedPods returns the pods which cannot be deleted with the drain options along with the reason
+func (l *PodDeleteList) BlockedPods() []PodDelete {
+	pods := make([]PodDelete, 0)
+	for _, i := range l.items {
+		if i.Status.Reason == PodDeleteStatusTypeError {
+			pods = append(pods, i)
+		}
+	}
+	return pods
+}
+
 func (l *PodDeleteList) Errors() []error {
 	failedPods := make(map[string][]string)
 	for _, i := range l.items {
@@ -0,0 +1,114 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import "time"
+
+type NodeMaintenanceRunStatus string
+
+const (
+	NodeMaintenanceRunRunning   NodeMaintenanceRunStatus = "Running"
+	NodeMaintenanceRunSucceeded NodeMaintenanceRunStatus = "Succeeded"
+	NodeMaintenanceRunFailed    NodeMaintenanceRunStatus = "Failed"
+	NodeMaintenanceRunAborted   NodeMaintenanceRunStatus = "Aborted"
+)
+
+type NodeMaintenanceNodeStatus string
+
+const (
+	NodeMaintenanceNodePending  NodeMaintenanceNodeStatus = "Pending"
+	NodeMaintenanceNodeDraining NodeMaintenanceNodeStatus = "Draining"
+	NodeMaintenanceNodeDrained  NodeMaintenanceNodeStatus = "Drained"
+	NodeMaintenanceNodeFailed   NodeMaintenanceNodeStatus = "Failed"
+	// NodeMaintenanceNodeSkipped is set for nodes removed from the cluster before being drained, e.g. by a node pool upgrade
+	NodeMaintenanceNodeSkipped NodeMaintenanceNodeStatus = "Skipped"
+	NodeMaintenanceNodeAborted NodeMaintenanceNodeStatus = "Aborted"
+)
+
+const (
+	NodeMaintenanceDefaultConcurrency         = 1
+	NodeMaintenanceMaxConcurrency             = 10
+	NodeMaintenanceDefaultDrainTimeoutSeconds = 600
+)
+
+// NodeMaintenanceRequest selects the nodes to drain either by name, by node group or by label selector
+type NodeMaintenanceRequest struct {
+	ClusterId    int      `json:"clusterId" validate:"number,required"`
+	NodeNames    []string `json:"nodeNames"`
+	NodeGroup    string   `json:"nodeGroup"`
+	NodeSelector string   `json:"nodeSelector"`
+	// Concurrency is the number of nodes drained at the same time
+	Concurrency int `json:"concurrency" validate:"omitempty,min=1,max=10"`
+	// DrainTimeoutSeconds is the time a node has for evicting all its pods, the run fails on timeout
+	DrainTimeoutSeconds int              `json:"drainTimeoutSeconds" validate:"omitempty,min=1"`
+	NodeDrainHelper     *NodeDrainHelper `json:"nodeDrainOptions" validate:"required"`
+	UserId              int32            `json:"-"`
+}
+
+type NodeMaintenanceRunDto struct {
+	Id                  int                       `json:"id"`
+	ClusterId           int                       `json:"clusterId"`
+	NodeGroup           string                    `json:"nodeGroup,omitempty"`
+	NodeSelector        string                    `json:"nodeSelector,omitempty"`
+	Concurrency         int                       `json:"concurrency"`
+	DrainTimeoutSeconds int                       `json:"drainTimeoutSeconds"`
+	NodeDrainHelper     *NodeDrainHelper          `json:"nodeDrainOptions,omitempty"`
+	Status              NodeMaintenanceRunStatus  `json:"status"`
+	Message             string                    `json:"message,omitempty"`
+	StartedOn           time.Time                 `json:"startedOn"`
+	FinishedOn          *time.Time                `json:"finishedOn,omitempty"`
+	TriggeredBy         int32                     `json:"triggeredBy"`
+	Progress            *NodeMaintenanceProgress  `json:"progress"`
+	Nodes               []*NodeMaintenanceNodeDto `json:"nodes,omitempty"`
+}
+
+// NodeMaintenanceProgress is the count of nodes of a run by status
+type NodeMaintenanceProgress struct {
+	Total    int `json:"total"`
+	Pending  int `json:"pending"`
+	Draining int `json:"draining"`
+	Drained  int `json:"drained"`
+	Failed   int `json:"failed"`
+	Skipped  int `json:"skipped"`
+	Aborted  int `json:"aborted"`
+}
+
+type NodeMaintenanceNodeDto struct {
+	NodeName        string                        `json:"nodeName"`
+	Status          NodeMaintenanceNodeStatus     `json:"status"`
+	Message         string                        `json:"message,omitempty"`
+	EvictedPodCount int                           `json:"evictedPodCount"`
+	BlockingPods    []*NodeMaintenanceBlockingPod `json:"blockingPods,omitempty"`
+	StartedOn       *time.Time                    `json:"startedOn,omitempty"`
+	FinishedOn      *time.Time                    `json:"finishedOn,omitempty"`
+}
+
+// NodeMaintenanceBlockingPod is a pod which prevents a node from being drained, either because the drain options
+// do not allow deleting it or because its eviction is refused, e.g. by a PodDisruptionBudget
+type NodeMaintenanceBlockingPod struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Reason    string `json:"reason"`
+}
+
+func (s NodeMaintenanceRunStatus) String() string {
+	return string(s)
+}
+
+func (s NodeMaintenanceNodeStatus) String() string {
+	return string(s)
+}
@@ -18,6 +18,7 @@ package capacity
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/devtron-labs/common-lib/utils"
 	k8s2 "github.com/devtron-labs/common-lib/utils/k8s"
@@ -343,14 +344,7 @@ func (impl *K8sCapacityServiceImpl) getNodeGroupAndTaints(node *corev1.Node) (st
 }
 
 func (impl *K8sCapacityServiceImpl) getNodeGroup(node *corev1.Node) string {
-	var nodeGroup = ""
-	//different cloud providers have their own node group label
-	for _, label := range bean.NodeGroupLabels {
-		if ng, ok := node.Labels[label]; ok {
-			nodeGroup = ng
-		}
-	}
-	return nodeGroup
+	return getNodeGroup(node)
 }
 
 func (impl *K8sCapacityServiceImpl) getNodeDetail(ctx context.Context, node *corev1.Node, nodeResourceUsage map[string]corev1.ResourceList, podList *corev1.PodList, callForList bool, cluster *bean2.ClusterBean) (*bean.NodeCapacityDetail, error) {
@@ -753,14 +747,14 @@ func parseTaint(taint corev1.Taint) error {
 	if len(value) > 0 {
 		if errStrs := validation.IsValidLabelValue(value); len(errStrs) > 0 {
 			for _, errStr := range errStrs {
-				errs = append(errs, fmt.Errorf(errStr))
+				errs = append(errs, errors.New(errStr))
 			}
 		}
 	}
 	key = taint.Key
 	if errStrs := validation.IsQualifiedName(key); len(errStrs) > 0 {
 		for _, errStr := range errStrs {
-			errs = append(errs, fmt.Errorf(errStr))
+			errs = append(errs, errors.New(errStr))
 		}
 	}
 
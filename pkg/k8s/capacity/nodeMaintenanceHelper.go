@@ -0,0 +1,152 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package capacity
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/devtron-labs/devtron/pkg/k8s/capacity/bean"
+	"github.com/devtron-labs/devtron/pkg/k8s/capacity/repository"
+	corev1 "k8s.io/api/core/v1"
+	"sort"
+	"strings"
+)
+
+// getNodeGroup returns the node group of a node, different cloud providers have their own node group label
+func getNodeGroup(node *corev1.Node) string {
+	var nodeGroup = ""
+	for _, label := range bean.NodeGroupLabels {
+		if ng, ok := node.Labels[label]; ok {
+			nodeGroup = ng
+		}
+	}
+	return nodeGroup
+}
+
+// selectNodesForMaintenance filters the nodes matching the label selector of the request by node group and names.
+// The selected node names are returned in the order they are drained: the order of the request if nodes are
+// selected by name, otherwise sorted by name.
+func selectNodesForMaintenance(nodes []corev1.Node, request *bean.NodeMaintenanceRequest) ([]string, error) {
+	nodeExists := make(map[string]bool, len(nodes))
+	selectedNodeNames := make([]string, 0, len(nodes))
+	for i := range nodes {
+		if len(request.NodeGroup) != 0 && getNodeGroup(&nodes[i]) != request.NodeGroup {
+			continue
+		}
+		nodeExists[nodes[i].Name] = true
+		selectedNodeNames = append(selectedNodeNames, nodes[i].Name)
+	}
+	if len(request.NodeNames) == 0 {
+		sort.Strings(selectedNodeNames)
+		return selectedNodeNames, nil
+	}
+	selectedNodeNames = make([]string, 0, len(request.NodeNames))
+	missingNodeNames := make([]string, 0)
+	added := make(map[string]bool, len(request.NodeNames))
+	for _, nodeName := range request.NodeNames {
+		if added[nodeName] {
+			continue
+		}
+		if !nodeExists[nodeName] {
+			missingNodeNames = append(missingNodeNames, nodeName)
+			continue
+		}
+		added[nodeName] = true
+		selectedNodeNames = append(selectedNodeNames, nodeName)
+	}
+	if len(missingNodeNames) != 0 {
+		return nil, fmt.Errorf("nodes %s not found in the cluster or not matching the node group/selector", strings.Join(missingNodeNames, ", "))
+	}
+	return selectedNodeNames, nil
+}
+
+func getNodeMaintenanceProgress(nodes []*repository.NodeMaintenanceNode) *bean.NodeMaintenanceProgress {
+	progress := &bean.NodeMaintenanceProgress{Total: len(nodes)}
+	for _, node := range nodes {
+		switch bean.NodeMaintenanceNodeStatus(node.Status) {
+		case bean.NodeMaintenanceNodePending:
+			progress.Pending++
+		case bean.NodeMaintenanceNodeDraining:
+			progress.Draining++
+		case bean.NodeMaintenanceNodeDrained:
+			progress.Drained++
+		case bean.NodeMaintenanceNodeFailed:
+			progress.Failed++
+		case bean.NodeMaintenanceNodeSkipped:
+			progress.Skipped++
+		case bean.NodeMaintenanceNodeAborted:
+			progress.Aborted++
+		}
+	}
+	return progress
+}
+
+func getBlockingPods(pods []bean.PodDelete) []*bean.NodeMaintenanceBlockingPod {
+	blockingPods := make([]*bean.NodeMaintenanceBlockingPod, 0, len(pods))
+	for _, pod := range pods {
+		blockingPods = append(blockingPods, &bean.NodeMaintenanceBlockingPod{
+			Name:      pod.Pod.Name,
+			Namespace: pod.Pod.Namespace,
+			Reason:    pod.Status.Message,
+		})
+	}
+	return blockingPods
+}
+
+func adaptNodeMaintenanceRun(run *repository.NodeMaintenanceRun, nodes []*repository.NodeMaintenanceNode, withNodes bool) *bean.NodeMaintenanceRunDto {
+	runDto := &bean.NodeMaintenanceRunDto{
+		Id:                  run.Id,
+		ClusterId:           run.ClusterId,
+		NodeGroup:           run.NodeGroup,
+		NodeSelector:        run.NodeSelector,
+		Concurrency:         run.Concurrency,
+		DrainTimeoutSeconds: run.DrainTimeoutSeconds,
+		Status:              bean.NodeMaintenanceRunStatus(run.Status),
+		Message:             run.Message,
+		StartedOn:           run.StartedOn,
+		TriggeredBy:         run.CreatedBy,
+		Progress:            getNodeMaintenanceProgress(nodes),
+	}
+	if runDto.Status != bean.NodeMaintenanceRunRunning {
+		runDto.FinishedOn = run.FinishedOn
+	}
+	if len(run.DrainOptions) != 0 {
+		drainOptions := &bean.NodeDrainHelper{}
+		if err := json.Unmarshal([]byte(run.DrainOptions), drainOptions); err == nil {
+			runDto.NodeDrainHelper = drainOptions
+		}
+	}
+	if !withNodes {
+		return runDto
+	}
+	runDto.Nodes = make([]*bean.NodeMaintenanceNodeDto, 0, len(nodes))
+	for _, node := range nodes {
+		nodeDto := &bean.NodeMaintenanceNodeDto{
+			NodeName:        node.NodeName,
+			Status:          bean.NodeMaintenanceNodeStatus(node.Status),
+			Message:         node.Message,
+			EvictedPodCount: node.EvictedPodCount,
+			StartedOn:       node.StartedOn,
+			FinishedOn:      node.FinishedOn,
+		}
+		if len(node.BlockingPods) != 0 {
+			_ = json.Unmarshal([]byte(node.BlockingPods), &nodeDto.BlockingPods)
+		}
+		runDto.Nodes = append(runDto.Nodes, nodeDto)
+	}
+	return runDto
+}
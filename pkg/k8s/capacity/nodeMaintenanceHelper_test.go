@@ -0,0 +1,70 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package capacity
+
+import (
+	"github.com/devtron-labs/devtron/pkg/k8s/capacity/bean"
+	"github.com/devtron-labs/devtron/pkg/k8s/capacity/repository"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"reflect"
+	"testing"
+)
+
+func TestSelectNodesForMaintenance(t *testing.T) {
+	newNode := func(name, nodeGroup string) corev1.Node {
+		return corev1.Node{ObjectMeta: v1.ObjectMeta{Name: name, Labels: map[string]string{"eks.amazonaws.com/nodegroup": nodeGroup}}}
+	}
+	nodes := []corev1.Node{newNode("node-c", "ng-1"), newNode("node-a", "ng-1"), newNode("node-b", "ng-2")}
+	tests := []struct {
+		name    string
+		request *bean.NodeMaintenanceRequest
+		want    []string
+		wantErr bool
+	}{
+		{name: "all nodes sorted by name", request: &bean.NodeMaintenanceRequest{}, want: []string{"node-a", "node-b", "node-c"}},
+		{name: "nodes of node group", request: &bean.NodeMaintenanceRequest{NodeGroup: "ng-1"}, want: []string{"node-a", "node-c"}},
+		{name: "nodes in request order without duplicates", request: &bean.NodeMaintenanceRequest{NodeNames: []string{"node-c", "node-b", "node-c"}}, want: []string{"node-c", "node-b"}},
+		{name: "node not in node group", request: &bean.NodeMaintenanceRequest{NodeGroup: "ng-2", NodeNames: []string{"node-a"}}, wantErr: true},
+		{name: "node not found", request: &bean.NodeMaintenanceRequest{NodeNames: []string{"node-d"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := selectNodesForMaintenance(nodes, tt.request)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("selectNodesForMaintenance() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("selectNodesForMaintenance() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetNodeMaintenanceProgress(t *testing.T) {
+	nodes := []*repository.NodeMaintenanceNode{
+		{Status: bean.NodeMaintenanceNodeDrained.String()},
+		{Status: bean.NodeMaintenanceNodeDrained.String()},
+		{Status: bean.NodeMaintenanceNodeDraining.String()},
+		{Status: bean.NodeMaintenanceNodeFailed.String()},
+		{Status: bean.NodeMaintenanceNodePending.String()},
+	}
+	want := &bean.NodeMaintenanceProgress{Total: 5, Pending: 1, Draining: 1, Drained: 2, Failed: 1}
+	if got := getNodeMaintenanceProgress(nodes); !reflect.DeepEqual(got, want) {
+		t.Errorf("getNodeMaintenanceProgress() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,216 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+// NodeMaintenanceRun is a rolling drain of a set of nodes of a cluster.
+// The whole state of the run is persisted so that it is progressed by any replica and survives restarts.
+type NodeMaintenanceRun struct {
+	TableName           struct{}   `sql:"node_maintenance_run" pg:",discard_unknown_columns"`
+	Id                  int        `sql:"id,pk"`
+	ClusterId           int        `sql:"cluster_id,notnull"`
+	NodeSelector        string     `sql:"node_selector"`
+	NodeGroup           string     `sql:"node_group"`
+	Concurrency         int        `sql:"concurrency,notnull"`
+	DrainTimeoutSeconds int        `sql:"drain_timeout_seconds,notnull"`
+	DrainOptions        string     `sql:"drain_options"`
+	Status              string     `sql:"status,notnull"`
+	Message             string     `sql:"message"`
+	StartedOn           time.Time  `sql:"started_on,notnull"`
+	FinishedOn          *time.Time `sql:"finished_on"`
+	sql.AuditLog
+}
+
+// NodeMaintenanceNode is the drain of one node of a run
+type NodeMaintenanceNode struct {
+	TableName       struct{}   `sql:"node_maintenance_node" pg:",discard_unknown_columns"`
+	Id              int        `sql:"id,pk"`
+	RunId           int        `sql:"run_id,notnull"`
+	NodeName        string     `sql:"node_name,notnull"`
+	NodeOrder       int        `sql:"node_order,notnull"`
+	Status          string     `sql:"status,notnull"`
+	Message         string     `sql:"message"`
+	BlockingPods    string     `sql:"blocking_pods"`
+	EvictedPodCount int        `sql:"evicted_pod_count,notnull"`
+	StartedOn       *time.Time `sql:"started_on"`
+	FinishedOn      *time.Time `sql:"finished_on"`
+	sql.AuditLog
+}
+
+type NodeMaintenanceRunRepository interface {
+	//transaction util funcs
+	sql.TransactionWrapper
+	SaveRun(run *NodeMaintenanceRun, tx *pg.Tx) error
+	// UpdateRunStatus moves the run to status only if it is in one of fromStatuses, returns false if it was not
+	UpdateRunStatus(id int, fromStatuses []string, status string, message string, finishedOn *time.Time, userId int32) (bool, error)
+	FindRunById(id int) (*NodeMaintenanceRun, error)
+	FindRunsByStatus(status string) ([]*NodeMaintenanceRun, error)
+	FindRunsByClusterId(clusterId int, offset, limit int) ([]*NodeMaintenanceRun, error)
+	// FindRunsByClusterIdAndStatuses returns the runs of the cluster in any of the given statuses
+	FindRunsByClusterIdAndStatuses(clusterId int, statuses []string) ([]*NodeMaintenanceRun, error)
+
+	SaveNodes(nodes []*NodeMaintenanceNode, tx *pg.Tx) error
+	UpdateNode(node *NodeMaintenanceNode) error
+	// UpdateNodeStatus moves the node to status only if it is in fromStatus, returns false if it was not.
+	// Used for claiming the drain of a node so that the same node is not drained twice by different replicas.
+	UpdateNodeStatus(id int, fromStatus string, status string, userId int32) (bool, error)
+	// UpdateNodesStatus moves all the nodes of the run which are in any of fromStatuses to status
+	UpdateNodesStatus(runId int, fromStatuses []string, status string, message string, userId int32) error
+	FindNodesByRunId(runId int) ([]*NodeMaintenanceNode, error)
+	FindNodeById(id int) (*NodeMaintenanceNode, error)
+}
+
+type NodeMaintenanceRunRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+	*sql.TransactionUtilImpl
+}
+
+func NewNodeMaintenanceRunRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger, transactionUtilImpl *sql.TransactionUtilImpl) *NodeMaintenanceRunRepositoryImpl {
+	return &NodeMaintenanceRunRepositoryImpl{
+		dbConnection:        dbConnection,
+		logger:              logger,
+		TransactionUtilImpl: transactionUtilImpl,
+	}
+}
+
+func (repo *NodeMaintenanceRunRepositoryImpl) SaveRun(run *NodeMaintenanceRun, tx *pg.Tx) error {
+	return tx.Insert(run)
+}
+
+func (repo *NodeMaintenanceRunRepositoryImpl) UpdateRunStatus(id int, fromStatuses []string, status string, message string, finishedOn *time.Time, userId int32) (bool, error) {
+	query := repo.dbConnection.Model(&NodeMaintenanceRun{}).
+		Set("status = ?", status).
+		Set("message = ?", message).
+		Set("updated_on = ?", time.Now()).
+		Set("updated_by = ?", userId)
+	if finishedOn != nil {
+		query = query.Set("finished_on = ?", finishedOn)
+	}
+	result, err := query.
+		Where("id = ?", id).
+		Where("status in (?)", pg.In(fromStatuses)).
+		Update()
+	if err != nil {
+		repo.logger.Errorw("error in updating node maintenance run status", "id", id, "status", status, "err", err)
+		return false, err
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+func (repo *NodeMaintenanceRunRepositoryImpl) FindRunById(id int) (*NodeMaintenanceRun, error) {
+	run := &NodeMaintenanceRun{}
+	err := repo.dbConnection.Model(run).
+		Where("id = ?", id).
+		Select()
+	return run, err
+}
+
+func (repo *NodeMaintenanceRunRepositoryImpl) FindRunsByStatus(status string) ([]*NodeMaintenanceRun, error) {
+	var runs []*NodeMaintenanceRun
+	err := repo.dbConnection.Model(&runs).
+		Where("status = ?", status).
+		Order("id ASC").
+		Select()
+	return runs, err
+}
+
+func (repo *NodeMaintenanceRunRepositoryImpl) FindRunsByClusterId(clusterId int, offset, limit int) ([]*NodeMaintenanceRun, error) {
+	var runs []*NodeMaintenanceRun
+	err := repo.dbConnection.Model(&runs).
+		Where("cluster_id = ?", clusterId).
+		Order("id DESC").
+		Offset(offset).
+		Limit(limit).
+		Select()
+	return runs, err
+}
+
+func (repo *NodeMaintenanceRunRepositoryImpl) FindRunsByClusterIdAndStatuses(clusterId int, statuses []string) ([]*NodeMaintenanceRun, error) {
+	var runs []*NodeMaintenanceRun
+	err := repo.dbConnection.Model(&runs).
+		Where("cluster_id = ?", clusterId).
+		Where("status in (?)", pg.In(statuses)).
+		Order("id DESC").
+		Select()
+	return runs, err
+}
+
+func (repo *NodeMaintenanceRunRepositoryImpl) SaveNodes(nodes []*NodeMaintenanceNode, tx *pg.Tx) error {
+	if len(nodes) == 0 {
+		return nil
+	}
+	_, err := tx.Model(&nodes).Insert()
+	return err
+}
+
+func (repo *NodeMaintenanceRunRepositoryImpl) UpdateNode(node *NodeMaintenanceNode) error {
+	return repo.dbConnection.Update(node)
+}
+
+func (repo *NodeMaintenanceRunRepositoryImpl) UpdateNodeStatus(id int, fromStatus string, status string, userId int32) (bool, error) {
+	result, err := repo.dbConnection.Model(&NodeMaintenanceNode{}).
+		Set("status = ?", status).
+		Set("updated_on = ?", time.Now()).
+		Set("updated_by = ?", userId).
+		Where("id = ?", id).
+		Where("status = ?", fromStatus).
+		Update()
+	if err != nil {
+		repo.logger.Errorw("error in updating node maintenance node status", "id", id, "status", status, "err", err)
+		return false, err
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+func (repo *NodeMaintenanceRunRepositoryImpl) UpdateNodesStatus(runId int, fromStatuses []string, status string, message string, userId int32) error {
+	_, err := repo.dbConnection.Model(&NodeMaintenanceNode{}).
+		Set("status = ?", status).
+		Set("message = ?", message).
+		Set("updated_on = ?", time.Now()).
+		Set("updated_by = ?", userId).
+		Where("run_id = ?", runId).
+		Where("status in (?)", pg.In(fromStatuses)).
+		Update()
+	if err != nil {
+		repo.logger.Errorw("error in updating node maintenance nodes status", "runId", runId, "status", status, "err", err)
+	}
+	return err
+}
+
+func (repo *NodeMaintenanceRunRepositoryImpl) FindNodesByRunId(runId int) ([]*NodeMaintenanceNode, error) {
+	var nodes []*NodeMaintenanceNode
+	err := repo.dbConnection.Model(&nodes).
+		Where("run_id = ?", runId).
+		Order("node_order ASC").
+		Select()
+	return nodes, err
+}
+
+func (repo *NodeMaintenanceRunRepositoryImpl) FindNodeById(id int) (*NodeMaintenanceNode, error) {
+	node := &NodeMaintenanceNode{}
+	err := repo.dbConnection.Model(node).
+		Where("id = ?", id).
+		Select()
+	return node, err
+}
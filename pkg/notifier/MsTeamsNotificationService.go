@@ -0,0 +1,145 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package notifier
+
+import (
+	"fmt"
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/notifier/adapter"
+	"github.com/devtron-labs/devtron/pkg/notifier/beans"
+	eventUtil "github.com/devtron-labs/devtron/util/event"
+	"go.uber.org/zap"
+	"time"
+)
+
+type MsTeamsNotificationService interface {
+	SaveOrEditNotificationConfig(channelReq []beans.MsTeamsConfigDto, userId int32) ([]int, error)
+	FetchMsTeamsNotificationConfigById(id int) (*beans.MsTeamsConfigDto, error)
+	FetchAllMsTeamsNotificationConfig() ([]*beans.MsTeamsConfigDto, error)
+	FetchAllMsTeamsNotificationConfigAutocomplete() ([]*beans.NotificationChannelAutoResponse, error)
+	DeleteNotificationConfig(deleteReq *beans.MsTeamsConfigDto, userId int32) error
+}
+
+type MsTeamsNotificationServiceImpl struct {
+	logger                         *zap.SugaredLogger
+	msTeamsRepository              repository.MsTeamsNotificationRepository
+	notificationSettingsRepository repository.NotificationSettingsRepository
+}
+
+func NewMsTeamsNotificationServiceImpl(logger *zap.SugaredLogger, msTeamsRepository repository.MsTeamsNotificationRepository,
+	notificationSettingsRepository repository.NotificationSettingsRepository) *MsTeamsNotificationServiceImpl {
+	return &MsTeamsNotificationServiceImpl{
+		logger:                         logger,
+		msTeamsRepository:              msTeamsRepository,
+		notificationSettingsRepository: notificationSettingsRepository,
+	}
+}
+
+func (impl *MsTeamsNotificationServiceImpl) SaveOrEditNotificationConfig(channelReq []beans.MsTeamsConfigDto, userId int32) ([]int, error) {
+	var responseIds []int
+	msTeamsConfigs := adapter.BuildMsTeamsNewConfigs(channelReq, userId)
+	for _, config := range msTeamsConfigs {
+		if config.Id != 0 {
+			model, err := impl.msTeamsRepository.FindOne(config.Id)
+			if err != nil {
+				impl.logger.Errorw("err while fetching ms teams config", "id", config.Id, "err", err)
+				return []int{}, err
+			}
+			adapter.BuildConfigUpdateModelForMsTeams(config, model, userId)
+			_, err = impl.msTeamsRepository.UpdateMsTeamsConfig(model)
+			if err != nil {
+				impl.logger.Errorw("err while updating ms teams config", "id", config.Id, "err", err)
+				return []int{}, err
+			}
+		} else {
+			_, err := impl.msTeamsRepository.SaveMsTeamsConfig(config)
+			if err != nil {
+				impl.logger.Errorw("err while inserting ms teams config", "err", err)
+				return []int{}, err
+			}
+		}
+		responseIds = append(responseIds, config.Id)
+	}
+	return responseIds, nil
+}
+
+func (impl *MsTeamsNotificationServiceImpl) FetchMsTeamsNotificationConfigById(id int) (*beans.MsTeamsConfigDto, error) {
+	msTeamsConfig, err := impl.msTeamsRepository.FindOne(id)
+	if err != nil {
+		impl.logger.Errorw("cannot find ms teams config", "id", id, "err", err)
+		return nil, err
+	}
+	msTeamsConfigDto := adapter.AdaptMsTeamsConfig(*msTeamsConfig)
+	return &msTeamsConfigDto, nil
+}
+
+func (impl *MsTeamsNotificationServiceImpl) FetchAllMsTeamsNotificationConfig() ([]*beans.MsTeamsConfigDto, error) {
+	msTeamsConfigs, err := impl.msTeamsRepository.FindAll()
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("cannot find all ms teams config", "err", err)
+		return []*beans.MsTeamsConfigDto{}, err
+	}
+	responseDto := make([]*beans.MsTeamsConfigDto, 0, len(msTeamsConfigs))
+	for _, msTeamsConfig := range msTeamsConfigs {
+		msTeamsConfigDto := adapter.AdaptMsTeamsConfig(msTeamsConfig)
+		responseDto = append(responseDto, &msTeamsConfigDto)
+	}
+	return responseDto, nil
+}
+
+func (impl *MsTeamsNotificationServiceImpl) FetchAllMsTeamsNotificationConfigAutocomplete() ([]*beans.NotificationChannelAutoResponse, error) {
+	var responseDto []*beans.NotificationChannelAutoResponse
+	msTeamsConfigs, err := impl.msTeamsRepository.FindAll()
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("cannot find all ms teams config", "err", err)
+		return []*beans.NotificationChannelAutoResponse{}, err
+	}
+	for _, msTeamsConfig := range msTeamsConfigs {
+		responseDto = append(responseDto, &beans.NotificationChannelAutoResponse{
+			Id:         msTeamsConfig.Id,
+			ConfigName: msTeamsConfig.ConfigName,
+			TeamId:     msTeamsConfig.TeamId,
+		})
+	}
+	return responseDto, nil
+}
+
+func (impl *MsTeamsNotificationServiceImpl) DeleteNotificationConfig(deleteReq *beans.MsTeamsConfigDto, userId int32) error {
+	existingConfig, err := impl.msTeamsRepository.FindOne(deleteReq.Id)
+	if err != nil {
+		impl.logger.Errorw("No matching entry found for delete", "err", err, "id", deleteReq.Id)
+		return err
+	}
+	notifications, err := impl.notificationSettingsRepository.FindNotificationSettingsByConfigIdAndConfigType(deleteReq.Id, eventUtil.MsTeams.String())
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching notifications using ms teams config", "id", deleteReq.Id, "err", err)
+		return err
+	}
+	if len(notifications) > 0 {
+		impl.logger.Errorw("found notifications using this config, cannot delete", "config", deleteReq)
+		return fmt.Errorf(" Please delete all notifications using this config before deleting")
+	}
+	existingConfig.UpdatedOn = time.Now()
+	existingConfig.UpdatedBy = userId
+	err = impl.msTeamsRepository.MarkMsTeamsConfigDeleted(existingConfig)
+	if err != nil {
+		impl.logger.Errorw("error in deleting ms teams config", "err", err, "id", existingConfig.Id)
+		return err
+	}
+	return nil
+}
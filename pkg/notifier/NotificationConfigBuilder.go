@@ -30,7 +30,8 @@ import (
 type NotificationConfigBuilder interface {
 	BuildNotificationSettingsConfig(notificationSettingsRequest *beans.NotificationConfigRequest, existingNotificationSettingsConfig *repository.NotificationSettingsView, userId int32) (*repository.NotificationSettingsView, error)
 	BuildNewNotificationSettings(notificationSettingsRequest *beans.NotificationConfigRequest, notificationSettingsView *repository.NotificationSettingsView) ([]repository.NotificationSettings, error)
-	BuildNotificationSettingWithPipeline(teamId *int, envId *int, appId *int, pipelineId *int, clusterId *int, pipelineType util.PipelineType, eventTypeId int, viewId int, providers []*bean.Provider) (repository.NotificationSettings, error)
+	BuildNotificationSettingWithPipeline(teamId *int, envId *int, appId *int, pipelineId *int, clusterId *int, pipelineType util.PipelineType, eventTypeId int, viewId int, providers []*bean.Provider, digestConfig *bean.NotificationDigestConfig) (repository.NotificationSettings, error)
+	BuildDigestConfigJson(digestConfig *bean.NotificationDigestConfig) (string, error)
 }
 
 type NotificationConfigBuilderImpl struct {
@@ -53,6 +54,7 @@ func (impl NotificationConfigBuilderImpl) BuildNotificationSettingsConfig(notifi
 	nsConfig.PipelineType = notificationSettingsRequest.PipelineType
 	nsConfig.EventTypeIds = notificationSettingsRequest.EventTypeIds
 	nsConfig.Providers = notificationSettingsRequest.Providers
+	nsConfig.DigestConfig = notificationSettingsRequest.DigestConfig
 
 	config, err := json.Marshal(nsConfig)
 	if err != nil {
@@ -85,7 +87,7 @@ func (impl NotificationConfigBuilderImpl) BuildNewNotificationSettings(notificat
 	var notificationSettings []repository.NotificationSettings
 	for _, item := range tempRequest {
 		for _, e := range notificationSettingsRequest.EventTypeIds {
-			notificationSetting, err := impl.BuildNotificationSettingWithPipeline(item.TeamId, item.EnvId, item.AppId, item.PipelineId, item.ClusterId, notificationSettingsRequest.PipelineType, e, notificationSettingsView.Id, notificationSettingsRequest.Providers)
+			notificationSetting, err := impl.BuildNotificationSettingWithPipeline(item.TeamId, item.EnvId, item.AppId, item.PipelineId, item.ClusterId, notificationSettingsRequest.PipelineType, e, notificationSettingsView.Id, notificationSettingsRequest.Providers, notificationSettingsRequest.DigestConfig)
 			if err != nil {
 				impl.logger.Error(err)
 				return nil, err
@@ -113,7 +115,7 @@ func (impl NotificationConfigBuilderImpl) buildNotificationSetting(notificationS
 	return notificationSetting, nil
 }
 
-func (impl NotificationConfigBuilderImpl) BuildNotificationSettingWithPipeline(teamId *int, envId *int, appId *int, pipelineId *int, clusterId *int, pipelineType util.PipelineType, eventTypeId int, viewId int, providers []*bean.Provider) (repository.NotificationSettings, error) {
+func (impl NotificationConfigBuilderImpl) BuildNotificationSettingWithPipeline(teamId *int, envId *int, appId *int, pipelineId *int, clusterId *int, pipelineType util.PipelineType, eventTypeId int, viewId int, providers []*bean.Provider, digestConfig *bean.NotificationDigestConfig) (repository.NotificationSettings, error) {
 
 	if teamId == nil && appId == nil && envId == nil && pipelineId == nil && clusterId == nil {
 		return repository.NotificationSettings{}, errors.New("no filter criteria is selected")
@@ -123,6 +125,11 @@ func (impl NotificationConfigBuilderImpl) BuildNotificationSettingWithPipeline(t
 		impl.logger.Error(err)
 		return repository.NotificationSettings{}, err
 	}
+	digestConfigJson, err := impl.BuildDigestConfigJson(digestConfig)
+	if err != nil {
+		impl.logger.Error(err)
+		return repository.NotificationSettings{}, err
+	}
 	notificationSetting := repository.NotificationSettings{
 		TeamId:       teamId,
 		AppId:        appId,
@@ -133,6 +140,20 @@ func (impl NotificationConfigBuilderImpl) BuildNotificationSettingWithPipeline(t
 		Config:       string(providersJson),
 		ViewId:       viewId,
 		ClusterId:    clusterId,
+		// digest config is kept on each setting, so that it is available while the events are notified
+		DigestConfig: digestConfigJson,
 	}
 	return notificationSetting, nil
 }
+
+// BuildDigestConfigJson returns empty json for nil digest config, i.e. the setting is not in the digest mode
+func (impl NotificationConfigBuilderImpl) BuildDigestConfigJson(digestConfig *bean.NotificationDigestConfig) (string, error) {
+	if digestConfig == nil {
+		return "", nil
+	}
+	digestConfigJson, err := json.Marshal(digestConfig)
+	if err != nil {
+		return "", err
+	}
+	return string(digestConfigJson), nil
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/devtron-labs/devtron/pkg/team/read"
 	repository2 "github.com/devtron-labs/devtron/pkg/team/repository"
 	"github.com/devtron-labs/devtron/util/sliceUtil"
+	"net/http"
 	"time"
 
 	"github.com/devtron-labs/devtron/internal/sql/repository"
@@ -57,6 +58,7 @@ type NotificationConfigServiceImpl struct {
 	pipelineRepository             pipelineConfig.PipelineRepository
 	slackRepository                repository.SlackNotificationRepository
 	webhookRepository              repository.WebhookNotificationRepository
+	msTeamsRepository              repository.MsTeamsNotificationRepository
 	sesRepository                  repository.SESNotificationRepository
 	smtpRepository                 repository.SMTPNotificationRepository
 	environmentRepository          repository3.EnvironmentRepository
@@ -73,7 +75,7 @@ func NewNotificationConfigServiceImpl(logger *zap.SugaredLogger, notificationSet
 	teamRepository repository2.TeamRepository,
 	environmentRepository repository3.EnvironmentRepository, appRepository app.AppRepository, clusterService clusterService.ClusterService,
 	userRepository repository4.UserRepository, ciPipelineMaterialRepository pipelineConfig.CiPipelineMaterialRepository,
-	teamReadService read.TeamReadService) *NotificationConfigServiceImpl {
+	teamReadService read.TeamReadService, msTeamsRepository repository.MsTeamsNotificationRepository) *NotificationConfigServiceImpl {
 	return &NotificationConfigServiceImpl{
 		logger:                         logger,
 		notificationSettingsRepository: notificationSettingsRepository,
@@ -83,6 +85,7 @@ func NewNotificationConfigServiceImpl(logger *zap.SugaredLogger, notificationSet
 		sesRepository:                  sesRepository,
 		slackRepository:                slackRepository,
 		webhookRepository:              webhookRepository,
+		msTeamsRepository:              msTeamsRepository,
 		smtpRepository:                 smtpRepository,
 		environmentRepository:          environmentRepository,
 		appRepository:                  appRepository,
@@ -159,6 +162,10 @@ func (impl *NotificationConfigServiceImpl) CreateOrUpdateNotificationSettings(no
 	defer tx.Rollback()
 
 	for _, request := range notificationSettingsRequest.NotificationConfigRequest {
+		if err = request.DigestConfig.Validate(); err != nil {
+			impl.logger.Errorw("invalid digest config", "digestConfig", request.DigestConfig, "err", err)
+			return 0, util2.NewApiError(http.StatusBadRequest, err.Error(), err.Error())
+		}
 		if request.Id != 0 {
 			_, err := impl.notificationSettingsRepository.DeleteNotificationSettingsByConfigId(request.Id, tx)
 			if err != nil {
@@ -193,6 +200,10 @@ func (impl *NotificationConfigServiceImpl) UpdateNotificationSettings(notificati
 	defer tx.Rollback()
 
 	for _, item := range notificationSettingsRequest.NotificationConfigRequest {
+		if err = item.DigestConfig.Validate(); err != nil {
+			impl.logger.Errorw("invalid digest config", "digestConfig", item.DigestConfig, "err", err)
+			return 0, util2.NewApiError(http.StatusBadRequest, err.Error(), err.Error())
+		}
 		configId, err = impl.updateNotificationSetting(item, notificationSettingsRequest.UpdateType, userId, tx)
 		if err != nil {
 			impl.logger.Errorw("failed to save notification settings", "err", err)
@@ -294,12 +305,15 @@ func (impl *NotificationConfigServiceImpl) BuildNotificationSettingsResponse(not
 		if config.Providers != nil && len(config.Providers) > 0 {
 			var slackIds []*int
 			var webhookIds []*int
+			var msTeamsIds []*int
 			var providerConfigs []*beans.ProvidersConfig
 			for _, item := range config.Providers {
 				if item.Destination == util.Slack {
 					slackIds = append(slackIds, &item.ConfigId)
 				} else if item.Destination == util.Webhook {
 					webhookIds = append(webhookIds, &item.ConfigId)
+				} else if item.Destination == util.MsTeams {
+					msTeamsIds = append(msTeamsIds, &item.ConfigId)
 				} else {
 					providerConfigs = append(providerConfigs, &beans.ProvidersConfig{Dest: string(item.Destination), Recipient: item.Recipient, Id: item.ConfigId})
 				}
@@ -324,6 +338,16 @@ func (impl *NotificationConfigServiceImpl) BuildNotificationSettingsResponse(not
 					providerConfigs = append(providerConfigs, &beans.ProvidersConfig{Id: item.Id, ConfigName: item.ConfigName, Dest: string(util.Webhook)})
 				}
 			}
+			if len(msTeamsIds) > 0 {
+				msTeamsConfigs, err := impl.msTeamsRepository.FindByIds(msTeamsIds)
+				if err != nil && err != pg.ErrNoRows {
+					impl.logger.Errorw("error in fetching ms teams config", "msTeamsIds", msTeamsIds, "err", err)
+					return notificationSettingsResponses, deletedItemCount, err
+				}
+				for _, item := range msTeamsConfigs {
+					providerConfigs = append(providerConfigs, &beans.ProvidersConfig{Id: item.Id, ConfigName: item.ConfigName, Dest: string(util.MsTeams)})
+				}
+			}
 			notificationSettingsResponse.ProvidersConfig = providerConfigs
 		}
 
@@ -370,6 +394,7 @@ func (impl *NotificationConfigServiceImpl) BuildNotificationSettingsResponse(not
 
 		notificationSettingsResponse.PipelineType = string(config.PipelineType)
 		notificationSettingsResponse.EventTypes = config.EventTypeIds
+		notificationSettingsResponse.DigestConfig = config.DigestConfig
 
 		notificationSettingsResponses = append(notificationSettingsResponses, notificationSettingsResponse)
 	}
@@ -382,12 +407,18 @@ func (impl *NotificationConfigServiceImpl) buildProvidersConfig(config beans.Con
 		sesConfigNamesMap := map[int]string{}
 		slackConfigNameMap := map[int]string{}
 		smtpConfigNamesMap := map[int]string{}
+		msTeamsConfigNamesMap := map[int]string{}
 		for _, c := range config.Providers {
 			if util.Slack == c.Destination {
 				if _, ok := slackConfigNameMap[c.ConfigId]; ok {
 					continue
 				}
 				slackConfigNameMap[c.ConfigId] = ""
+			} else if util.MsTeams == c.Destination {
+				if _, ok := msTeamsConfigNamesMap[c.ConfigId]; ok {
+					continue
+				}
+				msTeamsConfigNamesMap[c.ConfigId] = ""
 			} else if util.SES == c.Destination {
 				if _, ok := sesConfigNamesMap[c.ConfigId]; ok {
 					continue
@@ -404,6 +435,7 @@ func (impl *NotificationConfigServiceImpl) buildProvidersConfig(config beans.Con
 		slackIds := make([]int, 0, len(slackConfigNameMap))
 		sesIds := make([]int, 0, len(sesConfigNamesMap))
 		smtpIds := make([]int, 0, len(smtpConfigNamesMap))
+		msTeamsIds := make([]int, 0, len(msTeamsConfigNamesMap))
 
 		for k := range slackConfigNameMap {
 			slackIds = append(slackIds, k)
@@ -414,6 +446,9 @@ func (impl *NotificationConfigServiceImpl) buildProvidersConfig(config beans.Con
 		for k := range smtpConfigNamesMap {
 			smtpIds = append(smtpIds, k)
 		}
+		for k := range msTeamsConfigNamesMap {
+			msTeamsIds = append(msTeamsIds, k)
+		}
 
 		if len(slackIds) > 0 {
 			slackConfigs, err := impl.slackRepository.FindByIdsIn(slackIds)
@@ -425,6 +460,16 @@ func (impl *NotificationConfigServiceImpl) buildProvidersConfig(config beans.Con
 				slackConfigNameMap[s.Id] = s.ConfigName
 			}
 		}
+		if len(msTeamsIds) > 0 {
+			msTeamsConfigs, err := impl.msTeamsRepository.FindByIdsIn(msTeamsIds)
+			if err != nil {
+				impl.logger.Errorw("error in fetch ms teams configs", "err", err)
+				return []beans.ProvidersConfig{}, err
+			}
+			for _, s := range msTeamsConfigs {
+				msTeamsConfigNamesMap[s.Id] = s.ConfigName
+			}
+		}
 		if len(sesIds) > 0 {
 			sesConfigs, err := impl.sesRepository.FindByIdsIn(sesIds)
 			if err != nil {
@@ -453,6 +498,8 @@ func (impl *NotificationConfigServiceImpl) buildProvidersConfig(config beans.Con
 				configName = sesConfigNamesMap[c.ConfigId]
 			} else if c.Destination == util.SMTP {
 				configName = smtpConfigNamesMap[c.ConfigId]
+			} else if c.Destination == util.MsTeams {
+				configName = msTeamsConfigNamesMap[c.ConfigId]
 			}
 			providerConfig := beans.ProvidersConfig{
 				Id:         c.ConfigId,
@@ -566,6 +613,8 @@ func (impl *NotificationConfigServiceImpl) updateNotificationSetting(notificatio
 		nsConfig.EventTypeIds = notificationSettingsRequest.EventTypeIds
 	} else if updateType == util.UpdateRecipients {
 		nsConfig.Providers = notificationSettingsRequest.Providers
+	} else if updateType == util.UpdateDigest {
+		nsConfig.DigestConfig = notificationSettingsRequest.DigestConfig
 	}
 	config, err := json.Marshal(nsConfig)
 	if err != nil {
@@ -590,6 +639,7 @@ func (impl *NotificationConfigServiceImpl) updateNotificationSetting(notificatio
 		notificationSettingsRequest.PipelineType = nsConfig.PipelineType
 		notificationSettingsRequest.Providers = nsConfig.Providers
 		notificationSettingsRequest.ClusterId = nsConfig.ClusterId
+		notificationSettingsRequest.DigestConfig = nsConfig.DigestConfig
 		var notificationSettings []repository.NotificationSettings
 		nsOptions, err := impl.notificationSettingsRepository.FetchNotificationSettingGroupBy(notificationSettingsRequest.Id)
 		if err != nil {
@@ -605,7 +655,7 @@ func (impl *NotificationConfigServiceImpl) updateNotificationSetting(notificatio
 		} else {
 			for _, item := range nsOptions {
 				for _, e := range notificationSettingsRequest.EventTypeIds {
-					notificationSetting, err := impl.notificationConfigBuilder.BuildNotificationSettingWithPipeline(item.TeamId, item.EnvId, item.AppId, item.PipelineId, item.ClusterId, util.PipelineType(item.PipelineType), e, notificationSettingsRequest.Id, nsConfig.Providers)
+					notificationSetting, err := impl.notificationConfigBuilder.BuildNotificationSettingWithPipeline(item.TeamId, item.EnvId, item.AppId, item.PipelineId, item.ClusterId, util.PipelineType(item.PipelineType), e, notificationSettingsRequest.Id, nsConfig.Providers, nsConfig.DigestConfig)
 					if err != nil {
 						impl.logger.Error(err)
 						return 0, err
@@ -655,6 +705,25 @@ func (impl *NotificationConfigServiceImpl) updateNotificationSetting(notificatio
 				return 0, err
 			}
 		}
+	} else if updateType == util.UpdateDigest {
+		nsOptions, err := impl.notificationSettingsRepository.FindNotificationSettingsByViewId(notificationSettingsRequest.Id)
+		if err != nil {
+			impl.logger.Errorw("failed to fetch existing notification settings view", "err", err)
+			return 0, err
+		}
+		digestConfigJson, err := impl.notificationConfigBuilder.BuildDigestConfigJson(nsConfig.DigestConfig)
+		if err != nil {
+			impl.logger.Errorw("error in building digest config json", "digestConfig", nsConfig.DigestConfig, "err", err)
+			return 0, err
+		}
+		for _, ns := range nsOptions {
+			ns.DigestConfig = digestConfigJson
+			_, err = impl.notificationSettingsRepository.UpdateNotificationSettings(&ns, tx)
+			if err != nil {
+				impl.logger.Errorw("failed to update digest config of notification setting", "id", ns.Id, "err", err)
+				return 0, err
+			}
+		}
 	}
 	return existingNotificationSettingsConfig.Id, nil
 }
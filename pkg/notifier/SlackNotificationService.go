@@ -47,17 +47,20 @@ type SlackNotificationServiceImpl struct {
 	teamService                    team.TeamService
 	slackRepository                repository.SlackNotificationRepository
 	webhookRepository              repository.WebhookNotificationRepository
+	msTeamsRepository              repository.MsTeamsNotificationRepository
 	userRepository                 repository2.UserRepository
 	notificationSettingsRepository repository.NotificationSettingsRepository
 }
 
 func NewSlackNotificationServiceImpl(logger *zap.SugaredLogger, slackRepository repository.SlackNotificationRepository, webhookRepository repository.WebhookNotificationRepository, teamService team.TeamService,
-	userRepository repository2.UserRepository, notificationSettingsRepository repository.NotificationSettingsRepository) *SlackNotificationServiceImpl {
+	userRepository repository2.UserRepository, notificationSettingsRepository repository.NotificationSettingsRepository,
+	msTeamsRepository repository.MsTeamsNotificationRepository) *SlackNotificationServiceImpl {
 	return &SlackNotificationServiceImpl{
 		logger:                         logger,
 		teamService:                    teamService,
 		slackRepository:                slackRepository,
 		webhookRepository:              webhookRepository,
+		msTeamsRepository:              msTeamsRepository,
 		userRepository:                 userRepository,
 		notificationSettingsRepository: notificationSettingsRepository,
 	}
@@ -164,6 +167,18 @@ func (impl *SlackNotificationServiceImpl) RecipientListingSuggestion(value strin
 			Dest:      eventUtil.Webhook}
 		results = append(results, result)
 	}
+	msTeamsConfigs, err := impl.msTeamsRepository.FindByName(value)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("cannot find all ms teams config", "err", err)
+		return []*beans.NotificationRecipientListingResponse{}, err
+	}
+	for _, msTeamsConfig := range msTeamsConfigs {
+		result := &beans.NotificationRecipientListingResponse{
+			ConfigId:  msTeamsConfig.Id,
+			Recipient: msTeamsConfig.ConfigName,
+			Dest:      eventUtil.MsTeams}
+		results = append(results, result)
+	}
 	userList, err := impl.userRepository.FetchUserMatchesByEmailIdExcludingApiTokenUser(value)
 	if err != nil && !util.IsErrNoRows(err) {
 		impl.logger.Errorw("cannot find all slack config", "err", err)
@@ -201,6 +216,8 @@ func (impl *SlackNotificationServiceImpl) RecipientListingSuggestion(value strin
 								}
 								if strings.Contains(v.(string), beans.SLACK_URL) {
 									result.Dest = eventUtil.Slack
+								} else if strings.Contains(v.(string), beans.MS_TEAMS_URL) || strings.Contains(v.(string), beans.MS_TEAMS_WORKFLOW_URL) {
+									result.Dest = eventUtil.MsTeams
 								} else if strings.Contains(v.(string), beans.WEBHOOK_URL) {
 									result.Dest = eventUtil.Webhook
 								} else {
@@ -115,6 +115,56 @@ func BuildConfigUpdateModelForSlack(slackConfig *repository.SlackConfig, model *
 	model.UpdatedBy = userId
 }
 
+func AdaptMsTeamsConfig(msTeamsConfig repository.MsTeamsConfig) beans.MsTeamsConfigDto {
+	msTeamsConfigDto := beans.MsTeamsConfigDto{
+		OwnerId:     msTeamsConfig.OwnerId,
+		TeamId:      msTeamsConfig.TeamId,
+		WebhookUrl:  msTeamsConfig.WebHookUrl,
+		ConfigName:  msTeamsConfig.ConfigName,
+		Description: msTeamsConfig.Description,
+		Id:          msTeamsConfig.Id,
+	}
+	return msTeamsConfigDto
+}
+
+func BuildMsTeamsNewConfigs(msTeamsReq []beans.MsTeamsConfigDto, userId int32) []*repository.MsTeamsConfig {
+	var msTeamsConfigs []*repository.MsTeamsConfig
+	for _, c := range msTeamsReq {
+		msTeamsConfig := &repository.MsTeamsConfig{
+			Id:          c.Id,
+			ConfigName:  c.ConfigName,
+			WebHookUrl:  c.WebhookUrl,
+			Description: c.Description,
+			AuditLog: sql.AuditLog{
+				CreatedBy: userId,
+				CreatedOn: time.Now(),
+				UpdatedOn: time.Now(),
+				UpdatedBy: userId,
+			},
+		}
+		if c.TeamId != 0 {
+			msTeamsConfig.TeamId = c.TeamId
+		} else {
+			msTeamsConfig.OwnerId = userId
+		}
+		msTeamsConfigs = append(msTeamsConfigs, msTeamsConfig)
+	}
+	return msTeamsConfigs
+}
+
+func BuildConfigUpdateModelForMsTeams(msTeamsConfig *repository.MsTeamsConfig, model *repository.MsTeamsConfig, userId int32) {
+	model.WebHookUrl = msTeamsConfig.WebHookUrl
+	model.ConfigName = msTeamsConfig.ConfigName
+	model.Description = msTeamsConfig.Description
+	if msTeamsConfig.TeamId != 0 {
+		model.TeamId = msTeamsConfig.TeamId
+	} else {
+		model.OwnerId = msTeamsConfig.OwnerId
+	}
+	model.UpdatedOn = time.Now()
+	model.UpdatedBy = userId
+}
+
 func AdaptSMTPConfig(smtpConfig *repository.SMTPConfig) *beans.SMTPConfigDto {
 	smtpConfigDto := &beans.SMTPConfigDto{
 		OwnerId:      smtpConfig.OwnerId,
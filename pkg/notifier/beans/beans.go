@@ -8,6 +8,9 @@ import (
 const (
 	SLACK_URL   = "https://hooks.slack.com/"
 	WEBHOOK_URL = "https://"
+	// MS_TEAMS_URL is the domain of teams incoming webhooks, workflow webhooks are hosted on logic.azure.com
+	MS_TEAMS_URL          = ".webhook.office.com/"
+	MS_TEAMS_WORKFLOW_URL = ".logic.azure.com"
 )
 
 type WebhookVariable string
@@ -45,6 +48,8 @@ type NotificationConfigRequest struct {
 	PipelineType util.PipelineType `json:"pipelineType" validate:"required"`
 	EventTypeIds []int             `json:"eventTypeIds" validate:"required"`
 	Providers    []*bean.Provider  `json:"providers"`
+	// DigestConfig opts the setting in the digest mode and quiet hours, nil keeps sending every event right away
+	DigestConfig *bean.NotificationDigestConfig `json:"digestConfig,omitempty"`
 }
 
 func (notificationSettingsRequest *NotificationConfigRequest) GenerateSettingCombinationsV1() []*LocalRequest {
@@ -195,14 +200,15 @@ func generateCombinationSettings(arrays [][]*int, current LocalRequest, index in
 }
 
 type NSConfig struct {
-	TeamId       []*int            `json:"teamId"`
-	AppId        []*int            `json:"appId"`
-	EnvId        []*int            `json:"envId"`
-	PipelineId   *int              `json:"pipelineId"`
-	ClusterId    []*int            `json:"clusterId"`
-	PipelineType util.PipelineType `json:"pipelineType" validate:"required"`
-	EventTypeIds []int             `json:"eventTypeIds" validate:"required"`
-	Providers    []*bean.Provider  `json:"providers" validate:"required"`
+	TeamId       []*int                         `json:"teamId"`
+	AppId        []*int                         `json:"appId"`
+	EnvId        []*int                         `json:"envId"`
+	PipelineId   *int                           `json:"pipelineId"`
+	ClusterId    []*int                         `json:"clusterId"`
+	PipelineType util.PipelineType              `json:"pipelineType" validate:"required"`
+	EventTypeIds []int                          `json:"eventTypeIds" validate:"required"`
+	Providers    []*bean.Provider               `json:"providers" validate:"required"`
+	DigestConfig *bean.NotificationDigestConfig `json:"digestConfig,omitempty"`
 }
 
 type NotificationSettingRequest struct {
@@ -243,16 +249,17 @@ type NSViewResponse struct {
 }
 
 type NotificationSettingsResponse struct {
-	Id               int                `json:"id"`
-	ConfigName       string             `json:"configName"`
-	TeamResponse     []*TeamResponse    `json:"team"`
-	AppResponse      []*AppResponse     `json:"app"`
-	EnvResponse      []*EnvResponse     `json:"environment"`
-	ClusterResponse  []*ClusterResponse `json:"cluster"`
-	PipelineResponse *PipelineResponse  `json:"pipeline"`
-	PipelineType     string             `json:"pipelineType"`
-	ProvidersConfig  []*ProvidersConfig `json:"providerConfigs"`
-	EventTypes       []int              `json:"eventTypes"`
+	Id               int                            `json:"id"`
+	ConfigName       string                         `json:"configName"`
+	TeamResponse     []*TeamResponse                `json:"team"`
+	AppResponse      []*AppResponse                 `json:"app"`
+	EnvResponse      []*EnvResponse                 `json:"environment"`
+	ClusterResponse  []*ClusterResponse             `json:"cluster"`
+	PipelineResponse *PipelineResponse              `json:"pipeline"`
+	PipelineType     string                         `json:"pipelineType"`
+	ProvidersConfig  []*ProvidersConfig             `json:"providerConfigs"`
+	EventTypes       []int                          `json:"eventTypes"`
+	DigestConfig     *bean.NotificationDigestConfig `json:"digestConfig,omitempty"`
 }
 
 type SearchFilterResponse struct {
@@ -359,6 +366,22 @@ type SlackConfigDto struct {
 	Id          int    `json:"id" validate:"number"`
 }
 
+//MS Teams
+
+type MsTeamsChannelConfig struct {
+	Channel           util.Channel       `json:"channel" validate:"required"`
+	MsTeamsConfigDtos []MsTeamsConfigDto `json:"configs"`
+}
+
+type MsTeamsConfigDto struct {
+	OwnerId     int32  `json:"userId" validate:"number"`
+	TeamId      int    `json:"teamId" validate:"required"`
+	WebhookUrl  string `json:"webhookUrl" validate:"required,url"`
+	ConfigName  string `json:"configName" validate:"required"`
+	Description string `json:"description"`
+	Id          int    `json:"id" validate:"number"`
+}
+
 //SMTP
 
 type SMTPChannelConfig struct {
@@ -12,10 +12,12 @@ import (
 
 	"github.com/devtron-labs/devtron/client/lens"
 	//"github.com/devtron-labs/devtron/client/lens"
+	repository2 "github.com/devtron-labs/devtron/internal/sql/repository"
 	"github.com/devtron-labs/devtron/internal/sql/repository/app"
 	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
 	"github.com/devtron-labs/devtron/pkg/cluster/environment/repository"
 	"github.com/devtron-labs/devtron/pkg/overview/bean"
+	"github.com/devtron-labs/devtron/pkg/overview/config"
 	"github.com/devtron-labs/devtron/pkg/overview/util"
 	"go.uber.org/zap"
 )
@@ -30,6 +32,8 @@ type DoraMetricsServiceImpl struct {
 	pipelineRepository    pipelineConfig.PipelineRepository
 	environmentRepository repository.EnvironmentRepository
 	cdWorkflowRepository  pipelineConfig.CdWorkflowRepository
+	ciArtifactRepository  repository2.CiArtifactRepository
+	doraMetricsConfig     *config.DoraMetricsConfig
 }
 
 func NewDoraMetricsServiceImpl(
@@ -39,6 +43,8 @@ func NewDoraMetricsServiceImpl(
 	pipelineRepository pipelineConfig.PipelineRepository,
 	environmentRepository repository.EnvironmentRepository,
 	cdWorkflowRepository pipelineConfig.CdWorkflowRepository,
+	ciArtifactRepository repository2.CiArtifactRepository,
+	doraMetricsConfig *config.DoraMetricsConfig,
 ) *DoraMetricsServiceImpl {
 	return &DoraMetricsServiceImpl{
 		logger:                logger,
@@ -47,6 +53,8 @@ func NewDoraMetricsServiceImpl(
 		pipelineRepository:    pipelineRepository,
 		environmentRepository: environmentRepository,
 		cdWorkflowRepository:  cdWorkflowRepository,
+		ciArtifactRepository:  ciArtifactRepository,
+		doraMetricsConfig:     doraMetricsConfig,
 	}
 }
 
@@ -65,13 +73,14 @@ func (impl *DoraMetricsServiceImpl) GetDoraMetrics(ctx context.Context, request
 		return bean.NewDoraMetricsResponse(), nil
 	}
 
-	// Calculate all DORA metrics using single Lens API call per app-env pair
-	allMetrics, err := impl.calculateAllDoraMetricsFromLens(ctx, request, appEnvPairs)
+	currentMetricsData, err := impl.fetchAllMetrics(ctx, appEnvPairs, request.TimeRangeRequest.From, request.TimeRangeRequest.To)
 	if err != nil {
-		impl.logger.Errorw("error calculating DORA metrics from lens", "err", err)
+		impl.logger.Errorw("error fetching current period DORA metrics", "source", impl.doraMetricsConfig.Source, "err", err)
 		return nil, err
 	}
 
+	allMetrics := impl.calculateAllDoraMetrics(ctx, request, currentMetricsData)
+
 	response := &bean.DoraMetricsResponse{
 		ProdDeploymentPipelineCount: len(appEnvPairs),
 		DeploymentFrequency:         allMetrics.DeploymentFrequency,
@@ -80,6 +89,15 @@ func (impl *DoraMetricsServiceImpl) GetDoraMetrics(ctx context.Context, request
 		MeanTimeToRecovery:          allMetrics.MeanTimeToRecovery,
 	}
 
+	teamGroups, envGroups, err := impl.getTeamAndEnvironmentGroups(appEnvPairs)
+	if err != nil {
+		// breakdown is supplementary, continue with the overall metrics
+		impl.logger.Errorw("error getting teams and environments for DORA metrics breakdown", "err", err)
+		return response, nil
+	}
+	response.TeamBreakdown = util.CalculateDoraMetricsBreakdown(currentMetricsData, teamGroups)
+	response.EnvironmentBreakdown = util.CalculateDoraMetricsBreakdown(currentMetricsData, envGroups)
+
 	return response, nil
 }
 
@@ -110,34 +128,175 @@ func (impl *DoraMetricsServiceImpl) getAppEnvironmentPairsOptimized(ctx context.
 	return appEnvPairs, nil
 }
 
-// calculateAllDoraMetricsFromLens calculates all DORA metrics using single Lens API call per app-env pair
-func (impl *DoraMetricsServiceImpl) calculateAllDoraMetricsFromLens(ctx context.Context, request *bean.DoraMetricsRequest, appEnvPairs []lens.AppEnvPair) (*bean.AllDoraMetrics, error) {
-	currentMetricsData, err := impl.fetchAllMetricsFromLens(ctx, appEnvPairs, request.TimeRangeRequest.From, request.TimeRangeRequest.To)
-	if err != nil {
-		impl.logger.Errorw("error fetching current period metrics from lens", "err", err)
-		return nil, err
-	}
-
+// calculateAllDoraMetrics calculates all DORA metrics of the current period along with the comparison to the previous period
+func (impl *DoraMetricsServiceImpl) calculateAllDoraMetrics(ctx context.Context, request *bean.DoraMetricsRequest, currentMetricsData map[string]*bean.LensMetrics) *bean.AllDoraMetrics {
 	// Get app-env pairs for previous period
 	previousAppEnvPairs, err := impl.getAppEnvironmentPairsOptimized(ctx, request.PrevFrom, request.PrevTo)
 	if err != nil {
 		impl.logger.Errorw("error getting app-environment pairs for previous period", "err", err)
 		// Continue without comparison if we can't get previous period data
-		return impl.createAllDoraMetricsWithoutComparison(currentMetricsData), nil
+		return impl.createAllDoraMetricsWithoutComparison(currentMetricsData)
 	}
 
 	var previousMetricsData map[string]*bean.LensMetrics
 	if len(previousAppEnvPairs) > 0 {
-		previousMetricsData, err = impl.fetchAllMetricsFromLens(ctx, previousAppEnvPairs, request.PrevFrom, request.PrevTo)
+		previousMetricsData, err = impl.fetchAllMetrics(ctx, previousAppEnvPairs, request.PrevFrom, request.PrevTo)
 		if err != nil {
-			impl.logger.Errorw("error fetching previous period metrics from lens", "err", err)
+			impl.logger.Errorw("error fetching previous period DORA metrics", "source", impl.doraMetricsConfig.Source, "err", err)
 			// Continue without comparison if we can't get previous period data
-			return impl.createAllDoraMetricsWithoutComparison(currentMetricsData), nil
+			return impl.createAllDoraMetricsWithoutComparison(currentMetricsData)
 		}
 	}
 
 	// Calculate all metrics with comparison
-	return impl.createAllDoraMetricsWithComparison(currentMetricsData, previousMetricsData), nil
+	return impl.createAllDoraMetricsWithComparison(currentMetricsData, previousMetricsData)
+}
+
+// fetchAllMetrics fetches the DORA metrics of the app-env pairs from the configured source
+func (impl *DoraMetricsServiceImpl) fetchAllMetrics(ctx context.Context, appEnvPairs []lens.AppEnvPair, from, to *time.Time) (map[string]*bean.LensMetrics, error) {
+	if impl.doraMetricsConfig.IsNativeSource() {
+		return impl.calculateAllMetricsNatively(appEnvPairs, from, to)
+	}
+	return impl.fetchAllMetricsFromLens(ctx, appEnvPairs, from, to)
+}
+
+// calculateAllMetricsNatively calculates the DORA metrics from the deployment history of the production pipelines,
+// the commit time used for the lead time is taken from the material info of the deployed artifacts
+func (impl *DoraMetricsServiceImpl) calculateAllMetricsNatively(appEnvPairs []lens.AppEnvPair, from, to *time.Time) (map[string]*bean.LensMetrics, error) {
+	metricsData := make(map[string]*bean.LensMetrics)
+	if len(appEnvPairs) == 0 || from == nil || to == nil {
+		return metricsData, nil
+	}
+
+	deploymentsData, err := impl.cdWorkflowRepository.GetProdDeploymentsForDoraMetrics(from, to)
+	if err != nil {
+		impl.logger.Errorw("error getting prod deployments for dora metrics", "from", from, "to", to, "err", err)
+		return nil, err
+	}
+
+	requestedPairs := make(map[string]bool, len(appEnvPairs))
+	for _, pair := range appEnvPairs {
+		requestedPairs[util.GetAppEnvKey(pair.AppId, pair.EnvId)] = true
+	}
+	var artifactIds []int
+	seenArtifactIds := make(map[int]bool)
+	for _, deploymentData := range deploymentsData {
+		if deploymentData.CiArtifactId > 0 && !seenArtifactIds[deploymentData.CiArtifactId] {
+			seenArtifactIds[deploymentData.CiArtifactId] = true
+			artifactIds = append(artifactIds, deploymentData.CiArtifactId)
+		}
+	}
+	commitTimes, err := getCommitTimesByArtifactId(impl.logger, impl.ciArtifactRepository, artifactIds)
+	if err != nil {
+		impl.logger.Errorw("error getting commit times of deployed artifacts", "err", err)
+		return nil, err
+	}
+
+	deployments := make([]*bean.DoraDeployment, 0, len(deploymentsData))
+	for _, deploymentData := range deploymentsData {
+		if !requestedPairs[util.GetAppEnvKey(deploymentData.AppId, deploymentData.EnvId)] {
+			continue
+		}
+		deployment := &bean.DoraDeployment{
+			AppId:      deploymentData.AppId,
+			EnvId:      deploymentData.EnvId,
+			Status:     deploymentData.Status,
+			StartedOn:  deploymentData.StartedOn,
+			FinishedOn: deploymentData.FinishedOn,
+		}
+		if commitTime, ok := commitTimes[deploymentData.CiArtifactId]; ok {
+			deployment.CommitTime = &commitTime
+		}
+		deployments = append(deployments, deployment)
+	}
+
+	return util.CalculateNativeDoraMetrics(deployments, *from, *to), nil
+}
+
+// getCommitTimesByArtifactId returns the time of the latest commit of each artifact, artifacts without a parsable commit time are skipped
+func getCommitTimesByArtifactId(logger *zap.SugaredLogger, ciArtifactRepository repository2.CiArtifactRepository, artifactIds []int) (map[int]time.Time, error) {
+	commitTimes := make(map[int]time.Time)
+	if len(artifactIds) == 0 {
+		return commitTimes, nil
+	}
+	artifacts, err := ciArtifactRepository.GetByIds(artifactIds)
+	if err != nil {
+		logger.Errorw("error getting artifacts by ids", "artifactIds", artifactIds, "err", err)
+		return nil, err
+	}
+	for _, artifact := range artifacts {
+		if len(artifact.MaterialInfo) == 0 {
+			continue
+		}
+		ciMaterials, err := repository2.GetCiMaterialInfo(artifact.MaterialInfo, artifact.DataSource)
+		if err != nil {
+			logger.Debugw("skipping artifact with unsupported material info for lead time", "artifactId", artifact.Id, "err", err)
+			continue
+		}
+		for _, ciMaterial := range ciMaterials {
+			for _, modification := range ciMaterial.Modifications {
+				commitTime, ok := util.ParseCommitTime(modification.ModifiedTime)
+				if !ok {
+					continue
+				}
+				if latest, exists := commitTimes[artifact.Id]; !exists || commitTime.After(latest) {
+					commitTimes[artifact.Id] = commitTime
+				}
+			}
+		}
+	}
+	return commitTimes, nil
+}
+
+// getTeamAndEnvironmentGroups returns the team and the environment of each app-env pair, keyed by app-env
+func (impl *DoraMetricsServiceImpl) getTeamAndEnvironmentGroups(appEnvPairs []lens.AppEnvPair) (map[string]*bean.DoraMetricsGroup, map[string]*bean.DoraMetricsGroup, error) {
+	var appIds []int
+	var envIds []*int
+	seenAppIds, seenEnvIds := make(map[int]bool), make(map[int]bool)
+	for _, pair := range appEnvPairs {
+		if !seenAppIds[pair.AppId] {
+			seenAppIds[pair.AppId] = true
+			appIds = append(appIds, pair.AppId)
+		}
+		if !seenEnvIds[pair.EnvId] {
+			seenEnvIds[pair.EnvId] = true
+			envId := pair.EnvId
+			envIds = append(envIds, &envId)
+		}
+	}
+
+	apps, err := impl.appRepository.FindAppAndProjectByIdsIn(appIds)
+	if err != nil {
+		impl.logger.Errorw("error getting apps with projects", "appIds", appIds, "err", err)
+		return nil, nil, err
+	}
+	teamByAppId := make(map[int]*bean.DoraMetricsGroup, len(apps))
+	for _, app := range apps {
+		teamByAppId[app.Id] = &bean.DoraMetricsGroup{Id: app.TeamId, Name: app.Team.Name}
+	}
+
+	envs, err := impl.environmentRepository.FindByIds(envIds)
+	if err != nil {
+		impl.logger.Errorw("error getting environments", "envIds", envIds, "err", err)
+		return nil, nil, err
+	}
+	envById := make(map[int]*bean.DoraMetricsGroup, len(envs))
+	for _, env := range envs {
+		envById[env.Id] = &bean.DoraMetricsGroup{Id: env.Id, Name: env.Name}
+	}
+
+	teamGroups := make(map[string]*bean.DoraMetricsGroup, len(appEnvPairs))
+	envGroups := make(map[string]*bean.DoraMetricsGroup, len(appEnvPairs))
+	for _, pair := range appEnvPairs {
+		key := util.GetAppEnvKey(pair.AppId, pair.EnvId)
+		if team, ok := teamByAppId[pair.AppId]; ok {
+			teamGroups[key] = team
+		}
+		if env, ok := envById[pair.EnvId]; ok {
+			envGroups[key] = env
+		}
+	}
+	return teamGroups, envGroups, nil
 }
 
 // fetchAllMetricsFromLens fetches all DORA metrics from Lens using single bulk API call
@@ -190,7 +349,7 @@ func (impl *DoraMetricsServiceImpl) fetchAllMetricsFromLens(ctx context.Context,
 		}
 
 		// Store metrics with app-env ID key
-		key := fmt.Sprintf("%d-%d", doraMetric.AppId, doraMetric.EnvId)
+		key := util.GetAppEnvKey(doraMetric.AppId, doraMetric.EnvId)
 		metricsData[key] = lensMetrics
 	}
 
@@ -0,0 +1,285 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ */
+
+package overview
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	repository2 "github.com/devtron-labs/devtron/internal/sql/repository"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/pkg/overview/config"
+	"github.com/devtron-labs/devtron/pkg/overview/util"
+	workflowStageRepository "github.com/devtron-labs/devtron/pkg/pipeline/workflowStatus/repository"
+	imageScanRepo "github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageScanning/repository"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+)
+
+// The exported metrics are served on the /metrics endpoint of the orchestrator. Every replica computes them from the
+// database, so dashboards should aggregate them with max over the instances instead of sum.
+var (
+	deploymentsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "devtron_deployments_total",
+		Help: "Finished deployments by app, environment and status",
+	}, []string{"app_name", "env_name", "status"})
+
+	deploymentDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "devtron_deployment_duration_seconds",
+		Help:    "Duration of the finished deployments from trigger to completion",
+		Buckets: prometheus.ExponentialBuckets(10, 2, 10),
+	}, []string{"app_name", "env_name", "status"})
+
+	ciBuildDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "devtron_ci_build_duration_seconds",
+		Help:    "Duration of the finished CI builds",
+		Buckets: prometheus.ExponentialBuckets(10, 2, 10),
+	}, []string{"app_name", "pipeline_name", "status"})
+
+	leadTime = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "devtron_lead_time_seconds",
+		Help:    "Time from the latest commit of the deployed artifact to the end of its successful deployment",
+		Buckets: prometheus.ExponentialBuckets(60, 2, 15),
+	}, []string{"app_name", "env_name"})
+
+	pipelineQueueTime = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "devtron_pipeline_queue_time_seconds",
+		Help:    "Time a CI or pre/post deployment workflow spent in preparation before its pod started executing",
+		Buckets: prometheus.ExponentialBuckets(1, 2, 12),
+	}, []string{"app_name", "pipeline_type"})
+
+	vulnerabilities = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "devtron_vulnerabilities",
+		Help: "Vulnerabilities found in the images deployed on each app and environment by severity",
+	}, []string{"app_name", "env_name", "severity"})
+)
+
+// MetricsExporterService records the builds, deployments and vulnerabilities in the prometheus metrics
+type MetricsExporterService interface {
+	// RefreshMetrics records the builds and deployments finished since the previous refresh
+	RefreshMetrics() error
+	// RefreshVulnerabilityMetrics replaces the vulnerability counts with the latest scan results
+	RefreshVulnerabilityMetrics() error
+}
+
+type MetricsExporterServiceImpl struct {
+	logger                    *zap.SugaredLogger
+	cdWorkflowRepository      pipelineConfig.CdWorkflowRepository
+	ciWorkflowRepository      pipelineConfig.CiWorkflowRepository
+	workflowStageRepository   workflowStageRepository.WorkflowStageRepository
+	ciArtifactRepository      repository2.CiArtifactRepository
+	imageScanResultRepository imageScanRepo.ImageScanResultRepository
+	config                    *config.MetricsExporterConfig
+
+	// exporterStartedOn bounds the first lookback window, workflows finished before the exporter started are not recorded
+	exporterStartedOn     time.Time
+	refreshLock           sync.Mutex
+	recordedDeploymentIds util.RecordedIds
+	recordedBuildIds      util.RecordedIds
+	recordedStageIds      util.RecordedIds
+}
+
+func NewMetricsExporterServiceImpl(
+	logger *zap.SugaredLogger,
+	cdWorkflowRepository pipelineConfig.CdWorkflowRepository,
+	ciWorkflowRepository pipelineConfig.CiWorkflowRepository,
+	workflowStageRepository workflowStageRepository.WorkflowStageRepository,
+	ciArtifactRepository repository2.CiArtifactRepository,
+	imageScanResultRepository imageScanRepo.ImageScanResultRepository,
+	cfg *config.MetricsExporterConfig,
+) *MetricsExporterServiceImpl {
+	service := &MetricsExporterServiceImpl{
+		logger:                    logger,
+		cdWorkflowRepository:      cdWorkflowRepository,
+		ciWorkflowRepository:      ciWorkflowRepository,
+		workflowStageRepository:   workflowStageRepository,
+		ciArtifactRepository:      ciArtifactRepository,
+		imageScanResultRepository: imageScanResultRepository,
+		config:                    cfg,
+		exporterStartedOn:         time.Now(),
+		recordedDeploymentIds:     make(util.RecordedIds),
+		recordedBuildIds:          make(util.RecordedIds),
+		recordedStageIds:          make(util.RecordedIds),
+	}
+
+	if cfg.Enabled {
+		service.startRefreshWorkers(context.Background())
+		logger.Infow("metrics exporter started", "refreshInterval", cfg.GetRefreshInterval(), "vulnerabilityRefreshInterval", cfg.GetVulnerabilityRefreshInterval())
+	} else {
+		logger.Info("metrics exporter disabled")
+	}
+
+	return service
+}
+
+// startRefreshWorkers periodically refreshes the build and deployment metrics and, on a longer interval, the vulnerability metrics
+func (impl *MetricsExporterServiceImpl) startRefreshWorkers(ctx context.Context) {
+	go impl.runPeriodically(ctx, impl.config.GetRefreshInterval(), "metrics", impl.RefreshMetrics)
+	go impl.runPeriodically(ctx, impl.config.GetVulnerabilityRefreshInterval(), "vulnerability metrics", impl.RefreshVulnerabilityMetrics)
+}
+
+func (impl *MetricsExporterServiceImpl) runPeriodically(ctx context.Context, interval time.Duration, name string, refresh func() error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	if err := refresh(); err != nil {
+		impl.logger.Errorw("error in refreshing exported metrics", "metrics", name, "err", err)
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			impl.logger.Infow("metrics exporter worker stopped", "metrics", name)
+			return
+		case <-ticker.C:
+			if err := refresh(); err != nil {
+				impl.logger.Errorw("error in refreshing exported metrics", "metrics", name, "err", err)
+			}
+		}
+	}
+}
+
+func (impl *MetricsExporterServiceImpl) RefreshMetrics() error {
+	impl.refreshLock.Lock()
+	defer impl.refreshLock.Unlock()
+
+	to := time.Now()
+	from := to.Add(-impl.config.GetLookback())
+	if from.Before(impl.exporterStartedOn) {
+		from = impl.exporterStartedOn
+	}
+	statuses := util.GetTerminalWorkflowStatuses()
+
+	err := impl.recordDeployments(&from, &to, statuses)
+	if err != nil {
+		return err
+	}
+	err = impl.recordCiBuilds(&from, &to, statuses)
+	if err != nil {
+		return err
+	}
+	err = impl.recordQueueTimes(&from, &to)
+	if err != nil {
+		return err
+	}
+
+	impl.recordedDeploymentIds.Prune(from)
+	impl.recordedBuildIds.Prune(from)
+	impl.recordedStageIds.Prune(from)
+	return nil
+}
+
+func (impl *MetricsExporterServiceImpl) recordDeployments(from, to *time.Time, statuses []string) error {
+	deployments, err := impl.cdWorkflowRepository.GetDeploymentsFinishedInTimeRange(from, to, statuses)
+	if err != nil {
+		impl.logger.Errorw("error in getting finished deployments for metrics", "from", from, "to", to, "err", err)
+		return err
+	}
+	var succeededArtifactIds []int
+	seenArtifactIds := make(map[int]bool)
+	for _, deployment := range deployments {
+		status, _ := util.GetMetricStatus(deployment.Status)
+		if status == util.MetricStatusSucceeded && deployment.CiArtifactId > 0 && !seenArtifactIds[deployment.CiArtifactId] {
+			seenArtifactIds[deployment.CiArtifactId] = true
+			succeededArtifactIds = append(succeededArtifactIds, deployment.CiArtifactId)
+		}
+	}
+	commitTimes, err := getCommitTimesByArtifactId(impl.logger, impl.ciArtifactRepository, succeededArtifactIds)
+	if err != nil {
+		impl.logger.Errorw("error in getting commit times of deployed artifacts for metrics", "err", err)
+		return err
+	}
+
+	for _, deployment := range deployments {
+		if !impl.recordedDeploymentIds.Add(deployment.Id, *to) {
+			continue
+		}
+		status, ok := util.GetMetricStatus(deployment.Status)
+		if !ok {
+			continue
+		}
+		deploymentsTotal.WithLabelValues(deployment.AppName, deployment.EnvName, status).Inc()
+		if duration, ok := util.GetDurationSeconds(deployment.StartedOn, deployment.FinishedOn); ok {
+			deploymentDuration.WithLabelValues(deployment.AppName, deployment.EnvName, status).Observe(duration)
+		}
+		if status != util.MetricStatusSucceeded {
+			continue
+		}
+		if commitTime, ok := commitTimes[deployment.CiArtifactId]; ok {
+			if duration, ok := util.GetDurationSeconds(commitTime, deployment.FinishedOn); ok {
+				leadTime.WithLabelValues(deployment.AppName, deployment.EnvName).Observe(duration)
+			}
+		}
+	}
+	return nil
+}
+
+func (impl *MetricsExporterServiceImpl) recordCiBuilds(from, to *time.Time, statuses []string) error {
+	builds, err := impl.ciWorkflowRepository.GetCIBuildsFinishedInTimeRange(from, to, statuses)
+	if err != nil {
+		impl.logger.Errorw("error in getting finished CI builds for metrics", "from", from, "to", to, "err", err)
+		return err
+	}
+	for _, build := range builds {
+		if !impl.recordedBuildIds.Add(build.Id, *to) {
+			continue
+		}
+		status, ok := util.GetMetricStatus(build.Status)
+		if !ok {
+			continue
+		}
+		if duration, ok := util.GetDurationSeconds(build.StartedOn, build.FinishedOn); ok {
+			ciBuildDuration.WithLabelValues(build.AppName, build.PipelineName, status).Observe(duration)
+		}
+	}
+	return nil
+}
+
+func (impl *MetricsExporterServiceImpl) recordQueueTimes(from, to *time.Time) error {
+	stages, err := impl.workflowStageRepository.GetPreparationStagesUpdatedInTimeRange(from, to)
+	if err != nil {
+		impl.logger.Errorw("error in getting preparation stages for metrics", "from", from, "to", to, "err", err)
+		return err
+	}
+	for _, stage := range stages {
+		if !impl.recordedStageIds.Add(stage.Id, *to) {
+			continue
+		}
+		startTime, err := util.ParseTimeString(stage.StartTime)
+		if err != nil {
+			continue
+		}
+		endTime, err := util.ParseTimeString(stage.EndTime)
+		if err != nil {
+			continue
+		}
+		if duration, ok := util.GetDurationSeconds(startTime, endTime); ok {
+			pipelineQueueTime.WithLabelValues(stage.AppName, stage.WorkflowType).Observe(duration)
+		}
+	}
+	return nil
+}
+
+func (impl *MetricsExporterServiceImpl) RefreshVulnerabilityMetrics() error {
+	vulnerabilityCounts, err := impl.imageScanResultRepository.GetVulnerabilityCountByAppEnvAndSeverity()
+	if err != nil {
+		impl.logger.Errorw("error in getting vulnerability counts for metrics", "err", err)
+		return err
+	}
+	type vulnerabilityKey struct {
+		appName, envName, severity string
+	}
+	// severities outside the known range are counted together as unknown
+	counts := make(map[vulnerabilityKey]int)
+	for _, data := range vulnerabilityCounts {
+		counts[vulnerabilityKey{appName: data.AppName, envName: data.EnvName, severity: util.GetSeverityLabel(data.Severity)}] += data.Count
+	}
+	// the apps and environments without vulnerabilities anymore must not be exported with their stale counts
+	vulnerabilities.Reset()
+	for key, count := range counts {
+		vulnerabilities.WithLabelValues(key.appName, key.envName, key.severity).Set(float64(count))
+	}
+	return nil
+}
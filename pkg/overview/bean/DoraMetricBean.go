@@ -103,11 +103,41 @@ type DoraMetricsRequest struct {
 }
 
 type DoraMetricsResponse struct {
-	ProdDeploymentPipelineCount int         `json:"prodDeploymentPipelineCount"`
-	DeploymentFrequency         *DoraMetric `json:"deploymentFrequency"`
-	MeanLeadTime                *DoraMetric `json:"meanLeadTime"`
-	ChangeFailureRate           *DoraMetric `json:"changeFailureRate"`
-	MeanTimeToRecovery          *DoraMetric `json:"meanTimeToRecovery"`
+	ProdDeploymentPipelineCount int                     `json:"prodDeploymentPipelineCount"`
+	DeploymentFrequency         *DoraMetric             `json:"deploymentFrequency"`
+	MeanLeadTime                *DoraMetric             `json:"meanLeadTime"`
+	ChangeFailureRate           *DoraMetric             `json:"changeFailureRate"`
+	MeanTimeToRecovery          *DoraMetric             `json:"meanTimeToRecovery"`
+	TeamBreakdown               []*DoraMetricsBreakdown `json:"teamBreakdown,omitempty"`
+	EnvironmentBreakdown        []*DoraMetricsBreakdown `json:"environmentBreakdown,omitempty"`
+}
+
+// DoraMetricsBreakdown holds the average DORA metrics of the production pipelines of a team or an environment
+type DoraMetricsBreakdown struct {
+	Id                  int     `json:"id"`
+	Name                string  `json:"name"`
+	PipelineCount       int     `json:"pipelineCount"`
+	DeploymentFrequency float64 `json:"deploymentFrequency"`
+	MeanLeadTime        float64 `json:"meanLeadTime"`
+	ChangeFailureRate   float64 `json:"changeFailureRate"`
+	MeanTimeToRecovery  float64 `json:"meanTimeToRecovery"`
+}
+
+// DoraMetricsGroup identifies the team or the environment an app-env pair belongs to in the breakdown
+type DoraMetricsGroup struct {
+	Id   int
+	Name string
+}
+
+// DoraDeployment is a production deployment used for calculating the DORA metrics natively,
+// CommitTime is the time of the latest commit in the deployed artifact, nil if unknown
+type DoraDeployment struct {
+	AppId      int
+	EnvId      int
+	Status     string
+	StartedOn  time.Time
+	FinishedOn time.Time
+	CommitTime *time.Time
 }
 
 func NewDoraMetricsResponse() *DoraMetricsResponse {
@@ -0,0 +1,44 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ */
+
+package config
+
+import (
+	"fmt"
+
+	"github.com/caarlos0/env"
+)
+
+type DoraMetricsSource string
+
+const (
+	// DoraMetricsSourceLens fetches the DORA metrics from the lens service
+	DoraMetricsSourceLens DoraMetricsSource = "LENS"
+	// DoraMetricsSourceNative calculates the DORA metrics from the deployment history stored in the orchestrator
+	DoraMetricsSourceNative DoraMetricsSource = "NATIVE"
+)
+
+// DoraMetricsConfig represents configuration for the DORA metrics of the overview
+type DoraMetricsConfig struct {
+	// Source selects where the DORA metrics are computed
+	Source DoraMetricsSource `env:"DORA_METRICS_SOURCE" envDefault:"LENS" description:"Source of the DORA metrics, LENS to fetch them from the lens service or NATIVE to calculate them from the deployment history without lens"`
+}
+
+// IsNativeSource returns true if the DORA metrics are to be calculated without the lens service
+func (c *DoraMetricsConfig) IsNativeSource() bool {
+	return c.Source == DoraMetricsSourceNative
+}
+
+func GetDoraMetricsConfig() (*DoraMetricsConfig, error) {
+	cfg := &DoraMetricsConfig{}
+	err := env.Parse(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse dora metrics config: %w", err)
+	}
+	if cfg.Source != DoraMetricsSourceLens && cfg.Source != DoraMetricsSourceNative {
+		return nil, fmt.Errorf("invalid dora metrics source %q, supported values are %s and %s", cfg.Source, DoraMetricsSourceLens, DoraMetricsSourceNative)
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,59 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ */
+
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/caarlos0/env"
+)
+
+// MetricsExporterConfig represents configuration for exporting the deployment and DORA metrics to prometheus
+type MetricsExporterConfig struct {
+	// Enabled enables publishing the metrics on the /metrics endpoint
+	Enabled bool `env:"OVERVIEW_METRICS_EXPORTER_ENABLED" envDefault:"false" description:"Export per app and environment deployment, build, lead time, queue time and vulnerability metrics on the /metrics endpoint"`
+
+	// RefreshIntervalSeconds defines how often the finished builds and deployments are recorded
+	RefreshIntervalSeconds int `env:"OVERVIEW_METRICS_EXPORTER_REFRESH_INTERVAL_SECONDS" envDefault:"60" description:"Interval in seconds at which finished builds and deployments are recorded in the exported metrics"`
+
+	// LookbackSeconds defines how far back the finished builds and deployments are looked up on every refresh,
+	// this covers the workflows whose finish time is updated late
+	LookbackSeconds int `env:"OVERVIEW_METRICS_EXPORTER_LOOKBACK_SECONDS" envDefault:"900" description:"Time window in seconds looked back on every refresh, builds and deployments whose finish time is saved later than this are not recorded"`
+
+	// VulnerabilityRefreshIntervalSeconds defines how often the vulnerability counts are refreshed
+	VulnerabilityRefreshIntervalSeconds int `env:"OVERVIEW_METRICS_EXPORTER_VULNERABILITY_REFRESH_INTERVAL_SECONDS" envDefault:"600" description:"Interval in seconds at which the exported vulnerability counts are refreshed"`
+}
+
+// GetRefreshInterval returns the refresh interval as a time.Duration
+func (c *MetricsExporterConfig) GetRefreshInterval() time.Duration {
+	return time.Duration(c.RefreshIntervalSeconds) * time.Second
+}
+
+// GetLookback returns the lookback window as a time.Duration
+func (c *MetricsExporterConfig) GetLookback() time.Duration {
+	return time.Duration(c.LookbackSeconds) * time.Second
+}
+
+// GetVulnerabilityRefreshInterval returns the vulnerability refresh interval as a time.Duration
+func (c *MetricsExporterConfig) GetVulnerabilityRefreshInterval() time.Duration {
+	return time.Duration(c.VulnerabilityRefreshIntervalSeconds) * time.Second
+}
+
+func GetMetricsExporterConfig() (*MetricsExporterConfig, error) {
+	cfg := &MetricsExporterConfig{}
+	err := env.Parse(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse metrics exporter config: %w", err)
+	}
+	if cfg.RefreshIntervalSeconds <= 0 || cfg.VulnerabilityRefreshIntervalSeconds <= 0 {
+		return nil, fmt.Errorf("metrics exporter refresh intervals must be positive")
+	}
+	if cfg.LookbackSeconds < cfg.RefreshIntervalSeconds {
+		return nil, fmt.Errorf("metrics exporter lookback %ds must not be shorter than the refresh interval %ds", cfg.LookbackSeconds, cfg.RefreshIntervalSeconds)
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,165 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ */
+
+package util
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig/bean/workflow/cdWorkflow"
+	"github.com/devtron-labs/devtron/pkg/overview/bean"
+)
+
+// commitTimeLayouts are the formats in which the commit time is stored in the material info of the artifacts
+var commitTimeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05 -0700 MST",
+	"2006-01-02 15:04:05 -0700",
+	time.RFC1123Z,
+	"Mon Jan 2 15:04:05 2006 -0700",
+}
+
+// ParseCommitTime parses the modified time of a git material modification, returns false if the format is unknown
+func ParseCommitTime(commitTime string) (time.Time, bool) {
+	commitTime = strings.TrimSpace(commitTime)
+	if len(commitTime) == 0 {
+		return time.Time{}, false
+	}
+	for _, layout := range commitTimeLayouts {
+		if parsed, err := time.Parse(layout, commitTime); err == nil {
+			return parsed, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// GetAppEnvKey returns the key of the app-env pair used in the metrics maps
+func GetAppEnvKey(appId, envId int) string {
+	return fmt.Sprintf("%d-%d", appId, envId)
+}
+
+func isSucceededDeployment(status string) bool {
+	return status == cdWorkflow.WorkflowSucceeded
+}
+
+func isFailedDeployment(status string) bool {
+	return status == cdWorkflow.WorkflowFailed || status == cdWorkflow.WorkflowTimedOut
+}
+
+func getDeploymentEndTime(deployment *bean.DoraDeployment) time.Time {
+	if deployment.FinishedOn.IsZero() {
+		return deployment.StartedOn
+	}
+	return deployment.FinishedOn
+}
+
+// CalculateNativeDoraMetrics calculates the DORA metrics of each app-env pair from its deployments in the time range.
+// The metrics are returned in the same units as lens, keyed by app-env:
+//   - deployment frequency: successful deployments per day
+//   - lead time for changes: average minutes from the commit to the end of its successful deployment
+//   - change failure rate: percentage of failed deployments among the finished ones
+//   - time to recovery: average minutes from a failed deployment to the next successful one
+func CalculateNativeDoraMetrics(deployments []*bean.DoraDeployment, from, to time.Time) map[string]*bean.LensMetrics {
+	metricsData := make(map[string]*bean.LensMetrics)
+
+	days := to.Sub(from).Hours() / 24
+	if days < 1 {
+		days = 1
+	}
+
+	deploymentsByAppEnv := make(map[string][]*bean.DoraDeployment)
+	for _, deployment := range deployments {
+		key := GetAppEnvKey(deployment.AppId, deployment.EnvId)
+		deploymentsByAppEnv[key] = append(deploymentsByAppEnv[key], deployment)
+	}
+
+	for key, appEnvDeployments := range deploymentsByAppEnv {
+		sort.SliceStable(appEnvDeployments, func(i, j int) bool {
+			return appEnvDeployments[i].StartedOn.Before(appEnvDeployments[j].StartedOn)
+		})
+
+		var succeeded, failed int
+		var leadTimes, recoveryTimes []float64
+		var failedSince *time.Time
+		for _, deployment := range appEnvDeployments {
+			endTime := getDeploymentEndTime(deployment)
+			switch {
+			case isSucceededDeployment(deployment.Status):
+				succeeded++
+				if deployment.CommitTime != nil && !endTime.Before(*deployment.CommitTime) {
+					leadTimes = append(leadTimes, endTime.Sub(*deployment.CommitTime).Minutes())
+				}
+				if failedSince != nil {
+					recoveryTimes = append(recoveryTimes, endTime.Sub(*failedSince).Minutes())
+					failedSince = nil
+				}
+			case isFailedDeployment(deployment.Status):
+				failed++
+				if failedSince == nil {
+					failedSince = &endTime
+				}
+			}
+		}
+
+		metrics := &bean.LensMetrics{
+			AverageCycleTime:    float64(succeeded) / days,
+			AverageLeadTime:     CalculateAverageFromValues(leadTimes),
+			AverageRecoveryTime: CalculateAverageFromValues(recoveryTimes),
+		}
+		if succeeded+failed > 0 {
+			metrics.ChangeFailureRate = float64(failed) * 100 / float64(succeeded+failed)
+		}
+		metricsData[key] = metrics
+	}
+
+	return metricsData
+}
+
+// CalculateDoraMetricsBreakdown averages the metrics of the app-env pairs belonging to the same group (team or environment),
+// app-env pairs without a group are skipped. The breakdown is sorted by the group name.
+func CalculateDoraMetricsBreakdown(metricsData map[string]*bean.LensMetrics, groups map[string]*bean.DoraMetricsGroup) []*bean.DoraMetricsBreakdown {
+	type groupValues struct {
+		group                                                      *bean.DoraMetricsGroup
+		deploymentFrequency, leadTime, changeFailure, recoveryTime []float64
+	}
+	valuesByGroupId := make(map[int]*groupValues)
+	for key, metrics := range metricsData {
+		group, ok := groups[key]
+		if !ok || group == nil {
+			continue
+		}
+		values, ok := valuesByGroupId[group.Id]
+		if !ok {
+			values = &groupValues{group: group}
+			valuesByGroupId[group.Id] = values
+		}
+		values.deploymentFrequency = append(values.deploymentFrequency, metrics.AverageCycleTime)
+		values.leadTime = append(values.leadTime, metrics.AverageLeadTime)
+		values.changeFailure = append(values.changeFailure, metrics.ChangeFailureRate)
+		values.recoveryTime = append(values.recoveryTime, metrics.AverageRecoveryTime)
+	}
+
+	breakdown := make([]*bean.DoraMetricsBreakdown, 0, len(valuesByGroupId))
+	for _, values := range valuesByGroupId {
+		breakdown = append(breakdown, &bean.DoraMetricsBreakdown{
+			Id:                  values.group.Id,
+			Name:                values.group.Name,
+			PipelineCount:       len(values.deploymentFrequency),
+			DeploymentFrequency: CalculateAverageFromValues(values.deploymentFrequency),
+			MeanLeadTime:        CalculateAverageFromValues(values.leadTime),
+			ChangeFailureRate:   CalculateAverageFromValues(values.changeFailure),
+			MeanTimeToRecovery:  CalculateAverageFromValues(values.recoveryTime),
+		})
+	}
+	sort.Slice(breakdown, func(i, j int) bool {
+		if breakdown[i].Name == breakdown[j].Name {
+			return breakdown[i].Id < breakdown[j].Id
+		}
+		return breakdown[i].Name < breakdown[j].Name
+	})
+	return breakdown
+}
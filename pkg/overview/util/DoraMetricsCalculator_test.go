@@ -0,0 +1,95 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ */
+
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/devtron-labs/devtron/pkg/overview/bean"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCommitTime(t *testing.T) {
+	expected := time.Date(2024, 5, 10, 8, 30, 0, 0, time.UTC)
+	for _, commitTime := range []string{
+		"2024-05-10T08:30:00Z",
+		"2024-05-10 08:30:00 +0000 UTC",
+		"Fri, 10 May 2024 08:30:00 +0000",
+	} {
+		parsed, ok := ParseCommitTime(commitTime)
+		assert.True(t, ok, commitTime)
+		assert.True(t, expected.Equal(parsed), commitTime)
+	}
+	_, ok := ParseCommitTime("")
+	assert.False(t, ok)
+	_, ok = ParseCommitTime("yesterday")
+	assert.False(t, ok)
+}
+
+func TestCalculateNativeDoraMetrics(t *testing.T) {
+	from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(10 * 24 * time.Hour)
+	commitTime := from.Add(time.Hour)
+	deployment := func(envId int, status string, startedAfter, duration time.Duration, commitTime *time.Time) *bean.DoraDeployment {
+		return &bean.DoraDeployment{
+			AppId:      1,
+			EnvId:      envId,
+			Status:     status,
+			StartedOn:  from.Add(startedAfter),
+			FinishedOn: from.Add(startedAfter + duration),
+			CommitTime: commitTime,
+		}
+	}
+	deployments := []*bean.DoraDeployment{
+		// out of order on purpose, the calculator sorts by start time
+		deployment(2, "Succeeded", 5*time.Hour, 10*time.Minute, &commitTime),
+		deployment(2, "Succeeded", 2*time.Hour, 10*time.Minute, &commitTime),
+		deployment(2, "Failed", 3*time.Hour, 10*time.Minute, nil),
+		deployment(2, "TimedOut", 4*time.Hour, 10*time.Minute, nil),
+		deployment(2, "Progressing", 6*time.Hour, 0, nil),
+		deployment(3, "Failed", time.Hour, 0, nil),
+	}
+
+	metrics := CalculateNativeDoraMetrics(deployments, from, to)
+	assert.Len(t, metrics, 2)
+
+	prodMetrics := metrics[GetAppEnvKey(1, 2)]
+	assert.InDelta(t, 0.2, prodMetrics.AverageCycleTime, 0.0001)
+	// lead times are 70 and 250 minutes from the commit
+	assert.InDelta(t, 160, prodMetrics.AverageLeadTime, 0.0001)
+	assert.InDelta(t, 50, prodMetrics.ChangeFailureRate, 0.0001)
+	// first failure ends at 3h10m and the next success at 5h10m
+	assert.InDelta(t, 120, prodMetrics.AverageRecoveryTime, 0.0001)
+
+	unrecoveredMetrics := metrics[GetAppEnvKey(1, 3)]
+	assert.Zero(t, unrecoveredMetrics.AverageCycleTime)
+	assert.InDelta(t, 100, unrecoveredMetrics.ChangeFailureRate, 0.0001)
+	assert.Zero(t, unrecoveredMetrics.AverageRecoveryTime)
+}
+
+func TestCalculateDoraMetricsBreakdown(t *testing.T) {
+	metricsData := map[string]*bean.LensMetrics{
+		"1-1": {AverageCycleTime: 1, AverageLeadTime: 10, ChangeFailureRate: 20, AverageRecoveryTime: 30},
+		"2-1": {AverageCycleTime: 3, AverageLeadTime: 30, ChangeFailureRate: 0, AverageRecoveryTime: 10},
+		"3-1": {AverageCycleTime: 5},
+	}
+	teams := map[string]*bean.DoraMetricsGroup{
+		"1-1": {Id: 2, Name: "payments"},
+		"2-1": {Id: 2, Name: "payments"},
+		"3-1": {Id: 1, Name: "checkout"},
+	}
+
+	breakdown := CalculateDoraMetricsBreakdown(metricsData, teams)
+	assert.Len(t, breakdown, 2)
+	assert.Equal(t, "checkout", breakdown[0].Name)
+	assert.Equal(t, 1, breakdown[0].PipelineCount)
+	assert.Equal(t, "payments", breakdown[1].Name)
+	assert.Equal(t, 2, breakdown[1].PipelineCount)
+	assert.InDelta(t, 2, breakdown[1].DeploymentFrequency, 0.0001)
+	assert.InDelta(t, 20, breakdown[1].MeanLeadTime, 0.0001)
+	assert.InDelta(t, 10, breakdown[1].ChangeFailureRate, 0.0001)
+	assert.InDelta(t, 20, breakdown[1].MeanTimeToRecovery, 0.0001)
+}
@@ -0,0 +1,89 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ */
+
+package util
+
+import (
+	"time"
+
+	"github.com/devtron-labs/common-lib/utils/k8s/health"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig/bean/workflow/cdWorkflow"
+	scanBean "github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageScanning/repository/bean"
+)
+
+// Status label values of the exported build and deployment metrics
+const (
+	MetricStatusSucceeded = "succeeded"
+	MetricStatusFailed    = "failed"
+	MetricStatusAborted   = "aborted"
+	MetricStatusTimedOut  = "timed_out"
+)
+
+// ciWorkflowErrorStatus is the status of a CI workflow which errored before or while running its pod
+const ciWorkflowErrorStatus = "Error"
+
+// metricStatusByWorkflowStatus maps the terminal statuses of the CI workflows and the cd workflow runners to the status label
+var metricStatusByWorkflowStatus = map[string]string{
+	cdWorkflow.WorkflowSucceeded:        MetricStatusSucceeded,
+	string(health.HealthStatusHealthy):  MetricStatusSucceeded,
+	cdWorkflow.WorkflowFailed:           MetricStatusFailed,
+	string(health.HealthStatusDegraded): MetricStatusFailed,
+	ciWorkflowErrorStatus:               MetricStatusFailed,
+	cdWorkflow.WorkflowAborted:          MetricStatusAborted,
+	cdWorkflow.WorkflowCancel:           MetricStatusAborted,
+	cdWorkflow.WorkflowTimedOut:         MetricStatusTimedOut,
+}
+
+// GetTerminalWorkflowStatuses returns the statuses of the finished workflows which are recorded in the exported metrics
+func GetTerminalWorkflowStatuses() []string {
+	statuses := make([]string, 0, len(metricStatusByWorkflowStatus))
+	for status := range metricStatusByWorkflowStatus {
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// GetMetricStatus returns the status label of a finished workflow, returns false if the workflow has not finished
+func GetMetricStatus(workflowStatus string) (string, bool) {
+	status, ok := metricStatusByWorkflowStatus[workflowStatus]
+	return status, ok
+}
+
+// GetSeverityLabel returns the severity label of a vulnerability, unknown severities are labelled unknown
+func GetSeverityLabel(severity int) string {
+	if severity < int(scanBean.Low) || severity > int(scanBean.Unknown) {
+		return scanBean.UNKNOWN
+	}
+	return scanBean.Severity(severity).String()
+}
+
+// GetDurationSeconds returns the seconds elapsed from start to end, returns false if either is not set or end is before start
+func GetDurationSeconds(start, end time.Time) (float64, bool) {
+	if start.IsZero() || end.IsZero() || end.Before(start) {
+		return 0, false
+	}
+	return end.Sub(start).Seconds(), true
+}
+
+// RecordedIds remembers the ids of the workflows recorded in the exported metrics with the time they were recorded,
+// so that a workflow returned by overlapping lookback windows is recorded only once
+type RecordedIds map[int]time.Time
+
+// Add marks the id recorded at the given time, returns false if it was already recorded
+func (r RecordedIds) Add(id int, recordedOn time.Time) bool {
+	if _, ok := r[id]; ok {
+		return false
+	}
+	r[id] = recordedOn
+	return true
+}
+
+// Prune forgets the ids recorded before the given time, these cannot be returned again by the lookback window
+func (r RecordedIds) Prune(before time.Time) {
+	for id, recordedOn := range r {
+		if recordedOn.Before(before) {
+			delete(r, id)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ */
+
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetMetricStatus(t *testing.T) {
+	statuses := map[string]string{
+		"Succeeded": MetricStatusSucceeded,
+		"Healthy":   MetricStatusSucceeded,
+		"Failed":    MetricStatusFailed,
+		"Degraded":  MetricStatusFailed,
+		"Error":     MetricStatusFailed,
+		"Aborted":   MetricStatusAborted,
+		"CANCELLED": MetricStatusAborted,
+		"TimedOut":  MetricStatusTimedOut,
+	}
+	for workflowStatus, expected := range statuses {
+		status, ok := GetMetricStatus(workflowStatus)
+		assert.True(t, ok, workflowStatus)
+		assert.Equal(t, expected, status, workflowStatus)
+	}
+	for _, workflowStatus := range []string{"Progressing", "Starting", "Running", ""} {
+		_, ok := GetMetricStatus(workflowStatus)
+		assert.False(t, ok, workflowStatus)
+	}
+	assert.ElementsMatch(t, []string{"Succeeded", "Healthy", "Failed", "Degraded", "Error", "Aborted", "CANCELLED", "TimedOut"}, GetTerminalWorkflowStatuses())
+}
+
+func TestGetSeverityLabel(t *testing.T) {
+	assert.Equal(t, "low", GetSeverityLabel(0))
+	assert.Equal(t, "critical", GetSeverityLabel(2))
+	assert.Equal(t, "unknown", GetSeverityLabel(5))
+	assert.Equal(t, "unknown", GetSeverityLabel(-1))
+	assert.Equal(t, "unknown", GetSeverityLabel(42))
+}
+
+func TestGetDurationSeconds(t *testing.T) {
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	duration, ok := GetDurationSeconds(start, start.Add(90*time.Second))
+	assert.True(t, ok)
+	assert.Equal(t, 90.0, duration)
+
+	_, ok = GetDurationSeconds(start, start.Add(-time.Second))
+	assert.False(t, ok)
+	_, ok = GetDurationSeconds(time.Time{}, start)
+	assert.False(t, ok)
+	_, ok = GetDurationSeconds(start, time.Time{})
+	assert.False(t, ok)
+}
+
+func TestRecordedIds(t *testing.T) {
+	now := time.Now()
+	recorded := make(RecordedIds)
+	assert.True(t, recorded.Add(1, now.Add(-time.Hour)))
+	assert.True(t, recorded.Add(2, now))
+	assert.False(t, recorded.Add(1, now))
+
+	recorded.Prune(now.Add(-time.Minute))
+	assert.True(t, recorded.Add(1, now))
+	assert.False(t, recorded.Add(2, now))
+}
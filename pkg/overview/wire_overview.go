@@ -13,6 +13,8 @@ import (
 // OverviewWireSet provides wire set for overview module
 var OverviewWireSet = wire.NewSet(
 	config.GetClusterOverviewConfig,
+	config.GetDoraMetricsConfig,
+	config.GetMetricsExporterConfig,
 
 	// Service layer
 	NewAppManagementServiceImpl,
@@ -36,6 +38,10 @@ var OverviewWireSet = wire.NewSet(
 	NewSecurityOverviewServiceImpl,
 	wire.Bind(new(SecurityOverviewService), new(*SecurityOverviewServiceImpl)),
 
+	// Metrics exporter (publishes the overview metrics on /metrics when enabled)
+	NewMetricsExporterServiceImpl,
+	wire.Bind(new(MetricsExporterService), new(*MetricsExporterServiceImpl)),
+
 	// Main overview service
 	NewOverviewServiceImpl,
 	wire.Bind(new(OverviewService), new(*OverviewServiceImpl)),
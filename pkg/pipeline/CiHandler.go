@@ -589,7 +589,7 @@ func (impl *CiHandlerImpl) UpdateWorkflow(workflowStatus eventProcessorBean.CiCd
 			savedWorkflow.Status = status
 		}
 		savedWorkflow.PodStatus = podStatus
-		if savedWorkflow.ExecutorType == cdWorkflowBean.WORKFLOW_EXECUTOR_TYPE_SYSTEM && savedWorkflow.Status == cdWorkflowBean.WorkflowCancel {
+		if savedWorkflow.ExecutorType.IsPodExecutor() && savedWorkflow.Status == cdWorkflowBean.WorkflowCancel {
 			savedWorkflow.PodStatus = "Failed"
 			savedWorkflow.Message = constants.TERMINATE_MESSAGE
 		}
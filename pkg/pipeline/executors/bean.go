@@ -19,6 +19,7 @@ package executors
 import (
 	k8sApiV1 "k8s.io/api/core/v1"
 	k8sMetaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 const (
@@ -44,3 +45,77 @@ const (
 	WorkflowJobBackoffLimit = 0
 	WorkflowJobFinalizer    = "foregroundDeletion"
 )
+
+const (
+	TektonApiVersion           = "tekton.dev/v1"
+	TektonPipelineRunKind      = "PipelineRun"
+	TektonPipelineRunPending   = "PipelineRunPending"
+	TektonPipelineRunCancelled = "Cancelled"
+	TektonSucceededCondition   = "Succeeded"
+)
+
+var TektonPipelineRunGVR = schema.GroupVersionResource{Group: "tekton.dev", Version: "v1", Resource: "pipelineruns"}
+
+var ConfigMapGVR = schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+
+var SecretGVR = schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+
+// TektonPipelineRun is the subset of the tekton.dev/v1 PipelineRun used by the tekton executor,
+// the pipeline is embedded in the run and has a single task running the workflow container as its step
+type TektonPipelineRun struct {
+	k8sMetaV1.TypeMeta   `json:",inline"`
+	k8sMetaV1.ObjectMeta `json:"metadata,omitempty"`
+	Spec                 TektonPipelineRunSpec `json:"spec"`
+}
+
+type TektonPipelineRunSpec struct {
+	Status          string                `json:"status,omitempty"`
+	PipelineSpec    TektonPipelineSpec    `json:"pipelineSpec"`
+	TaskRunTemplate TektonTaskRunTemplate `json:"taskRunTemplate,omitempty"`
+	Timeouts        *TektonTimeouts       `json:"timeouts,omitempty"`
+}
+
+type TektonTimeouts struct {
+	Pipeline *k8sMetaV1.Duration `json:"pipeline,omitempty"`
+}
+
+type TektonTaskRunTemplate struct {
+	ServiceAccountName string             `json:"serviceAccountName,omitempty"`
+	PodTemplate        *TektonPodTemplate `json:"podTemplate,omitempty"`
+}
+
+type TektonPodTemplate struct {
+	NodeSelector     map[string]string               `json:"nodeSelector,omitempty"`
+	Tolerations      []k8sApiV1.Toleration           `json:"tolerations,omitempty"`
+	Affinity         *k8sApiV1.Affinity              `json:"affinity,omitempty"`
+	SecurityContext  *k8sApiV1.PodSecurityContext    `json:"securityContext,omitempty"`
+	Volumes          []k8sApiV1.Volume               `json:"volumes,omitempty"`
+	ImagePullSecrets []k8sApiV1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+}
+
+type TektonPipelineSpec struct {
+	Tasks []TektonPipelineTask `json:"tasks"`
+}
+
+type TektonPipelineTask struct {
+	Name     string         `json:"name"`
+	TaskSpec TektonTaskSpec `json:"taskSpec"`
+}
+
+type TektonTaskSpec struct {
+	Steps []TektonStep `json:"steps"`
+}
+
+type TektonStep struct {
+	Name             string                         `json:"name"`
+	Image            string                         `json:"image"`
+	Command          []string                       `json:"command,omitempty"`
+	Args             []string                       `json:"args,omitempty"`
+	WorkingDir       string                         `json:"workingDir,omitempty"`
+	Env              []k8sApiV1.EnvVar              `json:"env,omitempty"`
+	EnvFrom          []k8sApiV1.EnvFromSource       `json:"envFrom,omitempty"`
+	VolumeMounts     []k8sApiV1.VolumeMount         `json:"volumeMounts,omitempty"`
+	ImagePullPolicy  k8sApiV1.PullPolicy            `json:"imagePullPolicy,omitempty"`
+	SecurityContext  *k8sApiV1.SecurityContext      `json:"securityContext,omitempty"`
+	ComputeResources *k8sApiV1.ResourceRequirements `json:"computeResources,omitempty"`
+}
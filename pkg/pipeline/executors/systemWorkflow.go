@@ -22,7 +22,6 @@ import (
 	"github.com/devtron-labs/common-lib/utils/k8s"
 	k8sCommonBean "github.com/devtron-labs/common-lib/utils/k8s/commonBean"
 	"github.com/devtron-labs/devtron/pkg/pipeline/bean"
-	"github.com/devtron-labs/devtron/pkg/pipeline/executors/adapter"
 	types2 "github.com/devtron-labs/devtron/pkg/pipeline/types"
 	"go.uber.org/zap"
 	v1 "k8s.io/api/batch/v1"
@@ -214,37 +213,10 @@ func (impl *SystemWorkflowExecutorImpl) getJobTemplate(workflowTemplate bean.Wor
 }
 
 func (impl *SystemWorkflowExecutorImpl) getCmAndSecrets(workflowTemplate bean.WorkflowTemplate, createdJob *v1.Job) ([]corev1.ConfigMap, []corev1.Secret, error) {
-	var configMaps []corev1.ConfigMap
-	var secrets []corev1.Secret
-	configMapDataArray := workflowTemplate.ConfigMaps
-	for _, configSecretMap := range configMapDataArray {
-		if configSecretMap.External {
-			continue
-		}
-		configMapSecretDto, err := adapter.GetConfigMapSecretDto(configSecretMap, impl.createJobOwnerRefVal(createdJob), false)
-		if err != nil {
-			impl.logger.Errorw("error occurred while creating config map dto", "err", err)
-			return configMaps, secrets, err
-		}
-		configMap := adapter.GetConfigMapBody(configMapSecretDto)
-		configMaps = append(configMaps, configMap)
-	}
-	secretMaps := workflowTemplate.Secrets
-	for _, secretMapData := range secretMaps {
-		if secretMapData.External {
-			continue
-		}
-		configMapSecretDto, err := adapter.GetConfigMapSecretDto(secretMapData, impl.createJobOwnerRefVal(createdJob), true)
-		if err != nil {
-			impl.logger.Errorw("error occurred while creating config map dto", "err", err)
-			return configMaps, secrets, err
-		}
-		secretBody, err := adapter.GetSecretBody(configMapSecretDto)
-		if err != nil {
-			impl.logger.Errorw("error occurred while creating secret body", "err", err)
-			return configMaps, secrets, err
-		}
-		secrets = append(secrets, secretBody)
+	configMaps, secrets, err := getConfigMapsAndSecrets(workflowTemplate, impl.createJobOwnerRefVal(createdJob))
+	if err != nil {
+		impl.logger.Errorw("error occurred while creating config map and secret bodies", "err", err)
+		return configMaps, secrets, err
 	}
 	return configMaps, secrets, nil
 }
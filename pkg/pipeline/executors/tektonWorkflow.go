@@ -0,0 +1,430 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executors
+
+import (
+	"context"
+	"fmt"
+	"github.com/argoproj/argo-workflows/v3/pkg/apis/workflow/v1alpha1"
+	informerBean "github.com/devtron-labs/common-lib/informer"
+	"github.com/devtron-labs/common-lib/utils/k8s"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig/bean/workflow/cdWorkflow"
+	eventProcessorBean "github.com/devtron-labs/devtron/pkg/eventProcessor/bean"
+	"github.com/devtron-labs/devtron/pkg/pipeline/bean"
+	types2 "github.com/devtron-labs/devtron/pkg/pipeline/types"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/errors"
+	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/pointer"
+	"time"
+)
+
+type TektonWorkflowExecutor interface {
+	WorkflowExecutor
+	// ListWorkflowStatus returns the status of the pipeline runs of the workflow type in the namespace by the name of the run,
+	// in the shape of the argo workflow status which kubewatch publishes for the other executors
+	ListWorkflowStatus(workflowType string, namespace string, clusterConfig *rest.Config) (map[string]eventProcessorBean.CiCdStatus, error)
+}
+
+// TektonWorkflowExecutorImpl runs the workflow as a tekton PipelineRun, the run is created in pending state
+// so that the config maps and secrets owned by it are present before its pod starts
+type TektonWorkflowExecutorImpl struct {
+	logger           *zap.SugaredLogger
+	k8sUtil          *k8s.K8sServiceImpl
+	getDynamicClient func(clusterConfig *rest.Config) (dynamic.Interface, error)
+}
+
+func NewTektonWorkflowExecutorImpl(logger *zap.SugaredLogger, k8sUtil *k8s.K8sServiceImpl) *TektonWorkflowExecutorImpl {
+	impl := &TektonWorkflowExecutorImpl{logger: logger, k8sUtil: k8sUtil}
+	impl.getDynamicClient = impl.getK8sDynamicClient
+	return impl
+}
+
+func (impl *TektonWorkflowExecutorImpl) getK8sDynamicClient(clusterConfig *rest.Config) (dynamic.Interface, error) {
+	httpClient, err := rest.HTTPClientFor(clusterConfig)
+	if err != nil {
+		return nil, err
+	}
+	return impl.k8sUtil.GetK8sDynamicClient(clusterConfig, httpClient)
+}
+
+func (impl *TektonWorkflowExecutorImpl) ExecuteWorkflow(workflowTemplate bean.WorkflowTemplate) (*unstructured.UnstructuredList, error) {
+	templatesList := &unstructured.UnstructuredList{}
+	client, err := impl.getDynamicClient(workflowTemplate.ClusterConfig)
+	if err != nil {
+		impl.logger.Errorw("error occurred while creating k8s dynamic client", "WorkflowRunnerId", workflowTemplate.WorkflowRunnerId, "err", err)
+		return nil, err
+	}
+	pipelineRun, err := getPipelineRunTemplate(workflowTemplate)
+	if err != nil {
+		impl.logger.Errorw("error occurred while creating pipeline run template", "WorkflowRunnerId", workflowTemplate.WorkflowRunnerId, "err", err)
+		return nil, err
+	}
+	ctx := context.Background()
+	pipelineRunClient := client.Resource(TektonPipelineRunGVR).Namespace(workflowTemplate.Namespace)
+	createdPipelineRun, err := pipelineRunClient.Create(ctx, pipelineRun, v12.CreateOptions{})
+	if err != nil {
+		impl.logger.Errorw("error occurred while creating tekton pipeline run", "WorkflowRunnerId", workflowTemplate.WorkflowRunnerId, "err", err)
+		return nil, err
+	}
+
+	//create cm and secrets with owner reference
+	err = impl.createCmAndSecrets(client, workflowTemplate, createdPipelineRun, templatesList)
+	if err != nil {
+		impl.logger.Errorw("error occurred while creating cm and secret", "WorkflowRunnerId", workflowTemplate.WorkflowRunnerId, "err", err)
+		return nil, err
+	}
+
+	//start the pipeline run
+	startedPipelineRun, err := pipelineRunClient.Patch(ctx, createdPipelineRun.GetName(), types.MergePatchType, []byte(`{"spec":{"status":null}}`), v12.PatchOptions{})
+	if err != nil {
+		impl.logger.Errorw("error occurred while starting pending pipeline run", "WorkflowRunnerId", workflowTemplate.WorkflowRunnerId, "err", err)
+		return nil, err
+	}
+	templatesList.Items = append(templatesList.Items, *startedPipelineRun)
+	return templatesList, nil
+}
+
+func (impl *TektonWorkflowExecutorImpl) TerminateWorkflow(workflowName string, namespace string, clusterConfig *rest.Config) error {
+	client, err := impl.getDynamicClient(clusterConfig)
+	if err != nil {
+		impl.logger.Errorw("error occurred while creating k8s dynamic client", "workflowName", workflowName, "namespace", namespace, "err", err)
+		return err
+	}
+	err = impl.cancelPipelineRun(client, workflowName, namespace)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			err = fmt.Errorf("cannot find workflow %s", workflowName)
+		}
+		impl.logger.Errorw("error occurred while cancelling pipeline run", "workflowName", workflowName, "namespace", namespace, "err", err)
+	}
+	return err
+}
+
+func (impl *TektonWorkflowExecutorImpl) TerminateDanglingWorkflow(workflowGenerateName string, namespace string, clusterConfig *rest.Config) error {
+	client, err := impl.getDynamicClient(clusterConfig)
+	if err != nil {
+		impl.logger.Errorw("error occurred while creating k8s dynamic client", "workflowGenerateName", workflowGenerateName, "namespace", namespace, "err", err)
+		return err
+	}
+	selectorLabel := fmt.Sprintf("%s=%s", bean.WorkflowGenerateNamePrefix, workflowGenerateName)
+	pipelineRuns, err := client.Resource(TektonPipelineRunGVR).Namespace(namespace).List(context.Background(), v12.ListOptions{LabelSelector: selectorLabel})
+	if err != nil {
+		impl.logger.Errorw("error occurred while fetching pipeline runs for terminating dangling workflows", "namespace", namespace, "err", err)
+		return err
+	}
+	for _, pipelineRun := range pipelineRuns.Items {
+		if isPipelineRunFinished(&pipelineRun) {
+			continue
+		}
+		err = impl.cancelPipelineRun(client, pipelineRun.GetName(), namespace)
+		if err != nil && !errors.IsNotFound(err) {
+			impl.logger.Errorw("error occurred while cancelling pipeline run", "workflowName", pipelineRun.GetName(), "namespace", namespace, "err", err)
+			return err
+		}
+	}
+	return nil
+}
+
+func (impl *TektonWorkflowExecutorImpl) GetWorkflow(workflowName string, namespace string, clusterConfig *rest.Config) (*unstructured.UnstructuredList, error) {
+	client, err := impl.getDynamicClient(clusterConfig)
+	if err != nil {
+		impl.logger.Errorw("error occurred while creating k8s dynamic client", "workflowName", workflowName, "namespace", namespace, "err", err)
+		return nil, err
+	}
+	pipelineRun, err := client.Resource(TektonPipelineRunGVR).Namespace(namespace).Get(context.Background(), workflowName, v12.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			err = fmt.Errorf("cannot find workflow %s", workflowName)
+		}
+		return nil, err
+	}
+	return &unstructured.UnstructuredList{Items: []unstructured.Unstructured{*pipelineRun}}, nil
+}
+
+func (impl *TektonWorkflowExecutorImpl) GetWorkflowStatus(workflowName string, namespace string, clusterConfig *rest.Config) (*types2.WorkflowStatus, error) {
+	client, err := impl.getDynamicClient(clusterConfig)
+	if err != nil {
+		impl.logger.Errorw("error occurred while creating k8s dynamic client", "workflowName", workflowName, "namespace", namespace, "err", err)
+		return nil, err
+	}
+	pipelineRun, err := client.Resource(TektonPipelineRunGVR).Namespace(namespace).Get(context.Background(), workflowName, v12.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			err = fmt.Errorf("cannot find workflow %s", workflowName)
+		}
+		return nil, err
+	}
+	status, message := getPipelineRunStatus(pipelineRun)
+	return &types2.WorkflowStatus{
+		WorkflowName: workflowName,
+		Status:       status,
+		Message:      message,
+	}, nil
+}
+
+func (impl *TektonWorkflowExecutorImpl) ListWorkflowStatus(workflowType string, namespace string, clusterConfig *rest.Config) (map[string]eventProcessorBean.CiCdStatus, error) {
+	client, err := impl.getDynamicClient(clusterConfig)
+	if err != nil {
+		impl.logger.Errorw("error occurred while creating k8s dynamic client", "workflowType", workflowType, "namespace", namespace, "err", err)
+		return nil, err
+	}
+	selectorLabel := fmt.Sprintf("%s=%s", informerBean.WorkflowTypeLabelKey, workflowType)
+	pipelineRuns, err := client.Resource(TektonPipelineRunGVR).Namespace(namespace).List(context.Background(), v12.ListOptions{LabelSelector: selectorLabel})
+	if err != nil {
+		impl.logger.Errorw("error occurred while listing pipeline runs", "workflowType", workflowType, "namespace", namespace, "err", err)
+		return nil, err
+	}
+	workflowStatuses := make(map[string]eventProcessorBean.CiCdStatus, len(pipelineRuns.Items))
+	for _, pipelineRun := range pipelineRuns.Items {
+		workflowStatuses[pipelineRun.GetName()] = getPipelineRunWorkflowStatus(&pipelineRun, workflowType)
+	}
+	return workflowStatuses, nil
+}
+
+func (impl *TektonWorkflowExecutorImpl) cancelPipelineRun(client dynamic.Interface, name string, namespace string) error {
+	patch := []byte(fmt.Sprintf(`{"spec":{"status":"%s"}}`, TektonPipelineRunCancelled))
+	_, err := client.Resource(TektonPipelineRunGVR).Namespace(namespace).Patch(context.Background(), name, types.MergePatchType, patch, v12.PatchOptions{})
+	return err
+}
+
+func (impl *TektonWorkflowExecutorImpl) createCmAndSecrets(client dynamic.Interface, template bean.WorkflowTemplate, createdPipelineRun *unstructured.Unstructured, templateList *unstructured.UnstructuredList) error {
+	ownerRef := v12.OwnerReference{
+		UID:                createdPipelineRun.GetUID(),
+		Name:               createdPipelineRun.GetName(),
+		Kind:               TektonPipelineRunKind,
+		APIVersion:         TektonApiVersion,
+		BlockOwnerDeletion: pointer.BoolPtr(true),
+		Controller:         pointer.BoolPtr(true),
+	}
+	configMaps, secrets, err := getConfigMapsAndSecrets(template, ownerRef)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	for _, configMap := range configMaps {
+		configMapObj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&configMap)
+		if err != nil {
+			return err
+		}
+		createdConfigMap, err := client.Resource(ConfigMapGVR).Namespace(template.Namespace).Create(ctx, &unstructured.Unstructured{Object: configMapObj}, v12.CreateOptions{})
+		if err != nil {
+			impl.logger.Errorw("error occurred while creating cm, but ignoring", "name", configMap.Name, "err", err)
+			continue
+		}
+		templateList.Items = append(templateList.Items, *createdConfigMap)
+	}
+	for _, secret := range secrets {
+		secretObj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&secret)
+		if err != nil {
+			return err
+		}
+		createdSecret, err := client.Resource(SecretGVR).Namespace(template.Namespace).Create(ctx, &unstructured.Unstructured{Object: secretObj}, v12.CreateOptions{})
+		if err != nil {
+			impl.logger.Errorw("error occurred while creating secret, but ignoring", "name", secret.Name, "err", err)
+			continue
+		}
+		templateList.Items = append(templateList.Items, *createdSecret)
+	}
+	return nil
+}
+
+// getPipelineRunTemplate renders the workflow template as a pending PipelineRun, the workflow container is the only step
+// of the only task of the embedded pipeline and the pod level settings are passed through the pod template of the task run
+func getPipelineRunTemplate(workflowTemplate bean.WorkflowTemplate) (*unstructured.Unstructured, error) {
+	if len(workflowTemplate.Containers) == 0 {
+		return nil, fmt.Errorf("no container found in workflow template")
+	}
+	workflowLabels := getWorkflowLabelsForSystemExecutor(workflowTemplate)
+	wfContainer := workflowTemplate.Containers[0]
+	// a zero timeout disables the default timeout of tekton, same as a job without active deadline
+	timeout := &v12.Duration{}
+	if workflowTemplate.ActiveDeadlineSeconds != nil {
+		timeout.Duration = time.Duration(*workflowTemplate.ActiveDeadlineSeconds) * time.Second
+	}
+	pipelineRun := &TektonPipelineRun{
+		TypeMeta: v12.TypeMeta{
+			Kind:       TektonPipelineRunKind,
+			APIVersion: TektonApiVersion,
+		},
+		ObjectMeta: v12.ObjectMeta{
+			GenerateName: fmt.Sprintf(WORKFLOW_GENERATE_NAME_REGEX, workflowTemplate.WorkflowNamePrefix),
+			Labels:       workflowLabels,
+		},
+		Spec: TektonPipelineRunSpec{
+			Status: TektonPipelineRunPending,
+			PipelineSpec: TektonPipelineSpec{
+				Tasks: []TektonPipelineTask{
+					{
+						Name: workflowTemplate.WorkflowType,
+						TaskSpec: TektonTaskSpec{
+							Steps: []TektonStep{
+								{
+									Name:             wfContainer.Name,
+									Image:            wfContainer.Image,
+									Command:          wfContainer.Command,
+									Args:             wfContainer.Args,
+									WorkingDir:       wfContainer.WorkingDir,
+									Env:              wfContainer.Env,
+									EnvFrom:          wfContainer.EnvFrom,
+									VolumeMounts:     wfContainer.VolumeMounts,
+									ImagePullPolicy:  wfContainer.ImagePullPolicy,
+									SecurityContext:  wfContainer.SecurityContext,
+									ComputeResources: &wfContainer.Resources,
+								},
+							},
+						},
+					},
+				},
+			},
+			TaskRunTemplate: TektonTaskRunTemplate{
+				ServiceAccountName: workflowTemplate.ServiceAccountName,
+				PodTemplate: &TektonPodTemplate{
+					NodeSelector:     workflowTemplate.NodeSelector,
+					Tolerations:      workflowTemplate.Tolerations,
+					Affinity:         workflowTemplate.Affinity,
+					SecurityContext:  workflowTemplate.SecurityContext,
+					Volumes:          workflowTemplate.Volumes,
+					ImagePullSecrets: workflowTemplate.ImagePullSecrets,
+				},
+			},
+			Timeouts: &TektonTimeouts{Pipeline: timeout},
+		},
+	}
+	pipelineRunObj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(pipelineRun)
+	if err != nil {
+		return nil, err
+	}
+	return &unstructured.Unstructured{Object: pipelineRunObj}, nil
+}
+
+// getPipelineRunStatus maps the Succeeded condition of the PipelineRun to the workflow status
+func getPipelineRunStatus(pipelineRun *unstructured.Unstructured) (status string, message string) {
+	condition := getPipelineRunSucceededCondition(pipelineRun)
+	if condition == nil {
+		return cdWorkflow.WorkflowStarting, ""
+	}
+	message, _, _ = unstructured.NestedString(condition, "message")
+	conditionStatus, _, _ := unstructured.NestedString(condition, "status")
+	switch conditionStatus {
+	case string(v12.ConditionTrue):
+		return cdWorkflow.WorkflowSucceeded, message
+	case string(v12.ConditionFalse):
+		return cdWorkflow.WorkflowFailed, message
+	default:
+		return cdWorkflow.WorkflowInProgress, message
+	}
+}
+
+// getPipelineRunWorkflowStatus converts the PipelineRun status to the argo workflow status, the pod of the only task run
+// is the only node of the workflow so that the workflow status update path extracts the run name and the pod from it
+func getPipelineRunWorkflowStatus(pipelineRun *unstructured.Unstructured, workflowType string) eventProcessorBean.CiCdStatus {
+	phase, message := getPipelineRunPhase(pipelineRun)
+	podName := getPipelineRunPodName(pipelineRun, workflowType)
+	workflowStatus := &v1alpha1.WorkflowStatus{
+		Phase:      phase,
+		Message:    message,
+		StartedAt:  getPipelineRunStatusTime(pipelineRun, "startTime"),
+		FinishedAt: getPipelineRunStatusTime(pipelineRun, "completionTime"),
+		Nodes: v1alpha1.Nodes{
+			podName: v1alpha1.NodeStatus{
+				ID:           podName,
+				Name:         podName,
+				TemplateName: workflowType,
+				BoundaryID:   pipelineRun.GetName(),
+				Phase:        v1alpha1.NodePhase(phase),
+				Message:      message,
+			},
+		},
+	}
+	return eventProcessorBean.CiCdStatus{
+		DevtronOwnerInstance: pipelineRun.GetLabels()[informerBean.DevtronOwnerInstanceLabelKey],
+		WorkflowStatus:       workflowStatus,
+	}
+}
+
+func getPipelineRunPhase(pipelineRun *unstructured.Unstructured) (v1alpha1.WorkflowPhase, string) {
+	condition := getPipelineRunSucceededCondition(pipelineRun)
+	if condition == nil {
+		return v1alpha1.WorkflowPending, ""
+	}
+	message, _, _ := unstructured.NestedString(condition, "message")
+	conditionStatus, _, _ := unstructured.NestedString(condition, "status")
+	switch conditionStatus {
+	case string(v12.ConditionTrue):
+		return v1alpha1.WorkflowSucceeded, message
+	case string(v12.ConditionFalse):
+		return v1alpha1.WorkflowFailed, message
+	default:
+		return v1alpha1.WorkflowRunning, message
+	}
+}
+
+// getPipelineRunPodName returns the pod of the task run of the PipelineRun, tekton names the task run after the run
+// and the task until the task run is reported in the child references
+func getPipelineRunPodName(pipelineRun *unstructured.Unstructured, workflowType string) string {
+	taskRunName := fmt.Sprintf("%s-%s", pipelineRun.GetName(), workflowType)
+	childReferences, _, _ := unstructured.NestedSlice(pipelineRun.Object, "status", "childReferences")
+	for _, childReference := range childReferences {
+		childReferenceMap, ok := childReference.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if name, _, _ := unstructured.NestedString(childReferenceMap, "name"); len(name) > 0 {
+			taskRunName = name
+			break
+		}
+	}
+	return fmt.Sprintf("%s-pod", taskRunName)
+}
+
+func getPipelineRunStatusTime(pipelineRun *unstructured.Unstructured, field string) v12.Time {
+	value, _, _ := unstructured.NestedString(pipelineRun.Object, "status", field)
+	parsedTime, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return v12.Time{}
+	}
+	return v12.NewTime(parsedTime)
+}
+
+func isPipelineRunFinished(pipelineRun *unstructured.Unstructured) bool {
+	condition := getPipelineRunSucceededCondition(pipelineRun)
+	if condition == nil {
+		return false
+	}
+	conditionStatus, _, _ := unstructured.NestedString(condition, "status")
+	return conditionStatus == string(v12.ConditionTrue) || conditionStatus == string(v12.ConditionFalse)
+}
+
+func getPipelineRunSucceededCondition(pipelineRun *unstructured.Unstructured) map[string]interface{} {
+	conditions, _, _ := unstructured.NestedSlice(pipelineRun.Object, "status", "conditions")
+	for _, condition := range conditions {
+		conditionMap, ok := condition.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if conditionType, _, _ := unstructured.NestedString(conditionMap, "type"); conditionType == TektonSucceededCondition {
+			return conditionMap
+		}
+	}
+	return nil
+}
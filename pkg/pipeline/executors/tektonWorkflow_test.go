@@ -0,0 +1,245 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executors
+
+import (
+	"context"
+	"fmt"
+	"github.com/argoproj/argo-workflows/v3/pkg/apis/workflow/v1alpha1"
+	informerBean "github.com/devtron-labs/common-lib/informer"
+	apiBean "github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig/bean/workflow/cdWorkflow"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/pipeline/bean"
+	"github.com/stretchr/testify/assert"
+	k8sApiV1 "k8s.io/api/core/v1"
+	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/rest"
+	k8sTesting "k8s.io/client-go/testing"
+	"testing"
+)
+
+const tektonTestNamespace = "devtron-ci"
+
+func getTektonExecutorWithFakeClient(t *testing.T, objects ...runtime.Object) (*TektonWorkflowExecutorImpl, *fake.FakeDynamicClient) {
+	logger, err := util.NewSugardLogger()
+	assert.Nil(t, err)
+	client := fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{TektonPipelineRunGVR: "PipelineRunList", ConfigMapGVR: "ConfigMapList", SecretGVR: "SecretList"}, objects...)
+	// the fake client does not generate names
+	generatedNameCount := 0
+	client.PrependReactor("create", "*", func(action k8sTesting.Action) (bool, runtime.Object, error) {
+		obj := action.(k8sTesting.CreateAction).GetObject().(*unstructured.Unstructured)
+		if len(obj.GetName()) == 0 && len(obj.GetGenerateName()) > 0 {
+			generatedNameCount++
+			obj.SetName(fmt.Sprintf("%s%d", obj.GetGenerateName(), generatedNameCount))
+		}
+		return false, nil, nil
+	})
+	impl := &TektonWorkflowExecutorImpl{
+		logger: logger,
+		getDynamicClient: func(clusterConfig *rest.Config) (dynamic.Interface, error) {
+			return client, nil
+		},
+	}
+	return impl, client
+}
+
+func getTektonWorkflowTemplate() bean.WorkflowTemplate {
+	activeDeadlineSeconds := int64(3600)
+	return bean.WorkflowTemplate{
+		WorkflowRunnerId:   1,
+		WorkflowType:       bean.CI_WORKFLOW_NAME,
+		WorkflowNamePrefix: "1-ci",
+		Namespace:          tektonTestNamespace,
+		ClusterConfig:      &rest.Config{},
+		PodSpec: k8sApiV1.PodSpec{
+			ServiceAccountName:    "ci-runner",
+			NodeSelector:          map[string]string{"purpose": "ci"},
+			ActiveDeadlineSeconds: &activeDeadlineSeconds,
+			Containers: []k8sApiV1.Container{
+				{
+					Name:  "ci",
+					Image: "quay.io/devtron/ci-runner:latest",
+					Env:   []k8sApiV1.EnvVar{{Name: "IN_APP_LOGGING", Value: "true"}},
+				},
+			},
+		},
+		ConfigMaps: []apiBean.ConfigSecretMap{
+			{Name: "env-cm", Type: "environment", Data: []byte(`{"KEY":"value"}`)},
+			{Name: "external-cm", Type: "environment", External: true},
+		},
+	}
+}
+
+func getTektonPipelineRun(name string, generateName string, conditionStatus string) *unstructured.Unstructured {
+	pipelineRun := &unstructured.Unstructured{}
+	pipelineRun.SetAPIVersion(TektonApiVersion)
+	pipelineRun.SetKind(TektonPipelineRunKind)
+	pipelineRun.SetName(name)
+	pipelineRun.SetNamespace(tektonTestNamespace)
+	pipelineRun.SetLabels(map[string]string{
+		bean.WorkflowGenerateNamePrefix:           generateName,
+		informerBean.WorkflowTypeLabelKey:         bean.CI_WORKFLOW_NAME,
+		informerBean.DevtronOwnerInstanceLabelKey: "devtron-ucid",
+	})
+	if len(conditionStatus) > 0 {
+		_ = unstructured.SetNestedSlice(pipelineRun.Object, []interface{}{
+			map[string]interface{}{"type": TektonSucceededCondition, "status": conditionStatus, "message": "tasks completed"},
+		}, "status", "conditions")
+	}
+	return pipelineRun
+}
+
+func TestTektonWorkflowExecutor(t *testing.T) {
+
+	t.Run("execute workflow creates started pipeline run with owned config maps", func(t *testing.T) {
+		impl, client := getTektonExecutorWithFakeClient(t)
+		templatesList, err := impl.ExecuteWorkflow(getTektonWorkflowTemplate())
+		assert.Nil(t, err)
+		assert.Len(t, templatesList.Items, 2)
+
+		pipelineRun, err := impl.GetWorkflow("1-ci-1", tektonTestNamespace, nil)
+		assert.Nil(t, err)
+		createdRun := pipelineRun.Items[0]
+		runStatus, found, _ := unstructured.NestedString(createdRun.Object, "spec", "status")
+		assert.False(t, found, "pipeline run should not be pending, status %s", runStatus)
+		assert.Equal(t, "1-ci", createdRun.GetLabels()[bean.WorkflowGenerateNamePrefix])
+		serviceAccountName, _, _ := unstructured.NestedString(createdRun.Object, "spec", "taskRunTemplate", "serviceAccountName")
+		assert.Equal(t, "ci-runner", serviceAccountName)
+		timeout, _, _ := unstructured.NestedString(createdRun.Object, "spec", "timeouts", "pipeline")
+		assert.Equal(t, "1h0m0s", timeout)
+		tasks, _, _ := unstructured.NestedSlice(createdRun.Object, "spec", "pipelineSpec", "tasks")
+		assert.Len(t, tasks, 1)
+		steps, _, _ := unstructured.NestedSlice(tasks[0].(map[string]interface{}), "taskSpec", "steps")
+		assert.Len(t, steps, 1)
+		assert.Equal(t, "quay.io/devtron/ci-runner:latest", steps[0].(map[string]interface{})["image"])
+
+		configMaps, err := client.Resource(ConfigMapGVR).Namespace(tektonTestNamespace).List(context.Background(), v12.ListOptions{})
+		assert.Nil(t, err)
+		assert.Len(t, configMaps.Items, 1)
+		ownerRefs := configMaps.Items[0].GetOwnerReferences()
+		assert.Len(t, ownerRefs, 1)
+		assert.Equal(t, TektonPipelineRunKind, ownerRefs[0].Kind)
+		assert.Equal(t, "1-ci-1", ownerRefs[0].Name)
+	})
+
+	t.Run("workflow status is mapped from succeeded condition", func(t *testing.T) {
+		impl, _ := getTektonExecutorWithFakeClient(t,
+			getTektonPipelineRun("pending", "1-ci", ""),
+			getTektonPipelineRun("running", "1-ci", "Unknown"),
+			getTektonPipelineRun("succeeded", "1-ci", "True"),
+			getTektonPipelineRun("failed", "1-ci", "False"),
+		)
+		expectedStatuses := map[string]string{
+			"pending":   cdWorkflow.WorkflowStarting,
+			"running":   cdWorkflow.WorkflowInProgress,
+			"succeeded": cdWorkflow.WorkflowSucceeded,
+			"failed":    cdWorkflow.WorkflowFailed,
+		}
+		for name, expectedStatus := range expectedStatuses {
+			wfStatus, err := impl.GetWorkflowStatus(name, tektonTestNamespace, nil)
+			assert.Nil(t, err)
+			assert.Equal(t, expectedStatus, wfStatus.Status, name)
+		}
+		_, err := impl.GetWorkflowStatus("missing", tektonTestNamespace, nil)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("list workflow status returns the pipeline runs as workflow status", func(t *testing.T) {
+		succeededRun := getTektonPipelineRun("1-ci-succeeded", "1-ci", "True")
+		_ = unstructured.SetNestedField(succeededRun.Object, "2024-01-02T10:00:00Z", "status", "completionTime")
+		_ = unstructured.SetNestedSlice(succeededRun.Object, []interface{}{
+			map[string]interface{}{"kind": "TaskRun", "name": "1-ci-succeeded-ci-run", "pipelineTaskName": "ci"},
+		}, "status", "childReferences")
+		cdRun := getTektonPipelineRun("2-cd-running", "2-cd", "Unknown")
+		cdRun.SetLabels(map[string]string{informerBean.WorkflowTypeLabelKey: bean.CD_WORKFLOW_NAME})
+		impl, _ := getTektonExecutorWithFakeClient(t,
+			getTektonPipelineRun("1-ci-pending", "1-ci", ""),
+			getTektonPipelineRun("1-ci-running", "1-ci", "Unknown"),
+			succeededRun,
+			getTektonPipelineRun("1-ci-failed", "1-ci", "False"),
+			cdRun,
+		)
+		workflowStatuses, err := impl.ListWorkflowStatus(bean.CI_WORKFLOW_NAME, tektonTestNamespace, nil)
+		assert.Nil(t, err)
+		assert.Len(t, workflowStatuses, 4)
+		expectedPhases := map[string]v1alpha1.WorkflowPhase{
+			"1-ci-pending":   v1alpha1.WorkflowPending,
+			"1-ci-running":   v1alpha1.WorkflowRunning,
+			"1-ci-succeeded": v1alpha1.WorkflowSucceeded,
+			"1-ci-failed":    v1alpha1.WorkflowFailed,
+		}
+		for name, expectedPhase := range expectedPhases {
+			assert.Equal(t, expectedPhase, workflowStatuses[name].Phase, name)
+			assert.Equal(t, "devtron-ucid", workflowStatuses[name].DevtronOwnerInstance, name)
+		}
+
+		workflowStatus := workflowStatuses["1-ci-succeeded"]
+		assert.Equal(t, "tasks completed", workflowStatus.Message)
+		assert.Equal(t, 2024, workflowStatus.FinishedAt.Year())
+		node, ok := workflowStatus.Nodes["1-ci-succeeded-ci-run-pod"]
+		assert.True(t, ok)
+		assert.Equal(t, bean.CI_WORKFLOW_NAME, node.TemplateName)
+		assert.Equal(t, "1-ci-succeeded", node.BoundaryID)
+		assert.Equal(t, v1alpha1.NodeSucceeded, node.Phase)
+
+		// the task run of a pending run is not reported yet
+		_, ok = workflowStatuses["1-ci-pending"].Nodes["1-ci-pending-ci-pod"]
+		assert.True(t, ok)
+		assert.True(t, workflowStatuses["1-ci-pending"].FinishedAt.IsZero())
+	})
+
+	t.Run("terminate cancels the pipeline run", func(t *testing.T) {
+		impl, _ := getTektonExecutorWithFakeClient(t, getTektonPipelineRun("running", "1-ci", "Unknown"))
+		err := impl.TerminateWorkflow("running", tektonTestNamespace, nil)
+		assert.Nil(t, err)
+		pipelineRun, err := impl.GetWorkflow("running", tektonTestNamespace, nil)
+		assert.Nil(t, err)
+		runStatus, _, _ := unstructured.NestedString(pipelineRun.Items[0].Object, "spec", "status")
+		assert.Equal(t, TektonPipelineRunCancelled, runStatus)
+
+		err = impl.TerminateWorkflow("missing", tektonTestNamespace, nil)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("terminate dangling cancels only unfinished runs of the prefix", func(t *testing.T) {
+		impl, _ := getTektonExecutorWithFakeClient(t,
+			getTektonPipelineRun("running", "1-ci", "Unknown"),
+			getTektonPipelineRun("succeeded", "1-ci", "True"),
+			getTektonPipelineRun("other", "2-ci", "Unknown"),
+		)
+		err := impl.TerminateDanglingWorkflow("1-ci", tektonTestNamespace, nil)
+		assert.Nil(t, err)
+		expectedStatuses := map[string]string{
+			"running":   TektonPipelineRunCancelled,
+			"succeeded": "",
+			"other":     "",
+		}
+		for name, expectedStatus := range expectedStatuses {
+			pipelineRun, err := impl.GetWorkflow(name, tektonTestNamespace, nil)
+			assert.Nil(t, err)
+			runStatus, _, _ := unstructured.NestedString(pipelineRun.Items[0].Object, "spec", "status")
+			assert.Equal(t, expectedStatus, runStatus, name)
+		}
+	})
+}
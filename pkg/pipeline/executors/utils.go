@@ -31,6 +31,7 @@ import (
 	"github.com/devtron-labs/devtron/pkg/pipeline/types"
 	"github.com/devtron-labs/devtron/util"
 	k8sApiV1 "k8s.io/api/core/v1"
+	k8sMetaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/rest"
 	"strconv"
 )
@@ -250,3 +251,35 @@ func getWorkflowLabelsForSystemExecutor(workflowTemplate bean.WorkflowTemplate)
 		informerBean.DevtronOwnerInstanceLabelKey: workflowTemplate.DevtronInstanceUID,
 	}
 }
+
+// getConfigMapsAndSecrets returns the config maps and secrets of the workflow which are created by the executor
+// with the given owner reference, external ones are skipped as they are already present in the cluster
+func getConfigMapsAndSecrets(workflowTemplate bean.WorkflowTemplate, ownerRef k8sMetaV1.OwnerReference) ([]k8sApiV1.ConfigMap, []k8sApiV1.Secret, error) {
+	var configMaps []k8sApiV1.ConfigMap
+	var secrets []k8sApiV1.Secret
+	for _, configSecretMap := range workflowTemplate.ConfigMaps {
+		if configSecretMap.External {
+			continue
+		}
+		configMapSecretDto, err := adapter.GetConfigMapSecretDto(configSecretMap, ownerRef, false)
+		if err != nil {
+			return configMaps, secrets, err
+		}
+		configMaps = append(configMaps, adapter.GetConfigMapBody(configMapSecretDto))
+	}
+	for _, secretMapData := range workflowTemplate.Secrets {
+		if secretMapData.External {
+			continue
+		}
+		configMapSecretDto, err := adapter.GetConfigMapSecretDto(secretMapData, ownerRef, true)
+		if err != nil {
+			return configMaps, secrets, err
+		}
+		secretBody, err := adapter.GetSecretBody(configMapSecretDto)
+		if err != nil {
+			return configMaps, secrets, err
+		}
+		secrets = append(secrets, secretBody)
+	}
+	return configMaps, secrets, nil
+}
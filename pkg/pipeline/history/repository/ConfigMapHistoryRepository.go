@@ -38,6 +38,10 @@ type ConfigMapHistoryRepository interface {
 	GetHistoryByPipelineIdAndWfrId(pipelineId, wfrId int, configType ConfigType) (*ConfigmapAndSecretHistory, error)
 	GetDeployedHistoryForPipelineIdOnTime(pipelineId int, deployedOn time.Time, configType ConfigType) (*ConfigmapAndSecretHistory, error)
 	GetDeployedHistoryList(pipelineId, baseConfigId int, configType ConfigType, componentName string) ([]*ConfigmapAndSecretHistory, error)
+	GetHistoryById(id int) (*ConfigmapAndSecretHistory, error)
+	// GetLatestHistoryByAppIdAndEnvId returns the latest saved (not deployed) version of the app level configs if envId is 0,
+	// else of the configs merged for any pipeline of the environment
+	GetLatestHistoryByAppIdAndEnvId(appId, envId int, configType ConfigType) (*ConfigmapAndSecretHistory, error)
 }
 
 type ConfigMapHistoryRepositoryImpl struct {
@@ -143,3 +147,32 @@ func (impl ConfigMapHistoryRepositoryImpl) GetDeployedHistoryForPipelineIdOnTime
 		Select()
 	return &history, err
 }
+
+func (impl ConfigMapHistoryRepositoryImpl) GetHistoryById(id int) (*ConfigmapAndSecretHistory, error) {
+	var history ConfigmapAndSecretHistory
+	err := impl.dbConnection.Model(&history).Where("id = ?", id).Select()
+	if err != nil {
+		impl.logger.Errorw("error in getting CM/CS history by id", "err", err, "id", id)
+		return &history, err
+	}
+	return &history, nil
+}
+
+func (impl ConfigMapHistoryRepositoryImpl) GetLatestHistoryByAppIdAndEnvId(appId, envId int, configType ConfigType) (*ConfigmapAndSecretHistory, error) {
+	var history ConfigmapAndSecretHistory
+	query := impl.dbConnection.Model(&history).
+		Where("app_id = ?", appId).
+		Where("data_type = ?", configType).
+		Where("deployed = ?", false)
+	if envId == 0 {
+		query = query.Where("pipeline_id IS NULL OR pipeline_id = 0")
+	} else {
+		query = query.Where("pipeline_id IN (SELECT id FROM pipeline WHERE app_id = ? AND environment_id = ?)", appId, envId)
+	}
+	err := query.Order("id DESC").Limit(1).Select()
+	if err != nil {
+		impl.logger.Errorw("error in getting latest CM/CS history", "err", err, "appId", appId, "envId", envId, "configType", configType)
+		return &history, err
+	}
+	return &history, nil
+}
@@ -31,6 +31,10 @@ type DeploymentTemplateHistoryRepository interface {
 	GetDeployedHistoryForPipelineIdOnTime(pipelineId int, deployedOn time.Time) (*DeploymentTemplateHistory, error)
 	GetDeployedHistoryList(pipelineId, baseConfigId int) ([]*DeploymentTemplateHistory, error)
 	GetDeployedOnByDeploymentTemplateAndPipelineId(id, pipelineId int) (time.Time, error)
+	GetHistoryById(id int) (*DeploymentTemplateHistory, error)
+	// GetLatestHistoryByAppIdAndEnvId returns the latest saved (not deployed) version of the base template if envId is 0,
+	// else of the template overridden for the environment
+	GetLatestHistoryByAppIdAndEnvId(appId, envId int) (*DeploymentTemplateHistory, error)
 }
 
 type DeploymentTemplateHistoryRepositoryImpl struct {
@@ -145,3 +149,31 @@ func (impl DeploymentTemplateHistoryRepositoryImpl) GetDeployedOnByDeploymentTem
 	}
 	return deployedOn, nil
 }
+
+func (impl DeploymentTemplateHistoryRepositoryImpl) GetHistoryById(id int) (*DeploymentTemplateHistory, error) {
+	var history DeploymentTemplateHistory
+	err := impl.dbConnection.Model(&history).Where("id = ?", id).Select()
+	if err != nil {
+		impl.logger.Errorw("error in getting deployment template history by id", "err", err, "id", id)
+		return &history, err
+	}
+	return &history, nil
+}
+
+func (impl DeploymentTemplateHistoryRepositoryImpl) GetLatestHistoryByAppIdAndEnvId(appId, envId int) (*DeploymentTemplateHistory, error) {
+	var history DeploymentTemplateHistory
+	query := impl.dbConnection.Model(&history).
+		Where("app_id = ?", appId).
+		Where("deployed = ?", false)
+	if envId == 0 {
+		query = query.Where("target_environment IS NULL OR target_environment = 0")
+	} else {
+		query = query.Where("target_environment = ?", envId)
+	}
+	err := query.Order("id DESC").Limit(1).Select()
+	if err != nil {
+		impl.logger.Errorw("error in getting latest deployment template history", "err", err, "appId", appId, "envId", envId)
+		return &history, err
+	}
+	return &history, nil
+}
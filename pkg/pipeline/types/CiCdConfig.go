@@ -91,7 +91,7 @@ type CiCdConfig struct {
 	EnableBuildContext               bool                            `env:"ENABLE_BUILD_CONTEXT" envDefault:"false" description:"To Enable build context in Devtron."`
 	ImageRetryCount                  int                             `env:"IMAGE_RETRY_COUNT" envDefault:"0" description:"push artifact(image) in ci retry count "`
 	ImageRetryInterval               int                             `env:"IMAGE_RETRY_INTERVAL" envDefault:"5" description:"image retry interval takes value in seconds"` // image retry interval takes value in seconds
-	CiWorkflowExecutorType           cdWorkflow.WorkflowExecutorType `env:"CI_WORKFLOW_EXECUTOR_TYPE" envDefault:"AWF" description:"Executor type for CI(AWF,System)"`
+	CiWorkflowExecutorType           cdWorkflow.WorkflowExecutorType `env:"CI_WORKFLOW_EXECUTOR_TYPE" envDefault:"AWF" description:"Default executor type for CI(AWF,System,Tekton), the executor type set on the cluster takes precedence"`
 	BuildxK8sDriverOptions           string                          `env:"BUILDX_K8S_DRIVER_OPTIONS" envDefault:"" description:"To enable the k8s driver and pass args for k8s driver in buildx"`
 	CIAutoTriggerBatchSize           int                             `env:"CI_SUCCESS_AUTO_TRIGGER_BATCH_SIZE" envDefault:"1" description:"this is to control the no of linked pipelines should be hanled in one go when a ci-success event of an parent ci is received"`
 	SkipCreatingEcrRepo              bool                            `env:"SKIP_CREATING_ECR_REPO" envDefault:"false" description:"By disabling this ECR repo won't get created if it's not available on ECR from build configuration"`
@@ -128,7 +128,7 @@ type CiCdConfig struct {
 	CdDefaultAddressPoolSize         int                             `env:"CD_DEFAULT_ADDRESS_POOL_SIZE" description:"The subnet size to allocate from the base pool for CD"`
 	ExposeCDMetrics                  bool                            `env:"EXPOSE_CD_METRICS" envDefault:"false" description:"To expose CD metrics"`
 	UseBlobStorageConfigInCdWorkflow bool                            `env:"USE_BLOB_STORAGE_CONFIG_IN_CD_WORKFLOW" envDefault:"true" description:"To enable blob storage in pre and post cd"`
-	CdWorkflowExecutorType           cdWorkflow.WorkflowExecutorType `env:"CD_WORKFLOW_EXECUTOR_TYPE" envDefault:"AWF" description:"Executor type for Pre/Post CD(AWF,System)"`
+	CdWorkflowExecutorType           cdWorkflow.WorkflowExecutorType `env:"CD_WORKFLOW_EXECUTOR_TYPE" envDefault:"AWF" description:"Default executor type for Pre/Post CD(AWF,System,Tekton), the executor type set on the cluster takes precedence"`
 	TerminationGracePeriod           int                             `env:"TERMINATION_GRACE_PERIOD_SECS" envDefault:"180" description:"this is the time given to workflow pods to shutdown. (grace full termination time)"`
 	MaxCdWorkflowRunnerRetries       int                             `env:"MAX_CD_WORKFLOW_RUNNER_RETRIES" envDefault:"0" description:"Maximum time pre/post-cd-workflow create pod if it fails to complete"`
 
@@ -485,6 +485,15 @@ func (impl *CiCdConfig) GetWorkflowExecutorType() cdWorkflow.WorkflowExecutorTyp
 	}
 }
 
+// GetWorkflowExecutorTypeForCluster returns the executor type set on the cluster the workflow runs in,
+// the executor type of the config is used for the clusters without one
+func (impl *CiCdConfig) GetWorkflowExecutorTypeForCluster(clusterExecutorType string) cdWorkflow.WorkflowExecutorType {
+	if len(clusterExecutorType) > 0 {
+		return cdWorkflow.WorkflowExecutorType(clusterExecutorType)
+	}
+	return impl.GetWorkflowExecutorType()
+}
+
 func (impl *CiCdConfig) WorkflowRetriesEnabled() bool {
 	switch impl.Type {
 	case CiConfigType:
@@ -394,7 +394,7 @@ func (workflowRequest *WorkflowRequest) getBlobStorageLogsPrefix() string {
 
 func (workflowRequest *WorkflowRequest) updateBlobStorageLogsKey(config *CiCdConfig) {
 	workflowRequest.BlobStorageLogsKey = fmt.Sprintf("%s/%s", workflowRequest.getDefaultBuildLogsKeyPrefix(config), workflowRequest.getBlobStorageLogsPrefix())
-	workflowRequest.InAppLoggingEnabled = config.InAppLoggingEnabled || (workflowRequest.WorkflowExecutor == cdWorkflow.WORKFLOW_EXECUTOR_TYPE_SYSTEM)
+	workflowRequest.InAppLoggingEnabled = config.InAppLoggingEnabled || workflowRequest.WorkflowExecutor.IsPodExecutor()
 }
 
 func (workflowRequest *WorkflowRequest) getWorkflowJson() ([]byte, error) {
@@ -16,6 +16,17 @@ type WorkflowStageRepository interface {
 	GetWorkflowStagesByWorkflowIdAndWtype(wfId int, wfType string) ([]*WorkflowExecutionStage, error)
 	GetWorkflowStagesByWorkflowIdsAndWtype(wfIds []int, wfType string) ([]*WorkflowExecutionStage, error)
 	GetSuccessfulCIExecutionStages(from, to *time.Time) ([]*WorkflowExecutionStage, error)
+	GetPreparationStagesUpdatedInTimeRange(from, to *time.Time) ([]*PreparationStageData, error)
+}
+
+// PreparationStageData is the completed preparation stage of a CI or pre/post deployment workflow,
+// the time spent in this stage is the time the workflow waited before its pod started executing
+type PreparationStageData struct {
+	Id           int    `sql:"id"`
+	AppName      string `sql:"app_name"`
+	WorkflowType string `sql:"workflow_type"`
+	StartTime    string `sql:"start_time"`
+	EndTime      string `sql:"end_time"`
 }
 
 type WorkflowStageRepositoryImpl struct {
@@ -114,3 +125,31 @@ func (impl *WorkflowStageRepositoryImpl) GetSuccessfulCIExecutionStages(from, to
 	}
 	return workflowStages, nil
 }
+
+// GetPreparationStagesUpdatedInTimeRange returns the preparation stages which got completed (updated with an end time) in the time range (from, to]
+func (impl *WorkflowStageRepositoryImpl) GetPreparationStagesUpdatedInTimeRange(from, to *time.Time) ([]*PreparationStageData, error) {
+	var stages []*PreparationStageData
+	query := `
+		SELECT wes.id, a.app_name, wes.workflow_type, wes.start_time, wes.end_time
+		FROM workflow_execution_stage wes
+		LEFT JOIN ci_workflow cw ON wes.workflow_type = 'CI' AND cw.id = wes.workflow_id
+		LEFT JOIN ci_pipeline cp ON cp.id = cw.ci_pipeline_id
+		LEFT JOIN cd_workflow_runner cwr ON wes.workflow_type IN ('PRE', 'POST') AND cwr.id = wes.workflow_id
+		LEFT JOIN cd_workflow cdw ON cdw.id = cwr.cd_workflow_id
+		LEFT JOIN pipeline p ON p.id = cdw.pipeline_id
+		INNER JOIN app a ON a.id = COALESCE(cp.app_id, p.app_id)
+		WHERE wes.stage_name = ?
+			AND wes.status_for = ?
+			AND wes.start_time IS NOT NULL AND wes.start_time <> ''
+			AND wes.end_time IS NOT NULL AND wes.end_time <> ''
+			AND wes.updated_on > ?
+			AND wes.updated_on <= ?
+		ORDER BY wes.id
+	`
+	_, err := impl.dbConnection.Query(&stages, query, bean.WORKFLOW_PREPARATION, bean.WORKFLOW_STAGE_STATUS_TYPE_WORKFLOW, from, to)
+	if err != nil {
+		impl.logger.Errorw("error in fetching preparation stages updated in time range", "from", from, "to", to, "err", err)
+		return stages, err
+	}
+	return stages, nil
+}
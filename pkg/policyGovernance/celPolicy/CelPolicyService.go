@@ -0,0 +1,465 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package celPolicy
+
+import (
+	"context"
+	"fmt"
+	"github.com/devtron-labs/devtron/cel"
+	appRepository "github.com/devtron-labs/devtron/internal/sql/repository/app"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/cluster/environment/repository"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/celPolicy/adapter"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/celPolicy/bean"
+	celPolicyRepository "github.com/devtron-labs/devtron/pkg/policyGovernance/celPolicy/repository"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/policyScope"
+	"github.com/devtron-labs/devtron/pkg/resourceQualifiers"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	celGo "github.com/google/cel-go/cel"
+	"go.opentelemetry.io/otel"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+type CelPolicyService interface {
+	CreatePolicy(ctx context.Context, policy *bean.CelPolicyDto) (*bean.CelPolicyDto, error)
+	UpdatePolicy(ctx context.Context, policy *bean.CelPolicyDto) (*bean.CelPolicyDto, error)
+	DeletePolicy(ctx context.Context, id int, userId int32) error
+	GetPolicyById(id int) (*bean.CelPolicyDto, error)
+	GetAllPolicies() ([]*bean.CelPolicyDto, error)
+	// DryRun evaluates the expression against the user given params without persisting anything,
+	// params not given in the request are evaluated with their zero values
+	DryRun(request *bean.DryRunRequest) (*bean.DryRunResponse, error)
+	// EvaluatePolicies evaluates all the enabled policies of the hook which are applicable on the request scope.
+	// returns *bean.PolicyViolationError if any of the blocking policies is violated
+	EvaluatePolicies(ctx context.Context, request *bean.EvaluationRequest) (*bean.EvaluationResult, error)
+}
+
+type CelPolicyServiceImpl struct {
+	logger              *zap.SugaredLogger
+	celPolicyRepository celPolicyRepository.CelPolicyRepository
+	policyScopeService  policyScope.PolicyScopeService
+	celEvaluatorService cel.EvaluatorService
+	appRepository       appRepository.AppRepository
+	envRepository       repository.EnvironmentRepository
+}
+
+func NewCelPolicyServiceImpl(logger *zap.SugaredLogger,
+	celPolicyRepository celPolicyRepository.CelPolicyRepository,
+	policyScopeService policyScope.PolicyScopeService,
+	celEvaluatorService cel.EvaluatorService,
+	appRepository appRepository.AppRepository,
+	envRepository repository.EnvironmentRepository) *CelPolicyServiceImpl {
+	return &CelPolicyServiceImpl{
+		logger:              logger,
+		celPolicyRepository: celPolicyRepository,
+		policyScopeService:  policyScopeService,
+		celEvaluatorService: celEvaluatorService,
+		appRepository:       appRepository,
+		envRepository:       envRepository,
+	}
+}
+
+func (impl *CelPolicyServiceImpl) CreatePolicy(ctx context.Context, policy *bean.CelPolicyDto) (*bean.CelPolicyDto, error) {
+	_, span := otel.Tracer("orchestrator").Start(ctx, "CelPolicyServiceImpl.CreatePolicy")
+	defer span.End()
+	err := impl.validatePolicy(policy)
+	if err != nil {
+		return nil, err
+	}
+	existingPolicy, err := impl.celPolicyRepository.FindByName(policy.Name)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching policy by name", "name", policy.Name, "err", err)
+		return nil, err
+	}
+	if existingPolicy != nil && existingPolicy.Id > 0 {
+		errMsg := fmt.Sprintf(bean.PolicyNameExistsMessage, policy.Name)
+		return nil, util.NewApiError(http.StatusConflict, errMsg, errMsg)
+	}
+	tx, err := impl.celPolicyRepository.StartTx()
+	if err != nil {
+		impl.logger.Errorw("error in starting transaction", "err", err)
+		return nil, err
+	}
+	defer tx.Rollback()
+	dbObject := adapter.GetCelPolicyDbObject(policy, sql.NewDefaultAuditLog(policy.UserId))
+	err = impl.celPolicyRepository.Save(dbObject, tx)
+	if err != nil {
+		impl.logger.Errorw("error in saving cel policy", "policy", policy, "err", err)
+		return nil, err
+	}
+	err = impl.policyScopeService.CreateScopes(tx, resourceQualifiers.CelPolicy, dbObject.Id, policy.Scopes, policy.UserId)
+	if err != nil {
+		impl.logger.Errorw("error in creating scope mappings for cel policy", "policyId", dbObject.Id, "err", err)
+		return nil, err
+	}
+	err = impl.celPolicyRepository.CommitTx(tx)
+	if err != nil {
+		impl.logger.Errorw("error in committing transaction", "err", err)
+		return nil, err
+	}
+	policy.Id = dbObject.Id
+	return policy, nil
+}
+
+func (impl *CelPolicyServiceImpl) UpdatePolicy(ctx context.Context, policy *bean.CelPolicyDto) (*bean.CelPolicyDto, error) {
+	_, span := otel.Tracer("orchestrator").Start(ctx, "CelPolicyServiceImpl.UpdatePolicy")
+	defer span.End()
+	err := impl.validatePolicy(policy)
+	if err != nil {
+		return nil, err
+	}
+	existingPolicy, err := impl.getPolicyDbObject(policy.Id)
+	if err != nil {
+		return nil, err
+	}
+	if existingPolicy.Name != policy.Name {
+		policyWithSameName, err := impl.celPolicyRepository.FindByName(policy.Name)
+		if err != nil && !util.IsErrNoRows(err) {
+			impl.logger.Errorw("error in fetching policy by name", "name", policy.Name, "err", err)
+			return nil, err
+		}
+		if policyWithSameName != nil && policyWithSameName.Id > 0 {
+			errMsg := fmt.Sprintf(bean.PolicyNameExistsMessage, policy.Name)
+			return nil, util.NewApiError(http.StatusConflict, errMsg, errMsg)
+		}
+	}
+	tx, err := impl.celPolicyRepository.StartTx()
+	if err != nil {
+		impl.logger.Errorw("error in starting transaction", "err", err)
+		return nil, err
+	}
+	defer tx.Rollback()
+	auditLog := existingPolicy.AuditLog
+	auditLog.UpdateAuditLog(policy.UserId)
+	dbObject := adapter.GetCelPolicyDbObject(policy, auditLog)
+	err = impl.celPolicyRepository.Update(dbObject, tx)
+	if err != nil {
+		impl.logger.Errorw("error in updating cel policy", "policy", policy, "err", err)
+		return nil, err
+	}
+	err = impl.policyScopeService.DeleteScopes(tx, resourceQualifiers.CelPolicy, policy.Id, policy.UserId)
+	if err != nil {
+		return nil, err
+	}
+	err = impl.policyScopeService.CreateScopes(tx, resourceQualifiers.CelPolicy, policy.Id, policy.Scopes, policy.UserId)
+	if err != nil {
+		impl.logger.Errorw("error in creating scope mappings for cel policy", "policyId", policy.Id, "err", err)
+		return nil, err
+	}
+	err = impl.celPolicyRepository.CommitTx(tx)
+	if err != nil {
+		impl.logger.Errorw("error in committing transaction", "err", err)
+		return nil, err
+	}
+	return policy, nil
+}
+
+func (impl *CelPolicyServiceImpl) DeletePolicy(ctx context.Context, id int, userId int32) error {
+	_, span := otel.Tracer("orchestrator").Start(ctx, "CelPolicyServiceImpl.DeletePolicy")
+	defer span.End()
+	existingPolicy, err := impl.getPolicyDbObject(id)
+	if err != nil {
+		return err
+	}
+	tx, err := impl.celPolicyRepository.StartTx()
+	if err != nil {
+		impl.logger.Errorw("error in starting transaction", "err", err)
+		return err
+	}
+	defer tx.Rollback()
+	existingPolicy.Deleted = true
+	existingPolicy.UpdateAuditLog(userId)
+	err = impl.celPolicyRepository.Update(existingPolicy, tx)
+	if err != nil {
+		impl.logger.Errorw("error in deleting cel policy", "policyId", id, "err", err)
+		return err
+	}
+	err = impl.policyScopeService.DeleteScopes(tx, resourceQualifiers.CelPolicy, id, userId)
+	if err != nil {
+		return err
+	}
+	return impl.celPolicyRepository.CommitTx(tx)
+}
+
+func (impl *CelPolicyServiceImpl) GetPolicyById(id int) (*bean.CelPolicyDto, error) {
+	policy, err := impl.getPolicyDbObject(id)
+	if err != nil {
+		return nil, err
+	}
+	policyIdToScopes, err := impl.policyScopeService.GetScopes(resourceQualifiers.CelPolicy, []int{id})
+	if err != nil {
+		return nil, err
+	}
+	return adapter.GetCelPolicyDto(policy, policyIdToScopes[id]), nil
+}
+
+func (impl *CelPolicyServiceImpl) GetAllPolicies() ([]*bean.CelPolicyDto, error) {
+	policies, err := impl.celPolicyRepository.FindAll()
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching cel policies", "err", err)
+		return nil, err
+	}
+	result := make([]*bean.CelPolicyDto, 0, len(policies))
+	if len(policies) == 0 {
+		return result, nil
+	}
+	policyIds := make([]int, 0, len(policies))
+	for _, policy := range policies {
+		policyIds = append(policyIds, policy.Id)
+	}
+	policyIdToScopes, err := impl.policyScopeService.GetScopes(resourceQualifiers.CelPolicy, policyIds)
+	if err != nil {
+		return nil, err
+	}
+	for _, policy := range policies {
+		result = append(result, adapter.GetCelPolicyDto(policy, policyIdToScopes[policy.Id]))
+	}
+	return result, nil
+}
+
+func (impl *CelPolicyServiceImpl) DryRun(request *bean.DryRunRequest) (*bean.DryRunResponse, error) {
+	if !request.Hook.IsValid() {
+		errMsg := fmt.Sprintf(bean.InvalidHookMessage, request.Hook)
+		return nil, util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+	}
+	params, err := getDryRunParams(request.Hook, request.Params)
+	if err != nil {
+		return nil, util.NewApiError(http.StatusBadRequest, err.Error(), err.Error())
+	}
+	response := &bean.DryRunResponse{
+		Params: params,
+	}
+	result, err := impl.celEvaluatorService.EvaluateCELRequest(cel.Request{
+		Expression: request.Expression,
+		ExpressionMetadata: cel.ExpressionMetadata{
+			Params: params,
+		},
+	})
+	if err != nil {
+		// evaluation errors are part of the dry run result, not a failure of the api
+		response.Error = err.Error()
+		return response, nil
+	}
+	response.Result = result
+	return response, nil
+}
+
+func (impl *CelPolicyServiceImpl) EvaluatePolicies(ctx context.Context, request *bean.EvaluationRequest) (*bean.EvaluationResult, error) {
+	_, span := otel.Tracer("orchestrator").Start(ctx, "CelPolicyServiceImpl.EvaluatePolicies")
+	defer span.End()
+	result := &bean.EvaluationResult{}
+	policies, err := impl.celPolicyRepository.FindEnabledByHook(request.Hook.ToString())
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching cel policies for hook", "hook", request.Hook, "err", err)
+		return nil, err
+	}
+	if len(policies) == 0 {
+		return result, nil
+	}
+	scope := request.Scope
+	if scope == nil {
+		scope = &bean.PolicyEvaluationScope{}
+	}
+	policyIds := make([]int, 0, len(policies))
+	for _, policy := range policies {
+		policyIds = append(policyIds, policy.Id)
+	}
+	policyIdToScopes, err := impl.policyScopeService.GetScopes(resourceQualifiers.CelPolicy, policyIds)
+	if err != nil {
+		return nil, err
+	}
+	applicablePolicies := make([]*celPolicyRepository.CelPolicy, 0, len(policies))
+	for _, policy := range policies {
+		if policyScope.IsApplicable(policyIdToScopes[policy.Id], scope) {
+			applicablePolicies = append(applicablePolicies, policy)
+		}
+	}
+	if len(applicablePolicies) == 0 {
+		return result, nil
+	}
+	params, err := impl.getCommonParams(scope)
+	if err != nil {
+		return nil, err
+	}
+	params = append(params, request.Params...)
+	if request.ParamsProvider != nil {
+		hookParams, err := request.ParamsProvider()
+		if err != nil {
+			impl.logger.Errorw("error in resolving cel policy params for hook", "hook", request.Hook, "scope", scope, "err", err)
+			return nil, err
+		}
+		params = append(params, hookParams...)
+	}
+	for _, policy := range applicablePolicies {
+		violation := impl.evaluatePolicy(policy, params)
+		if violation == nil {
+			continue
+		}
+		if violation.Action == bean.PolicyActionWarn {
+			result.Warnings = append(result.Warnings, violation)
+		} else {
+			result.Violations = append(result.Violations, violation)
+		}
+	}
+	if len(result.Warnings) > 0 {
+		impl.logger.Infow("cel policy warnings found", "hook", request.Hook, "scope", scope, "warnings", result.Warnings)
+	}
+	if result.IsBlocked() {
+		impl.logger.Infow("blocked by cel policies", "hook", request.Hook, "scope", scope, "violations", result.Violations)
+		return result, &bean.PolicyViolationError{Hook: request.Hook, Violations: result.Violations}
+	}
+	return result, nil
+}
+
+// evaluatePolicy returns the violation if the expression does not evaluate to true.
+// a policy which fails to evaluate is considered violated, as governance rules must not fail open
+func (impl *CelPolicyServiceImpl) evaluatePolicy(policy *celPolicyRepository.CelPolicy, params []cel.ExpressionParam) *bean.PolicyViolation {
+	violation := &bean.PolicyViolation{
+		PolicyId:   policy.Id,
+		PolicyName: policy.Name,
+		Action:     bean.PolicyAction(policy.Action),
+		Message:    policy.Message,
+	}
+	if len(violation.Message) == 0 {
+		violation.Message = bean.DefaultViolationMessage
+	}
+	satisfied, err := impl.celEvaluatorService.EvaluateCELRequest(cel.Request{
+		Expression: policy.Expression,
+		ExpressionMetadata: cel.ExpressionMetadata{
+			Params: params,
+		},
+	})
+	if err != nil {
+		impl.logger.Errorw("error in evaluating cel policy", "policyId", policy.Id, "expression", policy.Expression, "err", err)
+		violation.Message = fmt.Sprintf(bean.EvaluationFailedMessage, err.Error())
+		return violation
+	}
+	if satisfied {
+		return nil
+	}
+	return violation
+}
+
+func (impl *CelPolicyServiceImpl) validatePolicy(policy *bean.CelPolicyDto) error {
+	if len(policy.Action) == 0 {
+		policy.Action = bean.PolicyActionBlock
+	}
+	if policy.Action != bean.PolicyActionBlock && policy.Action != bean.PolicyActionWarn {
+		errMsg := fmt.Sprintf(bean.InvalidActionMessage, policy.Action)
+		return util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+	}
+	// a policy without scopes never applies, so it is rejected instead of being saved and silently skipped
+	if len(policy.Scopes) == 0 {
+		return util.NewApiError(http.StatusBadRequest, bean.ScopeRequiredMessage, bean.ScopeRequiredMessage)
+	}
+	for _, scope := range policy.Scopes {
+		if scope.Selector != bean.GlobalScopeSelector && scope.Id <= 0 {
+			errMsg := fmt.Sprintf(bean.InvalidScopeMessage, scope.Selector)
+			return util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+		}
+	}
+	// a policy without hooks is never evaluated
+	if len(policy.Hooks) == 0 {
+		return util.NewApiError(http.StatusBadRequest, bean.HookRequiredMessage, bean.HookRequiredMessage)
+	}
+	for _, hook := range policy.Hooks {
+		if !hook.IsValid() {
+			errMsg := fmt.Sprintf(bean.InvalidHookMessage, hook)
+			return util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+		}
+		// expression is compiled against the params of every hook it is attached to
+		err := impl.validateExpression(policy.Expression, hook)
+		if err != nil {
+			errMsg := fmt.Sprintf(bean.InvalidExpressionMessage, hook, err.Error())
+			return util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+		}
+	}
+	return nil
+}
+
+func (impl *CelPolicyServiceImpl) validateExpression(expression string, hook bean.EvaluationHook) error {
+	params, err := getDryRunParams(hook, nil)
+	if err != nil {
+		return err
+	}
+	ast, _, err := impl.celEvaluatorService.Validate(cel.Request{
+		Expression: expression,
+		ExpressionMetadata: cel.ExpressionMetadata{
+			Params: params,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if !ast.OutputType().IsExactType(celGo.BoolType) && !ast.OutputType().IsExactType(celGo.DynType) {
+		return fmt.Errorf(bean.NonBooleanExpressionError, ast.OutputType().String())
+	}
+	return nil
+}
+
+func (impl *CelPolicyServiceImpl) getPolicyDbObject(id int) (*celPolicyRepository.CelPolicy, error) {
+	policy, err := impl.celPolicyRepository.FindById(id)
+	if util.IsErrNoRows(err) {
+		return nil, util.NewApiError(http.StatusNotFound, bean.PolicyNotFoundMessage, bean.PolicyNotFoundMessage)
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching cel policy", "policyId", id, "err", err)
+		return nil, err
+	}
+	return policy, nil
+}
+
+// getCommonParams resolves the app, project, env and cluster params of the scope.
+// missing project and cluster ids of the scope are populated from the app and env
+func (impl *CelPolicyServiceImpl) getCommonParams(scope *bean.PolicyEvaluationScope) ([]cel.ExpressionParam, error) {
+	var appName, projectName, envName, clusterName string
+	var isProdEnv bool
+	if scope.AppId > 0 {
+		app, err := impl.appRepository.FindAppAndProjectByAppId(scope.AppId)
+		if err != nil {
+			impl.logger.Errorw("error in fetching app for cel policy evaluation", "appId", scope.AppId, "err", err)
+			return nil, err
+		}
+		appName = app.AppName
+		projectName = app.Team.Name
+		if scope.ProjectId == 0 {
+			scope.ProjectId = app.TeamId
+		}
+	}
+	if scope.EnvId > 0 {
+		env, err := impl.envRepository.FindById(scope.EnvId)
+		if err != nil {
+			impl.logger.Errorw("error in fetching environment for cel policy evaluation", "envId", scope.EnvId, "err", err)
+			return nil, err
+		}
+		envName = env.Name
+		isProdEnv = env.Default
+		if env.Cluster != nil {
+			clusterName = env.Cluster.ClusterName
+		}
+		if scope.ClusterId == 0 {
+			scope.ClusterId = env.ClusterId
+		}
+	}
+	return []cel.ExpressionParam{
+		{ParamName: cel.AppName, Value: appName, Type: cel.ParamTypeString},
+		{ParamName: cel.ProjectName, Value: projectName, Type: cel.ParamTypeString},
+		{ParamName: cel.EnvName, Value: envName, Type: cel.ParamTypeString},
+		{ParamName: cel.ClusterName, Value: clusterName, Type: cel.ParamTypeString},
+		{ParamName: cel.IsProdEnv, Value: isProdEnv, Type: cel.ParamTypeBool},
+	}, nil
+}
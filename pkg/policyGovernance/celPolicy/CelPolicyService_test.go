@@ -0,0 +1,120 @@
+package celPolicy
+
+import (
+	"github.com/devtron-labs/devtron/cel"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/celPolicy/bean"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestCelPolicyServiceImpl_ValidatePolicy(t *testing.T) {
+	logger, err := util.NewSugardLogger()
+	assert.NoError(t, err)
+	impl := &CelPolicyServiceImpl{
+		logger:              logger,
+		celEvaluatorService: cel.NewCELServiceImpl(logger),
+	}
+	newPolicy := func(scopes []*bean.PolicyScopeDto) *bean.CelPolicyDto {
+		return &bean.CelPolicyDto{
+			Name:       "no-critical-cve",
+			Expression: "criticalVulnerabilityCount == 0",
+			Hooks:      []bean.EvaluationHook{bean.CdTriggerHook},
+			Scopes:     scopes,
+		}
+	}
+
+	policy := newPolicy([]*bean.PolicyScopeDto{{Selector: bean.GlobalScopeSelector}})
+	assert.NoError(t, impl.validatePolicy(policy))
+	assert.Equal(t, bean.PolicyActionBlock, policy.Action)
+
+	err = impl.validatePolicy(newPolicy(nil))
+	assert.Error(t, err)
+	assert.Equal(t, bean.ScopeRequiredMessage, err.(*util.ApiError).UserMessage)
+
+	assert.Error(t, impl.validatePolicy(newPolicy([]*bean.PolicyScopeDto{{Selector: bean.ApplicationScopeSelector}})))
+
+	policy = newPolicy([]*bean.PolicyScopeDto{{Selector: bean.GlobalScopeSelector}})
+	policy.Hooks = nil
+	err = impl.validatePolicy(policy)
+	assert.Equal(t, http.StatusBadRequest, err.(*util.ApiError).HttpStatusCode)
+	assert.Equal(t, bean.HookRequiredMessage, err.(*util.ApiError).UserMessage)
+}
+
+func TestGetDryRunParams(t *testing.T) {
+	params, err := getDryRunParams(bean.CdTriggerHook, map[string]interface{}{
+		string(cel.EnvName):                    "prod",
+		string(cel.CriticalVulnerabilityCount): float64(2),
+		string(cel.ImageLabels):                []interface{}{"stable"},
+	})
+	assert.NoError(t, err)
+	values := make(map[cel.ParamName]interface{}, len(params))
+	for _, param := range params {
+		values[param.ParamName] = param.Value
+	}
+	assert.Len(t, params, len(bean.GetParamDeclarations(bean.CdTriggerHook)))
+	assert.Equal(t, "prod", values[cel.EnvName])
+	assert.Equal(t, 2, values[cel.CriticalVulnerabilityCount])
+	assert.Equal(t, []string{"stable"}, values[cel.ImageLabels])
+	assert.Equal(t, 0, values[cel.HighVulnerabilityCount])
+	assert.Equal(t, false, values[cel.IsProdEnv])
+
+	_, err = getDryRunParams(bean.CdTriggerHook, map[string]interface{}{
+		string(cel.CriticalVulnerabilityCount): "two",
+	})
+	assert.Error(t, err)
+}
+
+func TestCelPolicyServiceImpl_DryRun(t *testing.T) {
+	logger, err := util.NewSugardLogger()
+	assert.NoError(t, err)
+	impl := &CelPolicyServiceImpl{
+		logger:              logger,
+		celEvaluatorService: cel.NewCELServiceImpl(logger),
+	}
+	tests := []struct {
+		name       string
+		request    *bean.DryRunRequest
+		wantResult bool
+		wantError  bool
+	}{
+		{
+			name: "satisfied expression",
+			request: &bean.DryRunRequest{
+				Expression: "!isProdEnv || criticalVulnerabilityCount == 0",
+				Hook:       bean.CdTriggerHook,
+				Params:     map[string]interface{}{string(cel.IsProdEnv): true},
+			},
+			wantResult: true,
+		},
+		{
+			name: "violated expression",
+			request: &bean.DryRunRequest{
+				Expression: "'approved' in imageLabels",
+				Hook:       bean.CdTriggerHook,
+				Params:     map[string]interface{}{string(cel.ImageLabels): []interface{}{"stable"}},
+			},
+			wantResult: false,
+		},
+		{
+			name: "unknown param",
+			request: &bean.DryRunRequest{
+				Expression: "cdPipelineName == 'deploy'",
+				Hook:       bean.CiTriggerHook,
+			},
+			wantError: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response, err := impl.DryRun(tt.request)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantResult, response.Result)
+			assert.Equal(t, tt.wantError, len(response.Error) > 0)
+		})
+	}
+
+	_, err = impl.DryRun(&bean.DryRunRequest{Expression: "true", Hook: "UNKNOWN"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,62 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adapter
+
+import (
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/celPolicy/bean"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/celPolicy/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+)
+
+func GetCelPolicyDbObject(dto *bean.CelPolicyDto, auditLog sql.AuditLog) *repository.CelPolicy {
+	hooks := make([]string, 0, len(dto.Hooks))
+	for _, hook := range dto.Hooks {
+		hooks = append(hooks, hook.ToString())
+	}
+	return &repository.CelPolicy{
+		Id:          dto.Id,
+		Name:        dto.Name,
+		Description: dto.Description,
+		Expression:  dto.Expression,
+		Message:     dto.Message,
+		Hooks:       hooks,
+		Action:      string(dto.Action),
+		Enabled:     dto.Enabled,
+		AuditLog:    auditLog,
+	}
+}
+
+func GetCelPolicyDto(policy *repository.CelPolicy, scopes []*bean.PolicyScopeDto) *bean.CelPolicyDto {
+	hooks := make([]bean.EvaluationHook, 0, len(policy.Hooks))
+	for _, hook := range policy.Hooks {
+		hooks = append(hooks, bean.EvaluationHook(hook))
+	}
+	if scopes == nil {
+		scopes = make([]*bean.PolicyScopeDto, 0)
+	}
+	return &bean.CelPolicyDto{
+		Id:          policy.Id,
+		Name:        policy.Name,
+		Description: policy.Description,
+		Expression:  policy.Expression,
+		Message:     policy.Message,
+		Hooks:       hooks,
+		Action:      bean.PolicyAction(policy.Action),
+		Enabled:     policy.Enabled,
+		Scopes:      scopes,
+	}
+}
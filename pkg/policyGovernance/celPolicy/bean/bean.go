@@ -0,0 +1,197 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import (
+	"fmt"
+	"github.com/devtron-labs/devtron/cel"
+	policyScopeBean "github.com/devtron-labs/devtron/pkg/policyGovernance/policyScope/bean"
+	"strings"
+)
+
+type EvaluationHook string
+
+const (
+	CiTriggerHook  EvaluationHook = "CI_TRIGGER"
+	CdTriggerHook  EvaluationHook = "CD_TRIGGER"
+	ConfigSaveHook EvaluationHook = "CONFIG_SAVE"
+)
+
+func (hook EvaluationHook) IsValid() bool {
+	_, ok := hookParamDeclarations[hook]
+	return ok
+}
+
+func (hook EvaluationHook) ToString() string {
+	return string(hook)
+}
+
+type PolicyAction string
+
+const (
+	// PolicyActionBlock blocks the operation when the policy expression does not evaluate to true
+	PolicyActionBlock PolicyAction = "BLOCK"
+	// PolicyActionWarn only reports the violation, the operation is allowed
+	PolicyActionWarn PolicyAction = "WARN"
+)
+
+type ScopeSelector = policyScopeBean.ScopeSelector
+
+const (
+	GlobalScopeSelector      = policyScopeBean.GlobalScopeSelector
+	ApplicationScopeSelector = policyScopeBean.ApplicationScopeSelector
+	EnvironmentScopeSelector = policyScopeBean.EnvironmentScopeSelector
+	ClusterScopeSelector     = policyScopeBean.ClusterScopeSelector
+	ProjectScopeSelector     = policyScopeBean.ProjectScopeSelector
+)
+
+type PolicyScopeDto = policyScopeBean.ScopeDto
+
+type CelPolicyDto struct {
+	Id          int    `json:"id"`
+	Name        string `json:"name" validate:"required,max=100"`
+	Description string `json:"description"`
+	Expression  string `json:"expression" validate:"required"`
+	// Message is shown to the user when the expression does not evaluate to true
+	Message string            `json:"message"`
+	Hooks   []EvaluationHook  `json:"hooks" validate:"required,min=1"`
+	Action  PolicyAction      `json:"action"`
+	Enabled bool              `json:"enabled"`
+	Scopes  []*PolicyScopeDto `json:"scopes" validate:"min=1,dive"`
+	UserId  int32             `json:"-"`
+}
+
+// PolicyEvaluationScope identifies the resource on which the policies are being evaluated
+type PolicyEvaluationScope = policyScopeBean.EvaluationScope
+
+type EvaluationRequest struct {
+	Hook  EvaluationHook
+	Scope *PolicyEvaluationScope
+	// Params are the hook specific params, common params (app, project, env and cluster)
+	// are resolved from the Scope by the policy service
+	Params []cel.ExpressionParam
+	// ParamsProvider resolves hook specific params which are costly to fetch, it is called
+	// only if any policy of the hook is applicable on the Scope
+	ParamsProvider func() ([]cel.ExpressionParam, error)
+}
+
+type PolicyViolation struct {
+	PolicyId   int          `json:"policyId"`
+	PolicyName string       `json:"policyName"`
+	Action     PolicyAction `json:"action"`
+	Message    string       `json:"message"`
+}
+
+type EvaluationResult struct {
+	Violations []*PolicyViolation `json:"violations"`
+	Warnings   []*PolicyViolation `json:"warnings"`
+}
+
+func (result *EvaluationResult) IsBlocked() bool {
+	return result != nil && len(result.Violations) > 0
+}
+
+// PolicyViolationError is returned by the evaluation hooks when one or more blocking policies are violated
+type PolicyViolationError struct {
+	Hook       EvaluationHook
+	Violations []*PolicyViolation
+}
+
+func (e *PolicyViolationError) Error() string {
+	messages := make([]string, 0, len(e.Violations))
+	for _, violation := range e.Violations {
+		messages = append(messages, fmt.Sprintf("%s: %s", violation.PolicyName, violation.Message))
+	}
+	return fmt.Sprintf("blocked by policy, %s", strings.Join(messages, "; "))
+}
+
+type DryRunRequest struct {
+	Expression string                 `json:"expression" validate:"required"`
+	Hook       EvaluationHook         `json:"hook" validate:"required"`
+	Params     map[string]interface{} `json:"params"`
+}
+
+type DryRunResponse struct {
+	Result bool                  `json:"result"`
+	Error  string                `json:"error,omitempty"`
+	Params []cel.ExpressionParam `json:"params"`
+}
+
+type ParamDeclaration struct {
+	Name        cel.ParamName       `json:"name"`
+	Type        cel.ParamValuesType `json:"type"`
+	Description string              `json:"description"`
+}
+
+const (
+	DefaultViolationMessage   = "policy condition not satisfied"
+	EvaluationFailedMessage   = "policy evaluation failed: %s"
+	PolicyNotFoundMessage     = "policy not found"
+	PolicyNameExistsMessage   = "policy with name '%s' already exists"
+	InvalidHookMessage        = "invalid hook '%s'"
+	InvalidActionMessage      = "invalid action '%s'"
+	InvalidScopeMessage       = "scope id is required for selector '%s'"
+	ScopeRequiredMessage      = "at least one scope is required, use GLOBAL selector to apply the policy everywhere"
+	HookRequiredMessage       = "at least one hook is required"
+	InvalidExpressionMessage  = "invalid expression for hook %s: %s"
+	NonBooleanExpressionError = "expression must evaluate to a bool, found %s"
+)
+
+var commonParamDeclarations = []ParamDeclaration{
+	{Name: cel.AppName, Type: cel.ParamTypeString, Description: "name of the application"},
+	{Name: cel.ProjectName, Type: cel.ParamTypeString, Description: "name of the project of the application"},
+	{Name: cel.EnvName, Type: cel.ParamTypeString, Description: "name of the environment, empty for base configurations and builds"},
+	{Name: cel.ClusterName, Type: cel.ParamTypeString, Description: "name of the cluster of the environment"},
+	{Name: cel.IsProdEnv, Type: cel.ParamTypeBool, Description: "true if the environment is marked as production"},
+}
+
+var hookParamDeclarations = map[EvaluationHook][]ParamDeclaration{
+	CiTriggerHook: {
+		{Name: cel.CiPipelineName, Type: cel.ParamTypeString, Description: "name of the build pipeline"},
+		{Name: cel.GitBranch, Type: cel.ParamTypeString, Description: "branch of the first git material being built"},
+		{Name: cel.GitBranches, Type: cel.ParamTypeList, Description: "branches of all the git materials being built"},
+		{Name: cel.TriggeredBy, Type: cel.ParamTypeString, Description: "email of the user triggering the build"},
+	},
+	CdTriggerHook: {
+		{Name: cel.CdPipelineName, Type: cel.ParamTypeString, Description: "name of the deployment pipeline"},
+		{Name: cel.CdPipelineTriggerType, Type: cel.ParamTypeString, Description: "trigger type of the deployment pipeline, AUTOMATIC or MANUAL"},
+		{Name: cel.ContainerRepo, Type: cel.ParamTypeString, Description: "container repository of the artifact"},
+		{Name: cel.ContainerImage, Type: cel.ParamTypeString, Description: "container image of the artifact"},
+		{Name: cel.ContainerImageTag, Type: cel.ParamTypeString, Description: "container image tag of the artifact"},
+		{Name: cel.ImageLabels, Type: cel.ParamTypeList, Description: "labels (release tags) of the artifact"},
+		{Name: cel.CriticalVulnerabilityCount, Type: cel.ParamTypeInteger, Description: "number of critical vulnerabilities found in the artifact"},
+		{Name: cel.HighVulnerabilityCount, Type: cel.ParamTypeInteger, Description: "number of high vulnerabilities found in the artifact"},
+		{Name: cel.MediumVulnerabilityCount, Type: cel.ParamTypeInteger, Description: "number of medium vulnerabilities found in the artifact"},
+		{Name: cel.LowVulnerabilityCount, Type: cel.ParamTypeInteger, Description: "number of low vulnerabilities found in the artifact"},
+		{Name: cel.ChartName, Type: cel.ParamTypeString, Description: "name of the deployment chart"},
+		{Name: cel.ChartVersion, Type: cel.ParamTypeString, Description: "version of the deployment chart"},
+		{Name: cel.TriggeredBy, Type: cel.ParamTypeString, Description: "email of the user triggering the deployment"},
+	},
+	ConfigSaveHook: {
+		{Name: cel.ChartRefId, Type: cel.ParamTypeInteger, Description: "id of the deployment chart reference"},
+		{Name: cel.ChartVersion, Type: cel.ParamTypeString, Description: "version of the deployment chart"},
+		{Name: cel.DeploymentTemplateValues, Type: cel.ParamTypeMapStringToAny, Description: "resolved deployment template values being saved"},
+	},
+}
+
+// GetParamDeclarations returns all the params available to an expression evaluated at the given hook
+func GetParamDeclarations(hook EvaluationHook) []ParamDeclaration {
+	declarations := make([]ParamDeclaration, 0, len(commonParamDeclarations)+len(hookParamDeclarations[hook]))
+	declarations = append(declarations, commonParamDeclarations...)
+	declarations = append(declarations, hookParamDeclarations[hook]...)
+	return declarations
+}
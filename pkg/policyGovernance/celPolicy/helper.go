@@ -0,0 +1,100 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package celPolicy
+
+import (
+	"fmt"
+	"github.com/devtron-labs/devtron/cel"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/celPolicy/bean"
+)
+
+// getDryRunParams builds the typed expression params of the hook from the (json decoded) user given values
+func getDryRunParams(hook bean.EvaluationHook, values map[string]interface{}) ([]cel.ExpressionParam, error) {
+	declarations := bean.GetParamDeclarations(hook)
+	params := make([]cel.ExpressionParam, 0, len(declarations))
+	for _, declaration := range declarations {
+		value, err := getParamValue(declaration, values[string(declaration.Name)])
+		if err != nil {
+			return nil, err
+		}
+		params = append(params, cel.ExpressionParam{
+			ParamName: declaration.Name,
+			Value:     value,
+			Type:      declaration.Type,
+		})
+	}
+	return params, nil
+}
+
+func getParamValue(declaration bean.ParamDeclaration, value interface{}) (interface{}, error) {
+	invalidValueErr := fmt.Errorf("invalid value '%v' for param '%s' of type %s", value, declaration.Name, declaration.Type)
+	switch declaration.Type {
+	case cel.ParamTypeString:
+		if value == nil {
+			return "", nil
+		}
+		if stringValue, ok := value.(string); ok {
+			return stringValue, nil
+		}
+	case cel.ParamTypeBool:
+		if value == nil {
+			return false, nil
+		}
+		if boolValue, ok := value.(bool); ok {
+			return boolValue, nil
+		}
+	case cel.ParamTypeInteger:
+		switch intValue := value.(type) {
+		case nil:
+			return 0, nil
+		case int:
+			return intValue, nil
+		case float64:
+			// json numbers are decoded as float64
+			if intValue == float64(int(intValue)) {
+				return int(intValue), nil
+			}
+		}
+	case cel.ParamTypeList:
+		switch listValue := value.(type) {
+		case nil:
+			return []string{}, nil
+		case []string:
+			return listValue, nil
+		case []interface{}:
+			stringList := make([]string, 0, len(listValue))
+			for _, item := range listValue {
+				stringItem, ok := item.(string)
+				if !ok {
+					return nil, invalidValueErr
+				}
+				stringList = append(stringList, stringItem)
+			}
+			return stringList, nil
+		}
+	case cel.ParamTypeMapStringToAny:
+		if value == nil {
+			return map[string]interface{}{}, nil
+		}
+		if mapValue, ok := value.(map[string]interface{}); ok {
+			return mapValue, nil
+		}
+	case cel.ParamTypeObject:
+		return value, nil
+	}
+	return nil, invalidValueErr
+}
@@ -0,0 +1,107 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+)
+
+type CelPolicy struct {
+	tableName   struct{} `sql:"cel_policy" pg:",discard_unknown_columns"`
+	Id          int      `sql:"id,pk"`
+	Name        string   `sql:"name,notnull"`
+	Description string   `sql:"description"`
+	Expression  string   `sql:"expression,notnull"`
+	Message     string   `sql:"message"`
+	Hooks       []string `sql:"hooks" pg:",array"`
+	Action      string   `sql:"action,notnull"`
+	Enabled     bool     `sql:"enabled,notnull"`
+	Deleted     bool     `sql:"deleted,notnull"`
+	sql.AuditLog
+}
+
+type CelPolicyRepository interface {
+	//transaction util funcs
+	sql.TransactionWrapper
+	Save(policy *CelPolicy, tx *pg.Tx) error
+	Update(policy *CelPolicy, tx *pg.Tx) error
+	FindById(id int) (*CelPolicy, error)
+	FindByName(name string) (*CelPolicy, error)
+	FindAll() ([]*CelPolicy, error)
+	FindEnabledByHook(hook string) ([]*CelPolicy, error)
+}
+
+type CelPolicyRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+	*sql.TransactionUtilImpl
+}
+
+func NewCelPolicyRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger, transactionUtilImpl *sql.TransactionUtilImpl) *CelPolicyRepositoryImpl {
+	return &CelPolicyRepositoryImpl{
+		dbConnection:        dbConnection,
+		logger:              logger,
+		TransactionUtilImpl: transactionUtilImpl,
+	}
+}
+
+func (repo *CelPolicyRepositoryImpl) Save(policy *CelPolicy, tx *pg.Tx) error {
+	return tx.Insert(policy)
+}
+
+func (repo *CelPolicyRepositoryImpl) Update(policy *CelPolicy, tx *pg.Tx) error {
+	return tx.Update(policy)
+}
+
+func (repo *CelPolicyRepositoryImpl) FindById(id int) (*CelPolicy, error) {
+	policy := &CelPolicy{}
+	err := repo.dbConnection.Model(policy).
+		Where("id = ?", id).
+		Where("deleted = ?", false).
+		Select()
+	return policy, err
+}
+
+func (repo *CelPolicyRepositoryImpl) FindByName(name string) (*CelPolicy, error) {
+	policy := &CelPolicy{}
+	err := repo.dbConnection.Model(policy).
+		Where("name = ?", name).
+		Where("deleted = ?", false).
+		Select()
+	return policy, err
+}
+
+func (repo *CelPolicyRepositoryImpl) FindAll() ([]*CelPolicy, error) {
+	var policies []*CelPolicy
+	err := repo.dbConnection.Model(&policies).
+		Where("deleted = ?", false).
+		Order("name ASC").
+		Select()
+	return policies, err
+}
+
+func (repo *CelPolicyRepositoryImpl) FindEnabledByHook(hook string) ([]*CelPolicy, error) {
+	var policies []*CelPolicy
+	err := repo.dbConnection.Model(&policies).
+		Where("deleted = ?", false).
+		Where("enabled = ?", true).
+		Where("? = ANY(hooks)", hook).
+		Select()
+	return policies, err
+}
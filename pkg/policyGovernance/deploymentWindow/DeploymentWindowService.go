@@ -0,0 +1,377 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploymentWindow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	appRepository "github.com/devtron-labs/devtron/internal/sql/repository/app"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/cluster/environment/repository"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/deploymentWindow/adapter"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/deploymentWindow/bean"
+	deploymentWindowRepository "github.com/devtron-labs/devtron/pkg/policyGovernance/deploymentWindow/repository"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/policyScope"
+	policyScopeBean "github.com/devtron-labs/devtron/pkg/policyGovernance/policyScope/bean"
+	"github.com/devtron-labs/devtron/pkg/resourceQualifiers"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"go.opentelemetry.io/otel"
+	"go.uber.org/zap"
+	"net/http"
+	"time"
+)
+
+type DeploymentWindowService interface {
+	CreateWindow(ctx context.Context, window *bean.DeploymentWindowDto) (*bean.DeploymentWindowDto, error)
+	UpdateWindow(ctx context.Context, window *bean.DeploymentWindowDto) (*bean.DeploymentWindowDto, error)
+	DeleteWindow(ctx context.Context, id int, userId int32) error
+	GetWindowById(id int) (*bean.DeploymentWindowDto, error)
+	GetAllWindows() ([]*bean.DeploymentWindowDto, error)
+	// GetDeploymentWindowState evaluates all the enabled windows applicable on the scope at the given time,
+	// missing project and cluster ids of the scope are populated from the app and env
+	GetDeploymentWindowState(ctx context.Context, scope *bean.WindowEvaluationScope, at time.Time) (*bean.DeploymentWindowState, error)
+	// SaveOverrideAudit records a deployment triggered in spite of the blocking windows
+	SaveOverrideAudit(override *bean.DeploymentWindowOverride) error
+	GetOverrideAudits(pipelineId int) ([]*bean.DeploymentWindowOverrideAuditDto, error)
+}
+
+type DeploymentWindowServiceImpl struct {
+	logger                     *zap.SugaredLogger
+	deploymentWindowRepository deploymentWindowRepository.DeploymentWindowRepository
+	overrideAuditRepository    deploymentWindowRepository.DeploymentWindowOverrideAuditRepository
+	policyScopeService         policyScope.PolicyScopeService
+	appRepository              appRepository.AppRepository
+	envRepository              repository.EnvironmentRepository
+}
+
+func NewDeploymentWindowServiceImpl(logger *zap.SugaredLogger,
+	deploymentWindowRepository deploymentWindowRepository.DeploymentWindowRepository,
+	overrideAuditRepository deploymentWindowRepository.DeploymentWindowOverrideAuditRepository,
+	policyScopeService policyScope.PolicyScopeService,
+	appRepository appRepository.AppRepository,
+	envRepository repository.EnvironmentRepository) *DeploymentWindowServiceImpl {
+	return &DeploymentWindowServiceImpl{
+		logger:                     logger,
+		deploymentWindowRepository: deploymentWindowRepository,
+		overrideAuditRepository:    overrideAuditRepository,
+		policyScopeService:         policyScopeService,
+		appRepository:              appRepository,
+		envRepository:              envRepository,
+	}
+}
+
+func (impl *DeploymentWindowServiceImpl) CreateWindow(ctx context.Context, window *bean.DeploymentWindowDto) (*bean.DeploymentWindowDto, error) {
+	_, span := otel.Tracer("orchestrator").Start(ctx, "DeploymentWindowServiceImpl.CreateWindow")
+	defer span.End()
+	err := validateWindow(window)
+	if err != nil {
+		return nil, err
+	}
+	existingWindow, err := impl.deploymentWindowRepository.FindByName(window.Name)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching deployment window by name", "name", window.Name, "err", err)
+		return nil, err
+	}
+	if existingWindow != nil && existingWindow.Id > 0 {
+		errMsg := fmt.Sprintf(bean.WindowNameExistsMessage, window.Name)
+		return nil, util.NewApiError(http.StatusConflict, errMsg, errMsg)
+	}
+	tx, err := impl.deploymentWindowRepository.StartTx()
+	if err != nil {
+		impl.logger.Errorw("error in starting transaction", "err", err)
+		return nil, err
+	}
+	defer tx.Rollback()
+	dbObject := adapter.GetDeploymentWindowDbObject(window, sql.NewDefaultAuditLog(window.UserId))
+	err = impl.deploymentWindowRepository.Save(dbObject, tx)
+	if err != nil {
+		impl.logger.Errorw("error in saving deployment window", "window", window, "err", err)
+		return nil, err
+	}
+	err = impl.policyScopeService.CreateScopes(tx, resourceQualifiers.DeploymentWindow, dbObject.Id, adapter.GetWindowScopes(window.Scopes), window.UserId)
+	if err != nil {
+		impl.logger.Errorw("error in creating scope mappings for deployment window", "windowId", dbObject.Id, "err", err)
+		return nil, err
+	}
+	err = impl.deploymentWindowRepository.CommitTx(tx)
+	if err != nil {
+		impl.logger.Errorw("error in committing transaction", "err", err)
+		return nil, err
+	}
+	window.Id = dbObject.Id
+	return window, nil
+}
+
+func (impl *DeploymentWindowServiceImpl) UpdateWindow(ctx context.Context, window *bean.DeploymentWindowDto) (*bean.DeploymentWindowDto, error) {
+	_, span := otel.Tracer("orchestrator").Start(ctx, "DeploymentWindowServiceImpl.UpdateWindow")
+	defer span.End()
+	err := validateWindow(window)
+	if err != nil {
+		return nil, err
+	}
+	existingWindow, err := impl.getWindowDbObject(window.Id)
+	if err != nil {
+		return nil, err
+	}
+	if existingWindow.Name != window.Name {
+		windowWithSameName, err := impl.deploymentWindowRepository.FindByName(window.Name)
+		if err != nil && !util.IsErrNoRows(err) {
+			impl.logger.Errorw("error in fetching deployment window by name", "name", window.Name, "err", err)
+			return nil, err
+		}
+		if windowWithSameName != nil && windowWithSameName.Id > 0 {
+			errMsg := fmt.Sprintf(bean.WindowNameExistsMessage, window.Name)
+			return nil, util.NewApiError(http.StatusConflict, errMsg, errMsg)
+		}
+	}
+	tx, err := impl.deploymentWindowRepository.StartTx()
+	if err != nil {
+		impl.logger.Errorw("error in starting transaction", "err", err)
+		return nil, err
+	}
+	defer tx.Rollback()
+	auditLog := existingWindow.AuditLog
+	auditLog.UpdateAuditLog(window.UserId)
+	dbObject := adapter.GetDeploymentWindowDbObject(window, auditLog)
+	err = impl.deploymentWindowRepository.Update(dbObject, tx)
+	if err != nil {
+		impl.logger.Errorw("error in updating deployment window", "window", window, "err", err)
+		return nil, err
+	}
+	err = impl.policyScopeService.DeleteScopes(tx, resourceQualifiers.DeploymentWindow, window.Id, window.UserId)
+	if err != nil {
+		return nil, err
+	}
+	err = impl.policyScopeService.CreateScopes(tx, resourceQualifiers.DeploymentWindow, window.Id, adapter.GetWindowScopes(window.Scopes), window.UserId)
+	if err != nil {
+		impl.logger.Errorw("error in creating scope mappings for deployment window", "windowId", window.Id, "err", err)
+		return nil, err
+	}
+	err = impl.deploymentWindowRepository.CommitTx(tx)
+	if err != nil {
+		impl.logger.Errorw("error in committing transaction", "err", err)
+		return nil, err
+	}
+	return window, nil
+}
+
+func (impl *DeploymentWindowServiceImpl) DeleteWindow(ctx context.Context, id int, userId int32) error {
+	_, span := otel.Tracer("orchestrator").Start(ctx, "DeploymentWindowServiceImpl.DeleteWindow")
+	defer span.End()
+	existingWindow, err := impl.getWindowDbObject(id)
+	if err != nil {
+		return err
+	}
+	tx, err := impl.deploymentWindowRepository.StartTx()
+	if err != nil {
+		impl.logger.Errorw("error in starting transaction", "err", err)
+		return err
+	}
+	defer tx.Rollback()
+	existingWindow.Deleted = true
+	existingWindow.UpdateAuditLog(userId)
+	err = impl.deploymentWindowRepository.Update(existingWindow, tx)
+	if err != nil {
+		impl.logger.Errorw("error in deleting deployment window", "windowId", id, "err", err)
+		return err
+	}
+	err = impl.policyScopeService.DeleteScopes(tx, resourceQualifiers.DeploymentWindow, id, userId)
+	if err != nil {
+		return err
+	}
+	return impl.deploymentWindowRepository.CommitTx(tx)
+}
+
+func (impl *DeploymentWindowServiceImpl) GetWindowById(id int) (*bean.DeploymentWindowDto, error) {
+	window, err := impl.getWindowDbObject(id)
+	if err != nil {
+		return nil, err
+	}
+	windowIdToScopes, err := impl.policyScopeService.GetScopes(resourceQualifiers.DeploymentWindow, []int{id})
+	if err != nil {
+		return nil, err
+	}
+	return adapter.GetDeploymentWindowDto(window, windowIdToScopes[id]), nil
+}
+
+func (impl *DeploymentWindowServiceImpl) GetAllWindows() ([]*bean.DeploymentWindowDto, error) {
+	windows, err := impl.deploymentWindowRepository.FindAll()
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching deployment windows", "err", err)
+		return nil, err
+	}
+	result := make([]*bean.DeploymentWindowDto, 0, len(windows))
+	if len(windows) == 0 {
+		return result, nil
+	}
+	windowIdToScopes, err := impl.policyScopeService.GetScopes(resourceQualifiers.DeploymentWindow, getWindowIds(windows))
+	if err != nil {
+		return nil, err
+	}
+	for _, window := range windows {
+		result = append(result, adapter.GetDeploymentWindowDto(window, windowIdToScopes[window.Id]))
+	}
+	return result, nil
+}
+
+func (impl *DeploymentWindowServiceImpl) GetDeploymentWindowState(ctx context.Context, scope *bean.WindowEvaluationScope, at time.Time) (*bean.DeploymentWindowState, error) {
+	_, span := otel.Tracer("orchestrator").Start(ctx, "DeploymentWindowServiceImpl.GetDeploymentWindowState")
+	defer span.End()
+	windows, err := impl.deploymentWindowRepository.FindAllEnabled()
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching enabled deployment windows", "err", err)
+		return nil, err
+	}
+	if len(windows) == 0 {
+		return getDeploymentWindowState(impl.logger, nil, nil, scope, at), nil
+	}
+	err = impl.populateScope(scope)
+	if err != nil {
+		return nil, err
+	}
+	windowIdToScopes, err := impl.policyScopeService.GetScopes(resourceQualifiers.DeploymentWindow, getWindowIds(windows))
+	if err != nil {
+		return nil, err
+	}
+	return getDeploymentWindowState(impl.logger, windows, windowIdToScopes, scope, at), nil
+}
+
+func (impl *DeploymentWindowServiceImpl) SaveOverrideAudit(override *bean.DeploymentWindowOverride) error {
+	windows, err := json.Marshal(override.Windows)
+	if err != nil {
+		impl.logger.Errorw("error in marshalling deployment windows of override", "override", override, "err", err)
+		return err
+	}
+	audit := &deploymentWindowRepository.DeploymentWindowOverrideAudit{
+		PipelineId: override.PipelineId,
+		AppId:      override.AppId,
+		EnvId:      override.EnvId,
+		CdWfrId:    override.WfrId,
+		Reason:     override.Reason,
+		Windows:    string(windows),
+		AuditLog:   sql.NewDefaultAuditLog(override.UserId),
+	}
+	err = impl.overrideAuditRepository.Save(audit)
+	if err != nil {
+		impl.logger.Errorw("error in saving deployment window override audit", "override", override, "err", err)
+		return err
+	}
+	impl.logger.Infow("deployment window overridden", "pipelineId", override.PipelineId, "wfrId", override.WfrId, "userId", override.UserId, "windows", string(windows))
+	return nil
+}
+
+func (impl *DeploymentWindowServiceImpl) GetOverrideAudits(pipelineId int) ([]*bean.DeploymentWindowOverrideAuditDto, error) {
+	audits, err := impl.overrideAuditRepository.FindByPipelineId(pipelineId, bean.OverrideAuditsLimit)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching deployment window override audits", "pipelineId", pipelineId, "err", err)
+		return nil, err
+	}
+	result := make([]*bean.DeploymentWindowOverrideAuditDto, 0, len(audits))
+	for _, audit := range audits {
+		windows := make([]*bean.WindowSummary, 0)
+		if len(audit.Windows) > 0 {
+			err = json.Unmarshal([]byte(audit.Windows), &windows)
+			if err != nil {
+				impl.logger.Errorw("error in unmarshalling deployment windows of override audit", "auditId", audit.Id, "err", err)
+			}
+		}
+		result = append(result, &bean.DeploymentWindowOverrideAuditDto{
+			Id:           audit.Id,
+			PipelineId:   audit.PipelineId,
+			AppId:        audit.AppId,
+			EnvId:        audit.EnvId,
+			WfrId:        audit.CdWfrId,
+			Reason:       audit.Reason,
+			Windows:      windows,
+			OverriddenBy: audit.CreatedBy,
+			OverriddenOn: audit.CreatedOn,
+		})
+	}
+	return result, nil
+}
+
+func (impl *DeploymentWindowServiceImpl) populateScope(scope *bean.WindowEvaluationScope) error {
+	if scope.ProjectId == 0 && scope.AppId > 0 {
+		app, err := impl.appRepository.FindById(scope.AppId)
+		if err != nil {
+			impl.logger.Errorw("error in fetching app for deployment window evaluation", "appId", scope.AppId, "err", err)
+			return err
+		}
+		scope.ProjectId = app.TeamId
+	}
+	if scope.ClusterId == 0 && scope.EnvId > 0 {
+		env, err := impl.envRepository.FindById(scope.EnvId)
+		if err != nil {
+			impl.logger.Errorw("error in fetching environment for deployment window evaluation", "envId", scope.EnvId, "err", err)
+			return err
+		}
+		scope.ClusterId = env.ClusterId
+	}
+	return nil
+}
+
+func (impl *DeploymentWindowServiceImpl) getWindowDbObject(id int) (*deploymentWindowRepository.DeploymentWindow, error) {
+	window, err := impl.deploymentWindowRepository.FindById(id)
+	if util.IsErrNoRows(err) {
+		return nil, util.NewApiError(http.StatusNotFound, bean.WindowNotFoundMessage, bean.WindowNotFoundMessage)
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching deployment window", "windowId", id, "err", err)
+		return nil, err
+	}
+	return window, nil
+}
+
+func validateWindow(window *bean.DeploymentWindowDto) error {
+	for _, scope := range window.Scopes {
+		if scope.Selector == policyScopeBean.ApplicationScopeSelector {
+			errMsg := fmt.Sprintf(bean.UnsupportedScopeMessage, scope.Selector)
+			return util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+		}
+		if scope.Selector != bean.GlobalScopeSelector && scope.Id <= 0 {
+			errMsg := fmt.Sprintf(bean.InvalidScopeMessage, scope.Selector)
+			return util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+		}
+	}
+	if _, err := time.LoadLocation(window.Timezone); err != nil {
+		errMsg := fmt.Sprintf(bean.InvalidTimezoneMessage, window.Timezone)
+		return util.NewApiError(http.StatusBadRequest, errMsg, err.Error())
+	}
+	switch window.ScheduleType {
+	case bean.OneTimeSchedule:
+		if window.StartTime == nil || window.EndTime == nil || !window.EndTime.After(*window.StartTime) {
+			return util.NewApiError(http.StatusBadRequest, bean.InvalidOneTimeWindowMessage, bean.InvalidOneTimeWindowMessage)
+		}
+	case bean.RecurringSchedule:
+		if _, _, err := parseRecurringSchedule(window.Cron, window.Timezone); err != nil {
+			errMsg := fmt.Sprintf(bean.InvalidCronMessage, window.Cron, err.Error())
+			return util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+		}
+		if window.DurationMinutes <= 0 {
+			return util.NewApiError(http.StatusBadRequest, bean.InvalidDurationMessage, bean.InvalidDurationMessage)
+		}
+	}
+	return nil
+}
+
+func getWindowIds(windows []*deploymentWindowRepository.DeploymentWindow) []int {
+	windowIds := make([]int, 0, len(windows))
+	for _, window := range windows {
+		windowIds = append(windowIds, window.Id)
+	}
+	return windowIds
+}
@@ -0,0 +1,35 @@
+package deploymentWindow
+
+import (
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/deploymentWindow/bean"
+	policyScopeBean "github.com/devtron-labs/devtron/pkg/policyGovernance/policyScope/bean"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestValidateWindow(t *testing.T) {
+	newRecurringWindow := func(cron string, scopes ...*bean.WindowScopeDto) *bean.DeploymentWindowDto {
+		return &bean.DeploymentWindowDto{
+			Name:            "weekend maintenance",
+			Type:            bean.MaintenanceWindowType,
+			ScheduleType:    bean.RecurringSchedule,
+			Cron:            cron,
+			DurationMinutes: 60,
+			Timezone:        "UTC",
+			Scopes:          scopes,
+		}
+	}
+	assert.NoError(t, validateWindow(newRecurringWindow("0 22 * * 6")))
+	assert.NoError(t, validateWindow(newRecurringWindow("0 22 * * 6", &bean.WindowScopeDto{Selector: bean.EnvironmentScopeSelector, Id: 2})))
+
+	err := validateWindow(newRecurringWindow("0 22 * *"))
+	assert.Error(t, err)
+	assert.Contains(t, err.(*util.ApiError).UserMessage, "invalid cron expression '0 22 * *'")
+
+	err = validateWindow(newRecurringWindow("0 22 * * 6", &bean.WindowScopeDto{Selector: policyScopeBean.ApplicationScopeSelector, Id: 1}))
+	assert.Error(t, err)
+
+	err = validateWindow(newRecurringWindow("0 22 * * 6", &bean.WindowScopeDto{Selector: bean.ClusterScopeSelector}))
+	assert.Error(t, err)
+}
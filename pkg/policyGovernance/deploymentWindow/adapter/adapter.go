@@ -0,0 +1,80 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adapter
+
+import (
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/deploymentWindow/bean"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/deploymentWindow/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+)
+
+func GetDeploymentWindowDbObject(dto *bean.DeploymentWindowDto, auditLog sql.AuditLog) *repository.DeploymentWindow {
+	window := &repository.DeploymentWindow{
+		Id:           dto.Id,
+		Name:         dto.Name,
+		Description:  dto.Description,
+		WindowType:   string(dto.Type),
+		ScheduleType: string(dto.ScheduleType),
+		Timezone:     dto.Timezone,
+		Enabled:      dto.Enabled,
+		AuditLog:     auditLog,
+	}
+	if dto.ScheduleType == bean.OneTimeSchedule {
+		window.StartTime = dto.StartTime
+		window.EndTime = dto.EndTime
+	} else {
+		window.Cron = dto.Cron
+		window.DurationMinutes = dto.DurationMinutes
+	}
+	return window
+}
+
+func GetDeploymentWindowDto(window *repository.DeploymentWindow, scopes []*bean.WindowScopeDto) *bean.DeploymentWindowDto {
+	if scopes == nil {
+		scopes = make([]*bean.WindowScopeDto, 0)
+	}
+	return &bean.DeploymentWindowDto{
+		Id:              window.Id,
+		Name:            window.Name,
+		Description:     window.Description,
+		Type:            bean.WindowType(window.WindowType),
+		ScheduleType:    bean.ScheduleType(window.ScheduleType),
+		StartTime:       window.StartTime,
+		EndTime:         window.EndTime,
+		Cron:            window.Cron,
+		DurationMinutes: window.DurationMinutes,
+		Timezone:        window.Timezone,
+		Enabled:         window.Enabled,
+		Scopes:          scopes,
+	}
+}
+
+func GetWindowSummary(window *repository.DeploymentWindow) *bean.WindowSummary {
+	return &bean.WindowSummary{
+		Id:   window.Id,
+		Name: window.Name,
+		Type: bean.WindowType(window.WindowType),
+	}
+}
+
+// GetWindowScopes returns the scopes to be persisted for the window, a window without any scope is applicable globally
+func GetWindowScopes(scopes []*bean.WindowScopeDto) []*bean.WindowScopeDto {
+	if len(scopes) == 0 {
+		return []*bean.WindowScopeDto{{Selector: bean.GlobalScopeSelector}}
+	}
+	return scopes
+}
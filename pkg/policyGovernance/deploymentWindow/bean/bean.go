@@ -0,0 +1,159 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import (
+	"fmt"
+	policyScopeBean "github.com/devtron-labs/devtron/pkg/policyGovernance/policyScope/bean"
+	"strings"
+	"time"
+)
+
+type WindowType string
+
+const (
+	// BlackoutWindowType blocks deployments while the window is active (change freeze)
+	BlackoutWindowType WindowType = "BLACKOUT"
+	// MaintenanceWindowType allows deployments only while one of the applicable maintenance windows is active
+	MaintenanceWindowType WindowType = "MAINTENANCE"
+)
+
+type ScheduleType string
+
+const (
+	// OneTimeSchedule is active between StartTime and EndTime
+	OneTimeSchedule ScheduleType = "ONE_TIME"
+	// RecurringSchedule is active for DurationMinutes from every activation of the Cron expression, evaluated in Timezone
+	RecurringSchedule ScheduleType = "RECURRING"
+)
+
+type ScopeSelector = policyScopeBean.ScopeSelector
+
+// windows are not scoped on applications, APPLICATION selector is rejected on save
+const (
+	GlobalScopeSelector      = policyScopeBean.GlobalScopeSelector
+	EnvironmentScopeSelector = policyScopeBean.EnvironmentScopeSelector
+	ClusterScopeSelector     = policyScopeBean.ClusterScopeSelector
+	ProjectScopeSelector     = policyScopeBean.ProjectScopeSelector
+)
+
+type WindowScopeDto = policyScopeBean.ScopeDto
+
+type DeploymentWindowDto struct {
+	Id           int          `json:"id"`
+	Name         string       `json:"name" validate:"required,max=100"`
+	Description  string       `json:"description"`
+	Type         WindowType   `json:"type" validate:"oneof=BLACKOUT MAINTENANCE"`
+	ScheduleType ScheduleType `json:"scheduleType" validate:"oneof=ONE_TIME RECURRING"`
+	// StartTime and EndTime are required for ONE_TIME schedule
+	StartTime *time.Time `json:"startTime,omitempty"`
+	EndTime   *time.Time `json:"endTime,omitempty"`
+	// Cron (standard 5 field expression) and DurationMinutes are required for RECURRING schedule
+	Cron            string            `json:"cron,omitempty"`
+	DurationMinutes int               `json:"durationMinutes,omitempty"`
+	Timezone        string            `json:"timezone"`
+	Enabled         bool              `json:"enabled"`
+	Scopes          []*WindowScopeDto `json:"scopes" validate:"dive"`
+	UserId          int32             `json:"-"`
+}
+
+// WindowEvaluationScope identifies the deployment target on which the windows are evaluated
+type WindowEvaluationScope = policyScopeBean.EvaluationScope
+
+type WindowSummary struct {
+	Id   int        `json:"id"`
+	Name string     `json:"name"`
+	Type WindowType `json:"type"`
+}
+
+type DeploymentWindowState struct {
+	// IsDeploymentAllowed is false if a blackout window is active or no maintenance window is active
+	IsDeploymentAllowed bool             `json:"isDeploymentAllowed"`
+	ActiveBlackouts     []*WindowSummary `json:"activeBlackouts"`
+	// MaintenanceWindows are all the maintenance windows applicable on the scope, deployments are allowed
+	// only while one of them is active
+	MaintenanceWindows []*WindowSummary `json:"maintenanceWindows"`
+	IsInMaintenance    bool             `json:"isInMaintenance"`
+	EvaluatedAt        time.Time        `json:"evaluatedAt"`
+}
+
+// BlockingWindows returns the windows because of which the deployment is not allowed
+func (state *DeploymentWindowState) BlockingWindows() []*WindowSummary {
+	if state.IsDeploymentAllowed {
+		return nil
+	}
+	if len(state.ActiveBlackouts) > 0 {
+		return state.ActiveBlackouts
+	}
+	return state.MaintenanceWindows
+}
+
+func (state *DeploymentWindowState) GetBlockedReason() string {
+	if state.IsDeploymentAllowed {
+		return ""
+	}
+	windows := state.BlockingWindows()
+	names := make([]string, 0, len(windows))
+	for _, window := range windows {
+		names = append(names, window.Name)
+	}
+	if len(state.ActiveBlackouts) > 0 {
+		return fmt.Sprintf(BlackoutActiveMessage, strings.Join(names, ", "))
+	}
+	return fmt.Sprintf(OutsideMaintenanceMessage, strings.Join(names, ", "))
+}
+
+// DeploymentWindowOverride is used to deploy in spite of the blocking windows, requires the override permission
+type DeploymentWindowOverride struct {
+	PipelineId int
+	AppId      int
+	EnvId      int
+	WfrId      int
+	Reason     string
+	UserId     int32
+	Windows    []*WindowSummary
+}
+
+type DeploymentWindowOverrideAuditDto struct {
+	Id           int              `json:"id"`
+	PipelineId   int              `json:"pipelineId"`
+	AppId        int              `json:"appId"`
+	EnvId        int              `json:"envId"`
+	WfrId        int              `json:"wfrId"`
+	Reason       string           `json:"reason"`
+	Windows      []*WindowSummary `json:"windows"`
+	OverriddenBy int32            `json:"overriddenBy"`
+	OverriddenOn time.Time        `json:"overriddenOn"`
+}
+
+const (
+	BlackoutActiveMessage       = "deployments are blocked by active blackout window(s): %s"
+	OutsideMaintenanceMessage   = "deployments are allowed only during maintenance window(s): %s"
+	WindowNotFoundMessage       = "deployment window not found"
+	WindowNameExistsMessage     = "deployment window with name '%s' already exists"
+	InvalidScopeMessage         = "scope id is required for selector '%s'"
+	UnsupportedScopeMessage     = "selector '%s' is not supported for deployment windows"
+	InvalidTimezoneMessage      = "invalid timezone '%s'"
+	InvalidOneTimeWindowMessage = "startTime and endTime are required for ONE_TIME window and endTime must be after startTime"
+	InvalidCronMessage          = "invalid cron expression '%s': %s"
+	InvalidDurationMessage      = "durationMinutes must be greater than 0 for RECURRING window"
+	OverrideReasonRequired      = "reason is required to override deployment window"
+	OverrideForbiddenMessage    = "you do not have permission to override deployment windows"
+)
+
+// OverrideAuditsLimit is the number of latest override audits returned for a pipeline
+const OverrideAuditsLimit = 50
@@ -0,0 +1,97 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploymentWindow
+
+import (
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/deploymentWindow/adapter"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/deploymentWindow/bean"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/deploymentWindow/repository"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/policyScope"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+	"time"
+)
+
+func parseRecurringSchedule(cronExpression, timezone string) (cron.Schedule, *time.Location, error) {
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, nil, err
+	}
+	schedule, err := cron.ParseStandard(cronExpression)
+	if err != nil {
+		return nil, nil, err
+	}
+	return schedule, location, nil
+}
+
+// isWindowActive returns true if the window schedule is active at the given time.
+// one time windows are active in [startTime, endTime), recurring windows in [activation, activation + duration)
+func isWindowActive(window *repository.DeploymentWindow, at time.Time) (bool, error) {
+	switch bean.ScheduleType(window.ScheduleType) {
+	case bean.OneTimeSchedule:
+		if window.StartTime == nil || window.EndTime == nil {
+			return false, nil
+		}
+		return !at.Before(*window.StartTime) && at.Before(*window.EndTime), nil
+	case bean.RecurringSchedule:
+		schedule, location, err := parseRecurringSchedule(window.Cron, window.Timezone)
+		if err != nil {
+			return false, err
+		}
+		duration := time.Duration(window.DurationMinutes) * time.Minute
+		// the window is open if it got activated after (at - duration)
+		nextActivation := schedule.Next(at.In(location).Add(-duration))
+		return !nextActivation.After(at), nil
+	}
+	return false, nil
+}
+
+// getDeploymentWindowState evaluates the applicable windows at the given time.
+// windows with an invalid schedule (e.g. the timezone is no longer available) are skipped and logged,
+// so that a broken window neither blocks all deployments nor opens a freeze
+func getDeploymentWindowState(logger *zap.SugaredLogger, windows []*repository.DeploymentWindow, windowIdToScopes map[int][]*bean.WindowScopeDto,
+	scope *bean.WindowEvaluationScope, at time.Time) *bean.DeploymentWindowState {
+	state := &bean.DeploymentWindowState{
+		ActiveBlackouts:    make([]*bean.WindowSummary, 0),
+		MaintenanceWindows: make([]*bean.WindowSummary, 0),
+		EvaluatedAt:        at,
+	}
+	for _, window := range windows {
+		if !policyScope.IsApplicable(windowIdToScopes[window.Id], scope) {
+			continue
+		}
+		active, err := isWindowActive(window, at)
+		if err != nil {
+			logger.Errorw("skipping deployment window with invalid schedule", "windowId", window.Id, "name", window.Name, "cron", window.Cron, "timezone", window.Timezone, "err", err)
+			continue
+		}
+		switch bean.WindowType(window.WindowType) {
+		case bean.BlackoutWindowType:
+			if active {
+				state.ActiveBlackouts = append(state.ActiveBlackouts, adapter.GetWindowSummary(window))
+			}
+		case bean.MaintenanceWindowType:
+			state.MaintenanceWindows = append(state.MaintenanceWindows, adapter.GetWindowSummary(window))
+			if active {
+				state.IsInMaintenance = true
+			}
+		}
+	}
+	state.IsDeploymentAllowed = len(state.ActiveBlackouts) == 0 &&
+		(len(state.MaintenanceWindows) == 0 || state.IsInMaintenance)
+	return state
+}
@@ -0,0 +1,105 @@
+package deploymentWindow
+
+import (
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/deploymentWindow/bean"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/deploymentWindow/repository"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestIsWindowActive(t *testing.T) {
+	start := time.Date(2025, 12, 24, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 12, 27, 0, 0, 0, 0, time.UTC)
+	oneTime := &repository.DeploymentWindow{ScheduleType: string(bean.OneTimeSchedule), StartTime: &start, EndTime: &end}
+	// every saturday 22:00 for 4 hours in Asia/Kolkata (UTC+05:30)
+	recurring := &repository.DeploymentWindow{
+		ScheduleType:    string(bean.RecurringSchedule),
+		Cron:            "0 22 * * 6",
+		DurationMinutes: 240,
+		Timezone:        "Asia/Kolkata",
+	}
+	tests := []struct {
+		name   string
+		window *repository.DeploymentWindow
+		at     time.Time
+		want   bool
+	}{
+		{name: "one time, before start", window: oneTime, at: start.Add(-time.Second), want: false},
+		{name: "one time, at start", window: oneTime, at: start, want: true},
+		{name: "one time, at end", window: oneTime, at: end, want: false},
+		// saturday 2025-12-06 22:00 IST is 16:30 UTC
+		{name: "recurring, at activation", window: recurring, at: time.Date(2025, 12, 6, 16, 30, 0, 0, time.UTC), want: true},
+		{name: "recurring, after midnight in window timezone", window: recurring, at: time.Date(2025, 12, 6, 19, 0, 0, 0, time.UTC), want: true},
+		{name: "recurring, at window end", window: recurring, at: time.Date(2025, 12, 6, 20, 30, 0, 0, time.UTC), want: false},
+		{name: "recurring, same time on other day", window: recurring, at: time.Date(2025, 12, 5, 16, 30, 0, 0, time.UTC), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := isWindowActive(tt.window, tt.at)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+	_, err := isWindowActive(&repository.DeploymentWindow{ScheduleType: string(bean.RecurringSchedule), Cron: "invalid", Timezone: "UTC"}, start)
+	assert.Error(t, err)
+}
+
+func TestGetDeploymentWindowState(t *testing.T) {
+	now := time.Date(2025, 12, 25, 10, 0, 0, 0, time.UTC)
+	past, future := now.Add(-time.Hour), now.Add(time.Hour)
+	activeFreeze := &repository.DeploymentWindow{Id: 1, Name: "holiday freeze", WindowType: string(bean.BlackoutWindowType),
+		ScheduleType: string(bean.OneTimeSchedule), StartTime: &past, EndTime: &future}
+	inactiveMaintenance := &repository.DeploymentWindow{Id: 2, Name: "weekend maintenance", WindowType: string(bean.MaintenanceWindowType),
+		ScheduleType: string(bean.OneTimeSchedule), StartTime: &future, EndTime: &future}
+	activeMaintenance := &repository.DeploymentWindow{Id: 3, Name: "daily maintenance", WindowType: string(bean.MaintenanceWindowType),
+		ScheduleType: string(bean.RecurringSchedule), Cron: "0 9 * * *", DurationMinutes: 120, Timezone: "UTC"}
+	scope := &bean.WindowEvaluationScope{AppId: 1, EnvId: 2, ClusterId: 3, ProjectId: 4}
+	logger, err := util.NewSugardLogger()
+	assert.NoError(t, err)
+
+	t.Run("blackout scoped on environment", func(t *testing.T) {
+		state := getDeploymentWindowState(logger, []*repository.DeploymentWindow{activeFreeze},
+			map[int][]*bean.WindowScopeDto{1: {{Selector: bean.EnvironmentScopeSelector, Id: 2}}}, scope, now)
+		assert.False(t, state.IsDeploymentAllowed)
+		assert.Equal(t, "deployments are blocked by active blackout window(s): holiday freeze", state.GetBlockedReason())
+	})
+	t.Run("blackout scoped on other project", func(t *testing.T) {
+		state := getDeploymentWindowState(logger, []*repository.DeploymentWindow{activeFreeze},
+			map[int][]*bean.WindowScopeDto{1: {{Selector: bean.ProjectScopeSelector, Id: 5}}}, scope, now)
+		assert.True(t, state.IsDeploymentAllowed)
+	})
+	t.Run("outside maintenance window", func(t *testing.T) {
+		state := getDeploymentWindowState(logger, []*repository.DeploymentWindow{inactiveMaintenance},
+			map[int][]*bean.WindowScopeDto{2: {{Selector: bean.GlobalScopeSelector}}}, scope, now)
+		assert.False(t, state.IsDeploymentAllowed)
+		assert.Len(t, state.BlockingWindows(), 1)
+	})
+	t.Run("inside one of the maintenance windows", func(t *testing.T) {
+		state := getDeploymentWindowState(logger, []*repository.DeploymentWindow{inactiveMaintenance, activeMaintenance},
+			map[int][]*bean.WindowScopeDto{
+				2: {{Selector: bean.GlobalScopeSelector}},
+				3: {{Selector: bean.ClusterScopeSelector, Id: 3}},
+			}, scope, now)
+		assert.True(t, state.IsDeploymentAllowed)
+		assert.True(t, state.IsInMaintenance)
+	})
+	t.Run("blackout wins over maintenance", func(t *testing.T) {
+		state := getDeploymentWindowState(logger, []*repository.DeploymentWindow{activeFreeze, activeMaintenance},
+			map[int][]*bean.WindowScopeDto{
+				1: {{Selector: bean.GlobalScopeSelector}},
+				3: {{Selector: bean.GlobalScopeSelector}},
+			}, scope, now)
+		assert.False(t, state.IsDeploymentAllowed)
+		assert.Equal(t, []*bean.WindowSummary{{Id: 1, Name: "holiday freeze", Type: bean.BlackoutWindowType}}, state.BlockingWindows())
+	})
+	t.Run("maintenance window with invalid schedule is skipped", func(t *testing.T) {
+		invalidMaintenance := &repository.DeploymentWindow{Id: 4, Name: "broken maintenance", WindowType: string(bean.MaintenanceWindowType),
+			ScheduleType: string(bean.RecurringSchedule), Cron: "invalid", DurationMinutes: 60, Timezone: "UTC"}
+		state := getDeploymentWindowState(logger, []*repository.DeploymentWindow{invalidMaintenance},
+			map[int][]*bean.WindowScopeDto{4: {{Selector: bean.GlobalScopeSelector}}}, scope, now)
+		assert.True(t, state.IsDeploymentAllowed)
+		assert.Empty(t, state.MaintenanceWindows)
+	})
+}
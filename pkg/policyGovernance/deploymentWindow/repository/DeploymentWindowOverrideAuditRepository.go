@@ -0,0 +1,68 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+)
+
+// DeploymentWindowOverrideAudit records every deployment triggered in spite of the blocking deployment windows
+type DeploymentWindowOverrideAudit struct {
+	tableName  struct{} `sql:"deployment_window_override_audit" pg:",discard_unknown_columns"`
+	Id         int      `sql:"id,pk"`
+	PipelineId int      `sql:"pipeline_id,notnull"`
+	AppId      int      `sql:"app_id,notnull"`
+	EnvId      int      `sql:"env_id,notnull"`
+	CdWfrId    int      `sql:"cd_workflow_runner_id"`
+	Reason     string   `sql:"reason,notnull"`
+	// Windows is the json of the blocking windows at the time of override
+	Windows string `sql:"windows"`
+	sql.AuditLog
+}
+
+type DeploymentWindowOverrideAuditRepository interface {
+	Save(audit *DeploymentWindowOverrideAudit) error
+	FindByPipelineId(pipelineId int, limit int) ([]*DeploymentWindowOverrideAudit, error)
+}
+
+type DeploymentWindowOverrideAuditRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewDeploymentWindowOverrideAuditRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *DeploymentWindowOverrideAuditRepositoryImpl {
+	return &DeploymentWindowOverrideAuditRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (repo *DeploymentWindowOverrideAuditRepositoryImpl) Save(audit *DeploymentWindowOverrideAudit) error {
+	return repo.dbConnection.Insert(audit)
+}
+
+func (repo *DeploymentWindowOverrideAuditRepositoryImpl) FindByPipelineId(pipelineId int, limit int) ([]*DeploymentWindowOverrideAudit, error) {
+	var audits []*DeploymentWindowOverrideAudit
+	err := repo.dbConnection.Model(&audits).
+		Where("pipeline_id = ?", pipelineId).
+		Order("id DESC").
+		Limit(limit).
+		Select()
+	return audits, err
+}
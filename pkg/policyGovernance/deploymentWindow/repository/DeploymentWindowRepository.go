@@ -0,0 +1,110 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+type DeploymentWindow struct {
+	tableName       struct{}   `sql:"deployment_window" pg:",discard_unknown_columns"`
+	Id              int        `sql:"id,pk"`
+	Name            string     `sql:"name,notnull"`
+	Description     string     `sql:"description"`
+	WindowType      string     `sql:"window_type,notnull"`
+	ScheduleType    string     `sql:"schedule_type,notnull"`
+	StartTime       *time.Time `sql:"start_time"`
+	EndTime         *time.Time `sql:"end_time"`
+	Cron            string     `sql:"cron"`
+	DurationMinutes int        `sql:"duration_minutes"`
+	Timezone        string     `sql:"timezone,notnull"`
+	Enabled         bool       `sql:"enabled,notnull"`
+	Deleted         bool       `sql:"deleted,notnull"`
+	sql.AuditLog
+}
+
+type DeploymentWindowRepository interface {
+	//transaction util funcs
+	sql.TransactionWrapper
+	Save(window *DeploymentWindow, tx *pg.Tx) error
+	Update(window *DeploymentWindow, tx *pg.Tx) error
+	FindById(id int) (*DeploymentWindow, error)
+	FindByName(name string) (*DeploymentWindow, error)
+	FindAll() ([]*DeploymentWindow, error)
+	FindAllEnabled() ([]*DeploymentWindow, error)
+}
+
+type DeploymentWindowRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+	*sql.TransactionUtilImpl
+}
+
+func NewDeploymentWindowRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger, transactionUtilImpl *sql.TransactionUtilImpl) *DeploymentWindowRepositoryImpl {
+	return &DeploymentWindowRepositoryImpl{
+		dbConnection:        dbConnection,
+		logger:              logger,
+		TransactionUtilImpl: transactionUtilImpl,
+	}
+}
+
+func (repo *DeploymentWindowRepositoryImpl) Save(window *DeploymentWindow, tx *pg.Tx) error {
+	return tx.Insert(window)
+}
+
+func (repo *DeploymentWindowRepositoryImpl) Update(window *DeploymentWindow, tx *pg.Tx) error {
+	return tx.Update(window)
+}
+
+func (repo *DeploymentWindowRepositoryImpl) FindById(id int) (*DeploymentWindow, error) {
+	window := &DeploymentWindow{}
+	err := repo.dbConnection.Model(window).
+		Where("id = ?", id).
+		Where("deleted = ?", false).
+		Select()
+	return window, err
+}
+
+func (repo *DeploymentWindowRepositoryImpl) FindByName(name string) (*DeploymentWindow, error) {
+	window := &DeploymentWindow{}
+	err := repo.dbConnection.Model(window).
+		Where("name = ?", name).
+		Where("deleted = ?", false).
+		Select()
+	return window, err
+}
+
+func (repo *DeploymentWindowRepositoryImpl) FindAll() ([]*DeploymentWindow, error) {
+	var windows []*DeploymentWindow
+	err := repo.dbConnection.Model(&windows).
+		Where("deleted = ?", false).
+		Order("name ASC").
+		Select()
+	return windows, err
+}
+
+func (repo *DeploymentWindowRepositoryImpl) FindAllEnabled() ([]*DeploymentWindow, error) {
+	var windows []*DeploymentWindow
+	err := repo.dbConnection.Model(&windows).
+		Where("deleted = ?", false).
+		Where("enabled = ?", true).
+		Select()
+	return windows, err
+}
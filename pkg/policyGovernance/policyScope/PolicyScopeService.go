@@ -0,0 +1,90 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package policyScope
+
+import (
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/policyScope/adapter"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/policyScope/bean"
+	"github.com/devtron-labs/devtron/pkg/resourceQualifiers"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+)
+
+// PolicyScopeService persists the scopes of the policies (cel policies, deployment windows) as resource qualifier mappings
+type PolicyScopeService interface {
+	// GetScopes returns the scopes of the given resources by resource id
+	GetScopes(resourceType resourceQualifiers.ResourceType, resourceIds []int) (map[int][]*bean.ScopeDto, error)
+	CreateScopes(tx *pg.Tx, resourceType resourceQualifiers.ResourceType, resourceId int, scopes []*bean.ScopeDto, userId int32) error
+	DeleteScopes(tx *pg.Tx, resourceType resourceQualifiers.ResourceType, resourceId int, userId int32) error
+}
+
+type PolicyScopeServiceImpl struct {
+	logger                  *zap.SugaredLogger
+	qualifierMappingService resourceQualifiers.QualifierMappingService
+}
+
+func NewPolicyScopeServiceImpl(logger *zap.SugaredLogger,
+	qualifierMappingService resourceQualifiers.QualifierMappingService) *PolicyScopeServiceImpl {
+	return &PolicyScopeServiceImpl{
+		logger:                  logger,
+		qualifierMappingService: qualifierMappingService,
+	}
+}
+
+func (impl *PolicyScopeServiceImpl) GetScopes(resourceType resourceQualifiers.ResourceType, resourceIds []int) (map[int][]*bean.ScopeDto, error) {
+	mappings, err := impl.qualifierMappingService.GetQualifierMappings(resourceType, nil, resourceIds)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching scope mappings", "resourceType", resourceType, "resourceIds", resourceIds, "err", err)
+		return nil, err
+	}
+	resourceIdToScopes := make(map[int][]*bean.ScopeDto, len(resourceIds))
+	for _, mapping := range mappings {
+		resourceIdToScopes[mapping.ResourceId] = append(resourceIdToScopes[mapping.ResourceId], adapter.GetScopeDto(mapping))
+	}
+	return resourceIdToScopes, nil
+}
+
+func (impl *PolicyScopeServiceImpl) CreateScopes(tx *pg.Tx, resourceType resourceQualifiers.ResourceType, resourceId int, scopes []*bean.ScopeDto, userId int32) error {
+	_, err := impl.qualifierMappingService.CreateMappingsForSelections(tx, userId, adapter.GetResourceMappingSelections(resourceType, resourceId, scopes))
+	if err != nil {
+		impl.logger.Errorw("error in creating scope mappings", "resourceType", resourceType, "resourceId", resourceId, "err", err)
+		return err
+	}
+	return nil
+}
+
+func (impl *PolicyScopeServiceImpl) DeleteScopes(tx *pg.Tx, resourceType resourceQualifiers.ResourceType, resourceId int, userId int32) error {
+	mappings, err := impl.qualifierMappingService.GetQualifierMappings(resourceType, nil, []int{resourceId})
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching scope mappings", "resourceType", resourceType, "resourceId", resourceId, "err", err)
+		return err
+	}
+	if len(mappings) == 0 {
+		return nil
+	}
+	mappingIds := make([]int, 0, len(mappings))
+	for _, mapping := range mappings {
+		mappingIds = append(mappingIds, mapping.Id)
+	}
+	err = impl.qualifierMappingService.DeleteAllByIds(mappingIds, userId, tx)
+	if err != nil {
+		impl.logger.Errorw("error in deleting scope mappings", "resourceType", resourceType, "resourceId", resourceId, "err", err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adapter
+
+import (
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/policyScope/bean"
+	"github.com/devtron-labs/devtron/pkg/resourceQualifiers"
+)
+
+func GetResourceMappingSelections(resourceType resourceQualifiers.ResourceType, resourceId int, scopes []*bean.ScopeDto) []*resourceQualifiers.ResourceMappingSelection {
+	selections := make([]*resourceQualifiers.ResourceMappingSelection, 0, len(scopes))
+	for _, scope := range scopes {
+		selection := &resourceQualifiers.ResourceMappingSelection{
+			ResourceType:        resourceType,
+			ResourceId:          resourceId,
+			SelectionIdentifier: &resourceQualifiers.SelectionIdentifier{},
+		}
+		switch scope.Selector {
+		case bean.ApplicationScopeSelector:
+			selection.QualifierSelector = resourceQualifiers.ApplicationSelector
+			selection.SelectionIdentifier.AppId = scope.Id
+		case bean.EnvironmentScopeSelector:
+			selection.QualifierSelector = resourceQualifiers.EnvironmentSelector
+			selection.SelectionIdentifier.EnvId = scope.Id
+		case bean.ClusterScopeSelector:
+			selection.QualifierSelector = resourceQualifiers.ClusterSelector
+			selection.SelectionIdentifier.ClusterId = scope.Id
+		case bean.ProjectScopeSelector:
+			selection.QualifierSelector = resourceQualifiers.ProjectSelector
+			selection.SelectionIdentifier.ProjectId = scope.Id
+		default:
+			selection.QualifierSelector = resourceQualifiers.GlobalSelector
+		}
+		selections = append(selections, selection)
+	}
+	return selections
+}
+
+func GetScopeDto(mapping *resourceQualifiers.QualifierMapping) *bean.ScopeDto {
+	scope := &bean.ScopeDto{
+		Id: mapping.IdentifierValueInt,
+	}
+	switch resourceQualifiers.Qualifier(mapping.QualifierId) {
+	case resourceQualifiers.APP_QUALIFIER:
+		scope.Selector = bean.ApplicationScopeSelector
+	case resourceQualifiers.ENV_QUALIFIER:
+		scope.Selector = bean.EnvironmentScopeSelector
+	case resourceQualifiers.CLUSTER_QUALIFIER:
+		scope.Selector = bean.ClusterScopeSelector
+	case resourceQualifiers.PROJECT_QUALIFIER:
+		scope.Selector = bean.ProjectScopeSelector
+	default:
+		scope.Selector = bean.GlobalScopeSelector
+		scope.Id = 0
+	}
+	return scope
+}
@@ -0,0 +1,42 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+type ScopeSelector string
+
+const (
+	GlobalScopeSelector      ScopeSelector = "GLOBAL"
+	ApplicationScopeSelector ScopeSelector = "APPLICATION"
+	EnvironmentScopeSelector ScopeSelector = "ENVIRONMENT"
+	ClusterScopeSelector     ScopeSelector = "CLUSTER"
+	ProjectScopeSelector     ScopeSelector = "PROJECT"
+)
+
+// ScopeDto selects the resources a policy (cel policy, deployment window) is applicable on
+type ScopeDto struct {
+	Selector ScopeSelector `json:"selector" validate:"oneof=GLOBAL APPLICATION ENVIRONMENT CLUSTER PROJECT"`
+	// Id is the identifier of the selected app/env/cluster/project, not required for GLOBAL selector
+	Id int `json:"id"`
+}
+
+// EvaluationScope is the resource a policy is evaluated for, zero ids are not matched by the respective selectors
+type EvaluationScope struct {
+	AppId     int `json:"appId"`
+	EnvId     int `json:"envId"`
+	ClusterId int `json:"clusterId"`
+	ProjectId int `json:"projectId"`
+}
@@ -0,0 +1,41 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package policyScope
+
+import (
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/policyScope/bean"
+	"slices"
+)
+
+// IsApplicable returns true if any of the policy scopes selects the evaluation scope
+func IsApplicable(policyScopes []*bean.ScopeDto, scope *bean.EvaluationScope) bool {
+	return slices.ContainsFunc(policyScopes, func(policyScope *bean.ScopeDto) bool {
+		switch policyScope.Selector {
+		case bean.GlobalScopeSelector:
+			return true
+		case bean.ApplicationScopeSelector:
+			return scope.AppId > 0 && policyScope.Id == scope.AppId
+		case bean.EnvironmentScopeSelector:
+			return scope.EnvId > 0 && policyScope.Id == scope.EnvId
+		case bean.ClusterScopeSelector:
+			return scope.ClusterId > 0 && policyScope.Id == scope.ClusterId
+		case bean.ProjectScopeSelector:
+			return scope.ProjectId > 0 && policyScope.Id == scope.ProjectId
+		}
+		return false
+	})
+}
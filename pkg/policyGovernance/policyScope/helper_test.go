@@ -0,0 +1,39 @@
+package policyScope
+
+import (
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/policyScope/bean"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestIsApplicable(t *testing.T) {
+	scope := &bean.EvaluationScope{AppId: 1, EnvId: 2, ClusterId: 3, ProjectId: 4}
+	tests := []struct {
+		name   string
+		scopes []*bean.ScopeDto
+		want   bool
+	}{
+		{name: "global scope", scopes: []*bean.ScopeDto{{Selector: bean.GlobalScopeSelector}}, want: true},
+		{name: "matching app", scopes: []*bean.ScopeDto{{Selector: bean.ApplicationScopeSelector, Id: 1}}, want: true},
+		{name: "matching env", scopes: []*bean.ScopeDto{{Selector: bean.EnvironmentScopeSelector, Id: 2}}, want: true},
+		{name: "matching cluster", scopes: []*bean.ScopeDto{{Selector: bean.ClusterScopeSelector, Id: 3}}, want: true},
+		{name: "matching project", scopes: []*bean.ScopeDto{{Selector: bean.ProjectScopeSelector, Id: 4}}, want: true},
+		{name: "different app", scopes: []*bean.ScopeDto{{Selector: bean.ApplicationScopeSelector, Id: 5}}, want: false},
+		{
+			name: "any matching scope",
+			scopes: []*bean.ScopeDto{
+				{Selector: bean.ApplicationScopeSelector, Id: 5},
+				{Selector: bean.EnvironmentScopeSelector, Id: 2},
+			},
+			want: true,
+		},
+		{name: "no scopes", scopes: nil, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsApplicable(tt.scopes, scope))
+		})
+	}
+	// build without environment must not match environment scoped policies
+	assert.False(t, IsApplicable([]*bean.ScopeDto{{Selector: bean.EnvironmentScopeSelector}}, &bean.EvaluationScope{AppId: 1}))
+}
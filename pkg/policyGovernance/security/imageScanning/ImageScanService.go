@@ -53,6 +53,8 @@ type ImageScanService interface {
 	FetchMinScanResultByAppIdAndEnvId(request *bean3.ImageScanRequest) (*bean3.ImageScanExecutionDetail, error)
 	VulnerabilityExposure(request *repository3.VulnerabilityRequest) (*repository3.VulnerabilityExposureListingResponse, error)
 	GetArtifactVulnerabilityStatus(ctx context.Context, request *bean2.VulnerabilityCheckRequest) (bool, error)
+	// GetArtifactBlockedCveList returns the vulnerabilities of the artifact blocked by the cve policy applicable on the cd pipeline
+	GetArtifactBlockedCveList(ctx context.Context, request *bean2.VulnerabilityCheckRequest) ([]*repository3.CveStore, error)
 	IsImageScanExecutionCompleted(image, imageDigest string) (bool, error)
 	FetchVulnerabilitySummary(ctx context.Context, request *bean3.VulnerabilitySummaryRequest, ids []int) (*bean3.VulnerabilitySummary, error)
 	FetchVulnerabilityListing(ctx context.Context, request *bean3.VulnerabilityListingRequest, ids []int) (*bean3.VulnerabilityListingResponse, error)
@@ -800,39 +802,44 @@ func (impl *ImageScanServiceImpl) CalculateSeverityCountInfo(vulnerabilities []*
 }
 
 func (impl *ImageScanServiceImpl) GetArtifactVulnerabilityStatus(ctx context.Context, request *bean2.VulnerabilityCheckRequest) (bool, error) {
-	isVulnerable := false
-	if len(request.ImageDigest) > 0 {
-		var cveStores []*repository3.CveStore
-		_, span := otel.Tracer("orchestrator").Start(ctx, "scanResultRepository.FindByImageDigest")
-		imageScanResult, err := impl.scanResultRepository.FindByImageDigest(request.ImageDigest)
-		span.End()
-		if err != nil && err != pg.ErrNoRows {
-			impl.Logger.Errorw("error fetching image digest", "digest", request.ImageDigest, "err", err)
-			return false, err
-		}
-		for _, item := range imageScanResult {
-			cveStores = append(cveStores, &item.CveStore)
-		}
-		_, span = otel.Tracer("orchestrator").Start(ctx, "cvePolicyRepository.GetBlockedCVEList")
-		if request.CdPipeline.Environment.ClusterId == 0 {
-			envDetails, err := impl.envService.GetDetailsById(request.CdPipeline.EnvironmentId)
-			if err != nil {
-				impl.Logger.Errorw("error fetching cluster details by env, GetArtifactVulnerabilityStatus", "envId", request.CdPipeline.EnvironmentId, "err", err)
-				return false, err
-			}
-			request.CdPipeline.Environment = *envDetails
-		}
-		blockCveList, err := impl.cvePolicyRepository.GetBlockedCVEList(cveStores, request.CdPipeline.Environment.ClusterId, request.CdPipeline.EnvironmentId, request.CdPipeline.AppId, false)
-		span.End()
+	blockCveList, err := impl.GetArtifactBlockedCveList(ctx, request)
+	if err != nil {
+		return false, err
+	}
+	return len(blockCveList) > 0, nil
+}
+
+func (impl *ImageScanServiceImpl) GetArtifactBlockedCveList(ctx context.Context, request *bean2.VulnerabilityCheckRequest) ([]*repository3.CveStore, error) {
+	if len(request.ImageDigest) == 0 {
+		return nil, nil
+	}
+	var cveStores []*repository3.CveStore
+	_, span := otel.Tracer("orchestrator").Start(ctx, "scanResultRepository.FindByImageDigest")
+	imageScanResult, err := impl.scanResultRepository.FindByImageDigest(request.ImageDigest)
+	span.End()
+	if err != nil && err != pg.ErrNoRows {
+		impl.Logger.Errorw("error fetching image digest", "digest", request.ImageDigest, "err", err)
+		return nil, err
+	}
+	for _, item := range imageScanResult {
+		cveStores = append(cveStores, &item.CveStore)
+	}
+	_, span = otel.Tracer("orchestrator").Start(ctx, "cvePolicyRepository.GetBlockedCVEList")
+	defer span.End()
+	if request.CdPipeline.Environment.ClusterId == 0 {
+		envDetails, err := impl.envService.GetDetailsById(request.CdPipeline.EnvironmentId)
 		if err != nil {
-			impl.Logger.Errorw("error encountered in GetArtifactVulnerabilityStatus", "clusterId", request.CdPipeline.Environment.ClusterId, "envId", request.CdPipeline.EnvironmentId, "appId", request.CdPipeline.AppId, "err", err)
-			return false, err
-		}
-		if len(blockCveList) > 0 {
-			isVulnerable = true
+			impl.Logger.Errorw("error fetching cluster details by env, GetArtifactBlockedCveList", "envId", request.CdPipeline.EnvironmentId, "err", err)
+			return nil, err
 		}
+		request.CdPipeline.Environment = *envDetails
+	}
+	blockCveList, err := impl.cvePolicyRepository.GetBlockedCVEList(cveStores, request.CdPipeline.Environment.ClusterId, request.CdPipeline.EnvironmentId, request.CdPipeline.AppId, false)
+	if err != nil {
+		impl.Logger.Errorw("error encountered in GetArtifactBlockedCveList", "clusterId", request.CdPipeline.Environment.ClusterId, "envId", request.CdPipeline.EnvironmentId, "appId", request.CdPipeline.AppId, "err", err)
+		return nil, err
 	}
-	return isVulnerable, nil
+	return blockCveList, nil
 }
 
 func (impl ImageScanServiceImpl) updateCount(severity securityBean.Severity, criticalCount int, highCount int, moderateCount int, lowCount int, unkownCount int) (int, int, int, int, int) {
@@ -84,6 +84,14 @@ type VulnerabilityRawData struct {
 	FixedVersion   string    `sql:"fixed_version"`
 }
 
+// VulnerabilityCountData is the number of vulnerabilities of a severity in the image deployed on an app and environment
+type VulnerabilityCountData struct {
+	AppName  string `sql:"app_name"`
+	EnvName  string `sql:"env_name"`
+	Severity int    `sql:"severity"`
+	Count    int    `sql:"count"`
+}
+
 type ImageScanResultRepository interface {
 	Save(model *ImageScanExecutionResult) error
 	FindAll() ([]*ImageScanExecutionResult, error)
@@ -102,6 +110,8 @@ type ImageScanResultRepository interface {
 
 	// Vulnerability Listing
 	GetVulnerabilityRawData(cveName string, severities, envIds, clusterIds, appIds, deployInfoIds []int) ([]*VulnerabilityRawData, error)
+	// GetVulnerabilityCountByAppEnvAndSeverity counts the vulnerabilities of the latest deployed image of every app and environment
+	GetVulnerabilityCountByAppEnvAndSeverity() ([]*VulnerabilityCountData, error)
 }
 
 type ImageScanResultRepositoryImpl struct {
@@ -463,3 +473,52 @@ func (impl ImageScanResultRepositoryImpl) GetVulnerabilityRawData(cveName string
 
 	return results, nil
 }
+
+func (impl ImageScanResultRepositoryImpl) GetVulnerabilityCountByAppEnvAndSeverity() ([]*VulnerabilityCountData, error) {
+	var results []*VulnerabilityCountData
+	query := `
+		WITH LatestDeployments AS (
+			SELECT DISTINCT ON (p.app_id, p.environment_id)
+				p.app_id,
+				a.app_name,
+				p.environment_id,
+				env.environment_name as env_name,
+				cia.image
+			FROM cd_workflow_runner cwr
+			INNER JOIN cd_workflow cw ON cw.id = cwr.cd_workflow_id
+			INNER JOIN pipeline p ON p.id = cw.pipeline_id
+			INNER JOIN app a ON a.id = p.app_id
+			INNER JOIN environment env ON env.id = p.environment_id
+			INNER JOIN ci_artifact cia ON cia.id = cw.ci_artifact_id
+			WHERE cwr.workflow_type = 'DEPLOY'
+			AND p.deleted = false
+			AND a.active = true
+			AND env.active = true
+			ORDER BY p.app_id, p.environment_id, cwr.id DESC
+		)
+		SELECT
+			ld.app_name,
+			ld.env_name,
+			COALESCE(cs.standard_severity, cs.severity) as severity,
+			COUNT(*) as count
+		FROM LatestDeployments ld
+		INNER JOIN image_scan_deploy_info isdi
+			ON isdi.scan_object_meta_id = ld.app_id
+			AND isdi.env_id = ld.environment_id
+			AND isdi.object_type = 'app'
+			AND isdi.image_scan_execution_history_id[1] != -1
+		INNER JOIN image_scan_execution_history iseh
+			ON iseh.id = isdi.image_scan_execution_history_id[1]
+			AND iseh.image = ld.image
+		INNER JOIN image_scan_execution_result iser
+			ON iser.image_scan_execution_history_id = iseh.id
+		INNER JOIN cve_store cs ON cs.name = iser.cve_store_name
+		GROUP BY ld.app_name, ld.env_name, COALESCE(cs.standard_severity, cs.severity)
+	`
+	_, err := impl.dbConnection.Query(&results, query)
+	if err != nil {
+		impl.logger.Errorw("error in getting vulnerability count by app, env and severity", "err", err)
+		return nil, err
+	}
+	return results, nil
+}
@@ -1,6 +1,9 @@
 package policyGovernance
 
 import (
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/celPolicy"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/deploymentWindow"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/policyScope"
 	"github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageScanning"
 	"github.com/devtron-labs/devtron/pkg/policyGovernance/security/scanTool"
 	"github.com/google/wire"
@@ -9,4 +12,7 @@ import (
 var PolicyGovernanceWireSet = wire.NewSet(
 	imageScanning.ImageScanningWireSet,
 	scanTool.ScanToolWireSet,
+	policyScope.PolicyScopeWireSet,
+	celPolicy.CelPolicyWireSet,
+	deploymentWindow.DeploymentWindowWireSet,
 )
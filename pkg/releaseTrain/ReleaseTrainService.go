@@ -0,0 +1,1014 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package releaseTrain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/caarlos0/env"
+	"github.com/devtron-labs/common-lib/utils/k8s/health"
+	bean2 "github.com/devtron-labs/devtron/api/bean"
+	client "github.com/devtron-labs/devtron/client/events"
+	repository3 "github.com/devtron-labs/devtron/internal/sql/repository"
+	"github.com/devtron-labs/devtron/internal/sql/repository/app"
+	"github.com/devtron-labs/devtron/internal/sql/repository/appStatus"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig/bean/workflow/cdWorkflow"
+	"github.com/devtron-labs/devtron/internal/util"
+	repository2 "github.com/devtron-labs/devtron/pkg/cluster/environment/repository"
+	"github.com/devtron-labs/devtron/pkg/eventProcessor/out"
+	eventBean "github.com/devtron-labs/devtron/pkg/eventProcessor/out/bean"
+	"github.com/devtron-labs/devtron/pkg/releaseTrain/adapter"
+	"github.com/devtron-labs/devtron/pkg/releaseTrain/bean"
+	"github.com/devtron-labs/devtron/pkg/releaseTrain/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	cron2 "github.com/devtron-labs/devtron/util/cron"
+	util2 "github.com/devtron-labs/devtron/util/event"
+	"github.com/robfig/cron/v3"
+	"go.opentelemetry.io/otel"
+	"go.uber.org/zap"
+	"net/http"
+	"time"
+)
+
+type ReleaseTrainConfig struct {
+	ReleaseTrainCron string `env:"RELEASE_TRAIN_CRON" envDefault:"* * * * *" description:"Cron schedule for progressing the running release trains through their stages"`
+}
+
+type ReleaseTrainService interface {
+	CreateReleaseTrain(ctx context.Context, train *bean.ReleaseTrainDto) (*bean.ReleaseTrainDto, error)
+	UpdateReleaseTrain(ctx context.Context, train *bean.ReleaseTrainDto) (*bean.ReleaseTrainDto, error)
+	DeleteReleaseTrain(id int, userId int32) error
+	GetReleaseTrain(id int) (*bean.ReleaseTrainDto, error)
+	GetAllReleaseTrains() ([]*bean.ReleaseTrainDto, error)
+
+	// StartRun starts releasing the given artifacts through the stages of the train, only one run of a train can be in progress
+	StartRun(ctx context.Context, request *bean.ReleaseTrainRunRequest) (*bean.ReleaseTrainRunDto, error)
+	GetRun(runId int) (*bean.ReleaseTrainRunDto, error)
+	GetRuns(releaseTrainId int, offset, limit int) ([]*bean.ReleaseTrainRunDto, error)
+	ApproveStage(runId, runStageId int, userId int32) (*bean.ReleaseTrainRunDto, error)
+	// ResumeRun retries the failed stage of a halted run, the apps already deployed on the stage are not deployed again
+	ResumeRun(runId int, userId int32) (*bean.ReleaseTrainRunDto, error)
+	// AbortRun stops the run from progressing further, deployments already triggered are not stopped
+	AbortRun(runId int, userId int32) error
+	// ProcessRunningRuns progresses all the running runs, called periodically
+	ProcessRunningRuns()
+}
+
+type ReleaseTrainServiceImpl struct {
+	logger                      *zap.SugaredLogger
+	releaseTrainRepository      repository.ReleaseTrainRepository
+	releaseTrainRunRepository   repository.ReleaseTrainRunRepository
+	appRepository               app.AppRepository
+	environmentRepository       repository2.EnvironmentRepository
+	pipelineRepository          pipelineConfig.PipelineRepository
+	ciArtifactRepository        repository3.CiArtifactRepository
+	cdWorkflowRepository        pipelineConfig.CdWorkflowRepository
+	appStatusRepository         appStatus.AppStatusRepository
+	workflowEventPublishService out.WorkflowEventPublishService
+	eventFactory                client.EventFactory
+	eventClient                 client.EventClient
+	cron                        *cron.Cron
+}
+
+func NewReleaseTrainServiceImpl(logger *zap.SugaredLogger,
+	releaseTrainRepository repository.ReleaseTrainRepository,
+	releaseTrainRunRepository repository.ReleaseTrainRunRepository,
+	appRepository app.AppRepository,
+	environmentRepository repository2.EnvironmentRepository,
+	pipelineRepository pipelineConfig.PipelineRepository,
+	ciArtifactRepository repository3.CiArtifactRepository,
+	cdWorkflowRepository pipelineConfig.CdWorkflowRepository,
+	appStatusRepository appStatus.AppStatusRepository,
+	workflowEventPublishService out.WorkflowEventPublishService,
+	eventFactory client.EventFactory,
+	eventClient client.EventClient,
+	cronLogger *cron2.CronLoggerImpl,
+) *ReleaseTrainServiceImpl {
+	impl := &ReleaseTrainServiceImpl{
+		logger:                      logger,
+		releaseTrainRepository:      releaseTrainRepository,
+		releaseTrainRunRepository:   releaseTrainRunRepository,
+		appRepository:               appRepository,
+		environmentRepository:       environmentRepository,
+		pipelineRepository:          pipelineRepository,
+		ciArtifactRepository:        ciArtifactRepository,
+		cdWorkflowRepository:        cdWorkflowRepository,
+		appStatusRepository:         appStatusRepository,
+		workflowEventPublishService: workflowEventPublishService,
+		eventFactory:                eventFactory,
+		eventClient:                 eventClient,
+	}
+	cfg := &ReleaseTrainConfig{}
+	if err := env.Parse(cfg); err != nil {
+		logger.Errorw("error in parsing release train config", "err", err)
+		return impl
+	}
+	impl.cron = cron.New(cron.WithChain(cron.Recover(cronLogger)))
+	impl.cron.Start()
+	_, err := impl.cron.AddFunc(cfg.ReleaseTrainCron, impl.ProcessRunningRuns)
+	if err != nil {
+		logger.Errorw("error in starting release train cron", "cron", cfg.ReleaseTrainCron, "err", err)
+	}
+	return impl
+}
+
+var unfinishedRunStatuses = []string{bean.ReleaseTrainRunRunning.String(), bean.ReleaseTrainRunHalted.String()}
+
+var gatePendingStageStatuses = []string{
+	bean.ReleaseTrainStagePending.String(),
+	bean.ReleaseTrainStageWaiting.String(),
+	bean.ReleaseTrainStageAwaitingApproval.String(),
+}
+
+func (impl *ReleaseTrainServiceImpl) CreateReleaseTrain(ctx context.Context, train *bean.ReleaseTrainDto) (*bean.ReleaseTrainDto, error) {
+	_, span := otel.Tracer("orchestrator").Start(ctx, "ReleaseTrainServiceImpl.CreateReleaseTrain")
+	defer span.End()
+	err := impl.validateReleaseTrain(train)
+	if err != nil {
+		return nil, err
+	}
+	existingTrain, err := impl.releaseTrainRepository.FindByName(train.Name)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching release train by name", "name", train.Name, "err", err)
+		return nil, err
+	}
+	if existingTrain != nil && existingTrain.Id > 0 {
+		errMsg := fmt.Sprintf(bean.ReleaseTrainNameExistsMessage, train.Name)
+		return nil, util.NewApiError(http.StatusConflict, errMsg, errMsg)
+	}
+	tx, err := impl.releaseTrainRepository.StartTx()
+	if err != nil {
+		impl.logger.Errorw("error in starting transaction", "err", err)
+		return nil, err
+	}
+	defer tx.Rollback()
+	auditLog := sql.NewDefaultAuditLog(train.UserId)
+	dbObject := adapter.GetReleaseTrainDbObject(train, auditLog)
+	dbObject.Id = 0
+	err = impl.releaseTrainRepository.Save(dbObject, tx)
+	if err != nil {
+		impl.logger.Errorw("error in saving release train", "train", train, "err", err)
+		return nil, err
+	}
+	err = impl.releaseTrainRepository.SaveStages(adapter.GetReleaseTrainStageDbObjects(dbObject.Id, train.Stages, auditLog), tx)
+	if err != nil {
+		impl.logger.Errorw("error in saving release train stages", "releaseTrainId", dbObject.Id, "err", err)
+		return nil, err
+	}
+	err = impl.releaseTrainRepository.CommitTx(tx)
+	if err != nil {
+		impl.logger.Errorw("error in committing transaction", "err", err)
+		return nil, err
+	}
+	return impl.GetReleaseTrain(dbObject.Id)
+}
+
+func (impl *ReleaseTrainServiceImpl) UpdateReleaseTrain(ctx context.Context, train *bean.ReleaseTrainDto) (*bean.ReleaseTrainDto, error) {
+	_, span := otel.Tracer("orchestrator").Start(ctx, "ReleaseTrainServiceImpl.UpdateReleaseTrain")
+	defer span.End()
+	err := impl.validateReleaseTrain(train)
+	if err != nil {
+		return nil, err
+	}
+	existingTrain, err := impl.getReleaseTrainDbObject(train.Id)
+	if err != nil {
+		return nil, err
+	}
+	if existingTrain.Name != train.Name {
+		trainWithSameName, err := impl.releaseTrainRepository.FindByName(train.Name)
+		if err != nil && !util.IsErrNoRows(err) {
+			impl.logger.Errorw("error in fetching release train by name", "name", train.Name, "err", err)
+			return nil, err
+		}
+		if trainWithSameName != nil && trainWithSameName.Id > 0 {
+			errMsg := fmt.Sprintf(bean.ReleaseTrainNameExistsMessage, train.Name)
+			return nil, util.NewApiError(http.StatusConflict, errMsg, errMsg)
+		}
+	}
+	tx, err := impl.releaseTrainRepository.StartTx()
+	if err != nil {
+		impl.logger.Errorw("error in starting transaction", "err", err)
+		return nil, err
+	}
+	defer tx.Rollback()
+	auditLog := sql.NewDefaultAuditLog(train.UserId)
+	auditLog.CreatedOn = existingTrain.CreatedOn
+	auditLog.CreatedBy = existingTrain.CreatedBy
+	err = impl.releaseTrainRepository.Update(adapter.GetReleaseTrainDbObject(train, auditLog), tx)
+	if err != nil {
+		impl.logger.Errorw("error in updating release train", "train", train, "err", err)
+		return nil, err
+	}
+	// stages are replaced, the runs in progress keep their own copy of the stages
+	err = impl.releaseTrainRepository.DeactivateStages(train.Id, train.UserId, tx)
+	if err != nil {
+		impl.logger.Errorw("error in deactivating release train stages", "releaseTrainId", train.Id, "err", err)
+		return nil, err
+	}
+	err = impl.releaseTrainRepository.SaveStages(adapter.GetReleaseTrainStageDbObjects(train.Id, train.Stages, sql.NewDefaultAuditLog(train.UserId)), tx)
+	if err != nil {
+		impl.logger.Errorw("error in saving release train stages", "releaseTrainId", train.Id, "err", err)
+		return nil, err
+	}
+	err = impl.releaseTrainRepository.CommitTx(tx)
+	if err != nil {
+		impl.logger.Errorw("error in committing transaction", "err", err)
+		return nil, err
+	}
+	return impl.GetReleaseTrain(train.Id)
+}
+
+func (impl *ReleaseTrainServiceImpl) DeleteReleaseTrain(id int, userId int32) error {
+	train, err := impl.getReleaseTrainDbObject(id)
+	if err != nil {
+		return err
+	}
+	_, err = impl.releaseTrainRunRepository.FindUnfinishedRunByReleaseTrainId(id, unfinishedRunStatuses)
+	if err == nil {
+		errMsg := "release train has a run in progress, abort the run before deleting the train"
+		return util.NewApiError(http.StatusConflict, errMsg, errMsg)
+	} else if !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching unfinished run of release train", "releaseTrainId", id, "err", err)
+		return err
+	}
+	tx, err := impl.releaseTrainRepository.StartTx()
+	if err != nil {
+		impl.logger.Errorw("error in starting transaction", "err", err)
+		return err
+	}
+	defer tx.Rollback()
+	train.Active = false
+	train.UpdatedOn = time.Now()
+	train.UpdatedBy = userId
+	err = impl.releaseTrainRepository.Update(train, tx)
+	if err != nil {
+		impl.logger.Errorw("error in deleting release train", "id", id, "err", err)
+		return err
+	}
+	err = impl.releaseTrainRepository.DeactivateStages(id, userId, tx)
+	if err != nil {
+		impl.logger.Errorw("error in deactivating release train stages", "releaseTrainId", id, "err", err)
+		return err
+	}
+	return impl.releaseTrainRepository.CommitTx(tx)
+}
+
+func (impl *ReleaseTrainServiceImpl) GetReleaseTrain(id int) (*bean.ReleaseTrainDto, error) {
+	train, err := impl.getReleaseTrainDbObject(id)
+	if err != nil {
+		return nil, err
+	}
+	stages, err := impl.releaseTrainRepository.FindStagesByReleaseTrainId(id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching release train stages", "releaseTrainId", id, "err", err)
+		return nil, err
+	}
+	envNames, err := impl.getEnvNames(stages)
+	if err != nil {
+		return nil, err
+	}
+	return adapter.GetReleaseTrainDto(train, stages, envNames), nil
+}
+
+func (impl *ReleaseTrainServiceImpl) GetAllReleaseTrains() ([]*bean.ReleaseTrainDto, error) {
+	trains, err := impl.releaseTrainRepository.FindAll()
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching release trains", "err", err)
+		return nil, err
+	}
+	trainDtos := make([]*bean.ReleaseTrainDto, 0, len(trains))
+	for _, train := range trains {
+		stages, err := impl.releaseTrainRepository.FindStagesByReleaseTrainId(train.Id)
+		if err != nil {
+			impl.logger.Errorw("error in fetching release train stages", "releaseTrainId", train.Id, "err", err)
+			return nil, err
+		}
+		trainDtos = append(trainDtos, adapter.GetReleaseTrainDto(train, stages, nil))
+	}
+	return trainDtos, nil
+}
+
+func (impl *ReleaseTrainServiceImpl) getReleaseTrainDbObject(id int) (*repository.ReleaseTrain, error) {
+	train, err := impl.releaseTrainRepository.FindById(id)
+	if util.IsErrNoRows(err) {
+		return nil, util.NewApiError(http.StatusNotFound, bean.ReleaseTrainNotFoundMessage, bean.ReleaseTrainNotFoundMessage)
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching release train", "id", id, "err", err)
+		return nil, err
+	}
+	return train, nil
+}
+
+func (impl *ReleaseTrainServiceImpl) getEnvNames(stages []*repository.ReleaseTrainStage) (map[int]string, error) {
+	envIds := make([]int, 0, len(stages))
+	for _, stage := range stages {
+		envIds = append(envIds, stage.EnvironmentId)
+	}
+	if len(envIds) == 0 {
+		return map[int]string{}, nil
+	}
+	envNames, err := impl.environmentRepository.FindNamesByIds(envIds)
+	if err != nil {
+		impl.logger.Errorw("error in fetching environment names", "envIds", envIds, "err", err)
+		return nil, err
+	}
+	return envNames, nil
+}
+
+// validateReleaseTrain validates the train and ensures that every app of the train has a cd pipeline on the environment of every stage
+func (impl *ReleaseTrainServiceImpl) validateReleaseTrain(train *bean.ReleaseTrainDto) error {
+	err := validateReleaseTrain(train)
+	if err != nil {
+		return err
+	}
+	pipelines, err := impl.pipelineRepository.FindActiveByAppIds(train.AppIds)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching cd pipelines of apps", "appIds", train.AppIds, "err", err)
+		return err
+	}
+	pipelineExists := make(map[string]bool, len(pipelines))
+	for _, pipeline := range pipelines {
+		pipelineExists[getAppEnvKey(pipeline.AppId, pipeline.EnvironmentId)] = true
+	}
+	for _, stage := range train.Stages {
+		for _, appId := range train.AppIds {
+			if !pipelineExists[getAppEnvKey(appId, stage.EnvironmentId)] {
+				errMsg := fmt.Sprintf("app %d does not have a cd pipeline on the environment %d of stage %s", appId, stage.EnvironmentId, stage.Name)
+				return util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+			}
+		}
+	}
+	return nil
+}
+
+func getAppEnvKey(appId, envId int) string {
+	return fmt.Sprintf("%d-%d", appId, envId)
+}
+
+func (impl *ReleaseTrainServiceImpl) StartRun(ctx context.Context, request *bean.ReleaseTrainRunRequest) (*bean.ReleaseTrainRunDto, error) {
+	_, span := otel.Tracer("orchestrator").Start(ctx, "ReleaseTrainServiceImpl.StartRun")
+	defer span.End()
+	train, err := impl.getReleaseTrainDbObject(request.ReleaseTrainId)
+	if err != nil {
+		return nil, err
+	}
+	err = validateRunArtifacts(train.AppIds, request.Artifacts)
+	if err != nil {
+		return nil, err
+	}
+	err = impl.validateArtifactsExist(request.Artifacts)
+	if err != nil {
+		return nil, err
+	}
+	_, err = impl.releaseTrainRunRepository.FindUnfinishedRunByReleaseTrainId(train.Id, unfinishedRunStatuses)
+	if err == nil {
+		errMsg := "release train already has a run in progress"
+		return nil, util.NewApiError(http.StatusConflict, errMsg, errMsg)
+	} else if !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching unfinished run of release train", "releaseTrainId", train.Id, "err", err)
+		return nil, err
+	}
+	stages, err := impl.releaseTrainRepository.FindStagesByReleaseTrainId(train.Id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching release train stages", "releaseTrainId", train.Id, "err", err)
+		return nil, err
+	}
+	artifacts, err := json.Marshal(request.Artifacts)
+	if err != nil {
+		impl.logger.Errorw("error in marshaling release train artifacts", "err", err)
+		return nil, err
+	}
+
+	tx, err := impl.releaseTrainRunRepository.StartTx()
+	if err != nil {
+		impl.logger.Errorw("error in starting transaction", "err", err)
+		return nil, err
+	}
+	defer tx.Rollback()
+	auditLog := sql.NewDefaultAuditLog(request.UserId)
+	run := &repository.ReleaseTrainRun{
+		ReleaseTrainId: train.Id,
+		Status:         bean.ReleaseTrainRunRunning.String(),
+		Artifacts:      string(artifacts),
+		StartedOn:      time.Now(),
+		AuditLog:       auditLog,
+	}
+	err = impl.releaseTrainRunRepository.SaveRun(run, tx)
+	if err != nil {
+		impl.logger.Errorw("error in saving release train run", "releaseTrainId", train.Id, "err", err)
+		return nil, err
+	}
+	err = impl.releaseTrainRunRepository.SaveRunStages(adapter.GetReleaseTrainRunStageDbObjects(run.Id, stages, auditLog), tx)
+	if err != nil {
+		impl.logger.Errorw("error in saving release train run stages", "runId", run.Id, "err", err)
+		return nil, err
+	}
+	err = impl.releaseTrainRunRepository.CommitTx(tx)
+	if err != nil {
+		impl.logger.Errorw("error in committing transaction", "err", err)
+		return nil, err
+	}
+	impl.logger.Infow("release train run started", "releaseTrainId", train.Id, "runId", run.Id, "triggeredBy", request.UserId)
+	impl.processRun(run)
+	return impl.GetRun(run.Id)
+}
+
+func (impl *ReleaseTrainServiceImpl) validateArtifactsExist(artifacts []*bean.ReleaseTrainArtifact) error {
+	artifactIds := make([]int, 0, len(artifacts))
+	for _, artifact := range artifacts {
+		artifactIds = append(artifactIds, artifact.CiArtifactId)
+	}
+	ciArtifacts, err := impl.ciArtifactRepository.GetByIds(artifactIds)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching artifacts", "artifactIds", artifactIds, "err", err)
+		return err
+	}
+	existingArtifactIds := make(map[int]bool, len(ciArtifacts))
+	for _, ciArtifact := range ciArtifacts {
+		existingArtifactIds[ciArtifact.Id] = true
+	}
+	for _, artifact := range artifacts {
+		if !existingArtifactIds[artifact.CiArtifactId] {
+			errMsg := fmt.Sprintf("artifact %d of app %d not found", artifact.CiArtifactId, artifact.AppId)
+			return util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+		}
+	}
+	return nil
+}
+
+func (impl *ReleaseTrainServiceImpl) GetRun(runId int) (*bean.ReleaseTrainRunDto, error) {
+	run, err := impl.getRunDbObject(runId)
+	if err != nil {
+		return nil, err
+	}
+	stages, err := impl.releaseTrainRunRepository.FindRunStagesByRunId(runId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching release train run stages", "runId", runId, "err", err)
+		return nil, err
+	}
+	deployments, err := impl.releaseTrainRunRepository.FindDeploymentsByRunStageIds(getRunStageIds(stages))
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching release train run deployments", "runId", runId, "err", err)
+		return nil, err
+	}
+	var releaseTrainName string
+	if train, err := impl.releaseTrainRepository.FindById(run.ReleaseTrainId); err == nil {
+		releaseTrainName = train.Name
+	}
+	return adapter.GetReleaseTrainRunDto(run, releaseTrainName, stages, deployments), nil
+}
+
+func (impl *ReleaseTrainServiceImpl) GetRuns(releaseTrainId int, offset, limit int) ([]*bean.ReleaseTrainRunDto, error) {
+	train, err := impl.getReleaseTrainDbObject(releaseTrainId)
+	if err != nil {
+		return nil, err
+	}
+	runs, err := impl.releaseTrainRunRepository.FindRunsByReleaseTrainId(releaseTrainId, offset, limit)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching release train runs", "releaseTrainId", releaseTrainId, "err", err)
+		return nil, err
+	}
+	runDtos := make([]*bean.ReleaseTrainRunDto, 0, len(runs))
+	for _, run := range runs {
+		stages, err := impl.releaseTrainRunRepository.FindRunStagesByRunId(run.Id)
+		if err != nil {
+			impl.logger.Errorw("error in fetching release train run stages", "runId", run.Id, "err", err)
+			return nil, err
+		}
+		runDtos = append(runDtos, adapter.GetReleaseTrainRunDto(run, train.Name, stages, nil))
+	}
+	return runDtos, nil
+}
+
+func (impl *ReleaseTrainServiceImpl) getRunDbObject(runId int) (*repository.ReleaseTrainRun, error) {
+	run, err := impl.releaseTrainRunRepository.FindRunById(runId)
+	if util.IsErrNoRows(err) {
+		return nil, util.NewApiError(http.StatusNotFound, bean.ReleaseTrainRunNotFoundMessage, bean.ReleaseTrainRunNotFoundMessage)
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching release train run", "runId", runId, "err", err)
+		return nil, err
+	}
+	return run, nil
+}
+
+func getRunStageIds(stages []*repository.ReleaseTrainRunStage) []int {
+	runStageIds := make([]int, 0, len(stages))
+	for _, stage := range stages {
+		runStageIds = append(runStageIds, stage.Id)
+	}
+	return runStageIds
+}
+
+func (impl *ReleaseTrainServiceImpl) ApproveStage(runId, runStageId int, userId int32) (*bean.ReleaseTrainRunDto, error) {
+	run, err := impl.getRunDbObject(runId)
+	if err != nil {
+		return nil, err
+	}
+	stage, err := impl.releaseTrainRunRepository.FindRunStageById(runStageId)
+	if util.IsErrNoRows(err) || (err == nil && stage.RunId != runId) {
+		errMsg := "stage not found in the release train run"
+		return nil, util.NewApiError(http.StatusNotFound, errMsg, errMsg)
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching release train run stage", "runStageId", runStageId, "err", err)
+		return nil, err
+	}
+	if run.Status != bean.ReleaseTrainRunRunning.String() && run.Status != bean.ReleaseTrainRunHalted.String() {
+		errMsg := fmt.Sprintf("stage of a %s run can not be approved", run.Status)
+		return nil, util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+	}
+	if !stage.RequireApproval || !bean.ReleaseTrainStageStatus(stage.Status).IsGatePending() {
+		errMsg := "stage is not waiting for approval"
+		return nil, util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+	}
+	if userId == run.CreatedBy {
+		return nil, util.NewApiError(http.StatusForbidden, bean.ReleaseTrainSelfApprovalMessage, "self approval of release train run stage")
+	}
+	if stage.ApprovedOn == nil {
+		now := time.Now()
+		stage.ApprovedBy = userId
+		stage.ApprovedOn = &now
+		stage.UpdatedBy = userId
+		stage.UpdatedOn = now
+		err = impl.releaseTrainRunRepository.UpdateRunStage(stage)
+		if err != nil {
+			impl.logger.Errorw("error in approving release train run stage", "runStageId", runStageId, "err", err)
+			return nil, err
+		}
+		impl.logger.Infow("release train run stage approved", "runId", runId, "runStageId", runStageId, "approvedBy", userId)
+	}
+	if run.Status == bean.ReleaseTrainRunRunning.String() {
+		impl.processRun(run)
+	}
+	return impl.GetRun(runId)
+}
+
+func (impl *ReleaseTrainServiceImpl) ResumeRun(runId int, userId int32) (*bean.ReleaseTrainRunDto, error) {
+	run, err := impl.getRunDbObject(runId)
+	if err != nil {
+		return nil, err
+	}
+	stages, err := impl.releaseTrainRunRepository.FindRunStagesByRunId(runId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching release train run stages", "runId", runId, "err", err)
+		return nil, err
+	}
+	resumed, err := impl.releaseTrainRunRepository.UpdateRunStatus(runId, []string{bean.ReleaseTrainRunHalted.String()}, bean.ReleaseTrainRunRunning.String(), "", nil, userId)
+	if err != nil {
+		return nil, err
+	}
+	if !resumed {
+		errMsg := "only halted release train runs can be resumed"
+		return nil, util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+	}
+	now := time.Now()
+	for _, stage := range stages {
+		if stage.Status != bean.ReleaseTrainStageFailed.String() {
+			continue
+		}
+		// the gate of the failed stage is evaluated again, the health timeout being counted from now
+		stage.Status = bean.ReleaseTrainStagePending.String()
+		stage.Message = ""
+		stage.GateStartedOn = &now
+		stage.FinishedOn = nil
+		stage.UpdatedBy = userId
+		stage.UpdatedOn = now
+		err = impl.releaseTrainRunRepository.UpdateRunStage(stage)
+		if err != nil {
+			impl.logger.Errorw("error in resetting failed stage of release train run", "runStageId", stage.Id, "err", err)
+			return nil, err
+		}
+	}
+	impl.logger.Infow("release train run resumed", "runId", runId, "resumedBy", userId)
+	run.Status = bean.ReleaseTrainRunRunning.String()
+	impl.processRun(run)
+	return impl.GetRun(runId)
+}
+
+func (impl *ReleaseTrainServiceImpl) AbortRun(runId int, userId int32) error {
+	_, err := impl.getRunDbObject(runId)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	aborted, err := impl.releaseTrainRunRepository.UpdateRunStatus(runId, unfinishedRunStatuses, bean.ReleaseTrainRunAborted.String(), "aborted by user", &now, userId)
+	if err != nil {
+		return err
+	}
+	if !aborted {
+		errMsg := "only running or halted release train runs can be aborted"
+		return util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+	}
+	impl.logger.Infow("release train run aborted", "runId", runId, "abortedBy", userId)
+	return nil
+}
+
+func (impl *ReleaseTrainServiceImpl) ProcessRunningRuns() {
+	runs, err := impl.releaseTrainRunRepository.FindRunsByStatus(bean.ReleaseTrainRunRunning.String())
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching running release train runs", "err", err)
+		return
+	}
+	for _, run := range runs {
+		impl.processRun(run)
+	}
+}
+
+// processRun progresses the run through its stages as far as possible: the stages are processed in order and
+// the next stage is processed in the same call if the current one succeeds
+func (impl *ReleaseTrainServiceImpl) processRun(run *repository.ReleaseTrainRun) {
+	stages, err := impl.releaseTrainRunRepository.FindRunStagesByRunId(run.Id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching release train run stages", "runId", run.Id, "err", err)
+		return
+	}
+	var artifacts []*bean.ReleaseTrainArtifact
+	if err = json.Unmarshal([]byte(run.Artifacts), &artifacts); err != nil {
+		impl.logger.Errorw("error in unmarshalling release train run artifacts", "runId", run.Id, "err", err)
+		impl.haltRun(run, nil, "invalid artifacts of the run")
+		return
+	}
+	gateOpenedOn := run.StartedOn
+	var previousStage *repository.ReleaseTrainRunStage
+	for _, stage := range stages {
+		status := bean.ReleaseTrainStageStatus(stage.Status)
+		if status == bean.ReleaseTrainStageSucceeded {
+			previousStage = stage
+			if stage.FinishedOn != nil {
+				gateOpenedOn = *stage.FinishedOn
+			}
+			continue
+		}
+		if status.IsGatePending() {
+			if deploying := impl.processStageGate(run, stage, previousStage, gateOpenedOn, artifacts); !deploying {
+				return
+			}
+		}
+		if stage.Status == bean.ReleaseTrainStageFailed.String() {
+			impl.haltRun(run, stage, stage.Message)
+			return
+		}
+		if succeeded := impl.processDeployingStage(run, stage); !succeeded {
+			return
+		}
+		previousStage = stage
+		gateOpenedOn = *stage.FinishedOn
+	}
+	now := time.Now()
+	_, err = impl.releaseTrainRunRepository.UpdateRunStatus(run.Id, []string{bean.ReleaseTrainRunRunning.String()}, bean.ReleaseTrainRunSucceeded.String(), "", &now, run.UpdatedBy)
+	if err != nil {
+		impl.logger.Errorw("error in marking release train run as succeeded", "runId", run.Id, "err", err)
+		return
+	}
+	impl.logger.Infow("release train run succeeded", "runId", run.Id)
+}
+
+// processStageGate evaluates the gate of the stage and triggers the deployments of the stage if the gate is met,
+// returns true if the stage is deploying
+func (impl *ReleaseTrainServiceImpl) processStageGate(run *repository.ReleaseTrainRun, stage, previousStage *repository.ReleaseTrainRunStage,
+	gateOpenedOn time.Time, artifacts []*bean.ReleaseTrainArtifact) bool {
+	now := time.Now()
+	if stage.GateStartedOn == nil {
+		stage.GateStartedOn = &now
+	}
+	var unhealthyApps []string
+	if stage.RequireHealthy && previousStage != nil {
+		var err error
+		unhealthyApps, err = impl.getUnhealthyApps(previousStage.EnvironmentId, artifacts)
+		if err != nil {
+			impl.logger.Errorw("error in checking health of apps of previous stage", "runId", run.Id, "runStageId", previousStage.Id, "err", err)
+			return false
+		}
+	}
+	result := EvaluateStageGate(stage, gateOpenedOn, unhealthyApps, now)
+	if result.Status == bean.ReleaseTrainStageAwaitingApproval && stage.Status != bean.ReleaseTrainStageAwaitingApproval.String() {
+		claimed, err := impl.releaseTrainRunRepository.UpdateRunStageStatus(stage.Id, []string{bean.ReleaseTrainStagePending.String(), bean.ReleaseTrainStageWaiting.String()}, result.Status.String(), run.CreatedBy)
+		if err != nil || !claimed {
+			// the approval is being requested by another replica
+			return false
+		}
+		impl.notifyApprovalRequested(run, stage, artifacts)
+	}
+	if result.Status != bean.ReleaseTrainStageDeploying {
+		stage.Status = result.Status.String()
+		stage.Message = result.Message
+		if result.Status == bean.ReleaseTrainStageFailed {
+			stage.FinishedOn = &now
+		}
+		stage.UpdatedOn = now
+		if err := impl.releaseTrainRunRepository.UpdateRunStage(stage); err != nil {
+			impl.logger.Errorw("error in updating release train run stage", "runStageId", stage.Id, "err", err)
+		}
+		return result.Status == bean.ReleaseTrainStageFailed
+	}
+	claimed, err := impl.releaseTrainRunRepository.UpdateRunStageStatus(stage.Id, gatePendingStageStatuses, bean.ReleaseTrainStageDeploying.String(), run.CreatedBy)
+	if err != nil || !claimed {
+		// the stage is being deployed by another replica
+		return false
+	}
+	stage.Status = bean.ReleaseTrainStageDeploying.String()
+	stage.Message = ""
+	if stage.StartedOn == nil {
+		stage.StartedOn = &now
+	}
+	stage.UpdatedOn = now
+	if err = impl.releaseTrainRunRepository.UpdateRunStage(stage); err != nil {
+		impl.logger.Errorw("error in updating release train run stage", "runStageId", stage.Id, "err", err)
+	}
+	if err = impl.triggerStageDeployments(run, stage, artifacts); err != nil {
+		impl.failStage(stage, fmt.Sprintf("error in saving deployments of the stage: %s", err.Error()))
+	}
+	return true
+}
+
+// notifyApprovalRequested sends the approval notification for the deployment pipeline of each app on the stage
+func (impl *ReleaseTrainServiceImpl) notifyApprovalRequested(run *repository.ReleaseTrainRun, stage *repository.ReleaseTrainRunStage, artifacts []*bean.ReleaseTrainArtifact) {
+	appIds := make([]int, 0, len(artifacts))
+	artifactIdByAppId := make(map[int]int, len(artifacts))
+	for _, artifact := range artifacts {
+		appIds = append(appIds, artifact.AppId)
+		artifactIdByAppId[artifact.AppId] = artifact.CiArtifactId
+	}
+	pipelines, err := impl.pipelineRepository.FindActiveByAppIds(appIds)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching cd pipelines of apps", "appIds", appIds, "err", err)
+		return
+	}
+	for _, pipeline := range pipelines {
+		if pipeline.EnvironmentId != stage.EnvironmentId {
+			continue
+		}
+		event, err := impl.eventFactory.Build(util2.Approval, &pipeline.Id, pipeline.AppId, &pipeline.EnvironmentId, util2.CD)
+		if err != nil {
+			impl.logger.Errorw("error in building approval event", "pipelineId", pipeline.Id, "err", err)
+			continue
+		}
+		event.CdWorkflowType = bean2.CD_WORKFLOW_TYPE_DEPLOY
+		event.CiArtifactId = artifactIdByAppId[pipeline.AppId]
+		event.UserId = int(run.CreatedBy)
+		_, err = impl.eventClient.WriteNotificationEvent(event)
+		if err != nil {
+			impl.logger.Errorw("error in writing approval event", "runStageId", stage.Id, "pipelineId", pipeline.Id, "err", err)
+		}
+	}
+}
+
+func (impl *ReleaseTrainServiceImpl) getUnhealthyApps(envId int, artifacts []*bean.ReleaseTrainArtifact) ([]string, error) {
+	appStatuses, err := impl.appStatusRepository.GetByEnvId(envId)
+	if err != nil && !util.IsErrNoRows(err) {
+		return nil, err
+	}
+	healthyAppIds := make(map[int]bool, len(appStatuses))
+	for _, appStatus := range appStatuses {
+		if appStatus.Status == string(health.HealthStatusHealthy) {
+			healthyAppIds[appStatus.AppId] = true
+		}
+	}
+	var unhealthyAppIds []int
+	for _, artifact := range artifacts {
+		if !healthyAppIds[artifact.AppId] {
+			unhealthyAppIds = append(unhealthyAppIds, artifact.AppId)
+		}
+	}
+	if len(unhealthyAppIds) == 0 {
+		return nil, nil
+	}
+	apps, err := impl.appRepository.FindAppAndProjectByIdsIn(unhealthyAppIds)
+	if err != nil {
+		return nil, err
+	}
+	unhealthyApps := make([]string, 0, len(apps))
+	for _, unhealthyApp := range apps {
+		unhealthyApps = append(unhealthyApps, unhealthyApp.AppName)
+	}
+	return unhealthyApps, nil
+}
+
+// triggerStageDeployments deploys the artifact of each app on the environment of the stage,
+// apps already deployed successfully on the stage (before the run was halted) are skipped.
+// The deployments are saved as pending before they are triggered, so that a triggered deployment is always tracked.
+func (impl *ReleaseTrainServiceImpl) triggerStageDeployments(run *repository.ReleaseTrainRun, stage *repository.ReleaseTrainRunStage, artifacts []*bean.ReleaseTrainArtifact) error {
+	existingDeployments, err := impl.releaseTrainRunRepository.FindDeploymentsByRunStageIds([]int{stage.Id})
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching release train run deployments", "runStageId", stage.Id, "err", err)
+		return err
+	}
+	deployedAppIds := make(map[int]bool)
+	for _, deployment := range existingDeployments {
+		if deployment.Status == bean.ReleaseTrainDeploymentSucceeded.String() {
+			deployedAppIds[deployment.AppId] = true
+		}
+	}
+	appIds := make([]int, 0, len(artifacts))
+	for _, artifact := range artifacts {
+		appIds = append(appIds, artifact.AppId)
+	}
+	pipelines, err := impl.pipelineRepository.FindActiveByAppIds(appIds)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching cd pipelines of apps", "appIds", appIds, "err", err)
+		return err
+	}
+	pipelineIdByAppId := make(map[int]int)
+	for _, pipeline := range pipelines {
+		if pipeline.EnvironmentId == stage.EnvironmentId {
+			pipelineIdByAppId[pipeline.AppId] = pipeline.Id
+		}
+	}
+
+	var deployments []*repository.ReleaseTrainRunDeployment
+	triggerCount := 0
+	auditLog := sql.NewDefaultAuditLog(run.CreatedBy)
+	for _, artifact := range artifacts {
+		if deployedAppIds[artifact.AppId] {
+			continue
+		}
+		deployment := &repository.ReleaseTrainRunDeployment{
+			RunStageId:   stage.Id,
+			AppId:        artifact.AppId,
+			PipelineId:   pipelineIdByAppId[artifact.AppId],
+			CiArtifactId: artifact.CiArtifactId,
+			Status:       bean.ReleaseTrainDeploymentPending.String(),
+			AuditLog:     auditLog,
+		}
+		if deployment.PipelineId == 0 {
+			deployment.Status = bean.ReleaseTrainDeploymentFailed.String()
+			deployment.Message = "cd pipeline not found on the environment of the stage"
+		} else {
+			triggerCount++
+		}
+		deployments = append(deployments, deployment)
+	}
+	err = impl.releaseTrainRunRepository.SaveDeployments(deployments)
+	if err != nil {
+		impl.logger.Errorw("error in saving release train run deployments", "runStageId", stage.Id, "err", err)
+		return err
+	}
+	for _, deployment := range deployments {
+		if deployment.Status != bean.ReleaseTrainDeploymentPending.String() {
+			continue
+		}
+		impl.triggerDeployment(run, deployment)
+		deployment.UpdatedOn = time.Now()
+		if err = impl.releaseTrainRunRepository.UpdateDeployment(deployment); err != nil {
+			// the deployment stays pending and fails the stage once it is processed
+			impl.logger.Errorw("error in updating release train run deployment", "deploymentId", deployment.Id, "err", err)
+		}
+	}
+	impl.logger.Infow("release train stage deployments triggered", "runId", run.Id, "runStageId", stage.Id, "count", triggerCount)
+	return nil
+}
+
+// triggerDeployment enqueues the deployment of a single app so that a failure only fails the deployment of that app
+func (impl *ReleaseTrainServiceImpl) triggerDeployment(run *repository.ReleaseTrainRun, deployment *repository.ReleaseTrainRunDeployment) {
+	triggerRequest := &eventBean.BulkTriggerRequest{
+		CiArtifactId: deployment.CiArtifactId,
+		PipelineId:   deployment.PipelineId,
+	}
+	cdWorkflows, err := impl.workflowEventPublishService.TriggerBulkDeploymentAsync([]*eventBean.BulkTriggerRequest{triggerRequest}, run.CreatedBy)
+	if err != nil || len(cdWorkflows) == 0 {
+		impl.logger.Errorw("error in triggering release train deployment", "runStageId", deployment.RunStageId, "pipelineId", deployment.PipelineId, "err", err)
+		deployment.Status = bean.ReleaseTrainDeploymentFailed.String()
+		deployment.Message = "error in triggering deployment"
+		if err != nil {
+			deployment.Message = fmt.Sprintf("error in triggering deployment: %s", err.Error())
+		}
+		return
+	}
+	deployment.CdWorkflowId = cdWorkflows[0].Id
+	deployment.Status = bean.ReleaseTrainDeploymentTriggered.String()
+	if cdWorkflows[0].WorkflowStatus == cdWorkflow.QUE_ERROR {
+		deployment.Status = bean.ReleaseTrainDeploymentFailed.String()
+		deployment.Message = "error in enqueuing deployment"
+	}
+}
+
+// stageTriggerTimeout is the time within which the deployments of a claimed stage are expected to be saved and triggered
+const stageTriggerTimeout = 5 * time.Minute
+
+// processDeployingStage refreshes the status of the deployments of the stage, the stage succeeds once all the apps are
+// deployed and fails as soon as any deployment fails. A stage without deployments, or with a deployment which is still
+// pending after its trigger, fails as its deployments could not be tracked. Returns true if the stage succeeded.
+func (impl *ReleaseTrainServiceImpl) processDeployingStage(run *repository.ReleaseTrainRun, stage *repository.ReleaseTrainRunStage) bool {
+	deployments, err := impl.releaseTrainRunRepository.FindDeploymentsByRunStageIds([]int{stage.Id})
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching release train run deployments", "runStageId", stage.Id, "err", err)
+		return false
+	}
+	// latest deployment of each app, older ones are the failed attempts before the run was resumed
+	latestDeploymentByAppId := make(map[int]*repository.ReleaseTrainRunDeployment)
+	for _, deployment := range deployments {
+		latestDeploymentByAppId[deployment.AppId] = deployment
+	}
+	// the deployments are saved and triggered while the stage is claimed, another replica may still be triggering them
+	isTriggering := time.Since(stage.UpdatedOn) < stageTriggerTimeout
+	if len(latestDeploymentByAppId) == 0 {
+		if !isTriggering {
+			impl.failStage(stage, "deployments of the stage were not saved")
+			impl.haltRun(run, stage, stage.Message)
+		}
+		return false
+	}
+	var failedDeployment *repository.ReleaseTrainRunDeployment
+	allSucceeded := true
+	for _, deployment := range latestDeploymentByAppId {
+		if deployment.Status == bean.ReleaseTrainDeploymentTriggered.String() {
+			impl.refreshDeploymentStatus(deployment)
+		}
+		switch deployment.Status {
+		case bean.ReleaseTrainDeploymentFailed.String():
+			failedDeployment = deployment
+			allSucceeded = false
+		case bean.ReleaseTrainDeploymentPending.String():
+			if !isTriggering {
+				deployment.Message = "result of the trigger of the deployment was not saved"
+				failedDeployment = deployment
+			}
+			allSucceeded = false
+		case bean.ReleaseTrainDeploymentTriggered.String():
+			allSucceeded = false
+		}
+	}
+	if failedDeployment != nil {
+		impl.failStage(stage, fmt.Sprintf("deployment of app %d failed: %s", failedDeployment.AppId, failedDeployment.Message))
+		impl.haltRun(run, stage, stage.Message)
+		return false
+	}
+	if !allSucceeded {
+		return false
+	}
+	now := time.Now()
+	stage.Status = bean.ReleaseTrainStageSucceeded.String()
+	stage.Message = ""
+	stage.FinishedOn = &now
+	stage.UpdatedOn = now
+	if err = impl.releaseTrainRunRepository.UpdateRunStage(stage); err != nil {
+		impl.logger.Errorw("error in updating release train run stage", "runStageId", stage.Id, "err", err)
+		return false
+	}
+	impl.logger.Infow("release train stage succeeded", "runId", run.Id, "runStageId", stage.Id)
+	return true
+}
+
+func (impl *ReleaseTrainServiceImpl) refreshDeploymentStatus(deployment *repository.ReleaseTrainRunDeployment) {
+	cdWorkflow, err := impl.cdWorkflowRepository.FindById(deployment.CdWorkflowId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching cd workflow of release train deployment", "deploymentId", deployment.Id, "cdWorkflowId", deployment.CdWorkflowId, "err", err)
+		return
+	}
+	runners, err := impl.cdWorkflowRepository.FindWorkflowRunnerByCdWorkflowId([]int{deployment.CdWorkflowId})
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching cd workflow runners of release train deployment", "deploymentId", deployment.Id, "cdWorkflowId", deployment.CdWorkflowId, "err", err)
+		return
+	}
+	status, message := GetDeploymentStatus(cdWorkflow.WorkflowStatus, runners)
+	if status.String() == deployment.Status {
+		return
+	}
+	deployment.Status = status.String()
+	deployment.Message = message
+	deployment.UpdatedOn = time.Now()
+	if err = impl.releaseTrainRunRepository.UpdateDeployment(deployment); err != nil {
+		impl.logger.Errorw("error in updating release train deployment", "deploymentId", deployment.Id, "err", err)
+	}
+}
+
+func (impl *ReleaseTrainServiceImpl) failStage(stage *repository.ReleaseTrainRunStage, message string) {
+	now := time.Now()
+	stage.Status = bean.ReleaseTrainStageFailed.String()
+	stage.Message = message
+	stage.FinishedOn = &now
+	stage.UpdatedOn = now
+	if err := impl.releaseTrainRunRepository.UpdateRunStage(stage); err != nil {
+		impl.logger.Errorw("error in updating release train run stage", "runStageId", stage.Id, "err", err)
+	}
+}
+
+// haltRun halts the run on the failure of a stage, the run can be resumed once the failure is fixed
+func (impl *ReleaseTrainServiceImpl) haltRun(run *repository.ReleaseTrainRun, stage *repository.ReleaseTrainRunStage, message string) {
+	if stage != nil {
+		message = fmt.Sprintf("stage %s failed: %s", stage.Name, message)
+	}
+	halted, err := impl.releaseTrainRunRepository.UpdateRunStatus(run.Id, []string{bean.ReleaseTrainRunRunning.String()}, bean.ReleaseTrainRunHalted.String(), message, nil, run.UpdatedBy)
+	if err != nil {
+		impl.logger.Errorw("error in halting release train run", "runId", run.Id, "err", err)
+		return
+	}
+	if halted {
+		impl.logger.Infow("release train run halted", "runId", run.Id, "message", message)
+	}
+}
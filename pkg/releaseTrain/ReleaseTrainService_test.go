@@ -0,0 +1,269 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package releaseTrain
+
+import (
+	"errors"
+	client "github.com/devtron-labs/devtron/client/events"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig/bean/workflow/cdWorkflow"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/eventProcessor/out"
+	eventBean "github.com/devtron-labs/devtron/pkg/eventProcessor/out/bean"
+	"github.com/devtron-labs/devtron/pkg/releaseTrain/bean"
+	"github.com/devtron-labs/devtron/pkg/releaseTrain/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	util2 "github.com/devtron-labs/devtron/util/event"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type releaseTrainRunRepositoryStub struct {
+	repository.ReleaseTrainRunRepository
+	run         *repository.ReleaseTrainRun
+	stage       *repository.ReleaseTrainRunStage
+	deployments []*repository.ReleaseTrainRunDeployment
+	saveErr     error
+	// statusesOnSave are the statuses of the deployments when they are saved
+	statusesOnSave []string
+	haltedRunIds   []int
+	// claimedStatuses are the statuses the stage was claimed for
+	claimedStatuses []string
+}
+
+func (stub *releaseTrainRunRepositoryStub) FindRunById(id int) (*repository.ReleaseTrainRun, error) {
+	return stub.run, nil
+}
+
+func (stub *releaseTrainRunRepositoryStub) FindRunStageById(id int) (*repository.ReleaseTrainRunStage, error) {
+	return stub.stage, nil
+}
+
+func (stub *releaseTrainRunRepositoryStub) FindDeploymentsByRunStageIds(runStageIds []int) ([]*repository.ReleaseTrainRunDeployment, error) {
+	return nil, nil
+}
+
+func (stub *releaseTrainRunRepositoryStub) SaveDeployments(deployments []*repository.ReleaseTrainRunDeployment) error {
+	if stub.saveErr != nil {
+		return stub.saveErr
+	}
+	for _, deployment := range deployments {
+		stub.statusesOnSave = append(stub.statusesOnSave, deployment.Status)
+	}
+	stub.deployments = deployments
+	return nil
+}
+
+func (stub *releaseTrainRunRepositoryStub) UpdateDeployment(deployment *repository.ReleaseTrainRunDeployment) error {
+	return nil
+}
+
+func (stub *releaseTrainRunRepositoryStub) UpdateRunStage(stage *repository.ReleaseTrainRunStage) error {
+	return nil
+}
+
+func (stub *releaseTrainRunRepositoryStub) UpdateRunStatus(runId int, fromStatuses []string, status string, message string, finishedOn *time.Time, userId int32) (bool, error) {
+	stub.haltedRunIds = append(stub.haltedRunIds, runId)
+	return true, nil
+}
+
+func (stub *releaseTrainRunRepositoryStub) UpdateRunStageStatus(id int, fromStatuses []string, status string, userId int32) (bool, error) {
+	stub.claimedStatuses = append(stub.claimedStatuses, status)
+	return true, nil
+}
+
+type eventFactoryStub struct {
+	client.EventFactory
+}
+
+func (stub *eventFactoryStub) Build(eventType util2.EventType, sourceId *int, appId int, envId *int, pipelineType util2.PipelineType) (client.Event, error) {
+	return client.Event{EventTypeId: int(eventType), PipelineId: *sourceId, AppId: appId, EnvId: *envId, PipelineType: string(pipelineType)}, nil
+}
+
+type eventClientStub struct {
+	client.EventClient
+	events []client.Event
+}
+
+func (stub *eventClientStub) WriteNotificationEvent(event client.Event) (bool, error) {
+	stub.events = append(stub.events, event)
+	return true, nil
+}
+
+type pipelineRepositoryStub struct {
+	pipelineConfig.PipelineRepository
+	pipelines []*pipelineConfig.Pipeline
+}
+
+func (stub *pipelineRepositoryStub) FindActiveByAppIds(appIds []int) ([]*pipelineConfig.Pipeline, error) {
+	return stub.pipelines, nil
+}
+
+// workflowEventPublishServiceStub fails the trigger of the pipelines in failedPipelineIds and
+// the enqueue of the pipelines in unqueuedPipelineIds
+type workflowEventPublishServiceStub struct {
+	out.WorkflowEventPublishService
+	failedPipelineIds   map[int]bool
+	unqueuedPipelineIds map[int]bool
+}
+
+func (stub *workflowEventPublishServiceStub) TriggerBulkDeploymentAsync(requests []*eventBean.BulkTriggerRequest, UserId int32) ([]*pipelineConfig.CdWorkflow, error) {
+	var cdWorkflows []*pipelineConfig.CdWorkflow
+	for _, request := range requests {
+		if stub.failedPipelineIds[request.PipelineId] {
+			return nil, errors.New("error in saving workflow")
+		}
+		cdWf := &pipelineConfig.CdWorkflow{Id: 100 + request.PipelineId, PipelineId: request.PipelineId, WorkflowStatus: cdWorkflow.ENQUEUED}
+		if stub.unqueuedPipelineIds[request.PipelineId] {
+			cdWf.WorkflowStatus = cdWorkflow.QUE_ERROR
+		}
+		cdWorkflows = append(cdWorkflows, cdWf)
+	}
+	return cdWorkflows, nil
+}
+
+func newReleaseTrainServiceForTest(t *testing.T, runRepository *releaseTrainRunRepositoryStub) *ReleaseTrainServiceImpl {
+	logger, err := util.NewSugardLogger()
+	assert.Nil(t, err)
+	return &ReleaseTrainServiceImpl{
+		logger:                    logger,
+		releaseTrainRunRepository: runRepository,
+	}
+}
+
+func TestApproveStage(t *testing.T) {
+	runRepository := &releaseTrainRunRepositoryStub{
+		run: &repository.ReleaseTrainRun{Id: 1, Status: bean.ReleaseTrainRunRunning.String(), AuditLog: sql.NewDefaultAuditLog(2)},
+		stage: &repository.ReleaseTrainRunStage{Id: 10, RunId: 1, RequireApproval: true,
+			Status: bean.ReleaseTrainStageAwaitingApproval.String()},
+	}
+	impl := newReleaseTrainServiceForTest(t, runRepository)
+
+	_, err := impl.ApproveStage(1, 10, 2)
+	var apiErr *util.ApiError
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusForbidden, apiErr.HttpStatusCode)
+	assert.Nil(t, runRepository.stage.ApprovedOn)
+}
+
+func TestTriggerStageDeployments(t *testing.T) {
+	runRepository := &releaseTrainRunRepositoryStub{}
+	impl := newReleaseTrainServiceForTest(t, runRepository)
+	impl.pipelineRepository = &pipelineRepositoryStub{pipelines: []*pipelineConfig.Pipeline{
+		{Id: 1, AppId: 1, EnvironmentId: 5},
+		{Id: 2, AppId: 2, EnvironmentId: 5},
+		{Id: 3, AppId: 3, EnvironmentId: 5},
+	}}
+	impl.workflowEventPublishService = &workflowEventPublishServiceStub{
+		failedPipelineIds:   map[int]bool{2: true},
+		unqueuedPipelineIds: map[int]bool{3: true},
+	}
+	run := &repository.ReleaseTrainRun{Id: 1, AuditLog: sql.NewDefaultAuditLog(2)}
+	stage := &repository.ReleaseTrainRunStage{Id: 10, RunId: 1, EnvironmentId: 5}
+	artifacts := []*bean.ReleaseTrainArtifact{
+		{AppId: 1, CiArtifactId: 11},
+		{AppId: 2, CiArtifactId: 12},
+		{AppId: 3, CiArtifactId: 13},
+		{AppId: 4, CiArtifactId: 14},
+	}
+
+	err := impl.triggerStageDeployments(run, stage, artifacts)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{
+		bean.ReleaseTrainDeploymentPending.String(),
+		bean.ReleaseTrainDeploymentPending.String(),
+		bean.ReleaseTrainDeploymentPending.String(),
+		bean.ReleaseTrainDeploymentFailed.String(),
+	}, runRepository.statusesOnSave)
+
+	statuses := make(map[int]string)
+	cdWorkflowIds := make(map[int]int)
+	for _, deployment := range runRepository.deployments {
+		statuses[deployment.AppId] = deployment.Status
+		cdWorkflowIds[deployment.AppId] = deployment.CdWorkflowId
+	}
+	assert.Equal(t, map[int]string{
+		1: bean.ReleaseTrainDeploymentTriggered.String(),
+		2: bean.ReleaseTrainDeploymentFailed.String(),
+		3: bean.ReleaseTrainDeploymentFailed.String(),
+		4: bean.ReleaseTrainDeploymentFailed.String(),
+	}, statuses)
+	assert.Equal(t, 101, cdWorkflowIds[1])
+	assert.Equal(t, 103, cdWorkflowIds[3])
+}
+
+func TestTriggerStageDeploymentsSaveFailure(t *testing.T) {
+	runRepository := &releaseTrainRunRepositoryStub{saveErr: errors.New("connection refused")}
+	impl := newReleaseTrainServiceForTest(t, runRepository)
+	impl.pipelineRepository = &pipelineRepositoryStub{pipelines: []*pipelineConfig.Pipeline{{Id: 1, AppId: 1, EnvironmentId: 5}}}
+	publishService := &workflowEventPublishServiceStub{failedPipelineIds: map[int]bool{1: true}}
+	impl.workflowEventPublishService = publishService
+	run := &repository.ReleaseTrainRun{Id: 1, AuditLog: sql.NewDefaultAuditLog(2)}
+	stage := &repository.ReleaseTrainRunStage{Id: 10, RunId: 1, EnvironmentId: 5}
+
+	err := impl.triggerStageDeployments(run, stage, []*bean.ReleaseTrainArtifact{{AppId: 1, CiArtifactId: 11}})
+	assert.NotNil(t, err)
+	assert.Nil(t, runRepository.deployments)
+}
+
+func TestProcessDeployingStageWithoutDeployments(t *testing.T) {
+	runRepository := &releaseTrainRunRepositoryStub{}
+	impl := newReleaseTrainServiceForTest(t, runRepository)
+	run := &repository.ReleaseTrainRun{Id: 1, AuditLog: sql.NewDefaultAuditLog(2)}
+
+	// the deployments may still be being saved by the replica which claimed the stage
+	stage := &repository.ReleaseTrainRunStage{Id: 10, RunId: 1, Status: bean.ReleaseTrainStageDeploying.String(),
+		AuditLog: sql.AuditLog{UpdatedOn: time.Now()}}
+	assert.False(t, impl.processDeployingStage(run, stage))
+	assert.Equal(t, bean.ReleaseTrainStageDeploying.String(), stage.Status)
+
+	stage.UpdatedOn = time.Now().Add(-stageTriggerTimeout)
+	assert.False(t, impl.processDeployingStage(run, stage))
+	assert.Equal(t, bean.ReleaseTrainStageFailed.String(), stage.Status)
+	assert.NotNil(t, stage.FinishedOn)
+	assert.Equal(t, []int{1}, runRepository.haltedRunIds)
+}
+
+func TestProcessStageGateApprovalNotification(t *testing.T) {
+	runRepository := &releaseTrainRunRepositoryStub{}
+	impl := newReleaseTrainServiceForTest(t, runRepository)
+	impl.pipelineRepository = &pipelineRepositoryStub{pipelines: []*pipelineConfig.Pipeline{
+		{Id: 1, AppId: 1, EnvironmentId: 5},
+		{Id: 2, AppId: 1, EnvironmentId: 6},
+	}}
+	impl.eventFactory = &eventFactoryStub{}
+	eventClient := &eventClientStub{}
+	impl.eventClient = eventClient
+	run := &repository.ReleaseTrainRun{Id: 1, AuditLog: sql.NewDefaultAuditLog(2)}
+	stage := &repository.ReleaseTrainRunStage{Id: 10, RunId: 1, EnvironmentId: 5, RequireApproval: true,
+		Status: bean.ReleaseTrainStagePending.String()}
+	artifacts := []*bean.ReleaseTrainArtifact{{AppId: 1, CiArtifactId: 11}}
+
+	assert.False(t, impl.processStageGate(run, stage, nil, time.Now(), artifacts))
+	assert.Equal(t, bean.ReleaseTrainStageAwaitingApproval.String(), stage.Status)
+	assert.Len(t, eventClient.events, 1)
+	assert.Equal(t, int(util2.Approval), eventClient.events[0].EventTypeId)
+	assert.Equal(t, 1, eventClient.events[0].PipelineId)
+	assert.Equal(t, 11, eventClient.events[0].CiArtifactId)
+
+	// the approval is requested only once
+	assert.False(t, impl.processStageGate(run, stage, nil, time.Now(), artifacts))
+	assert.Len(t, eventClient.events, 1)
+	assert.Equal(t, []string{bean.ReleaseTrainStageAwaitingApproval.String()}, runRepository.claimedStatuses)
+}
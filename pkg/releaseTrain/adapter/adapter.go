@@ -0,0 +1,156 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adapter
+
+import (
+	"encoding/json"
+	"github.com/devtron-labs/devtron/pkg/releaseTrain/bean"
+	"github.com/devtron-labs/devtron/pkg/releaseTrain/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+)
+
+func GetReleaseTrainDbObject(train *bean.ReleaseTrainDto, auditLog sql.AuditLog) *repository.ReleaseTrain {
+	return &repository.ReleaseTrain{
+		Id:          train.Id,
+		Name:        train.Name,
+		Description: train.Description,
+		AppIds:      train.AppIds,
+		Active:      true,
+		AuditLog:    auditLog,
+	}
+}
+
+func GetReleaseTrainStageDbObjects(releaseTrainId int, stages []*bean.ReleaseTrainStageDto, auditLog sql.AuditLog) []*repository.ReleaseTrainStage {
+	dbObjects := make([]*repository.ReleaseTrainStage, 0, len(stages))
+	for i, stage := range stages {
+		gate := stage.Gate
+		if gate == nil {
+			gate = &bean.StageGate{}
+		}
+		dbObjects = append(dbObjects, &repository.ReleaseTrainStage{
+			ReleaseTrainId:        releaseTrainId,
+			Name:                  stage.Name,
+			StageOrder:            i + 1,
+			EnvironmentId:         stage.EnvironmentId,
+			WaitMinutes:           gate.WaitMinutes,
+			RequireHealthy:        gate.RequireHealthy,
+			HealthyTimeoutMinutes: gate.HealthyTimeoutMinutes,
+			RequireApproval:       gate.RequireApproval,
+			Active:                true,
+			AuditLog:              auditLog,
+		})
+	}
+	return dbObjects
+}
+
+func GetReleaseTrainDto(train *repository.ReleaseTrain, stages []*repository.ReleaseTrainStage, envNames map[int]string) *bean.ReleaseTrainDto {
+	stageDtos := make([]*bean.ReleaseTrainStageDto, 0, len(stages))
+	for _, stage := range stages {
+		stageDtos = append(stageDtos, &bean.ReleaseTrainStageDto{
+			Id:              stage.Id,
+			Name:            stage.Name,
+			EnvironmentId:   stage.EnvironmentId,
+			EnvironmentName: envNames[stage.EnvironmentId],
+			Gate: &bean.StageGate{
+				WaitMinutes:           stage.WaitMinutes,
+				RequireHealthy:        stage.RequireHealthy,
+				HealthyTimeoutMinutes: stage.HealthyTimeoutMinutes,
+				RequireApproval:       stage.RequireApproval,
+			},
+		})
+	}
+	return &bean.ReleaseTrainDto{
+		Id:          train.Id,
+		Name:        train.Name,
+		Description: train.Description,
+		AppIds:      train.AppIds,
+		Stages:      stageDtos,
+	}
+}
+
+// GetReleaseTrainRunStageDbObjects copies the stages of the train for a run
+func GetReleaseTrainRunStageDbObjects(runId int, stages []*repository.ReleaseTrainStage, auditLog sql.AuditLog) []*repository.ReleaseTrainRunStage {
+	dbObjects := make([]*repository.ReleaseTrainRunStage, 0, len(stages))
+	for _, stage := range stages {
+		dbObjects = append(dbObjects, &repository.ReleaseTrainRunStage{
+			RunId:                 runId,
+			StageOrder:            stage.StageOrder,
+			Name:                  stage.Name,
+			EnvironmentId:         stage.EnvironmentId,
+			WaitMinutes:           stage.WaitMinutes,
+			RequireHealthy:        stage.RequireHealthy,
+			HealthyTimeoutMinutes: stage.HealthyTimeoutMinutes,
+			RequireApproval:       stage.RequireApproval,
+			Status:                bean.ReleaseTrainStagePending.String(),
+			AuditLog:              auditLog,
+		})
+	}
+	return dbObjects
+}
+
+func GetReleaseTrainRunDto(run *repository.ReleaseTrainRun, releaseTrainName string, stages []*repository.ReleaseTrainRunStage,
+	deployments []*repository.ReleaseTrainRunDeployment) *bean.ReleaseTrainRunDto {
+	var artifacts []*bean.ReleaseTrainArtifact
+	_ = json.Unmarshal([]byte(run.Artifacts), &artifacts)
+	deploymentsByStageId := make(map[int][]*bean.ReleaseTrainDeploymentDto)
+	for _, deployment := range deployments {
+		deploymentsByStageId[deployment.RunStageId] = append(deploymentsByStageId[deployment.RunStageId], &bean.ReleaseTrainDeploymentDto{
+			Id:           deployment.Id,
+			AppId:        deployment.AppId,
+			PipelineId:   deployment.PipelineId,
+			CiArtifactId: deployment.CiArtifactId,
+			CdWorkflowId: deployment.CdWorkflowId,
+			Status:       bean.ReleaseTrainDeploymentStatus(deployment.Status),
+			Message:      deployment.Message,
+			CreatedOn:    deployment.CreatedOn,
+		})
+	}
+	stageDtos := make([]*bean.ReleaseTrainRunStageDto, 0, len(stages))
+	for _, stage := range stages {
+		stageDtos = append(stageDtos, &bean.ReleaseTrainRunStageDto{
+			Id:            stage.Id,
+			Name:          stage.Name,
+			StageOrder:    stage.StageOrder,
+			EnvironmentId: stage.EnvironmentId,
+			Gate: &bean.StageGate{
+				WaitMinutes:           stage.WaitMinutes,
+				RequireHealthy:        stage.RequireHealthy,
+				HealthyTimeoutMinutes: stage.HealthyTimeoutMinutes,
+				RequireApproval:       stage.RequireApproval,
+			},
+			Status:      bean.ReleaseTrainStageStatus(stage.Status),
+			Message:     stage.Message,
+			ApprovedBy:  stage.ApprovedBy,
+			ApprovedOn:  stage.ApprovedOn,
+			StartedOn:   stage.StartedOn,
+			FinishedOn:  stage.FinishedOn,
+			Deployments: deploymentsByStageId[stage.Id],
+		})
+	}
+	return &bean.ReleaseTrainRunDto{
+		Id:               run.Id,
+		ReleaseTrainId:   run.ReleaseTrainId,
+		ReleaseTrainName: releaseTrainName,
+		Status:           bean.ReleaseTrainRunStatus(run.Status),
+		Message:          run.Message,
+		Artifacts:        artifacts,
+		TriggeredBy:      run.CreatedBy,
+		StartedOn:        run.StartedOn,
+		FinishedOn:       run.FinishedOn,
+		Stages:           stageDtos,
+	}
+}
@@ -0,0 +1,158 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import "time"
+
+type ReleaseTrainRunStatus string
+
+const (
+	ReleaseTrainRunRunning   ReleaseTrainRunStatus = "RUNNING"
+	ReleaseTrainRunHalted    ReleaseTrainRunStatus = "HALTED"
+	ReleaseTrainRunSucceeded ReleaseTrainRunStatus = "SUCCEEDED"
+	ReleaseTrainRunAborted   ReleaseTrainRunStatus = "ABORTED"
+)
+
+func (s ReleaseTrainRunStatus) String() string {
+	return string(s)
+}
+
+type ReleaseTrainStageStatus string
+
+const (
+	ReleaseTrainStagePending ReleaseTrainStageStatus = "PENDING"
+	// ReleaseTrainStageWaiting is for the stages waiting for the wait time or the health of the previous stage
+	ReleaseTrainStageWaiting          ReleaseTrainStageStatus = "WAITING"
+	ReleaseTrainStageAwaitingApproval ReleaseTrainStageStatus = "AWAITING_APPROVAL"
+	ReleaseTrainStageDeploying        ReleaseTrainStageStatus = "DEPLOYING"
+	ReleaseTrainStageSucceeded        ReleaseTrainStageStatus = "SUCCEEDED"
+	ReleaseTrainStageFailed           ReleaseTrainStageStatus = "FAILED"
+)
+
+func (s ReleaseTrainStageStatus) String() string {
+	return string(s)
+}
+
+// IsGatePending tells if the stage has not started deploying yet
+func (s ReleaseTrainStageStatus) IsGatePending() bool {
+	return s == ReleaseTrainStagePending || s == ReleaseTrainStageWaiting || s == ReleaseTrainStageAwaitingApproval
+}
+
+type ReleaseTrainDeploymentStatus string
+
+const (
+	// ReleaseTrainDeploymentPending is the deployment saved before it is triggered
+	ReleaseTrainDeploymentPending   ReleaseTrainDeploymentStatus = "PENDING"
+	ReleaseTrainDeploymentTriggered ReleaseTrainDeploymentStatus = "TRIGGERED"
+	ReleaseTrainDeploymentSucceeded ReleaseTrainDeploymentStatus = "SUCCEEDED"
+	ReleaseTrainDeploymentFailed    ReleaseTrainDeploymentStatus = "FAILED"
+)
+
+func (s ReleaseTrainDeploymentStatus) String() string {
+	return string(s)
+}
+
+const (
+	ReleaseTrainNameExistsMessage   = "release train with name %s already exists"
+	ReleaseTrainNotFoundMessage     = "release train not found"
+	ReleaseTrainRunNotFoundMessage  = "release train run not found"
+	ReleaseTrainSelfApprovalMessage = "stage can not be approved by the user who started the run"
+	// DefaultHealthyTimeoutMinutes is the time for which a stage waits for the apps of the previous stage to be healthy
+	// before halting the run, if the timeout is not set on its gate
+	DefaultHealthyTimeoutMinutes = 30
+)
+
+// StageGate is the set of conditions to be met before a stage starts deploying
+type StageGate struct {
+	// WaitMinutes is the time to wait after the previous stage succeeded
+	WaitMinutes int `json:"waitMinutes" validate:"min=0"`
+	// RequireHealthy waits for all the apps of the previous stage to be healthy
+	RequireHealthy        bool `json:"requireHealthy"`
+	HealthyTimeoutMinutes int  `json:"healthyTimeoutMinutes" validate:"min=0"`
+	// RequireApproval waits for a user with trigger access on the stage to approve it
+	RequireApproval bool `json:"requireApproval"`
+}
+
+type ReleaseTrainStageDto struct {
+	Id              int        `json:"id"`
+	Name            string     `json:"name" validate:"required,max=50"`
+	EnvironmentId   int        `json:"environmentId" validate:"required,min=1"`
+	EnvironmentName string     `json:"environmentName,omitempty"`
+	Gate            *StageGate `json:"gate"`
+}
+
+// ReleaseTrainDto is an ordered set of stages through which the apps of the train are released, stage i+1 starts
+// only after all the apps are deployed on the environment of stage i and the gates of stage i+1 are met
+type ReleaseTrainDto struct {
+	Id          int                     `json:"id"`
+	Name        string                  `json:"name" validate:"required,max=100"`
+	Description string                  `json:"description"`
+	AppIds      []int                   `json:"appIds" validate:"required,min=1"`
+	Stages      []*ReleaseTrainStageDto `json:"stages" validate:"required,min=1,dive"`
+	UserId      int32                   `json:"-"`
+}
+
+type ReleaseTrainArtifact struct {
+	AppId        int `json:"appId" validate:"required,min=1"`
+	CiArtifactId int `json:"ciArtifactId" validate:"required,min=1"`
+}
+
+// ReleaseTrainRunRequest starts a run of the train releasing the given artifact of each app
+type ReleaseTrainRunRequest struct {
+	ReleaseTrainId int                     `json:"-"`
+	Artifacts      []*ReleaseTrainArtifact `json:"artifacts" validate:"required,min=1,dive"`
+	UserId         int32                   `json:"-"`
+}
+
+type ReleaseTrainRunDto struct {
+	Id               int                        `json:"id"`
+	ReleaseTrainId   int                        `json:"releaseTrainId"`
+	ReleaseTrainName string                     `json:"releaseTrainName"`
+	Status           ReleaseTrainRunStatus      `json:"status"`
+	Message          string                     `json:"message,omitempty"`
+	Artifacts        []*ReleaseTrainArtifact    `json:"artifacts"`
+	TriggeredBy      int32                      `json:"triggeredBy"`
+	StartedOn        time.Time                  `json:"startedOn"`
+	FinishedOn       *time.Time                 `json:"finishedOn,omitempty"`
+	Stages           []*ReleaseTrainRunStageDto `json:"stages,omitempty"`
+}
+
+type ReleaseTrainRunStageDto struct {
+	Id            int                          `json:"id"`
+	Name          string                       `json:"name"`
+	StageOrder    int                          `json:"stageOrder"`
+	EnvironmentId int                          `json:"environmentId"`
+	Gate          *StageGate                   `json:"gate"`
+	Status        ReleaseTrainStageStatus      `json:"status"`
+	Message       string                       `json:"message,omitempty"`
+	ApprovedBy    int32                        `json:"approvedBy,omitempty"`
+	ApprovedOn    *time.Time                   `json:"approvedOn,omitempty"`
+	StartedOn     *time.Time                   `json:"startedOn,omitempty"`
+	FinishedOn    *time.Time                   `json:"finishedOn,omitempty"`
+	Deployments   []*ReleaseTrainDeploymentDto `json:"deployments,omitempty"`
+}
+
+type ReleaseTrainDeploymentDto struct {
+	Id           int                          `json:"id"`
+	AppId        int                          `json:"appId"`
+	PipelineId   int                          `json:"pipelineId"`
+	CiArtifactId int                          `json:"ciArtifactId"`
+	CdWorkflowId int                          `json:"cdWorkflowId"`
+	Status       ReleaseTrainDeploymentStatus `json:"status"`
+	Message      string                       `json:"message,omitempty"`
+	CreatedOn    time.Time                    `json:"createdOn"`
+}
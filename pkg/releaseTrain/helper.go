@@ -0,0 +1,163 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package releaseTrain
+
+import (
+	"fmt"
+	"github.com/devtron-labs/common-lib/utils/k8s/health"
+	apiBean "github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig/bean/workflow/cdWorkflow"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/releaseTrain/bean"
+	"github.com/devtron-labs/devtron/pkg/releaseTrain/repository"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// StageGateResult is the outcome of evaluating the gate of a stage, the stage can start deploying
+// if Status is bean.ReleaseTrainStageDeploying
+type StageGateResult struct {
+	Status  bean.ReleaseTrainStageStatus
+	Message string
+}
+
+// EvaluateStageGate evaluates the gate of the stage, the gate opens when the previous stage succeeds (or the run starts
+// for the first stage). unhealthyApps are the apps of the previous stage which are not healthy yet.
+// The stage fails if these apps are not healthy within the healthy timeout, counted from the end of the wait time or
+// from the time the stage was resumed, whichever is later.
+func EvaluateStageGate(stage *repository.ReleaseTrainRunStage, gateOpenedOn time.Time, unhealthyApps []string, now time.Time) *StageGateResult {
+	waitUntil := gateOpenedOn.Add(time.Duration(stage.WaitMinutes) * time.Minute)
+	if now.Before(waitUntil) {
+		return &StageGateResult{
+			Status:  bean.ReleaseTrainStageWaiting,
+			Message: fmt.Sprintf("waiting till %s", waitUntil.Format(time.RFC3339)),
+		}
+	}
+	if stage.RequireHealthy && len(unhealthyApps) > 0 {
+		healthySince := waitUntil
+		if stage.GateStartedOn != nil && stage.GateStartedOn.After(healthySince) {
+			healthySince = *stage.GateStartedOn
+		}
+		timeoutMinutes := stage.HealthyTimeoutMinutes
+		if timeoutMinutes <= 0 {
+			timeoutMinutes = bean.DefaultHealthyTimeoutMinutes
+		}
+		if now.After(healthySince.Add(time.Duration(timeoutMinutes) * time.Minute)) {
+			return &StageGateResult{
+				Status:  bean.ReleaseTrainStageFailed,
+				Message: fmt.Sprintf("apps %s of the previous stage are not healthy in %d minutes", strings.Join(unhealthyApps, ", "), timeoutMinutes),
+			}
+		}
+		return &StageGateResult{
+			Status:  bean.ReleaseTrainStageWaiting,
+			Message: fmt.Sprintf("waiting for apps %s of the previous stage to be healthy", strings.Join(unhealthyApps, ", ")),
+		}
+	}
+	if stage.RequireApproval && stage.ApprovedOn == nil {
+		return &StageGateResult{
+			Status:  bean.ReleaseTrainStageAwaitingApproval,
+			Message: "waiting for approval",
+		}
+	}
+	return &StageGateResult{Status: bean.ReleaseTrainStageDeploying}
+}
+
+// failedWorkflowStatuses are the statuses of the cd workflow for which the deployment never started
+var failedWorkflowStatuses = map[cdWorkflow.WorkflowStatus]bool{
+	cdWorkflow.QUE_ERROR:     true,
+	cdWorkflow.DEQUE_ERROR:   true,
+	cdWorkflow.TRIGGER_ERROR: true,
+	cdWorkflow.DROPPED_STALE: true,
+}
+
+var failedRunnerStatuses = map[string]bool{
+	cdWorkflow.WorkflowFailed:           true,
+	cdWorkflow.WorkflowAborted:          true,
+	cdWorkflow.WorkflowTimedOut:         true,
+	cdWorkflow.WorkflowCancel:           true,
+	string(health.HealthStatusDegraded): true,
+}
+
+// GetDeploymentStatus resolves the status of a deployment of a stage from its cd workflow and the runners of the workflow.
+// The deployment succeeds once the deploy stage succeeds, the post deployment stage if any is not waited for.
+func GetDeploymentStatus(workflowStatus cdWorkflow.WorkflowStatus, runners []*pipelineConfig.CdWorkflowRunner) (bean.ReleaseTrainDeploymentStatus, string) {
+	if failedWorkflowStatuses[workflowStatus] {
+		return bean.ReleaseTrainDeploymentFailed, fmt.Sprintf("deployment could not be triggered, status %s", workflowStatus.String())
+	}
+	for _, runner := range runners {
+		if failedRunnerStatuses[runner.Status] {
+			message := fmt.Sprintf("%s stage %s", strings.ToLower(string(runner.WorkflowType)), runner.Status)
+			if len(runner.Message) > 0 {
+				message = fmt.Sprintf("%s: %s", message, runner.Message)
+			}
+			return bean.ReleaseTrainDeploymentFailed, message
+		}
+	}
+	for _, runner := range runners {
+		if runner.WorkflowType == apiBean.CD_WORKFLOW_TYPE_DEPLOY &&
+			(runner.Status == cdWorkflow.WorkflowSucceeded || runner.Status == string(health.HealthStatusHealthy)) {
+			return bean.ReleaseTrainDeploymentSucceeded, ""
+		}
+	}
+	return bean.ReleaseTrainDeploymentTriggered, ""
+}
+
+// validateReleaseTrain validates the stages of the train, the environments of the stages must be unique
+func validateReleaseTrain(train *bean.ReleaseTrainDto) error {
+	seenEnvIds := make(map[int]bool, len(train.Stages))
+	for _, stage := range train.Stages {
+		if seenEnvIds[stage.EnvironmentId] {
+			errMsg := fmt.Sprintf("environment %d is used in more than one stage", stage.EnvironmentId)
+			return util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+		}
+		seenEnvIds[stage.EnvironmentId] = true
+	}
+	seenAppIds := make(map[int]bool, len(train.AppIds))
+	for _, appId := range train.AppIds {
+		if seenAppIds[appId] {
+			errMsg := fmt.Sprintf("app %d is added more than once", appId)
+			return util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+		}
+		seenAppIds[appId] = true
+	}
+	return nil
+}
+
+// validateRunArtifacts ensures that exactly one artifact is given for each app of the train
+func validateRunArtifacts(appIds []int, artifacts []*bean.ReleaseTrainArtifact) error {
+	artifactAppIds := make(map[int]bool, len(artifacts))
+	for _, artifact := range artifacts {
+		if artifactAppIds[artifact.AppId] {
+			errMsg := fmt.Sprintf("more than one artifact given for app %d", artifact.AppId)
+			return util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+		}
+		artifactAppIds[artifact.AppId] = true
+	}
+	for _, appId := range appIds {
+		if !artifactAppIds[appId] {
+			errMsg := fmt.Sprintf("artifact not given for app %d", appId)
+			return util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+		}
+	}
+	if len(artifactAppIds) != len(appIds) {
+		errMsg := "artifacts given for apps which are not part of the release train"
+		return util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+	}
+	return nil
+}
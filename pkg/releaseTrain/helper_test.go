@@ -0,0 +1,86 @@
+package releaseTrain
+
+import (
+	apiBean "github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig/bean/workflow/cdWorkflow"
+	"github.com/devtron-labs/devtron/pkg/releaseTrain/bean"
+	"github.com/devtron-labs/devtron/pkg/releaseTrain/repository"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestEvaluateStageGate(t *testing.T) {
+	gateOpenedOn := time.Date(2025, 12, 1, 10, 0, 0, 0, time.UTC)
+	approvedOn := gateOpenedOn
+	resumedOn := gateOpenedOn.Add(2 * time.Hour)
+	tests := []struct {
+		name          string
+		stage         *repository.ReleaseTrainRunStage
+		unhealthyApps []string
+		now           time.Time
+		want          bean.ReleaseTrainStageStatus
+	}{
+		{name: "no gate", stage: &repository.ReleaseTrainRunStage{}, now: gateOpenedOn, want: bean.ReleaseTrainStageDeploying},
+		{name: "within wait time", stage: &repository.ReleaseTrainRunStage{WaitMinutes: 30}, now: gateOpenedOn.Add(29 * time.Minute), want: bean.ReleaseTrainStageWaiting},
+		{name: "after wait time", stage: &repository.ReleaseTrainRunStage{WaitMinutes: 30}, now: gateOpenedOn.Add(30 * time.Minute), want: bean.ReleaseTrainStageDeploying},
+		{name: "apps healthy", stage: &repository.ReleaseTrainRunStage{RequireHealthy: true}, now: gateOpenedOn, want: bean.ReleaseTrainStageDeploying},
+		{name: "apps unhealthy within timeout", stage: &repository.ReleaseTrainRunStage{RequireHealthy: true, HealthyTimeoutMinutes: 10},
+			unhealthyApps: []string{"payments"}, now: gateOpenedOn.Add(10 * time.Minute), want: bean.ReleaseTrainStageWaiting},
+		{name: "apps unhealthy after timeout", stage: &repository.ReleaseTrainRunStage{RequireHealthy: true, HealthyTimeoutMinutes: 10},
+			unhealthyApps: []string{"payments"}, now: gateOpenedOn.Add(11 * time.Minute), want: bean.ReleaseTrainStageFailed},
+		{name: "apps unhealthy, default timeout", stage: &repository.ReleaseTrainRunStage{RequireHealthy: true},
+			unhealthyApps: []string{"payments"}, now: gateOpenedOn.Add(20 * time.Minute), want: bean.ReleaseTrainStageWaiting},
+		{name: "apps unhealthy, timeout counted from resume", stage: &repository.ReleaseTrainRunStage{RequireHealthy: true, HealthyTimeoutMinutes: 10, GateStartedOn: &resumedOn},
+			unhealthyApps: []string{"payments"}, now: resumedOn.Add(5 * time.Minute), want: bean.ReleaseTrainStageWaiting},
+		{name: "not approved", stage: &repository.ReleaseTrainRunStage{RequireApproval: true}, now: gateOpenedOn, want: bean.ReleaseTrainStageAwaitingApproval},
+		{name: "approved", stage: &repository.ReleaseTrainRunStage{RequireApproval: true, ApprovedOn: &approvedOn}, now: gateOpenedOn, want: bean.ReleaseTrainStageDeploying},
+		{name: "approved, within wait time", stage: &repository.ReleaseTrainRunStage{WaitMinutes: 30, RequireApproval: true, ApprovedOn: &approvedOn},
+			now: gateOpenedOn.Add(time.Minute), want: bean.ReleaseTrainStageWaiting},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := EvaluateStageGate(tt.stage, gateOpenedOn, tt.unhealthyApps, tt.now)
+			assert.Equal(t, tt.want, result.Status)
+		})
+	}
+}
+
+func TestGetDeploymentStatus(t *testing.T) {
+	deployRunner := func(status string) *pipelineConfig.CdWorkflowRunner {
+		return &pipelineConfig.CdWorkflowRunner{WorkflowType: apiBean.CD_WORKFLOW_TYPE_DEPLOY, Status: status}
+	}
+	tests := []struct {
+		name           string
+		workflowStatus cdWorkflow.WorkflowStatus
+		runners        []*pipelineConfig.CdWorkflowRunner
+		want           bean.ReleaseTrainDeploymentStatus
+	}{
+		{name: "not picked yet", workflowStatus: cdWorkflow.WF_STARTED, want: bean.ReleaseTrainDeploymentTriggered},
+		{name: "trigger error", workflowStatus: cdWorkflow.TRIGGER_ERROR, want: bean.ReleaseTrainDeploymentFailed},
+		{name: "deploying", workflowStatus: cdWorkflow.WF_STARTED, runners: []*pipelineConfig.CdWorkflowRunner{deployRunner(cdWorkflow.WorkflowInProgress)},
+			want: bean.ReleaseTrainDeploymentTriggered},
+		{name: "deploy succeeded", workflowStatus: cdWorkflow.WF_STARTED, runners: []*pipelineConfig.CdWorkflowRunner{deployRunner(cdWorkflow.WorkflowSucceeded)},
+			want: bean.ReleaseTrainDeploymentSucceeded},
+		{name: "deploy failed", workflowStatus: cdWorkflow.WF_STARTED, runners: []*pipelineConfig.CdWorkflowRunner{deployRunner(cdWorkflow.WorkflowFailed)},
+			want: bean.ReleaseTrainDeploymentFailed},
+		{name: "pre deployment failed", workflowStatus: cdWorkflow.WF_STARTED,
+			runners: []*pipelineConfig.CdWorkflowRunner{{WorkflowType: apiBean.CD_WORKFLOW_TYPE_PRE, Status: cdWorkflow.WorkflowFailed}},
+			want:    bean.ReleaseTrainDeploymentFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _ := GetDeploymentStatus(tt.workflowStatus, tt.runners)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestValidateRunArtifacts(t *testing.T) {
+	appIds := []int{1, 2}
+	assert.NoError(t, validateRunArtifacts(appIds, []*bean.ReleaseTrainArtifact{{AppId: 1, CiArtifactId: 10}, {AppId: 2, CiArtifactId: 20}}))
+	assert.Error(t, validateRunArtifacts(appIds, []*bean.ReleaseTrainArtifact{{AppId: 1, CiArtifactId: 10}}))
+	assert.Error(t, validateRunArtifacts(appIds, []*bean.ReleaseTrainArtifact{{AppId: 1, CiArtifactId: 10}, {AppId: 1, CiArtifactId: 11}}))
+	assert.Error(t, validateRunArtifacts(appIds, []*bean.ReleaseTrainArtifact{{AppId: 1, CiArtifactId: 10}, {AppId: 2, CiArtifactId: 20}, {AppId: 3, CiArtifactId: 30}}))
+}
@@ -0,0 +1,142 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+type ReleaseTrain struct {
+	tableName   struct{} `sql:"release_train" pg:",discard_unknown_columns"`
+	Id          int      `sql:"id,pk"`
+	Name        string   `sql:"name,notnull"`
+	Description string   `sql:"description"`
+	AppIds      []int    `sql:"app_ids,array"`
+	Active      bool     `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+type ReleaseTrainStage struct {
+	tableName             struct{} `sql:"release_train_stage" pg:",discard_unknown_columns"`
+	Id                    int      `sql:"id,pk"`
+	ReleaseTrainId        int      `sql:"release_train_id,notnull"`
+	Name                  string   `sql:"name,notnull"`
+	StageOrder            int      `sql:"stage_order,notnull"`
+	EnvironmentId         int      `sql:"environment_id,notnull"`
+	WaitMinutes           int      `sql:"wait_minutes,notnull"`
+	RequireHealthy        bool     `sql:"require_healthy,notnull"`
+	HealthyTimeoutMinutes int      `sql:"healthy_timeout_minutes,notnull"`
+	RequireApproval       bool     `sql:"require_approval,notnull"`
+	Active                bool     `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+type ReleaseTrainRepository interface {
+	//transaction util funcs
+	sql.TransactionWrapper
+	Save(train *ReleaseTrain, tx *pg.Tx) error
+	Update(train *ReleaseTrain, tx *pg.Tx) error
+	FindById(id int) (*ReleaseTrain, error)
+	FindByName(name string) (*ReleaseTrain, error)
+	FindAll() ([]*ReleaseTrain, error)
+
+	SaveStages(stages []*ReleaseTrainStage, tx *pg.Tx) error
+	DeactivateStages(releaseTrainId int, userId int32, tx *pg.Tx) error
+	// FindStagesByReleaseTrainId returns the active stages of the train in their order
+	FindStagesByReleaseTrainId(releaseTrainId int) ([]*ReleaseTrainStage, error)
+}
+
+type ReleaseTrainRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+	*sql.TransactionUtilImpl
+}
+
+func NewReleaseTrainRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger, transactionUtilImpl *sql.TransactionUtilImpl) *ReleaseTrainRepositoryImpl {
+	return &ReleaseTrainRepositoryImpl{
+		dbConnection:        dbConnection,
+		logger:              logger,
+		TransactionUtilImpl: transactionUtilImpl,
+	}
+}
+
+func (repo *ReleaseTrainRepositoryImpl) Save(train *ReleaseTrain, tx *pg.Tx) error {
+	return tx.Insert(train)
+}
+
+func (repo *ReleaseTrainRepositoryImpl) Update(train *ReleaseTrain, tx *pg.Tx) error {
+	return tx.Update(train)
+}
+
+func (repo *ReleaseTrainRepositoryImpl) FindById(id int) (*ReleaseTrain, error) {
+	train := &ReleaseTrain{}
+	err := repo.dbConnection.Model(train).
+		Where("id = ?", id).
+		Where("active = ?", true).
+		Select()
+	return train, err
+}
+
+func (repo *ReleaseTrainRepositoryImpl) FindByName(name string) (*ReleaseTrain, error) {
+	train := &ReleaseTrain{}
+	err := repo.dbConnection.Model(train).
+		Where("name = ?", name).
+		Where("active = ?", true).
+		Select()
+	return train, err
+}
+
+func (repo *ReleaseTrainRepositoryImpl) FindAll() ([]*ReleaseTrain, error) {
+	var trains []*ReleaseTrain
+	err := repo.dbConnection.Model(&trains).
+		Where("active = ?", true).
+		Order("name ASC").
+		Select()
+	return trains, err
+}
+
+func (repo *ReleaseTrainRepositoryImpl) SaveStages(stages []*ReleaseTrainStage, tx *pg.Tx) error {
+	if len(stages) == 0 {
+		return nil
+	}
+	_, err := tx.Model(&stages).Insert()
+	return err
+}
+
+func (repo *ReleaseTrainRepositoryImpl) DeactivateStages(releaseTrainId int, userId int32, tx *pg.Tx) error {
+	_, err := tx.Model(&ReleaseTrainStage{}).
+		Set("active = ?", false).
+		Set("updated_on = ?", time.Now()).
+		Set("updated_by = ?", userId).
+		Where("release_train_id = ?", releaseTrainId).
+		Where("active = ?", true).
+		Update()
+	return err
+}
+
+func (repo *ReleaseTrainRepositoryImpl) FindStagesByReleaseTrainId(releaseTrainId int) ([]*ReleaseTrainStage, error) {
+	var stages []*ReleaseTrainStage
+	err := repo.dbConnection.Model(&stages).
+		Where("release_train_id = ?", releaseTrainId).
+		Where("active = ?", true).
+		Order("stage_order ASC").
+		Select()
+	return stages, err
+}
@@ -0,0 +1,250 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+// ReleaseTrainRun is a run of a release train, Artifacts is the json of the artifact released for each app.
+// The whole state of the run is persisted so that it is resumed by any replica.
+type ReleaseTrainRun struct {
+	tableName      struct{}   `sql:"release_train_run" pg:",discard_unknown_columns"`
+	Id             int        `sql:"id,pk"`
+	ReleaseTrainId int        `sql:"release_train_id,notnull"`
+	Status         string     `sql:"status,notnull"`
+	Message        string     `sql:"message"`
+	Artifacts      string     `sql:"artifacts,notnull"`
+	StartedOn      time.Time  `sql:"started_on,notnull"`
+	FinishedOn     *time.Time `sql:"finished_on"`
+	sql.AuditLog
+}
+
+// ReleaseTrainRunStage is a stage of a run, the stage of the train is copied when the run starts so that
+// editing the train does not impact the runs in progress
+type ReleaseTrainRunStage struct {
+	tableName             struct{}   `sql:"release_train_run_stage" pg:",discard_unknown_columns"`
+	Id                    int        `sql:"id,pk"`
+	RunId                 int        `sql:"run_id,notnull"`
+	StageOrder            int        `sql:"stage_order,notnull"`
+	Name                  string     `sql:"name,notnull"`
+	EnvironmentId         int        `sql:"environment_id,notnull"`
+	WaitMinutes           int        `sql:"wait_minutes,notnull"`
+	RequireHealthy        bool       `sql:"require_healthy,notnull"`
+	HealthyTimeoutMinutes int        `sql:"healthy_timeout_minutes,notnull"`
+	RequireApproval       bool       `sql:"require_approval,notnull"`
+	Status                string     `sql:"status,notnull"`
+	Message               string     `sql:"message"`
+	GateStartedOn         *time.Time `sql:"gate_started_on"`
+	ApprovedBy            int32      `sql:"approved_by"`
+	ApprovedOn            *time.Time `sql:"approved_on"`
+	StartedOn             *time.Time `sql:"started_on"`
+	FinishedOn            *time.Time `sql:"finished_on"`
+	sql.AuditLog
+}
+
+type ReleaseTrainRunDeployment struct {
+	tableName    struct{} `sql:"release_train_run_deployment" pg:",discard_unknown_columns"`
+	Id           int      `sql:"id,pk"`
+	RunStageId   int      `sql:"run_stage_id,notnull"`
+	AppId        int      `sql:"app_id,notnull"`
+	PipelineId   int      `sql:"pipeline_id,notnull"`
+	CiArtifactId int      `sql:"ci_artifact_id,notnull"`
+	CdWorkflowId int      `sql:"cd_workflow_id"`
+	Status       string   `sql:"status,notnull"`
+	Message      string   `sql:"message"`
+	sql.AuditLog
+}
+
+type ReleaseTrainRunRepository interface {
+	//transaction util funcs
+	sql.TransactionWrapper
+	SaveRun(run *ReleaseTrainRun, tx *pg.Tx) error
+	UpdateRun(run *ReleaseTrainRun) error
+	// UpdateRunStatus moves the run to status only if it is in one of fromStatuses, returns false if it was not.
+	// finishedOn is set only if it is not nil.
+	UpdateRunStatus(id int, fromStatuses []string, status string, message string, finishedOn *time.Time, userId int32) (bool, error)
+	FindRunById(id int) (*ReleaseTrainRun, error)
+	FindRunsByStatus(status string) ([]*ReleaseTrainRun, error)
+	FindRunsByReleaseTrainId(releaseTrainId int, offset, limit int) ([]*ReleaseTrainRun, error)
+	// FindUnfinishedRunByReleaseTrainId returns the run of the train which is running or halted
+	FindUnfinishedRunByReleaseTrainId(releaseTrainId int, unfinishedStatuses []string) (*ReleaseTrainRun, error)
+
+	SaveRunStages(stages []*ReleaseTrainRunStage, tx *pg.Tx) error
+	UpdateRunStage(stage *ReleaseTrainRunStage) error
+	// UpdateRunStageStatus moves the stage to status only if it is in one of fromStatuses, returns false if it was not.
+	// Used for claiming the transitions of a stage so that the same stage is not deployed twice by different replicas.
+	UpdateRunStageStatus(id int, fromStatuses []string, status string, userId int32) (bool, error)
+	FindRunStageById(id int) (*ReleaseTrainRunStage, error)
+	FindRunStagesByRunId(runId int) ([]*ReleaseTrainRunStage, error)
+
+	SaveDeployments(deployments []*ReleaseTrainRunDeployment) error
+	UpdateDeployment(deployment *ReleaseTrainRunDeployment) error
+	FindDeploymentsByRunStageIds(runStageIds []int) ([]*ReleaseTrainRunDeployment, error)
+}
+
+type ReleaseTrainRunRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+	*sql.TransactionUtilImpl
+}
+
+func NewReleaseTrainRunRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger, transactionUtilImpl *sql.TransactionUtilImpl) *ReleaseTrainRunRepositoryImpl {
+	return &ReleaseTrainRunRepositoryImpl{
+		dbConnection:        dbConnection,
+		logger:              logger,
+		TransactionUtilImpl: transactionUtilImpl,
+	}
+}
+
+func (repo *ReleaseTrainRunRepositoryImpl) SaveRun(run *ReleaseTrainRun, tx *pg.Tx) error {
+	return tx.Insert(run)
+}
+
+func (repo *ReleaseTrainRunRepositoryImpl) UpdateRun(run *ReleaseTrainRun) error {
+	return repo.dbConnection.Update(run)
+}
+
+func (repo *ReleaseTrainRunRepositoryImpl) UpdateRunStatus(id int, fromStatuses []string, status string, message string, finishedOn *time.Time, userId int32) (bool, error) {
+	query := repo.dbConnection.Model(&ReleaseTrainRun{}).
+		Set("status = ?", status).
+		Set("message = ?", message).
+		Set("updated_on = ?", time.Now()).
+		Set("updated_by = ?", userId)
+	if finishedOn != nil {
+		query = query.Set("finished_on = ?", finishedOn)
+	}
+	result, err := query.
+		Where("id = ?", id).
+		Where("status IN (?)", pg.In(fromStatuses)).
+		Update()
+	if err != nil {
+		repo.logger.Errorw("error in updating release train run status", "id", id, "status", status, "err", err)
+		return false, err
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+func (repo *ReleaseTrainRunRepositoryImpl) FindRunById(id int) (*ReleaseTrainRun, error) {
+	run := &ReleaseTrainRun{}
+	err := repo.dbConnection.Model(run).
+		Where("id = ?", id).
+		Select()
+	return run, err
+}
+
+func (repo *ReleaseTrainRunRepositoryImpl) FindRunsByStatus(status string) ([]*ReleaseTrainRun, error) {
+	var runs []*ReleaseTrainRun
+	err := repo.dbConnection.Model(&runs).
+		Where("status = ?", status).
+		Order("id ASC").
+		Select()
+	return runs, err
+}
+
+func (repo *ReleaseTrainRunRepositoryImpl) FindRunsByReleaseTrainId(releaseTrainId int, offset, limit int) ([]*ReleaseTrainRun, error) {
+	var runs []*ReleaseTrainRun
+	err := repo.dbConnection.Model(&runs).
+		Where("release_train_id = ?", releaseTrainId).
+		Order("id DESC").
+		Offset(offset).
+		Limit(limit).
+		Select()
+	return runs, err
+}
+
+func (repo *ReleaseTrainRunRepositoryImpl) FindUnfinishedRunByReleaseTrainId(releaseTrainId int, unfinishedStatuses []string) (*ReleaseTrainRun, error) {
+	run := &ReleaseTrainRun{}
+	err := repo.dbConnection.Model(run).
+		Where("release_train_id = ?", releaseTrainId).
+		Where("status IN (?)", pg.In(unfinishedStatuses)).
+		Order("id DESC").
+		Limit(1).
+		Select()
+	return run, err
+}
+
+func (repo *ReleaseTrainRunRepositoryImpl) SaveRunStages(stages []*ReleaseTrainRunStage, tx *pg.Tx) error {
+	if len(stages) == 0 {
+		return nil
+	}
+	_, err := tx.Model(&stages).Insert()
+	return err
+}
+
+func (repo *ReleaseTrainRunRepositoryImpl) UpdateRunStage(stage *ReleaseTrainRunStage) error {
+	return repo.dbConnection.Update(stage)
+}
+
+func (repo *ReleaseTrainRunRepositoryImpl) UpdateRunStageStatus(id int, fromStatuses []string, status string, userId int32) (bool, error) {
+	result, err := repo.dbConnection.Model(&ReleaseTrainRunStage{}).
+		Set("status = ?", status).
+		Set("updated_on = ?", time.Now()).
+		Set("updated_by = ?", userId).
+		Where("id = ?", id).
+		Where("status IN (?)", pg.In(fromStatuses)).
+		Update()
+	if err != nil {
+		repo.logger.Errorw("error in updating release train run stage status", "id", id, "status", status, "err", err)
+		return false, err
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+func (repo *ReleaseTrainRunRepositoryImpl) FindRunStageById(id int) (*ReleaseTrainRunStage, error) {
+	stage := &ReleaseTrainRunStage{}
+	err := repo.dbConnection.Model(stage).
+		Where("id = ?", id).
+		Select()
+	return stage, err
+}
+
+func (repo *ReleaseTrainRunRepositoryImpl) FindRunStagesByRunId(runId int) ([]*ReleaseTrainRunStage, error) {
+	var stages []*ReleaseTrainRunStage
+	err := repo.dbConnection.Model(&stages).
+		Where("run_id = ?", runId).
+		Order("stage_order ASC").
+		Select()
+	return stages, err
+}
+
+func (repo *ReleaseTrainRunRepositoryImpl) SaveDeployments(deployments []*ReleaseTrainRunDeployment) error {
+	if len(deployments) == 0 {
+		return nil
+	}
+	_, err := repo.dbConnection.Model(&deployments).Insert()
+	return err
+}
+
+func (repo *ReleaseTrainRunRepositoryImpl) UpdateDeployment(deployment *ReleaseTrainRunDeployment) error {
+	return repo.dbConnection.Update(deployment)
+}
+
+func (repo *ReleaseTrainRunRepositoryImpl) FindDeploymentsByRunStageIds(runStageIds []int) ([]*ReleaseTrainRunDeployment, error) {
+	var deployments []*ReleaseTrainRunDeployment
+	if len(runStageIds) == 0 {
+		return deployments, nil
+	}
+	err := repo.dbConnection.Model(&deployments).
+		Where("run_stage_id IN (?)", pg.In(runStageIds)).
+		Order("id ASC").
+		Select()
+	return deployments, err
+}
@@ -28,6 +28,7 @@ const (
 	InfraProfile                       = 3
 	ImagePromotionPolicy  ResourceType = 4
 	DeploymentWindow      ResourceType = 5
+	CelPolicy             ResourceType = 6
 )
 
 type ResourceQualifierMappings struct {
@@ -65,6 +66,7 @@ type SelectionIdentifier struct {
 	AppId                   int                      `json:"appId"`
 	EnvId                   int                      `json:"envId"`
 	ClusterId               int                      `json:"clusterId"`
+	ProjectId               int                      `json:"projectId"`
 	SelectionIdentifierName *SelectionIdentifierName `json:"-"`
 }
 
@@ -72,6 +74,7 @@ type SelectionIdentifierName struct {
 	AppName         string
 	EnvironmentName string
 	ClusterName     string
+	ProjectName     string
 }
 
 func (mapping *QualifierMapping) GetIdValueAndName() (int, string) {
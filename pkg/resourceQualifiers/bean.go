@@ -60,6 +60,7 @@ const (
 	CLUSTER_QUALIFIER     Qualifier = 4
 	GLOBAL_QUALIFIER      Qualifier = 5
 	PIPELINE_QUALIFIER    Qualifier = 6
+	PROJECT_QUALIFIER     Qualifier = 7
 )
 
 var CompoundQualifiers []Qualifier
@@ -31,6 +31,7 @@ const (
 	ClusterSelector                QualifierSelector = 2
 	ApplicationEnvironmentSelector QualifierSelector = 3
 	GlobalSelector                 QualifierSelector = 4
+	ProjectSelector                QualifierSelector = 5
 )
 
 func (selector QualifierSelector) isCompound() bool {
@@ -49,6 +50,8 @@ func (selector QualifierSelector) toQualifier() Qualifier {
 		return APP_AND_ENV_QUALIFIER
 	case GlobalSelector:
 		return GLOBAL_QUALIFIER
+	case ProjectSelector:
+		return PROJECT_QUALIFIER
 	}
 	return Qualifier(0)
 }
@@ -61,6 +64,8 @@ func GetIdentifierKey(selector QualifierSelector, searchableKeyNameIdMap map[bea
 		return searchableKeyNameIdMap[bean.DEVTRON_RESOURCE_SEARCHABLE_KEY_CLUSTER_ID]
 	case EnvironmentSelector:
 		return searchableKeyNameIdMap[bean.DEVTRON_RESOURCE_SEARCHABLE_KEY_ENV_ID]
+	case ProjectSelector:
+		return searchableKeyNameIdMap[bean.DEVTRON_RESOURCE_SEARCHABLE_KEY_PROJECT_ID]
 	default:
 		return 0
 	}
@@ -80,6 +85,8 @@ func GetSelectorFromKey(key int, searchableKeyIdNameMap map[int]bean.DevtronReso
 		return ClusterSelector
 	case bean.DEVTRON_RESOURCE_SEARCHABLE_KEY_ENV_ID:
 		return EnvironmentSelector
+	case bean.DEVTRON_RESOURCE_SEARCHABLE_KEY_PROJECT_ID:
+		return ProjectSelector
 	default:
 		return 0
 	}
@@ -97,6 +104,8 @@ func GetQualifierIdForSelector(selector QualifierSelector) Qualifier {
 		return CLUSTER_QUALIFIER
 	case GlobalSelector:
 		return GLOBAL_QUALIFIER
+	case ProjectSelector:
+		return PROJECT_QUALIFIER
 	default:
 		return 0
 	}
@@ -116,6 +125,8 @@ func GetValuesFromSelectionIdentifier(selector QualifierSelector, selectionIdent
 		return selectionIdentifier.EnvId, selectionIdentifier.SelectionIdentifierName.EnvironmentName
 	case ClusterSelector:
 		return selectionIdentifier.ClusterId, selectionIdentifier.SelectionIdentifierName.ClusterName
+	case ProjectSelector:
+		return selectionIdentifier.ProjectId, selectionIdentifier.SelectionIdentifierName.ProjectName
 	default:
 		return 0, ""
 	}
@@ -0,0 +1,243 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package recording
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/devtron-labs/devtron/pkg/terminal/recording/bean"
+	"sync"
+	"time"
+)
+
+// chunkSize is the size of the recorded events after which they are handed over to the writer
+const chunkSize = 64 * 1024
+
+// recordingWriter stores a recording while the session runs. The calls for a recorder are made in order from
+// a single goroutine, so a slow storage does not block the terminal session.
+type recordingWriter interface {
+	// Create stores the recording when the session starts
+	Create(recorder *SessionRecorder) error
+	// WriteChunk appends the events, one asciicast event per line, to the recording
+	WriteChunk(recorder *SessionRecorder, chunkIndex int, events []byte) error
+	// Finish stores the end of the recording
+	Finish(recorder *SessionRecorder) error
+}
+
+// SessionRecorder records the events of a terminal session in asciicast v2 format and streams them to the writer
+// in chunks, every chunkSize of events or every flushInterval. Once maxSize of events is recorded, the recording is
+// marked as truncated and the rest of the session is not recorded.
+// All the methods are safe to call on a nil recorder, a nil recorder records nothing.
+type SessionRecorder struct {
+	metadata      *bean.RecordingMetadata
+	maxSize       int
+	flushInterval time.Duration
+	writer        recordingWriter
+	lock          sync.Mutex
+	startedOn     time.Time
+	endedOn       time.Time
+	width         int
+	height        int
+	// events are the recorded events not yet handed over to the writer
+	events    bytes.Buffer
+	pending   [][]byte
+	size      int
+	truncated bool
+	finished  bool
+	notify    chan struct{}
+	done      chan struct{}
+}
+
+func NewSessionRecorder(metadata *bean.RecordingMetadata, maxSize int, flushInterval time.Duration, writer recordingWriter) *SessionRecorder {
+	return &SessionRecorder{
+		metadata:      metadata,
+		maxSize:       maxSize,
+		flushInterval: flushInterval,
+		writer:        writer,
+		notify:        make(chan struct{}, 1),
+		done:          make(chan struct{}),
+	}
+}
+
+// Start marks the start of the session, the time of the events is relative to it. Only the first call is considered
+// as the shell is started again for each shell being tried.
+func (r *SessionRecorder) Start(startedOn time.Time) {
+	if r == nil {
+		return
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if !r.startedOn.IsZero() || r.finished {
+		return
+	}
+	r.startedOn = startedOn
+	go r.run()
+}
+
+func (r *SessionRecorder) RecordOutput(data string) {
+	r.record(bean.AsciicastOutputEvent, data, time.Now())
+}
+
+func (r *SessionRecorder) RecordInput(data string) {
+	r.record(bean.AsciicastInputEvent, data, time.Now())
+}
+
+// RecordResize records the resize of the terminal, the first size is used as the size of the recording
+func (r *SessionRecorder) RecordResize(cols, rows uint16) {
+	if r == nil {
+		return
+	}
+	r.lock.Lock()
+	if r.width == 0 && r.height == 0 {
+		r.width, r.height = int(cols), int(rows)
+	}
+	r.lock.Unlock()
+	r.record(bean.AsciicastResizeEvent, fmt.Sprintf("%dx%d", cols, rows), time.Now())
+}
+
+func (r *SessionRecorder) record(eventType, data string, at time.Time) {
+	if r == nil {
+		return
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.startedOn.IsZero() || r.finished || r.truncated {
+		return
+	}
+	event, err := json.Marshal([]interface{}{at.Sub(r.startedOn).Seconds(), eventType, data})
+	if err != nil {
+		return
+	}
+	if r.maxSize > 0 && r.size+len(event)+1 > r.maxSize {
+		r.truncated = true
+		event, _ = json.Marshal([]interface{}{at.Sub(r.startedOn).Seconds(), bean.AsciicastOutputEvent, bean.RecordingTruncatedNote})
+	}
+	r.events.Write(event)
+	r.events.WriteByte('\n')
+	r.size += len(event) + 1
+	// the truncation is handed over right away so that the stored recording is marked as truncated
+	if r.events.Len() >= chunkSize || r.truncated {
+		r.handOverEvents()
+		r.notifyWriter()
+	}
+}
+
+// Finish ends the recording, the remaining events are handed over to the writer
+func (r *SessionRecorder) Finish(endedOn time.Time) {
+	if r == nil {
+		return
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.finished {
+		return
+	}
+	r.finished = true
+	if r.startedOn.IsZero() {
+		return
+	}
+	r.endedOn = endedOn
+	r.handOverEvents()
+	r.notifyWriter()
+}
+
+// handOverEvents moves the recorded events to the pending chunks, the lock must be held by the caller
+func (r *SessionRecorder) handOverEvents() {
+	if r.events.Len() == 0 {
+		return
+	}
+	chunk := make([]byte, r.events.Len())
+	copy(chunk, r.events.Bytes())
+	r.pending = append(r.pending, chunk)
+	r.events.Reset()
+}
+
+func (r *SessionRecorder) notifyWriter() {
+	select {
+	case r.notify <- struct{}{}:
+	default:
+	}
+}
+
+// run writes the pending chunks until the recording is finished, the recording is not written any further if it
+// could not be created
+func (r *SessionRecorder) run() {
+	defer close(r.done)
+	created := r.writer.Create(r) == nil
+	ticker := time.NewTicker(r.flushInterval)
+	defer ticker.Stop()
+	chunkIndex := 0
+	for {
+		select {
+		case <-r.notify:
+		case <-ticker.C:
+		}
+		r.lock.Lock()
+		r.handOverEvents()
+		chunks, finished := r.pending, r.finished
+		r.pending = nil
+		r.lock.Unlock()
+		for _, chunk := range chunks {
+			if created {
+				// a chunk failing to be written is skipped, the events of the other chunks are still replayable
+				_ = r.writer.WriteChunk(r, chunkIndex, chunk)
+			}
+			chunkIndex++
+		}
+		if finished {
+			if created {
+				_ = r.writer.Finish(r)
+			}
+			return
+		}
+	}
+}
+
+func (r *SessionRecorder) Metadata() *bean.RecordingMetadata {
+	return r.metadata
+}
+
+func (r *SessionRecorder) StartedOn() time.Time {
+	return r.startedOn
+}
+
+func (r *SessionRecorder) EndedOn() time.Time {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.endedOn
+}
+
+// TerminalSize returns the first size of the terminal, zero if the terminal was never resized
+func (r *SessionRecorder) TerminalSize() (width int, height int) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.width, r.height
+}
+
+// Size returns the size of the events recorded so far
+func (r *SessionRecorder) Size() int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.size
+}
+
+func (r *SessionRecorder) IsTruncated() bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.truncated
+}
@@ -0,0 +1,153 @@
+package recording
+
+import (
+	"encoding/json"
+	"github.com/devtron-labs/devtron/pkg/terminal/recording/bean"
+	"github.com/devtron-labs/devtron/pkg/terminal/recording/repository"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type recordingWriterStub struct {
+	lock       sync.Mutex
+	created    bool
+	chunks     []string
+	finished   bool
+	truncated  bool
+	createErr  error
+	chunkIndex []int
+}
+
+func (w *recordingWriterStub) Create(recorder *SessionRecorder) error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.created = w.createErr == nil
+	return w.createErr
+}
+
+func (w *recordingWriterStub) WriteChunk(recorder *SessionRecorder, chunkIndex int, events []byte) error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.chunks = append(w.chunks, string(events))
+	w.chunkIndex = append(w.chunkIndex, chunkIndex)
+	w.truncated = recorder.IsTruncated()
+	return nil
+}
+
+func (w *recordingWriterStub) Finish(recorder *SessionRecorder) error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.finished = true
+	return nil
+}
+
+func (w *recordingWriterStub) events() string {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return strings.Join(w.chunks, "")
+}
+
+func TestSessionRecorder(t *testing.T) {
+	metadata := &bean.RecordingMetadata{SessionId: "abc", Namespace: "default", PodName: "nginx", Shell: "bash"}
+	startedOn := time.Now()
+
+	t.Run("asciicast v2 events", func(t *testing.T) {
+		writer := &recordingWriterStub{}
+		recorder := NewSessionRecorder(metadata, 0, time.Hour, writer)
+		recorder.RecordOutput("ignored before start")
+		recorder.Start(startedOn)
+		recorder.RecordResize(120, 40)
+		recorder.RecordInput("ls\r")
+		recorder.RecordOutput("file\r\n")
+		recorder.Finish(startedOn.Add(time.Minute))
+		recorder.RecordOutput("ignored after finish")
+		<-recorder.done
+		assert.True(t, writer.created)
+		assert.True(t, writer.finished)
+		width, height := recorder.TerminalSize()
+		assert.Equal(t, 120, width)
+		assert.Equal(t, 40, height)
+
+		lines := strings.Split(strings.TrimSuffix(writer.events(), "\n"), "\n")
+		assert.Len(t, lines, 3)
+		for i, want := range [][2]string{{"r", "120x40"}, {"i", "ls\r"}, {"o", "file\r\n"}} {
+			var event []interface{}
+			assert.NoError(t, json.Unmarshal([]byte(lines[i]), &event))
+			assert.Equal(t, want[0], event[1])
+			assert.Equal(t, want[1], event[2])
+		}
+	})
+	t.Run("events streamed while the session runs", func(t *testing.T) {
+		writer := &recordingWriterStub{}
+		recorder := NewSessionRecorder(metadata, 0, 10*time.Millisecond, writer)
+		recorder.Start(startedOn)
+		recorder.RecordOutput("first")
+		assert.Eventually(t, func() bool { return strings.Contains(writer.events(), "first") }, time.Second, 5*time.Millisecond)
+		recorder.RecordOutput(strings.Repeat("x", chunkSize))
+		recorder.Finish(time.Now())
+		<-recorder.done
+		assert.Equal(t, []int{0, 1}, writer.chunkIndex)
+		assert.Equal(t, recorder.Size(), len(writer.events()))
+	})
+	t.Run("truncated at max size", func(t *testing.T) {
+		writer := &recordingWriterStub{}
+		recorder := NewSessionRecorder(metadata, 100, time.Hour, writer)
+		recorder.Start(startedOn)
+		for i := 0; i < 10; i++ {
+			recorder.RecordOutput("0123456789")
+		}
+		assert.True(t, recorder.IsTruncated())
+		// the truncation is written without waiting for the session to finish
+		assert.Eventually(t, func() bool {
+			return strings.HasSuffix(writer.events(), `"o","\r\n[recording truncated, size limit reached]\r\n"]`+"\n")
+		}, time.Second, 5*time.Millisecond)
+		recorder.Finish(time.Now())
+		<-recorder.done
+		assert.True(t, writer.truncated)
+	})
+	t.Run("recording not created", func(t *testing.T) {
+		writer := &recordingWriterStub{createErr: assert.AnError}
+		recorder := NewSessionRecorder(metadata, 0, time.Hour, writer)
+		recorder.Start(startedOn)
+		recorder.RecordOutput("data")
+		recorder.Finish(time.Now())
+		<-recorder.done
+		assert.Empty(t, writer.chunks)
+		assert.False(t, writer.finished)
+	})
+	t.Run("session never started", func(t *testing.T) {
+		writer := &recordingWriterStub{}
+		recorder := NewSessionRecorder(metadata, 0, time.Hour, writer)
+		recorder.Finish(time.Now())
+		recorder.Start(startedOn)
+		assert.False(t, writer.created)
+	})
+	t.Run("nil recorder", func(t *testing.T) {
+		var recorder *SessionRecorder
+		recorder.Start(startedOn)
+		recorder.RecordOutput("data")
+		recorder.Finish(time.Now())
+	})
+}
+
+func TestGetCastHeader(t *testing.T) {
+	startedOn := time.Now()
+	endedOn := startedOn.Add(time.Minute)
+	header := getCastHeader(&repository.TerminalSessionRecording{Namespace: "default", PodName: "nginx", Shell: "bash",
+		StartedOn: startedOn, EndedOn: &endedOn, Width: 120, Height: 40})
+	assert.Equal(t, 2, header.Version)
+	assert.Equal(t, 120, header.Width)
+	assert.Equal(t, 40, header.Height)
+	assert.Equal(t, startedOn.Unix(), header.Timestamp)
+	assert.Equal(t, float64(60), header.Duration)
+	assert.Equal(t, "default/nginx", header.Title)
+	assert.Equal(t, "bash", header.Env["SHELL"])
+
+	header = getCastHeader(&repository.TerminalSessionRecording{StartedOn: startedOn})
+	assert.Equal(t, bean.DefaultTerminalWidth, header.Width)
+	assert.Equal(t, bean.DefaultTerminalHeight, header.Height)
+	assert.Zero(t, header.Duration)
+}
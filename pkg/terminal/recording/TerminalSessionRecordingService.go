@@ -0,0 +1,261 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package recording
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/caarlos0/env"
+	"github.com/devtron-labs/devtron/internal/util"
+	userRepository "github.com/devtron-labs/devtron/pkg/auth/user/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/devtron-labs/devtron/pkg/terminal/recording/bean"
+	"github.com/devtron-labs/devtron/pkg/terminal/recording/repository"
+	"go.uber.org/zap"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const defaultFlushIntervalSecs = 5
+
+type TerminalSessionRecordingConfig struct {
+	MaxSizeKb         int `env:"TERMINAL_SESSION_RECORDING_MAX_SIZE_KB" envDefault:"10240" description:"Max size of a terminal session recording, the recording is marked as truncated and the rest of the session is not recorded once the limit is reached"`
+	FlushIntervalSecs int `env:"TERMINAL_SESSION_RECORDING_FLUSH_INTERVAL_SECS" envDefault:"5" description:"Interval at which the events of a running terminal session are written to the recording"`
+}
+
+type TerminalSessionRecordingService interface {
+	// NewRecorder returns the recorder for a terminal session, the recording is stored as the session runs
+	NewRecorder(metadata *bean.RecordingMetadata) *SessionRecorder
+	GetRecordings(filter *bean.RecordingFilter) ([]*bean.TerminalSessionRecordingDto, error)
+	GetRecording(id int) (*bean.TerminalSessionRecordingDto, error)
+	// GetRecordingCast returns the asciicast v2 content of the recording for replay
+	GetRecordingCast(id int) (string, error)
+}
+
+type TerminalSessionRecordingServiceImpl struct {
+	logger                             *zap.SugaredLogger
+	terminalSessionRecordingRepository repository.TerminalSessionRecordingRepository
+	userRepository                     userRepository.UserRepository
+	config                             *TerminalSessionRecordingConfig
+}
+
+func NewTerminalSessionRecordingServiceImpl(logger *zap.SugaredLogger,
+	terminalSessionRecordingRepository repository.TerminalSessionRecordingRepository,
+	userRepository userRepository.UserRepository) *TerminalSessionRecordingServiceImpl {
+	config := &TerminalSessionRecordingConfig{}
+	if err := env.Parse(config); err != nil {
+		logger.Errorw("error in parsing terminal session recording config, using the defaults", "err", err)
+		config = &TerminalSessionRecordingConfig{MaxSizeKb: 10240, FlushIntervalSecs: defaultFlushIntervalSecs}
+	}
+	if config.FlushIntervalSecs <= 0 {
+		config.FlushIntervalSecs = defaultFlushIntervalSecs
+	}
+	return &TerminalSessionRecordingServiceImpl{
+		logger:                             logger,
+		terminalSessionRecordingRepository: terminalSessionRecordingRepository,
+		userRepository:                     userRepository,
+		config:                             config,
+	}
+}
+
+func (impl *TerminalSessionRecordingServiceImpl) NewRecorder(metadata *bean.RecordingMetadata) *SessionRecorder {
+	writer := &sessionRecordingWriter{
+		logger:                             impl.logger,
+		terminalSessionRecordingRepository: impl.terminalSessionRecordingRepository,
+	}
+	return NewSessionRecorder(metadata, impl.config.MaxSizeKb*1024, time.Duration(impl.config.FlushIntervalSecs)*time.Second, writer)
+}
+
+func (impl *TerminalSessionRecordingServiceImpl) GetRecordings(filter *bean.RecordingFilter) ([]*bean.TerminalSessionRecordingDto, error) {
+	recordings, err := impl.terminalSessionRecordingRepository.FindAll(filter.UserId, filter.ClusterId, filter.Namespace, filter.PodName, filter.Offset, filter.Size)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching terminal session recordings", "filter", filter, "err", err)
+		return nil, err
+	}
+	userIds := make([]int32, 0, len(recordings))
+	for _, recording := range recordings {
+		userIds = append(userIds, recording.UserId)
+	}
+	userEmails := impl.getUserEmails(userIds)
+	recordingDtos := make([]*bean.TerminalSessionRecordingDto, 0, len(recordings))
+	for _, recording := range recordings {
+		recordingDtos = append(recordingDtos, getRecordingDto(recording, userEmails[recording.UserId]))
+	}
+	return recordingDtos, nil
+}
+
+func (impl *TerminalSessionRecordingServiceImpl) GetRecording(id int) (*bean.TerminalSessionRecordingDto, error) {
+	recording, err := impl.terminalSessionRecordingRepository.FindById(id)
+	if util.IsErrNoRows(err) {
+		return nil, util.NewApiError(http.StatusNotFound, bean.RecordingNotFoundMessage, bean.RecordingNotFoundMessage)
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching terminal session recording", "id", id, "err", err)
+		return nil, err
+	}
+	userEmails := impl.getUserEmails([]int32{recording.UserId})
+	return getRecordingDto(recording, userEmails[recording.UserId]), nil
+}
+
+func (impl *TerminalSessionRecordingServiceImpl) GetRecordingCast(id int) (string, error) {
+	recording, err := impl.terminalSessionRecordingRepository.FindById(id)
+	if util.IsErrNoRows(err) {
+		return "", util.NewApiError(http.StatusNotFound, bean.RecordingNotFoundMessage, bean.RecordingNotFoundMessage)
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching terminal session recording", "id", id, "err", err)
+		return "", err
+	}
+	chunks, err := impl.terminalSessionRecordingRepository.FindChunksByRecordingId(id)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching terminal session recording chunks", "id", id, "err", err)
+		return "", err
+	}
+	header, err := json.Marshal(getCastHeader(recording))
+	if err != nil {
+		impl.logger.Errorw("error in building terminal session recording header", "id", id, "err", err)
+		return "", err
+	}
+	return string(header) + "\n" + strings.Join(chunks, ""), nil
+}
+
+// getUserEmails returns the emails of the users, the recordings are listed without the emails if fetching them fails
+func (impl *TerminalSessionRecordingServiceImpl) getUserEmails(userIds []int32) map[int32]string {
+	userEmails := make(map[int32]string, len(userIds))
+	if len(userIds) == 0 {
+		return userEmails
+	}
+	users, err := impl.userRepository.GetByIds(userIds)
+	if err != nil {
+		impl.logger.Errorw("error in fetching users of terminal session recordings", "userIds", userIds, "err", err)
+		return userEmails
+	}
+	for _, user := range users {
+		userEmails[user.Id] = user.EmailId
+	}
+	return userEmails
+}
+
+// getCastHeader returns the asciicast v2 header of the recording, the duration is set once the session has ended
+func getCastHeader(recording *repository.TerminalSessionRecording) *bean.AsciicastHeader {
+	header := &bean.AsciicastHeader{
+		Version:   bean.AsciicastVersion,
+		Width:     recording.Width,
+		Height:    recording.Height,
+		Timestamp: recording.StartedOn.Unix(),
+		Title:     fmt.Sprintf("%s/%s", recording.Namespace, recording.PodName),
+	}
+	if header.Width == 0 || header.Height == 0 {
+		header.Width, header.Height = bean.DefaultTerminalWidth, bean.DefaultTerminalHeight
+	}
+	if recording.EndedOn != nil {
+		header.Duration = recording.EndedOn.Sub(recording.StartedOn).Seconds()
+	}
+	if len(recording.Shell) > 0 {
+		header.Env = map[string]string{"SHELL": recording.Shell}
+	}
+	return header
+}
+
+func getRecordingDto(recording *repository.TerminalSessionRecording, userEmail string) *bean.TerminalSessionRecordingDto {
+	return &bean.TerminalSessionRecordingDto{
+		Id:               recording.Id,
+		SessionId:        recording.SessionId,
+		UserId:           recording.UserId,
+		UserEmail:        userEmail,
+		ClusterId:        recording.ClusterId,
+		EnvironmentId:    recording.EnvironmentId,
+		AppId:            recording.AppId,
+		Namespace:        recording.Namespace,
+		PodName:          recording.PodName,
+		ContainerName:    recording.ContainerName,
+		TerminalAccessId: recording.TerminalAccessId,
+		StartedOn:        recording.StartedOn,
+		EndedOn:          recording.EndedOn,
+		SizeBytes:        recording.SizeBytes,
+		Truncated:        recording.Truncated,
+	}
+}
+
+// sessionRecordingWriter stores the recording of a single terminal session
+type sessionRecordingWriter struct {
+	logger                             *zap.SugaredLogger
+	terminalSessionRecordingRepository repository.TerminalSessionRecordingRepository
+	recording                          *repository.TerminalSessionRecording
+}
+
+func (w *sessionRecordingWriter) Create(recorder *SessionRecorder) error {
+	metadata := recorder.Metadata()
+	recording := &repository.TerminalSessionRecording{
+		SessionId:        metadata.SessionId,
+		UserId:           metadata.UserId,
+		ClusterId:        metadata.ClusterId,
+		EnvironmentId:    metadata.EnvironmentId,
+		AppId:            metadata.AppId,
+		Namespace:        metadata.Namespace,
+		PodName:          metadata.PodName,
+		ContainerName:    metadata.ContainerName,
+		Shell:            metadata.Shell,
+		TerminalAccessId: metadata.TerminalAccessId,
+		StartedOn:        recorder.StartedOn(),
+		AuditLog:         sql.NewDefaultAuditLog(metadata.UserId),
+	}
+	err := w.terminalSessionRecordingRepository.Save(recording)
+	if err != nil {
+		w.logger.Errorw("error in saving terminal session recording, session is not recorded", "sessionId", metadata.SessionId, "err", err)
+		return err
+	}
+	w.recording = recording
+	return nil
+}
+
+func (w *sessionRecordingWriter) WriteChunk(recorder *SessionRecorder, chunkIndex int, events []byte) error {
+	chunk := &repository.TerminalSessionRecordingChunk{
+		RecordingId: w.recording.Id,
+		ChunkIndex:  chunkIndex,
+		Events:      string(events),
+	}
+	err := w.terminalSessionRecordingRepository.SaveChunk(chunk)
+	if err != nil {
+		w.logger.Errorw("error in saving terminal session recording chunk", "recordingId", w.recording.Id, "chunkIndex", chunkIndex, "err", err)
+		return err
+	}
+	// the size and the truncation are kept up to date so that the recording of a running session is listed correctly
+	return w.updateRecording(recorder)
+}
+
+func (w *sessionRecordingWriter) Finish(recorder *SessionRecorder) error {
+	endedOn := recorder.EndedOn()
+	w.recording.EndedOn = &endedOn
+	err := w.updateRecording(recorder)
+	if err != nil {
+		return err
+	}
+	w.logger.Infow("terminal session recording saved", "sessionId", w.recording.SessionId, "recordingId", w.recording.Id, "sizeBytes", w.recording.SizeBytes)
+	return nil
+}
+
+func (w *sessionRecordingWriter) updateRecording(recorder *SessionRecorder) error {
+	w.recording.Width, w.recording.Height = recorder.TerminalSize()
+	w.recording.SizeBytes = recorder.Size()
+	w.recording.Truncated = recorder.IsTruncated()
+	w.recording.UpdatedOn = time.Now()
+	err := w.terminalSessionRecordingRepository.Update(w.recording)
+	if err != nil {
+		w.logger.Errorw("error in updating terminal session recording", "recordingId", w.recording.Id, "err", err)
+	}
+	return err
+}
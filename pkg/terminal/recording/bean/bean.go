@@ -0,0 +1,85 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import "time"
+
+const (
+	AsciicastVersion       = 2
+	AsciicastContentType   = "application/x-asciicast"
+	AsciicastOutputEvent   = "o"
+	AsciicastInputEvent    = "i"
+	AsciicastResizeEvent   = "r"
+	DefaultTerminalWidth   = 80
+	DefaultTerminalHeight  = 24
+	RecordingTruncatedNote = "\r\n[recording truncated, size limit reached]\r\n"
+)
+
+const RecordingNotFoundMessage = "terminal session recording not found"
+
+// RecordingMetadata identifies the terminal session being recorded
+type RecordingMetadata struct {
+	SessionId     string
+	UserId        int32
+	ClusterId     int
+	EnvironmentId int
+	AppId         int
+	Namespace     string
+	PodName       string
+	ContainerName string
+	Shell         string
+	// TerminalAccessId is set for the sessions of the cluster terminal (user terminal access pods)
+	TerminalAccessId int
+}
+
+// AsciicastHeader is the first line of an asciicast v2 recording
+type AsciicastHeader struct {
+	Version   int               `json:"version"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp"`
+	Duration  float64           `json:"duration,omitempty"`
+	Title     string            `json:"title,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+type RecordingFilter struct {
+	UserId    int32
+	ClusterId int
+	Namespace string
+	PodName   string
+	Offset    int
+	Size      int
+}
+
+type TerminalSessionRecordingDto struct {
+	Id               int        `json:"id"`
+	SessionId        string     `json:"sessionId"`
+	UserId           int32      `json:"userId"`
+	UserEmail        string     `json:"userEmail,omitempty"`
+	ClusterId        int        `json:"clusterId"`
+	EnvironmentId    int        `json:"environmentId,omitempty"`
+	AppId            int        `json:"appId,omitempty"`
+	Namespace        string     `json:"namespace"`
+	PodName          string     `json:"podName"`
+	ContainerName    string     `json:"containerName,omitempty"`
+	TerminalAccessId int        `json:"terminalAccessId,omitempty"`
+	StartedOn        time.Time  `json:"startedOn"`
+	EndedOn          *time.Time `json:"endedOn,omitempty"`
+	SizeBytes        int        `json:"sizeBytes"`
+	Truncated        bool       `json:"truncated"`
+}
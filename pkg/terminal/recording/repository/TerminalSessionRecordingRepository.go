@@ -0,0 +1,133 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+// TerminalSessionRecording is the recording of a terminal session, the events of the session are stored in
+// TerminalSessionRecordingChunk as the session runs
+type TerminalSessionRecording struct {
+	tableName        struct{}   `sql:"terminal_session_recording" pg:",discard_unknown_columns"`
+	Id               int        `sql:"id,pk"`
+	SessionId        string     `sql:"session_id,notnull"`
+	UserId           int32      `sql:"user_id,notnull"`
+	ClusterId        int        `sql:"cluster_id"`
+	EnvironmentId    int        `sql:"environment_id"`
+	AppId            int        `sql:"app_id"`
+	Namespace        string     `sql:"namespace"`
+	PodName          string     `sql:"pod_name"`
+	ContainerName    string     `sql:"container_name"`
+	Shell            string     `sql:"shell"`
+	TerminalAccessId int        `sql:"terminal_access_id"`
+	StartedOn        time.Time  `sql:"started_on,notnull"`
+	EndedOn          *time.Time `sql:"ended_on"`
+	SizeBytes        int        `sql:"size_bytes,notnull"`
+	Truncated        bool       `sql:"truncated,notnull"`
+	Width            int        `sql:"width"`
+	Height           int        `sql:"height"`
+	sql.AuditLog
+}
+
+// TerminalSessionRecordingChunk holds the asciicast v2 events of a recording, one event per line
+type TerminalSessionRecordingChunk struct {
+	tableName   struct{} `sql:"terminal_session_recording_chunk" pg:",discard_unknown_columns"`
+	Id          int      `sql:"id,pk"`
+	RecordingId int      `sql:"recording_id,notnull"`
+	ChunkIndex  int      `sql:"chunk_index,notnull"`
+	Events      string   `sql:"events,notnull"`
+}
+
+type TerminalSessionRecordingRepository interface {
+	Save(recording *TerminalSessionRecording) error
+	Update(recording *TerminalSessionRecording) error
+	FindById(id int) (*TerminalSessionRecording, error)
+	// FindAll returns the recordings, the latest first
+	FindAll(userId int32, clusterId int, namespace, podName string, offset, limit int) ([]*TerminalSessionRecording, error)
+	SaveChunk(chunk *TerminalSessionRecordingChunk) error
+	// FindChunksByRecordingId returns the events of the chunks of a recording in the order they were recorded
+	FindChunksByRecordingId(recordingId int) ([]string, error)
+}
+
+type TerminalSessionRecordingRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewTerminalSessionRecordingRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *TerminalSessionRecordingRepositoryImpl {
+	return &TerminalSessionRecordingRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (repo *TerminalSessionRecordingRepositoryImpl) Save(recording *TerminalSessionRecording) error {
+	return repo.dbConnection.Insert(recording)
+}
+
+func (repo *TerminalSessionRecordingRepositoryImpl) Update(recording *TerminalSessionRecording) error {
+	return repo.dbConnection.Update(recording)
+}
+
+func (repo *TerminalSessionRecordingRepositoryImpl) FindById(id int) (*TerminalSessionRecording, error) {
+	recording := &TerminalSessionRecording{}
+	err := repo.dbConnection.Model(recording).
+		Where("id = ?", id).
+		Select()
+	return recording, err
+}
+
+func (repo *TerminalSessionRecordingRepositoryImpl) FindAll(userId int32, clusterId int, namespace, podName string, offset, limit int) ([]*TerminalSessionRecording, error) {
+	var recordings []*TerminalSessionRecording
+	query := repo.dbConnection.Model(&recordings)
+	if userId > 0 {
+		query = query.Where("user_id = ?", userId)
+	}
+	if clusterId > 0 {
+		query = query.Where("cluster_id = ?", clusterId)
+	}
+	if len(namespace) > 0 {
+		query = query.Where("namespace = ?", namespace)
+	}
+	if len(podName) > 0 {
+		query = query.Where("pod_name = ?", podName)
+	}
+	err := query.
+		Order("id DESC").
+		Offset(offset).
+		Limit(limit).
+		Select()
+	return recordings, err
+}
+
+func (repo *TerminalSessionRecordingRepositoryImpl) SaveChunk(chunk *TerminalSessionRecordingChunk) error {
+	return repo.dbConnection.Insert(chunk)
+}
+
+func (repo *TerminalSessionRecordingRepositoryImpl) FindChunksByRecordingId(recordingId int) ([]string, error) {
+	var events []string
+	err := repo.dbConnection.Model(&TerminalSessionRecordingChunk{}).
+		Column("events").
+		Where("recording_id = ?", recordingId).
+		Order("chunk_index ASC").
+		Select(&events)
+	return events, err
+}
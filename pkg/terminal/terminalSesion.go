@@ -45,6 +45,8 @@ import (
 	bean2 "github.com/devtron-labs/devtron/pkg/cluster/environment/bean"
 	"github.com/devtron-labs/devtron/pkg/cluster/read"
 	"github.com/devtron-labs/devtron/pkg/cluster/repository"
+	"github.com/devtron-labs/devtron/pkg/terminal/recording"
+	recordingBean "github.com/devtron-labs/devtron/pkg/terminal/recording/bean"
 	errors1 "github.com/juju/errors"
 	"go.uber.org/zap"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -81,6 +83,7 @@ type TerminalSession struct {
 	namespace         string
 	clusterId         string
 	startedOn         time.Time
+	recorder          *recording.SessionRecorder
 }
 
 // TerminalMessage is the messaging protocol between ShellController and TerminalSession.
@@ -124,8 +127,10 @@ func (t TerminalSession) Read(p []byte) (int, error) {
 
 	switch msg.Op {
 	case "stdin":
+		t.recorder.RecordInput(msg.Data)
 		return copy(p, msg.Data), nil
 	case "resize":
+		t.recorder.RecordResize(msg.Cols, msg.Rows)
 		t.sizeChan <- remotecommand.TerminalSize{Width: msg.Cols, Height: msg.Rows}
 		return 0, nil
 	default:
@@ -147,6 +152,7 @@ func (t TerminalSession) Write(p []byte) (int, error) {
 	if err = t.sockJSSession.Send(string(msg)); err != nil {
 		return 0, err
 	}
+	t.recorder.RecordOutput(string(p))
 	return len(p), nil
 }
 
@@ -193,6 +199,7 @@ func (sm *SessionMap) SetTerminalSessionStartTime(sessionId string) {
 	defer sm.Lock.Unlock()
 	if session, ok := sm.Sessions[sessionId]; ok {
 		session.startedOn = time.Now()
+		session.recorder.Start(session.startedOn)
 		sm.Sessions[sessionId] = session
 	}
 }
@@ -242,6 +249,7 @@ func (sm *SessionMap) Close(sessionId string, status uint32, reason string) {
 		isErroredConnectionTermination := isConnectionClosedByError(status)
 		middleware.IncTerminalSessionRequestCounter(SessionTerminated, strconv.FormatBool(isErroredConnectionTermination))
 		middleware.RecordTerminalSessionDurationMetrics(terminalSession.podName, terminalSession.namespace, terminalSession.clusterId, time.Since(terminalSession.startedOn).Seconds())
+		terminalSession.recorder.Finish(time.Now())
 		terminalSession.contextCancelFunc()
 		close(terminalSession.bound)
 		delete(sm.Sessions, sessionId)
@@ -404,6 +412,8 @@ type TerminalSessionRequest struct {
 	ExternalArgoApplicationName      string
 	ExternalArgoApplicationNamespace string
 	ExternalArgoAppIdentifier        *bean3.ArgoAppIdentifier
+	// TerminalAccessId is set for the sessions of the cluster terminal, used for recording the session
+	TerminalAccessId int
 }
 
 const CommandExecutionFailed = "Failed to Execute Command"
@@ -466,12 +476,14 @@ type TerminalSessionHandlerImpl struct {
 	argoApplicationConfigService config.ArgoApplicationConfigService
 	ClusterReadService           read.ClusterReadService
 	asyncRunnable                *async.Runnable
+	recordingService             recording.TerminalSessionRecordingService
 }
 
 func NewTerminalSessionHandlerImpl(environmentService environment.EnvironmentService,
 	logger *zap.SugaredLogger, k8sUtil *k8s.K8sServiceImpl, ephemeralContainerService cluster.EphemeralContainerService,
 	argoApplicationConfigService config.ArgoApplicationConfigService,
-	ClusterReadService read.ClusterReadService, asyncRunnable *async.Runnable) *TerminalSessionHandlerImpl {
+	ClusterReadService read.ClusterReadService, asyncRunnable *async.Runnable,
+	recordingService recording.TerminalSessionRecordingService) *TerminalSessionHandlerImpl {
 	return &TerminalSessionHandlerImpl{
 		environmentService:           environmentService,
 		logger:                       logger,
@@ -480,6 +492,7 @@ func NewTerminalSessionHandlerImpl(environmentService environment.EnvironmentSer
 		argoApplicationConfigService: argoApplicationConfigService,
 		ClusterReadService:           ClusterReadService,
 		asyncRunnable:                asyncRunnable,
+		recordingService:             recordingService,
 	}
 }
 
@@ -522,6 +535,7 @@ func (impl *TerminalSessionHandlerImpl) GetTerminalSession(req *TerminalSessionR
 		podName:           req.PodName,
 		namespace:         req.Namespace,
 		clusterId:         strconv.Itoa(req.ClusterId),
+		recorder:          impl.recordingService.NewRecorder(getRecordingMetadata(req)),
 	})
 	config, client, err := impl.getClientSetAndRestConfigForTerminalConn(req)
 
@@ -540,6 +554,21 @@ func (impl *TerminalSessionHandlerImpl) GetTerminalSession(req *TerminalSessionR
 	return http.StatusOK, &TerminalMessage{SessionID: sessionID}, nil
 }
 
+func getRecordingMetadata(req *TerminalSessionRequest) *recordingBean.RecordingMetadata {
+	return &recordingBean.RecordingMetadata{
+		SessionId:        req.SessionId,
+		UserId:           req.UserId,
+		ClusterId:        req.ClusterId,
+		EnvironmentId:    req.EnvironmentId,
+		AppId:            req.AppId,
+		Namespace:        req.Namespace,
+		PodName:          req.PodName,
+		ContainerName:    req.ContainerName,
+		Shell:            req.Shell,
+		TerminalAccessId: req.TerminalAccessId,
+	}
+}
+
 func (impl *TerminalSessionHandlerImpl) getClientSetAndRestConfigForTerminalConn(req *TerminalSessionRequest) (*rest.Config, *kubernetes.Clientset, error) {
 	var clusterBean *bean.ClusterBean
 	var clusterConfig *k8s.ClusterConfig
@@ -94,6 +94,12 @@ func (impl *ScopedVariableCMCSManagerImpl) ResolveCMCSHistoryDto(ctx context.Con
 	return variableSnapshotMapGranular[componentName], string(cMCSData[componentName].Data), nil
 }
 
+// getVariableSnapshotAndResolveCMCSTemplate resolves the config map or secret with the variable snapshot of the reference,
+// the data which are yaml files are resolved as yaml
+func (impl *ScopedVariableCMCSManagerImpl) getVariableSnapshotAndResolveCMCSTemplate(template string, templateType parsers.VariableTemplateType, reference repository1.HistoryReference, isSuperAdmin bool, ignoreUnknown bool) (map[string]string, string, error) {
+	return impl.getVariableSnapshotAndResolveTemplate(template, templateType, reference, isSuperAdmin, ignoreUnknown, impl.parseTemplateWithYamlValues)
+}
+
 func (impl *ScopedVariableCMCSManagerImpl) getGranularSnapshotDataForConfigDataList(configList []*serviceBean.ConfigData, snapshot map[string]string) (map[string]map[string]string, error) {
 
 	expandedVariableSnapshot := make(map[string]map[string]string)
@@ -144,7 +150,7 @@ func (impl *ScopedVariableCMCSManagerImpl) ResolveSecretHistoryDto(ctx context.C
 	}
 	isSuperAdmin, err := util.GetIsSuperAdminFromContext(ctx)
 
-	variableSnapshotMap, resolvedTemplate, err := impl.GetVariableSnapshotAndResolveTemplate(data, parsers.StringVariableTemplate, reference, isSuperAdmin, false)
+	variableSnapshotMap, resolvedTemplate, err := impl.getVariableSnapshotAndResolveCMCSTemplate(data, parsers.StringVariableTemplate, reference, isSuperAdmin, false)
 	if err != nil {
 		return cMCSData, nil, err
 	}
@@ -179,7 +185,7 @@ func (impl *ScopedVariableCMCSManagerImpl) ResolveCMHistoryDto(ctx context.Conte
 	if err != nil {
 		return cMCSData, nil, err
 	}
-	variableSnapshotMap, resolvedTemplate, err := impl.GetVariableSnapshotAndResolveTemplate(string(configListJson), parsers.StringVariableTemplate, reference, isSuperAdmin, true)
+	variableSnapshotMap, resolvedTemplate, err := impl.getVariableSnapshotAndResolveCMCSTemplate(string(configListJson), parsers.StringVariableTemplate, reference, isSuperAdmin, true)
 	if err != nil {
 		return cMCSData, nil, err
 	}
@@ -283,7 +289,7 @@ func (impl *ScopedVariableCMCSManagerImpl) ResolveCMCS(ctx context.Context,
 	}
 
 	parserRequest := parsers.CreateParserRequest(string(mergedConfigMapJson), parsers.StringVariableTemplate, scopedVariables, true)
-	resolvedTemplateCM, err = impl.ParseTemplateWithScopedVariables(parserRequest)
+	resolvedTemplateCM, err = impl.parseTemplateWithYamlValues(parserRequest)
 	if err != nil {
 		return nil, nil, nil, nil, err
 	}
@@ -300,7 +306,7 @@ func (impl *ScopedVariableCMCSManagerImpl) ResolveCMCS(ctx context.Context,
 	}
 
 	parserRequest = parsers.CreateParserRequest(decodedSecrets, parsers.StringVariableTemplate, scopedVariables, true)
-	resolvedTemplateCS, err := impl.ParseTemplateWithScopedVariables(parserRequest)
+	resolvedTemplateCS, err := impl.parseTemplateWithYamlValues(parserRequest)
 	if err != nil {
 		return nil, nil, nil, nil, err
 	}
@@ -358,7 +364,7 @@ func (impl *ScopedVariableCMCSManagerImpl) ResolvedVariableForLastSaved(scope re
 
 	if configMapByte != nil && len(varNamesCM) > 0 {
 		parserRequest := parsers.CreateParserRequest(string(configMapByte), parsers.StringVariableTemplate, scopedVariables, true)
-		resolvedCM, err = impl.ParseTemplateWithScopedVariables(parserRequest)
+		resolvedCM, err = impl.parseTemplateWithYamlValues(parserRequest)
 		variableSnapshotForCM = parsers.GetVariableMapForUsedVariables(scopedVariables, varNamesCM)
 	}
 
@@ -368,7 +374,7 @@ func (impl *ScopedVariableCMCSManagerImpl) ResolvedVariableForLastSaved(scope re
 			return resolvedCM, string(secretDataByte), variableSnapshotForCM, variableSnapshotForCS, err
 		}
 		parserRequest := parsers.CreateParserRequest(data, parsers.StringVariableTemplate, scopedVariables, true)
-		resolvedCSDecoded, err := impl.ParseTemplateWithScopedVariables(parserRequest)
+		resolvedCSDecoded, err := impl.parseTemplateWithYamlValues(parserRequest)
 		variableSnapshotForCS = parsers.GetVariableMapForUsedVariables(scopedVariables, varNamesCS)
 		resolvedCS, err = bean.GetTransformedDataForSecretRootJsonData(resolvedCSDecoded, util.EncodeSecret)
 		if err != nil {
@@ -401,7 +407,7 @@ func (impl *ScopedVariableCMCSManagerImpl) ResolvedVariableForSpecificType(confi
 		HistoryReferenceType: repository1.HistoryReferenceTypeConfigMap,
 	}
 
-	variableMapCM, resolvedTemplateCM, err := impl.GetVariableSnapshotAndResolveTemplate(string(configMapByte), parsers.StringVariableTemplate, reference, true, true)
+	variableMapCM, resolvedTemplateCM, err := impl.getVariableSnapshotAndResolveCMCSTemplate(string(configMapByte), parsers.StringVariableTemplate, reference, true, true)
 	if err != nil {
 		return "", "", nil, nil, err
 	}
@@ -413,7 +419,7 @@ func (impl *ScopedVariableCMCSManagerImpl) ResolvedVariableForSpecificType(confi
 	if err != nil {
 		return "", "", nil, nil, err
 	}
-	variableMapCS, resolvedTemplateCS, err := impl.GetVariableSnapshotAndResolveTemplate(data, parsers.StringVariableTemplate, reference, true, true)
+	variableMapCS, resolvedTemplateCS, err := impl.getVariableSnapshotAndResolveCMCSTemplate(data, parsers.StringVariableTemplate, reference, true, true)
 	encodedSecretData, err := bean.GetTransformedDataForSecretRootJsonData(resolvedTemplateCS, util.EncodeSecret)
 	if err != nil {
 		return "", "", nil, nil, err
@@ -26,6 +26,7 @@ import (
 	"github.com/devtron-labs/devtron/pkg/variables/utils"
 	"github.com/go-pg/pg"
 	"go.uber.org/zap"
+	"strings"
 )
 
 type ScopedVariableManager interface {
@@ -100,7 +101,7 @@ func (impl ScopedVariableManagerImpl) GetMappedVariablesAndResolveTemplate(templ
 	}
 
 	for _, variable := range scopedVariables {
-		variableMap[variable.VariableName] = variable.VariableValue.StringValue()
+		variableMap[variable.VariableName] = variable.GetSnapshotValue()
 	}
 
 	if len(variableMap) == 0 {
@@ -135,7 +136,7 @@ func (impl ScopedVariableManagerImpl) ExtractVariablesAndResolveTemplate(scope r
 	}
 
 	for _, variable := range scopedVariables {
-		variableSnapshot[variable.VariableName] = variable.VariableValue.StringValue()
+		variableSnapshot[variable.VariableName] = variable.GetSnapshotValue()
 	}
 
 	if maskUnknownVariable {
@@ -174,6 +175,12 @@ func (impl ScopedVariableManagerImpl) ExtractAndMapVariables(template string, en
 }
 
 func (impl ScopedVariableManagerImpl) GetVariableSnapshotAndResolveTemplate(template string, templateType parsers.VariableTemplateType, reference repository.HistoryReference, isSuperAdmin bool, ignoreUnknown bool) (map[string]string, string, error) {
+	return impl.getVariableSnapshotAndResolveTemplate(template, templateType, reference, isSuperAdmin, ignoreUnknown, impl.ParseTemplateWithScopedVariables)
+}
+
+// getVariableSnapshotAndResolveTemplate resolves the template with the variable snapshot of the reference using parseTemplate
+func (impl ScopedVariableManagerImpl) getVariableSnapshotAndResolveTemplate(template string, templateType parsers.VariableTemplateType, reference repository.HistoryReference, isSuperAdmin bool, ignoreUnknown bool,
+	parseTemplate func(request parsers.VariableParserRequest) (string, error)) (map[string]string, string, error) {
 	variableSnapshotMap := make(map[string]string)
 	references, err := impl.variableSnapshotHistoryService.GetVariableHistoryForReferences([]repository.HistoryReference{reference})
 	if err != nil {
@@ -201,9 +208,16 @@ func (impl ScopedVariableManagerImpl) GetVariableSnapshotAndResolveTemplate(temp
 	}
 
 	scopedVariableData := parsers.GetScopedVarData(variableSnapshotMap, varNameToIsSensitive, isSuperAdmin)
+	if isSuperAdmin {
+		// the snapshot has the references of the variables resolved from the secret backend
+		err = impl.scopedVariableService.ResolveSecretReferences(scopedVariableData, varNameToIsSensitive)
+		if err != nil {
+			return variableSnapshotMap, template, err
+		}
+	}
 	request := parsers.VariableParserRequest{Template: template, TemplateType: templateType, Variables: scopedVariableData, IgnoreUnknownVariables: ignoreUnknown}
 
-	resolvedTemplate, err := impl.ParseTemplateWithScopedVariables(request)
+	resolvedTemplate, err := parseTemplate(request)
 	if err != nil {
 		return variableSnapshotMap, resolvedTemplate, err
 	}
@@ -253,7 +267,7 @@ func (impl ScopedVariableManagerImpl) GetMappedVariablesAndResolveTemplateBatch(
 
 	variableSnapshot := make(map[string]string)
 	for _, variable := range scopedVariables {
-		variableSnapshot[variable.VariableName] = variable.VariableValue.StringValue()
+		variableSnapshot[variable.VariableName] = variable.GetSnapshotValue()
 	}
 
 	request := parsers.VariableParserRequest{
@@ -262,7 +276,7 @@ func (impl ScopedVariableManagerImpl) GetMappedVariablesAndResolveTemplateBatch(
 		Variables:              scopedVariables,
 		IgnoreUnknownVariables: true,
 	}
-	resolvedTemplate, err := impl.ParseTemplateWithScopedVariables(request)
+	resolvedTemplate, err := impl.parseTemplateWithYamlValues(request)
 	if err != nil {
 		impl.logger.Errorw("Error in parsing stage", "error", err, "template", template, "vars", scopedVariables)
 		return template, variableMap, err
@@ -291,3 +305,72 @@ func (impl ScopedVariableManagerImpl) ParseTemplateWithScopedVariables(request p
 	resolvedTemplate := parserResponse.ResolvedTemplate
 	return resolvedTemplate, nil
 }
+
+// parseTemplateWithYamlValues resolves the string values of a json template which are yaml documents, like the files of
+// a config map or the input values of a plugin, as yaml before resolving the rest of the template
+func (impl ScopedVariableManagerImpl) parseTemplateWithYamlValues(request parsers.VariableParserRequest) (string, error) {
+	template, err := impl.resolveYamlValues(request)
+	if err != nil {
+		return request.Template, err
+	}
+	request.Template = template
+	return impl.ParseTemplateWithScopedVariables(request)
+}
+
+// resolveYamlValues returns the template with its yaml values resolved, the template as is if it is not json
+// or has no yaml values with variables
+func (impl ScopedVariableManagerImpl) resolveYamlValues(request parsers.VariableParserRequest) (string, error) {
+	var template interface{}
+	decoder := json.NewDecoder(strings.NewReader(request.Template))
+	decoder.UseNumber()
+	if err := decoder.Decode(&template); err != nil {
+		return request.Template, nil
+	}
+	isResolved := false
+	template, err := impl.resolveYamlValue(template, request, &isResolved)
+	if err != nil || !isResolved {
+		return request.Template, err
+	}
+	resolvedTemplate, err := json.Marshal(template)
+	if err != nil {
+		return request.Template, err
+	}
+	return string(resolvedTemplate), nil
+}
+
+func (impl ScopedVariableManagerImpl) resolveYamlValue(value interface{}, request parsers.VariableParserRequest, isResolved *bool) (interface{}, error) {
+	switch typedValue := value.(type) {
+	case map[string]interface{}:
+		for key, item := range typedValue {
+			resolvedItem, err := impl.resolveYamlValue(item, request, isResolved)
+			if err != nil {
+				return value, err
+			}
+			typedValue[key] = resolvedItem
+		}
+	case []interface{}:
+		for i, item := range typedValue {
+			resolvedItem, err := impl.resolveYamlValue(item, request, isResolved)
+			if err != nil {
+				return value, err
+			}
+			typedValue[i] = resolvedItem
+		}
+	case string:
+		// single line values are plain strings, even if they can be read as yaml
+		if !strings.Contains(typedValue, "\n") || !impl.variableTemplateParser.IsYamlTemplate(typedValue) {
+			return value, nil
+		}
+		yamlRequest := request
+		yamlRequest.TemplateType = parsers.YamlVariableTemplate
+		yamlRequest.Template = typedValue
+		resolvedValue, err := impl.ParseTemplateWithScopedVariables(yamlRequest)
+		if err != nil {
+			impl.logger.Errorw("error in resolving yaml value of template", "err", err)
+			return value, err
+		}
+		*isResolved = *isResolved || resolvedValue != typedValue
+		return resolvedValue, nil
+	}
+	return value, nil
+}
@@ -0,0 +1,57 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package variables
+
+import (
+	"encoding/json"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/variables/models"
+	"github.com/devtron-labs/devtron/pkg/variables/parsers"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseTemplateWithYamlValues(t *testing.T) {
+	logger, err := util.NewSugardLogger()
+	assert.Nil(t, err)
+	t.Setenv("SCOPED_VARIABLE_ENABLED", "true")
+	templateParser, err := parsers.NewVariableTemplateParserImpl(logger)
+	assert.Nil(t, err)
+	impl := ScopedVariableManagerImpl{logger: logger, variableTemplateParser: templateParser}
+	scopedVariables := []*models.ScopedVariableData{
+		{VariableName: "port", VariableValue: &models.VariableValue{Value: 8080}},
+		{VariableName: "hosts", VariableValue: &models.VariableValue{Value: "[a.example.com, b.example.com]"}},
+	}
+
+	t.Run("yaml data of config map", func(t *testing.T) {
+		template := `{"maps":[{"name":"cm","type":"volume","data":{"application.yaml":"server:\n  port: @{{port}}\n  hosts: @{{hosts}}\n","PORT":"@{{port}}"}}]}`
+		resolvedTemplate, err := impl.parseTemplateWithYamlValues(parsers.CreateParserRequest(template, parsers.StringVariableTemplate, scopedVariables, true))
+		assert.Nil(t, err)
+		resolved := map[string][]map[string]interface{}{}
+		assert.Nil(t, json.Unmarshal([]byte(resolvedTemplate), &resolved))
+		data := resolved["maps"][0]["data"].(map[string]interface{})
+		assert.Equal(t, "server:\n  port: 8080\n  hosts: [a.example.com, b.example.com]\n", data["application.yaml"])
+		assert.Equal(t, "8080", data["PORT"])
+	})
+
+	t.Run("template without yaml values", func(t *testing.T) {
+		template := `{"inputVariables":[{"name":"PORT","value":"@{{port}}"}]}`
+		resolvedTemplate, err := impl.parseTemplateWithYamlValues(parsers.CreateParserRequest(template, parsers.StringVariableTemplate, scopedVariables, true))
+		assert.Nil(t, err)
+		assert.Equal(t, `{"inputVariables":[{"name":"PORT","value":"8080"}]}`, resolvedTemplate)
+	})
+}
@@ -17,6 +17,7 @@
 package variables
 
 import (
+	"encoding/json"
 	"fmt"
 	"regexp"
 	"strings"
@@ -26,6 +27,7 @@ import (
 	"github.com/caarlos0/env"
 	"github.com/devtron-labs/common-lib/async"
 	"github.com/devtron-labs/devtron/internal/sql/repository/app"
+	"github.com/devtron-labs/devtron/pkg/auth/user/bean"
 	repository3 "github.com/devtron-labs/devtron/pkg/cluster/environment/repository"
 	"github.com/devtron-labs/devtron/pkg/cluster/repository"
 	"github.com/devtron-labs/devtron/pkg/devtronResource/read"
@@ -35,6 +37,7 @@ import (
 	"github.com/devtron-labs/devtron/pkg/variables/helper"
 	"github.com/devtron-labs/devtron/pkg/variables/models"
 	repository2 "github.com/devtron-labs/devtron/pkg/variables/repository"
+	"github.com/devtron-labs/devtron/pkg/variables/secretBackend"
 	"github.com/devtron-labs/devtron/pkg/variables/utils"
 	"github.com/go-pg/pg"
 	"go.uber.org/zap"
@@ -49,25 +52,32 @@ type ScopedVariableService interface {
 	GetFormattedVariableForName(name string) string
 	GetMatchedScopedVariables(varScope []*resourceQualifiers.QualifierMapping) map[int][]*resourceQualifiers.QualifierMapping
 	GetScopeWithPriority(variableIdToVariableScopes map[int][]*resourceQualifiers.QualifierMapping) map[int]int
+	// ResolveSecretReferences replaces the references to the secret backend in the values of the sensitive variables with the secrets
+	ResolveSecretReferences(scopedVariables []*models.ScopedVariableData, varNameToIsSensitive map[string]bool) error
 }
 
 type ScopedVariableServiceImpl struct {
-	logger                   *zap.SugaredLogger
-	scopedVariableRepository repository2.ScopedVariableRepository
-	qualifierMappingService  resourceQualifiers.QualifierMappingService
-	VariableNameConfig       *VariableConfig
-	VariableCache            *cache.VariableCacheObj
-	asyncRunnable            *async.Runnable
+	logger                    *zap.SugaredLogger
+	scopedVariableRepository  repository2.ScopedVariableRepository
+	qualifierMappingService   resourceQualifiers.QualifierMappingService
+	variableVersionRepository repository2.ScopedVariableVersionRepository
+	secretBackendService      secretBackend.SecretBackendService
+	VariableNameConfig        *VariableConfig
+	VariableCache             *cache.VariableCacheObj
+	asyncRunnable             *async.Runnable
 }
 
 func NewScopedVariableServiceImpl(logger *zap.SugaredLogger, scopedVariableRepository repository2.ScopedVariableRepository, appRepository app.AppRepository, environmentRepository repository3.EnvironmentRepository, devtronResourceSearchableKeyService read.DevtronResourceSearchableKeyService, clusterRepository repository.ClusterRepository,
-	qualifierMappingService resourceQualifiers.QualifierMappingService, asyncRunnable *async.Runnable) (*ScopedVariableServiceImpl, error) {
+	qualifierMappingService resourceQualifiers.QualifierMappingService, asyncRunnable *async.Runnable,
+	variableVersionRepository repository2.ScopedVariableVersionRepository, secretBackendService secretBackend.SecretBackendService) (*ScopedVariableServiceImpl, error) {
 	scopedVariableService := &ScopedVariableServiceImpl{
-		logger:                   logger,
-		scopedVariableRepository: scopedVariableRepository,
-		qualifierMappingService:  qualifierMappingService,
-		VariableCache:            &cache.VariableCacheObj{CacheLock: &sync.Mutex{}},
-		asyncRunnable:            asyncRunnable,
+		logger:                    logger,
+		scopedVariableRepository:  scopedVariableRepository,
+		qualifierMappingService:   qualifierMappingService,
+		variableVersionRepository: variableVersionRepository,
+		secretBackendService:      secretBackendService,
+		VariableCache:             &cache.VariableCacheObj{CacheLock: &sync.Mutex{}},
+		asyncRunnable:             asyncRunnable,
 	}
 	cfg, err := GetVariableNameConfig()
 	if err != nil {
@@ -199,6 +209,10 @@ func (impl *ScopedVariableServiceImpl) CreateVariables(payload models.Payload) e
 		}
 
 	}
+	err = impl.saveVariableVersion(payload, auditLog, tx)
+	if err != nil {
+		return err
+	}
 	err = impl.scopedVariableRepository.CommitTx(tx)
 	if err != nil {
 		impl.logger.Errorw("error in committing transaction of variable creation", "err", err)
@@ -208,6 +222,51 @@ func (impl *ScopedVariableServiceImpl) CreateVariables(payload models.Payload) e
 	return nil
 }
 
+// saveVariableVersion saves the payload as the next version of the variables. If no version is saved yet,
+// the variables defined before the versioning are saved first as a version by the system user so that
+// the first change can be rolled back as well.
+func (impl *ScopedVariableServiceImpl) saveVariableVersion(payload models.Payload, auditLog sql.AuditLog, tx *pg.Tx) error {
+	latestVersion, err := impl.variableVersionRepository.GetLatestVersionNumber(tx)
+	if err != nil {
+		return err
+	}
+	if latestVersion == 0 {
+		existingPayload, err := impl.GetJsonForVariables()
+		if err != nil {
+			impl.logger.Errorw("error in getting existing variables for the base version", "err", err)
+			return err
+		}
+		if existingPayload != nil {
+			latestVersion++
+			err = impl.storeVariableVersion(existingPayload, latestVersion, 0, resourceQualifiers.GetAuditLog(bean.SystemUserId), tx)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return impl.storeVariableVersion(&payload, latestVersion+1, payload.RolledBackFromVersion, auditLog, tx)
+}
+
+func (impl *ScopedVariableServiceImpl) storeVariableVersion(payload *models.Payload, version int, rolledBackFromVersion int, auditLog sql.AuditLog, tx *pg.Tx) error {
+	payloadJson, err := json.Marshal(payload)
+	if err != nil {
+		impl.logger.Errorw("error in marshalling variable payload for version", "version", version, "err", err)
+		return err
+	}
+	variableVersion := &repository2.ScopedVariableVersion{
+		Version:               version,
+		Payload:               string(payloadJson),
+		RolledBackFromVersion: rolledBackFromVersion,
+		AuditLog:              auditLog,
+	}
+	err = impl.variableVersionRepository.SaveVersion(variableVersion, tx)
+	if err != nil {
+		impl.logger.Errorw("error in saving variable version", "version", version, "err", err)
+		return err
+	}
+	return nil
+}
+
 func (impl *ScopedVariableServiceImpl) storeVariableData(scopeIdToVarData map[int]string, auditLog sql.AuditLog, tx *pg.Tx) error {
 	VariableDataList := make([]*repository2.VariableData, 0)
 	for scopeId, data := range scopeIdToVarData {
@@ -458,9 +517,17 @@ func (impl *ScopedVariableServiceImpl) GetScopedVariables(scope resourceQualifie
 
 		var varValue *models.VariableValue
 		var isRedacted bool
+		var secretReference string
 		if !unmaskSensitiveData && variableIdToDefinition[varId].VarType == models.PRIVATE {
 			varValue = &models.VariableValue{Value: models.HiddenValue}
 			isRedacted = true
+		} else if variableIdToDefinition[varId].VarType == models.PRIVATE && impl.isSecretReference(value) {
+			secretReference = value.(string)
+			value, err = impl.resolveSecretReference(variableIdToDefinition[varId].Name, secretReference)
+			if err != nil {
+				return nil, err
+			}
+			varValue = &models.VariableValue{Value: value}
 		} else {
 			varValue = &models.VariableValue{Value: value}
 		}
@@ -468,7 +535,8 @@ func (impl *ScopedVariableServiceImpl) GetScopedVariables(scope resourceQualifie
 			VariableName:     variableIdToDefinition[varId].Name,
 			ShortDescription: variableIdToDefinition[varId].ShortDescription,
 			VariableValue:    varValue,
-			IsRedacted:       isRedacted}
+			IsRedacted:       isRedacted,
+			SecretReference:  secretReference}
 
 		scopedVariableDataObj = append(scopedVariableDataObj, scopedVariableData)
 	}
@@ -499,6 +567,36 @@ func (impl *ScopedVariableServiceImpl) GetScopedVariables(scope resourceQualifie
 	return usedScopedVariableDataObj, err
 }
 
+func (impl *ScopedVariableServiceImpl) ResolveSecretReferences(scopedVariables []*models.ScopedVariableData, varNameToIsSensitive map[string]bool) error {
+	for _, variable := range scopedVariables {
+		if variable.VariableValue == nil || !varNameToIsSensitive[variable.VariableName] || !impl.isSecretReference(variable.VariableValue.Value) {
+			continue
+		}
+		secretReference := variable.VariableValue.Value.(string)
+		value, err := impl.resolveSecretReference(variable.VariableName, secretReference)
+		if err != nil {
+			return err
+		}
+		variable.VariableValue = &models.VariableValue{Value: value}
+		variable.SecretReference = secretReference
+	}
+	return nil
+}
+
+func (impl *ScopedVariableServiceImpl) isSecretReference(value interface{}) bool {
+	stringValue, ok := value.(string)
+	return ok && impl.secretBackendService.IsSecretReference(stringValue)
+}
+
+func (impl *ScopedVariableServiceImpl) resolveSecretReference(variableName string, secretReference string) (string, error) {
+	value, err := impl.secretBackendService.ResolveSecretReference(secretReference)
+	if err != nil {
+		impl.logger.Errorw("error in resolving sensitive variable from secret backend", "variableName", variableName, "err", err)
+		return "", fmt.Errorf("error in resolving sensitive variable %s: %w", variableName, err)
+	}
+	return value, nil
+}
+
 func resolveExpressionWithVariableValues(expr string, varNameToData map[string]*models.ScopedVariableData) (string, error) {
 	// regex to find  variable placeholder and extracts a variable name which is alphanumeric
 	// and can contain hyphen, underscore and whitespaces. white spaces will be trimmed on lookup
@@ -50,6 +50,9 @@ func (impl *ScopedVariableServiceImpl) isValidPayload(payload models.Payload) (e
 			if !utils.IsStringType(attributeValue.VariableValue.Value) && variable.Definition.VarType.IsTypeSensitive() {
 				return models.ValidationError{Err: fmt.Errorf("data type other than string cannot be sensitive")}, false
 			}
+			if variable.Definition.VarType.IsTypeSensitive() && impl.secretBackendService.IsEnabled() && !impl.isSecretReference(attributeValue.VariableValue.Value) {
+				return models.ValidationError{Err: fmt.Errorf("value of sensitive variable %s must be a reference to the secret backend like vault:<path>#<key>", variable.Definition.VarName)}, false
+			}
 
 			validIdentifierTypeList := helper.GetIdentifierTypeFromAttributeType(attributeValue.AttributeType)
 			if len(validIdentifierTypeList) != len(attributeValue.AttributeParams) {
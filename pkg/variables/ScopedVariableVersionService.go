@@ -0,0 +1,199 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package variables
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/devtron-labs/devtron/internal/util"
+	userRepository "github.com/devtron-labs/devtron/pkg/auth/user/repository"
+	"github.com/devtron-labs/devtron/pkg/variables/helper"
+	"github.com/devtron-labs/devtron/pkg/variables/models"
+	"github.com/devtron-labs/devtron/pkg/variables/repository"
+	"github.com/devtron-labs/devtron/pkg/variables/utils"
+	"go.uber.org/zap"
+)
+
+type ScopedVariableVersionService interface {
+	GetVersions(offset, limit int) ([]*models.VariableVersion, error)
+	GetVersion(version int) (*models.VariableVersionDetail, error)
+	// DiffVersions returns the changes of the variables from fromVersion to toVersion along with the entities using the changed variables
+	DiffVersions(fromVersion, toVersion int) (*models.VariableVersionDiff, error)
+	// RollbackToVersion saves the variables of the version again, which creates a new version
+	RollbackToVersion(version int, userId int32) error
+}
+
+type ScopedVariableVersionServiceImpl struct {
+	logger                       *zap.SugaredLogger
+	variableVersionRepository    repository.ScopedVariableVersionRepository
+	scopedVariableService        ScopedVariableService
+	variableEntityMappingService VariableEntityMappingService
+	userRepository               userRepository.UserRepository
+}
+
+func NewScopedVariableVersionServiceImpl(logger *zap.SugaredLogger, variableVersionRepository repository.ScopedVariableVersionRepository,
+	scopedVariableService ScopedVariableService, variableEntityMappingService VariableEntityMappingService,
+	userRepository userRepository.UserRepository) *ScopedVariableVersionServiceImpl {
+	return &ScopedVariableVersionServiceImpl{
+		logger:                       logger,
+		variableVersionRepository:    variableVersionRepository,
+		scopedVariableService:        scopedVariableService,
+		variableEntityMappingService: variableEntityMappingService,
+		userRepository:               userRepository,
+	}
+}
+
+func (impl *ScopedVariableVersionServiceImpl) GetVersions(offset, limit int) ([]*models.VariableVersion, error) {
+	variableVersions, err := impl.variableVersionRepository.GetVersions(offset, limit)
+	if err != nil {
+		impl.logger.Errorw("error in getting variable versions", "offset", offset, "limit", limit, "err", err)
+		return nil, err
+	}
+	userIds := make([]int32, 0, len(variableVersions))
+	for _, variableVersion := range variableVersions {
+		userIds = append(userIds, variableVersion.CreatedBy)
+	}
+	emailById, err := impl.getUserEmails(userIds)
+	if err != nil {
+		return nil, err
+	}
+	versions := make([]*models.VariableVersion, 0, len(variableVersions))
+	for _, variableVersion := range variableVersions {
+		versions = append(versions, getVariableVersionDto(variableVersion, emailById))
+	}
+	return versions, nil
+}
+
+func (impl *ScopedVariableVersionServiceImpl) GetVersion(version int) (*models.VariableVersionDetail, error) {
+	variableVersion, payload, err := impl.getVersionWithPayload(version)
+	if err != nil {
+		return nil, err
+	}
+	emailById, err := impl.getUserEmails([]int32{variableVersion.CreatedBy})
+	if err != nil {
+		return nil, err
+	}
+	manifest := utils.PayloadToManifest(*payload)
+	return &models.VariableVersionDetail{
+		VariableVersion: getVariableVersionDto(variableVersion, emailById),
+		Manifest:        &manifest,
+	}, nil
+}
+
+func (impl *ScopedVariableVersionServiceImpl) DiffVersions(fromVersion, toVersion int) (*models.VariableVersionDiff, error) {
+	_, fromPayload, err := impl.getVersionWithPayload(fromVersion)
+	if err != nil {
+		return nil, err
+	}
+	_, toPayload, err := impl.getVersionWithPayload(toVersion)
+	if err != nil {
+		return nil, err
+	}
+	variableDiffs := helper.GetVariableDiffs(fromPayload, toPayload)
+	changedVarNames := make([]string, 0, len(variableDiffs))
+	for _, variableDiff := range variableDiffs {
+		changedVarNames = append(changedVarNames, variableDiff.VarName)
+	}
+	varNameToReferences, err := impl.variableEntityMappingService.GetEntityReferencesForVariables(changedVarNames)
+	if err != nil {
+		return nil, err
+	}
+	for _, variableDiff := range variableDiffs {
+		variableDiff.UsedBy = getVariableEntityUsages(varNameToReferences[variableDiff.VarName])
+	}
+	return &models.VariableVersionDiff{
+		FromVersion: fromVersion,
+		ToVersion:   toVersion,
+		Variables:   variableDiffs,
+	}, nil
+}
+
+func (impl *ScopedVariableVersionServiceImpl) RollbackToVersion(version int, userId int32) error {
+	_, payload, err := impl.getVersionWithPayload(version)
+	if err != nil {
+		return err
+	}
+	payload.UserId = userId
+	payload.RolledBackFromVersion = version
+	err = impl.scopedVariableService.CreateVariables(*payload)
+	if err != nil {
+		impl.logger.Errorw("error in rolling back variables", "version", version, "err", err)
+		return err
+	}
+	return nil
+}
+
+func (impl *ScopedVariableVersionServiceImpl) getVersionWithPayload(version int) (*repository.ScopedVariableVersion, *models.Payload, error) {
+	variableVersion, err := impl.variableVersionRepository.GetVersion(version)
+	if util.IsErrNoRows(err) {
+		errMsg := fmt.Sprintf("variable version %d not found", version)
+		return nil, nil, util.NewApiError(http.StatusNotFound, errMsg, errMsg)
+	} else if err != nil {
+		impl.logger.Errorw("error in getting variable version", "version", version, "err", err)
+		return nil, nil, err
+	}
+	payload, err := helper.GetPayloadFromJson(variableVersion.Payload)
+	if err != nil {
+		impl.logger.Errorw("error in decoding payload of variable version", "version", version, "err", err)
+		return nil, nil, err
+	}
+	return variableVersion, payload, nil
+}
+
+func (impl *ScopedVariableVersionServiceImpl) getUserEmails(userIds []int32) (map[int32]string, error) {
+	emailById := make(map[int32]string)
+	if len(userIds) == 0 {
+		return emailById, nil
+	}
+	users, err := impl.userRepository.GetByIds(userIds)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in getting users of variable versions", "userIds", userIds, "err", err)
+		return nil, err
+	}
+	for _, user := range users {
+		emailById[user.Id] = user.EmailId
+	}
+	return emailById, nil
+}
+
+func getVariableVersionDto(variableVersion *repository.ScopedVariableVersion, emailById map[int32]string) *models.VariableVersion {
+	return &models.VariableVersion{
+		Version:               variableVersion.Version,
+		RolledBackFromVersion: variableVersion.RolledBackFromVersion,
+		CreatedBy:             variableVersion.CreatedBy,
+		CreatedByEmail:        emailById[variableVersion.CreatedBy],
+		CreatedOn:             variableVersion.CreatedOn,
+	}
+}
+
+func getVariableEntityUsages(references []*repository.VariableEntityReference) []*models.VariableEntityUsage {
+	usages := make([]*models.VariableEntityUsage, 0, len(references))
+	for _, reference := range references {
+		usages = append(usages, &models.VariableEntityUsage{
+			EntityType:   reference.EntityType.String(),
+			EntityId:     reference.EntityId,
+			AppId:        reference.AppId,
+			AppName:      reference.AppName,
+			EnvId:        reference.EnvId,
+			EnvName:      reference.EnvName,
+			CiPipelineId: reference.CiPipelineId,
+			CdPipelineId: reference.CdPipelineId,
+		})
+	}
+	return usages
+}
@@ -30,6 +30,8 @@ type VariableEntityMappingService interface {
 	UpdateVariablesForEntity(variableNames []string, entity repository.Entity, userId int32, tx *pg.Tx) error
 	GetAllMappingsForEntities(entities []repository.Entity) (map[repository.Entity][]string, error)
 	DeleteMappingsForEntities(entities []repository.Entity, userId int32, tx *pg.Tx) error
+	// GetEntityReferencesForVariables returns the entities using each of the variables, keyed by the variable name
+	GetEntityReferencesForVariables(variableNames []string) (map[string][]*repository.VariableEntityReference, error)
 }
 
 type VariableEntityMappingServiceImpl struct {
@@ -143,3 +145,16 @@ func (impl VariableEntityMappingServiceImpl) DeleteMappingsForEntities(entities
 	}
 	return nil
 }
+
+func (impl VariableEntityMappingServiceImpl) GetEntityReferencesForVariables(variableNames []string) (map[string][]*repository.VariableEntityReference, error) {
+	variableNameToReferences := make(map[string][]*repository.VariableEntityReference)
+	references, err := impl.variableEntityMappingRepository.GetEntityReferencesForVariables(variableNames)
+	if err != nil {
+		impl.logger.Errorw("error in fetching entity references for variables", "variableNames", variableNames, "err", err)
+		return nil, err
+	}
+	for _, reference := range references {
+		variableNameToReferences[reference.VariableName] = append(variableNameToReferences[reference.VariableName], reference)
+	}
+	return variableNameToReferences, nil
+}
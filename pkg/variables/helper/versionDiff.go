@@ -0,0 +1,172 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package helper
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/devtron-labs/devtron/pkg/variables/models"
+)
+
+// GetPayloadFromJson decodes a saved payload, numbers are kept as json.Number as in the create variables request
+// so that the payload can be saved again as is
+func GetPayloadFromJson(payloadJson string) (*models.Payload, error) {
+	payload := &models.Payload{}
+	decoder := json.NewDecoder(bytes.NewReader([]byte(payloadJson)))
+	decoder.UseNumber()
+	err := decoder.Decode(payload)
+	if err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+// GetVariableDiffs returns the changes of the variables from the payload "from" to the payload "to", sorted by variable name.
+// Variables which are unchanged are not returned.
+func GetVariableDiffs(from, to *models.Payload) []*models.VariableDiff {
+	fromVariables := getVariablesByName(from)
+	toVariables := getVariablesByName(to)
+	varNames := make([]string, 0, len(fromVariables)+len(toVariables))
+	for varName := range fromVariables {
+		varNames = append(varNames, varName)
+	}
+	for varName := range toVariables {
+		if _, ok := fromVariables[varName]; !ok {
+			varNames = append(varNames, varName)
+		}
+	}
+	sort.Strings(varNames)
+
+	diffs := make([]*models.VariableDiff, 0)
+	for _, varName := range varNames {
+		fromVariable, inFrom := fromVariables[varName]
+		toVariable, inTo := toVariables[varName]
+		diff := &models.VariableDiff{VarName: varName}
+		switch {
+		case !inFrom:
+			diff.ChangeType = models.VariableAdded
+			diff.ScopeChanges = getScopeDiffs(nil, toVariable.AttributeValues)
+		case !inTo:
+			diff.ChangeType = models.VariableRemoved
+			diff.ScopeChanges = getScopeDiffs(fromVariable.AttributeValues, nil)
+		default:
+			diff.DefinitionChanges = getDefinitionChanges(fromVariable.Definition, toVariable.Definition)
+			diff.ScopeChanges = getScopeDiffs(fromVariable.AttributeValues, toVariable.AttributeValues)
+			if len(diff.DefinitionChanges) == 0 && len(diff.ScopeChanges) == 0 {
+				continue
+			}
+			diff.ChangeType = models.VariableModified
+		}
+		diffs = append(diffs, diff)
+	}
+	return diffs
+}
+
+func getVariablesByName(payload *models.Payload) map[string]*models.Variables {
+	variablesByName := make(map[string]*models.Variables)
+	if payload == nil {
+		return variablesByName
+	}
+	for _, variable := range payload.Variables {
+		variablesByName[variable.Definition.VarName] = variable
+	}
+	return variablesByName
+}
+
+func getDefinitionChanges(from, to models.Definition) []*models.DefinitionFieldChange {
+	changes := make([]*models.DefinitionFieldChange, 0)
+	addChange := func(field, oldValue, newValue string) {
+		if oldValue != newValue {
+			changes = append(changes, &models.DefinitionFieldChange{Field: field, OldValue: oldValue, NewValue: newValue})
+		}
+	}
+	addChange("dataType", string(from.DataType), string(to.DataType))
+	addChange("varType", string(from.VarType), string(to.VarType))
+	addChange("description", from.Description, to.Description)
+	addChange("shortDescription", from.ShortDescription, to.ShortDescription)
+	return changes
+}
+
+func getScopeDiffs(from, to []models.AttributeValue) []*models.ScopeDiff {
+	fromScopes := getAttributeValuesByScope(from)
+	toScopes := getAttributeValuesByScope(to)
+	scopeKeys := make([]string, 0, len(fromScopes)+len(toScopes))
+	for scopeKey := range fromScopes {
+		scopeKeys = append(scopeKeys, scopeKey)
+	}
+	for scopeKey := range toScopes {
+		if _, ok := fromScopes[scopeKey]; !ok {
+			scopeKeys = append(scopeKeys, scopeKey)
+		}
+	}
+	sort.Strings(scopeKeys)
+
+	diffs := make([]*models.ScopeDiff, 0)
+	for _, scopeKey := range scopeKeys {
+		fromValue, inFrom := fromScopes[scopeKey]
+		toValue, inTo := toScopes[scopeKey]
+		switch {
+		case !inFrom:
+			diffs = append(diffs, getScopeDiff(toValue, models.VariableAdded, nil, toValue.VariableValue.Value))
+		case !inTo:
+			diffs = append(diffs, getScopeDiff(fromValue, models.VariableRemoved, fromValue.VariableValue.Value, nil))
+		case !isSameValue(fromValue.VariableValue.Value, toValue.VariableValue.Value):
+			diffs = append(diffs, getScopeDiff(toValue, models.VariableModified, fromValue.VariableValue.Value, toValue.VariableValue.Value))
+		}
+	}
+	return diffs
+}
+
+func getScopeDiff(attributeValue models.AttributeValue, changeType models.VariableChangeType, oldValue, newValue interface{}) *models.ScopeDiff {
+	return &models.ScopeDiff{
+		AttributeType:   attributeValue.AttributeType,
+		AttributeParams: attributeValue.AttributeParams,
+		ChangeType:      changeType,
+		OldValue:        oldValue,
+		NewValue:        newValue,
+	}
+}
+
+// getAttributeValuesByScope keys the values by the attribute type and the sorted attribute params
+func getAttributeValuesByScope(attributeValues []models.AttributeValue) map[string]models.AttributeValue {
+	valuesByScope := make(map[string]models.AttributeValue, len(attributeValues))
+	for _, attributeValue := range attributeValues {
+		params := make([]string, 0, len(attributeValue.AttributeParams))
+		for identifierType, identifier := range attributeValue.AttributeParams {
+			params = append(params, fmt.Sprintf("%s=%s", identifierType, identifier))
+		}
+		sort.Strings(params)
+		scopeKey := fmt.Sprintf("%s/%s", attributeValue.AttributeType, strings.Join(params, ","))
+		valuesByScope[scopeKey] = attributeValue
+	}
+	return valuesByScope
+}
+
+// isSameValue compares the json of the values, so that the same number is equal whether it is an int,
+// a float or a json.Number
+func isSameValue(a, b interface{}) bool {
+	aJson, errA := json.Marshal(a)
+	bJson, errB := json.Marshal(b)
+	if errA != nil || errB != nil {
+		return false
+	}
+	return bytes.Equal(aJson, bJson)
+}
@@ -0,0 +1,81 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package helper
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/devtron-labs/devtron/pkg/variables/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetVariableDiffs(t *testing.T) {
+	from, err := GetPayloadFromJson(`{"variables":[
+		{"definition":{"varName":"replicas","dataType":"primitive","varType":"public","description":"replica count"},
+		 "attributeValue":[{"variableValue":{"value":2},"attributeType":"Global"}]},
+		{"definition":{"varName":"region","dataType":"primitive","varType":"public"},
+		 "attributeValue":[{"variableValue":{"value":"us-east-1"},"attributeType":"Global"}]},
+		{"definition":{"varName":"removed","dataType":"primitive","varType":"private"},
+		 "attributeValue":[{"variableValue":{"value":true},"attributeType":"Global"}]}
+	]}`)
+	assert.NoError(t, err)
+	to, err := GetPayloadFromJson(`{"variables":[
+		{"definition":{"varName":"replicas","dataType":"primitive","varType":"private","description":"replica count"},
+		 "attributeValue":[{"variableValue":{"value":3},"attributeType":"Global"}]},
+		{"definition":{"varName":"region","dataType":"primitive","varType":"public"},
+		 "attributeValue":[{"variableValue":{"value":"us-east-1"},"attributeType":"Global"}]},
+		{"definition":{"varName":"added","dataType":"primitive","varType":"public"},
+		 "attributeValue":[{"variableValue":{"value":"new"},"attributeType":"Global"}]}
+	]}`)
+	assert.NoError(t, err)
+
+	diffs := GetVariableDiffs(from, to)
+	assert.Len(t, diffs, 3)
+
+	assert.Equal(t, "added", diffs[0].VarName)
+	assert.Equal(t, models.VariableAdded, diffs[0].ChangeType)
+	assert.Len(t, diffs[0].ScopeChanges, 1)
+	assert.Nil(t, diffs[0].ScopeChanges[0].OldValue)
+	assert.Equal(t, "new", diffs[0].ScopeChanges[0].NewValue)
+
+	assert.Equal(t, "removed", diffs[1].VarName)
+	assert.Equal(t, models.VariableRemoved, diffs[1].ChangeType)
+	assert.Equal(t, true, diffs[1].ScopeChanges[0].OldValue)
+	assert.Nil(t, diffs[1].ScopeChanges[0].NewValue)
+
+	assert.Equal(t, "replicas", diffs[2].VarName)
+	assert.Equal(t, models.VariableModified, diffs[2].ChangeType)
+	assert.Equal(t, []*models.DefinitionFieldChange{{Field: "varType", OldValue: "public", NewValue: "private"}}, diffs[2].DefinitionChanges)
+	assert.Len(t, diffs[2].ScopeChanges, 1)
+	assert.Equal(t, models.Global, diffs[2].ScopeChanges[0].AttributeType)
+	assert.Equal(t, models.VariableModified, diffs[2].ScopeChanges[0].ChangeType)
+	assert.Equal(t, json.Number("2"), diffs[2].ScopeChanges[0].OldValue)
+	assert.Equal(t, json.Number("3"), diffs[2].ScopeChanges[0].NewValue)
+}
+
+func TestGetVariableDiffsSameNumberOfDifferentTypes(t *testing.T) {
+	from := &models.Payload{Variables: []*models.Variables{{
+		Definition:      models.Definition{VarName: "port", DataType: models.PRIMITIVE_TYPE, VarType: models.PUBLIC},
+		AttributeValues: []models.AttributeValue{{VariableValue: models.VariableValue{Value: 8080}, AttributeType: models.Global}},
+	}}}
+	to, err := GetPayloadFromJson(`{"variables":[{"definition":{"varName":"port","dataType":"primitive","varType":"public"},
+		"attributeValue":[{"variableValue":{"value":8080},"attributeType":"Global"}]}]}`)
+	assert.NoError(t, err)
+	assert.Empty(t, GetVariableDiffs(from, to))
+	assert.Len(t, GetVariableDiffs(nil, to), 1)
+}
@@ -25,6 +25,17 @@ type ScopedVariableData struct {
 	ShortDescription string         `json:"shortDescription"`
 	VariableValue    *VariableValue `json:"variableValue,omitempty"`
 	IsRedacted       bool           `json:"isRedacted"`
+	// SecretReference is the reference to the secret backend from which the value of a sensitive variable is resolved
+	SecretReference string `json:"-"`
+}
+
+// GetSnapshotValue returns the value of the variable to be saved in the variable snapshot,
+// the reference is saved for the variables resolved from the secret backend so that the secret is not saved
+func (data *ScopedVariableData) GetSnapshotValue() string {
+	if len(data.SecretReference) > 0 {
+		return data.SecretReference
+	}
+	return data.VariableValue.StringValue()
 }
 
 type VariableScopeMapping struct {
@@ -24,6 +24,8 @@ import (
 type Payload struct {
 	Variables []*Variables `json:"variables" validate:"required,dive"`
 	UserId    int32        `json:"-"`
+	// RolledBackFromVersion is set when the payload is of an older version being rolled back to
+	RolledBackFromVersion int `json:"-"`
 }
 type Variables struct {
 	Definition      Definition       `json:"definition" validate:"required,dive"`
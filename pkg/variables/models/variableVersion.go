@@ -0,0 +1,85 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package models
+
+import (
+	"time"
+)
+
+type VariableVersion struct {
+	Version               int       `json:"version"`
+	RolledBackFromVersion int       `json:"rolledBackFromVersion,omitempty"`
+	CreatedBy             int32     `json:"createdBy"`
+	CreatedByEmail        string    `json:"createdByEmail"`
+	CreatedOn             time.Time `json:"createdOn"`
+}
+
+type VariableVersionDetail struct {
+	*VariableVersion
+	Manifest *ScopedVariableManifest `json:"manifest"`
+}
+
+type VariableChangeType string
+
+const (
+	VariableAdded    VariableChangeType = "ADDED"
+	VariableRemoved  VariableChangeType = "REMOVED"
+	VariableModified VariableChangeType = "MODIFIED"
+)
+
+type VariableVersionDiff struct {
+	FromVersion int             `json:"fromVersion"`
+	ToVersion   int             `json:"toVersion"`
+	Variables   []*VariableDiff `json:"variables"`
+}
+
+// VariableDiff is the change of a variable between two versions. UsedBy are the entities which use the variable
+// currently and would pick up the changed value on their next deployment or trigger.
+type VariableDiff struct {
+	VarName           string                   `json:"varName"`
+	ChangeType        VariableChangeType       `json:"changeType"`
+	DefinitionChanges []*DefinitionFieldChange `json:"definitionChanges,omitempty"`
+	ScopeChanges      []*ScopeDiff             `json:"scopeChanges,omitempty"`
+	UsedBy            []*VariableEntityUsage   `json:"usedBy"`
+}
+
+type DefinitionFieldChange struct {
+	Field    string `json:"field"`
+	OldValue string `json:"oldValue"`
+	NewValue string `json:"newValue"`
+}
+
+// ScopeDiff is the change of the value of a variable for a scope, OldValue is nil for an added scope
+// and NewValue is nil for a removed scope
+type ScopeDiff struct {
+	AttributeType   AttributeType             `json:"attributeType"`
+	AttributeParams map[IdentifierType]string `json:"attributeParams,omitempty"`
+	ChangeType      VariableChangeType        `json:"changeType"`
+	OldValue        interface{}               `json:"oldValue"`
+	NewValue        interface{}               `json:"newValue"`
+}
+
+type VariableEntityUsage struct {
+	EntityType   string `json:"entityType"`
+	EntityId     int    `json:"entityId"`
+	AppId        int    `json:"appId,omitempty"`
+	AppName      string `json:"appName,omitempty"`
+	EnvId        int    `json:"envId,omitempty"`
+	EnvName      string `json:"envName,omitempty"`
+	CiPipelineId int    `json:"ciPipelineId,omitempty"`
+	CdPipelineId int    `json:"cdPipelineId,omitempty"`
+}
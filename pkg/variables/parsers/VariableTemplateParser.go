@@ -39,11 +39,14 @@ type VariableTemplateParser interface {
 	ExtractVariables(template string, templateType VariableTemplateType) ([]string, error)
 	//ParseTemplate(template string, values map[string]string) string
 	ParseTemplate(parserRequest VariableParserRequest) VariableParserResponse
+	// IsYamlTemplate returns true if the template is a yaml map or list which is not json
+	IsYamlTemplate(template string) bool
 }
 
 type VariableTemplateParserImpl struct {
 	logger                       *zap.SugaredLogger
 	variableTemplateParserConfig *VariableTemplateParserConfig
+	variableExpressionRegex      *regexp.Regexp
 }
 
 func NewVariableTemplateParserImpl(logger *zap.SugaredLogger) (*VariableTemplateParserImpl, error) {
@@ -53,6 +56,10 @@ func NewVariableTemplateParserImpl(logger *zap.SugaredLogger) (*VariableTemplate
 		return nil, err
 	}
 	impl.variableTemplateParserConfig = cfg
+	impl.variableExpressionRegex, err = regexp.Compile(cfg.VariableExpressionRegex)
+	if err != nil {
+		return nil, err
+	}
 	return impl, nil
 }
 
@@ -109,6 +116,9 @@ func (impl *VariableTemplateParserImpl) ParseTemplate(parserRequest VariablePars
 	if impl.variableTemplateParserConfig.isScopedVariablesDisabled() {
 		return parserRequest.GetEmptyResponse()
 	}
+	if parserRequest.TemplateType == YamlVariableTemplate {
+		return impl.parseYamlTemplate(parserRequest)
+	}
 	request := parserRequest
 	if impl.handlePrimitivesForJson(parserRequest) {
 		variableToValue := parserRequest.GetOriginalValuesMap()
@@ -136,6 +146,10 @@ func (impl *VariableTemplateParserImpl) ExtractVariables(template string, templa
 		return variables, nil
 	}
 
+	if templateType == YamlVariableTemplate {
+		return impl.extractYamlVariables(template)
+	}
+
 	// preprocess existing template to comment
 	template, err := impl.convertToHclCompatible(templateType, template)
 	if err != nil {
@@ -34,10 +34,13 @@ func TestVariableTemplateParserImpl_ExtractVariables(t *testing.T) {
 	logger, err := util.NewSugardLogger()
 	assert.Nil(t, err)
 
+	t.Setenv("SCOPED_VARIABLE_ENABLED", "true")
+
 	t.Run("extract variables", func(t *testing.T) {
-		templateParser := NewVariableTemplateParserImpl(logger) // \"value\"
+		templateParser, err := NewVariableTemplateParserImpl(logger) // \"value\"
+		assert.Nil(t, err)
 		sampleTemplate := `{"ConfigMaps":{"enabled":false,"maps":[]},"ConfigSecrets":{"enabled":false,"secrets":[]},"ContainerPort":[{"envoyPort":"@{{envoyPort + 0}}","idleTimeout":"@{{idleTimeoutVar / idleTimeoutDivVar}}s","name":"${1 + appName}","port":8080,"servicePort":80,"supportStreaming":false,"useHTTP2":false}],"EnvVariables":[],"EnvVariablesFromFieldPath":[{"fieldPath":"metadata.name","name":"POD_NAME"}],"GracePeriod":30,"LivenessProbe":{"Path":"","command":[],"failureThreshold":3,"httpHeaders":[],"initialDelaySeconds":20,"periodSeconds":10,"port":8080,"scheme":"","successThreshold":1,"tcp":false,"timeoutSeconds":5},"MaxSurge":1,"MaxUnavailable":0,"MinReadySeconds":60,"ReadinessProbe":{"Path":"","command":[],"failureThreshold":3,"httpHeaders":[],"initialDelaySeconds":20,"periodSeconds":10,"port":8080,"scheme":"","successThreshold":1,"tcp":false,"timeoutSeconds":5},"Spec":{"Affinity":{"Values":"nodes","key":""}},"ambassadorMapping":{"ambassadorId":"","cors":{},"enabled":false,"hostname":"devtron.example.com","labels":{},"prefix":"/","retryPolicy":{},"rewrite":"","tls":{"context":"","create":false,"hosts":[],"secretName":""}},"args":{"enabled":false,"value":["/bin/sh","-c","touch /tmp/healthy; sleep 30; rm -rf /tmp/healthy; sleep 600"]},"autoPromotionSeconds":30,"autoscaling":{"MaxReplicas":2,"MinReplicas":1,"TargetCPUUtilizationPercentage":90,"TargetMemoryUtilizationPercentage":80,"annotations":{},"behavior":{},"enabled":false,"extraMetrics":[],"labels":{}},"command":{"enabled":false,"value":[],"workingDir":{}},"containerExtraSpecs":{},"containerSecurityContext":{},"containerSpec":{"lifecycle":{"enabled":false,"postStart":{"httpGet":{"host":"example.com","path":"/example","port":90}},"preStop":{"exec":{"command":["sleep","10"]}}}},"containers":[],"dbMigrationConfig":{"enabled":false},"envoyproxy":{"configMapName":"","image":"quay.io/devtron/envoy:v1.14.1","lifecycle":{},"resources":{"limits":{"cpu":"50m","memory":"50Mi"},"requests":{"cpu":"50m","memory":"50Mi"}}},"hostAliases":[],"image":{"pullPolicy":"IfNotPresent"},"imagePullSecrets":[],"ingress":{"annotations":{},"className":"","enabled":false,"hosts":[{"host":"chart-example1.local","pathType":"ImplementationSpecific","paths":["/example1"]},{"host":"chart-example2.local","pathType":"ImplementationSpecific","paths":["/example2","/example2/healthz"]}],"labels":{},"tls":[]},"ingressInternal":{"annotations":{},"className":"","enabled":false,"hosts":[{"host":"chart-example1.internal","pathType":"ImplementationSpecific","paths":["/example1"]},{"host":"chart-example2.internal","pathType":"ImplementationSpecific","paths":["/example2","/example2/healthz"]}],"tls":[]},"initContainers":[],"istio":{"enable":false,"gateway":{"annotations":{},"enabled":false,"host":"example.com","labels":{},"tls":{"enabled":false,"secretName":"secret-name"}},"virtualService":{"annotations":{},"enabled":false,"gateways":[],"hosts":[],"http":[{"corsPolicy":{},"headers":{},"match":[{"uri":{"prefix":"/v1"}},{"uri":{"prefix":"/v2"}}],"retries":{"attempts":2,"perTryTimeout":"3s"},"rewriteUri":"/","route":[{"destination":{"host":"service1","port":80}}],"timeout":"12s"},{"route":[{"destination":{"host":"service2"}}]}],"labels":{}}},"kedaAutoscaling":{"advanced":{},"authenticationRef":{},"cooldownPeriod":300,"enabled":false,"envSourceContainerName":"","fallback":{},"idleReplicaCount":0,"maxReplicaCount":2,"minReplicaCount":1,"pollingInterval":30,"triggerAuthentication":{"enabled":false,"name":"","spec":{}},"triggers":[]},"nodeSelector":{},"orchestrator.deploymant.algo":1,"pauseForSecondsBeforeSwitchActive":30,"podAnnotations":{},"podDisruptionBudget":{},"podExtraSpecs":{},"podLabels":{},"podSecurityContext":{},"prometheus":{"release":"monitoring"},"prometheusRule":{"additionalLabels":{},"enabled":false,"namespace":""},"rawYaml":[],"replicaCount":1,"resources":{"limits":{"cpu":"0.05","memory":"50Mi"},"requests":{"cpu":"0.01","memory":"10Mi"}},"rolloutAnnotations":{},"rolloutLabels":{},"secret":{"data":{},"enabled":false},"server":{"deployment":{"image":"","image_tag":"1-95af053"}},"service":{"annotations":{},"loadBalancerSourceRanges":[],"type":"ClusterIP"},"serviceAccount":{"annotations":{},"create":false,"name":""},"servicemonitor":{"additionalLabels":{}},"tolerations":[],"topologySpreadConstraints":[],"volumeMounts":[],"volumes":[],"waitForSecondsBeforeScalingDown":30}`
-		variables, err := templateParser.ExtractVariables(sampleTemplate)
+		variables, err := templateParser.ExtractVariables(sampleTemplate, JsonVariableTemplate)
 		assert.Nil(t, err)
 		assert.Equal(t, 3, len(variables))
 		assert.Equal(t, "envoyPort", variables[0])
@@ -49,7 +52,9 @@ func TestVariableTemplateParserImpl_ExtractVariables(t *testing.T) {
 func TestVariableTemplateParserImpl_ParseTemplate(t *testing.T) {
 	logger, err := util.NewSugardLogger()
 	assert.Nil(t, err)
-	templateParser := NewVariableTemplateParserImpl(logger)
+	t.Setenv("SCOPED_VARIABLE_ENABLED", "true")
+	templateParser, err := NewVariableTemplateParserImpl(logger)
+	assert.Nil(t, err)
 	t.Run("parse template", func(t *testing.T) {
 		scopedVariables := []*models.ScopedVariableData{{VariableName: "container-port-number-new", VariableValue: &models.VariableValue{Value: "1800"}}}
 		parserResponse := templateParser.ParseTemplate(VariableParserRequest{TemplateType: JsonVariableTemplate, Template: JsonWithIntParam, Variables: scopedVariables})
@@ -73,3 +78,78 @@ func TestVariableTemplateParserImpl_ParseTemplate(t *testing.T) {
 		assert.Equal(t, StringTemplateWithIntParamResolvedTemplate, parserResponse.ResolvedTemplate)
 	})
 }
+
+const YamlTemplate = `# port of @{{service}}
+service: "@{{service}}"
+port: @{{port}}
+debug: @{{debug}}
+hosts: @{{hosts}}
+url: http://@{{service}}:@{{port}}/health
+script: @{{script}}
+`
+const YamlTemplateResolved = `# port of @{{service}}
+service: "8080"
+port: 8080
+debug: true
+hosts: [a.example.com, b.example.com]
+url: http://8080:8080/health
+script: |-
+  echo start
+  echo done
+`
+
+func TestVariableTemplateParserImpl_ParseYamlTemplate(t *testing.T) {
+	logger, err := util.NewSugardLogger()
+	assert.Nil(t, err)
+	t.Setenv("SCOPED_VARIABLE_ENABLED", "true")
+	templateParser, err := NewVariableTemplateParserImpl(logger)
+	assert.Nil(t, err)
+	scopedVariables := []*models.ScopedVariableData{
+		{VariableName: "service", VariableValue: &models.VariableValue{Value: "8080"}},
+		{VariableName: "port", VariableValue: &models.VariableValue{Value: 8080}},
+		{VariableName: "debug", VariableValue: &models.VariableValue{Value: "true"}},
+		{VariableName: "hosts", VariableValue: &models.VariableValue{Value: "[a.example.com, b.example.com]"}},
+		{VariableName: "script", VariableValue: &models.VariableValue{Value: "echo start\necho done"}},
+	}
+
+	t.Run("extract yaml variables", func(t *testing.T) {
+		variables, err := templateParser.ExtractVariables(YamlTemplate, YamlVariableTemplate)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"service", "service", "port", "debug", "hosts", "service", "port", "script"}, variables)
+	})
+
+	t.Run("parse yaml template with type preservation", func(t *testing.T) {
+		parserResponse := templateParser.ParseTemplate(VariableParserRequest{TemplateType: YamlVariableTemplate, Template: YamlTemplate, Variables: scopedVariables})
+		assert.Nil(t, parserResponse.Error)
+		assert.Equal(t, YamlTemplateResolved, parserResponse.ResolvedTemplate)
+	})
+
+	t.Run("parse yaml template with expression", func(t *testing.T) {
+		parserResponse := templateParser.ParseTemplate(VariableParserRequest{TemplateType: YamlVariableTemplate, Template: "port: @{{port + 1}}\n", Variables: scopedVariables})
+		assert.Nil(t, parserResponse.Error)
+		assert.Equal(t, "port: 8081\n", parserResponse.ResolvedTemplate)
+	})
+
+	t.Run("unknown variables are reported with position", func(t *testing.T) {
+		template := "env:\n  name: @{{service}}\n  region: \"@{{region}}\"\n"
+		parserResponse := templateParser.ParseTemplate(VariableParserRequest{TemplateType: YamlVariableTemplate, Template: template, Variables: scopedVariables})
+		assert.Equal(t, UnknownVariableFound, parserResponse.Error.Error())
+		assert.Equal(t, "unknown variables found, region (line 3, column 12)", parserResponse.DetailedError)
+		assert.Equal(t, template, parserResponse.ResolvedTemplate)
+	})
+
+	t.Run("yaml templates", func(t *testing.T) {
+		assert.True(t, templateParser.IsYamlTemplate(YamlTemplate))
+		assert.True(t, templateParser.IsYamlTemplate("- @{{service}}\n- b\n"))
+		assert.False(t, templateParser.IsYamlTemplate(JsonWithIntParam))
+		assert.False(t, templateParser.IsYamlTemplate("@{{service}}"))
+		assert.False(t, templateParser.IsYamlTemplate(""))
+	})
+
+	t.Run("unknown variables are kept when ignored", func(t *testing.T) {
+		template := "name: @{{service}}\nregion: @{{region}}\n"
+		parserResponse := templateParser.ParseTemplate(VariableParserRequest{TemplateType: YamlVariableTemplate, Template: template, Variables: scopedVariables, IgnoreUnknownVariables: true})
+		assert.Nil(t, parserResponse.Error)
+		assert.Equal(t, "name: 8080\nregion: '@{{region}}'\n", parserResponse.ResolvedTemplate)
+	})
+}
@@ -0,0 +1,391 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package parsers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/hashicorp/hcl2/hcl"
+	"github.com/hashicorp/hcl2/hcl/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+	ctyJson "github.com/zclconf/go-cty/cty/json"
+	"gopkg.in/yaml.v3"
+	"io"
+	"math/big"
+	"regexp"
+	"strings"
+)
+
+// placeholders are not valid yaml (a plain scalar can not start with @), so they are replaced with tokens before
+// parsing the template and the tokens are resolved on the parsed nodes
+const yamlPlaceholderTokenFormat = "__DEVTRON_VARIABLE_%d__"
+
+var yamlPlaceholderTokenRegex = regexp.MustCompile(`__DEVTRON_VARIABLE_\d+__`)
+
+type yamlPlaceholder struct {
+	token      string
+	original   string
+	expression string
+	line       int
+	column     int
+}
+
+type yamlTemplateError struct {
+	code   string
+	detail string
+}
+
+func (err *yamlTemplateError) Error() string {
+	return err.detail
+}
+
+type yamlTemplateResolver struct {
+	impl             *VariableTemplateParserImpl
+	placeholders     map[string]*yamlPlaceholder
+	values           map[string]interface{}
+	stringValues     map[string]string
+	unknownVariables []string
+}
+
+func (impl *VariableTemplateParserImpl) IsYamlTemplate(template string) bool {
+	if len(strings.TrimSpace(template)) == 0 || json.Valid([]byte(template)) {
+		return false
+	}
+	template, _ = impl.replaceYamlPlaceholders(template)
+	document := &yaml.Node{}
+	if err := yaml.Unmarshal([]byte(template), document); err != nil || len(document.Content) != 1 {
+		return false
+	}
+	return document.Content[0].Kind == yaml.MappingNode || document.Content[0].Kind == yaml.SequenceNode
+}
+
+func (impl *VariableTemplateParserImpl) extractYamlVariables(template string) ([]string, error) {
+	var variables []string
+	_, placeholders := impl.replaceYamlPlaceholders(template)
+	for _, placeholder := range placeholders {
+		hclExpression, diagnostics := hclsyntax.ParseExpression([]byte(placeholder.expression), "", hcl.Pos{Line: 1, Column: 1, Byte: 0})
+		if diagnostics.HasErrors() {
+			impl.logger.Errorw("error occurred while extracting variables from yaml template", "expression", placeholder.expression, "line", placeholder.line, "error", diagnostics.Error())
+			return variables, errors.New(InvalidTemplate)
+		}
+		variables = append(variables, impl.extractVarNames(hclExpression.Variables())...)
+	}
+	impl.logger.Debugw("extracted variables from yaml template", "variables", variables)
+	return variables, nil
+}
+
+// parseYamlTemplate resolves the placeholders node by node. A placeholder which is the whole plain scalar is replaced by
+// the yaml node of its value so that the type of the value is kept, any other placeholder is interpolated as string.
+func (impl *VariableTemplateParserImpl) parseYamlTemplate(parserRequest VariableParserRequest) VariableParserResponse {
+	response := parserRequest.GetEmptyResponse()
+	template, placeholders := impl.replaceYamlPlaceholders(parserRequest.Template)
+	if len(placeholders) == 0 {
+		return response
+	}
+	resolver := &yamlTemplateResolver{
+		impl:         impl,
+		placeholders: make(map[string]*yamlPlaceholder, len(placeholders)),
+		values:       parserRequest.GetOriginalValuesMap(),
+		stringValues: parserRequest.GetValuesMap(),
+	}
+	for _, placeholder := range placeholders {
+		resolver.placeholders[placeholder.token] = placeholder
+	}
+	documents, err := decodeYamlDocuments(template)
+	if err != nil {
+		impl.logger.Errorw("error occurred while parsing yaml template", "err", err)
+		response.Error = errors.New(InvalidTemplate)
+		response.DetailedError = resolver.restorePlaceholders(err.Error())
+		return response
+	}
+	for _, document := range documents {
+		err = walkYamlNodes(document, false, resolver.resolveNode)
+		if err != nil {
+			impl.logger.Errorw("error occurred while resolving yaml template", "err", err)
+			response.Error = errors.New(VariableParsingFailed)
+			response.DetailedError = err.Error()
+			var templateErr *yamlTemplateError
+			if errors.As(err, &templateErr) {
+				response.Error = errors.New(templateErr.code)
+			}
+			return response
+		}
+	}
+	if len(resolver.unknownVariables) > 0 && !parserRequest.IgnoreUnknownVariables {
+		impl.logger.Errorw("error occurred while parsing yaml template, unknown variables found", "unknownVariables", resolver.unknownVariables)
+		response.Error = errors.New(UnknownVariableFound)
+		response.DetailedError = fmt.Sprintf(UnknownVariableErrorMsg, strings.Join(resolver.unknownVariables, ", "))
+		return response
+	}
+	resolvedTemplate, err := encodeYamlDocuments(documents)
+	if err != nil {
+		impl.logger.Errorw("error occurred while marshalling resolved yaml template", "err", err)
+		response.Error = errors.New(VariableParsingFailed)
+		response.DetailedError = err.Error()
+		return response
+	}
+	response.ResolvedTemplate = resolvedTemplate
+	return response
+}
+
+func (impl *VariableTemplateParserImpl) replaceYamlPlaceholders(template string) (string, []*yamlPlaceholder) {
+	indexesData := impl.variableExpressionRegex.FindAllStringSubmatchIndex(template, -1)
+	placeholders := make([]*yamlPlaceholder, 0, len(indexesData))
+	var strBuilder strings.Builder
+	strBuilder.Grow(len(template))
+	currentIndex := 0
+	for i, datum := range indexesData {
+		if len(datum) < 4 {
+			continue
+		}
+		line, column := getLineAndColumn(template, datum[0])
+		placeholder := &yamlPlaceholder{
+			token:      fmt.Sprintf(yamlPlaceholderTokenFormat, i),
+			original:   template[datum[0]:datum[1]],
+			expression: strings.TrimSpace(template[datum[2]:datum[3]]),
+			line:       line,
+			column:     column,
+		}
+		placeholders = append(placeholders, placeholder)
+		strBuilder.WriteString(template[currentIndex:datum[0]] + placeholder.token)
+		currentIndex = datum[1]
+	}
+	strBuilder.WriteString(template[currentIndex:])
+	return strBuilder.String(), placeholders
+}
+
+func (resolver *yamlTemplateResolver) resolveNode(node *yaml.Node, isKey bool) error {
+	node.HeadComment = resolver.restorePlaceholders(node.HeadComment)
+	node.LineComment = resolver.restorePlaceholders(node.LineComment)
+	node.FootComment = resolver.restorePlaceholders(node.FootComment)
+	if node.Kind != yaml.ScalarNode {
+		return nil
+	}
+	tokenIndexes := yamlPlaceholderTokenRegex.FindAllStringIndex(node.Value, -1)
+	if len(tokenIndexes) == 0 {
+		return nil
+	}
+	if placeholder, ok := resolver.placeholders[node.Value]; ok && !isKey && node.Style == 0 {
+		value, resolved, err := resolver.evaluate(placeholder)
+		if err != nil {
+			return err
+		}
+		if !resolved {
+			node.Value = placeholder.original
+			return nil
+		}
+		valueNode, err := getYamlNodeForValue(value)
+		if err != nil {
+			return err
+		}
+		valueNode.HeadComment, valueNode.LineComment, valueNode.FootComment = node.HeadComment, node.LineComment, node.FootComment
+		*node = *valueNode
+		return nil
+	}
+	var strBuilder strings.Builder
+	currentIndex := 0
+	for _, tokenIndex := range tokenIndexes {
+		strBuilder.WriteString(node.Value[currentIndex:tokenIndex[0]])
+		currentIndex = tokenIndex[1]
+		placeholder, ok := resolver.placeholders[node.Value[tokenIndex[0]:tokenIndex[1]]]
+		if !ok {
+			strBuilder.WriteString(node.Value[tokenIndex[0]:tokenIndex[1]])
+			continue
+		}
+		value, resolved, err := resolver.evaluate(placeholder)
+		if err != nil {
+			return err
+		}
+		if resolved {
+			strBuilder.WriteString(formatYamlInterpolatedValue(value))
+		} else {
+			strBuilder.WriteString(placeholder.original)
+		}
+	}
+	strBuilder.WriteString(node.Value[currentIndex:])
+	node.Value = strBuilder.String()
+	return nil
+}
+
+// evaluate returns the value of the placeholder, a placeholder with a single variable keeps the type of its value.
+// It returns false if the placeholder uses unknown variables, these are collected with their position.
+func (resolver *yamlTemplateResolver) evaluate(placeholder *yamlPlaceholder) (interface{}, bool, error) {
+	hclExpression, diagnostics := hclsyntax.ParseExpression([]byte(placeholder.expression), "", hcl.Pos{Line: 1, Column: 1, Byte: 0})
+	if diagnostics.HasErrors() {
+		return nil, false, &yamlTemplateError{
+			code:   InvalidTemplate,
+			detail: fmt.Sprintf("invalid expression %s at line %d, column %d, %s", placeholder.original, placeholder.line, placeholder.column, diagnostics.Error()),
+		}
+	}
+	resolved := true
+	for _, traversal := range hclExpression.Variables() {
+		if _, ok := resolver.values[traversal.RootName()]; !ok {
+			resolver.unknownVariables = append(resolver.unknownVariables, fmt.Sprintf("%s (line %d, column %d)", traversal.RootName(), placeholder.line, placeholder.column))
+			resolved = false
+		}
+	}
+	if !resolved {
+		return nil, false, nil
+	}
+	if traversal, traversalDiagnostics := hcl.AbsTraversalForExpr(hclExpression); !traversalDiagnostics.HasErrors() && len(traversal) == 1 {
+		return resolver.values[traversal.RootName()], true, nil
+	}
+	opValue, diagnostics := hclExpression.Value(&hcl.EvalContext{
+		Variables: resolver.impl.getHclVarValues(resolver.stringValues),
+		Functions: resolver.impl.getDefaultMappedFunc(),
+	})
+	if diagnostics.HasErrors() {
+		return nil, false, &yamlTemplateError{
+			code:   VariableParsingFailed,
+			detail: fmt.Sprintf("error in evaluating %s at line %d, column %d, %s", placeholder.original, placeholder.line, placeholder.column, diagnostics.Error()),
+		}
+	}
+	value, err := getValueFromCty(opValue)
+	return value, err == nil, err
+}
+
+func (resolver *yamlTemplateResolver) restorePlaceholders(value string) string {
+	if len(value) == 0 {
+		return value
+	}
+	return yamlPlaceholderTokenRegex.ReplaceAllStringFunc(value, func(token string) string {
+		if placeholder, ok := resolver.placeholders[token]; ok {
+			return placeholder.original
+		}
+		return token
+	})
+}
+
+// getYamlNodeForValue returns the node injected in place of an unquoted placeholder. A string value is read as yaml,
+// as if it was written in place of the placeholder, so that lists and maps are injected as yaml nodes and single line
+// numbers and booleans keep their type. Any other string stays a string, multiline strings as literal block.
+func getYamlNodeForValue(value interface{}) (*yaml.Node, error) {
+	stringValue, ok := value.(string)
+	if !ok {
+		valueNode := &yaml.Node{}
+		err := valueNode.Encode(value)
+		return valueNode, err
+	}
+	document := &yaml.Node{}
+	if err := yaml.Unmarshal([]byte(stringValue), document); err == nil && len(document.Content) == 1 {
+		content := document.Content[0]
+		switch content.Kind {
+		case yaml.MappingNode, yaml.SequenceNode:
+			return content, nil
+		case yaml.ScalarNode:
+			if !strings.Contains(stringValue, "\n") && (content.Tag == "!!int" || content.Tag == "!!float" || content.Tag == "!!bool") {
+				return content, nil
+			}
+		}
+	}
+	stringNode := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: stringValue}
+	if strings.Contains(stringValue, "\n") {
+		stringNode.Style = yaml.LiteralStyle
+	}
+	return stringNode, nil
+}
+
+func formatYamlInterpolatedValue(value interface{}) string {
+	if stringValue, ok := value.(string); ok {
+		return stringValue
+	}
+	jsonValue, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(jsonValue)
+}
+
+func getValueFromCty(value cty.Value) (interface{}, error) {
+	if value.IsNull() || !value.IsKnown() {
+		return nil, nil
+	}
+	switch value.Type() {
+	case cty.String:
+		return value.AsString(), nil
+	case cty.Bool:
+		return value.True(), nil
+	case cty.Number:
+		bigFloat := value.AsBigFloat()
+		if intValue, accuracy := bigFloat.Int64(); bigFloat.IsInt() && accuracy == big.Exact {
+			return int(intValue), nil
+		}
+		floatValue, _ := bigFloat.Float64()
+		return floatValue, nil
+	}
+	jsonValue, err := ctyJson.SimpleJSONValue{Value: value}.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	var goValue interface{}
+	err = json.Unmarshal(jsonValue, &goValue)
+	return goValue, err
+}
+
+func walkYamlNodes(node *yaml.Node, isKey bool, visit func(node *yaml.Node, isKey bool) error) error {
+	err := visit(node, isKey)
+	if err != nil {
+		return err
+	}
+	for i, child := range node.Content {
+		err = walkYamlNodes(child, node.Kind == yaml.MappingNode && i%2 == 0, visit)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func decodeYamlDocuments(template string) ([]*yaml.Node, error) {
+	var documents []*yaml.Node
+	decoder := yaml.NewDecoder(strings.NewReader(template))
+	for {
+		document := &yaml.Node{}
+		err := decoder.Decode(document)
+		if err == io.EOF {
+			return documents, nil
+		} else if err != nil {
+			return nil, err
+		}
+		documents = append(documents, document)
+	}
+}
+
+func encodeYamlDocuments(documents []*yaml.Node) (string, error) {
+	var buffer bytes.Buffer
+	encoder := yaml.NewEncoder(&buffer)
+	encoder.SetIndent(2)
+	for _, document := range documents {
+		err := encoder.Encode(document)
+		if err != nil {
+			return "", err
+		}
+	}
+	err := encoder.Close()
+	if err != nil {
+		return "", err
+	}
+	return buffer.String(), nil
+}
+
+// getLineAndColumn returns the 1 based line and column of the offset in the template
+func getLineAndColumn(template string, offset int) (int, int) {
+	prefix := template[:offset]
+	return strings.Count(prefix, "\n") + 1, offset - strings.LastIndex(prefix, "\n")
+}
@@ -32,6 +32,7 @@ type VariableTemplateType int
 const (
 	StringVariableTemplate VariableTemplateType = 0
 	JsonVariableTemplate                        = 1
+	YamlVariableTemplate                        = 2
 )
 
 const DefaultVariableTemplate = JsonVariableTemplate
@@ -100,7 +101,7 @@ func GetVariableMapForUsedVariables(scopedVariables []*models.ScopedVariableData
 	variableMap := make(map[string]string)
 	for _, variable := range scopedVariables {
 		if slices.Contains(usedVars, variable.VariableName) {
-			variableMap[variable.VariableName] = variable.VariableValue.StringValue()
+			variableMap[variable.VariableName] = variable.GetSnapshotValue()
 		}
 	}
 	return variableMap
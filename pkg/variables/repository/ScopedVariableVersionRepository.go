@@ -0,0 +1,88 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+)
+
+// ScopedVariableVersion is a save of the scoped variables, Payload is the json of the whole models.Payload saved.
+// RolledBackFromVersion is set when the version is created by rolling back to an older version.
+type ScopedVariableVersion struct {
+	tableName             struct{} `sql:"scoped_variable_version" pg:",discard_unknown_columns"`
+	Id                    int      `sql:"id,pk"`
+	Version               int      `sql:"version,notnull"`
+	Payload               string   `sql:"payload,notnull"`
+	RolledBackFromVersion int      `sql:"rolled_back_from_version"`
+	sql.AuditLog
+}
+
+type ScopedVariableVersionRepository interface {
+	SaveVersion(version *ScopedVariableVersion, tx *pg.Tx) error
+	// GetLatestVersionNumber returns 0 if no version is saved yet
+	GetLatestVersionNumber(tx *pg.Tx) (int, error)
+	GetVersion(version int) (*ScopedVariableVersion, error)
+	// GetVersions returns the versions latest first, payload is not selected
+	GetVersions(offset, limit int) ([]*ScopedVariableVersion, error)
+}
+
+type ScopedVariableVersionRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewScopedVariableVersionRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *ScopedVariableVersionRepositoryImpl {
+	return &ScopedVariableVersionRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *ScopedVariableVersionRepositoryImpl) SaveVersion(version *ScopedVariableVersion, tx *pg.Tx) error {
+	return tx.Insert(version)
+}
+
+func (impl *ScopedVariableVersionRepositoryImpl) GetLatestVersionNumber(tx *pg.Tx) (int, error) {
+	var latestVersion int
+	_, err := tx.Query(pg.Scan(&latestVersion), "SELECT COALESCE(MAX(version), 0) FROM scoped_variable_version")
+	if err != nil {
+		impl.logger.Errorw("error in getting latest scoped variable version", "err", err)
+		return 0, err
+	}
+	return latestVersion, nil
+}
+
+func (impl *ScopedVariableVersionRepositoryImpl) GetVersion(version int) (*ScopedVariableVersion, error) {
+	variableVersion := &ScopedVariableVersion{}
+	err := impl.dbConnection.Model(variableVersion).
+		Where("version = ?", version).
+		Select()
+	return variableVersion, err
+}
+
+func (impl *ScopedVariableVersionRepositoryImpl) GetVersions(offset, limit int) ([]*ScopedVariableVersion, error) {
+	var versions []*ScopedVariableVersion
+	err := impl.dbConnection.Model(&versions).
+		Column("id", "version", "rolled_back_from_version", "created_on", "created_by", "updated_on", "updated_by").
+		Order("version DESC").
+		Offset(offset).
+		Limit(limit).
+		Select()
+	return versions, err
+}
@@ -47,6 +47,41 @@ const (
 	EntityTypeSecretEnvLevel             EntityType = 7
 )
 
+func (entityType EntityType) String() string {
+	switch entityType {
+	case EntityTypeDeploymentTemplateAppLevel:
+		return "DeploymentTemplateAppLevel"
+	case EntityTypeDeploymentTemplateEnvLevel:
+		return "DeploymentTemplateEnvLevel"
+	case EntityTypePipelineStage:
+		return "PipelineStage"
+	case EntityTypeConfigMapAppLevel:
+		return "ConfigMapAppLevel"
+	case EntityTypeConfigMapEnvLevel:
+		return "ConfigMapEnvLevel"
+	case EntityTypeSecretAppLevel:
+		return "SecretAppLevel"
+	case EntityTypeSecretEnvLevel:
+		return "SecretEnvLevel"
+	default:
+		return ""
+	}
+}
+
+// VariableEntityReference is an entity using a variable along with the app, environment and pipeline of the entity.
+// EnvId is 0 for the app level entities and the pipeline ids are set only for the pipeline stages.
+type VariableEntityReference struct {
+	VariableName string     `sql:"variable_name"`
+	EntityType   EntityType `sql:"entity_type"`
+	EntityId     int        `sql:"entity_id"`
+	AppId        int        `sql:"app_id"`
+	AppName      string     `sql:"app_name"`
+	EnvId        int        `sql:"env_id"`
+	EnvName      string     `sql:"env_name"`
+	CiPipelineId int        `sql:"ci_pipeline_id"`
+	CdPipelineId int        `sql:"cd_pipeline_id"`
+}
+
 func GetEntity(entityId int, entityType EntityType) Entity {
 
 	return Entity{
@@ -30,6 +30,7 @@ type VariableEntityMappingRepository interface {
 	SaveVariableEntityMappings(tx *pg.Tx, mappings []*VariableEntityMapping) error
 	DeleteAllVariablesForEntities(tx *pg.Tx, entities []Entity, userId int32) error
 	DeleteVariablesForEntity(tx *pg.Tx, variableIDs []string, entity Entity, userId int32) error
+	GetEntityReferencesForVariables(variableNames []string) ([]*VariableEntityReference, error)
 }
 
 func NewVariableEntityMappingRepository(logger *zap.SugaredLogger, dbConnection *pg.DB, TransactionUtilImpl *sql.TransactionUtilImpl) *VariableEntityMappingRepositoryImpl {
@@ -116,3 +117,34 @@ func (impl *VariableEntityMappingRepositoryImpl) DeleteAllVariablesForEntities(t
 	}
 	return nil
 }
+
+func (impl *VariableEntityMappingRepositoryImpl) GetEntityReferencesForVariables(variableNames []string) ([]*VariableEntityReference, error) {
+	references := make([]*VariableEntityReference, 0)
+	if len(variableNames) == 0 {
+		return references, nil
+	}
+	query := "SELECT vem.variable_name, vem.entity_type, vem.entity_id, a.id AS app_id, a.app_name, " +
+		" env.id AS env_id, env.environment_name AS env_name, ps.ci_pipeline_id, ps.cd_pipeline_id " +
+		" FROM variable_entity_mapping vem " +
+		" LEFT JOIN charts c ON vem.entity_type = ? AND c.id = vem.entity_id " +
+		" LEFT JOIN chart_env_config_override ceco ON vem.entity_type = ? AND ceco.id = vem.entity_id " +
+		" LEFT JOIN charts oc ON oc.id = ceco.chart_id " +
+		" LEFT JOIN pipeline_stage ps ON vem.entity_type = ? AND ps.id = vem.entity_id " +
+		" LEFT JOIN ci_pipeline cp ON cp.id = ps.ci_pipeline_id " +
+		" LEFT JOIN pipeline p ON p.id = ps.cd_pipeline_id " +
+		" LEFT JOIN config_map_app_level cma ON vem.entity_type IN (?, ?) AND cma.id = vem.entity_id " +
+		" LEFT JOIN config_map_env_level cme ON vem.entity_type IN (?, ?) AND cme.id = vem.entity_id " +
+		" LEFT JOIN app a ON a.id = COALESCE(c.app_id, oc.app_id, cp.app_id, p.app_id, cma.app_id, cme.app_id) " +
+		" LEFT JOIN environment env ON env.id = COALESCE(ceco.target_environment, p.environment_id, cme.environment_id) " +
+		" WHERE vem.is_deleted = false AND vem.variable_name IN (?) " +
+		" ORDER BY vem.variable_name, a.app_name, vem.entity_type;"
+	_, err := impl.dbConnection.Query(&references, query,
+		EntityTypeDeploymentTemplateAppLevel, EntityTypeDeploymentTemplateEnvLevel, EntityTypePipelineStage,
+		EntityTypeConfigMapAppLevel, EntityTypeSecretAppLevel, EntityTypeConfigMapEnvLevel, EntityTypeSecretEnvLevel,
+		pg.In(variableNames))
+	if err != nil {
+		impl.logger.Errorw("err in getting entity references for variables", "variableNames", variableNames, "err", err)
+		return nil, err
+	}
+	return references, nil
+}
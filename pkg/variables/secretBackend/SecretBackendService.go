@@ -0,0 +1,134 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package secretBackend
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/caarlos0/env"
+	"go.uber.org/zap"
+)
+
+// SecretBackendService resolves the values of the sensitive variables which are kept in an external secret manager,
+// only the references to the secrets are saved in the database
+type SecretBackendService interface {
+	IsEnabled() bool
+	// IsSecretReference returns true if the value is a reference to a secret of the configured backend
+	IsSecretReference(value string) bool
+	// ResolveSecretReference returns the value of the key of the secret referred to, a *ResolutionError is returned on failure
+	ResolveSecretReference(value string) (string, error)
+}
+
+type secretCacheEntry struct {
+	secret    map[string]string
+	expiresOn time.Time
+}
+
+type SecretBackendServiceImpl struct {
+	logger   *zap.SugaredLogger
+	backend  SecretBackend
+	cacheTTL time.Duration
+	// cache keeps the secrets by their path so that the keys of the same secret are read once
+	cache     map[string]*secretCacheEntry
+	cacheLock *sync.RWMutex
+}
+
+func NewSecretBackendServiceImpl(logger *zap.SugaredLogger) (*SecretBackendServiceImpl, error) {
+	cfg := &SecretBackendConfig{}
+	err := env.Parse(cfg)
+	if err != nil {
+		return nil, err
+	}
+	var backend SecretBackend
+	switch cfg.BackendType {
+	case BackendTypeNone:
+	case BackendTypeVault:
+		backend, err = NewVaultKVBackend(cfg)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported secret backend %s for scoped variables", cfg.BackendType)
+	}
+	return newSecretBackendServiceImpl(logger, backend, time.Duration(cfg.CacheTTLSeconds)*time.Second), nil
+}
+
+func newSecretBackendServiceImpl(logger *zap.SugaredLogger, backend SecretBackend, cacheTTL time.Duration) *SecretBackendServiceImpl {
+	return &SecretBackendServiceImpl{
+		logger:    logger,
+		backend:   backend,
+		cacheTTL:  cacheTTL,
+		cache:     make(map[string]*secretCacheEntry),
+		cacheLock: &sync.RWMutex{},
+	}
+}
+
+func (impl *SecretBackendServiceImpl) IsEnabled() bool {
+	return impl.backend != nil
+}
+
+func (impl *SecretBackendServiceImpl) IsSecretReference(value string) bool {
+	if !impl.IsEnabled() {
+		return false
+	}
+	_, ok := ParseSecretReference(impl.backend.ReferencePrefix(), value)
+	return ok
+}
+
+func (impl *SecretBackendServiceImpl) ResolveSecretReference(value string) (string, error) {
+	if !impl.IsEnabled() {
+		return "", &ResolutionError{Reference: value, Err: fmt.Errorf("secret backend is not configured")}
+	}
+	reference, ok := ParseSecretReference(impl.backend.ReferencePrefix(), value)
+	if !ok {
+		return "", &ResolutionError{Reference: value, Err: fmt.Errorf("invalid reference, expected %s:<path>#<key>", impl.backend.ReferencePrefix())}
+	}
+	secret, err := impl.getSecret(reference.Path)
+	if err != nil {
+		impl.logger.Errorw("error in reading secret from secret backend", "reference", reference.String(), "err", err)
+		return "", &ResolutionError{Reference: reference.String(), Err: err}
+	}
+	secretValue, ok := secret[reference.Key]
+	if !ok {
+		return "", &ResolutionError{Reference: reference.String(), Err: fmt.Errorf("key %s not found in secret %s", reference.Key, reference.Path)}
+	}
+	return secretValue, nil
+}
+
+func (impl *SecretBackendServiceImpl) getSecret(path string) (map[string]string, error) {
+	if impl.cacheTTL > 0 {
+		impl.cacheLock.RLock()
+		entry, ok := impl.cache[path]
+		impl.cacheLock.RUnlock()
+		if ok && time.Now().Before(entry.expiresOn) {
+			return entry.secret, nil
+		}
+	}
+	secret, err := impl.backend.GetSecret(context.Background(), path)
+	if err != nil {
+		return nil, err
+	}
+	if impl.cacheTTL > 0 {
+		impl.cacheLock.Lock()
+		impl.cache[path] = &secretCacheEntry{secret: secret, expiresOn: time.Now().Add(impl.cacheTTL)}
+		impl.cacheLock.Unlock()
+	}
+	return secret, nil
+}
@@ -0,0 +1,135 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package secretBackend
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func newMockVaultServer(requestCount *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requestCount++
+		if r.Header.Get("X-Vault-Token") != "test-token" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/devtron/db":
+			_, _ = w.Write([]byte(`{"data":{"data":{"password":"s3cr3t","port":5432},"metadata":{"version":2}}}`))
+		case "/v1/secret/data/devtron/denied":
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errors":["1 error occurred:\n\t* permission denied\n\n"]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errors":[]}`))
+		}
+	}))
+}
+
+func newTestService(t *testing.T, address string, token string, cacheTTL time.Duration) *SecretBackendServiceImpl {
+	backend, err := NewVaultKVBackend(&SecretBackendConfig{
+		VaultAddress:        address,
+		VaultToken:          token,
+		VaultKVMount:        "secret",
+		VaultTimeoutSeconds: 5,
+	})
+	assert.NoError(t, err)
+	return newSecretBackendServiceImpl(zap.NewNop().Sugar(), backend, cacheTTL)
+}
+
+func TestParseSecretReference(t *testing.T) {
+	reference, ok := ParseSecretReference("vault", "vault:/devtron/db/#password")
+	assert.True(t, ok)
+	assert.Equal(t, &SecretReference{Backend: "vault", Path: "devtron/db", Key: "password"}, reference)
+
+	reference, ok = ParseSecretReference("vault", "vault:devtron/db")
+	assert.True(t, ok)
+	assert.Equal(t, DefaultReferenceKey, reference.Key)
+
+	_, ok = ParseSecretReference("vault", "plain-value")
+	assert.False(t, ok)
+	_, ok = ParseSecretReference("vault", "vault:#password")
+	assert.False(t, ok)
+}
+
+func TestResolveSecretReference(t *testing.T) {
+	requestCount := 0
+	server := newMockVaultServer(&requestCount)
+	defer server.Close()
+	service := newTestService(t, server.URL, "test-token", time.Minute)
+
+	assert.True(t, service.IsSecretReference("vault:devtron/db#password"))
+	assert.False(t, service.IsSecretReference("s3cr3t"))
+
+	value, err := service.ResolveSecretReference("vault:devtron/db#password")
+	assert.NoError(t, err)
+	assert.Equal(t, "s3cr3t", value)
+	value, err = service.ResolveSecretReference("vault:devtron/db#port")
+	assert.NoError(t, err)
+	assert.Equal(t, "5432", value)
+	// both keys are read from the cached secret
+	assert.Equal(t, 1, requestCount)
+
+	_, err = service.ResolveSecretReference("vault:devtron/db#user")
+	var resolutionError *ResolutionError
+	assert.True(t, errors.As(err, &resolutionError))
+	assert.Contains(t, err.Error(), "key user not found in secret devtron/db")
+
+	_, err = service.ResolveSecretReference("vault:devtron/missing#password")
+	assert.ErrorContains(t, err, "secret not found at devtron/missing")
+
+	_, err = service.ResolveSecretReference("vault:devtron/denied#password")
+	assert.ErrorContains(t, err, "permission denied for secret devtron/denied")
+}
+
+func TestResolveSecretReferenceCacheExpiry(t *testing.T) {
+	requestCount := 0
+	server := newMockVaultServer(&requestCount)
+	defer server.Close()
+
+	service := newTestService(t, server.URL, "test-token", 0)
+	for i := 0; i < 2; i++ {
+		_, err := service.ResolveSecretReference("vault:devtron/db#password")
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 2, requestCount)
+
+	requestCount = 0
+	service = newTestService(t, server.URL, "test-token", time.Minute)
+	_, err := service.ResolveSecretReference("vault:devtron/db#password")
+	assert.NoError(t, err)
+	service.cache["devtron/db"].expiresOn = time.Now().Add(-time.Second)
+	_, err = service.ResolveSecretReference("vault:devtron/db#password")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, requestCount)
+}
+
+func TestResolveSecretReferenceWithoutBackend(t *testing.T) {
+	service := newSecretBackendServiceImpl(zap.NewNop().Sugar(), nil, time.Minute)
+	assert.False(t, service.IsEnabled())
+	assert.False(t, service.IsSecretReference("vault:devtron/db#password"))
+	_, err := service.ResolveSecretReference("vault:devtron/db#password")
+	assert.ErrorContains(t, err, "secret backend is not configured")
+}
@@ -0,0 +1,88 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package secretBackend
+
+import (
+	"fmt"
+	"strings"
+)
+
+type BackendType string
+
+const (
+	// BackendTypeNone keeps the values of the sensitive variables in the database
+	BackendTypeNone  BackendType = ""
+	BackendTypeVault BackendType = "VAULT"
+)
+
+// referenceKeySeparator separates the path of the secret from the key in the secret
+const referenceKeySeparator = "#"
+
+// DefaultReferenceKey is the key read from the secret when the reference does not have one
+const DefaultReferenceKey = "value"
+
+type SecretBackendConfig struct {
+	BackendType         BackendType `env:"SCOPED_VARIABLE_SECRET_BACKEND" envDefault:"" description:"Backend for the values of the sensitive scoped variables, VAULT or empty to keep the values in the database. With a backend the values of the sensitive variables must be references like vault:<path>#<key>."`
+	CacheTTLSeconds     int         `env:"SCOPED_VARIABLE_SECRET_CACHE_TTL_SECS" envDefault:"300" description:"Time in seconds for which the secrets read from the secret backend are cached, 0 disables the cache."`
+	VaultAddress        string      `env:"VAULT_ADDR" envDefault:"" description:"Address of the Vault server used as the secret backend of the sensitive scoped variables."`
+	VaultToken          string      `env:"VAULT_TOKEN" envDefault:"" description:"Token used for reading the secrets of the sensitive scoped variables from Vault."`
+	VaultNamespace      string      `env:"VAULT_NAMESPACE" envDefault:"" description:"Vault enterprise namespace of the secrets of the sensitive scoped variables."`
+	VaultKVMount        string      `env:"VAULT_KV_MOUNT" envDefault:"secret" description:"Mount path of the KV v2 secrets engine holding the secrets of the sensitive scoped variables."`
+	VaultTimeoutSeconds int         `env:"VAULT_REQUEST_TIMEOUT_SECS" envDefault:"10" description:"Timeout in seconds of the requests to Vault."`
+}
+
+// SecretReference points to a key of a secret in a backend, it is written as <backend>:<path>#<key> e.g. vault:devtron/db#password
+type SecretReference struct {
+	Backend string
+	Path    string
+	Key     string
+}
+
+func (reference *SecretReference) String() string {
+	return fmt.Sprintf("%s:%s%s%s", reference.Backend, reference.Path, referenceKeySeparator, reference.Key)
+}
+
+// ParseSecretReference parses the reference for the backend, returns false if the value is not a reference for it
+func ParseSecretReference(backend string, value string) (*SecretReference, bool) {
+	prefix := backend + ":"
+	if !strings.HasPrefix(value, prefix) {
+		return nil, false
+	}
+	path, key, found := strings.Cut(strings.TrimPrefix(value, prefix), referenceKeySeparator)
+	if !found || len(key) == 0 {
+		key = DefaultReferenceKey
+	}
+	path = strings.Trim(path, "/")
+	if len(path) == 0 {
+		return nil, false
+	}
+	return &SecretReference{Backend: backend, Path: path, Key: key}, true
+}
+
+// ResolutionError is returned when the value of a reference can not be read from the backend
+type ResolutionError struct {
+	Reference string
+	Err       error
+}
+
+func (err *ResolutionError) Error() string {
+	return fmt.Sprintf("could not resolve secret reference %s: %s", err.Reference, err.Err.Error())
+}
+
+func (err *ResolutionError) Unwrap() error {
+	return err.Err
+}
@@ -0,0 +1,136 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package secretBackend
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const vaultReferencePrefix = "vault"
+
+// SecretBackend reads the secrets of the sensitive variables from an external secret manager
+type SecretBackend interface {
+	// ReferencePrefix is the prefix of the references to the secrets of the backend
+	ReferencePrefix() string
+	// GetSecret returns all the keys of the secret at path
+	GetSecret(ctx context.Context, path string) (map[string]string, error)
+}
+
+// VaultKVBackend reads the secrets from a KV v2 secrets engine of HashiCorp Vault
+type VaultKVBackend struct {
+	address    string
+	token      string
+	namespace  string
+	mount      string
+	httpClient *http.Client
+}
+
+func NewVaultKVBackend(cfg *SecretBackendConfig) (*VaultKVBackend, error) {
+	if len(cfg.VaultAddress) == 0 || len(cfg.VaultToken) == 0 {
+		return nil, fmt.Errorf("VAULT_ADDR and VAULT_TOKEN are required for the vault secret backend")
+	}
+	return &VaultKVBackend{
+		address:    strings.TrimSuffix(cfg.VaultAddress, "/"),
+		token:      cfg.VaultToken,
+		namespace:  cfg.VaultNamespace,
+		mount:      strings.Trim(cfg.VaultKVMount, "/"),
+		httpClient: &http.Client{Timeout: time.Duration(cfg.VaultTimeoutSeconds) * time.Second},
+	}, nil
+}
+
+func (impl *VaultKVBackend) ReferencePrefix() string {
+	return vaultReferencePrefix
+}
+
+type vaultKVResponse struct {
+	Data struct {
+		Data map[string]interface{} `json:"data"`
+	} `json:"data"`
+	Errors []string `json:"errors"`
+}
+
+func (impl *VaultKVBackend) GetSecret(ctx context.Context, path string) (map[string]string, error) {
+	secretUrl := fmt.Sprintf("%s/v1/%s/data/%s", impl.address, url.PathEscape(impl.mount), escapeSecretPath(path))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, secretUrl, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", impl.token)
+	if len(impl.namespace) > 0 {
+		req.Header.Set("X-Vault-Namespace", impl.namespace)
+	}
+	resp, err := impl.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("vault is not reachable: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error in reading vault response: %w", err)
+	}
+	vaultResponse := &vaultKVResponse{}
+	// the error responses are json as well, the body is ignored if it can not be decoded
+	_ = json.Unmarshal(body, vaultResponse)
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, fmt.Errorf("secret not found at %s in mount %s", path, impl.mount)
+	case http.StatusForbidden:
+		return nil, fmt.Errorf("permission denied for secret %s in mount %s%s", path, impl.mount, formatVaultErrors(vaultResponse.Errors))
+	default:
+		return nil, fmt.Errorf("vault responded with status %d%s", resp.StatusCode, formatVaultErrors(vaultResponse.Errors))
+	}
+	if vaultResponse.Data.Data == nil {
+		// the latest version of the secret is deleted
+		return nil, fmt.Errorf("secret not found at %s in mount %s", path, impl.mount)
+	}
+	secret := make(map[string]string, len(vaultResponse.Data.Data))
+	for key, value := range vaultResponse.Data.Data {
+		if stringValue, ok := value.(string); ok {
+			secret[key] = stringValue
+			continue
+		}
+		jsonValue, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("error in reading key %s of secret %s: %w", key, path, err)
+		}
+		secret[key] = string(jsonValue)
+	}
+	return secret, nil
+}
+
+func escapeSecretPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+func formatVaultErrors(errors []string) string {
+	if len(errors) == 0 {
+		return ""
+	}
+	return ": " + strings.Join(errors, ", ")
+}
@@ -59,6 +59,7 @@ import (
 	"github.com/devtron-labs/devtron/pkg/executor"
 	"github.com/devtron-labs/devtron/pkg/fluxApplication"
 	bean8 "github.com/devtron-labs/devtron/pkg/fluxApplication/bean"
+	"github.com/devtron-labs/devtron/pkg/jira"
 	k8sPkg "github.com/devtron-labs/devtron/pkg/k8s"
 	"github.com/devtron-labs/devtron/pkg/pipeline"
 	constants2 "github.com/devtron-labs/devtron/pkg/pipeline/constants"
@@ -160,6 +161,7 @@ type WorkflowDagExecutorImpl struct {
 	ciHandlerService            trigger.HandlerService
 	workflowTriggerAuditService auditService.WorkflowTriggerAuditService
 	fluxApplicationService      fluxApplication.FluxApplicationService
+	jiraIntegrationService      jira.JiraIntegrationService
 }
 
 func NewWorkflowDagExecutorImpl(Logger *zap.SugaredLogger, pipelineRepository pipelineConfig.PipelineRepository,
@@ -196,6 +198,7 @@ func NewWorkflowDagExecutorImpl(Logger *zap.SugaredLogger, pipelineRepository pi
 	ciHandlerService trigger.HandlerService,
 	workflowTriggerAuditService auditService.WorkflowTriggerAuditService,
 	fluxApplicationService fluxApplication.FluxApplicationService,
+	jiraIntegrationService jira.JiraIntegrationService,
 ) *WorkflowDagExecutorImpl {
 	wde := &WorkflowDagExecutorImpl{logger: Logger,
 		pipelineRepository:            pipelineRepository,
@@ -231,7 +234,8 @@ func NewWorkflowDagExecutorImpl(Logger *zap.SugaredLogger, pipelineRepository pi
 		workflowService:               workflowService,
 		ciHandlerService:              ciHandlerService,
 		workflowTriggerAuditService:   workflowTriggerAuditService,
-		fluxApplicationService:        fluxApplicationService}
+		fluxApplicationService:        fluxApplicationService,
+		jiraIntegrationService:        jiraIntegrationService}
 	config, err := types.GetCdConfig()
 	if err != nil {
 		return nil
@@ -320,7 +324,7 @@ func (impl *WorkflowDagExecutorImpl) UpdateCiWorkflowStatusFailure(timeoutForFai
 						// skip this and process for next ci workflow
 					}
 				}
-				if ciWorkflow.ExecutorType == cdWorkflow2.WORKFLOW_EXECUTOR_TYPE_SYSTEM {
+				if ciWorkflow.ExecutorType.IsPodExecutor() {
 					if wf.Status == string(v1alpha1.WorkflowFailed) {
 						isPodDeleted = true
 					}
@@ -812,6 +816,9 @@ func (impl *WorkflowDagExecutorImpl) HandleDeploymentSuccessEvent(triggerContext
 		impl.logger.Errorw("error in fetching cd workflow by id", "pipelineOverride", pipelineOverride)
 		return err
 	}
+	if pipelineOverride.DeploymentType != models.DEPLOYMENTTYPE_STOP && pipelineOverride.DeploymentType != models.DEPLOYMENTTYPE_START {
+		impl.jiraIntegrationService.TransitionIssuesOnDeploymentSuccess(cdWorkflow.Id)
+	}
 
 	postStage, err := impl.getPipelineStage(pipelineOverride.PipelineId, repository4.PIPELINE_STAGE_TYPE_POST_CD)
 	if err != nil {
@@ -0,0 +1,6 @@
+/*
+ * Copyright (c) 2025. Devtron Inc.
+ */
+
+ALTER TABLE public.gitops_config
+    DROP COLUMN IF EXISTS gitea_org_id;
@@ -0,0 +1,7 @@
+/*
+ * Copyright (c) 2025. Devtron Inc.
+ */
+
+-- Add gitea_org_id column to gitops_config for the Gitea/Forgejo gitops provider
+ALTER TABLE public.gitops_config
+    ADD COLUMN IF NOT EXISTS gitea_org_id VARCHAR(250);
@@ -0,0 +1,14 @@
+/*
+ * Copyright (c) 2025. Devtron Inc.
+ */
+
+-- resource_type 6 is the cel policy resource type of resource qualifier mappings
+DELETE FROM resource_qualifier_mapping WHERE resource_type = 6;
+
+-- PROJECT_ID searchable key is kept, it is used by the other resource qualifier mappings as well
+
+DROP INDEX IF EXISTS idx_unique_cel_policy_name;
+
+DROP TABLE IF EXISTS public.cel_policy;
+
+DROP SEQUENCE IF EXISTS id_seq_cel_policy;
@@ -0,0 +1,34 @@
+/*
+ * Copyright (c) 2025. Devtron Inc.
+ */
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_cel_policy;
+
+CREATE TABLE IF NOT EXISTS public.cel_policy
+(
+    id          INTEGER      NOT NULL DEFAULT nextval('id_seq_cel_policy'::regclass),
+    name        VARCHAR(100) NOT NULL,
+    description TEXT,
+    expression  TEXT         NOT NULL,
+    message     TEXT,
+    -- evaluation hooks of the policy, one or more of CI_TRIGGER, CD_TRIGGER and CONFIG_SAVE
+    hooks       TEXT[],
+    -- BLOCK or WARN
+    action      VARCHAR(20)  NOT NULL,
+    enabled     BOOLEAN      NOT NULL DEFAULT true,
+    deleted     BOOLEAN      NOT NULL DEFAULT false,
+    created_on  TIMESTAMPTZ  NOT NULL,
+    created_by  INTEGER      NOT NULL,
+    updated_on  TIMESTAMPTZ  NOT NULL,
+    updated_by  INTEGER      NOT NULL,
+    PRIMARY KEY (id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_unique_cel_policy_name
+    ON public.cel_policy (name)
+    WHERE deleted = false;
+
+-- project qualifier is used to scope the cel policies on projects
+INSERT INTO devtron_resource_searchable_key(name, is_removed, created_on, created_by, updated_on, updated_by)
+SELECT 'PROJECT_ID', false, now(), 1, now(), 1
+WHERE NOT EXISTS (SELECT 1 FROM devtron_resource_searchable_key WHERE name = 'PROJECT_ID');
@@ -0,0 +1,18 @@
+/*
+ * Copyright (c) 2025. Devtron Inc.
+ */
+
+-- resource_type 5 is the deployment window resource type of resource qualifier mappings
+DELETE FROM resource_qualifier_mapping WHERE resource_type = 5;
+
+DROP INDEX IF EXISTS idx_deployment_window_override_audit_pipeline_id;
+
+DROP TABLE IF EXISTS public.deployment_window_override_audit;
+
+DROP SEQUENCE IF EXISTS id_seq_deployment_window_override_audit;
+
+DROP INDEX IF EXISTS idx_unique_deployment_window_name;
+
+DROP TABLE IF EXISTS public.deployment_window;
+
+DROP SEQUENCE IF EXISTS id_seq_deployment_window;
@@ -0,0 +1,54 @@
+/*
+ * Copyright (c) 2025. Devtron Inc.
+ */
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_deployment_window;
+
+CREATE TABLE IF NOT EXISTS public.deployment_window
+(
+    id               INTEGER      NOT NULL DEFAULT nextval('id_seq_deployment_window'::regclass),
+    name             VARCHAR(100) NOT NULL,
+    description      TEXT,
+    -- BLACKOUT or MAINTENANCE
+    window_type      VARCHAR(20)  NOT NULL,
+    -- ONE_TIME or RECURRING
+    schedule_type    VARCHAR(20)  NOT NULL,
+    start_time       TIMESTAMPTZ,
+    end_time         TIMESTAMPTZ,
+    cron             VARCHAR(100),
+    duration_minutes INTEGER,
+    timezone         VARCHAR(100) NOT NULL,
+    enabled          BOOLEAN      NOT NULL DEFAULT true,
+    deleted          BOOLEAN      NOT NULL DEFAULT false,
+    created_on       TIMESTAMPTZ  NOT NULL,
+    created_by       INTEGER      NOT NULL,
+    updated_on       TIMESTAMPTZ  NOT NULL,
+    updated_by       INTEGER      NOT NULL,
+    PRIMARY KEY (id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_unique_deployment_window_name
+    ON public.deployment_window (name)
+    WHERE deleted = false;
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_deployment_window_override_audit;
+
+CREATE TABLE IF NOT EXISTS public.deployment_window_override_audit
+(
+    id                    INTEGER     NOT NULL DEFAULT nextval('id_seq_deployment_window_override_audit'::regclass),
+    pipeline_id           INTEGER     NOT NULL,
+    app_id                INTEGER     NOT NULL,
+    env_id                INTEGER     NOT NULL,
+    cd_workflow_runner_id INTEGER,
+    reason                TEXT        NOT NULL,
+    -- json array of the windows overridden
+    windows               TEXT,
+    created_on            TIMESTAMPTZ NOT NULL,
+    created_by            INTEGER     NOT NULL,
+    updated_on            TIMESTAMPTZ NOT NULL,
+    updated_by            INTEGER     NOT NULL,
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_deployment_window_override_audit_pipeline_id
+    ON public.deployment_window_override_audit (pipeline_id);
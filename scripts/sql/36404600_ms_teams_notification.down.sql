@@ -0,0 +1,7 @@
+/*
+ * Copyright (c) 2025. Devtron Inc.
+ */
+
+DROP TABLE IF EXISTS public.ms_teams_config;
+
+DROP SEQUENCE IF EXISTS id_seq_ms_teams_config;
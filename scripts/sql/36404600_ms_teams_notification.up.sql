@@ -0,0 +1,22 @@
+/*
+ * Copyright (c) 2025. Devtron Inc.
+ */
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_ms_teams_config;
+
+CREATE TABLE IF NOT EXISTS public.ms_teams_config
+(
+    id           INTEGER      NOT NULL DEFAULT nextval('id_seq_ms_teams_config'::regclass),
+    web_hook_url VARCHAR(500) NOT NULL,
+    config_name  VARCHAR(250) NOT NULL,
+    description  TEXT,
+    owner_id     INTEGER,
+    team_id      INTEGER      NOT NULL,
+    deleted      BOOLEAN      NOT NULL DEFAULT FALSE,
+    created_on   TIMESTAMPTZ  NOT NULL,
+    created_by   INTEGER      NOT NULL,
+    updated_on   TIMESTAMPTZ  NOT NULL,
+    updated_by   INTEGER      NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT ms_teams_config_team_id_fkey FOREIGN KEY (team_id) REFERENCES public.team (id)
+);
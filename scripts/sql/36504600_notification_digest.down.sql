@@ -0,0 +1,18 @@
+/*
+ * Copyright (c) 2025. Devtron Inc.
+ */
+
+DELETE FROM "public"."notification_templates" WHERE event_type_id = 10;
+
+DELETE FROM notifier_event_log WHERE event_type_id = 10;
+
+DELETE FROM public.event WHERE id = 10;
+
+DROP INDEX IF EXISTS idx_notification_digest_event_pending;
+
+DROP TABLE IF EXISTS public.notification_digest_event;
+
+DROP SEQUENCE IF EXISTS id_seq_notification_digest_event;
+
+ALTER TABLE public.notification_settings
+    DROP COLUMN IF EXISTS digest_config;
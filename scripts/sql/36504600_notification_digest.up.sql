@@ -0,0 +1,127 @@
+/*
+ * Copyright (c) 2025. Devtron Inc.
+ */
+
+-- digest mode and quiet hours of a notification setting, empty when the events are notified right away
+ALTER TABLE public.notification_settings
+    ADD COLUMN IF NOT EXISTS digest_config TEXT;
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_notification_digest_event;
+
+-- events held back for a channel of a notification setting in digest mode or in quiet hours
+CREATE TABLE IF NOT EXISTS public.notification_digest_event
+(
+    id                      INTEGER     NOT NULL DEFAULT nextval('id_seq_notification_digest_event'::regclass),
+    notification_setting_id INTEGER     NOT NULL,
+    channel                 VARCHAR(50) NOT NULL,
+    config                  TEXT        NOT NULL,
+    digest_config           TEXT        NOT NULL,
+    event_type_id           INTEGER     NOT NULL,
+    pipeline_type           VARCHAR(50),
+    pipeline_id             INTEGER,
+    event                   TEXT        NOT NULL,
+    dispatched              BOOLEAN     NOT NULL DEFAULT FALSE,
+    dispatched_on           TIMESTAMPTZ,
+    created_on              TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_notification_digest_event_pending
+    ON public.notification_digest_event (notification_setting_id, channel)
+    WHERE dispatched = false;
+
+-- digest summary of the aggregated pipeline trigger, success and fail events, payload.digest holds the summary
+INSERT INTO public.event (id, event_type, description)
+SELECT 10, 'DIGEST', ''
+WHERE NOT EXISTS (SELECT 1 FROM public.event WHERE id = 10);
+
+INSERT INTO "public"."notification_templates" (channel_type, node_type, event_type_id, template_name, template_payload)
+SELECT 'slack', 'CI', 10, 'CI digest slack template', '{
+    "text": ":bell: {{digest.title}}",
+    "blocks": [{
+            "type": "section",
+            "text": {
+                "type": "mrkdwn",
+                "text": ":bell: *Pipeline notifications digest*\n{{digest.from}} - {{digest.to}}"
+            }
+        },
+        {
+            "type": "section",
+            "fields": [{
+                    "type": "mrkdwn",
+                    "text": "*Triggered*\n{{digest.triggered}}"
+                },
+                {
+                    "type": "mrkdwn",
+                    "text": "*Succeeded*\n{{digest.succeeded}}"
+                },
+                {
+                    "type": "mrkdwn",
+                    "text": "*Failed*\n{{digest.failed}}"
+                }
+            ]
+        },
+        {
+            "type": "divider"
+        },
+        {
+            "type": "section",
+            "text": {
+                "type": "mrkdwn",
+                "text": "{{#digest.items}}• *{{appName}}*{{#envName}} / {{envName}}{{/envName}} ({{pipelineName}}): {{status}}{{#count}} x{{count}}{{/count}}{{#link}} <{{& link}}|View>{{/link}}\n{{/digest.items}}"
+            }
+        }
+    ]
+}'
+WHERE NOT EXISTS (SELECT 1 FROM "public"."notification_templates" WHERE channel_type = 'slack' AND node_type = 'CI' AND event_type_id = 10);
+INSERT INTO "public"."notification_templates" (channel_type, node_type, event_type_id, template_name, template_payload)
+SELECT 'ses', 'CI', 10, 'CI digest ses template', '{"from": "{{fromEmail}}", "to": "{{toEmail}}","subject": "🔔 {{digest.title}}","html": "<h2 style=\"color:#000A14;\">Pipeline notifications digest</h2><span>{{digest.from}} - {{digest.to}}</span><br><br><span>Triggered: <strong>{{digest.triggered}}</strong></span>&nbsp;&nbsp;|&nbsp;&nbsp;<span>Succeeded: <strong style=\"color:#1dad70;\">{{digest.succeeded}}</strong></span>&nbsp;&nbsp;|&nbsp;&nbsp;<span>Failed: <strong style=\"color:#f33e3e;\">{{digest.failed}}</strong></span><br><br><hr><table style=\"width:600px;border-collapse:collapse;\"><tr><th align=\"left\">Application</th><th align=\"left\">Environment</th><th align=\"left\">Pipeline</th><th align=\"left\">Status</th><th align=\"left\">Count</th></tr>{{#digest.items}}<tr><td>{{#link}}<a href=\"{{& link}}\">{{appName}}</a>{{/link}}{{^link}}{{appName}}{{/link}}</td><td>{{envName}}</td><td>{{pipelineName}}</td><td>{{status}}</td><td>{{count}}</td></tr>{{/digest.items}}</table>"}'
+WHERE NOT EXISTS (SELECT 1 FROM "public"."notification_templates" WHERE channel_type = 'ses' AND node_type = 'CI' AND event_type_id = 10);
+INSERT INTO "public"."notification_templates" (channel_type, node_type, event_type_id, template_name, template_payload)
+SELECT 'smtp', 'CI', 10, 'CI digest smtp template', '{"from": "{{fromEmail}}", "to": "{{toEmail}}","subject": "🔔 {{digest.title}}","html": "<h2 style=\"color:#000A14;\">Pipeline notifications digest</h2><span>{{digest.from}} - {{digest.to}}</span><br><br><span>Triggered: <strong>{{digest.triggered}}</strong></span>&nbsp;&nbsp;|&nbsp;&nbsp;<span>Succeeded: <strong style=\"color:#1dad70;\">{{digest.succeeded}}</strong></span>&nbsp;&nbsp;|&nbsp;&nbsp;<span>Failed: <strong style=\"color:#f33e3e;\">{{digest.failed}}</strong></span><br><br><hr><table style=\"width:600px;border-collapse:collapse;\"><tr><th align=\"left\">Application</th><th align=\"left\">Environment</th><th align=\"left\">Pipeline</th><th align=\"left\">Status</th><th align=\"left\">Count</th></tr>{{#digest.items}}<tr><td>{{#link}}<a href=\"{{& link}}\">{{appName}}</a>{{/link}}{{^link}}{{appName}}{{/link}}</td><td>{{envName}}</td><td>{{pipelineName}}</td><td>{{status}}</td><td>{{count}}</td></tr>{{/digest.items}}</table>"}'
+WHERE NOT EXISTS (SELECT 1 FROM "public"."notification_templates" WHERE channel_type = 'smtp' AND node_type = 'CI' AND event_type_id = 10);
+INSERT INTO "public"."notification_templates" (channel_type, node_type, event_type_id, template_name, template_payload)
+SELECT 'slack', 'CD', 10, 'CD digest slack template', '{
+    "text": ":bell: {{digest.title}}",
+    "blocks": [{
+            "type": "section",
+            "text": {
+                "type": "mrkdwn",
+                "text": ":bell: *Pipeline notifications digest*\n{{digest.from}} - {{digest.to}}"
+            }
+        },
+        {
+            "type": "section",
+            "fields": [{
+                    "type": "mrkdwn",
+                    "text": "*Triggered*\n{{digest.triggered}}"
+                },
+                {
+                    "type": "mrkdwn",
+                    "text": "*Succeeded*\n{{digest.succeeded}}"
+                },
+                {
+                    "type": "mrkdwn",
+                    "text": "*Failed*\n{{digest.failed}}"
+                }
+            ]
+        },
+        {
+            "type": "divider"
+        },
+        {
+            "type": "section",
+            "text": {
+                "type": "mrkdwn",
+                "text": "{{#digest.items}}• *{{appName}}*{{#envName}} / {{envName}}{{/envName}} ({{pipelineName}}): {{status}}{{#count}} x{{count}}{{/count}}{{#link}} <{{& link}}|View>{{/link}}\n{{/digest.items}}"
+            }
+        }
+    ]
+}'
+WHERE NOT EXISTS (SELECT 1 FROM "public"."notification_templates" WHERE channel_type = 'slack' AND node_type = 'CD' AND event_type_id = 10);
+INSERT INTO "public"."notification_templates" (channel_type, node_type, event_type_id, template_name, template_payload)
+SELECT 'ses', 'CD', 10, 'CD digest ses template', '{"from": "{{fromEmail}}", "to": "{{toEmail}}","subject": "🔔 {{digest.title}}","html": "<h2 style=\"color:#000A14;\">Pipeline notifications digest</h2><span>{{digest.from}} - {{digest.to}}</span><br><br><span>Triggered: <strong>{{digest.triggered}}</strong></span>&nbsp;&nbsp;|&nbsp;&nbsp;<span>Succeeded: <strong style=\"color:#1dad70;\">{{digest.succeeded}}</strong></span>&nbsp;&nbsp;|&nbsp;&nbsp;<span>Failed: <strong style=\"color:#f33e3e;\">{{digest.failed}}</strong></span><br><br><hr><table style=\"width:600px;border-collapse:collapse;\"><tr><th align=\"left\">Application</th><th align=\"left\">Environment</th><th align=\"left\">Pipeline</th><th align=\"left\">Status</th><th align=\"left\">Count</th></tr>{{#digest.items}}<tr><td>{{#link}}<a href=\"{{& link}}\">{{appName}}</a>{{/link}}{{^link}}{{appName}}{{/link}}</td><td>{{envName}}</td><td>{{pipelineName}}</td><td>{{status}}</td><td>{{count}}</td></tr>{{/digest.items}}</table>"}'
+WHERE NOT EXISTS (SELECT 1 FROM "public"."notification_templates" WHERE channel_type = 'ses' AND node_type = 'CD' AND event_type_id = 10);
+INSERT INTO "public"."notification_templates" (channel_type, node_type, event_type_id, template_name, template_payload)
+SELECT 'smtp', 'CD', 10, 'CD digest smtp template', '{"from": "{{fromEmail}}", "to": "{{toEmail}}","subject": "🔔 {{digest.title}}","html": "<h2 style=\"color:#000A14;\">Pipeline notifications digest</h2><span>{{digest.from}} - {{digest.to}}</span><br><br><span>Triggered: <strong>{{digest.triggered}}</strong></span>&nbsp;&nbsp;|&nbsp;&nbsp;<span>Succeeded: <strong style=\"color:#1dad70;\">{{digest.succeeded}}</strong></span>&nbsp;&nbsp;|&nbsp;&nbsp;<span>Failed: <strong style=\"color:#f33e3e;\">{{digest.failed}}</strong></span><br><br><hr><table style=\"width:600px;border-collapse:collapse;\"><tr><th align=\"left\">Application</th><th align=\"left\">Environment</th><th align=\"left\">Pipeline</th><th align=\"left\">Status</th><th align=\"left\">Count</th></tr>{{#digest.items}}<tr><td>{{#link}}<a href=\"{{& link}}\">{{appName}}</a>{{/link}}{{^link}}{{appName}}{{/link}}</td><td>{{envName}}</td><td>{{pipelineName}}</td><td>{{status}}</td><td>{{count}}</td></tr>{{/digest.items}}</table>"}'
+WHERE NOT EXISTS (SELECT 1 FROM "public"."notification_templates" WHERE channel_type = 'smtp' AND node_type = 'CD' AND event_type_id = 10);
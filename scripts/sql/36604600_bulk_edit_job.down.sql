@@ -0,0 +1,8 @@
+/*
+ * Copyright (c) 2025. Devtron Inc.
+ */
+
+DROP TABLE IF EXISTS public.bulk_edit_job_object;
+DROP SEQUENCE IF EXISTS id_seq_bulk_edit_job_object;
+DROP TABLE IF EXISTS public.bulk_edit_job;
+DROP SEQUENCE IF EXISTS id_seq_bulk_edit_job;
@@ -0,0 +1,59 @@
+/*
+ * Copyright (c) 2025. Devtron Inc.
+ */
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_bulk_edit_job;
+
+-- bulk edits persisted with their payload, executed immediately or at scheduled_at
+CREATE TABLE IF NOT EXISTS public.bulk_edit_job
+(
+    id               INTEGER     NOT NULL DEFAULT nextval('id_seq_bulk_edit_job'::regclass),
+    payload          TEXT        NOT NULL,
+    impacted_objects TEXT,
+    status           VARCHAR(50) NOT NULL,
+    message          TEXT,
+    scheduled_at     TIMESTAMPTZ,
+    started_on       TIMESTAMPTZ,
+    finished_on      TIMESTAMPTZ,
+    response         TEXT,
+    user_email_id    VARCHAR(250),
+    rolled_back_by   INTEGER,
+    rolled_back_on   TIMESTAMPTZ,
+    created_on       TIMESTAMPTZ NOT NULL,
+    created_by       INTEGER     NOT NULL,
+    updated_on       TIMESTAMPTZ NOT NULL,
+    updated_by       INTEGER     NOT NULL,
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_bulk_edit_job_status_scheduled_at
+    ON public.bulk_edit_job (status, scheduled_at);
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_bulk_edit_job_object;
+
+-- deployment templates, config maps and secrets impacted by a bulk edit job with their data before and after the edit,
+-- history_id is the history entry of the version before the edit which is restored on rollback
+CREATE TABLE IF NOT EXISTS public.bulk_edit_job_object
+(
+    id            INTEGER     NOT NULL DEFAULT nextval('id_seq_bulk_edit_job_object'::regclass),
+    job_id        INTEGER     NOT NULL,
+    object_type   VARCHAR(50) NOT NULL,
+    object_id     INTEGER     NOT NULL,
+    app_id        INTEGER     NOT NULL,
+    app_name      VARCHAR(250),
+    env_id        INTEGER     NOT NULL DEFAULT 0,
+    previous_data TEXT,
+    history_id    INTEGER,
+    edited_data   TEXT,
+    status        VARCHAR(50) NOT NULL,
+    message       TEXT,
+    created_on    TIMESTAMPTZ NOT NULL,
+    created_by    INTEGER     NOT NULL,
+    updated_on    TIMESTAMPTZ NOT NULL,
+    updated_by    INTEGER     NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT bulk_edit_job_object_job_id_fkey FOREIGN KEY (job_id) REFERENCES public.bulk_edit_job (id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_bulk_edit_job_object_job_id
+    ON public.bulk_edit_job_object (job_id);
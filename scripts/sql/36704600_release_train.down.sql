@@ -0,0 +1,14 @@
+/*
+ * Copyright (c) 2025. Devtron Inc.
+ */
+
+DROP TABLE IF EXISTS public.release_train_run_deployment;
+DROP SEQUENCE IF EXISTS id_seq_release_train_run_deployment;
+DROP TABLE IF EXISTS public.release_train_run_stage;
+DROP SEQUENCE IF EXISTS id_seq_release_train_run_stage;
+DROP TABLE IF EXISTS public.release_train_run;
+DROP SEQUENCE IF EXISTS id_seq_release_train_run;
+DROP TABLE IF EXISTS public.release_train_stage;
+DROP SEQUENCE IF EXISTS id_seq_release_train_stage;
+DROP TABLE IF EXISTS public.release_train;
+DROP SEQUENCE IF EXISTS id_seq_release_train;
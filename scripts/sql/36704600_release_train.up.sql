@@ -0,0 +1,123 @@
+/*
+ * Copyright (c) 2025. Devtron Inc.
+ */
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_release_train;
+
+-- release train releases the artifacts of a set of apps through an ordered set of environment stages
+CREATE TABLE IF NOT EXISTS public.release_train
+(
+    id          INTEGER      NOT NULL DEFAULT nextval('id_seq_release_train'::regclass),
+    name        VARCHAR(100) NOT NULL,
+    description TEXT,
+    app_ids     INTEGER[]    NOT NULL,
+    active      BOOLEAN      NOT NULL DEFAULT TRUE,
+    created_on  TIMESTAMPTZ  NOT NULL,
+    created_by  INTEGER      NOT NULL,
+    updated_on  TIMESTAMPTZ  NOT NULL,
+    updated_by  INTEGER      NOT NULL,
+    PRIMARY KEY (id)
+);
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_release_train_stage;
+
+CREATE TABLE IF NOT EXISTS public.release_train_stage
+(
+    id                      INTEGER     NOT NULL DEFAULT nextval('id_seq_release_train_stage'::regclass),
+    release_train_id        INTEGER     NOT NULL,
+    name                    VARCHAR(50) NOT NULL,
+    stage_order             INTEGER     NOT NULL,
+    environment_id          INTEGER     NOT NULL,
+    wait_minutes            INTEGER     NOT NULL DEFAULT 0,
+    require_healthy         BOOLEAN     NOT NULL DEFAULT FALSE,
+    healthy_timeout_minutes INTEGER     NOT NULL DEFAULT 0,
+    require_approval        BOOLEAN     NOT NULL DEFAULT FALSE,
+    active                  BOOLEAN     NOT NULL DEFAULT TRUE,
+    created_on              TIMESTAMPTZ NOT NULL,
+    created_by              INTEGER     NOT NULL,
+    updated_on              TIMESTAMPTZ NOT NULL,
+    updated_by              INTEGER     NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT release_train_stage_release_train_id_fkey FOREIGN KEY (release_train_id) REFERENCES public.release_train (id),
+    CONSTRAINT release_train_stage_environment_id_fkey FOREIGN KEY (environment_id) REFERENCES public.environment (id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_release_train_stage_release_train_id
+    ON public.release_train_stage (release_train_id);
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_release_train_run;
+
+-- state of each run of a release train, the runs in RUNNING status are progressed by the release train cron
+CREATE TABLE IF NOT EXISTS public.release_train_run
+(
+    id               INTEGER     NOT NULL DEFAULT nextval('id_seq_release_train_run'::regclass),
+    release_train_id INTEGER     NOT NULL,
+    status           VARCHAR(50) NOT NULL,
+    message          TEXT,
+    artifacts        TEXT        NOT NULL,
+    started_on       TIMESTAMPTZ NOT NULL,
+    finished_on      TIMESTAMPTZ,
+    created_on       TIMESTAMPTZ NOT NULL,
+    created_by       INTEGER     NOT NULL,
+    updated_on       TIMESTAMPTZ NOT NULL,
+    updated_by       INTEGER     NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT release_train_run_release_train_id_fkey FOREIGN KEY (release_train_id) REFERENCES public.release_train (id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_release_train_run_release_train_id_status
+    ON public.release_train_run (release_train_id, status);
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_release_train_run_stage;
+
+CREATE TABLE IF NOT EXISTS public.release_train_run_stage
+(
+    id                      INTEGER     NOT NULL DEFAULT nextval('id_seq_release_train_run_stage'::regclass),
+    run_id                  INTEGER     NOT NULL,
+    stage_order             INTEGER     NOT NULL,
+    name                    VARCHAR(50) NOT NULL,
+    environment_id          INTEGER     NOT NULL,
+    wait_minutes            INTEGER     NOT NULL DEFAULT 0,
+    require_healthy         BOOLEAN     NOT NULL DEFAULT FALSE,
+    healthy_timeout_minutes INTEGER     NOT NULL DEFAULT 0,
+    require_approval        BOOLEAN     NOT NULL DEFAULT FALSE,
+    status                  VARCHAR(50) NOT NULL,
+    message                 TEXT,
+    gate_started_on         TIMESTAMPTZ,
+    approved_by             INTEGER,
+    approved_on             TIMESTAMPTZ,
+    started_on              TIMESTAMPTZ,
+    finished_on             TIMESTAMPTZ,
+    created_on              TIMESTAMPTZ NOT NULL,
+    created_by              INTEGER     NOT NULL,
+    updated_on              TIMESTAMPTZ NOT NULL,
+    updated_by              INTEGER     NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT release_train_run_stage_run_id_fkey FOREIGN KEY (run_id) REFERENCES public.release_train_run (id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_release_train_run_stage_run_id
+    ON public.release_train_run_stage (run_id);
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_release_train_run_deployment;
+
+CREATE TABLE IF NOT EXISTS public.release_train_run_deployment
+(
+    id             INTEGER     NOT NULL DEFAULT nextval('id_seq_release_train_run_deployment'::regclass),
+    run_stage_id   INTEGER     NOT NULL,
+    app_id         INTEGER     NOT NULL,
+    pipeline_id    INTEGER     NOT NULL,
+    ci_artifact_id INTEGER     NOT NULL,
+    cd_workflow_id INTEGER,
+    status         VARCHAR(50) NOT NULL,
+    message        TEXT,
+    created_on     TIMESTAMPTZ NOT NULL,
+    created_by     INTEGER     NOT NULL,
+    updated_on     TIMESTAMPTZ NOT NULL,
+    updated_by     INTEGER     NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT release_train_run_deployment_run_stage_id_fkey FOREIGN KEY (run_stage_id) REFERENCES public.release_train_run_stage (id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_release_train_run_deployment_run_stage_id
+    ON public.release_train_run_deployment (run_stage_id);
@@ -0,0 +1,8 @@
+/*
+ * Copyright (c) 2025. Devtron Inc.
+ */
+
+DROP TABLE IF EXISTS public.terminal_session_recording_chunk;
+DROP SEQUENCE IF EXISTS id_seq_terminal_session_recording_chunk;
+DROP TABLE IF EXISTS public.terminal_session_recording;
+DROP SEQUENCE IF EXISTS id_seq_terminal_session_recording;
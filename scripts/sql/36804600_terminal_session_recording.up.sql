@@ -0,0 +1,55 @@
+/*
+ * Copyright (c) 2025. Devtron Inc.
+ */
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_terminal_session_recording;
+
+-- asciicast v2 recordings of the pod and cluster terminal sessions
+CREATE TABLE IF NOT EXISTS public.terminal_session_recording
+(
+    id                 INTEGER      NOT NULL DEFAULT nextval('id_seq_terminal_session_recording'::regclass),
+    session_id         VARCHAR(100) NOT NULL,
+    user_id            INTEGER      NOT NULL,
+    cluster_id         INTEGER,
+    environment_id     INTEGER,
+    app_id             INTEGER,
+    namespace          VARCHAR(250),
+    pod_name           VARCHAR(250),
+    container_name     VARCHAR(250),
+    shell              VARCHAR(50),
+    terminal_access_id INTEGER,
+    started_on         TIMESTAMPTZ  NOT NULL,
+    ended_on           TIMESTAMPTZ,
+    size_bytes         INTEGER      NOT NULL DEFAULT 0,
+    truncated          BOOLEAN      NOT NULL DEFAULT FALSE,
+    width              INTEGER,
+    height             INTEGER,
+    created_on         TIMESTAMPTZ  NOT NULL,
+    created_by         INTEGER      NOT NULL,
+    updated_on         TIMESTAMPTZ  NOT NULL,
+    updated_by         INTEGER      NOT NULL,
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_terminal_session_recording_user_id
+    ON public.terminal_session_recording (user_id);
+
+CREATE INDEX IF NOT EXISTS idx_terminal_session_recording_cluster_id_namespace
+    ON public.terminal_session_recording (cluster_id, namespace);
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_terminal_session_recording_chunk;
+
+-- asciicast v2 events of a recording, streamed in chunks while the session runs
+CREATE TABLE IF NOT EXISTS public.terminal_session_recording_chunk
+(
+    id           INTEGER NOT NULL DEFAULT nextval('id_seq_terminal_session_recording_chunk'::regclass),
+    recording_id INTEGER NOT NULL,
+    chunk_index  INTEGER NOT NULL,
+    events       TEXT    NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT terminal_session_recording_chunk_recording_id_fkey
+        FOREIGN KEY (recording_id) REFERENCES public.terminal_session_recording (id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_unique_terminal_session_recording_chunk
+    ON public.terminal_session_recording_chunk (recording_id, chunk_index);
@@ -0,0 +1,7 @@
+/*
+ * Copyright (c) 2025. Devtron Inc.
+ */
+
+DROP INDEX IF EXISTS public.scoped_variable_version_version_idx;
+DROP TABLE IF EXISTS public.scoped_variable_version;
+DROP SEQUENCE IF EXISTS id_seq_scoped_variable_version;
@@ -0,0 +1,21 @@
+/*
+ * Copyright (c) 2025. Devtron Inc.
+ */
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_scoped_variable_version;
+
+-- every save of the scoped variables is kept as a version, payload is the whole variable payload of the save
+CREATE TABLE IF NOT EXISTS public.scoped_variable_version
+(
+    id                       INTEGER     NOT NULL DEFAULT nextval('id_seq_scoped_variable_version'::regclass),
+    version                  INTEGER     NOT NULL,
+    payload                  TEXT        NOT NULL,
+    rolled_back_from_version INTEGER,
+    created_on               TIMESTAMPTZ NOT NULL,
+    created_by               INTEGER     NOT NULL,
+    updated_on               TIMESTAMPTZ NOT NULL,
+    updated_by               INTEGER     NOT NULL,
+    PRIMARY KEY (id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS scoped_variable_version_version_idx ON public.scoped_variable_version (version);
@@ -0,0 +1,7 @@
+/*
+ * Copyright (c) 2025. Devtron Inc.
+ */
+
+DROP INDEX IF EXISTS public.installed_app_drift_installed_app_id_idx;
+DROP TABLE IF EXISTS public.installed_app_drift;
+DROP SEQUENCE IF EXISTS id_seq_installed_app_drift;
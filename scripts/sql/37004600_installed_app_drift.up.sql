@@ -0,0 +1,29 @@
+/*
+ * Copyright (c) 2025. Devtron Inc.
+ */
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_installed_app_drift;
+
+-- latest drift check of each chart store app, diff is the json of the values and resources differing from the last deployment
+CREATE TABLE IF NOT EXISTS public.installed_app_drift
+(
+    id                               INTEGER     NOT NULL DEFAULT nextval('id_seq_installed_app_drift'::regclass),
+    installed_app_id                 INTEGER     NOT NULL,
+    installed_app_version_history_id INTEGER,
+    status                           VARCHAR(50) NOT NULL,
+    values_drifted                   BOOLEAN     NOT NULL DEFAULT FALSE,
+    drifted_resource_count           INTEGER     NOT NULL DEFAULT 0,
+    diff                             TEXT,
+    message                          TEXT,
+    checked_on                       TIMESTAMPTZ NOT NULL,
+    detected_on                      TIMESTAMPTZ,
+    reconciled_on                    TIMESTAMPTZ,
+    created_on                       TIMESTAMPTZ NOT NULL,
+    created_by                       INTEGER     NOT NULL,
+    updated_on                       TIMESTAMPTZ NOT NULL,
+    updated_by                       INTEGER     NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT installed_app_drift_installed_app_id_fkey FOREIGN KEY (installed_app_id) REFERENCES public.installed_apps (id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS installed_app_drift_installed_app_id_idx ON public.installed_app_drift (installed_app_id);
@@ -0,0 +1,13 @@
+/*
+ * Copyright (c) 2025. Devtron Inc.
+ */
+
+DROP INDEX IF EXISTS chart_group_run_step_run_id_idx;
+DROP TABLE IF EXISTS public.chart_group_run_step;
+DROP SEQUENCE IF EXISTS id_seq_chart_group_run_step;
+
+DROP INDEX IF EXISTS chart_group_run_status_idx;
+DROP TABLE IF EXISTS public.chart_group_run;
+DROP SEQUENCE IF EXISTS id_seq_chart_group_run;
+
+ALTER TABLE public.chart_group_entry DROP COLUMN IF EXISTS depends_on_app_store_ids;
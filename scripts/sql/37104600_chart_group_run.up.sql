@@ -0,0 +1,59 @@
+/*
+ * Copyright (c) 2025. Devtron Inc.
+ */
+
+-- charts of the group which must be installed and healthy before the chart of the entry is deployed
+ALTER TABLE public.chart_group_entry ADD COLUMN IF NOT EXISTS depends_on_app_store_ids INTEGER[];
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_chart_group_run;
+
+-- ordered install or upgrade of the apps of a chart group installation
+CREATE TABLE IF NOT EXISTS public.chart_group_run
+(
+    id                    INTEGER      NOT NULL DEFAULT nextval('id_seq_chart_group_run'::regclass),
+    chart_group_id        INTEGER      NOT NULL,
+    group_installation_id VARCHAR(250) NOT NULL,
+    run_type              VARCHAR(50)  NOT NULL,
+    status                VARCHAR(50)  NOT NULL,
+    message               TEXT,
+    started_on            TIMESTAMPTZ  NOT NULL,
+    finished_on           TIMESTAMPTZ,
+    created_on            TIMESTAMPTZ  NOT NULL,
+    created_by            INTEGER      NOT NULL,
+    updated_on            TIMESTAMPTZ  NOT NULL,
+    updated_by            INTEGER      NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT chart_group_run_chart_group_id_fkey FOREIGN KEY (chart_group_id) REFERENCES public.chart_group (id)
+);
+
+CREATE INDEX IF NOT EXISTS chart_group_run_status_idx ON public.chart_group_run (status);
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_chart_group_run_step;
+
+-- deployment of one installed app of a run, the steps are deployed one after the other in step_order
+CREATE TABLE IF NOT EXISTS public.chart_group_run_step
+(
+    id                                    INTEGER     NOT NULL DEFAULT nextval('id_seq_chart_group_run_step'::regclass),
+    run_id                                INTEGER     NOT NULL,
+    step_order                            INTEGER     NOT NULL,
+    chart_group_entry_id                  INTEGER,
+    installed_app_id                      INTEGER     NOT NULL,
+    installed_app_version_id              INTEGER,
+    installed_app_version_history_id      INTEGER,
+    from_app_store_application_version_id INTEGER,
+    to_app_store_application_version_id   INTEGER     NOT NULL,
+    app_store_values_version_id           INTEGER,
+    status                                VARCHAR(50) NOT NULL,
+    message                               TEXT,
+    started_on                            TIMESTAMPTZ,
+    finished_on                           TIMESTAMPTZ,
+    created_on                            TIMESTAMPTZ NOT NULL,
+    created_by                            INTEGER     NOT NULL,
+    updated_on                            TIMESTAMPTZ NOT NULL,
+    updated_by                            INTEGER     NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT chart_group_run_step_run_id_fkey FOREIGN KEY (run_id) REFERENCES public.chart_group_run (id),
+    CONSTRAINT chart_group_run_step_installed_app_id_fkey FOREIGN KEY (installed_app_id) REFERENCES public.installed_apps (id)
+);
+
+CREATE INDEX IF NOT EXISTS chart_group_run_step_run_id_idx ON public.chart_group_run_step (run_id);
@@ -0,0 +1,11 @@
+/*
+ * Copyright (c) 2025. Devtron Inc.
+ */
+
+DROP INDEX IF EXISTS node_maintenance_node_run_id_idx;
+DROP TABLE IF EXISTS public.node_maintenance_node;
+DROP SEQUENCE IF EXISTS id_seq_node_maintenance_node;
+
+DROP INDEX IF EXISTS node_maintenance_run_cluster_id_status_idx;
+DROP TABLE IF EXISTS public.node_maintenance_run;
+DROP SEQUENCE IF EXISTS id_seq_node_maintenance_run;
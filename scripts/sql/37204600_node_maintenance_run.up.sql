@@ -0,0 +1,54 @@
+/*
+ * Copyright (c) 2025. Devtron Inc.
+ */
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_node_maintenance_run;
+
+-- rolling drain of a set of nodes of a cluster
+CREATE TABLE IF NOT EXISTS public.node_maintenance_run
+(
+    id                    INTEGER     NOT NULL DEFAULT nextval('id_seq_node_maintenance_run'::regclass),
+    cluster_id            INTEGER     NOT NULL,
+    node_selector         TEXT,
+    node_group            VARCHAR(250),
+    concurrency           INTEGER     NOT NULL,
+    drain_timeout_seconds INTEGER     NOT NULL,
+    drain_options         TEXT,
+    status                VARCHAR(50) NOT NULL,
+    message               TEXT,
+    started_on            TIMESTAMPTZ NOT NULL,
+    finished_on           TIMESTAMPTZ,
+    created_on            TIMESTAMPTZ NOT NULL,
+    created_by            INTEGER     NOT NULL,
+    updated_on            TIMESTAMPTZ NOT NULL,
+    updated_by            INTEGER     NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT node_maintenance_run_cluster_id_fkey FOREIGN KEY (cluster_id) REFERENCES public.cluster (id)
+);
+
+CREATE INDEX IF NOT EXISTS node_maintenance_run_cluster_id_status_idx ON public.node_maintenance_run (cluster_id, status);
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_node_maintenance_node;
+
+-- drain of one node of a run
+CREATE TABLE IF NOT EXISTS public.node_maintenance_node
+(
+    id                INTEGER      NOT NULL DEFAULT nextval('id_seq_node_maintenance_node'::regclass),
+    run_id            INTEGER      NOT NULL,
+    node_name         VARCHAR(250) NOT NULL,
+    node_order        INTEGER      NOT NULL,
+    status            VARCHAR(50)  NOT NULL,
+    message           TEXT,
+    blocking_pods     TEXT,
+    evicted_pod_count INTEGER      NOT NULL DEFAULT 0,
+    started_on        TIMESTAMPTZ,
+    finished_on       TIMESTAMPTZ,
+    created_on        TIMESTAMPTZ  NOT NULL,
+    created_by        INTEGER      NOT NULL,
+    updated_on        TIMESTAMPTZ  NOT NULL,
+    updated_by        INTEGER      NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT node_maintenance_node_run_id_fkey FOREIGN KEY (run_id) REFERENCES public.node_maintenance_run (id)
+);
+
+CREATE INDEX IF NOT EXISTS node_maintenance_node_run_id_idx ON public.node_maintenance_node (run_id);
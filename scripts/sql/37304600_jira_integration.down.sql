@@ -0,0 +1,15 @@
+/*
+ * Copyright (c) 2025. Devtron Inc.
+ */
+
+DROP INDEX IF EXISTS jira_issue_transition_cd_workflow_runner_id_idx;
+DROP TABLE IF EXISTS public.jira_issue_transition;
+DROP SEQUENCE IF EXISTS id_seq_jira_issue_transition;
+
+DROP INDEX IF EXISTS jira_transition_rule_jira_config_id_idx;
+DROP TABLE IF EXISTS public.jira_transition_rule;
+DROP SEQUENCE IF EXISTS id_seq_jira_transition_rule;
+
+DROP INDEX IF EXISTS jira_config_team_id_unique_idx;
+DROP TABLE IF EXISTS public.jira_config;
+DROP SEQUENCE IF EXISTS id_seq_jira_config;
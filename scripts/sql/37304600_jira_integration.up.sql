@@ -0,0 +1,67 @@
+/*
+ * Copyright (c) 2025. Devtron Inc.
+ */
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_jira_config;
+
+-- jira account used for the apps of a project
+CREATE TABLE IF NOT EXISTS public.jira_config
+(
+    id           INTEGER      NOT NULL DEFAULT nextval('id_seq_jira_config'::regclass),
+    team_id      INTEGER      NOT NULL,
+    jira_url     VARCHAR(250) NOT NULL,
+    username     VARCHAR(250) NOT NULL,
+    api_token    TEXT         NOT NULL,
+    project_keys TEXT[],
+    active       BOOLEAN      NOT NULL,
+    created_on   TIMESTAMPTZ  NOT NULL,
+    created_by   INTEGER      NOT NULL,
+    updated_on   TIMESTAMPTZ  NOT NULL,
+    updated_by   INTEGER      NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT jira_config_team_id_fkey FOREIGN KEY (team_id) REFERENCES public.team (id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS jira_config_team_id_unique_idx ON public.jira_config (team_id) WHERE active = true;
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_jira_transition_rule;
+
+-- transition applied to the linked issues once a deployment on the environment succeeds
+CREATE TABLE IF NOT EXISTS public.jira_transition_rule
+(
+    id              INTEGER      NOT NULL DEFAULT nextval('id_seq_jira_transition_rule'::regclass),
+    jira_config_id  INTEGER      NOT NULL,
+    environment_id  INTEGER      NOT NULL,
+    transition_name VARCHAR(250) NOT NULL,
+    active          BOOLEAN      NOT NULL,
+    created_on      TIMESTAMPTZ  NOT NULL,
+    created_by      INTEGER      NOT NULL,
+    updated_on      TIMESTAMPTZ  NOT NULL,
+    updated_by      INTEGER      NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT jira_transition_rule_jira_config_id_fkey FOREIGN KEY (jira_config_id) REFERENCES public.jira_config (id),
+    CONSTRAINT jira_transition_rule_environment_id_fkey FOREIGN KEY (environment_id) REFERENCES public.environment (id)
+);
+
+CREATE INDEX IF NOT EXISTS jira_transition_rule_jira_config_id_idx ON public.jira_transition_rule (jira_config_id);
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_jira_issue_transition;
+
+-- outcome of the transition of a linked issue after a deployment
+CREATE TABLE IF NOT EXISTS public.jira_issue_transition
+(
+    id                    INTEGER      NOT NULL DEFAULT nextval('id_seq_jira_issue_transition'::regclass),
+    cd_workflow_runner_id INTEGER      NOT NULL,
+    issue_key             VARCHAR(250) NOT NULL,
+    transition_name       VARCHAR(250) NOT NULL,
+    status                VARCHAR(50)  NOT NULL,
+    message               TEXT,
+    created_on            TIMESTAMPTZ  NOT NULL,
+    created_by            INTEGER      NOT NULL,
+    updated_on            TIMESTAMPTZ  NOT NULL,
+    updated_by            INTEGER      NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT jira_issue_transition_cd_workflow_runner_id_fkey FOREIGN KEY (cd_workflow_runner_id) REFERENCES public.cd_workflow_runner (id)
+);
+
+CREATE INDEX IF NOT EXISTS jira_issue_transition_cd_workflow_runner_id_idx ON public.jira_issue_transition (cd_workflow_runner_id);
@@ -0,0 +1,11 @@
+/*
+ * Copyright (c) 2025. Devtron Inc.
+ */
+
+DROP INDEX IF EXISTS scim_resource_type_id_unique_idx;
+DROP TABLE IF EXISTS public.scim_resource;
+DROP SEQUENCE IF EXISTS id_seq_scim_resource;
+
+DROP INDEX IF EXISTS scim_token_active_unique_idx;
+DROP TABLE IF EXISTS public.scim_token;
+DROP SEQUENCE IF EXISTS id_seq_scim_token;
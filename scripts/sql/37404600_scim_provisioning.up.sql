@@ -0,0 +1,42 @@
+/*
+ * Copyright (c) 2025. Devtron Inc.
+ */
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_scim_token;
+
+-- bearer token used by the identity provider to call the scim endpoints, only the sha256 hash of the token is stored
+CREATE TABLE IF NOT EXISTS public.scim_token
+(
+    id           INTEGER     NOT NULL DEFAULT nextval('id_seq_scim_token'::regclass),
+    token_hash   VARCHAR(64) NOT NULL,
+    active       BOOLEAN     NOT NULL,
+    last_used_on TIMESTAMPTZ,
+    created_on   TIMESTAMPTZ NOT NULL,
+    created_by   INTEGER     NOT NULL,
+    updated_on   TIMESTAMPTZ NOT NULL,
+    updated_by   INTEGER     NOT NULL,
+    PRIMARY KEY (id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS scim_token_active_unique_idx ON public.scim_token (active) WHERE active = true;
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_scim_resource;
+
+-- scim attributes of the users and role groups which have no counterpart in devtron
+CREATE TABLE IF NOT EXISTS public.scim_resource
+(
+    id            INTEGER      NOT NULL DEFAULT nextval('id_seq_scim_resource'::regclass),
+    resource_type VARCHAR(20)  NOT NULL,
+    resource_id   INTEGER      NOT NULL,
+    external_id   VARCHAR(250),
+    display_name  VARCHAR(250),
+    given_name    VARCHAR(250),
+    family_name   VARCHAR(250),
+    created_on    TIMESTAMPTZ  NOT NULL,
+    created_by    INTEGER      NOT NULL,
+    updated_on    TIMESTAMPTZ  NOT NULL,
+    updated_by    INTEGER      NOT NULL,
+    PRIMARY KEY (id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS scim_resource_type_id_unique_idx ON public.scim_resource (resource_type, resource_id);
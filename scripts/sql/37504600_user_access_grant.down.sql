@@ -0,0 +1,12 @@
+/*
+ * Copyright (c) 2025. Devtron Inc.
+ */
+
+DROP INDEX IF EXISTS user_access_grant_audit_access_grant_id_idx;
+DROP TABLE IF EXISTS public.user_access_grant_audit;
+DROP SEQUENCE IF EXISTS id_seq_user_access_grant_audit;
+
+DROP INDEX IF EXISTS user_access_grant_subject_idx;
+DROP INDEX IF EXISTS user_access_grant_status_expires_on_idx;
+DROP TABLE IF EXISTS public.user_access_grant;
+DROP SEQUENCE IF EXISTS id_seq_user_access_grant;
@@ -0,0 +1,49 @@
+/*
+ * Copyright (c) 2025. Devtron Inc.
+ */
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_user_access_grant;
+
+-- temporary role filters of a user or a role group, either granted directly or requested and approved
+CREATE TABLE IF NOT EXISTS public.user_access_grant
+(
+    id               INTEGER     NOT NULL DEFAULT nextval('id_seq_user_access_grant'::regclass),
+    subject_type     VARCHAR(10) NOT NULL,
+    subject_id       INTEGER     NOT NULL,
+    role_filters     TEXT        NOT NULL,
+    duration_minutes INTEGER     NOT NULL,
+    justification    TEXT        NOT NULL,
+    status           VARCHAR(20) NOT NULL,
+    requested_by     INTEGER     NOT NULL,
+    reviewed_by      INTEGER,
+    reviewed_on      TIMESTAMPTZ,
+    review_comment   TEXT,
+    starts_on        TIMESTAMPTZ,
+    expires_on       TIMESTAMPTZ,
+    granted_role_ids INTEGER[],
+    created_on       TIMESTAMPTZ NOT NULL,
+    created_by       INTEGER     NOT NULL,
+    updated_on       TIMESTAMPTZ NOT NULL,
+    updated_by       INTEGER     NOT NULL,
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS user_access_grant_status_expires_on_idx ON public.user_access_grant (status, expires_on);
+CREATE INDEX IF NOT EXISTS user_access_grant_subject_idx ON public.user_access_grant (subject_type, subject_id);
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_user_access_grant_audit;
+
+-- audit trail of the access grants
+CREATE TABLE IF NOT EXISTS public.user_access_grant_audit
+(
+    id              INTEGER     NOT NULL DEFAULT nextval('id_seq_user_access_grant_audit'::regclass),
+    access_grant_id INTEGER     NOT NULL,
+    action          VARCHAR(20) NOT NULL,
+    comment         TEXT,
+    action_by       INTEGER     NOT NULL,
+    action_on       TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT user_access_grant_audit_access_grant_id_fkey FOREIGN KEY (access_grant_id) REFERENCES public.user_access_grant (id)
+);
+
+CREATE INDEX IF NOT EXISTS user_access_grant_audit_access_grant_id_idx ON public.user_access_grant_audit (access_grant_id);
@@ -0,0 +1,12 @@
+/*
+ * Copyright (c) 2025. Devtron Inc.
+ */
+
+-- last use of the token, the version replaced by the last rotation which stays valid until the overlap ends,
+-- and the api paths and ip cidrs the token is restricted to
+ALTER TABLE public.api_token
+    ADD COLUMN IF NOT EXISTS last_used_at                     TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS last_used_by_ip                  VARCHAR(250),
+    ADD COLUMN IF NOT EXISTS previous_version                 INTEGER,
+    ADD COLUMN IF NOT EXISTS previous_version_expire_at_in_ms BIGINT,
+    ADD COLUMN IF NOT EXISTS scope                            TEXT;
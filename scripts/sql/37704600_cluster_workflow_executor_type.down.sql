@@ -0,0 +1,6 @@
+/*
+ * Copyright (c) 2025. Devtron Inc.
+ */
+
+ALTER TABLE public.cluster
+    DROP COLUMN IF EXISTS workflow_executor_type;
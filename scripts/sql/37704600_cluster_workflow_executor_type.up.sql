@@ -0,0 +1,8 @@
+/*
+ * Copyright (c) 2025. Devtron Inc.
+ */
+
+-- executor type (AWF, SYSTEM, TEKTON) of the ci and pre/post cd workflows running in the cluster,
+-- null falls back to CI_WORKFLOW_EXECUTOR_TYPE / CD_WORKFLOW_EXECUTOR_TYPE
+ALTER TABLE public.cluster
+    ADD COLUMN IF NOT EXISTS workflow_executor_type VARCHAR(50);
@@ -21,6 +21,10 @@ type EventType int
 const Trigger EventType = 1
 const Success EventType = 2
 const Fail EventType = 3
+const Approval EventType = 4
+
+// Digest is the summary of the events held back for a notification setting in digest mode or in quiet hours
+const Digest EventType = 10
 
 type PipelineType string
 
@@ -36,6 +40,7 @@ const (
 	SES     Channel = "ses"
 	SMTP    Channel = "smtp"
 	Webhook Channel = "webhook"
+	MsTeams Channel = "msteams"
 )
 
 func (c Channel) String() string {
@@ -47,4 +52,5 @@ type UpdateType string
 const (
 	UpdateEvents     UpdateType = "events"
 	UpdateRecipients UpdateType = "recipients"
+	UpdateDigest     UpdateType = "digest"
 )